@@ -36,7 +36,7 @@ SPECIFIC_PATH="${PLUGIN_BASE_DIR}/registry.terraform.io/${PROVIDER_NAMESPACE}/${
 # 3. Build the Binary
 echo "Building provider for $OS_ARCH..."
 # We use -o to specify the exact filename Terraform expects
-go build -o "$BINARY_NAME"
+go build -ldflags "-X main.version=${VERSION}" -o "$BINARY_NAME"
 
 if [ $? -ne 0 ]; then
     echo "Error: Go build failed. Ensure you are in the provider source root and Go is installed."
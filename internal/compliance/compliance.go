@@ -0,0 +1,158 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compliance checks Iceberg tables against rules an organization
+// expects all of its tables to follow, such as required properties or a
+// minimum format version. Rules only read table metadata, so they can be
+// evaluated against a live table at plan time.
+package compliance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+)
+
+// Rule is a check of the metadata of a table.
+type Rule interface {
+	// Name identifies the rule in results.
+	Name() string
+	// Violations returns why meta breaks the rule, none if it follows it.
+	Violations(meta table.Metadata) []string
+}
+
+// Result is the outcome of a rule for one table.
+type Result struct {
+	Rule       string
+	Violations []string
+}
+
+// Passed reports whether the table follows the rule.
+func (r Result) Passed() bool {
+	return len(r.Violations) == 0
+}
+
+// Evaluate checks meta against rules, returning a result per rule in the
+// same order.
+func Evaluate(meta table.Metadata, rules ...Rule) []Result {
+	results := make([]Result, 0, len(rules))
+	for _, rule := range rules {
+		results = append(results, Result{Rule: rule.Name(), Violations: rule.Violations(meta)})
+	}
+
+	return results
+}
+
+// RequiredProperties requires the table to have each of the listed properties
+// with a non-empty value.
+type RequiredProperties []string
+
+func (RequiredProperties) Name() string { return "required_properties" }
+
+func (r RequiredProperties) Violations(meta table.Metadata) []string {
+	props := meta.Properties()
+
+	var violations []string
+	for _, key := range r {
+		if strings.TrimSpace(props[key]) == "" {
+			violations = append(violations, fmt.Sprintf("property %s is not set", key))
+		}
+	}
+
+	return violations
+}
+
+// Properties that limit how long snapshots are kept when they are expired.
+const (
+	MaxSnapshotAgeProperty     = "history.expire.max-snapshot-age-ms"
+	MinSnapshotsToKeepProperty = "history.expire.min-snapshots-to-keep"
+)
+
+// RetentionConfigured requires a snapshot retention policy: the table
+// properties for snapshot expiration, or a retention set on the main branch.
+type RetentionConfigured struct{}
+
+func (RetentionConfigured) Name() string { return "retention_configured" }
+
+func (RetentionConfigured) Violations(meta table.Metadata) []string {
+	props := meta.Properties()
+	if props[MaxSnapshotAgeProperty] != "" || props[MinSnapshotsToKeepProperty] != "" {
+		return nil
+	}
+	if ref := meta.Ref(); ref.MaxSnapshotAgeMs != nil || ref.MinSnapshotsToKeep != nil {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("snapshot retention is not configured: set %s or %s", MaxSnapshotAgeProperty, MinSnapshotsToKeepProperty)}
+}
+
+// MinFormatVersion requires the table to use at least this format version.
+type MinFormatVersion int
+
+func (MinFormatVersion) Name() string { return "min_format_version" }
+
+func (r MinFormatVersion) Violations(meta table.Metadata) []string {
+	if meta.Version() >= int(r) {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("format version is %d, at least %d is required", meta.Version(), int(r))}
+}
+
+// PartitionedByTime requires the current partition spec to partition by
+// time: with a year, month, day or hour transform, or by the identity of a
+// date or timestamp column.
+type PartitionedByTime struct{}
+
+func (PartitionedByTime) Name() string { return "partitioned_by_time" }
+
+func (PartitionedByTime) Violations(meta table.Metadata) []string {
+	spec := meta.PartitionSpec()
+	schema := meta.CurrentSchema()
+	var terms []string
+	for field := range spec.Fields() {
+		source, ok := schema.FindFieldByID(field.SourceID)
+		switch field.Transform.(type) {
+		case iceberg.YearTransform, iceberg.MonthTransform, iceberg.DayTransform, iceberg.HourTransform:
+			return nil
+		case iceberg.IdentityTransform:
+			if ok && isTimeType(source.Type) {
+				return nil
+			}
+		}
+		if !ok {
+			source.Name = strconv.Itoa(field.SourceID)
+		}
+		terms = append(terms, fmt.Sprintf("%s(%s)", field.Transform, source.Name))
+	}
+
+	if len(terms) == 0 {
+		return []string{"the table is not partitioned"}
+	}
+
+	return []string{"the table is partitioned by " + strings.Join(terms, ", ") + ", not by time"}
+}
+
+func isTimeType(t iceberg.Type) bool {
+	switch t.(type) {
+	case iceberg.DateType, iceberg.TimestampType, iceberg.TimestampTzType, iceberg.TimestampNsType, iceberg.TimestampTzNsType:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,145 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compliance
+
+import (
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = iceberg.NewSchema(0,
+	iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+	iceberg.NestedField{ID: 2, Name: "event_date", Type: iceberg.PrimitiveTypes.Date},
+	iceberg.NestedField{ID: 3, Name: "created_at", Type: iceberg.PrimitiveTypes.TimestampTz},
+	iceberg.NestedField{ID: 4, Name: "region", Type: iceberg.PrimitiveTypes.String},
+)
+
+func newMetadata(t *testing.T, spec iceberg.PartitionSpec, props iceberg.Properties) table.Metadata {
+	t.Helper()
+
+	meta, err := table.NewMetadata(testSchema, &spec, table.UnsortedSortOrder, "file:///tmp/warehouse/db/events", props)
+	require.NoError(t, err)
+
+	return meta
+}
+
+func partitionedBy(fields ...iceberg.PartitionField) iceberg.PartitionSpec {
+	for i := range fields {
+		fields[i].FieldID = 1000 + i
+	}
+
+	return iceberg.NewPartitionSpec(fields...)
+}
+
+func TestRequiredProperties(t *testing.T) {
+	meta := newMetadata(t, *iceberg.UnpartitionedSpec, iceberg.Properties{"owner": "data_eng", "comment": " "})
+
+	assert.Empty(t, RequiredProperties{"owner"}.Violations(meta))
+	assert.Equal(t, []string{"property comment is not set", "property team is not set"},
+		RequiredProperties{"owner", "comment", "team"}.Violations(meta))
+}
+
+func TestRetentionConfigured(t *testing.T) {
+	assert.Equal(t, []string{"snapshot retention is not configured: set history.expire.max-snapshot-age-ms or history.expire.min-snapshots-to-keep"},
+		RetentionConfigured{}.Violations(newMetadata(t, *iceberg.UnpartitionedSpec, nil)))
+	assert.Empty(t, RetentionConfigured{}.Violations(newMetadata(t, *iceberg.UnpartitionedSpec, iceberg.Properties{MaxSnapshotAgeProperty: "604800000"})))
+	assert.Empty(t, RetentionConfigured{}.Violations(newMetadata(t, *iceberg.UnpartitionedSpec, iceberg.Properties{MinSnapshotsToKeepProperty: "10"})))
+
+	t.Run("main branch", func(t *testing.T) {
+		meta, err := table.ParseMetadataBytes([]byte(`{
+  "format-version": 2,
+  "table-uuid": "9c12d441-03fe-4693-9a96-a0705ddf69c1",
+  "location": "file:///tmp/warehouse/db/events",
+  "last-sequence-number": 1,
+  "last-updated-ms": 1700000000000,
+  "last-column-id": 1,
+  "current-schema-id": 0,
+  "schemas": [{"type": "struct", "schema-id": 0, "fields": [{"id": 1, "name": "id", "type": "long", "required": true}]}],
+  "default-spec-id": 0,
+  "partition-specs": [{"spec-id": 0, "fields": []}],
+  "last-partition-id": 999,
+  "default-sort-order-id": 0,
+  "sort-orders": [{"order-id": 0, "fields": []}],
+  "properties": {},
+  "current-snapshot-id": 1,
+  "snapshots": [{"snapshot-id": 1, "sequence-number": 1, "timestamp-ms": 1700000000000, "manifest-list": "file:///tmp/warehouse/db/events/metadata/snap-1.avro", "summary": {"operation": "append"}, "schema-id": 0}],
+  "refs": {"main": {"snapshot-id": 1, "type": "branch", "min-snapshots-to-keep": 5}}
+}`))
+		require.NoError(t, err)
+		assert.Empty(t, RetentionConfigured{}.Violations(meta))
+	})
+}
+
+func TestMinFormatVersion(t *testing.T) {
+	v2 := newMetadata(t, *iceberg.UnpartitionedSpec, nil)
+	v3 := newMetadata(t, *iceberg.UnpartitionedSpec, iceberg.Properties{"format-version": "3"})
+
+	assert.Empty(t, MinFormatVersion(2).Violations(v2))
+	assert.Empty(t, MinFormatVersion(2).Violations(v3))
+	assert.Equal(t, []string{"format version is 2, at least 3 is required"}, MinFormatVersion(3).Violations(v2))
+}
+
+func TestPartitionedByTime(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		spec iceberg.PartitionSpec
+		want []string
+	}{
+		{
+			name: "day transform",
+			spec: partitionedBy(iceberg.PartitionField{SourceID: 3, Name: "created_at_day", Transform: iceberg.DayTransform{}}),
+		},
+		{
+			name: "identity of a date",
+			spec: partitionedBy(
+				iceberg.PartitionField{SourceID: 4, Name: "region", Transform: iceberg.IdentityTransform{}},
+				iceberg.PartitionField{SourceID: 2, Name: "event_date", Transform: iceberg.IdentityTransform{}},
+			),
+		},
+		{
+			name: "unpartitioned",
+			spec: *iceberg.UnpartitionedSpec,
+			want: []string{"the table is not partitioned"},
+		},
+		{
+			name: "not by time",
+			spec: partitionedBy(
+				iceberg.PartitionField{SourceID: 4, Name: "region", Transform: iceberg.IdentityTransform{}},
+				iceberg.PartitionField{SourceID: 1, Name: "id_bucket", Transform: iceberg.BucketTransform{NumBuckets: 16}},
+			),
+			want: []string{"the table is partitioned by identity(region), bucket[16](id), not by time"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, PartitionedByTime{}.Violations(newMetadata(t, tc.spec, nil)))
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	meta := newMetadata(t, *iceberg.UnpartitionedSpec, iceberg.Properties{"owner": "data_eng"})
+
+	results := Evaluate(meta, RequiredProperties{"owner"}, MinFormatVersion(3))
+	require.Len(t, results, 2)
+	assert.Equal(t, "required_properties", results[0].Rule)
+	assert.True(t, results[0].Passed())
+	assert.Equal(t, "min_format_version", results[1].Rule)
+	assert.False(t, results[1].Passed())
+}
@@ -0,0 +1,313 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package properties brings the properties a user manages on a catalog
+// object, such as a namespace, a table or a Polaris entity, in line with the
+// configuration without touching the properties the server or other clients
+// set. The user-managed keys are those of the configuration and of the
+// previous state; every other key on the server is server-managed.
+package properties
+
+import (
+	"maps"
+	"sort"
+	"strings"
+)
+
+// Delta is the set of changes needed to bring the user-managed properties of
+// a catalog object in line with the configuration.
+type Delta struct {
+	// Updates are the properties to set, keyed as in the configuration.
+	Updates map[string]string
+	// Removals are the keys to remove, spelled as on the server, sorted.
+	Removals []string
+}
+
+// IsEmpty reports whether d changes nothing.
+func (d Delta) IsEmpty() bool {
+	return len(d.Updates) == 0 && len(d.Removals) == 0
+}
+
+// Unremoved returns the removals of d that server still has after d was
+// applied, sorted, which catalogs that ignore property removals without an
+// error return.
+func (d Delta) Unremoved(server map[string]string) []string {
+	var unremoved []string
+	for _, key := range d.Removals {
+		if _, ok := server[key]; ok {
+			unremoved = append(unremoved, key)
+		}
+	}
+
+	return unremoved
+}
+
+// Keys matches the property keys of the configuration with the keys on the
+// server. The zero value matches keys exactly and reserves none.
+type Keys struct {
+	// IgnoreCase makes a configured key match a server key that differs only
+	// in case, for catalogs such as Glue that lowercase property keys. The
+	// configured spelling is kept in state and sent in updates, so the
+	// configuration doesn't show a diff, while removals use the server's
+	// spelling.
+	IgnoreCase bool
+	// ReservedPrefixes are the prefixes of the keys the server keeps for its
+	// own settings. They are never updated or removed, and are always
+	// server-managed.
+	ReservedPrefixes []string
+}
+
+// Reserved reports whether key is reserved by the server.
+func (k Keys) Reserved(key string) bool {
+	for _, prefix := range k.ReservedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithoutReserved returns the properties of props that aren't reserved.
+func (k Keys) WithoutReserved(props map[string]string) map[string]string {
+	result := make(map[string]string, len(props))
+	for key, v := range props {
+		if !k.Reserved(key) {
+			result[key] = v
+		}
+	}
+
+	return result
+}
+
+// ReservedKeys returns the reserved keys among keys, sorted, for rejecting
+// them in the configuration.
+func (k Keys) ReservedKeys(keys []string) []string {
+	var reserved []string
+	for _, key := range keys {
+		if k.Reserved(key) {
+			reserved = append(reserved, key)
+		}
+	}
+	sort.Strings(reserved)
+
+	return reserved
+}
+
+// Collision is a pair of configured keys that Keys can't tell apart, as they
+// would manage the same property on the server.
+type Collision struct {
+	First, Second string
+}
+
+// Collisions returns the keys of keys that collide with a key sorting before
+// them, in sorted order. Keys matched exactly never collide.
+func (k Keys) Collisions(keys []string) []Collision {
+	if !k.IgnoreCase {
+		return nil
+	}
+
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	seen := make(map[string]string, len(sorted))
+	var collisions []Collision
+	for _, key := range sorted {
+		if other, ok := seen[k.normalize(key)]; ok {
+			collisions = append(collisions, Collision{First: other, Second: key})
+
+			continue
+		}
+		seen[k.normalize(key)] = key
+	}
+
+	return collisions
+}
+
+func (k Keys) normalize(key string) string {
+	if k.IgnoreCase {
+		return strings.ToLower(key)
+	}
+
+	return key
+}
+
+// index maps the normalized keys of props to the keys themselves. When
+// several keys of props normalize alike, the smallest one is kept, so the
+// result doesn't depend on the order the server returned them in.
+func (k Keys) index(props map[string]string) map[string]string {
+	keys := make(map[string]string, len(props))
+	for key := range props {
+		if other, ok := keys[k.normalize(key)]; ok && other < key {
+			continue
+		}
+		keys[k.normalize(key)] = key
+	}
+
+	return keys
+}
+
+// match returns the key of props that key matches, using the index of props:
+// key itself if props has it, the indexed key otherwise.
+func (k Keys) match(index, props map[string]string, key string) (string, bool) {
+	if _, ok := props[key]; ok {
+		return key, true
+	}
+	match, ok := index[k.normalize(key)]
+
+	return match, ok
+}
+
+// Delta compares the desired properties with what the server currently has.
+// managed holds the keys Terraform managed before this change, which is what
+// allows removals without touching server-managed keys. current may be nil
+// when the server state is not known, in which case managed is used as the
+// best approximation; use Known for objects the server returned without
+// properties. Reserved keys are left out of the delta.
+func (k Keys) Delta(desired, managed, current map[string]string) Delta {
+	if current == nil {
+		current = managed
+	}
+	currentKeys := k.index(current)
+	desiredKeys := k.index(desired)
+
+	delta := Delta{Updates: make(map[string]string)}
+
+	for key, v := range desired {
+		if k.Reserved(key) {
+			continue
+		}
+		if serverKey, ok := k.match(currentKeys, current, key); !ok || !ValuesEqual(current[serverKey], v) {
+			delta.Updates[key] = v
+		}
+	}
+
+	for key := range managed {
+		if _, ok := desiredKeys[k.normalize(key)]; ok {
+			continue
+		}
+		if serverKey, ok := k.match(currentKeys, current, key); ok && !k.Reserved(serverKey) {
+			delta.Removals = append(delta.Removals, serverKey)
+		}
+	}
+	sort.Strings(delta.Removals)
+
+	return delta
+}
+
+// Reconcile returns the server values for the user-managed keys, after an
+// apply or on refresh. When the server holds a semantically equal value, the
+// managed spelling of the value and of the key is kept so that normalization
+// on the server side doesn't show up as a diff. Keys missing on the server are
+// dropped, reflecting that they were removed.
+func (k Keys) Reconcile(managed, server map[string]string) map[string]string {
+	serverKeys := k.index(server)
+
+	result := make(map[string]string, len(managed))
+	for key, want := range managed {
+		serverKey, ok := k.match(serverKeys, server, key)
+		if !ok {
+			continue
+		}
+		got := server[serverKey]
+		if ValuesEqual(want, got) {
+			result[key] = want
+		} else {
+			result[key] = got
+		}
+	}
+
+	return result
+}
+
+// ServerManaged returns the server properties that aren't managed by the
+// user, so plan consumers can tell both sets apart without set math. Reserved
+// keys are always included.
+func (k Keys) ServerManaged(managed, server map[string]string) map[string]string {
+	managedKeys := k.index(managed)
+
+	result := make(map[string]string, len(server))
+	for key, v := range server {
+		if _, ok := managedKeys[k.normalize(key)]; !ok || k.Reserved(key) {
+			result[key] = v
+		}
+	}
+
+	return result
+}
+
+// ComputeDelta is Keys.Delta with keys matched exactly.
+func ComputeDelta(desired, managed, current map[string]string) Delta {
+	return Keys{}.Delta(desired, managed, current)
+}
+
+// Reconcile is Keys.Reconcile with keys matched exactly.
+func Reconcile(managed, server map[string]string) map[string]string {
+	return Keys{}.Reconcile(managed, server)
+}
+
+// ServerManaged is Keys.ServerManaged with keys matched exactly.
+func ServerManaged(managed, server map[string]string) map[string]string {
+	return Keys{}.ServerManaged(managed, server)
+}
+
+// Apply returns a copy of props with delta applied, for APIs that replace all
+// properties of an object at once.
+func Apply(props map[string]string, delta Delta) map[string]string {
+	result := maps.Clone(props)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	maps.Copy(result, delta.Updates)
+	for _, key := range delta.Removals {
+		delete(result, key)
+	}
+
+	return result
+}
+
+// ValuesEqual reports whether two property values are semantically the same.
+// Values are compared exactly, except for booleans which some catalogs
+// normalize to lower case.
+func ValuesEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	if isBoolLiteral(a) && isBoolLiteral(b) {
+		return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+	}
+
+	return false
+}
+
+func isBoolLiteral(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "true", "false":
+		return true
+	}
+
+	return false
+}
+
+// Known returns props, or an empty map if props is nil. Catalogs return nil
+// for objects without properties, which Delta would otherwise take as "server
+// state unknown".
+func Known(props map[string]string) map[string]string {
+	if props == nil {
+		return map[string]string{}
+	}
+
+	return props
+}
@@ -0,0 +1,347 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package properties
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ignoreCase = Keys{IgnoreCase: true}
+	reserved   = Keys{ReservedPrefixes: []string{"polaris.", "internal."}}
+)
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		name         string
+		keys         Keys
+		desired      map[string]string
+		managed      map[string]string
+		current      map[string]string
+		wantUpdates  map[string]string
+		wantRemovals []string
+	}{
+		{
+			name:        "same keys in a different order",
+			desired:     map[string]string{"a": "1", "b": "2", "c": "3"},
+			managed:     map[string]string{"c": "3", "a": "1", "b": "2"},
+			current:     map[string]string{"b": "2", "c": "3", "a": "1"},
+			wantUpdates: map[string]string{},
+		},
+		{
+			name:        "boolean normalized by the server",
+			desired:     map[string]string{"gc.enabled": "True"},
+			managed:     map[string]string{"gc.enabled": "True"},
+			current:     map[string]string{"gc.enabled": "true"},
+			wantUpdates: map[string]string{},
+		},
+		{
+			name:        "value case difference is a real change",
+			desired:     map[string]string{"tier": "Gold"},
+			managed:     map[string]string{"tier": "gold"},
+			current:     map[string]string{"tier": "gold"},
+			wantUpdates: map[string]string{"tier": "Gold"},
+		},
+		{
+			name:        "new key",
+			desired:     map[string]string{"owner": "a", "tier": "gold"},
+			managed:     map[string]string{"owner": "a"},
+			current:     map[string]string{"owner": "a"},
+			wantUpdates: map[string]string{"tier": "gold"},
+		},
+		{
+			name:        "key set on the server by another client is taken over",
+			desired:     map[string]string{"tier": "gold"},
+			managed:     map[string]string{},
+			current:     map[string]string{"tier": "silver"},
+			wantUpdates: map[string]string{"tier": "gold"},
+		},
+		{
+			name:        "server-managed keys are left alone",
+			desired:     map[string]string{"owner": "a"},
+			managed:     map[string]string{"owner": "a"},
+			current:     map[string]string{"owner": "a", "location": "s3://bucket/ns"},
+			wantUpdates: map[string]string{},
+		},
+		{
+			name:         "removed managed key is removed on the server",
+			desired:      map[string]string{},
+			managed:      map[string]string{"owner": "a"},
+			current:      map[string]string{"owner": "a", "location": "s3://bucket/ns"},
+			wantUpdates:  map[string]string{},
+			wantRemovals: []string{"owner"},
+		},
+		{
+			name:         "removals are sorted",
+			desired:      map[string]string{},
+			managed:      map[string]string{"c": "3", "a": "1", "b": "2"},
+			current:      map[string]string{"a": "1", "b": "2", "c": "3"},
+			wantUpdates:  map[string]string{},
+			wantRemovals: []string{"a", "b", "c"},
+		},
+		{
+			name:        "removed managed key already gone on the server",
+			desired:     map[string]string{},
+			managed:     map[string]string{"owner": "a"},
+			current:     map[string]string{"location": "s3://bucket/ns"},
+			wantUpdates: map[string]string{},
+		},
+		{
+			name:        "state already matches the plan but the server drifted",
+			desired:     map[string]string{"owner": "a"},
+			managed:     map[string]string{"owner": "a"},
+			current:     map[string]string{"owner": "b"},
+			wantUpdates: map[string]string{"owner": "a"},
+		},
+		{
+			name:        "object without properties",
+			desired:     map[string]string{"owner": "a"},
+			managed:     map[string]string{"owner": "a"},
+			current:     Known(nil),
+			wantUpdates: map[string]string{"owner": "a"},
+		},
+		{
+			name:         "unknown server state falls back to managed",
+			desired:      map[string]string{"owner": "b"},
+			managed:      map[string]string{"owner": "a", "tier": "gold"},
+			current:      nil,
+			wantUpdates:  map[string]string{"owner": "b"},
+			wantRemovals: []string{"tier"},
+		},
+		{
+			name:        "key case ignored",
+			keys:        ignoreCase,
+			desired:     map[string]string{"Description": "Sales"},
+			managed:     map[string]string{"Description": "Sales"},
+			current:     map[string]string{"description": "Sales"},
+			wantUpdates: map[string]string{},
+		},
+		{
+			name:        "key case ignored, updates keep the configured spelling",
+			keys:        ignoreCase,
+			desired:     map[string]string{"Description": "Orders"},
+			managed:     map[string]string{"Description": "Sales"},
+			current:     map[string]string{"description": "Sales"},
+			wantUpdates: map[string]string{"Description": "Orders"},
+		},
+		{
+			name:         "key case ignored, removals use the server's spelling",
+			keys:         ignoreCase,
+			desired:      map[string]string{},
+			managed:      map[string]string{"Description": "Sales"},
+			current:      map[string]string{"description": "Sales", "location": "s3://bucket/ns"},
+			wantUpdates:  map[string]string{},
+			wantRemovals: []string{"description"},
+		},
+		{
+			name:        "key case matters by default",
+			desired:     map[string]string{"Description": "Sales"},
+			managed:     map[string]string{"Description": "Sales"},
+			current:     map[string]string{"description": "Sales"},
+			wantUpdates: map[string]string{"Description": "Sales"},
+		},
+		{
+			name:        "reserved keys aren't updated",
+			keys:        reserved,
+			desired:     map[string]string{"polaris.internal": "x", "env": "prod"},
+			managed:     map[string]string{},
+			current:     map[string]string{"polaris.internal": "y"},
+			wantUpdates: map[string]string{"env": "prod"},
+		},
+		{
+			name:         "reserved keys aren't removed",
+			keys:         reserved,
+			desired:      map[string]string{},
+			managed:      map[string]string{"internal.id": "1", "env": "prod"},
+			current:      map[string]string{"internal.id": "1", "env": "prod"},
+			wantUpdates:  map[string]string{},
+			wantRemovals: []string{"env"},
+		},
+		{
+			name:         "reserved prefixes are matched exactly",
+			keys:         reserved,
+			desired:      map[string]string{"polaris": "x"},
+			managed:      map[string]string{"Polaris.id": "1"},
+			current:      map[string]string{"Polaris.id": "1"},
+			wantUpdates:  map[string]string{"polaris": "x"},
+			wantRemovals: []string{"Polaris.id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta := tt.keys.Delta(tt.desired, tt.managed, tt.current)
+			assert.Equal(t, tt.wantUpdates, delta.Updates)
+			assert.Equal(t, tt.wantRemovals, delta.Removals)
+			assert.Equal(t, len(tt.wantUpdates) == 0 && len(tt.wantRemovals) == 0, delta.IsEmpty())
+
+			if tt.keys.IgnoreCase || len(tt.keys.ReservedPrefixes) > 0 {
+				return
+			}
+			assert.Equal(t, delta, ComputeDelta(tt.desired, tt.managed, tt.current))
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    Keys
+		managed map[string]string
+		server  map[string]string
+		want    map[string]string
+	}{
+		{
+			name:    "server values of the managed keys",
+			managed: map[string]string{"gc.enabled": "True", "owner": "a", "gone": "x"},
+			server:  map[string]string{"gc.enabled": "true", "owner": "b", "location": "s3://bucket"},
+			want:    map[string]string{"gc.enabled": "True", "owner": "b"},
+		},
+		{
+			name:    "nothing managed",
+			managed: map[string]string{},
+			server:  map[string]string{"location": "s3://bucket"},
+			want:    map[string]string{},
+		},
+		{
+			name:    "no server properties",
+			managed: map[string]string{"owner": "a"},
+			server:  nil,
+			want:    map[string]string{},
+		},
+		{
+			name:    "key case ignored keeps the managed spelling",
+			keys:    ignoreCase,
+			managed: map[string]string{"Description": "Sales", "Gone": "x"},
+			server:  map[string]string{"description": "Sales", "location": "s3://bucket/ns"},
+			want:    map[string]string{"Description": "Sales"},
+		},
+		{
+			name:    "key case matters by default",
+			managed: map[string]string{"Description": "Sales"},
+			server:  map[string]string{"description": "Sales"},
+			want:    map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.keys.Reconcile(tt.managed, tt.server))
+			if !tt.keys.IgnoreCase {
+				assert.Equal(t, tt.want, Reconcile(tt.managed, tt.server))
+			}
+		})
+	}
+}
+
+func TestServerKeysDifferingInCase(t *testing.T) {
+	// Maps are iterated in random order, so a few rounds catch a result that
+	// depends on the order of the server keys.
+	server := map[string]string{"owner": "a", "OWNER": "b", "Owner": "c"}
+	for range 20 {
+		assert.Equal(t, map[string]string{"owner": "a"}, ignoreCase.Reconcile(map[string]string{"owner": "a"}, server), "an exact match wins")
+		assert.Equal(t, map[string]string{"oWnEr": "b"}, ignoreCase.Reconcile(map[string]string{"oWnEr": "x"}, server), "the smallest key wins")
+		assert.Equal(t, Delta{Updates: map[string]string{"oWnEr": "a"}},
+			ignoreCase.Delta(map[string]string{"oWnEr": "a"}, map[string]string{"oWnEr": "b"}, server))
+		assert.Equal(t, []string{"OWNER"},
+			ignoreCase.Delta(map[string]string{}, map[string]string{"oWnEr": "b"}, server).Removals)
+	}
+}
+
+func TestUnremoved(t *testing.T) {
+	delta := Delta{Updates: map[string]string{"env": "prod"}, Removals: []string{"owner", "team"}}
+
+	assert.Equal(t, []string{"owner", "team"}, delta.Unremoved(map[string]string{"env": "prod", "team": "data", "owner": "a"}))
+	assert.Equal(t, []string{"team"}, delta.Unremoved(map[string]string{"env": "prod", "team": "data"}))
+	assert.Empty(t, delta.Unremoved(map[string]string{"env": "prod"}))
+	assert.Empty(t, delta.Unremoved(nil))
+	assert.Empty(t, Delta{}.Unremoved(map[string]string{"owner": "a"}))
+}
+
+func TestServerManaged(t *testing.T) {
+	server := map[string]string{"description": "Sales", "location": "s3://bucket/ns", "polaris.id": "1"}
+
+	assert.Equal(t, map[string]string{"location": "s3://bucket/ns", "polaris.id": "1"},
+		ServerManaged(map[string]string{"description": "x"}, server))
+	assert.Equal(t, server, ServerManaged(nil, server))
+	assert.Equal(t, map[string]string{"location": "s3://bucket/ns", "polaris.id": "1"},
+		ignoreCase.ServerManaged(map[string]string{"Description": "Sales"}, server))
+	assert.Equal(t, map[string]string{"description": "Sales", "location": "s3://bucket/ns", "polaris.id": "1"},
+		reserved.ServerManaged(map[string]string{"polaris.id": "1"}, server), "reserved keys are always server-managed")
+}
+
+func TestApply(t *testing.T) {
+	props := map[string]string{"env": "dev", "team": "data", "polaris.id": "1"}
+	delta := reserved.Delta(
+		map[string]string{"env": "prod", "polaris.id": "2"},
+		map[string]string{"env": "dev", "team": "data"},
+		props,
+	)
+
+	assert.Equal(t, map[string]string{"env": "prod", "polaris.id": "1"}, Apply(props, delta))
+	assert.Equal(t, map[string]string{"env": "dev", "team": "data", "polaris.id": "1"}, props, "props isn't changed")
+	assert.Equal(t, map[string]string{"env": "prod"}, Apply(nil, Delta{Updates: map[string]string{"env": "prod"}}))
+	assert.Equal(t, map[string]string{}, Apply(nil, Delta{}))
+}
+
+func TestReservedKeys(t *testing.T) {
+	assert.True(t, reserved.Reserved("polaris.id"))
+	assert.True(t, reserved.Reserved("internal.x"))
+	assert.False(t, reserved.Reserved("polaris"))
+	assert.False(t, Keys{}.Reserved("polaris.id"))
+
+	assert.Equal(t, []string{"internal.x", "polaris.id"}, reserved.ReservedKeys([]string{"polaris.id", "env", "internal.x"}))
+	assert.Empty(t, reserved.ReservedKeys([]string{"env"}))
+	assert.Equal(t, map[string]string{"env": "prod"}, reserved.WithoutReserved(map[string]string{"env": "prod", "polaris.id": "1"}))
+}
+
+func TestCollisions(t *testing.T) {
+	keys := []string{"owner", "description", "Description", "DESCRIPTION"}
+
+	assert.Empty(t, Keys{}.Collisions(keys))
+	assert.Equal(t, []Collision{
+		{First: "DESCRIPTION", Second: "Description"},
+		{First: "DESCRIPTION", Second: "description"},
+	}, ignoreCase.Collisions(keys))
+	assert.Empty(t, ignoreCase.Collisions([]string{"owner", "description"}))
+}
+
+func TestValuesEqual(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{"gold", "gold", true},
+		{"", "", true},
+		{"True", "true", true},
+		{"FALSE", "false", true},
+		{" true", "TRUE ", true},
+		{"true", "false", false},
+		{"Gold", "gold", false},
+		{"true", "yes", false},
+		{"1", "true", false},
+	} {
+		assert.Equal(t, tc.want, ValuesEqual(tc.a, tc.b), "%q %q", tc.a, tc.b)
+	}
+}
+
+func TestKnown(t *testing.T) {
+	assert.Equal(t, map[string]string{}, Known(nil))
+	props := map[string]string{"owner": "a"}
+	assert.Equal(t, props, Known(props))
+}
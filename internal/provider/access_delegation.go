@@ -0,0 +1,67 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// accessDelegationHeader asks REST catalogs to return storage credentials, or
+// to sign storage requests, with the tables they load and create. Catalogs may
+// return different metadata and config for a table depending on it.
+const accessDelegationHeader = "X-Iceberg-Access-Delegation"
+
+// accessDelegationModes are the values access_delegation allows.
+var accessDelegationModes = []string{"vended-credentials", "remote-signing"}
+
+// accessDelegationConfig returns access_delegation, or "" if it isn't set.
+func accessDelegationConfig(data icebergProviderModel, diags *diag.Diagnostics) string {
+	if data.AccessDelegation.IsNull() || data.AccessDelegation.IsUnknown() {
+		return ""
+	}
+
+	mode := data.AccessDelegation.ValueString()
+	if !slices.Contains(accessDelegationModes, mode) {
+		diags.AddAttributeError(path.Root("access_delegation"), "Invalid access_delegation",
+			fmt.Sprintf("access_delegation must be one of %s, got %q.", strings.Join(accessDelegationModes, ", "), mode))
+
+		return ""
+	}
+
+	return mode
+}
+
+// setAccessDelegation sends mode in the access delegation header of table
+// loads and creates, and no header with other requests or if mode is empty.
+// iceberg-go asks for vended credentials with every request, which the
+// provider never uses.
+func setAccessDelegation(req *http.Request, mode string) {
+	req.Header.Del(accessDelegationHeader)
+	if mode == "" {
+		return
+	}
+
+	switch requestEndpointFamily(req) {
+	case familyLoadTable, familyCreateTable:
+		req.Header.Set(accessDelegationHeader, mode)
+	}
+}
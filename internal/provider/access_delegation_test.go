@@ -0,0 +1,92 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderAccessDelegation(t *testing.T) {
+	ctx := context.Background()
+
+	for _, mode := range []string{"", "vended-credentials", "remote-signing"} {
+		t.Run("mode "+mode, func(t *testing.T) {
+			m := newMockRESTCatalog()
+			m.addTable("db", "events", nil)
+			backend := m.start(t)
+
+			// The access delegation header of every request, by method and
+			// path; absent headers are recorded as "".
+			var mu sync.Mutex
+			headers := make(map[string]string)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				headers[r.Method+" "+r.URL.Path] = r.Header.Get(accessDelegationHeader)
+				mu.Unlock()
+				backend.Config.Handler.ServeHTTP(w, r)
+			}))
+			t.Cleanup(server.Close)
+
+			attributes := map[string]tftypes.Value{
+				"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+			}
+			if mode != "" {
+				attributes["access_delegation"] = tftypes.NewValue(tftypes.String, mode)
+			}
+			p, diags := configureTestProvider(t, attributes)
+			require.False(t, diags.HasError(), "%v", diags)
+
+			cat, err := p.NewCatalog(ctx, "iceberg_table")
+			require.NoError(t, err)
+			_, err = cat.LoadTable(ctx, catalog.ToIdentifier("db", "events"))
+			require.NoError(t, err)
+			_, err = cat.CreateTable(ctx, catalog.ToIdentifier("db", "orders"), iceberg.NewSchema(0,
+				iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true}))
+			require.NoError(t, err)
+			_, err = cat.LoadNamespaceProperties(ctx, catalog.ToIdentifier("db"))
+			require.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, map[string]string{
+				"GET /v1/config":                      "",
+				"GET /v1/namespaces/db/tables/events": mode,
+				"POST /v1/namespaces/db/tables":       mode,
+				"GET /v1/namespaces/db":               "",
+			}, headers)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri":       tftypes.NewValue(tftypes.String, "http://catalog"),
+			"access_delegation": tftypes.NewValue(tftypes.String, "vended"),
+		})
+		require.True(t, diags.HasError())
+		assert.Equal(t, "Invalid access_delegation", diags.Errors()[0].Summary())
+		assert.Contains(t, diags.Errors()[0].Detail(), "vended-credentials, remote-signing")
+	})
+}
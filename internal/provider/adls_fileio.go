@@ -0,0 +1,158 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"os"
+
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The service principal properties, named as in PyIceberg. iceberg-go has no
+// constants for them: its FileIO reads service principals from the
+// environment variables of adlsServicePrincipalEnvVars instead.
+const (
+	adlsTenantID     = "adls.tenant-id"
+	adlsClientID     = "adls.client-id"
+	adlsClientSecret = "adls.client-secret"
+)
+
+// adlsServicePrincipalEnvVars are the environment variables the default Azure
+// credential chain takes a service principal from, by property.
+var adlsServicePrincipalEnvVars = map[string]string{
+	adlsTenantID:     "AZURE_TENANT_ID",
+	adlsClientID:     "AZURE_CLIENT_ID",
+	adlsClientSecret: "AZURE_CLIENT_SECRET",
+}
+
+// adlsStorageDomains are the domains of the hosts of abfss:// and wasbs://
+// locations, which SAS tokens are keyed by.
+var adlsStorageDomains = []string{"dfs.core.windows.net", "blob.core.windows.net"}
+
+// adlsModel is the adls block, the Azure Data Lake Storage FileIO
+// configuration the provider writes and reads table metadata files with, like
+// the s3 block.
+type adlsModel struct {
+	AccountName  types.String `tfsdk:"account_name"`
+	AccountKey   types.String `tfsdk:"account_key"`
+	SASToken     types.String `tfsdk:"sas_token"`
+	TenantID     types.String `tfsdk:"tenant_id"`
+	ClientID     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+}
+
+// adlsBlock returns the schema of the adls block.
+func adlsBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Azure Data Lake Storage settings for the table metadata files the provider writes and reads itself with type = 'glue', 'sql' or 'memory', for warehouses such as abfss://container@account.dfs.core.windows.net/warehouse. REST catalogs write them on the server, so the block isn't allowed with them. Without it, the default Azure credential chain is used.",
+		Attributes: map[string]schema.Attribute{
+			"account_name": schema.StringAttribute{
+				Description: "The storage account name. Required with account_key or sas_token.",
+				Optional:    true,
+			},
+			"account_key": schema.StringAttribute{
+				Description: "The shared key of the storage account.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"sas_token": schema.StringAttribute{
+				Description: "A shared access signature for the storage account. Can't be set with account_key.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"tenant_id": schema.StringAttribute{
+				Description: "The Microsoft Entra tenant of the service principal. Requires client_id and client_secret. The service principal is also exported to the AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET environment variables of the provider process, which is where the Azure FileIO reads it from, so all provider configurations with an adls block must use the same one.",
+				Optional:    true,
+			},
+			"client_id": schema.StringAttribute{
+				Description: "The client ID of the service principal. Requires tenant_id and client_secret.",
+				Optional:    true,
+			},
+			"client_secret": schema.StringAttribute{
+				Description: "The client secret of the service principal. Requires tenant_id and client_id.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+}
+
+// adlsConfig returns the FileIO properties of the adls block of data, nil if
+// it isn't set. Invalid settings are reported as errors.
+func adlsConfig(data icebergProviderModel, diags *diag.Diagnostics) iceberg.Properties {
+	if data.ADLS == nil {
+		return nil
+	}
+	adls := data.ADLS
+	accountName := adls.AccountName.ValueString()
+
+	if accountName == "" && (adls.AccountKey.ValueString() != "" || adls.SASToken.ValueString() != "") {
+		diags.AddAttributeError(path.Root("adls").AtName("account_name"), "Invalid adls configuration",
+			"adls.account_name is required with adls.account_key or adls.sas_token.")
+	}
+	if !adls.AccountKey.IsNull() && !adls.SASToken.IsNull() {
+		diags.AddAttributeError(path.Root("adls"), "Invalid adls configuration",
+			"adls.account_key and adls.sas_token can't be set together.")
+	}
+	if noTenant := adls.TenantID.IsNull(); adls.ClientID.IsNull() != noTenant || adls.ClientSecret.IsNull() != noTenant {
+		diags.AddAttributeError(path.Root("adls"), "Invalid adls configuration",
+			"adls.tenant_id, adls.client_id and adls.client_secret must be set together.")
+	}
+	if diags.HasError() {
+		return nil
+	}
+
+	props := iceberg.Properties{}
+	if key := adls.AccountKey.ValueString(); key != "" {
+		props[iceio.ADLSSharedKeyAccountName] = accountName
+		props[iceio.ADLSSharedKeyAccountKey] = key
+	}
+	if token := adls.SASToken.ValueString(); token != "" {
+		for _, domain := range adlsStorageDomains {
+			props[iceio.ADLSSasTokenPrefix+accountName+"."+domain] = token
+		}
+	}
+	for key, value := range map[string]types.String{
+		adlsTenantID:     adls.TenantID,
+		adlsClientID:     adls.ClientID,
+		adlsClientSecret: adls.ClientSecret,
+	} {
+		if value.ValueString() != "" {
+			props[key] = value.ValueString()
+		}
+	}
+
+	return props
+}
+
+// exportADLSServicePrincipal sets the environment variables the Azure FileIO
+// reads a service principal from to the service principal of props, if any.
+func exportADLSServicePrincipal(props iceberg.Properties) error {
+	for key, envVar := range adlsServicePrincipalEnvVars {
+		if value, ok := props[key]; ok {
+			if err := os.Setenv(envVar, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"maps"
+	"os"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// adlsBlockValue returns an adls block of the provider schema with attrs set.
+func adlsBlockValue(attrs map[string]tftypes.Value) tftypes.Value {
+	attrTypes := map[string]tftypes.Type{
+		"account_name":  tftypes.String,
+		"account_key":   tftypes.String,
+		"sas_token":     tftypes.String,
+		"tenant_id":     tftypes.String,
+		"client_id":     tftypes.String,
+		"client_secret": tftypes.String,
+	}
+	values := make(map[string]tftypes.Value, len(attrTypes))
+	for name, typ := range attrTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	maps.Copy(values, attrs)
+
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: attrTypes}, values)
+}
+
+func TestProviderADLSConfig(t *testing.T) {
+	// The service principal is exported to these; t.Setenv restores them.
+	for _, envVar := range adlsServicePrincipalEnvVars {
+		t.Setenv(envVar, "")
+	}
+
+	for _, tc := range []struct {
+		name      string
+		adls      map[string]tftypes.Value
+		want      iceberg.Properties
+		wantError string
+	}{
+		{
+			name: "shared key",
+			adls: map[string]tftypes.Value{
+				"account_name": tftypes.NewValue(tftypes.String, "lake"),
+				"account_key":  tftypes.NewValue(tftypes.String, "a2V5"),
+			},
+			want: iceberg.Properties{
+				"adls.auth.shared-key.account.name": "lake",
+				"adls.auth.shared-key.account.key":  "a2V5",
+			},
+		},
+		{
+			name: "sas token",
+			adls: map[string]tftypes.Value{
+				"account_name": tftypes.NewValue(tftypes.String, "lake"),
+				"sas_token":    tftypes.NewValue(tftypes.String, "sv=2024&sig=abc"),
+			},
+			want: iceberg.Properties{
+				"adls.sas-token.lake.dfs.core.windows.net":  "sv=2024&sig=abc",
+				"adls.sas-token.lake.blob.core.windows.net": "sv=2024&sig=abc",
+			},
+		},
+		{
+			name: "service principal",
+			adls: map[string]tftypes.Value{
+				"tenant_id":     tftypes.NewValue(tftypes.String, "tenant"),
+				"client_id":     tftypes.NewValue(tftypes.String, "client"),
+				"client_secret": tftypes.NewValue(tftypes.String, "secret"),
+			},
+			want: iceberg.Properties{
+				"adls.tenant-id":     "tenant",
+				"adls.client-id":     "client",
+				"adls.client-secret": "secret",
+			},
+		},
+		{
+			name: "account name alone",
+			adls: map[string]tftypes.Value{
+				"account_name": tftypes.NewValue(tftypes.String, "lake"),
+			},
+			want: iceberg.Properties{},
+		},
+		{
+			name: "empty block",
+			adls: map[string]tftypes.Value{},
+			want: iceberg.Properties{},
+		},
+		{
+			name: "account key without account name",
+			adls: map[string]tftypes.Value{
+				"account_key": tftypes.NewValue(tftypes.String, "a2V5"),
+			},
+			wantError: "adls.account_name is required with adls.account_key or adls.sas_token.",
+		},
+		{
+			name: "sas token without account name",
+			adls: map[string]tftypes.Value{
+				"sas_token": tftypes.NewValue(tftypes.String, "sv=2024&sig=abc"),
+			},
+			wantError: "adls.account_name is required with adls.account_key or adls.sas_token.",
+		},
+		{
+			name: "account key and sas token",
+			adls: map[string]tftypes.Value{
+				"account_name": tftypes.NewValue(tftypes.String, "lake"),
+				"account_key":  tftypes.NewValue(tftypes.String, "a2V5"),
+				"sas_token":    tftypes.NewValue(tftypes.String, "sv=2024&sig=abc"),
+			},
+			wantError: "adls.account_key and adls.sas_token can't be set together.",
+		},
+		{
+			name: "partial service principal",
+			adls: map[string]tftypes.Value{
+				"tenant_id": tftypes.NewValue(tftypes.String, "tenant"),
+				"client_id": tftypes.NewValue(tftypes.String, "client"),
+			},
+			wantError: "adls.tenant_id, adls.client_id and adls.client_secret must be set together.",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, diags := configureTestProvider(t, map[string]tftypes.Value{
+				"type": tftypes.NewValue(tftypes.String, "memory"),
+				"adls": adlsBlockValue(tc.adls),
+			})
+			if tc.wantError != "" {
+				require.True(t, diags.HasError())
+				assert.Equal(t, "Invalid adls configuration", diags.Errors()[0].Summary())
+				assert.Equal(t, tc.wantError, diags.Errors()[0].Detail())
+
+				return
+			}
+			require.False(t, diags.HasError(), "%v", diags)
+			assert.Equal(t, tc.want, p.fileIO)
+			for k, v := range p.fileIO {
+				assert.NotEmpty(t, v, k)
+			}
+		})
+	}
+
+	t.Run("service principal exported", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "memory"),
+			"adls": adlsBlockValue(map[string]tftypes.Value{
+				"tenant_id":     tftypes.NewValue(tftypes.String, "tenant"),
+				"client_id":     tftypes.NewValue(tftypes.String, "client"),
+				"client_secret": tftypes.NewValue(tftypes.String, "secret"),
+			}),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, "tenant", os.Getenv("AZURE_TENANT_ID"))
+		assert.Equal(t, "client", os.Getenv("AZURE_CLIENT_ID"))
+		assert.Equal(t, "secret", os.Getenv("AZURE_CLIENT_SECRET"))
+	})
+
+	t.Run("merged with s3", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "memory"),
+			"s3": s3BlockValue(map[string]tftypes.Value{
+				"region": tftypes.NewValue(tftypes.String, "us-east-1"),
+			}),
+			"adls": adlsBlockValue(map[string]tftypes.Value{
+				"account_name": tftypes.NewValue(tftypes.String, "lake"),
+				"account_key":  tftypes.NewValue(tftypes.String, "a2V5"),
+			}),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, iceberg.Properties{
+			"s3.region":                         "us-east-1",
+			"adls.auth.shared-key.account.name": "lake",
+			"adls.auth.shared-key.account.key":  "a2V5",
+		}, p.fileIO)
+	})
+
+	t.Run("rest catalog", func(t *testing.T) {
+		var diags diag.Diagnostics
+		validateCatalogTypeConfig(icebergProviderModel{
+			Type:       types.StringValue("rest"),
+			CatalogURI: types.StringValue("http://localhost:8181"),
+			ADLS:       &adlsModel{},
+		}, &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Invalid adls configuration", diags.Errors()[0].Summary())
+	})
+}
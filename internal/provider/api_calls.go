@@ -0,0 +1,207 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// endpointFamily groups the API calls counted together for quota planning.
+type endpointFamily int
+
+const (
+	familyConfig endpointFamily = iota
+	familyOAuthToken
+	familyListNamespaces
+	familyLoadNamespace
+	familyCreateNamespace
+	familyUpdateNamespace
+	familyDropNamespace
+	familyListTables
+	familyLoadTable
+	familyCreateTable
+	familyCommitTable
+	familyRenameTable
+	familyDropTable
+	familyViews
+	familyManagement
+	familyOther
+	numEndpointFamilies
+)
+
+// endpointFamilyNames are the names of the families, in the order of their
+// constants.
+var endpointFamilyNames = [numEndpointFamilies]string{
+	"config",
+	"oauth_token",
+	"list_namespaces",
+	"load_namespace",
+	"create_namespace",
+	"update_namespace",
+	"drop_namespace",
+	"list_tables",
+	"load_table",
+	"create_table",
+	"commit_table",
+	"rename_table",
+	"drop_table",
+	"views",
+	"management",
+	"other",
+}
+
+// glueEndpointFamilies are the families of the AWS Glue actions the provider
+// uses. Glue calls of other actions count as "other".
+var glueEndpointFamilies = map[string]endpointFamily{
+	"GetDatabases":   familyListNamespaces,
+	"GetDatabase":    familyLoadNamespace,
+	"CreateDatabase": familyCreateNamespace,
+	"UpdateDatabase": familyUpdateNamespace,
+	"DeleteDatabase": familyDropNamespace,
+	"GetTables":      familyListTables,
+	"GetTable":       familyLoadTable,
+	"CreateTable":    familyCreateTable,
+	"UpdateTable":    familyCommitTable,
+	"DeleteTable":    familyDropTable,
+}
+
+// apiCallCounter counts the calls a provider instance sends, by endpoint
+// family. The counters are atomic since the resources of a run are refreshed
+// and applied concurrently.
+type apiCallCounter struct {
+	counts [numEndpointFamilies]atomic.Int64
+}
+
+// snapshot returns the number of calls of every family, including those with
+// none.
+func (c *apiCallCounter) snapshot() map[string]int64 {
+	counts := make(map[string]int64, numEndpointFamilies)
+	for family, name := range endpointFamilyNames {
+		counts[name] = c.counts[family].Load()
+	}
+
+	return counts
+}
+
+// writeSummary writes the families with calls on one line, in the order of
+// endpointFamilyNames.
+func (c *apiCallCounter) writeSummary(w io.Writer) error {
+	var calls []string
+	var total int64
+	for family, name := range endpointFamilyNames {
+		if n := c.counts[family].Load(); n > 0 {
+			calls = append(calls, fmt.Sprintf("%s %d", name, n))
+			total += n
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "API calls: %d (%s)\n", total, strings.Join(calls, ", "))
+
+	return err
+}
+
+// apiCallTransport counts every request it sends, retries included, since
+// catalogs that bill per call bill each attempt.
+type apiCallTransport struct {
+	next    http.RoundTripper
+	counter *apiCallCounter
+}
+
+func (t *apiCallTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.counter.counts[requestEndpointFamily(req)].Add(1)
+
+	return t.next.RoundTrip(req)
+}
+
+// requestEndpointFamily returns the family of req, a request to the REST
+// catalog, the OAuth2 token endpoint, a management API or AWS Glue.
+func requestEndpointFamily(req *http.Request) endpointFamily {
+	if target := req.Header.Get("X-Amz-Target"); target != "" {
+		if family, ok := glueEndpointFamilies[target[strings.LastIndex(target, ".")+1:]]; ok {
+			return family
+		}
+
+		return familyOther
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, segment := range segments {
+		next := ""
+		if i+1 < len(segments) {
+			next = segments[i+1]
+		}
+		switch {
+		case segment == "management":
+			return familyManagement
+		case segment == "oauth" && next == "tokens":
+			return familyOAuthToken
+		case segment == "config" && i == len(segments)-1:
+			return familyConfig
+		case segment == "namespaces":
+			return namespaceEndpointFamily(req.Method, segments[i+1:])
+		case segment == "tables" && next == "rename":
+			return familyRenameTable
+		case segment == "views":
+			return familyViews
+		case segment == "transactions" && next == "commit":
+			return familyCommitTable
+		}
+	}
+
+	return familyOther
+}
+
+// namespaceEndpointFamily returns the family of a request below the
+// namespaces endpoint of the REST catalog; segments follow "namespaces".
+func namespaceEndpointFamily(method string, segments []string) endpointFamily {
+	switch {
+	case len(segments) == 0 && method == http.MethodPost:
+		return familyCreateNamespace
+	case len(segments) == 0:
+		return familyListNamespaces
+	case len(segments) == 1 && method == http.MethodDelete:
+		return familyDropNamespace
+	case len(segments) == 1:
+		return familyLoadNamespace
+	case segments[1] == "properties":
+		return familyUpdateNamespace
+	case segments[1] == "views":
+		return familyViews
+	case segments[1] == "register":
+		return familyCreateTable
+	case segments[1] != "tables":
+		return familyOther
+	case len(segments) == 2 && method == http.MethodPost:
+		return familyCreateTable
+	case len(segments) == 2:
+		return familyListTables
+	case len(segments) > 3:
+		// Metrics reports and credential requests of a table.
+		return familyOther
+	case method == http.MethodDelete:
+		return familyDropTable
+	case method == http.MethodPost:
+		return familyCommitTable
+	default:
+		return familyLoadTable
+	}
+}
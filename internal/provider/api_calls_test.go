@@ -0,0 +1,144 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestEndpointFamily(t *testing.T) {
+	for _, tc := range []struct {
+		method string
+		path   string
+		target string
+		want   string
+	}{
+		{http.MethodGet, "/api/catalog/v1/config", "", "config"},
+		{http.MethodPost, "/api/catalog/v1/oauth/tokens", "", "oauth_token"},
+		{http.MethodGet, "/v1/prefix/namespaces", "", "list_namespaces"},
+		{http.MethodPost, "/v1/prefix/namespaces", "", "create_namespace"},
+		{http.MethodGet, "/v1/prefix/namespaces/db", "", "load_namespace"},
+		{http.MethodHead, "/v1/prefix/namespaces/db", "", "load_namespace"},
+		{http.MethodDelete, "/v1/prefix/namespaces/db", "", "drop_namespace"},
+		{http.MethodPost, "/v1/prefix/namespaces/db/properties", "", "update_namespace"},
+		{http.MethodGet, "/v1/prefix/namespaces/db/tables", "", "list_tables"},
+		{http.MethodPost, "/v1/prefix/namespaces/db/tables", "", "create_table"},
+		{http.MethodPost, "/v1/prefix/namespaces/db/register", "", "create_table"},
+		{http.MethodGet, "/v1/prefix/namespaces/db/tables/config", "", "load_table"},
+		{http.MethodHead, "/v1/prefix/namespaces/db/tables/events", "", "load_table"},
+		{http.MethodPost, "/v1/prefix/namespaces/db/tables/events", "", "commit_table"},
+		{http.MethodPost, "/v1/prefix/transactions/commit", "", "commit_table"},
+		{http.MethodDelete, "/v1/prefix/namespaces/db/tables/events", "", "drop_table"},
+		{http.MethodPost, "/v1/prefix/namespaces/db/tables/events/metrics", "", "other"},
+		{http.MethodPost, "/v1/prefix/tables/rename", "", "rename_table"},
+		{http.MethodGet, "/v1/prefix/namespaces/db/views/daily", "", "views"},
+		{http.MethodPost, "/v1/prefix/views/rename", "", "views"},
+		{http.MethodGet, "/api/management/v1/principals", "", "management"},
+		{http.MethodGet, "/management/v1/warehouse", "", "management"},
+		{http.MethodPost, "/", "AWSGlue.GetTable", "load_table"},
+		{http.MethodPost, "/", "AWSGlue.UpdateTable", "commit_table"},
+		{http.MethodPost, "/", "AWSGlue.GetPartitions", "other"},
+		{http.MethodGet, "/healthz", "", "other"},
+	} {
+		t.Run(tc.method+" "+tc.path+tc.target, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://catalog"+tc.path, nil)
+			if tc.target != "" {
+				req.Header.Set("X-Amz-Target", tc.target)
+			}
+			assert.Equal(t, tc.want, endpointFamilyNames[requestEndpointFamily(req)])
+		})
+	}
+}
+
+func TestAPICallTransportConcurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	counter := &apiCallCounter{}
+	client := &http.Client{Transport: &apiCallTransport{next: http.DefaultTransport, counter: counter}}
+
+	const workers, requests = 16, 25
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range requests {
+				for _, path := range []string{"/v1/namespaces/db/tables/events", "/v1/namespaces"} {
+					resp, err := client.Get(server.URL + path)
+					if !assert.NoError(t, err) {
+						return
+					}
+					_ = resp.Body.Close()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	counts := counter.snapshot()
+	assert.Len(t, counts, int(numEndpointFamilies))
+	assert.Equal(t, int64(workers*requests), counts["load_table"])
+	assert.Equal(t, int64(workers*requests), counts["list_namespaces"])
+	assert.Zero(t, counts["commit_table"])
+
+	var b strings.Builder
+	require.NoError(t, counter.writeSummary(&b))
+	assert.Equal(t, "API calls: 800 (list_namespaces 400, load_table 400)\n", b.String())
+}
+
+func TestProviderCountsAPICalls(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	server := m.start(t)
+
+	p := &icebergProvider{
+		catalogURI:   server.URL,
+		catalogType:  "rest",
+		capabilities: newCatalogCapabilities(),
+		apiCalls:     &apiCallCounter{},
+		metrics:      newOperationMetrics(),
+	}
+	p.metrics.apiCalls = p.apiCalls
+
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+	_, err = cat.LoadTable(ctx, catalog.ToIdentifier("db", "events"))
+	require.NoError(t, err)
+	_, err = cat.LoadNamespaceProperties(ctx, catalog.ToIdentifier("db"))
+	require.NoError(t, err)
+
+	counts := p.apiCalls.snapshot()
+	assert.Equal(t, int64(1), counts["config"])
+	assert.Equal(t, int64(1), counts["load_table"])
+	assert.Equal(t, int64(1), counts["load_namespace"])
+
+	var b strings.Builder
+	require.NoError(t, p.metrics.writeSummary(&b))
+	assert.Contains(t, b.String(), "API calls: 3 (config 1, load_namespace 1, load_table 1)\n")
+}
@@ -0,0 +1,66 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// With verify_after_apply, every create and update reads the object back once
+// more when it is done and compares the values the resource manages with the
+// ones the apply was to leave. The comparison is the one of drift detection,
+// with the intended values as the prior ones, so values the catalog is known
+// to normalize aren't reported. Each resource does its own read-back; the
+// differences are reported the same way by addMismatchTo.
+
+// compareMembers records the members of intended missing from actual and the
+// members of actual that aren't intended. Both map a key identifying a member
+// to how it is shown, and kind names what the members are, such as "grant".
+func (d *driftReport) compareMembers(kind string, intended, actual map[string]string) {
+	for _, k := range slices.Sorted(maps.Keys(intended)) {
+		if _, ok := actual[k]; !ok {
+			d.changes = append(d.changes, fmt.Sprintf("%s %s missing", kind, intended[k]))
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(actual)) {
+		if _, ok := intended[k]; !ok {
+			d.changes = append(d.changes, fmt.Sprintf("%s %s not in the configuration", kind, actual[k]))
+		}
+	}
+}
+
+// addMismatchTo appends an error listing how the object read back after an
+// apply differs from what the apply was to leave, if it does. The state is
+// still saved with what was read, so the next plan shows the differences
+// again.
+func (d *driftReport) addMismatchTo(diags *diag.Diagnostics) {
+	if len(d.changes) == 0 {
+		return
+	}
+
+	diags.AddError(
+		"Apply not reflected by the catalog",
+		fmt.Sprintf("%s was read back after the apply and differs from the configuration (configured → read back):\n  - %s\n\n"+
+			"The catalog accepted the changes but returns other values, so it likely rewrites, drops or delays some of them. "+
+			"This fails the apply because verify_after_apply is set in the provider configuration.",
+			d.subject, strings.Join(d.changes, "\n  - ")),
+	)
+}
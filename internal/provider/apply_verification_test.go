@@ -0,0 +1,169 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftReportMismatch(t *testing.T) {
+	report := newDriftReport("Catalog role reader of Polaris catalog lake")
+	report.compareMembers("grant",
+		map[string]string{"a": "TABLE_READ_DATA on catalog", "b": "NAMESPACE_LIST on namespace db"},
+		map[string]string{"a": "TABLE_READ_DATA on catalog", "c": "CATALOG_MANAGE_CONTENT on catalog"},
+	)
+
+	var diags diag.Diagnostics
+	report.addMismatchTo(&diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Apply not reflected by the catalog", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), "Catalog role reader of Polaris catalog lake was read back after the apply and differs from the configuration (configured → read back):\n"+
+		"  - grant NAMESPACE_LIST on namespace db missing\n"+
+		"  - grant CATALOG_MANAGE_CONTENT on catalog not in the configuration\n")
+
+	diags = nil
+	newDriftReport("Namespace db").addMismatchTo(&diags)
+	assert.Empty(t, diags)
+}
+
+func TestVerifyApplied(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("namespace", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "events", nil)
+		m.namespaces["db"]["retention"] = "7"
+		m.namespaces["db"]["Compaction.Enabled"] = "true"
+		server := m.start(t)
+
+		r := &icebergNamespaceResource{provider: &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}}
+		var diags diag.Diagnostics
+		r.ConfigureCatalog(ctx, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		intended := map[string]string{"retention": "7", "compaction.enabled": "TRUE"}
+		r.verifyApplied(ctx, []string{"db"}, properties.Keys{IgnoreCase: true}, intended, &diags)
+		require.Empty(t, diags, "values the catalog normalizes aren't differences")
+
+		m.alteredProperties = map[string]string{"retention": "7d"}
+		r.verifyApplied(ctx, []string{"db"}, properties.Keys{IgnoreCase: true}, intended, &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Contains(t, diags.Errors()[0].Detail(), "Namespace db was read back after the apply")
+		assert.Contains(t, diags.Errors()[0].Detail(), `property retention changed "7" → "7d"`)
+		assert.NotContains(t, diags.Errors()[0].Detail(), "compaction.enabled")
+	})
+
+	t.Run("namespaces", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "events", map[string]string{})
+		m.namespaces["db"]["retention"] = "7"
+		m.alteredProperties = map[string]string{"retention": "7 "}
+		r := newNamespacesTestResource(t, m)
+
+		var diags diag.Diagnostics
+		r.verifyApplied(ctx, "", map[string]map[string]string{"db": {"retention": "7"}, "staging": {}}, &diags)
+		require.Len(t, diags.Errors(), 2)
+		assert.Contains(t, diags.Errors()[0].Detail(), `property retention changed "7" → "7 "`)
+		assert.Equal(t, "Failed to read namespace staging back after the apply", diags.Errors()[1].Summary())
+	})
+
+	t.Run("table", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "events", map[string]string{"retention": "7", "owner": "etl"})
+		server := m.start(t)
+
+		r := &icebergTableResource{provider: &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}}
+		var diags diag.Diagnostics
+		r.ConfigureCatalog(ctx, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		schema, d := schemaObjectValue(ctx, mockTableSchema())
+		require.False(t, d.HasError(), "%v", d)
+		intended := icebergTableResourceModel{
+			UserProperties: types.MapValueMust(types.StringType, map[string]attr.Value{"retention": types.StringValue("7")}),
+			Owner:          types.StringValue("etl"),
+			Schema:         schema,
+		}
+		r.verifyApplied(ctx, []string{"db", "events"}, &intended, &diags)
+		require.Empty(t, diags)
+
+		m.alteredProperties = map[string]string{"owner": "ETL"}
+		m.mangleSchemas = true
+		r.verifyApplied(ctx, []string{"db", "events"}, &intended, &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Contains(t, diags.Errors()[0].Detail(), "Table db.events was read back after the apply")
+		assert.Contains(t, diags.Errors()[0].Detail(), `property owner changed "etl" → "ETL"`)
+		assert.Contains(t, diags.Errors()[0].Detail(), "column id type changed long → string")
+	})
+
+	t.Run("table properties overlay", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "events", map[string]string{"write.format.default": "parquet"})
+		m.addTable("db", "orders", map[string]string{"write.format.default": "parquet"})
+		r := newOverlayTestResource(t, m)
+
+		intended := overlayModel(t, nil, map[string]string{"write.format.default": "parquet"})
+		var diags diag.Diagnostics
+		r.verifyApplied(ctx, &intended, &diags)
+		require.Empty(t, diags)
+
+		m.alteredProperties = map[string]string{"write.format.default": "PARQUET"}
+		r.verifyApplied(ctx, &intended, &diags)
+		require.Len(t, diags.Errors(), 2)
+		assert.Contains(t, diags.Errors()[0].Detail(), "Table db.events was read back")
+		assert.Contains(t, diags.Errors()[1].Detail(), "Table db.orders was read back")
+	})
+}
+
+func TestAccIcebergNamespace_VerifyAfterApply(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.alteredProperties = map[string]string{"retention": "7d"}
+	server := m.start(t)
+
+	config := fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri        = "%s"
+  verify_after_apply = true
+}
+
+resource "iceberg_namespace" "db" {
+  name            = ["db"]
+  user_properties = { retention = "7" }
+}
+`, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`property retention changed "7" → "7d"`),
+			},
+		},
+	})
+}
@@ -0,0 +1,363 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// auditEntry is the line written to the audit log for a mutating request. It
+// only holds what the path, the method and a few name fields of the request
+// body say about the request: headers, properties and the rest of the body
+// may hold credentials and are never written.
+type auditEntry struct {
+	Timestamp    string `json:"timestamp"`
+	ResourceType string `json:"resource_type"`
+	Operation    string `json:"operation"`
+	Identifier   string `json:"identifier"`
+	Outcome      string `json:"outcome"`
+	Status       int    `json:"status"`
+	DurationMS   int64  `json:"duration_ms"`
+}
+
+// auditLog appends a JSON line per mutating request to a file.
+type auditLog struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+}
+
+// configureAuditLog sets p.auditLog to the audit log writing to the file
+// audit_log_path names, opened for appending. A provider configured again
+// with the same path keeps its file open, and closes a file it no longer logs
+// to. A file that can't be opened is reported as an error.
+func (p *icebergProvider) configureAuditLog(data icebergProviderModel, diags *diag.Diagnostics) {
+	name := ""
+	if !data.AuditLogPath.IsNull() && !data.AuditLogPath.IsUnknown() {
+		name = data.AuditLogPath.ValueString()
+	}
+	if p.auditLog != nil {
+		if p.auditLog.path == name {
+			return
+		}
+		if err := p.auditLog.close(); err != nil {
+			diags.AddWarning("Failed to close the audit log", "The audit log "+p.auditLog.path+" couldn't be closed: "+err.Error()+".")
+		}
+		p.auditLog = nil
+	}
+	if name == "" {
+		return
+	}
+
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		diags.AddAttributeError(path.Root("audit_log_path"), "Invalid audit_log_path",
+			"The audit log can't be opened for appending: "+err.Error()+".")
+
+		return
+	}
+	p.auditLog = &auditLog{path: name, file: f}
+}
+
+// closeAuditLog flushes the audit log to disk and closes it.
+func (p *icebergProvider) closeAuditLog() error {
+	if p.auditLog == nil {
+		return nil
+	}
+
+	return p.auditLog.close()
+}
+
+// write appends entry as one line. Each line is written with a single call,
+// so lines of concurrent requests, and of other provider instances and
+// processes appending to the same file, don't interleave.
+func (l *auditLog) write(entry auditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return os.ErrClosed
+	}
+	_, err = l.file.Write(append(line, '\n'))
+
+	return err
+}
+
+func (l *auditLog) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := errors.Join(l.file.Sync(), l.file.Close())
+	l.file = nil
+
+	return err
+}
+
+// auditTransport writes an entry to the audit log for every request that
+// isn't a GET or HEAD, once it completed, for the resource type of its
+// context, or resourceType. A request fails if it returns an error or a
+// status of 400 or above.
+type auditTransport struct {
+	next         http.RoundTripper
+	log          *auditLog
+	resourceType string
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation, identifier, ok := auditOperation(req)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	entry := auditEntry{
+		Timestamp:    start.UTC().Format(time.RFC3339Nano),
+		ResourceType: contextResourceType(req.Context(), t.resourceType),
+		Operation:    operation,
+		Identifier:   identifier,
+		Outcome:      "success",
+		DurationMS:   duration.Milliseconds(),
+	}
+	if resp != nil {
+		entry.Status = resp.StatusCode
+	}
+	if err != nil || entry.Status >= http.StatusBadRequest {
+		entry.Outcome = "failure"
+	}
+	if werr := t.log.write(entry); werr != nil {
+		// A mutation that can't be recorded must not look like one that
+		// didn't happen, so the response is still returned.
+		tflog.Warn(req.Context(), "Failed to write audit log", map[string]any{
+			"path":  t.log.path,
+			"error": werr.Error(),
+		})
+	}
+
+	return resp, err
+}
+
+// auditOperation returns the operation and the identifier of the object req
+// changes, false if req doesn't change anything.
+func auditOperation(req *http.Request) (string, string, bool) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return "", "", false
+	}
+	if target := req.Header.Get("X-Amz-Target"); target != "" {
+		return glueAuditOperation(req, target)
+	}
+
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "oauth" && i+1 < len(segments) && segments[i+1] == "tokens":
+			// Token requests are POSTs but don't change the catalog.
+			return "", "", false
+		case segment == "namespaces":
+			return namespaceAuditOperation(req, segments[i+1:])
+		case segment == "tables" && i+1 < len(segments) && segments[i+1] == "rename":
+			return "rename_table", renameAuditIdentifier(req), true
+		case segment == "views" && i+1 < len(segments) && segments[i+1] == "rename":
+			return "rename_view", renameAuditIdentifier(req), true
+		case segment == "transactions" && i+1 < len(segments) && segments[i+1] == "commit":
+			return "commit_transaction", transactionAuditIdentifier(req), true
+		}
+	}
+
+	// Requests to the management APIs are recorded by method and path.
+	return req.Method + " " + req.URL.Path, segments[len(segments)-1], true
+}
+
+// namespaceAuditOperation returns the operation of a request below the
+// namespaces endpoint of the REST catalog; segments follow "namespaces".
+func namespaceAuditOperation(req *http.Request, segments []string) (string, string, bool) {
+	if len(segments) == 0 {
+		var body struct {
+			Namespace []string `json:"namespace"`
+		}
+		readAuditBody(req, &body)
+
+		return "create_namespace", encodeIdentifier(body.Namespace), true
+	}
+
+	namespace := strings.Split(segments[0], "\x1f")
+	switch {
+	case len(segments) == 1 && req.Method == http.MethodDelete:
+		return "drop_namespace", encodeIdentifier(namespace), true
+	case len(segments) == 2 && segments[1] == "properties":
+		return "update_namespace_properties", encodeIdentifier(namespace), true
+	case len(segments) == 2 && (segments[1] == "tables" || segments[1] == "views" || segments[1] == "register"):
+		var body struct {
+			Name        string `json:"name"`
+			StageCreate bool   `json:"stage-create"`
+		}
+		readAuditBody(req, &body)
+		operation := map[string]string{"tables": "create_table", "views": "create_view", "register": "register_table"}[segments[1]]
+		if body.StageCreate {
+			operation = "stage_create_table"
+		}
+
+		return operation, encodeIdentifier(append(namespace, body.Name)), true
+	case len(segments) == 3 && (segments[1] == "tables" || segments[1] == "views"):
+		kind := strings.TrimSuffix(segments[1], "s")
+		identifier := encodeIdentifier(append(namespace, segments[2]))
+		switch {
+		case req.Method == http.MethodDelete && req.URL.Query().Get("purgeRequested") == "true":
+			return "purge_" + kind, identifier, true
+		case req.Method == http.MethodDelete:
+			return "drop_" + kind, identifier, true
+		default:
+			return "update_" + kind, identifier, true
+		}
+	}
+
+	return req.Method + " " + req.URL.Path, encodeIdentifier(namespace), true
+}
+
+// renameAuditIdentifier returns "source -> destination" for a rename request.
+func renameAuditIdentifier(req *http.Request) string {
+	type identifier struct {
+		Namespace []string `json:"namespace"`
+		Name      string   `json:"name"`
+	}
+	var body struct {
+		Source      identifier `json:"source"`
+		Destination identifier `json:"destination"`
+	}
+	readAuditBody(req, &body)
+
+	return encodeIdentifier(append(body.Source.Namespace, body.Source.Name)) + " -> " +
+		encodeIdentifier(append(body.Destination.Namespace, body.Destination.Name))
+}
+
+// transactionAuditIdentifier returns the tables a transaction commits to,
+// separated by commas.
+func transactionAuditIdentifier(req *http.Request) string {
+	var body struct {
+		TableChanges []struct {
+			Identifier struct {
+				Namespace []string `json:"namespace"`
+				Name      string   `json:"name"`
+			} `json:"identifier"`
+		} `json:"table-changes"`
+	}
+	readAuditBody(req, &body)
+
+	tables := make([]string, len(body.TableChanges))
+	for i, change := range body.TableChanges {
+		tables[i] = encodeIdentifier(append(change.Identifier.Namespace, change.Identifier.Name))
+	}
+
+	return strings.Join(tables, ", ")
+}
+
+// glueAuditOperation returns the operation of a request to the AWS Glue API,
+// whose action is named by its X-Amz-Target header, such as
+// "AWSGlue.CreateTable". All Glue requests are POSTs; those of actions that
+// only read don't change anything.
+func glueAuditOperation(req *http.Request, target string) (string, string, bool) {
+	action := target[strings.LastIndex(target, ".")+1:]
+	for _, prefix := range []string{"Get", "List", "Search", "BatchGet"} {
+		if strings.HasPrefix(action, prefix) {
+			return "", "", false
+		}
+	}
+
+	var body struct {
+		DatabaseName  string
+		Name          string
+		DatabaseInput struct{ Name string }
+		TableInput    struct{ Name string }
+	}
+	readAuditBody(req, &body)
+
+	var identifier []string
+	switch {
+	case body.DatabaseName != "" && body.TableInput.Name != "":
+		identifier = []string{body.DatabaseName, body.TableInput.Name}
+	case body.DatabaseName != "" && body.Name != "":
+		identifier = []string{body.DatabaseName, body.Name}
+	case body.Name != "":
+		identifier = []string{body.Name}
+	case body.DatabaseInput.Name != "":
+		identifier = []string{body.DatabaseInput.Name}
+	}
+
+	return action, encodeIdentifier(identifier), true
+}
+
+// readAuditBody decodes the fields of v from the JSON body of req, leaving
+// the body to be sent unchanged. Fields stay empty if the body can't be
+// read.
+func readAuditBody(req *http.Request, v any) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+
+	var body []byte
+	if req.GetBody != nil {
+		r, err := req.GetBody()
+		if err != nil {
+			return
+		}
+		defer r.Close()
+		if body, err = io.ReadAll(r); err != nil {
+			return
+		}
+	} else {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+
+	_ = json.Unmarshal(body, v)
+}
+
+// withAuditLog wraps next in an auditTransport if audit_log_path is set.
+func (p *icebergProvider) withAuditLog(next http.RoundTripper, resourceType string) http.RoundTripper {
+	if p.auditLog == nil {
+		return next
+	}
+
+	return &auditTransport{next: next, log: p.auditLog, resourceType: resourceType}
+}
@@ -0,0 +1,272 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAuditLog returns the entries in the audit log at name, with their
+// timestamps and durations checked and cleared so they can be compared.
+func readAuditLog(t *testing.T, name string) []auditEntry {
+	t.Helper()
+
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry auditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), scanner.Text())
+		_, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		require.NoError(t, err, "timestamp of %s", scanner.Text())
+		assert.GreaterOrEqual(t, entry.DurationMS, int64(0))
+		entry.Timestamp, entry.DurationMS = "", 0
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+
+	return entries
+}
+
+func TestAuditOperation(t *testing.T) {
+	for _, tc := range []struct {
+		method, path, body, target string
+		wantOperation, wantID      string
+		wantSkipped                bool
+	}{
+		{method: http.MethodGet, path: "/v1/namespaces/db/tables/events", wantSkipped: true},
+		{method: http.MethodHead, path: "/v1/namespaces/db", wantSkipped: true},
+		{method: http.MethodPost, path: "/v1/oauth/tokens", body: "client_secret=s3cr3t", wantSkipped: true},
+		{method: http.MethodPost, path: "/v1/namespaces", body: `{"namespace":["db","raw"],"properties":{"password":"s3cr3t"}}`, wantOperation: "create_namespace", wantID: "db.raw"},
+		{method: http.MethodPost, path: "/v1/wh/namespaces/db\x1fraw/properties", wantOperation: "update_namespace_properties", wantID: "db.raw"},
+		{method: http.MethodDelete, path: "/v1/namespaces/db", wantOperation: "drop_namespace", wantID: "db"},
+		{method: http.MethodPost, path: "/v1/namespaces/db/tables", body: `{"name":"events"}`, wantOperation: "create_table", wantID: "db.events"},
+		{method: http.MethodPost, path: "/v1/namespaces/db/tables", body: `{"name":"events","stage-create":true}`, wantOperation: "stage_create_table", wantID: "db.events"},
+		{method: http.MethodPost, path: "/v1/namespaces/db/register", body: `{"name":"events","metadata-location":"s3://lake/m.json"}`, wantOperation: "register_table", wantID: "db.events"},
+		{method: http.MethodPost, path: "/v1/namespaces/db/tables/events", wantOperation: "update_table", wantID: "db.events"},
+		{method: http.MethodDelete, path: "/v1/namespaces/db/tables/events", wantOperation: "drop_table", wantID: "db.events"},
+		{method: http.MethodDelete, path: "/v1/namespaces/db/tables/events?purgeRequested=true", wantOperation: "purge_table", wantID: "db.events"},
+		{method: http.MethodDelete, path: "/v1/namespaces/db/views/daily", wantOperation: "drop_view", wantID: "db.daily"},
+		{
+			method: http.MethodPost, path: "/v1/tables/rename",
+			body:          `{"source":{"namespace":["db"],"name":"a"},"destination":{"namespace":["db"],"name":"b"}}`,
+			wantOperation: "rename_table", wantID: "db.a -> db.b",
+		},
+		{
+			method: http.MethodPost, path: "/v1/transactions/commit",
+			body:          `{"table-changes":[{"identifier":{"namespace":["db"],"name":"a"}},{"identifier":{"namespace":["db"],"name":"b"}}]}`,
+			wantOperation: "commit_transaction", wantID: "db.a, db.b",
+		},
+		{method: http.MethodDelete, path: "/api/management/v1/principals/etl", wantOperation: "DELETE /api/management/v1/principals/etl", wantID: "etl"},
+		{method: http.MethodPost, path: "/", target: "AWSGlue.GetDatabase", body: `{"Name":"db"}`, wantSkipped: true},
+		{method: http.MethodPost, path: "/", target: "AWSGlue.CreateDatabase", body: `{"DatabaseInput":{"Name":"db","Parameters":{"password":"s3cr3t"}}}`, wantOperation: "CreateDatabase", wantID: "db"},
+		{method: http.MethodPost, path: "/", target: "AWSGlue.UpdateTable", body: `{"DatabaseName":"db","TableInput":{"Name":"events"}}`, wantOperation: "UpdateTable", wantID: "db.events"},
+		{method: http.MethodPost, path: "/", target: "AWSGlue.DeleteTable", body: `{"DatabaseName":"db","Name":"events"}`, wantOperation: "DeleteTable", wantID: "db.events"},
+	} {
+		t.Run(tc.method+" "+tc.path+tc.target, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://catalog"+strings.ReplaceAll(tc.path, "\x1f", "%1F"), strings.NewReader(tc.body))
+			if tc.target != "" {
+				req.Header.Set("X-Amz-Target", tc.target)
+			}
+
+			operation, identifier, ok := auditOperation(req)
+			assert.Equal(t, !tc.wantSkipped, ok)
+			assert.Equal(t, tc.wantOperation, operation)
+			assert.Equal(t, tc.wantID, identifier)
+
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.body, string(body), "the body is still sent")
+		})
+	}
+}
+
+func TestProviderAuditLog(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	server := m.start(t)
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"catalog_uri":    tftypes.NewValue(tftypes.String, server.URL),
+		"token":          tftypes.NewValue(tftypes.String, "bearer-s3cr3t"),
+		"audit_log_path": tftypes.NewValue(tftypes.String, auditPath),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	t.Cleanup(func() { _ = p.closeAuditLog() })
+
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+
+	ns := table.Identifier{"db"}
+	ident := table.Identifier{"db", "events"}
+	require.NoError(t, cat.CreateNamespace(ctx, ns, iceberg.Properties{"password": "prop-s3cr3t"}))
+	_, err = cat.UpdateNamespaceProperties(ctx, ns, nil, iceberg.Properties{"owner": "data_eng"})
+	require.NoError(t, err)
+	schema := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	tbl, err := cat.CreateTable(ctx, ident, schema)
+	require.NoError(t, err)
+	_, err = cat.LoadTable(ctx, ident)
+	require.NoError(t, err)
+	_, _, err = cat.CommitTable(ctx, ident, []table.Requirement{table.AssertTableUUID(tbl.Metadata().TableUUID())},
+		[]table.Update{table.NewSetPropertiesUpdate(iceberg.Properties{"token": "prop-s3cr3t"})})
+	require.NoError(t, err)
+	require.NoError(t, cat.DropTable(ctx, ident))
+	require.Error(t, cat.DropTable(ctx, ident))
+	require.NoError(t, cat.DropNamespace(ctx, ns))
+
+	p.polaris = &polarisConfig{managementURI: server.URL + "/api/management/v1"}
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+	require.Error(t, client.DeletePrincipal(ctx, "etl"))
+
+	require.NoError(t, p.Close())
+	content, err := os.ReadFile(auditPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "s3cr3t", "secrets are never written")
+
+	assert.Equal(t, []auditEntry{
+		{ResourceType: "iceberg_table", Operation: "create_namespace", Identifier: "db", Outcome: "success", Status: http.StatusOK},
+		{ResourceType: "iceberg_table", Operation: "update_namespace_properties", Identifier: "db", Outcome: "success", Status: http.StatusOK},
+		{ResourceType: "iceberg_table", Operation: "create_table", Identifier: "db.events", Outcome: "success", Status: http.StatusOK},
+		{ResourceType: "iceberg_table", Operation: "update_table", Identifier: "db.events", Outcome: "success", Status: http.StatusOK},
+		{ResourceType: "iceberg_table", Operation: "drop_table", Identifier: "db.events", Outcome: "success", Status: http.StatusNoContent},
+		{ResourceType: "iceberg_table", Operation: "drop_table", Identifier: "db.events", Outcome: "failure", Status: http.StatusNotFound},
+		{ResourceType: "iceberg_table", Operation: "drop_namespace", Identifier: "db", Outcome: "success", Status: http.StatusNoContent},
+		{ResourceType: "iceberg_polaris_principal", Operation: "DELETE /api/management/v1/principals/etl", Identifier: "etl", Outcome: "failure", Status: http.StatusNotImplemented},
+	}, readAuditLog(t, auditPath))
+
+	t.Run("appends", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri":    tftypes.NewValue(tftypes.String, server.URL),
+			"audit_log_path": tftypes.NewValue(tftypes.String, auditPath),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+		require.NoError(t, err)
+		require.NoError(t, cat.CreateNamespace(ctx, ns, nil))
+		require.NoError(t, p.closeAuditLog())
+
+		entries := readAuditLog(t, auditPath)
+		require.Len(t, entries, 9)
+		assert.Equal(t, auditEntry{ResourceType: "iceberg_namespace", Operation: "create_namespace", Identifier: "db", Outcome: "success", Status: http.StatusOK}, entries[8])
+	})
+
+	t.Run("configured again", func(t *testing.T) {
+		config := func(auditPath string) map[string]tftypes.Value {
+			return map[string]tftypes.Value{
+				"catalog_uri":    tftypes.NewValue(tftypes.String, server.URL),
+				"audit_log_path": tftypes.NewValue(tftypes.String, auditPath),
+			}
+		}
+		p, diags := configureTestProvider(t, config(auditPath))
+		require.False(t, diags.HasError(), "%v", diags)
+		t.Cleanup(func() { _ = p.closeAuditLog() })
+		first := p.auditLog
+
+		var resp provider.ConfigureResponse
+		p.Configure(ctx, testProviderConfigureRequest(t, config(auditPath)), &resp)
+		require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+		assert.Same(t, first, p.auditLog, "the same file stays open")
+
+		otherPath := filepath.Join(t.TempDir(), "other.jsonl")
+		p.Configure(ctx, testProviderConfigureRequest(t, config(otherPath)), &resp)
+		require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+		assert.Equal(t, otherPath, p.auditLog.path)
+		assert.ErrorIs(t, first.write(auditEntry{}), os.ErrClosed, "the file no longer logged to is closed")
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri":    tftypes.NewValue(tftypes.String, server.URL),
+			"audit_log_path": tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "missing", "audit.jsonl")),
+		})
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Invalid audit_log_path", diags.Errors()[0].Summary())
+	})
+}
+
+func TestProviderAuditLogGlue(t *testing.T) {
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch r.Header.Get("X-Amz-Target") {
+		case "AWSGlue.GetDatabase":
+			_, _ = io.WriteString(w, `{"Database":{"Name":"db","Parameters":{}}}`)
+		case "AWSGlue.CreateDatabase", "AWSGlue.UpdateDatabase", "AWSGlue.DeleteDatabase":
+			_, _ = io.WriteString(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"InvalidInputException","message":"unexpected action"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"type":                   tftypes.NewValue(tftypes.String, "glue"),
+		"glue_region":            tftypes.NewValue(tftypes.String, "eu-west-1"),
+		"glue_access_key_id":     tftypes.NewValue(tftypes.String, "AKID"),
+		"glue_secret_access_key": tftypes.NewValue(tftypes.String, "key-s3cr3t"),
+		"audit_log_path":         tftypes.NewValue(tftypes.String, auditPath),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	t.Cleanup(func() { _ = p.closeAuditLog() })
+	p.glue.awsConfig.BaseEndpoint = aws.String(server.URL)
+
+	cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+	require.NoError(t, err)
+	ns := table.Identifier{"db"}
+	require.NoError(t, cat.CreateNamespace(ctx, ns, iceberg.Properties{"password": "prop-s3cr3t"}))
+	_, err = cat.UpdateNamespaceProperties(ctx, ns, nil, iceberg.Properties{"owner": "data_eng"})
+	require.NoError(t, err)
+	require.NoError(t, cat.DropNamespace(ctx, ns))
+
+	require.NoError(t, p.closeAuditLog())
+	content, err := os.ReadFile(auditPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "s3cr3t", "secrets are never written")
+
+	assert.Equal(t, []auditEntry{
+		{ResourceType: "iceberg_namespace", Operation: "CreateDatabase", Identifier: "db", Outcome: "success", Status: http.StatusOK},
+		{ResourceType: "iceberg_namespace", Operation: "UpdateDatabase", Identifier: "db", Outcome: "success", Status: http.StatusOK},
+		{ResourceType: "iceberg_namespace", Operation: "DeleteDatabase", Identifier: "db", Outcome: "success", Status: http.StatusOK},
+	}, readAuditLog(t, auditPath))
+}
@@ -0,0 +1,106 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// authHeader authenticates catalog and management API requests with a
+// header of its own in place of Authorization: Bearer, for catalogs behind an
+// API gateway that takes a key in a header such as X-Api-Key.
+type authHeader struct {
+	name  string
+	value string
+}
+
+// validateAuthHeaderConfig checks the auth_header_* provider attributes of
+// data and returns the header to send, or nil if none is configured. It
+// replaces the bearer token, so it can't be combined with the attributes
+// that provide one or otherwise authenticate catalog requests.
+func validateAuthHeaderConfig(data icebergProviderModel, diags *diag.Diagnostics) *authHeader {
+	if data.AuthHeaderName.IsUnknown() || data.AuthHeaderValue.IsUnknown() {
+		// Known by the time resources are applied.
+		return nil
+	}
+	if data.AuthHeaderName.IsNull() && data.AuthHeaderValue.IsNull() {
+		return nil
+	}
+
+	if data.AuthHeaderName.IsNull() || data.AuthHeaderValue.IsNull() {
+		diags.AddError(
+			"Invalid auth header configuration",
+			"auth_header_name and auth_header_value must be set together.",
+		)
+
+		return nil
+	}
+	name := data.AuthHeaderName.ValueString()
+	if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+		diags.AddAttributeError(
+			path.Root("auth_header_name"),
+			"Invalid auth header configuration",
+			"auth_header_name must be an HTTP header name such as X-Api-Key, without spaces or colons.",
+		)
+
+		return nil
+	}
+
+	polarisClientID := data.PolarisSettings == nil || data.PolarisSettings.ClientID.IsNull()
+	for _, attr := range []nullAttribute{
+		{"token", data.Token.IsNull()},
+		{"oauth2_client_id", data.OAuth2ClientID.IsNull()},
+		{"oauth2_client_secret", data.OAuth2ClientSecret.IsNull()},
+		{"oauth2_server_uri", data.OAuth2ServerURI.IsNull()},
+		{"credential", data.Credential.IsNull()},
+		{"basic_auth_username", data.BasicAuthUsername.IsNull()},
+		{"sigv4_enabled", !data.SigV4Enabled.ValueBool()},
+		{"polaris_settings.client_id", polarisClientID},
+	} {
+		if !attr.null {
+			diags.AddError(
+				"Conflicting authentication settings",
+				"auth_header_name and "+attr.name+" can't both be set: the auth header replaces the Authorization header the other settings authenticate catalog requests with. Remove one of them.",
+			)
+		}
+	}
+	if diags.HasError() {
+		return nil
+	}
+
+	return &authHeader{name: http.CanonicalHeaderKey(name), value: data.AuthHeaderValue.ValueString()}
+}
+
+// authHeaderTransport sends the auth header with every request, in place of
+// the Authorization header. The catalog client sends "Bearer" without a token
+// when none is configured, which gateways may reject.
+type authHeaderTransport struct {
+	next   http.RoundTripper
+	header *authHeader
+}
+
+func (t *authHeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper mustn't modify the request it was given.
+	req = req.Clone(req.Context())
+	req.Header.Del("Authorization")
+	req.Header.Set(t.header.name, t.header.value)
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,205 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthHeader(t *testing.T) {
+	var keys, auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("X-Api-Key"))
+		auth = append(auth, r.Header.Get("Authorization"))
+		// The gateway in front of the catalog.
+		if r.Header.Get("X-Api-Key") != "k3y" {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": map[string]any{"message": "Unauthorized", "type": "NotAuthorizedException", "code": 401}})
+
+			return
+		}
+		switch r.URL.Path {
+		case "/api/catalog/v1/config":
+			writeJSON(w, http.StatusOK, map[string]any{"defaults": map[string]string{}, "overrides": map[string]string{}})
+		case "/api/catalog/v1/namespaces/db":
+			writeJSON(w, http.StatusOK, map[string]any{"namespace": []string{"db"}, "properties": map[string]string{"owner": "etl"}})
+		case "/api/management/v1/principal-roles/reader":
+			writeJSON(w, http.StatusOK, map[string]any{"name": "reader", "properties": map[string]string{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	// Neither is used with an auth header.
+	t.Setenv(tokenEnvVar, "from-env")
+	t.Setenv(oauth2ClientIDEnvVar, "from-env")
+	t.Setenv(oauth2ClientSecretEnvVar, "from-env")
+	t.Setenv(oauth2ServerURIEnvVar, "http://localhost:8181/v1/oauth/tokens")
+
+	authHeader := func(name, value string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"catalog_uri":       tftypes.NewValue(tftypes.String, server.URL+"/api/catalog"),
+			"auth_header_name":  tftypes.NewValue(tftypes.String, name),
+			"auth_header_value": tftypes.NewValue(tftypes.String, value),
+		}
+	}
+
+	t.Run("catalog requests", func(t *testing.T) {
+		keys, auth = nil, nil
+		p, diags := configureTestProvider(t, authHeader("x-api-key", "k3y"))
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Empty(t, p.token)
+		assert.Nil(t, p.oauth2)
+
+		cat, err := p.NewCatalog(context.Background(), "iceberg_namespace")
+		require.NoError(t, err)
+		props, err := cat.LoadNamespaceProperties(context.Background(), []string{"db"})
+		require.NoError(t, err)
+		assert.Equal(t, "etl", props["owner"])
+		assert.Equal(t, []string{"k3y", "k3y"}, keys)
+		assert.Equal(t, []string{"", ""}, auth, "no Authorization header is sent")
+	})
+
+	t.Run("polaris requests", func(t *testing.T) {
+		keys, auth = nil, nil
+		attributes := authHeader("X-Api-Key", "k3y")
+		attributes["type"] = tftypes.NewValue(tftypes.String, "polaris")
+		p, diags := configureTestProvider(t, attributes)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		client, err := p.newPolarisManagementClient("iceberg_polaris_principal_role")
+		require.NoError(t, err)
+		role, err := client.GetPrincipalRole(context.Background(), "reader")
+		require.NoError(t, err)
+		assert.Equal(t, "reader", role.Name)
+		assert.Equal(t, []string{"k3y"}, keys)
+		assert.Equal(t, []string{""}, auth, "no Authorization header is sent")
+	})
+
+	t.Run("connection check", func(t *testing.T) {
+		attributes := authHeader("X-Api-Key", "wrong")
+		attributes["validate_connection"] = tftypes.NewValue(tftypes.Bool, true)
+		_, diags := configureTestProvider(t, attributes)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Catalog rejected the credentials", diags.Errors()[0].Summary())
+
+		attributes = authHeader("X-Api-Key", "k3y")
+		attributes["validate_connection"] = tftypes.NewValue(tftypes.Bool, true)
+		_, diags = configureTestProvider(t, attributes)
+		require.False(t, diags.HasError(), "%v", diags)
+	})
+}
+
+func TestAuthHeaderConfig(t *testing.T) {
+	header := map[string]tftypes.Value{
+		"auth_header_name":  tftypes.NewValue(tftypes.String, "X-Api-Key"),
+		"auth_header_value": tftypes.NewValue(tftypes.String, "k3y"),
+	}
+
+	for _, tc := range []struct {
+		name       string
+		attributes map[string]tftypes.Value
+		wantError  string
+	}{
+		{
+			name: "value without name",
+			attributes: map[string]tftypes.Value{
+				"auth_header_value": tftypes.NewValue(tftypes.String, "k3y"),
+			},
+			wantError: "auth_header_name and auth_header_value must be set together.",
+		},
+		{
+			name: "name with a colon",
+			attributes: map[string]tftypes.Value{
+				"auth_header_name":  tftypes.NewValue(tftypes.String, "X-Api-Key:"),
+				"auth_header_value": tftypes.NewValue(tftypes.String, "k3y"),
+			},
+			wantError: "auth_header_name must be an HTTP header name such as X-Api-Key, without spaces or colons.",
+		},
+		{
+			name: "token",
+			attributes: map[string]tftypes.Value{
+				"token": tftypes.NewValue(tftypes.String, "from-config"),
+			},
+			wantError: "auth_header_name and token can't both be set: the auth header replaces the Authorization header the other settings authenticate catalog requests with. Remove one of them.",
+		},
+		{
+			name: "oauth2",
+			attributes: map[string]tftypes.Value{
+				"oauth2_client_id":     tftypes.NewValue(tftypes.String, "etl"),
+				"oauth2_client_secret": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			},
+			wantError: "auth_header_name and oauth2_client_id can't both be set: the auth header replaces the Authorization header the other settings authenticate catalog requests with. Remove one of them.",
+		},
+		{
+			name: "credential",
+			attributes: map[string]tftypes.Value{
+				"credential": tftypes.NewValue(tftypes.String, "etl:s3cr3t"),
+			},
+			wantError: "auth_header_name and credential can't both be set: the auth header replaces the Authorization header the other settings authenticate catalog requests with. Remove one of them.",
+		},
+		{
+			name: "basic auth",
+			attributes: map[string]tftypes.Value{
+				"basic_auth_username": tftypes.NewValue(tftypes.String, "etl"),
+				"basic_auth_password": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			},
+			wantError: "auth_header_name and basic_auth_username can't both be set: the auth header replaces the Authorization header the other settings authenticate catalog requests with. Remove one of them.",
+		},
+		{
+			name: "polaris client credentials",
+			attributes: map[string]tftypes.Value{
+				"type": tftypes.NewValue(tftypes.String, "polaris"),
+				"polaris_settings": polarisSettingsValue(map[string]tftypes.Value{
+					"client_id":     tftypes.NewValue(tftypes.String, "admin"),
+					"client_secret": tftypes.NewValue(tftypes.String, "s3cr3t"),
+				}),
+			},
+			wantError: "auth_header_name and polaris_settings.client_id can't both be set: the auth header replaces the Authorization header the other settings authenticate catalog requests with. Remove one of them.",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			attributes := map[string]tftypes.Value{"catalog_uri": tftypes.NewValue(tftypes.String, "http://localhost:8181")}
+			if _, ok := tc.attributes["auth_header_value"]; !ok {
+				maps.Copy(attributes, header)
+			}
+			maps.Copy(attributes, tc.attributes)
+			_, diags := configureTestProvider(t, attributes)
+			require.True(t, diags.HasError())
+			assert.Equal(t, tc.wantError, diags.Errors()[0].Detail())
+		})
+	}
+
+	t.Run("memory catalog", func(t *testing.T) {
+		var diags diag.Diagnostics
+		validateCatalogTypeConfig(icebergProviderModel{
+			Type:            types.StringValue("memory"),
+			AuthHeaderName:  types.StringValue("X-Api-Key"),
+			AuthHeaderValue: types.StringValue("k3y"),
+		}, &diags)
+		require.Len(t, diags.Errors(), 2)
+		assert.Equal(t, "auth_header_name only applies to catalogs reached over HTTP. The memory catalog is stored in the provider process; remove it from the configuration.", diags.Errors()[0].Detail())
+	})
+}
@@ -0,0 +1,103 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// basicAuthCredentials authenticate catalog requests with HTTP basic
+// authentication, for REST catalogs behind a reverse proxy that requires it.
+type basicAuthCredentials struct {
+	username string
+	password string
+}
+
+// validateBasicAuthConfig checks the basic_auth_* provider attributes of data
+// and returns the credentials to use, or nil if basic authentication isn't
+// configured. It can't be combined with the other ways of authenticating
+// catalog requests, which all set the Authorization header too.
+func validateBasicAuthConfig(data icebergProviderModel, diags *diag.Diagnostics) *basicAuthCredentials {
+	if data.BasicAuthUsername.IsUnknown() || data.BasicAuthPassword.IsUnknown() {
+		// Known by the time resources are applied.
+		return nil
+	}
+	if data.BasicAuthUsername.IsNull() && data.BasicAuthPassword.IsNull() {
+		return nil
+	}
+
+	if data.BasicAuthUsername.IsNull() || data.BasicAuthPassword.IsNull() {
+		diags.AddError(
+			"Invalid basic authentication configuration",
+			"basic_auth_username and basic_auth_password must be set together.",
+		)
+
+		return nil
+	}
+	username := data.BasicAuthUsername.ValueString()
+	if username == "" || strings.Contains(username, ":") {
+		diags.AddAttributeError(
+			path.Root("basic_auth_username"),
+			"Invalid basic authentication configuration",
+			"basic_auth_username must be a non-empty user name without colons, which separate the user name from the password in the Authorization header.",
+		)
+
+		return nil
+	}
+
+	for _, attr := range []nullAttribute{
+		{"token", data.Token.IsNull()},
+		{"oauth2_client_id", data.OAuth2ClientID.IsNull()},
+		{"oauth2_client_secret", data.OAuth2ClientSecret.IsNull()},
+		{"credential", data.Credential.IsNull()},
+		{"sigv4_enabled", !data.SigV4Enabled.ValueBool()},
+	} {
+		if !attr.null {
+			diags.AddError(
+				"Conflicting authentication settings",
+				"basic_auth_username and "+attr.name+" can't both be set: both authenticate catalog requests with the Authorization header. Remove one of them.",
+			)
+		}
+	}
+	if diags.HasError() {
+		return nil
+	}
+
+	return &basicAuthCredentials{username: username, password: data.BasicAuthPassword.ValueString()}
+}
+
+// basicAuthTransport sets the Authorization header of catalog requests to the
+// basic credentials, unless the request has one already.
+type basicAuthTransport struct {
+	next        http.RoundTripper
+	credentials *basicAuthCredentials
+}
+
+func (t *basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if hasAuthorization(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	// A RoundTripper mustn't modify the request it was given.
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(t.credentials.username, t.credentials.password)
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,186 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasicAuth(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		// The proxy in front of the catalog.
+		if user, password, ok := r.BasicAuth(); !ok || user != "etl" || password != "s3cr3t" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="catalog"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": map[string]any{"message": "Unauthorized", "type": "NotAuthorizedException", "code": 401}})
+
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/config":
+			writeJSON(w, http.StatusOK, map[string]any{"defaults": map[string]string{}, "overrides": map[string]string{}})
+		case "/v1/namespaces/db":
+			writeJSON(w, http.StatusOK, map[string]any{"namespace": []string{"db"}, "properties": map[string]string{"owner": "etl"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	// Neither is used with basic authentication.
+	t.Setenv(tokenEnvVar, "from-env")
+	t.Setenv(oauth2ClientIDEnvVar, "from-env")
+	t.Setenv(oauth2ClientSecretEnvVar, "from-env")
+
+	basicAuth := func(username, password string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"catalog_uri":         tftypes.NewValue(tftypes.String, server.URL),
+			"basic_auth_username": tftypes.NewValue(tftypes.String, username),
+			"basic_auth_password": tftypes.NewValue(tftypes.String, password),
+		}
+	}
+
+	t.Run("catalog requests", func(t *testing.T) {
+		auth = nil
+		p, diags := configureTestProvider(t, basicAuth("etl", "s3cr3t"))
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Empty(t, p.token)
+		assert.Nil(t, p.oauth2)
+
+		cat, err := p.NewCatalog(context.Background(), "iceberg_namespace")
+		require.NoError(t, err)
+		props, err := cat.LoadNamespaceProperties(context.Background(), []string{"db"})
+		require.NoError(t, err)
+		assert.Equal(t, "etl", props["owner"])
+		assert.Equal(t, []string{"Basic ZXRsOnMzY3IzdA==", "Basic ZXRsOnMzY3IzdA=="}, auth)
+	})
+
+	t.Run("connection check", func(t *testing.T) {
+		attributes := basicAuth("etl", "wrong")
+		attributes["validate_connection"] = tftypes.NewValue(tftypes.Bool, true)
+		_, diags := configureTestProvider(t, attributes)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Catalog rejected the credentials", diags.Errors()[0].Summary())
+
+		attributes = basicAuth("etl", "s3cr3t")
+		attributes["validate_connection"] = tftypes.NewValue(tftypes.Bool, true)
+		_, diags = configureTestProvider(t, attributes)
+		require.False(t, diags.HasError(), "%v", diags)
+	})
+
+	t.Run("authorization header set", func(t *testing.T) {
+		auth = nil
+		p, diags := configureTestProvider(t, basicAuth("etl", "s3cr3t"))
+		require.False(t, diags.HasError(), "%v", diags)
+
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/config", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer issued")
+		resp, err := (&http.Client{Transport: p.catalogTransport("iceberg_namespace")}).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, []string{"Bearer issued"}, auth, "an Authorization header of the request is kept")
+	})
+}
+
+func TestBasicAuthConfig(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		attributes map[string]tftypes.Value
+		wantError  string
+	}{
+		{
+			name: "password without username",
+			attributes: map[string]tftypes.Value{
+				"basic_auth_password": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			},
+			wantError: "basic_auth_username and basic_auth_password must be set together.",
+		},
+		{
+			name: "username with a colon",
+			attributes: map[string]tftypes.Value{
+				"basic_auth_username": tftypes.NewValue(tftypes.String, "etl:prod"),
+				"basic_auth_password": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			},
+			wantError: "basic_auth_username must be a non-empty user name without colons, which separate the user name from the password in the Authorization header.",
+		},
+		{
+			name: "token",
+			attributes: map[string]tftypes.Value{
+				"basic_auth_username": tftypes.NewValue(tftypes.String, "etl"),
+				"basic_auth_password": tftypes.NewValue(tftypes.String, "s3cr3t"),
+				"token":               tftypes.NewValue(tftypes.String, "from-config"),
+			},
+			wantError: "basic_auth_username and token can't both be set: both authenticate catalog requests with the Authorization header. Remove one of them.",
+		},
+		{
+			name: "oauth2",
+			attributes: map[string]tftypes.Value{
+				"basic_auth_username":  tftypes.NewValue(tftypes.String, "etl"),
+				"basic_auth_password":  tftypes.NewValue(tftypes.String, "s3cr3t"),
+				"oauth2_client_id":     tftypes.NewValue(tftypes.String, "etl"),
+				"oauth2_client_secret": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			},
+			wantError: "basic_auth_username and oauth2_client_id can't both be set: both authenticate catalog requests with the Authorization header. Remove one of them.",
+		},
+		{
+			name: "credential",
+			attributes: map[string]tftypes.Value{
+				"basic_auth_username": tftypes.NewValue(tftypes.String, "etl"),
+				"basic_auth_password": tftypes.NewValue(tftypes.String, "s3cr3t"),
+				"credential":          tftypes.NewValue(tftypes.String, "etl:s3cr3t"),
+			},
+			wantError: "basic_auth_username and credential can't both be set: both authenticate catalog requests with the Authorization header. Remove one of them.",
+		},
+		{
+			name: "sigv4",
+			attributes: map[string]tftypes.Value{
+				"basic_auth_username": tftypes.NewValue(tftypes.String, "etl"),
+				"basic_auth_password": tftypes.NewValue(tftypes.String, "s3cr3t"),
+				"sigv4_enabled":       tftypes.NewValue(tftypes.Bool, true),
+			},
+			wantError: "basic_auth_username and sigv4_enabled can't both be set: both authenticate catalog requests with the Authorization header. Remove one of them.",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			attributes := map[string]tftypes.Value{"catalog_uri": tftypes.NewValue(tftypes.String, "http://localhost:8181")}
+			maps.Copy(attributes, tc.attributes)
+			_, diags := configureTestProvider(t, attributes)
+			require.True(t, diags.HasError())
+			assert.Equal(t, tc.wantError, diags.Errors()[0].Detail())
+		})
+	}
+	t.Run("memory catalog", func(t *testing.T) {
+		var diags diag.Diagnostics
+		validateCatalogTypeConfig(icebergProviderModel{
+			Type:              types.StringValue("memory"),
+			BasicAuthUsername: types.StringValue("etl"),
+			BasicAuthPassword: types.StringValue("s3cr3t"),
+		}, &diags)
+		require.Len(t, diags.Errors(), 2)
+		assert.Equal(t, "basic_auth_username only applies to catalogs reached over HTTP. The memory catalog is stored in the provider process; remove it from the configuration.", diags.Errors()[0].Detail())
+	})
+}
@@ -0,0 +1,275 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/apache/iceberg-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// catalogFeature is an optional part of the REST catalog API. endpoint is the
+// entry the catalog lists in the endpoints field of its config response, and
+// key identifies the feature in the iceberg_provider_info data source.
+type catalogFeature struct {
+	key      string
+	name     string
+	endpoint string
+}
+
+var (
+	featureNamespaceProperties = catalogFeature{
+		key:      "namespace_properties",
+		name:     "updating namespace properties",
+		endpoint: "POST /v1/{prefix}/namespaces/{namespace}/properties",
+	}
+	// featureNamespacePropertyRemoval shares its endpoint with
+	// featureNamespaceProperties; some catalogs accept updates but reject removals.
+	featureNamespacePropertyRemoval = catalogFeature{
+		key:      "namespace_property_removal",
+		name:     "removing namespace properties",
+		endpoint: "POST /v1/{prefix}/namespaces/{namespace}/properties",
+	}
+	featureUpdateTable = catalogFeature{
+		key:      "update_table",
+		name:     "updating tables",
+		endpoint: "POST /v1/{prefix}/namespaces/{namespace}/tables/{table}",
+	}
+
+	// catalogFeatures lists all features, for reporting.
+	catalogFeatures = []catalogFeature{
+		featureNamespaceProperties,
+		featureNamespacePropertyRemoval,
+		featureUpdateTable,
+	}
+)
+
+// serverImplementations maps what catalog implementations put in the Server
+// header of their responses to the name iceberg_provider_info reports.
+var serverImplementations = map[string]string{
+	"polaris":    "polaris",
+	"lakekeeper": "lakekeeper",
+	"nessie":     "nessie",
+	"gravitino":  "gravitino",
+	"unity":      "unity",
+}
+
+// serverUnknown is the server implementation reported when it couldn't be
+// identified.
+const serverUnknown = "unknown"
+
+// catalogCapabilities caches which optional features the catalog supports. It
+// is filled from the endpoints advertised in the config response and from
+// "not implemented" responses, and shared by all resources of a provider.
+type catalogCapabilities struct {
+	mu sync.Mutex
+	// advertised is nil if the catalog didn't list its endpoints, in which case
+	// every feature is assumed to be supported until a request says otherwise.
+	advertised  map[string]bool
+	unsupported map[string]bool
+	// prefix is the path prefix from the config overrides. The catalog client
+	// applies it itself; it's kept for the few requests sent without it.
+	prefix string
+	// server is the catalog implementation identified from the config
+	// response, empty until one was seen.
+	server string
+	// polarisVersion is the release of the Polaris management API, empty if
+	// it couldn't be identified. It is looked up once, see polaris_version.go.
+	polarisVersion     string
+	polarisVersionOnce sync.Once
+}
+
+func newCatalogCapabilities() *catalogCapabilities {
+	return &catalogCapabilities{unsupported: make(map[string]bool)}
+}
+
+func (c *catalogCapabilities) supports(f catalogFeature) bool {
+	if c == nil {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.unsupported[f.name] {
+		return false
+	}
+
+	return c.advertised == nil || c.advertised[f.endpoint]
+}
+
+func (c *catalogCapabilities) markUnsupported(f catalogFeature) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.unsupported[f.name] = true
+}
+
+func (c *catalogCapabilities) setAdvertised(endpoints []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advertised = make(map[string]bool, len(endpoints))
+	for _, e := range endpoints {
+		c.advertised[e] = true
+	}
+}
+
+func (c *catalogCapabilities) setPrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prefix = prefix
+}
+
+func (c *catalogCapabilities) setServer(server string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.server = server
+}
+
+// serverImplementation returns the identified catalog implementation.
+func (c *catalogCapabilities) serverImplementation() string {
+	if c == nil {
+		return serverUnknown
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.server == "" {
+		return serverUnknown
+	}
+
+	return c.server
+}
+
+// featureFlags reports for every feature whether it is supported, by key.
+func (c *catalogCapabilities) featureFlags() map[string]bool {
+	flags := make(map[string]bool, len(catalogFeatures))
+	for _, f := range catalogFeatures {
+		flags[f.key] = c.supports(f)
+	}
+
+	return flags
+}
+
+func (c *catalogCapabilities) catalogPrefix() string {
+	if c == nil {
+		return ""
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.prefix
+}
+
+// observe records the endpoints and prefix listed in a config response and
+// the catalog implementation that sent it. The body is buffered and handed
+// back unchanged to the catalog client.
+func (c *catalogCapabilities) observe(req *http.Request, resp *http.Response) (*http.Response, error) {
+	if req.Method != http.MethodGet || !strings.HasSuffix(req.URL.Path, "/v1/config") || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	var cfg struct {
+		Overrides map[string]string `json:"overrides"`
+		Endpoints []string          `json:"endpoints"`
+	}
+	if err := json.Unmarshal(b, &cfg); err == nil {
+		if cfg.Endpoints != nil {
+			c.setAdvertised(cfg.Endpoints)
+		}
+		c.setPrefix(cfg.Overrides["prefix"])
+	}
+	c.setServer(identifyServer(req.URL.Hostname(), resp.Header.Get("Server"), cfg.Endpoints))
+
+	return resp, nil
+}
+
+// check adds an error explaining that the catalog lacks f and reports whether
+// the feature is available.
+func (c *catalogCapabilities) check(f catalogFeature, diags *diag.Diagnostics) bool {
+	if c.supports(f) {
+		return true
+	}
+
+	diags.AddError(
+		"Unsupported catalog feature",
+		"The configured catalog doesn't support "+f.name+" ("+f.endpoint+"). "+
+			"Change the configuration so this operation isn't needed, or use a catalog that implements it.",
+	)
+
+	return false
+}
+
+// isUnsupportedOperationError reports whether err means the catalog doesn't
+// implement the called endpoint.
+func isUnsupportedOperationError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, iceberg.ErrNotImplemented) ||
+		strings.Contains(err.Error(), "UnsupportedOperationException")
+}
+
+// identifyServer guesses the catalog implementation from the config response:
+// its Server header, Polaris-specific endpoints or the host of the AWS
+// services. It returns serverUnknown if nothing matches.
+func identifyServer(host, serverHeader string, endpoints []string) string {
+	header := strings.ToLower(serverHeader)
+	for marker, name := range serverImplementations {
+		if strings.Contains(header, marker) {
+			return name
+		}
+	}
+
+	for _, e := range endpoints {
+		if strings.Contains(e, "/polaris/") {
+			return "polaris"
+		}
+	}
+
+	switch host = strings.ToLower(host); {
+	case strings.HasPrefix(host, "glue.") && strings.HasSuffix(host, ".amazonaws.com"):
+		return "glue"
+	case strings.HasPrefix(host, "s3tables.") && strings.HasSuffix(host, ".amazonaws.com"):
+		return "s3tables"
+	}
+
+	return serverUnknown
+}
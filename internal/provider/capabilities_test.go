@@ -0,0 +1,213 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCapabilitiesCatalog serves the config endpoint with the given endpoint
+// list (omitted if nil) and answers namespace property updates. Requests with
+// removals fail with 501 if rejectRemovals is set. The bodies of successful
+// property updates are recorded.
+type mockCapabilitiesCatalog struct {
+	endpoints      []string
+	rejectRemovals bool
+	rejectUpdates  bool
+	received       []map[string]any
+}
+
+func (m *mockCapabilitiesCatalog) start(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/config", func(w http.ResponseWriter, _ *http.Request) {
+		cfg := map[string]any{"defaults": map[string]string{}, "overrides": map[string]string{}}
+		if m.endpoints != nil {
+			cfg["endpoints"] = m.endpoints
+		}
+		_ = json.NewEncoder(w).Encode(cfg)
+	})
+	mux.HandleFunc("/v1/namespaces/db/properties", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		removals, _ := body["removals"].([]any)
+		if m.rejectUpdates || (m.rejectRemovals && len(removals) > 0) {
+			w.WriteHeader(http.StatusNotImplemented)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{
+				"message": "not supported", "type": "UnsupportedOperationException", "code": 501,
+			}})
+
+			return
+		}
+
+		m.received = append(m.received, body)
+		_ = json.NewEncoder(w).Encode(map[string]any{"updated": []string{}, "removed": []string{}, "missing": []string{}})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newCapabilitiesTestResource(t *testing.T, m *mockCapabilitiesCatalog) *icebergNamespaceResource {
+	t.Helper()
+
+	server := m.start(t)
+	p := &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}
+
+	r := &icebergNamespaceResource{provider: p}
+	var diags diag.Diagnostics
+	r.ConfigureCatalog(context.Background(), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	return r
+}
+
+func TestCatalogCapabilitiesAdvertised(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []string
+		want      map[catalogFeature]bool
+	}{
+		{
+			name:      "no endpoint list",
+			endpoints: nil,
+			want:      map[catalogFeature]bool{featureNamespaceProperties: true, featureUpdateTable: true},
+		},
+		{
+			name:      "properties endpoint missing",
+			endpoints: []string{"GET /v1/{prefix}/namespaces", featureUpdateTable.endpoint},
+			want:      map[catalogFeature]bool{featureNamespaceProperties: false, featureUpdateTable: true},
+		},
+		{
+			name:      "everything advertised",
+			endpoints: []string{featureNamespaceProperties.endpoint, featureUpdateTable.endpoint},
+			want:      map[catalogFeature]bool{featureNamespaceProperties: true, featureNamespacePropertyRemoval: true, featureUpdateTable: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newCapabilitiesTestResource(t, &mockCapabilitiesCatalog{endpoints: tt.endpoints})
+			for f, want := range tt.want {
+				assert.Equal(t, want, r.provider.capabilities.supports(f), f.name)
+			}
+		})
+	}
+}
+
+func TestNamespacePropertyUpdateUnsupportedEndpoint(t *testing.T) {
+	m := &mockCapabilitiesCatalog{endpoints: []string{"GET /v1/{prefix}/namespaces"}}
+	r := newCapabilitiesTestResource(t, m)
+
+	var diags diag.Diagnostics
+	r.applyPropertyDelta(context.Background(), catalog.ToIdentifier("db"), properties.Delta{Updates: map[string]string{"a": "1"}}, &diags)
+
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Unsupported catalog feature", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), "updating namespace properties")
+	assert.Empty(t, m.received)
+}
+
+func TestNamespacePropertyRemovalFallback(t *testing.T) {
+	m := &mockCapabilitiesCatalog{rejectRemovals: true}
+	r := newCapabilitiesTestResource(t, m)
+	delta := properties.Delta{Updates: map[string]string{"a": "1"}, Removals: []string{"b"}}
+
+	var diags diag.Diagnostics
+	r.applyPropertyDelta(context.Background(), catalog.ToIdentifier("db"), delta, &diags)
+
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Len(t, diags.Warnings(), 1)
+	assert.Contains(t, diags.Warnings()[0].Detail(), "b")
+	require.Len(t, m.received, 1)
+	assert.Equal(t, map[string]any{"a": "1"}, m.received[0]["updates"])
+	assert.False(t, r.provider.capabilities.supports(featureNamespacePropertyRemoval))
+
+	// The capability is cached: the next update skips the removals up front.
+	diags = nil
+	r.applyPropertyDelta(context.Background(), catalog.ToIdentifier("db"), delta, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Len(t, m.received, 2)
+}
+
+func TestNamespacePropertyUpdateNotImplemented(t *testing.T) {
+	m := &mockCapabilitiesCatalog{rejectUpdates: true}
+	r := newCapabilitiesTestResource(t, m)
+
+	var diags diag.Diagnostics
+	r.applyPropertyDelta(context.Background(), catalog.ToIdentifier("db"), properties.Delta{Updates: map[string]string{"a": "1"}}, &diags)
+
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Unsupported catalog feature", diags.Errors()[0].Summary())
+	assert.False(t, r.provider.capabilities.supports(featureNamespaceProperties))
+}
+
+func TestIsUnsupportedOperationError(t *testing.T) {
+	assert.False(t, isUnsupportedOperationError(nil))
+	assert.False(t, isUnsupportedOperationError(errors.New("connection refused")))
+	assert.True(t, isUnsupportedOperationError(errors.New("UnsupportedOperationException: removals are not supported")))
+}
+
+func TestIdentifyServer(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		server    string
+		endpoints []string
+		want      string
+	}{
+		{name: "server header", host: "catalog.internal", server: "Lakekeeper/0.9.1", want: "lakekeeper"},
+		{name: "polaris endpoints", host: "catalog.internal", endpoints: []string{"GET /polaris/v1/{prefix}/namespaces/{namespace}/policies"}, want: "polaris"},
+		{name: "glue", host: "glue.eu-west-1.amazonaws.com", want: "glue"},
+		{name: "s3 tables", host: "s3tables.us-east-1.amazonaws.com", want: "s3tables"},
+		{name: "unknown", host: "localhost", server: "Jetty(9.4)", endpoints: []string{"GET /v1/{prefix}/namespaces"}, want: serverUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, identifyServer(tt.host, tt.server, tt.endpoints))
+		})
+	}
+}
+
+func TestCatalogCapabilitiesFeatureFlags(t *testing.T) {
+	r := newCapabilitiesTestResource(t, &mockCapabilitiesCatalog{endpoints: []string{featureUpdateTable.endpoint}})
+
+	assert.Equal(t, map[string]bool{
+		"namespace_properties":       false,
+		"namespace_property_removal": false,
+		"update_table":               true,
+	}, r.provider.capabilities.featureFlags())
+	assert.Equal(t, serverUnknown, r.provider.capabilities.serverImplementation())
+
+	var unconfigured *catalogCapabilities
+	assert.Equal(t, serverUnknown, unconfigured.serverImplementation())
+}
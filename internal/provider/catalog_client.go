@@ -0,0 +1,76 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/apache/iceberg-go/catalog"
+)
+
+// sharedCatalog holds the catalog client all resources and data sources of a
+// provider configuration share, so a REST catalog is sent its config request,
+// and an OAuth2 token is requested, once per configuration rather than once
+// per resource. The client is created by the first resource that needs it,
+// not in Configure, so commands that never read the catalog, such as
+// terraform validate, don't connect to it.
+type sharedCatalog struct {
+	mu  sync.Mutex
+	cat catalog.Catalog
+}
+
+// get returns the client, creating it with newCatalog if there is none yet.
+// Concurrent callers wait for the first one. A client that couldn't be
+// created isn't kept, so the next caller tries again.
+func (s *sharedCatalog) get(newCatalog func() (catalog.Catalog, error)) (catalog.Catalog, error) {
+	if s == nil {
+		return newCatalog()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cat != nil {
+		return s.cat, nil
+	}
+	cat, err := newCatalog()
+	if err != nil {
+		return nil, err
+	}
+	s.cat = cat
+
+	return cat, nil
+}
+
+type resourceTypeKey struct{}
+
+// withResourceType returns ctx for the requests made for resourceType. The
+// shared catalog client sends the requests of all resource types, so they are
+// recorded in the operation metrics and the audit log for the resource type
+// of their context.
+func withResourceType(ctx context.Context, resourceType string) context.Context {
+	return context.WithValue(ctx, resourceTypeKey{}, resourceType)
+}
+
+// contextResourceType returns the resource type ctx is for, fallback if it
+// names none.
+func contextResourceType(ctx context.Context, fallback string) string {
+	if resourceType, ok := ctx.Value(resourceTypeKey{}).(string); ok {
+		return resourceType
+	}
+
+	return fallback
+}
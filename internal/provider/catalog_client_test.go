@@ -0,0 +1,110 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedCatalog(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	server := m.start(t)
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"catalog_uri":              tftypes.NewValue(tftypes.String, server.URL),
+		"enable_operation_metrics": tftypes.NewValue(tftypes.Bool, true),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Zero(t, m.configRequests, "the catalog isn't contacted before a resource needs it")
+
+	// Terraform creates a resource for every request.
+	var wg sync.WaitGroup
+	catalogs := make([]catalog.Catalog, 40)
+	for i := range catalogs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var diags diag.Diagnostics
+			if i%2 == 0 {
+				r := &icebergNamespaceResource{provider: p}
+				r.ConfigureCatalog(ctx, &diags)
+				catalogs[i] = r.catalog
+			} else {
+				r := &icebergTableResource{provider: p}
+				r.ConfigureCatalog(ctx, &diags)
+				catalogs[i] = r.catalog
+			}
+			assert.False(t, diags.HasError(), "%v", diags)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, m.configRequests)
+	for _, cat := range catalogs {
+		assert.Same(t, catalogs[0], cat)
+	}
+
+	// The requests are counted for the resource type they are made for.
+	_, err := catalogs[0].LoadTable(withResourceType(ctx, "data.iceberg_table_schema"), catalog.ToIdentifier("db", "events"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), p.metrics.get("data.iceberg_table_schema", "GET").calls)
+
+	// Configure starts over with a new client.
+	p, diags = configureTestProvider(t, map[string]tftypes.Value{
+		"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+	assert.NotSame(t, catalogs[0], cat)
+	assert.Equal(t, 2, m.configRequests)
+}
+
+// stubCatalog is a catalog client that can't be used, for tests that only
+// pass clients around.
+type stubCatalog struct {
+	catalog.Catalog
+}
+
+func TestSharedCatalogRetriesFailures(t *testing.T) {
+	var s sharedCatalog
+	_, err := s.get(func() (catalog.Catalog, error) { return nil, errors.New("catalog unavailable") })
+	require.EqualError(t, err, "catalog unavailable")
+
+	created := 0
+	newCatalog := func() (catalog.Catalog, error) {
+		created++
+
+		return &stubCatalog{}, nil
+	}
+	first, err := s.get(newCatalog)
+	require.NoError(t, err)
+	second, err := s.get(newCatalog)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, created)
+}
@@ -0,0 +1,78 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// catalogErrorResponse is the body of an error response of the REST catalog.
+type catalogErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error"`
+}
+
+// catalogRequest sends a request the catalog client has no method for to the
+// REST catalog, authenticated and through the same transport as the requests
+// of the client. segments is the path below v1 and the catalog prefix; body
+// is sent as JSON unless it is nil. It returns the status and the body of the
+// response. The catalog client must have been created, so the prefix from the
+// catalog config is known.
+func (p *icebergProvider) catalogRequest(ctx context.Context, resourceType, method string, segments []string, body []byte) (int, []byte, error) {
+	base, err := url.Parse(strings.TrimRight(p.catalogURI, "/"))
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid catalog_uri %q: %w", p.catalogURI, err)
+	}
+	elems := []string{"v1"}
+	if prefix := p.capabilities.catalogPrefix(); prefix != "" {
+		elems = append(elems, prefix)
+	}
+	elems = append(elems, segments...)
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, base.JoinPath(elems...).String(), reqBody)
+	if err != nil {
+		return 0, nil, fmt.Errorf("create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Transport: p.catalogTransport(resourceType)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("read response: %w", err)
+	}
+
+	return resp.StatusCode, respBody, nil
+}
@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// commentProperty is the property engines and BI tools read the description
+// of a table or namespace from. It is managed through the comment attribute.
+const commentProperty = "comment"
+
+// withComment returns a copy of props with the comment attribute added as the
+// comment property. props is returned as is if the comment isn't set.
+func withComment(props map[string]string, comment types.String) map[string]string {
+	return withAttributeProperty(props, commentProperty, comment)
+}
+
+// syncComment returns the comment property of the server as the new value of
+// the comment attribute. A comment that isn't managed by Terraform stays
+// null, so comments written by engines don't show up as a diff.
+func syncComment(comment types.String, server map[string]string) types.String {
+	return syncAttributeProperty(comment, commentProperty, server)
+}
+
+// validateCommentConfig rejects configurations that set the comment both
+// with the comment attribute and in user_properties.
+func validateCommentConfig(comment types.String, userProperties types.Map, diags *diag.Diagnostics) {
+	validateAttributePropertyConfig(commentProperty, comment, userProperties, diags)
+}
+
+// The helpers below manage a property through an attribute named like the
+// property, such as comment and owner.
+
+// withAttributeProperty returns a copy of props with value added as the key
+// property. props is returned as is if value isn't set.
+func withAttributeProperty(props map[string]string, key string, value types.String) map[string]string {
+	if value.IsNull() || value.IsUnknown() {
+		return props
+	}
+
+	result := maps.Clone(props)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	result[key] = value.ValueString()
+
+	return result
+}
+
+// syncAttributeProperty returns the key property of the server as the new
+// value of its attribute. An attribute that is null stays null, so values
+// written by other clients don't show up as a diff.
+func syncAttributeProperty(value types.String, key string, server map[string]string) types.String {
+	if value.IsNull() {
+		return value
+	}
+
+	serverValue, ok := server[key]
+	if !ok {
+		return types.StringNull()
+	}
+
+	return types.StringValue(serverValue)
+}
+
+// validateAttributePropertyConfig rejects configurations that set the key
+// property both with its attribute and in user_properties.
+func validateAttributePropertyConfig(key string, value types.String, userProperties types.Map, diags *diag.Diagnostics) {
+	if value.IsNull() || userProperties.IsNull() || userProperties.IsUnknown() {
+		return
+	}
+
+	if _, ok := userProperties.Elements()[key]; ok {
+		diags.AddAttributeError(
+			path.Root(key),
+			fmt.Sprintf("Conflicting %s configuration", key),
+			fmt.Sprintf("The %[1]s is set both by the %[1]s attribute and by the %[1]s key of user_properties. Remove the %[1]s key from user_properties.", key),
+		)
+	}
+}
@@ -0,0 +1,128 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithComment(t *testing.T) {
+	props := map[string]string{"owner": "data"}
+
+	assert.Equal(t, props, withComment(props, types.StringNull()))
+	assert.Equal(t, map[string]string{"owner": "data", "comment": "Orders"}, withComment(props, types.StringValue("Orders")))
+	assert.Equal(t, map[string]string{"owner": "data"}, props, "the input must not be modified")
+	assert.Equal(t, map[string]string{"comment": ""}, withComment(nil, types.StringValue("")))
+}
+
+func TestSyncComment(t *testing.T) {
+	server := map[string]string{"comment": "Written by Spark"}
+
+	assert.True(t, syncComment(types.StringNull(), server).IsNull(), "an unmanaged comment stays null")
+	assert.Equal(t, types.StringValue("Written by Spark"), syncComment(types.StringValue("Orders"), server))
+	assert.True(t, syncComment(types.StringValue("Orders"), map[string]string{}).IsNull())
+}
+
+func TestValidateCommentConfig(t *testing.T) {
+	withKey := types.MapValueMust(types.StringType, map[string]attr.Value{"comment": types.StringValue("Orders")})
+	withoutKey := types.MapValueMust(types.StringType, map[string]attr.Value{"owner": types.StringValue("data")})
+
+	var diags diag.Diagnostics
+	validateCommentConfig(types.StringNull(), withKey, &diags)
+	validateCommentConfig(types.StringValue("Orders"), withoutKey, &diags)
+	validateCommentConfig(types.StringValue("Orders"), types.MapUnknown(types.StringType), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	validateCommentConfig(types.StringUnknown(), withKey, &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Conflicting comment configuration", diags.Errors()[0].Summary())
+}
+
+func TestAccIcebergTable_Comment(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := func(comment string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "orders" {
+  namespace = ["db"]
+  name      = "orders"
+  %s
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL, comment)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`comment = "All orders"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.orders", "comment", "All orders"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.comment", "All orders"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "server_managed_properties.comment"),
+				),
+			},
+			{
+				Config: config(`comment = "All orders, deduplicated"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.orders", "comment", "All orders, deduplicated"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.comment", "All orders, deduplicated"),
+				),
+			},
+			{
+				Config: config(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "comment"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "server_properties.comment"),
+				),
+			},
+			{
+				Config: config(`comment = "All orders"
+  user_properties = {
+    comment = "Orders"
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Conflicting comment configuration`),
+			},
+		},
+	})
+}
@@ -0,0 +1,166 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// connectionCheckResourceType is the resource type the requests of the
+// connection check are counted and logged for.
+const connectionCheckResourceType = "provider"
+
+// checkConnection creates the shared catalog client with
+// validate_connection, which sends the config request to a REST catalog, so
+// a wrong catalog_uri, warehouse or credentials fail the provider
+// configuration rather than the first resource that uses the catalog. The
+// resources then reuse the client rather than sending the request again.
+// Other catalog types are checked the same way, which opens the database of
+// type = 'sql'; Glue clients only send requests once a resource uses them.
+func (p *icebergProvider) checkConnection(ctx context.Context, diags *diag.Diagnostics) {
+	if p.glue != nil || p.sql != nil {
+		if _, err := p.NewCatalog(ctx, connectionCheckResourceType); err != nil {
+			diags.AddError("Catalog connection failed", "Creating the catalog client failed: "+err.Error()+
+				". Set validate_connection = false to skip this check.")
+		}
+
+		return
+	}
+
+	// The token is cached, so the catalog client doesn't request another.
+	if _, err := p.accessToken(ctx, connectionCheckResourceType); err != nil {
+		diags.AddError("Catalog authentication failed",
+			"Requesting an access token for the catalog failed: "+err.Error()+". Check the oauth2 settings of the provider.")
+
+		return
+	}
+
+	var check configCheck
+	_, err := p.NewCatalog(withConfigCheck(ctx, &check), connectionCheckResourceType)
+	if err == nil {
+		return
+	}
+
+	var nonJSON *nonJSONResponseError
+	switch {
+	case errors.As(err, &nonJSON):
+		diags.AddAttributeError(path.Root("catalog_uri"), "Catalog returned an unexpected response", nonJSON.Error()+".")
+
+		return
+	case check.url == "":
+		diags.AddAttributeError(path.Root("catalog_uri"), "Catalog connection failed",
+			"Creating the catalog client failed: "+err.Error()+". Set validate_connection = false to skip this check.")
+
+		return
+	case check.status == 0:
+		diags.AddAttributeError(path.Root("catalog_uri"), "Catalog unreachable",
+			fmt.Sprintf("GET %s failed: %s. Check that catalog_uri points at a running REST catalog that can be reached from where Terraform runs, "+
+				"or set validate_connection = false to skip this check.", check.url, err))
+
+		return
+	}
+
+	detail := fmt.Sprintf("GET %s returned status %d", check.url, check.status)
+	if check.snippet != "" {
+		detail += ": " + check.snippet
+	}
+	switch check.status {
+	case http.StatusOK:
+		diags.AddAttributeError(path.Root("catalog_uri"), "Catalog connection failed",
+			detail+", but creating the catalog client failed: "+err.Error()+". Set validate_connection = false to skip this check.")
+	case http.StatusUnauthorized, http.StatusForbidden:
+		diags.AddError("Catalog rejected the credentials",
+			detail+". Check token, the oauth2 settings or the credentials of the catalog.")
+	case http.StatusNotFound:
+		diags.AddAttributeError(path.Root("catalog_uri"), "Catalog config endpoint not found",
+			detail+". catalog_uri must be the base URI of the REST catalog, which serves the config endpoint below /v1, "+
+				"such as http://localhost:8181 or https://polaris.example.com/api/catalog; some catalogs also answer 404 for an unknown warehouse.")
+	default:
+		diags.AddAttributeError(path.Root("catalog_uri"), "Catalog connection failed",
+			detail+". Set validate_connection = false to skip this check.")
+	}
+}
+
+type configCheckKey struct{}
+
+// configCheck is the config response the catalog client got while the
+// connection check created it, for the diagnostics of a failed check.
+type configCheck struct {
+	// url is the redacted URL of the config request, empty if none was sent.
+	url string
+	// status is the status code of the response, 0 if there was none.
+	status int
+	// snippet is the responseSnippet of an error response.
+	snippet string
+}
+
+// withConfigCheck returns ctx whose config requests are recorded in check.
+func withConfigCheck(ctx context.Context, check *configCheck) context.Context {
+	return context.WithValue(ctx, configCheckKey{}, check)
+}
+
+// observeConfigRequest records req in the configCheck of its context if it
+// is a config request.
+func observeConfigRequest(req *http.Request) *configCheck {
+	check, ok := req.Context().Value(configCheckKey{}).(*configCheck)
+	if !ok || req.Method != http.MethodGet || !strings.HasSuffix(req.URL.Path, "/v1/config") {
+		return nil
+	}
+	check.url = req.URL.Redacted()
+
+	return check
+}
+
+// observe records the status of resp and the snippet of an error response.
+// The body stays readable from the start.
+func (c *configCheck) observe(resp *http.Response) {
+	if c == nil {
+		return
+	}
+
+	c.status = resp.StatusCode
+	if resp.StatusCode == http.StatusOK || resp.Body == nil {
+		return
+	}
+	peek, _ := io.ReadAll(io.LimitReader(resp.Body, responsePeekSize))
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(peek), resp.Body), Closer: resp.Body}
+	c.snippet = responseSnippet(peek)
+}
+
+// responseSnippet returns the error message of the REST error response in
+// body, or the first line of any other response body.
+func responseSnippet(body []byte) string {
+	var errResp catalogErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Message != "" {
+		if errResp.Error.Type == "" {
+			return errResp.Error.Message
+		}
+
+		return errResp.Error.Type + ": " + errResp.Error.Message
+	}
+
+	return firstLine(body)
+}
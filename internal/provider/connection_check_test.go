@@ -0,0 +1,120 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderValidateConnection(t *testing.T) {
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeRESTErrorMessage(w, http.StatusUnauthorized, "NotAuthorizedException", "Invalid bearer token")
+	}))
+	t.Cleanup(unauthorized.Close)
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(notFound.Close)
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refused.Close()
+
+	for _, tc := range []struct {
+		name        string
+		attrs       map[string]tftypes.Value
+		wantSummary string
+		wantDetail  []string
+	}{
+		{
+			name:        "unauthorized",
+			attrs:       map[string]tftypes.Value{"catalog_uri": tftypes.NewValue(tftypes.String, unauthorized.URL)},
+			wantSummary: "Catalog rejected the credentials",
+			wantDetail:  []string{"returned status 401", "NotAuthorizedException: Invalid bearer token"},
+		},
+		{
+			name: "not found",
+			attrs: map[string]tftypes.Value{
+				"catalog_uri": tftypes.NewValue(tftypes.String, notFound.URL+"/api"),
+				"warehouse":   tftypes.NewValue(tftypes.String, "lake"),
+			},
+			wantSummary: "Catalog config endpoint not found",
+			wantDetail:  []string{"GET " + notFound.URL + "/api/v1/config?warehouse=lake returned status 404: 404 page not found", "base URI of the REST catalog"},
+		},
+		{
+			name:        "connection refused",
+			attrs:       map[string]tftypes.Value{"catalog_uri": tftypes.NewValue(tftypes.String, refused.URL)},
+			wantSummary: "Catalog unreachable",
+			wantDetail:  []string{"connection refused"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.attrs["validate_connection"] = tftypes.NewValue(tftypes.Bool, true)
+			_, diags := configureTestProvider(t, tc.attrs)
+			require.Len(t, diags.Errors(), 1, "%v", diags)
+			assert.Equal(t, tc.wantSummary, diags.Errors()[0].Summary())
+			for _, want := range tc.wantDetail {
+				assert.Contains(t, diags.Errors()[0].Detail(), want)
+			}
+		})
+	}
+
+	t.Run("reachable", func(t *testing.T) {
+		// The check is on by default.
+		m := newMockRESTCatalog()
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri":         tftypes.NewValue(tftypes.String, m.start(t).URL),
+			"validate_connection": tftypes.NewValue(tftypes.Bool, nil),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, 1, m.configRequests)
+
+		// Resources reuse the client the check created.
+		_, err := p.NewCatalog(context.Background(), "iceberg_namespace")
+		require.NoError(t, err)
+		assert.Equal(t, 1, m.configRequests)
+	})
+
+	t.Run("memory catalog", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":                tftypes.NewValue(tftypes.String, "memory"),
+			"validate_connection": tftypes.NewValue(tftypes.Bool, true),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.NotNil(t, p.sharedCatalog.cat, "the client is created by the check and shared")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri":         tftypes.NewValue(tftypes.String, refused.URL),
+			"validate_connection": tftypes.NewValue(tftypes.Bool, false),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+	})
+
+	t.Run("unknown values", func(t *testing.T) {
+		// The token of a resource that isn't created yet.
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri":         tftypes.NewValue(tftypes.String, unauthorized.URL),
+			"token":               tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			"validate_connection": tftypes.NewValue(tftypes.Bool, true),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+	})
+}
@@ -0,0 +1,136 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/catalog/rest"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// createAttempts is how often a create is tried when it races the creation
+// of sibling resources.
+const createAttempts = 5
+
+// createRetryDelay is the delay before the first retry of a create; later
+// retries wait proportionally longer. It is a variable for tests.
+var createRetryDelay = 250 * time.Millisecond
+
+// isCommitConflict reports whether err is a conflict with a concurrent commit
+// rather than an existing object. Catalogs that commit all changes of a
+// namespace or branch together, such as Nessie or JDBC catalogs, report it
+// when sibling tables are created in parallel. The REST client maps every 409
+// of a create to an already exists error, so the error type is checked too.
+func isCommitConflict(err error) bool {
+	return errors.Is(err, rest.ErrCommitFailed) || strings.Contains(err.Error(), "CommitFailedException")
+}
+
+// isAlreadyExists reports whether err means the created object exists.
+func isAlreadyExists(err error) bool {
+	return !isCommitConflict(err) &&
+		(errors.Is(err, catalog.ErrTableAlreadyExists) || errors.Is(err, catalog.ErrNamespaceAlreadyExists))
+}
+
+// createWithRetry calls create until it succeeds or fails with an error that
+// retrying doesn't fix. Commit conflicts with concurrent creations are
+// retried, and so is a missing namespace when retryMissingNamespace is set:
+// catalogs may take a moment before a namespace created by the same apply is
+// visible to all of their nodes.
+//
+// An earlier attempt may have been committed even though it failed, so an
+// already exists error of a retry is checked with createdByUs: if the existing
+// object is the one this create would have made, the create succeeded.
+func createWithRetry(ctx context.Context, what string, retryMissingNamespace bool, create func() error, createdByUs func() (bool, error)) error {
+	var err error
+	for attempt := range createAttempts {
+		if attempt > 0 {
+			tflog.Debug(ctx, "Retrying create of "+what, map[string]any{"attempt": attempt + 1, "error": err.Error()})
+			select {
+			case <-ctx.Done():
+				return errors.Join(err, ctx.Err())
+			case <-time.After(time.Duration(attempt) * createRetryDelay):
+			}
+		}
+
+		err = create()
+		switch {
+		case err == nil:
+			return nil
+		case attempt > 0 && isAlreadyExists(err):
+			ok, checkErr := createdByUs()
+			if checkErr != nil {
+				return errors.Join(err, checkErr)
+			}
+			if ok {
+				tflog.Info(ctx, "An earlier attempt created "+what+", treating the create as successful")
+
+				return nil
+			}
+
+			return err
+		case isCommitConflict(err):
+		case retryMissingNamespace && errors.Is(err, catalog.ErrNoSuchNamespace):
+		default:
+			return err
+		}
+	}
+
+	return err
+}
+
+// createMissingNamespace creates namespaceIdent and any missing parents with
+// empty properties. Levels that already exist, for example because a sibling
+// iceberg_namespace resource created them concurrently, are left alone.
+func createMissingNamespace(ctx context.Context, cat catalog.Catalog, namespaceIdent table.Identifier) error {
+	for i := range namespaceIdent {
+		level := namespaceIdent[:i+1]
+		if err := createNamespace(ctx, cat, level, map[string]string{}); err != nil && !isAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isCreatedTable reports whether tbl is what creating a table with schema
+// would have made: a table without snapshots whose columns have the same names
+// in the same order. Field IDs are assigned by the catalog, so they aren't
+// compared.
+func isCreatedTable(tbl *table.Table, schema *iceberg.Schema) bool {
+	if tbl.CurrentSnapshot() != nil {
+		return false
+	}
+
+	existing := tbl.Schema().Fields()
+	want := schema.Fields()
+	if len(existing) != len(want) {
+		return false
+	}
+	for i := range want {
+		if existing[i].Name != want[i].Name || existing[i].Required != want[i].Required {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,246 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withoutCreateRetryDelay(t *testing.T) {
+	t.Helper()
+
+	delay := createRetryDelay
+	createRetryDelay = 0
+	t.Cleanup(func() { createRetryDelay = delay })
+}
+
+func TestCreateWithRetry(t *testing.T) {
+	withoutCreateRetryDelay(t)
+	ctx := context.Background()
+	schema := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+
+	newCatalog := func(m *mockRESTCatalog) catalog.Catalog {
+		server := m.start(t)
+		p := &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}
+		cat, err := p.NewCatalog(ctx, "iceberg_table")
+		require.NoError(t, err)
+
+		return cat
+	}
+	createTable := func(cat catalog.Catalog, name string) error {
+		ident := catalog.ToIdentifier("db", name)
+
+		return createWithRetry(ctx, "table db."+name, true,
+			func() error {
+				_, err := cat.CreateTable(ctx, ident, schema)
+
+				return err
+			},
+			func() (bool, error) {
+				tbl, err := cat.LoadTable(ctx, ident)
+				if err != nil {
+					return false, err
+				}
+
+				return isCreatedTable(tbl, schema), nil
+			},
+		)
+	}
+
+	t.Run("commit conflicts are retried", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "other", nil)
+		m.conflictCreates = 2
+		cat := newCatalog(m)
+
+		require.NoError(t, createTable(cat, "events"))
+		assert.NotNil(t, m.tableProperties("db", "events"))
+		assert.Len(t, m.writes, 3)
+	})
+
+	t.Run("a committed attempt that failed is adopted", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "other", nil)
+		m.lostCreates = 1
+		cat := newCatalog(m)
+
+		require.NoError(t, createTable(cat, "events"))
+		assert.Len(t, m.writes, 2)
+	})
+
+	t.Run("an existing table is not adopted", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "events", nil)
+		cat := newCatalog(m)
+
+		err := createTable(cat, "events")
+		require.ErrorIs(t, err, catalog.ErrTableAlreadyExists)
+		assert.Len(t, m.writes, 1)
+	})
+
+	t.Run("conflicts beyond the attempts are returned", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "other", nil)
+		m.conflictCreates = createAttempts
+		cat := newCatalog(m)
+
+		err := createTable(cat, "events")
+		require.Error(t, err)
+		assert.True(t, isCommitConflict(err))
+		assert.Len(t, m.writes, createAttempts)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		calls := 0
+		err := createWithRetry(ctx, "table db.events", false,
+			func() error {
+				calls++
+
+				return fmt.Errorf("wrapped: %w", catalog.ErrNoSuchNamespace)
+			},
+			func() (bool, error) { return false, errors.New("unexpected call") },
+		)
+		require.ErrorIs(t, err, catalog.ErrNoSuchNamespace)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+// TestAccIcebergTable_SiblingsInNewNamespace documents the recommended way to
+// create a namespace together with its tables: the tables reference the name
+// of the namespace resource, which orders their creation after it without
+// depends_on, and their parallel creation tolerates commit conflicts.
+func TestAccIcebergTable_SiblingsInNewNamespace(t *testing.T) {
+	withoutCreateRetryDelay(t)
+	m := newMockRESTCatalog()
+	m.conflictCreates = 4
+	server := m.start(t)
+
+	var tables strings.Builder
+	for i := range 5 {
+		fmt.Fprintf(&tables, `
+resource "iceberg_table" "t%d" {
+  namespace = iceberg_namespace.analytics.name
+  name      = "t%d"
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, i, i)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_namespace" "analytics" {
+  name = ["analytics"]
+}
+`, server.URL) + tables.String(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.t0", "id", "analytics.t0"),
+					resource.TestCheckResourceAttr("iceberg_table.t4", "id", "analytics.t4"),
+				),
+			},
+		},
+	})
+}
+
+func TestCreateMissingNamespace(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.namespaces["existing"] = map[string]string{"owner": "data_eng"}
+	server := m.start(t)
+
+	p := &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+
+	require.NoError(t, createMissingNamespace(ctx, cat, catalog.ToIdentifier("missing")))
+	assert.Equal(t, map[string]string{}, m.namespaceProperties("missing"))
+
+	// A namespace that exists, e.g. created by a sibling resource, is left alone.
+	require.NoError(t, createMissingNamespace(ctx, cat, catalog.ToIdentifier("existing")))
+	assert.Equal(t, map[string]string{"owner": "data_eng"}, m.namespaceProperties("existing"))
+}
+
+func TestAccIcebergTable_CreateNamespaceIfMissing(t *testing.T) {
+	withoutCreateRetryDelay(t)
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	config := fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "events" {
+  namespace                   = ["restored"]
+  name                        = "events"
+  create_namespace_if_missing = true
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			// The table doesn't own the namespace it created.
+			if m.namespaceProperties("restored") == nil {
+				return errors.New("the namespace was dropped with the table")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("iceberg_table.events", "id", "restored.events"),
+			},
+		},
+	})
+}
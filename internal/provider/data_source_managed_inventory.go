@@ -0,0 +1,371 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"sort"
+	"strings"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/apache/iceberg-go/view"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// inventoryLoadParallelism bounds the number of tables and views loaded at
+// once while building an inventory.
+const inventoryLoadParallelism = 8
+
+// inventoryOwnerProperty is the table and view property reported as owner.
+const inventoryOwnerProperty = "owner"
+
+var _ datasource.DataSource = &icebergManagedInventoryDataSource{}
+
+func NewManagedInventoryDataSource() datasource.DataSource {
+	return &icebergManagedInventoryDataSource{}
+}
+
+type icebergManagedInventoryDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Namespaces types.List   `tfsdk:"namespaces"`
+	Objects    types.List   `tfsdk:"objects"`
+	JSON       types.String `tfsdk:"json"`
+}
+
+// inventoryObject is one table or view of the inventory. It is both an
+// element of the objects attribute and of the JSON document.
+type inventoryObject struct {
+	Type                  string   `tfsdk:"type"                     json:"type"`
+	ID                    string   `tfsdk:"id"                       json:"id"`
+	Namespace             []string `tfsdk:"namespace"                json:"namespace"`
+	Name                  string   `tfsdk:"name"                     json:"name"`
+	Location              string   `tfsdk:"location"                 json:"location"`
+	MetadataLocation      string   `tfsdk:"metadata_location"        json:"metadata_location"`
+	Owner                 *string  `tfsdk:"owner"                    json:"owner,omitempty"`
+	FormatVersion         int64    `tfsdk:"format_version"           json:"format_version"`
+	LastCommitTimestampMs *int64   `tfsdk:"last_commit_timestamp_ms" json:"last_commit_timestamp_ms,omitempty"`
+}
+
+var inventoryObjectType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"type":                     types.StringType,
+		"id":                       types.StringType,
+		"namespace":                types.ListType{ElemType: types.StringType},
+		"name":                     types.StringType,
+		"location":                 types.StringType,
+		"metadata_location":        types.StringType,
+		"owner":                    types.StringType,
+		"format_version":           types.Int64Type,
+		"last_commit_timestamp_ms": types.Int64Type,
+	},
+}
+
+// viewCatalog is implemented by catalogs that can list and load views.
+type viewCatalog interface {
+	ListViews(ctx context.Context, namespace table.Identifier) iter.Seq2[table.Identifier, error]
+	LoadView(ctx context.Context, identifier table.Identifier) (*view.View, error)
+}
+
+// icebergManagedInventoryDataSource aggregates the tables and views of a set
+// of namespaces, with their key metadata, into a single inventory.
+type icebergManagedInventoryDataSource struct {
+	catalog  catalog.Catalog
+	provider *icebergProvider
+}
+
+func (d *icebergManagedInventoryDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_managed_inventory"
+}
+
+func (d *icebergManagedInventoryDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the tables and views of a set of namespaces with their key metadata.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"namespaces": schema.ListAttribute{
+				Description: "The namespaces to include in the inventory.",
+				Required:    true,
+				ElementType: types.ListType{ElemType: types.StringType},
+			},
+			"objects": schema.ListNestedAttribute{
+				Description: "The tables and views in the namespaces, sorted by id and type.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Description: "Either table or view.",
+							Computed:    true,
+						},
+						"id": schema.StringAttribute{
+							Description: "The full identifier of the object, in the format used for import IDs.",
+							Computed:    true,
+						},
+						"namespace": schema.ListAttribute{
+							Description: "The namespace of the object.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of the object.",
+							Computed:    true,
+						},
+						"location": schema.StringAttribute{
+							Description: "The base location of the object.",
+							Computed:    true,
+						},
+						"metadata_location": schema.StringAttribute{
+							Description: "The location of the current metadata file.",
+							Computed:    true,
+						},
+						"owner": schema.StringAttribute{
+							Description: "The value of the owner property, if set.",
+							Computed:    true,
+						},
+						"format_version": schema.Int64Attribute{
+							Description: "The format version of the table or view metadata.",
+							Computed:    true,
+						},
+						"last_commit_timestamp_ms": schema.Int64Attribute{
+							Description: "The time of the last commit to a table, the timestamp of its current snapshot in milliseconds since the epoch. Null for views and tables without snapshots.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"json": schema.StringAttribute{
+				Description: "The inventory as a JSON document, for example to write to a file with local_file.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *icebergManagedInventoryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			"Expected *icebergProvider, got: %T. Please report this issue to the provider developers.",
+		)
+
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *icebergManagedInventoryDataSource) ConfigureCatalog(ctx context.Context, diags *diag.Diagnostics) {
+	if d.catalog != nil {
+		return
+	}
+
+	if d.provider == nil {
+		diags.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation",
+		)
+
+		return
+	}
+
+	catalog, err := d.provider.NewCatalog(ctx, "data.iceberg_managed_inventory")
+	if err != nil {
+		diags.AddError(
+			"Failed to create catalog",
+			"Failed to create catalog: "+err.Error(),
+		)
+
+		return
+	}
+	d.catalog = catalog
+}
+
+func (d *icebergManagedInventoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withResourceType(ctx, "data.iceberg_managed_inventory")
+	d.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergManagedInventoryDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var namespaces [][]string
+	diags = data.Namespaces.ElementsAs(ctx, &namespaces, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	objects := d.inventory(ctx, namespaces, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ids := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		ids = append(ids, encodeIdentifier(ns))
+	}
+	data.ID = types.StringValue(strings.Join(ids, ","))
+
+	data.Objects, diags = types.ListValueFrom(ctx, inventoryObjectType, objects)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	document, err := json.MarshalIndent(map[string]any{"objects": objects}, "", "  ")
+	if err != nil {
+		resp.Diagnostics.AddError("failed to encode inventory", err.Error())
+
+		return
+	}
+	data.JSON = types.StringValue(string(document))
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// inventory lists the tables and views of the namespaces and loads them with
+// at most inventoryLoadParallelism requests in flight. Views are left out
+// with a warning if the catalog doesn't support them.
+func (d *icebergManagedInventoryDataSource) inventory(ctx context.Context, namespaces [][]string, diags *diag.Diagnostics) []inventoryObject {
+	views, _ := d.catalog.(viewCatalog)
+
+	var objects []inventoryObject
+	for _, ns := range namespaces {
+		namespaceIdent := catalog.ToIdentifier(ns...)
+
+		tables, err := listTableIdentifiers(ctx, d.catalog, namespaceIdent)
+		if err != nil {
+			diags.AddError("failed to list tables in namespace "+encodeIdentifier(namespaceIdent), err.Error())
+
+			return nil
+		}
+		for _, ident := range tables {
+			objects = append(objects, inventoryObject{Type: "table", ID: encodeIdentifier(ident), Namespace: catalog.NamespaceFromIdent(ident), Name: catalog.TableNameFromIdent(ident)})
+		}
+
+		if views == nil {
+			continue
+		}
+		viewIdents, err := listViewIdentifiers(ctx, views, namespaceIdent)
+		if isUnsupportedOperationError(err) {
+			diags.AddWarning("Views not included in inventory", "The catalog doesn't support listing views: "+err.Error())
+			views = nil
+
+			continue
+		}
+		if err != nil {
+			diags.AddError("failed to list views in namespace "+encodeIdentifier(namespaceIdent), err.Error())
+
+			return nil
+		}
+		for _, ident := range viewIdents {
+			objects = append(objects, inventoryObject{Type: "view", ID: encodeIdentifier(ident), Namespace: catalog.NamespaceFromIdent(ident), Name: catalog.TableNameFromIdent(ident)})
+		}
+	}
+
+	errs := make([]error, len(objects))
+	runBounded(len(objects), inventoryLoadParallelism, func(i int) {
+		errs[i] = d.load(ctx, views, &objects[i])
+	})
+	for i, err := range errs {
+		if err != nil {
+			diags.AddError("failed to load "+objects[i].Type+" "+objects[i].ID, err.Error())
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].ID != objects[j].ID {
+			return objects[i].ID < objects[j].ID
+		}
+
+		return objects[i].Type < objects[j].Type
+	})
+
+	return objects
+}
+
+// load fills in the metadata of obj.
+func (d *icebergManagedInventoryDataSource) load(ctx context.Context, views viewCatalog, obj *inventoryObject) error {
+	ident := append(append(table.Identifier{}, obj.Namespace...), obj.Name)
+
+	if obj.Type == "view" {
+		v, err := views.LoadView(ctx, ident)
+		if err != nil {
+			return err
+		}
+		obj.Location = v.Location()
+		obj.MetadataLocation = v.MetadataLocation()
+		obj.FormatVersion = int64(v.Metadata().FormatVersion())
+		if owner, ok := v.Properties()[inventoryOwnerProperty]; ok {
+			obj.Owner = &owner
+		}
+
+		return nil
+	}
+
+	tbl, err := d.catalog.LoadTable(ctx, ident)
+	if err != nil {
+		return err
+	}
+	obj.Location = tbl.Location()
+	obj.MetadataLocation = tbl.MetadataLocation()
+	obj.FormatVersion = int64(tbl.Metadata().Version())
+	obj.LastCommitTimestampMs = lastCommitTimestampMs(tbl)
+	if owner, ok := tbl.Properties()[inventoryOwnerProperty]; ok {
+		obj.Owner = &owner
+	}
+
+	return nil
+}
+
+// listViewIdentifiers returns the identifiers of all views in the namespace,
+// sorted like listTableIdentifiers.
+func listViewIdentifiers(ctx context.Context, cat viewCatalog, namespaceIdent table.Identifier) ([]table.Identifier, error) {
+	views := make([]table.Identifier, 0)
+	for ident, err := range cat.ListViews(ctx, namespaceIdent) {
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, ident)
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		return encodeIdentifier(views[i]) < encodeIdentifier(views[j])
+	})
+
+	return views, nil
+}
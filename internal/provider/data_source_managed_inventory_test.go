@@ -0,0 +1,137 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newInventoryTestDataSource(t *testing.T, m *mockRESTCatalog) *icebergManagedInventoryDataSource {
+	t.Helper()
+
+	server := m.start(t)
+	d := &icebergManagedInventoryDataSource{
+		provider: &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()},
+	}
+
+	var diags diag.Diagnostics
+	d.ConfigureCatalog(context.Background(), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	return d
+}
+
+func TestManagedInventory(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("sales", "orders", map[string]string{"owner": "sales-team"})
+	m.addTable("analytics", "events", nil)
+	m.addView("analytics", "daily_events", map[string]string{"owner": "bi"})
+	m.addTable("ignored", "other", nil)
+	d := newInventoryTestDataSource(t, m)
+
+	var diags diag.Diagnostics
+	objects := d.inventory(ctx, [][]string{{"sales"}, {"analytics"}}, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	owner := func(s string) *string { return &s }
+	assert.Equal(t, []inventoryObject{
+		{
+			Type: "view", ID: "analytics.daily_events", Namespace: []string{"analytics"}, Name: "daily_events",
+			Location:         "file:///tmp/warehouse/analytics/daily_events",
+			MetadataLocation: "file:///tmp/warehouse/analytics/daily_events/metadata/00000.metadata.json",
+			Owner:            owner("bi"), FormatVersion: 1,
+		},
+		{
+			Type: "table", ID: "analytics.events", Namespace: []string{"analytics"}, Name: "events",
+			Location:         "file:///tmp/warehouse/analytics/events",
+			MetadataLocation: "file:///tmp/warehouse/analytics/events/metadata/00000.metadata.json",
+			FormatVersion:    2,
+		},
+		{
+			Type: "table", ID: "sales.orders", Namespace: []string{"sales"}, Name: "orders",
+			Location:         "file:///tmp/warehouse/sales/orders",
+			MetadataLocation: "file:///tmp/warehouse/sales/orders/metadata/00000.metadata.json",
+			Owner:            owner("sales-team"), FormatVersion: 2,
+		},
+	}, objects)
+
+	list, d2 := types.ListValueFrom(ctx, inventoryObjectType, objects)
+	require.False(t, d2.HasError(), "%v", d2)
+	assert.Len(t, list.Elements(), 3)
+}
+
+func TestManagedInventoryWithoutViews(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	m.noViews = true
+	d := newInventoryTestDataSource(t, m)
+
+	var diags diag.Diagnostics
+	objects := d.inventory(ctx, [][]string{{"db"}}, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Len(t, diags.Warnings(), 1)
+	assert.Equal(t, "Views not included in inventory", diags.Warnings()[0].Summary())
+	require.Len(t, objects, 1)
+	assert.Equal(t, "db.events", objects[0].ID)
+}
+
+func TestManagedInventoryMissingNamespace(t *testing.T) {
+	m := newMockRESTCatalog()
+	d := newInventoryTestDataSource(t, m)
+
+	var diags diag.Diagnostics
+	d.inventory(context.Background(), [][]string{{"missing"}}, &diags)
+	require.True(t, diags.HasError())
+	assert.Equal(t, "failed to list tables in namespace missing", diags.Errors()[0].Summary())
+}
+
+func TestAccIcebergManagedInventoryDataSource(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", map[string]string{"owner": "data"})
+	m.addView("db", "daily", nil)
+	server := m.start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(providerConfig, server.URL) + `
+data "iceberg_managed_inventory" "all" {
+  namespaces = [["db"]]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.iceberg_managed_inventory.all", "objects.#", "2"),
+					resource.TestCheckResourceAttr("data.iceberg_managed_inventory.all", "objects.0.type", "view"),
+					resource.TestCheckNoResourceAttr("data.iceberg_managed_inventory.all", "objects.0.owner"),
+					resource.TestCheckResourceAttr("data.iceberg_managed_inventory.all", "objects.1.owner", "data"),
+					resource.TestCheckResourceAttrSet("data.iceberg_managed_inventory.all", "json"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,157 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &icebergNamespaceDataSource{}
+
+func NewNamespaceDataSource() datasource.DataSource {
+	return &icebergNamespaceDataSource{}
+}
+
+type icebergNamespaceDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	Name       types.List   `tfsdk:"name"`
+	Properties types.Map    `tfsdk:"properties"`
+}
+
+// icebergNamespaceDataSource reads the current properties of a namespace. Since
+// it only depends on the namespace name it is resolved during plan, which makes
+// it suitable for lifecycle preconditions on other resources.
+type icebergNamespaceDataSource struct {
+	catalog  catalog.Catalog
+	provider *icebergProvider
+}
+
+func (d *icebergNamespaceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_namespace"
+}
+
+func (d *icebergNamespaceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads an existing Iceberg namespace and its properties.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.ListAttribute{
+				Description: "The name of the namespace.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"properties": schema.MapAttribute{
+				Description: "All properties currently set on the namespace, as returned by the server.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *icebergNamespaceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			"Expected *icebergProvider, got: %T. Please report this issue to the provider developers.",
+		)
+
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *icebergNamespaceDataSource) ConfigureCatalog(ctx context.Context, diags *diag.Diagnostics) {
+	if d.catalog != nil {
+		return
+	}
+
+	if d.provider == nil {
+		diags.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation",
+		)
+
+		return
+	}
+
+	catalog, err := d.provider.NewCatalog(ctx, "data.iceberg_namespace")
+	if err != nil {
+		diags.AddError(
+			"Failed to create catalog",
+			"Failed to create catalog: "+err.Error(),
+		)
+
+		return
+	}
+	d.catalog = catalog
+}
+
+func (d *icebergNamespaceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withResourceType(ctx, "data.iceberg_namespace")
+	d.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergNamespaceDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var namespaceName []string
+	diags = data.Name.ElementsAs(ctx, &namespaceName, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespaceIdent := catalog.ToIdentifier(namespaceName...)
+
+	nsProps, err := d.catalog.LoadNamespaceProperties(ctx, namespaceIdent)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to load namespace", err.Error())
+
+		return
+	}
+
+	data.ID = types.StringValue(encodeIdentifier(namespaceIdent))
+	data.Properties, diags = types.MapValueFrom(ctx, types.StringType, nsProps)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
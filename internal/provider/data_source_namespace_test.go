@@ -0,0 +1,103 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccIcebergNamespaceDataSourcePrecondition(t *testing.T) {
+	t.Parallel()
+
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIcebergNamespaceDataSourceConfig(providerCfg, namespace, "silver", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.precondition", "user_properties.tier", "silver"),
+				),
+			},
+			{
+				// The data source is read during plan, so the precondition fails before anything is created.
+				Config:      testAccIcebergNamespaceDataSourceConfig(providerCfg, namespace, "silver", true),
+				ExpectError: regexp.MustCompile(`Resource precondition failed`),
+			},
+			{
+				Config: testAccIcebergNamespaceDataSourceConfig(providerCfg, namespace, "gold", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.iceberg_namespace.precondition", "properties.tier", "gold"),
+				),
+			},
+			{
+				Config: testAccIcebergNamespaceDataSourceConfig(providerCfg, namespace, "gold", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.precondition", "name", "gold_only"),
+				),
+			},
+		},
+	})
+}
+
+func testAccIcebergNamespaceDataSourceConfig(providerCfg, namespace, tier string, withTable bool) string {
+	tableCfg := ""
+	if withTable {
+		tableCfg = `
+resource "iceberg_table" "precondition" {
+  namespace = data.iceberg_namespace.precondition.name
+  name      = "gold_only"
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+
+  lifecycle {
+    precondition {
+      condition     = lookup(data.iceberg_namespace.precondition.properties, "tier", "") == "gold"
+      error_message = "Tables may only be created in gold namespaces."
+    }
+  }
+}
+`
+	}
+
+	return providerCfg + fmt.Sprintf(`
+resource "iceberg_namespace" "precondition" {
+  name = [%q]
+  user_properties = {
+    tier = "%s"
+  }
+}
+
+data "iceberg_namespace" "precondition" {
+  name = iceberg_namespace.precondition.name
+}
+%s`, namespace, tier, tableCfg)
+}
@@ -0,0 +1,172 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &icebergProviderInfoDataSource{}
+
+func NewProviderInfoDataSource() datasource.DataSource {
+	return &icebergProviderInfoDataSource{}
+}
+
+type icebergProviderInfoDataSourceModel struct {
+	ID                   types.String `tfsdk:"id"`
+	CatalogType          types.String `tfsdk:"catalog_type"`
+	CatalogHost          types.String `tfsdk:"catalog_host"`
+	CatalogName          types.String `tfsdk:"catalog_name"`
+	ServerImplementation types.String `tfsdk:"server_implementation"`
+	Capabilities         types.Map    `tfsdk:"capabilities"`
+	APICalls             types.Map    `tfsdk:"api_calls"`
+}
+
+// icebergProviderInfoDataSource describes the catalog the provider talks to,
+// so modules can adapt to the catalog implementation and its features.
+type icebergProviderInfoDataSource struct {
+	provider *icebergProvider
+}
+
+func (d *icebergProviderInfoDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_provider_info"
+}
+
+func (d *icebergProviderInfoDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Describes the catalog the provider is configured for: its type, host, implementation and the optional features it supports.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"catalog_type": schema.StringAttribute{
+				Description: "The configured type of catalog, 'rest', 'polaris', 'glue', 'sql' or 'memory'.",
+				Computed:    true,
+			},
+			"catalog_host": schema.StringAttribute{
+				Description: "The host of catalog_uri, including the port if it has one. With type = 'glue', the host of the Glue API endpoint; with type = 'sql', the host of sql_dsn, empty for SQLite and with type = 'memory'.",
+				Computed:    true,
+			},
+			"catalog_name": schema.StringAttribute{
+				Description: catalogNameDescription,
+				Computed:    true,
+			},
+			"server_implementation": schema.StringAttribute{
+				Description: "The catalog implementation, identified from the Server header and the endpoints of its config response or from its host: 'polaris', 'lakekeeper', 'nessie', 'gravitino', 'unity', 'glue', 's3tables', 'sql', 'memory' or 'unknown'.",
+				Computed:    true,
+			},
+			"api_calls": schema.MapAttribute{
+				Description: "The number of requests the provider sent in this Terraform run before the data source was read, by endpoint family: config, oauth_token, list_namespaces, load_namespace, create_namespace, update_namespace, drop_namespace, list_tables, load_table, create_table, commit_table, rename_table, drop_table, views, management for the Polaris and Lakekeeper management APIs, and other. Retries count as separate requests. With enable_operation_metrics, the totals of the whole run are written to the provider's stderr when it shuts down.",
+				Computed:    true,
+				ElementType: types.Int64Type,
+			},
+			"capabilities": schema.MapAttribute{
+				Description: "Whether the catalog supports each optional feature the provider uses: namespace_properties, namespace_property_removal and update_table. Features are assumed to be supported unless the catalog leaves them out of its advertised endpoints or rejected them earlier in the run.",
+				Computed:    true,
+				ElementType: types.BoolType,
+			},
+		},
+	}
+}
+
+func (d *icebergProviderInfoDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			"Expected *icebergProvider, got: %T. Please report this issue to the provider developers.",
+		)
+
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *icebergProviderInfoDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.provider == nil {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation",
+		)
+
+		return
+	}
+
+	// Creating the catalog client fetches its config, which fills the
+	// capabilities, unless a resource created it already.
+	if _, err := d.provider.NewCatalog(ctx, "data.iceberg_provider_info"); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to create catalog",
+			"Failed to create catalog: "+err.Error(),
+		)
+
+		return
+	}
+
+	catalogType, catalogURI := "rest", d.provider.catalogURI
+	switch {
+	case d.provider.polaris != nil:
+		catalogType = "polaris"
+	case d.provider.glue != nil:
+		catalogType, catalogURI = "glue", d.provider.glue.endpoint()
+	case d.provider.sql != nil:
+		catalogType, catalogURI = d.provider.catalogType, d.provider.sql.endpoint
+	}
+
+	var host string
+	if u, err := url.Parse(catalogURI); err == nil {
+		host = u.Host
+	}
+
+	data := icebergProviderInfoDataSourceModel{
+		ID:                   types.StringValue(catalogURI),
+		CatalogType:          types.StringValue(catalogType),
+		CatalogHost:          types.StringValue(host),
+		CatalogName:          d.provider.catalogNameValue(),
+		ServerImplementation: types.StringValue(d.provider.capabilities.serverImplementation()),
+	}
+
+	var diags diag.Diagnostics
+	data.Capabilities, diags = types.MapValueFrom(ctx, types.BoolType, d.provider.capabilities.featureFlags())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	apiCalls := d.provider.apiCalls
+	if apiCalls == nil {
+		apiCalls = &apiCallCounter{}
+	}
+	data.APICalls, diags = types.MapValueFrom(ctx, types.Int64Type, apiCalls.snapshot())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,74 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccProviderInfoDataSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/config" {
+			writeRESTError(w, http.StatusNotFound, "NotFoundException")
+
+			return
+		}
+		w.Header().Set("Server", "Lakekeeper/0.9.1")
+		writeJSON(w, http.StatusOK, map[string]any{
+			"defaults":  map[string]string{},
+			"overrides": map[string]string{},
+			"endpoints": []string{featureNamespaceProperties.endpoint},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+data "iceberg_provider_info" "this" {}
+`, server.URL),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.iceberg_provider_info.this", "catalog_type", "rest"),
+					resource.TestCheckResourceAttr("data.iceberg_provider_info.this", "catalog_host", u.Host),
+					resource.TestCheckNoResourceAttr("data.iceberg_provider_info.this", "catalog_name"),
+					resource.TestCheckResourceAttr("data.iceberg_provider_info.this", "server_implementation", "lakekeeper"),
+					resource.TestCheckResourceAttr("data.iceberg_provider_info.this", "capabilities.namespace_properties", "true"),
+					resource.TestCheckResourceAttr("data.iceberg_provider_info.this", "capabilities.update_table", "false"),
+					resource.TestCheckResourceAttr("data.iceberg_provider_info.this", "api_calls.config", "1"),
+					resource.TestCheckResourceAttr("data.iceberg_provider_info.this", "api_calls.load_table", "0"),
+				),
+			},
+		},
+	})
+}
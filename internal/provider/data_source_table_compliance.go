@@ -0,0 +1,279 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/apache/iceberg-terraform/internal/compliance"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &icebergTableComplianceDataSource{}
+
+func NewTableComplianceDataSource() datasource.DataSource {
+	return &icebergTableComplianceDataSource{}
+}
+
+type icebergTableComplianceDataSourceModel struct {
+	ID                        types.String `tfsdk:"id"`
+	Namespace                 types.List   `tfsdk:"namespace"`
+	Name                      types.String `tfsdk:"name"`
+	RequiredProperties        types.List   `tfsdk:"required_properties"`
+	RequireRetention          types.Bool   `tfsdk:"require_retention"`
+	MinFormatVersion          types.Int64  `tfsdk:"min_format_version"`
+	RequireTimePartitioning   types.Bool   `tfsdk:"require_time_partitioning"`
+	Compliant                 types.Bool   `tfsdk:"compliant"`
+	RequiredPropertiesPresent types.Bool   `tfsdk:"required_properties_present"`
+	RetentionConfigured       types.Bool   `tfsdk:"retention_configured"`
+	FormatVersionOK           types.Bool   `tfsdk:"format_version_ok"`
+	PartitionedByTime         types.Bool   `tfsdk:"partitioned_by_time"`
+	Violations                types.List   `tfsdk:"violations"`
+}
+
+// icebergTableComplianceDataSource checks a table against the rules of the
+// compliance package that are turned on, for use in check blocks and
+// postconditions.
+type icebergTableComplianceDataSource struct {
+	catalog  catalog.Catalog
+	provider *icebergProvider
+}
+
+func (d *icebergTableComplianceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table_compliance"
+}
+
+func (d *icebergTableComplianceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Checks an existing Iceberg table against a set of rules, such as required properties or a minimum format version. " +
+			"Only the rules that are turned on are checked; the result of every other rule is null.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"namespace": schema.ListAttribute{
+				Description: "The namespace of the table.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the table.",
+				Required:    true,
+			},
+			"required_properties": schema.ListAttribute{
+				Description: "Properties the table must have with a non-empty value, for example owner.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"require_retention": schema.BoolAttribute{
+				Description: "If true, the table must have a snapshot retention policy: the history.expire.max-snapshot-age-ms or history.expire.min-snapshots-to-keep property, or a retention set on its main branch.",
+				Optional:    true,
+			},
+			"min_format_version": schema.Int64Attribute{
+				Description: "The lowest format version the table may have.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"require_time_partitioning": schema.BoolAttribute{
+				Description: "If true, the current partition spec of the table must partition by time: with a year, month, day or hour transform, or by a date or timestamp column.",
+				Optional:    true,
+			},
+			"compliant": schema.BoolAttribute{
+				Description: "Whether the table follows all rules that are turned on. True if none are.",
+				Computed:    true,
+			},
+			"required_properties_present": schema.BoolAttribute{
+				Description: "Whether the table has all required_properties. Null if required_properties is unset.",
+				Computed:    true,
+			},
+			"retention_configured": schema.BoolAttribute{
+				Description: "Whether the table has a snapshot retention policy. Null unless require_retention is set.",
+				Computed:    true,
+			},
+			"format_version_ok": schema.BoolAttribute{
+				Description: "Whether the format version of the table is at least min_format_version. Null if min_format_version is unset.",
+				Computed:    true,
+			},
+			"partitioned_by_time": schema.BoolAttribute{
+				Description: "Whether the table is partitioned by time. Null unless require_time_partitioning is set.",
+				Computed:    true,
+			},
+			"violations": schema.ListAttribute{
+				Description: "A message for every way the table breaks a rule, prefixed with the rule, for example 'min_format_version: format version is 2, at least 3 is required'. Empty if the table is compliant.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (d *icebergTableComplianceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			"Expected *icebergProvider, got: %T. Please report this issue to the provider developers.",
+		)
+
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *icebergTableComplianceDataSource) ConfigureCatalog(ctx context.Context, diags *diag.Diagnostics) {
+	if d.catalog != nil {
+		return
+	}
+
+	if d.provider == nil {
+		diags.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation",
+		)
+
+		return
+	}
+
+	catalog, err := d.provider.NewCatalog(ctx, "data.iceberg_table_compliance")
+	if err != nil {
+		diags.AddError(
+			"Failed to create catalog",
+			"Failed to create catalog: "+err.Error(),
+		)
+
+		return
+	}
+	d.catalog = catalog
+}
+
+func (d *icebergTableComplianceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withResourceType(ctx, "data.iceberg_table_compliance")
+	d.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergTableComplianceDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var namespaceName []string
+	diags = data.Namespace.ElementsAs(ctx, &namespaceName, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tableIdent := table.Identifier(append(namespaceName, data.Name.ValueString()))
+
+	tbl, err := d.catalog.LoadTable(ctx, tableIdent)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to load table", err.Error())
+
+		return
+	}
+	if err := checkTableMetadata(tbl.Metadata()); err != nil {
+		addInconsistentMetadataError(encodeIdentifier(tableIdent), err, &resp.Diagnostics)
+
+		return
+	}
+
+	rules := complianceRules(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(encodeIdentifier(tableIdent))
+	resp.Diagnostics.Append(data.setResults(ctx, compliance.Evaluate(tbl.Metadata(), rules...))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// complianceRules returns the rules turned on in data.
+func complianceRules(ctx context.Context, data *icebergTableComplianceDataSourceModel, diags *diag.Diagnostics) []compliance.Rule {
+	var rules []compliance.Rule
+	if !data.RequiredProperties.IsNull() {
+		var keys []string
+		diags.Append(data.RequiredProperties.ElementsAs(ctx, &keys, false)...)
+		rules = append(rules, compliance.RequiredProperties(keys))
+	}
+	if data.RequireRetention.ValueBool() {
+		rules = append(rules, compliance.RetentionConfigured{})
+	}
+	if !data.MinFormatVersion.IsNull() {
+		rules = append(rules, compliance.MinFormatVersion(data.MinFormatVersion.ValueInt64()))
+	}
+	if data.RequireTimePartitioning.ValueBool() {
+		rules = append(rules, compliance.PartitionedByTime{})
+	}
+
+	return rules
+}
+
+// setResults sets the computed attributes from the results of
+// complianceRules.
+func (data *icebergTableComplianceDataSourceModel) setResults(ctx context.Context, results []compliance.Result) diag.Diagnostics {
+	data.RequiredPropertiesPresent = types.BoolNull()
+	data.RetentionConfigured = types.BoolNull()
+	data.FormatVersionOK = types.BoolNull()
+	data.PartitionedByTime = types.BoolNull()
+
+	violations := make([]string, 0)
+	for _, result := range results {
+		passed := types.BoolValue(result.Passed())
+		switch result.Rule {
+		case compliance.RequiredProperties(nil).Name():
+			data.RequiredPropertiesPresent = passed
+		case compliance.RetentionConfigured{}.Name():
+			data.RetentionConfigured = passed
+		case compliance.MinFormatVersion(0).Name():
+			data.FormatVersionOK = passed
+		case compliance.PartitionedByTime{}.Name():
+			data.PartitionedByTime = passed
+		}
+		for _, v := range result.Violations {
+			violations = append(violations, result.Rule+": "+v)
+		}
+	}
+	data.Compliant = types.BoolValue(len(violations) == 0)
+
+	var diags diag.Diagnostics
+	data.Violations, diags = types.ListValueFrom(ctx, types.StringType, violations)
+
+	return diags
+}
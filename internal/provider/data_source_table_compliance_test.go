@@ -0,0 +1,126 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-terraform/internal/compliance"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableComplianceResults(t *testing.T) {
+	ctx := context.Background()
+	metadata, err := mockTableMetadata("db", "events", map[string]string{"owner": "data_eng"})
+	require.NoError(t, err)
+
+	data := icebergTableComplianceDataSourceModel{
+		RequiredProperties:      types.ListValueMust(types.StringType, []attr.Value{types.StringValue("owner")}),
+		RequireRetention:        types.BoolNull(),
+		MinFormatVersion:        types.Int64Value(3),
+		RequireTimePartitioning: types.BoolValue(false),
+	}
+	var diags diag.Diagnostics
+	rules := complianceRules(ctx, &data, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Len(t, rules, 2)
+
+	require.False(t, data.setResults(ctx, compliance.Evaluate(metadata, rules...)).HasError())
+	assert.Equal(t, types.BoolValue(false), data.Compliant)
+	assert.Equal(t, types.BoolValue(true), data.RequiredPropertiesPresent)
+	assert.Equal(t, types.BoolValue(false), data.FormatVersionOK)
+	assert.True(t, data.RetentionConfigured.IsNull(), "rules that are off have no result")
+	assert.True(t, data.PartitionedByTime.IsNull(), "rules that are off have no result")
+	assert.Equal(t, types.ListValueMust(types.StringType, []attr.Value{
+		types.StringValue("min_format_version: format version is 2, at least 3 is required"),
+	}), data.Violations)
+
+	t.Run("no rules", func(t *testing.T) {
+		var empty icebergTableComplianceDataSourceModel
+		require.False(t, empty.setResults(ctx, compliance.Evaluate(metadata)).HasError())
+		assert.Equal(t, types.BoolValue(true), empty.Compliant)
+		assert.Empty(t, empty.Violations.Elements())
+	})
+}
+
+func TestAccTableComplianceDataSource(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", map[string]string{"owner": "data_eng", "history.expire.max-snapshot-age-ms": "604800000"})
+	server := m.start(t)
+
+	config := func(rules string) string {
+		return fmt.Sprintf(providerConfig, server.URL) + fmt.Sprintf(`
+data "iceberg_table_compliance" "events" {
+  namespace = ["db"]
+  name      = "events"
+  %s
+}
+
+output "violations" {
+  value = join("; ", data.iceberg_table_compliance.events.violations)
+}
+`, rules)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`
+  required_properties = ["owner"]
+  require_retention   = true
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.iceberg_table_compliance.events", "compliant", "true"),
+					resource.TestCheckResourceAttr("data.iceberg_table_compliance.events", "retention_configured", "true"),
+					resource.TestCheckNoResourceAttr("data.iceberg_table_compliance.events", "partitioned_by_time"),
+					resource.TestCheckOutput("violations", ""),
+				),
+			},
+			{
+				Config: config(`
+  required_properties       = ["owner", "team"]
+  require_time_partitioning = true
+`) + `
+check "events_compliant" {
+  assert {
+    condition     = data.iceberg_table_compliance.events.compliant
+    error_message = join("\n", data.iceberg_table_compliance.events.violations)
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.iceberg_table_compliance.events", "compliant", "false"),
+					resource.TestCheckResourceAttr("data.iceberg_table_compliance.events", "required_properties_present", "false"),
+					resource.TestCheckOutput("violations", "required_properties: property team is not set; partitioned_by_time: the table is not partitioned"),
+				),
+			},
+			{
+				Config:      config(`min_format_version = 0`),
+				ExpectError: regexp.MustCompile("must be at least 1"),
+			},
+		},
+	})
+}
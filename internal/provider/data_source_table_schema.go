@@ -0,0 +1,249 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &icebergTableSchemaDataSource{}
+
+func NewTableSchemaDataSource() datasource.DataSource {
+	return &icebergTableSchemaDataSource{}
+}
+
+type icebergTableSchemaDataSourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Namespace       types.List   `tfsdk:"namespace"`
+	Name            types.String `tfsdk:"name"`
+	SchemaID        types.Int64  `tfsdk:"schema_id"`
+	CurrentSchemaID types.Int64  `tfsdk:"current_schema_id"`
+	SchemaIDs       types.List   `tfsdk:"schema_ids"`
+	Schema          types.Object `tfsdk:"schema"`
+	SchemaJSON      types.String `tfsdk:"schema_json"`
+}
+
+// icebergTableSchemaDataSource reads one of the schemas retained in the
+// metadata of a table, the current one unless schema_id pins another, so
+// DDL generated from it doesn't change when the table evolves.
+type icebergTableSchemaDataSource struct {
+	catalog  catalog.Catalog
+	provider *icebergProvider
+}
+
+func (d *icebergTableSchemaDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table_schema"
+}
+
+func (d *icebergTableSchemaDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads a schema of an existing Iceberg table. Table metadata keeps every schema the table had, so a schema_id pins the schema " +
+			"even after the table evolved; without one, the current schema is read.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"namespace": schema.ListAttribute{
+				Description: "The namespace of the table.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the table.",
+				Required:    true,
+			},
+			"schema_id": schema.Int64Attribute{
+				Description: "The ID of the schema to read. Reading fails if the table metadata has no schema with this ID. Defaults to the current schema.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"current_schema_id": schema.Int64Attribute{
+				Description: "The ID of the current schema of the table.",
+				Computed:    true,
+			},
+			"schema_ids": schema.ListAttribute{
+				Description: "The IDs of all schemas in the table metadata, in the order they were added.",
+				Computed:    true,
+				ElementType: types.Int64Type,
+			},
+			"schema": schema.ObjectAttribute{
+				Description:    "The schema, in the form of the schema attribute of iceberg_table.",
+				Computed:       true,
+				AttributeTypes: schemaAttrTypes,
+			},
+			"schema_json": schema.StringAttribute{
+				Description: "The schema as Iceberg schema JSON, for example for the schema_diff function.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (d *icebergTableSchemaDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			"Expected *icebergProvider, got: %T. Please report this issue to the provider developers.",
+		)
+
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *icebergTableSchemaDataSource) ConfigureCatalog(ctx context.Context, diags *diag.Diagnostics) {
+	if d.catalog != nil {
+		return
+	}
+
+	if d.provider == nil {
+		diags.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation",
+		)
+
+		return
+	}
+
+	catalog, err := d.provider.NewCatalog(ctx, "data.iceberg_table_schema")
+	if err != nil {
+		diags.AddError(
+			"Failed to create catalog",
+			"Failed to create catalog: "+err.Error(),
+		)
+
+		return
+	}
+	d.catalog = catalog
+}
+
+func (d *icebergTableSchemaDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withResourceType(ctx, "data.iceberg_table_schema")
+	d.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergTableSchemaDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var namespaceName []string
+	diags = data.Namespace.ElementsAs(ctx, &namespaceName, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tableIdent := table.Identifier(append(namespaceName, data.Name.ValueString()))
+
+	tbl, err := d.catalog.LoadTable(ctx, tableIdent)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to load table", err.Error())
+
+		return
+	}
+	if err := checkTableMetadata(tbl.Metadata()); err != nil {
+		addInconsistentMetadataError(encodeIdentifier(tableIdent), err, &resp.Diagnostics)
+
+		return
+	}
+
+	data.ID = types.StringValue(encodeIdentifier(tableIdent))
+	data.setSchema(ctx, tbl.Metadata(), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// setSchema sets the computed attributes from the schema of metadata that
+// schema_id selects, the current schema if it is unset.
+func (data *icebergTableSchemaDataSourceModel) setSchema(ctx context.Context, metadata table.Metadata, diags *diag.Diagnostics) {
+	current := metadata.CurrentSchema()
+	ids := make([]int64, 0, len(metadata.Schemas()))
+	for _, s := range metadata.Schemas() {
+		ids = append(ids, int64(s.ID))
+	}
+
+	selected := current
+	if !data.SchemaID.IsNull() && !data.SchemaID.IsUnknown() {
+		selected = schemaByID(metadata, int(data.SchemaID.ValueInt64()))
+		if selected == nil {
+			known := make([]string, 0, len(ids))
+			for _, id := range ids {
+				known = append(known, strconv.FormatInt(id, 10))
+			}
+			diags.AddAttributeError(path.Root("schema_id"), "Schema not found",
+				"The table "+data.ID.ValueString()+" has no schema with ID "+strconv.FormatInt(data.SchemaID.ValueInt64(), 10)+
+					". Its metadata has the schemas "+strings.Join(known, ", ")+".")
+
+			return
+		}
+	}
+
+	schemaJSON, err := json.Marshal(selected)
+	if err != nil {
+		diags.AddError("failed to encode schema", err.Error())
+
+		return
+	}
+
+	var d diag.Diagnostics
+	data.SchemaIDs, d = types.ListValueFrom(ctx, types.Int64Type, ids)
+	diags.Append(d...)
+	data.Schema, d = schemaObjectValue(ctx, selected)
+	diags.Append(d...)
+	data.SchemaID = types.Int64Value(int64(selected.ID))
+	data.CurrentSchemaID = types.Int64Value(int64(current.ID))
+	data.SchemaJSON = types.StringValue(string(schemaJSON))
+}
+
+// schemaByID returns the schema of metadata with the ID id, nil if there is
+// none.
+func schemaByID(metadata table.Metadata, id int) *iceberg.Schema {
+	for _, s := range metadata.Schemas() {
+		if s.ID == id {
+			return s
+		}
+	}
+
+	return nil
+}
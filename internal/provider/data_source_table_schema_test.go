@@ -0,0 +1,149 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tableSchemaTestEvolutions are the two schemas the test table evolves to
+// from the mock schema: a column is added, then renamed.
+func tableSchemaTestEvolutions() []*iceberg.Schema {
+	return []*iceberg.Schema{
+		iceberg.NewSchema(1,
+			iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+			iceberg.NestedField{ID: 2, Name: "email", Type: iceberg.PrimitiveTypes.String},
+		),
+		iceberg.NewSchema(2,
+			iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+			iceberg.NestedField{ID: 2, Name: "email_address", Type: iceberg.PrimitiveTypes.String},
+		),
+	}
+}
+
+func TestTableSchemaDataSourceSetSchema(t *testing.T) {
+	ctx := context.Background()
+	base, err := mockTableMetadata("db", "events", nil)
+	require.NoError(t, err)
+	metadata, err := evolveMetadata(base, tableSchemaTestEvolutions())
+	require.NoError(t, err)
+
+	fieldNames := func(t *testing.T, data icebergTableSchemaDataSourceModel) []string {
+		t.Helper()
+
+		var s icebergTableSchema
+		require.False(t, data.Schema.As(ctx, &s, basetypes.ObjectAsOptions{}).HasError())
+		var names []string
+		for _, f := range s.Fields {
+			names = append(names, f.Name)
+		}
+
+		return names
+	}
+
+	t.Run("current", func(t *testing.T) {
+		data := icebergTableSchemaDataSourceModel{ID: types.StringValue("db.events"), SchemaID: types.Int64Null()}
+		var diags diag.Diagnostics
+		data.setSchema(ctx, metadata, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		assert.Equal(t, types.Int64Value(2), data.SchemaID)
+		assert.Equal(t, types.Int64Value(2), data.CurrentSchemaID)
+		assert.Equal(t, types.ListValueMust(types.Int64Type, []attr.Value{types.Int64Value(0), types.Int64Value(1), types.Int64Value(2)}), data.SchemaIDs)
+		assert.Equal(t, []string{"id", "email_address"}, fieldNames(t, data))
+	})
+
+	t.Run("pinned", func(t *testing.T) {
+		data := icebergTableSchemaDataSourceModel{ID: types.StringValue("db.events"), SchemaID: types.Int64Value(1)}
+		var diags diag.Diagnostics
+		data.setSchema(ctx, metadata, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		assert.Equal(t, types.Int64Value(1), data.SchemaID)
+		assert.Equal(t, types.Int64Value(2), data.CurrentSchemaID)
+		assert.Equal(t, []string{"id", "email"}, fieldNames(t, data))
+		assert.JSONEq(t, `{"type": "struct", "schema-id": 1, "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "email", "type": "string", "required": false}
+], "identifier-field-ids": []}`, data.SchemaJSON.ValueString())
+	})
+
+	t.Run("unknown schema", func(t *testing.T) {
+		data := icebergTableSchemaDataSourceModel{ID: types.StringValue("db.events"), SchemaID: types.Int64Value(7)}
+		var diags diag.Diagnostics
+		data.setSchema(ctx, metadata, &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Schema not found", diags.Errors()[0].Summary())
+		assert.Contains(t, diags.Errors()[0].Detail(), "has no schema with ID 7. Its metadata has the schemas 0, 1, 2.")
+	})
+}
+
+func TestAccTableSchemaDataSource(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	m.evolveTable("db", "events", tableSchemaTestEvolutions()...)
+	server := m.start(t)
+
+	config := func(schemaID string) string {
+		return fmt.Sprintf(providerConfig, server.URL) + fmt.Sprintf(`
+data "iceberg_table_schema" "events" {
+  namespace = ["db"]
+  name      = "events"
+  %s
+}
+`, schemaID)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.iceberg_table_schema.events", "schema_id", "2"),
+					resource.TestCheckResourceAttr("data.iceberg_table_schema.events", "current_schema_id", "2"),
+					resource.TestCheckResourceAttr("data.iceberg_table_schema.events", "schema_ids.#", "3"),
+					resource.TestCheckResourceAttr("data.iceberg_table_schema.events", "schema.fields.1.name", "email_address"),
+				),
+			},
+			{
+				Config: config(`schema_id = 1`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.iceberg_table_schema.events", "schema_id", "1"),
+					resource.TestCheckResourceAttr("data.iceberg_table_schema.events", "schema.id", "1"),
+					resource.TestCheckResourceAttr("data.iceberg_table_schema.events", "schema.fields.1.name", "email"),
+				),
+			},
+			{
+				Config:      config(`schema_id = 7`),
+				ExpectError: regexp.MustCompile("has no schema with ID 7"),
+			},
+		},
+	})
+}
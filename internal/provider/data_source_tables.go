@@ -0,0 +1,228 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"sort"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &icebergTablesDataSource{}
+
+func NewTablesDataSource() datasource.DataSource {
+	return &icebergTablesDataSource{}
+}
+
+type icebergTablesDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Namespace types.List   `tfsdk:"namespace"`
+	Tables    types.List   `tfsdk:"tables"`
+}
+
+var icebergTablesDataSourceTableType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"id":        types.StringType,
+		"namespace": types.ListType{ElemType: types.StringType},
+		"name":      types.StringType,
+	},
+}
+
+// icebergTablesDataSource lists the tables of a namespace. The id of every
+// table is the import ID of iceberg_table, so the list can drive import
+// blocks that adopt all tables of an existing namespace.
+type icebergTablesDataSource struct {
+	catalog  catalog.Catalog
+	provider *icebergProvider
+}
+
+func (d *icebergTablesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tables"
+}
+
+func (d *icebergTablesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the tables of an Iceberg namespace.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"namespace": schema.ListAttribute{
+				Description: "The namespace to list the tables of.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"tables": schema.ListNestedAttribute{
+				Description: "The tables in the namespace, sorted by id.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Description: "The full identifier of the table, in the format accepted when importing an iceberg_table.",
+							Computed:    true,
+						},
+						"namespace": schema.ListAttribute{
+							Description: "The namespace of the table.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+						"name": schema.StringAttribute{
+							Description: "The name of the table.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *icebergTablesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			"Expected *icebergProvider, got: %T. Please report this issue to the provider developers.",
+		)
+
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *icebergTablesDataSource) ConfigureCatalog(ctx context.Context, diags *diag.Diagnostics) {
+	if d.catalog != nil {
+		return
+	}
+
+	if d.provider == nil {
+		diags.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation",
+		)
+
+		return
+	}
+
+	catalog, err := d.provider.NewCatalog(ctx, "data.iceberg_tables")
+	if err != nil {
+		diags.AddError(
+			"Failed to create catalog",
+			"Failed to create catalog: "+err.Error(),
+		)
+
+		return
+	}
+	d.catalog = catalog
+}
+
+func (d *icebergTablesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = withResourceType(ctx, "data.iceberg_tables")
+	d.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergTablesDataSourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var namespaceName []string
+	diags = data.Namespace.ElementsAs(ctx, &namespaceName, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespaceIdent := catalog.ToIdentifier(namespaceName...)
+
+	tables, err := listTableIdentifiers(ctx, d.catalog, namespaceIdent)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to list tables", err.Error())
+
+		return
+	}
+
+	data.ID = types.StringValue(encodeIdentifier(namespaceIdent))
+	data.Tables, diags = tablesListValue(ctx, tables)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+// listTableIdentifiers returns the identifiers of all tables in the namespace,
+// sorted by their encoded form so the result is stable between reads.
+func listTableIdentifiers(ctx context.Context, cat catalog.Catalog, namespaceIdent table.Identifier) ([]table.Identifier, error) {
+	tables := make([]table.Identifier, 0)
+	for ident, err := range cat.ListTables(ctx, namespaceIdent) {
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, ident)
+	}
+
+	sort.Slice(tables, func(i, j int) bool {
+		return encodeIdentifier(tables[i]) < encodeIdentifier(tables[j])
+	})
+
+	return tables, nil
+}
+
+func tablesListValue(ctx context.Context, tables []table.Identifier) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	elements := make([]attr.Value, 0, len(tables))
+	for _, ident := range tables {
+		namespace, d := types.ListValueFrom(ctx, types.StringType, catalog.NamespaceFromIdent(ident))
+		diags.Append(d...)
+
+		element, d := types.ObjectValue(icebergTablesDataSourceTableType.AttrTypes, map[string]attr.Value{
+			"id":        types.StringValue(encodeIdentifier(ident)),
+			"namespace": namespace,
+			"name":      types.StringValue(catalog.TableNameFromIdent(ident)),
+		})
+		diags.Append(d...)
+		elements = append(elements, element)
+	}
+	if diags.HasError() {
+		return types.ListNull(icebergTablesDataSourceTableType), diags
+	}
+
+	list, d := types.ListValue(icebergTablesDataSourceTableType, elements)
+	diags.Append(d...)
+
+	return list, diags
+}
@@ -0,0 +1,140 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListTableIdentifiers(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "orders", nil)
+	m.addTable("db", "events", nil)
+	m.addTable("other", "ignored", nil)
+	server := m.start(t)
+
+	d := &icebergTablesDataSource{
+		provider: &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()},
+	}
+	var diags diag.Diagnostics
+	d.ConfigureCatalog(ctx, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	tables, err := listTableIdentifiers(ctx, d.catalog, table.Identifier{"db"})
+	require.NoError(t, err)
+	assert.Equal(t, []table.Identifier{{"db", "events"}, {"db", "orders"}}, tables)
+
+	list, diags := tablesListValue(ctx, tables)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	var got []struct {
+		ID        string   `tfsdk:"id"`
+		Namespace []string `tfsdk:"namespace"`
+		Name      string   `tfsdk:"name"`
+	}
+	require.False(t, list.ElementsAs(ctx, &got, false).HasError())
+	require.Len(t, got, 2)
+	assert.Equal(t, "db.events", got[0].ID)
+	assert.Equal(t, []string{"db"}, got[0].Namespace)
+	assert.Equal(t, "events", got[0].Name)
+}
+
+// TestAccIcebergTablesDataSource_ImportBlocks adopts every table of an
+// existing namespace with import blocks generated from data.iceberg_tables.
+func TestAccIcebergTablesDataSource_ImportBlocks(t *testing.T) {
+	m := newMockRESTCatalog()
+	for i := range 20 {
+		m.addTable("db", fmt.Sprintf("table_%02d", i), nil)
+	}
+	server := m.start(t)
+
+	providerCfg := fmt.Sprintf(providerConfig, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// for_each in import blocks
+			tfversion.SkipBelow(tfversion.Version1_7_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIcebergTablesImportConfig(providerCfg),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.iceberg_tables.db", "tables.#", "20"),
+					resource.TestCheckResourceAttr("iceberg_table.adopted[\"db.table_00\"]", "name", "table_00"),
+					resource.TestCheckResourceAttr("iceberg_table.adopted[\"db.table_19\"]", "namespace.0", "db"),
+					func(_ *terraform.State) error {
+						m.mu.Lock()
+						defer m.mu.Unlock()
+
+						if len(m.writes) > 0 {
+							return fmt.Errorf("expected import to adopt the tables without writes, got %v", m.writes)
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testAccIcebergTablesImportConfig(providerCfg string) string {
+	return providerCfg + `
+data "iceberg_tables" "db" {
+  namespace = ["db"]
+}
+
+locals {
+  tables = { for t in data.iceberg_tables.db.tables : t.id => t }
+}
+
+import {
+  for_each = local.tables
+  to       = iceberg_table.adopted[each.key]
+  id       = each.key
+}
+
+resource "iceberg_table" "adopted" {
+  for_each = local.tables
+
+  namespace = each.value.namespace
+  name      = each.value.name
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`
+}
@@ -0,0 +1,153 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// driftReport collects human-readable descriptions of changes made to a managed
+// object outside of Terraform. It is filled during Read by comparing the prior
+// state with what the catalog returned and is surfaced as a single warning.
+type driftReport struct {
+	subject string
+	changes []string
+}
+
+func newDriftReport(subject string) *driftReport {
+	return &driftReport{subject: subject}
+}
+
+// compareProperties records drift for the given user-managed property keys.
+// Only keys present in prior are considered; keys the user never managed are
+// expected to change on the server and are not reported.
+func (d *driftReport) compareProperties(prior, current map[string]string) {
+	keys := make([]string, 0, len(prior))
+	for k := range prior {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		oldV := prior[k]
+		newV, ok := current[k]
+		switch {
+		case !ok:
+			d.changes = append(d.changes, fmt.Sprintf("property %s removed (was %q)", k, oldV))
+		case !properties.ValuesEqual(oldV, newV):
+			d.changes = append(d.changes, fmt.Sprintf("property %s changed %q → %q", k, oldV, newV))
+		}
+	}
+}
+
+// compareSensitiveProperties is compareProperties for properties whose values
+// must not be shown: only the changed keys are reported.
+func (d *driftReport) compareSensitiveProperties(prior, current map[string]string) {
+	keys := make([]string, 0, len(prior))
+	for k := range prior {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		newV, ok := current[k]
+		switch {
+		case !ok:
+			d.changes = append(d.changes, fmt.Sprintf("sensitive property %s removed", k))
+		case !properties.ValuesEqual(prior[k], newV):
+			d.changes = append(d.changes, fmt.Sprintf("sensitive property %s changed", k))
+		}
+	}
+}
+
+// compareIdentity records that the object was dropped and recreated when its
+// server-assigned identifier changed. An unknown prior identifier is ignored.
+func (d *driftReport) compareIdentity(prior, current string) {
+	if prior != "" && prior != current {
+		d.changes = append(d.changes, fmt.Sprintf("recreated outside of Terraform (UUID %s → %s)", prior, current))
+	}
+}
+
+// compareSchemaFields records added, removed and modified columns. Fields are
+// matched by their dotted path so nested struct fields are reported individually.
+func (d *driftReport) compareSchemaFields(prior, current []icebergTableSchemaField) {
+	priorFields := make(map[string]icebergTableSchemaField)
+	flattenSchemaFields("", prior, priorFields)
+	currentFields := make(map[string]icebergTableSchemaField)
+	flattenSchemaFields("", current, currentFields)
+
+	names := make([]string, 0, len(priorFields)+len(currentFields))
+	for name := range priorFields {
+		names = append(names, name)
+	}
+	for name := range currentFields {
+		if _, ok := priorFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		oldF, hadOld := priorFields[name]
+		newF, hasNew := currentFields[name]
+		switch {
+		case !hadOld:
+			d.changes = append(d.changes, "column added: "+name)
+		case !hasNew:
+			d.changes = append(d.changes, "column removed: "+name)
+		default:
+			if oldF.Type != newF.Type {
+				d.changes = append(d.changes, fmt.Sprintf("column %s type changed %s → %s", name, oldF.Type, newF.Type))
+			}
+			if oldF.Required != newF.Required {
+				d.changes = append(d.changes, fmt.Sprintf("column %s required changed %t → %t", name, oldF.Required, newF.Required))
+			}
+			if !fieldDocsEqual(oldF.Doc, newF.Doc) {
+				d.changes = append(d.changes, fmt.Sprintf("column %s doc changed", name))
+			}
+		}
+	}
+}
+
+func flattenSchemaFields(prefix string, fields []icebergTableSchemaField, out map[string]icebergTableSchemaField) {
+	for _, f := range fields {
+		name := f.Name
+		if prefix != "" {
+			name = prefix + "." + f.Name
+		}
+		out[name] = f
+		if f.StructProperties != nil {
+			flattenSchemaFields(name, f.StructProperties.Fields, out)
+		}
+	}
+}
+
+// addTo appends a warning summarizing the drift, if any was found.
+func (d *driftReport) addTo(diags *diag.Diagnostics) {
+	if len(d.changes) == 0 {
+		return
+	}
+
+	diags.AddWarning(
+		"Drift detected",
+		fmt.Sprintf("%s changed outside of Terraform:\n  - %s", d.subject, strings.Join(d.changes, "\n  - ")),
+	)
+}
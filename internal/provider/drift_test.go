@@ -0,0 +1,92 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriftReportProperties(t *testing.T) {
+	report := newDriftReport("Table db.t")
+	report.compareProperties(
+		map[string]string{"owner": "a", "tier": "gold", "same": "x"},
+		map[string]string{"owner": "b", "same": "x", "server-only": "y"},
+	)
+
+	assert.Equal(t, []string{
+		`property owner changed "a" → "b"`,
+		`property tier removed (was "gold")`,
+	}, report.changes)
+}
+
+func TestDriftReportSchemaFields(t *testing.T) {
+	doc := func(s string) *string { return &s }
+	prior := []icebergTableSchemaField{
+		{Name: "id", Type: "long", Required: true, Doc: doc("{\"source\": \"orders\"}")},
+		{Name: "gone", Type: "string"},
+		{Name: "addr", Type: "struct", Doc: doc(""), StructProperties: &icebergTableSchemaFieldStructProperties{
+			Fields: []icebergTableSchemaField{{Name: "zip", Type: "int"}},
+		}},
+	}
+	current := []icebergTableSchemaField{
+		{Name: "id", Type: "long", Required: false, Doc: doc("{\"source\": \"payments\"}")},
+		{Name: "y", Type: "string"},
+		{Name: "addr", Type: "struct", StructProperties: &icebergTableSchemaFieldStructProperties{
+			Fields: []icebergTableSchemaField{{Name: "zip", Type: "long"}},
+		}},
+	}
+
+	report := newDriftReport("Table db.t")
+	report.compareSchemaFields(prior, current)
+
+	assert.Equal(t, []string{
+		"column addr.zip type changed int → long",
+		"column removed: gone",
+		"column id required changed true → false",
+		"column id doc changed",
+		"column added: y",
+	}, report.changes)
+}
+
+func TestDriftReportIdentity(t *testing.T) {
+	report := newDriftReport("Table db.t")
+	report.compareIdentity("", "b")
+	report.compareIdentity("a", "a")
+	assert.Empty(t, report.changes)
+
+	report.compareIdentity("a", "b")
+	assert.Equal(t, []string{"recreated outside of Terraform (UUID a → b)"}, report.changes)
+}
+
+func TestDriftReportAddTo(t *testing.T) {
+	var diags diag.Diagnostics
+
+	newDriftReport("Namespace db").addTo(&diags)
+	assert.Empty(t, diags)
+
+	report := newDriftReport("Namespace db")
+	report.compareProperties(map[string]string{"owner": "a"}, map[string]string{"owner": "b"})
+	report.addTo(&diags)
+
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.SeverityWarning, diags[0].Severity())
+	assert.Contains(t, diags[0].Detail(), `Namespace db changed outside of Terraform`)
+	assert.Contains(t, diags[0].Detail(), `property owner changed "a" → "b"`)
+}
@@ -0,0 +1,124 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Some catalogs return properties objects with a key given more than once.
+// The JSON decoders of the catalog clients keep the last value without a
+// word, so the value Terraform sees depends on how the server happened to
+// write the object. The duplicate keys are logged as a warning so that such
+// flip-flopping values can be traced back to the catalog.
+
+// warnDuplicateProperties logs the keys given more than once in a properties
+// object of a successful JSON response.
+func warnDuplicateProperties(req *http.Request, resp *http.Response) {
+	if resp.Body == nil || req.Method == http.MethodHead || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	keys := duplicatePropertyKeys(body)
+	if len(keys) == 0 {
+		return
+	}
+	tflog.Warn(req.Context(), "The catalog returned duplicate property keys, the last value of each is used", map[string]any{
+		"url":  req.URL.Redacted(),
+		"keys": strings.Join(keys, ", "),
+	})
+}
+
+// duplicatePropertyKeys returns the keys given more than once in a
+// "properties" object of the JSON document body, sorted. A body that isn't
+// valid JSON is left for the decoder to report.
+func duplicatePropertyKeys(body []byte) []string {
+	if !bytes.Contains(body, []byte(`"properties"`)) {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	duplicates := make(map[string]struct{})
+	if err := scanDuplicatePropertyKeys(dec, false, duplicates); err != nil || len(duplicates) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(duplicates))
+	for k := range duplicates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// scanDuplicatePropertyKeys reads the next value of dec, adding the keys it
+// repeats to duplicates if it is a properties object.
+func scanDuplicatePropertyKeys(dec *json.Decoder, properties bool, duplicates map[string]struct{}) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		seen := make(map[string]struct{})
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := tok.(string)
+			if properties {
+				if _, ok := seen[key]; ok {
+					duplicates[key] = struct{}{}
+				}
+				seen[key] = struct{}{}
+			}
+			if err := scanDuplicatePropertyKeys(dec, key == "properties", duplicates); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for dec.More() {
+			if err := scanDuplicatePropertyKeys(dec, false, duplicates); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+
+	// The closing delimiter.
+	_, err = dec.Token()
+
+	return err
+}
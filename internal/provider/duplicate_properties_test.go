@@ -0,0 +1,114 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplicatePropertyKeys(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "namespace_duplicate_properties.json"))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "namespace",
+			body: string(fixture),
+			want: []string{"comment", "owner"},
+		},
+		{
+			name: "table",
+			body: `{"metadata-location": "s3://bucket/t/v1.json", "metadata": {"format-version": 2, "schemas": [{"fields": []}], "properties": {"write.format.default": "parquet", "write.format.default": "orc"}}}`,
+			want: []string{"write.format.default"},
+		},
+		{
+			name: "key repeated in another properties object",
+			body: `{"principal": {"properties": {"team": "a"}}, "grant": {"properties": {"team": "b"}}}`,
+		},
+		{
+			name: "key repeated outside of properties",
+			body: `{"namespace": ["db"], "properties": {"owner": "alice"}, "owner": "a", "owner": "b"}`,
+		},
+		{
+			name: "no properties",
+			body: `{"namespaces": [["db"]], "next-page-token": null}`,
+		},
+		{
+			name: "invalid json",
+			body: `{"properties": {"owner": "a", "owner": "b"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, duplicatePropertyKeys([]byte(tc.body)))
+		})
+	}
+}
+
+func TestDuplicatePropertiesFromCatalog(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "namespace_duplicate_properties.json"))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/config":
+			_, _ = io.WriteString(w, `{"defaults": {}, "overrides": {}}`)
+		case "/v1/namespaces/db":
+			_, _ = w.Write(fixture)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	p := &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}
+	cat, err := p.NewCatalog(context.Background(), "iceberg_namespace")
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	ctx, _ := withNullProperties(tflogtest.RootLogger(context.Background(), &logs))
+	for range 5 {
+		props, err := cat.LoadNamespaceProperties(ctx, []string{"db"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"owner": "bob", "location": "s3://bucket/db", "comment": "Sales data"}, map[string]string(props), "the last value wins")
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&logs)
+	require.NoError(t, err)
+	var warnings []map[string]any
+	for _, entry := range entries {
+		if entry["@level"] == "warn" {
+			warnings = append(warnings, entry)
+		}
+	}
+	require.NotEmpty(t, warnings)
+	assert.Equal(t, "comment, owner", warnings[0]["keys"])
+}
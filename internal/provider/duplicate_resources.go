@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// resourceClaims records the catalog objects the resources of a plan or an
+// apply manage, so that two resources managing the same object are reported.
+// Terraform configures the provider once per graph walk and plans every
+// resource instance once per walk, so an object claimed twice between two
+// Configure calls is claimed by two resources. Resources being destroyed
+// claim nothing, which lets a replaced resource, or one taking the name
+// another resource gives up, plan without a report.
+//
+// A resource instance that is replaced is planned twice in a walk: once
+// against its prior state and once more as a create. Each claim therefore
+// carries a token of the resource instance, kept in its private state, which
+// Terraform hands from the first plan to the second, and a resource claiming
+// an object again with the same token isn't reported.
+type resourceClaims struct {
+	mu      sync.Mutex
+	claimed map[string]string
+}
+
+func newResourceClaims() *resourceClaims {
+	return &resourceClaims{claimed: make(map[string]string)}
+}
+
+// claim records that the resource instance identified by token, of
+// resourceType, manages ident, and reports whether another resource instance
+// of the walk did already. Resources are planned concurrently, so claims are
+// safe for concurrent use.
+func (c *resourceClaims) claim(resourceType string, ident []string, token string) bool {
+	if c == nil {
+		return false
+	}
+	key := resourceType + " " + encodeIdentifier(ident)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if owner, ok := c.claimed[key]; ok {
+		return owner != token
+	}
+	c.claimed[key] = token
+
+	return false
+}
+
+// claimToken returns the token the resource instance planned with req claims
+// objects with, and keeps it in the private state of the plan. A resource
+// instance without a token, e.g. one being created, gets a new one.
+func claimToken(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) string {
+	token, ok := getPrivateValue[string](ctx, req.Private, privateKeyClaimToken, &resp.Diagnostics)
+	if !ok {
+		token = uuid.NewString()
+	}
+	setPrivateValue(ctx, resp.Private, privateKeyClaimToken, token, &resp.Diagnostics)
+
+	return token
+}
+
+// claimTable records that the iceberg_table resource instance identified by
+// token manages the table ident. A table claimed by another resource instance
+// is reported as a warning, or as an error with forbid_duplicate_tables.
+func (p *icebergProvider) claimTable(ident []string, token string, diags *diag.Diagnostics) {
+	if !p.resourceClaims.claim("iceberg_table", ident, token) {
+		return
+	}
+
+	summary := "Table managed by several resources"
+	detail := fmt.Sprintf("Another iceberg_table resource of this configuration also manages the table %s. The resources undo each other's changes on every apply; remove all but one of them.", encodeIdentifier(ident))
+	if p.forbidDuplicateTables {
+		diags.AddError(summary, detail)
+
+		return
+	}
+	diags.AddWarning(summary, detail+" Set forbid_duplicate_tables in the provider configuration to make this an error.")
+}
@@ -0,0 +1,153 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceClaims(t *testing.T) {
+	claims := newResourceClaims()
+	assert.False(t, claims.claim("iceberg_table", []string{"db", "events"}, "a"))
+	assert.True(t, claims.claim("iceberg_table", []string{"db", "events"}, "b"))
+	assert.False(t, claims.claim("iceberg_table", []string{"db", "events"}, "a"), "a resource planned again claims its table again")
+	assert.False(t, claims.claim("iceberg_table", []string{"db", "orders"}, "b"))
+	assert.False(t, claims.claim("iceberg_table", []string{"db.events"}, "b"), "identifiers are compared by level")
+	assert.False(t, claims.claim("iceberg_namespace", []string{"db", "events"}, "b"))
+
+	var nilClaims *resourceClaims
+	assert.False(t, nilClaims.claim("iceberg_table", []string{"db", "events"}, "a"))
+
+	t.Run("concurrent claims", func(t *testing.T) {
+		claims := newResourceClaims()
+		var duplicates atomic.Int64
+		var wg sync.WaitGroup
+		for range 32 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if claims.claim("iceberg_table", []string{"db", "events"}, uuid.NewString()) {
+					duplicates.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int64(31), duplicates.Load(), "exactly one claim succeeds")
+	})
+}
+
+func TestClaimTable(t *testing.T) {
+	p := &icebergProvider{resourceClaims: newResourceClaims()}
+
+	var diags diag.Diagnostics
+	p.claimTable([]string{"db", "events"}, "a", &diags)
+	require.Empty(t, diags)
+	p.claimTable([]string{"db", "events"}, "a", &diags)
+	require.Empty(t, diags)
+
+	p.claimTable([]string{"db", "events"}, "b", &diags)
+	require.Len(t, diags.Warnings(), 1)
+	assert.Equal(t, "Table managed by several resources", diags.Warnings()[0].Summary())
+	assert.Contains(t, diags.Warnings()[0].Detail(), "also manages the table db.events.")
+
+	// Configure starts over for every walk.
+	p.resourceClaims = newResourceClaims()
+	p.forbidDuplicateTables = true
+	diags = nil
+	p.claimTable([]string{"db", "events"}, "a", &diags)
+	require.Empty(t, diags)
+	p.claimTable([]string{"db", "events"}, "b", &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Table managed by several resources", diags.Errors()[0].Summary())
+}
+
+func TestAccIcebergTable_DuplicateIdentifier(t *testing.T) {
+	server := newMockRESTCatalog().start(t)
+
+	config := func(firstName, secondName string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri             = "%s"
+  forbid_duplicate_tables = true
+}
+
+resource "iceberg_namespace" "db" {
+  name = ["db"]
+}
+
+resource "iceberg_table" "events" {
+  namespace = iceberg_namespace.db.name
+  name      = %q
+  schema = {
+    fields = [
+      { name = "id", type = "long", required = true },
+    ]
+  }
+}
+
+resource "iceberg_table" "copy" {
+  namespace = iceberg_namespace.db.name
+  name      = %q
+  schema = {
+    fields = [
+      { name = "id", type = "long", required = true },
+    ]
+  }
+}
+`, server.URL, firstName, secondName)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("events", "events"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Table managed by several resources`),
+			},
+			{
+				Config: config("events", "orders"),
+			},
+			{
+				// A tainted table is planned once, as a create.
+				Config: config("events", "orders"),
+				Taint:  []string{"iceberg_table.events"},
+			},
+			{
+				// A renamed table is planned twice, against its prior state
+				// and as a create, and claims its new name once.
+				Config: config("clicks", "orders"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("iceberg_table.events", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,285 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	rscschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// engineHintKind is the Terraform type of an engine hint attribute.
+type engineHintKind int
+
+const (
+	engineHintBool engineHintKind = iota
+	engineHintInt64
+)
+
+// engineHint is an attribute of the engine_hints attribute of a table. It
+// sets each of properties to its value.
+type engineHint struct {
+	attribute   string
+	properties  []string
+	kind        engineHintKind
+	description string
+	// minValue is the lowest value of an engineHintInt64 hint.
+	minValue int64
+}
+
+// engineHints lists the attributes of engine_hints. Add an entry here to
+// support a new hint.
+var engineHints = []engineHint{
+	{
+		attribute:   "spark_fanout_enabled",
+		properties:  []string{"write.spark.fanout.enabled"},
+		kind:        engineHintBool,
+		description: "If true, Spark writes with fanout writers, which keep a file open for every partition so the data needn't be sorted by partition before it is written.",
+	},
+	{
+		attribute:   "spark_accept_any_schema",
+		properties:  []string{"write.spark.accept-any-schema"},
+		kind:        engineHintBool,
+		description: "If true, Spark accepts writes of data frames whose schema differs from the table schema, as needed to merge schemas on write.",
+	},
+	{
+		attribute:   "trino_max_partitions_per_writer",
+		properties:  []string{"trino.max-partitions-per-writer"},
+		kind:        engineHintInt64,
+		description: "The most partitions a Trino writer opens at once.",
+		minValue:    1,
+	},
+	{
+		attribute:   "flink_sink_parallelism",
+		properties:  []string{"write-parallelism", "sink.parallelism"},
+		kind:        engineHintInt64,
+		description: "The parallelism of the Flink sink writing to the table.",
+		minValue:    1,
+	},
+	{
+		attribute:   "flink_max_continuous_empty_commits",
+		properties:  []string{"flink.max-continuous-empty-commits"},
+		kind:        engineHintInt64,
+		description: "How many checkpoints in a row without new data the Flink sink skips before it commits an empty snapshot.",
+		minValue:    1,
+	},
+}
+
+// engineHintsAttribute returns the schema of the engine_hints attribute.
+func engineHintsAttribute() rscschema.SingleNestedAttribute {
+	attrs := make(map[string]rscschema.Attribute, len(engineHints))
+	for _, h := range engineHints {
+		description := h.description + " Sets the " + h.properties[0] + " property."
+		if len(h.properties) > 1 {
+			description = h.description + " Sets the " + strings.Join(h.properties, " and ") + " properties."
+		}
+		switch h.kind {
+		case engineHintBool:
+			attrs[h.attribute] = rscschema.BoolAttribute{Description: description, Optional: true}
+		case engineHintInt64:
+			attrs[h.attribute] = rscschema.Int64Attribute{
+				Description: description,
+				Optional:    true,
+				Validators:  []validator.Int64{int64validator.AtLeast(h.minValue)},
+			}
+		}
+	}
+
+	return rscschema.SingleNestedAttribute{
+		Description: "Settings of query engines that are stored in table properties, typed and validated at plan time. Each hint sets the properties named in its description, which then can't be set in user_properties. Properties of unset hints written by other clients are left alone.",
+		Optional:    true,
+		Attributes:  attrs,
+	}
+}
+
+// engineHintsAttributeTypes returns the attribute types of engine_hints.
+func engineHintsAttributeTypes() map[string]attr.Type {
+	attrTypes := make(map[string]attr.Type, len(engineHints))
+	for _, h := range engineHints {
+		attrTypes[h.attribute] = h.attrType()
+	}
+
+	return attrTypes
+}
+
+func (h engineHint) attrType() attr.Type {
+	if h.kind == engineHintInt64 {
+		return types.Int64Type
+	}
+
+	return types.BoolType
+}
+
+// format returns the property value of v, false if v is null or unknown.
+func (h engineHint) format(v attr.Value) (string, bool) {
+	if v == nil || v.IsNull() || v.IsUnknown() {
+		return "", false
+	}
+	switch v := v.(type) {
+	case types.Bool:
+		return strconv.FormatBool(v.ValueBool()), true
+	case types.Int64:
+		return strconv.FormatInt(v.ValueInt64(), 10), true
+	}
+
+	return "", false
+}
+
+// parse returns the value of the hint for a property value, false if it
+// isn't a valid value of the hint's type.
+func (h engineHint) parse(s string) (attr.Value, bool) {
+	switch h.kind {
+	case engineHintBool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, false
+		}
+
+		return types.BoolValue(b), true
+	case engineHintInt64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < h.minValue {
+			return nil, false
+		}
+
+		return types.Int64Value(n), true
+	}
+
+	return nil, false
+}
+
+// compileEngineHints returns the properties the set attributes of hints
+// compile to.
+func compileEngineHints(hints types.Object) map[string]string {
+	props := make(map[string]string)
+	if hints.IsNull() || hints.IsUnknown() {
+		return props
+	}
+
+	values := hints.Attributes()
+	for _, h := range engineHints {
+		value, ok := h.format(values[h.attribute])
+		if !ok {
+			continue
+		}
+		for _, key := range h.properties {
+			props[key] = value
+		}
+	}
+
+	return props
+}
+
+// withEngineHints returns a copy of props with the properties of hints set.
+// props is returned as is if no hint is set.
+func withEngineHints(props map[string]string, hints types.Object) map[string]string {
+	compiled := compileEngineHints(hints)
+	if len(compiled) == 0 {
+		return props
+	}
+
+	result := maps.Clone(props)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	maps.Copy(result, compiled)
+
+	return result
+}
+
+// syncEngineHints returns the hints of the properties of the server as the
+// new value of the engine_hints attribute. A hint whose properties are
+// missing, don't all hold the same value or hold one that isn't valid is
+// null so the plan sets them again. Hints that aren't managed by Terraform
+// stay null.
+func syncEngineHints(hints types.Object, server map[string]string) types.Object {
+	if hints.IsNull() || hints.IsUnknown() {
+		return hints
+	}
+
+	prior := hints.Attributes()
+	values := make(map[string]attr.Value, len(engineHints))
+	for _, h := range engineHints {
+		values[h.attribute] = syncEngineHint(h, prior[h.attribute], server)
+	}
+
+	return types.ObjectValueMust(engineHintsAttributeTypes(), values)
+}
+
+// syncEngineHint returns the value of h in the properties of the server,
+// null if prior, its value in state, is null.
+func syncEngineHint(h engineHint, prior attr.Value, server map[string]string) attr.Value {
+	if prior == nil || prior.IsNull() {
+		return h.null()
+	}
+
+	value, ok := server[h.properties[0]]
+	if !ok {
+		return h.null()
+	}
+	for _, key := range h.properties[1:] {
+		if server[key] != value {
+			return h.null()
+		}
+	}
+	parsed, ok := h.parse(value)
+	if !ok {
+		return h.null()
+	}
+
+	return parsed
+}
+
+func (h engineHint) null() attr.Value {
+	if h.kind == engineHintInt64 {
+		return types.Int64Null()
+	}
+
+	return types.BoolNull()
+}
+
+// validateEngineHintsConfig rejects configurations that set a property with
+// a hint of engine_hints and in user_properties.
+func validateEngineHintsConfig(hints types.Object, userProperties types.Map, diags *diag.Diagnostics) {
+	if hints.IsNull() || hints.IsUnknown() || userProperties.IsNull() || userProperties.IsUnknown() {
+		return
+	}
+
+	values := hints.Attributes()
+	for _, h := range engineHints {
+		if v := values[h.attribute]; v == nil || v.IsNull() {
+			continue
+		}
+		for _, key := range h.properties {
+			if _, ok := userProperties.Elements()[key]; ok {
+				diags.AddAttributeError(
+					path.Root("engine_hints").AtName(h.attribute),
+					"Conflicting engine_hints configuration",
+					fmt.Sprintf("engine_hints.%s sets the %s property, which user_properties sets as well. Remove %[2]s from user_properties.", h.attribute, key),
+				)
+			}
+		}
+	}
+}
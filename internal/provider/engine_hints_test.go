@@ -0,0 +1,206 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEngineHints returns an engine_hints value with the given attributes
+// set and all others null.
+func testEngineHints(t *testing.T, set map[string]attr.Value) types.Object {
+	t.Helper()
+
+	values := make(map[string]attr.Value, len(engineHints))
+	for _, h := range engineHints {
+		values[h.attribute] = h.null()
+	}
+	for name, value := range set {
+		require.Contains(t, values, name)
+		values[name] = value
+	}
+
+	return types.ObjectValueMust(engineHintsAttributeTypes(), values)
+}
+
+func TestEngineHintsSchema(t *testing.T) {
+	attribute := engineHintsAttribute()
+	require.Len(t, attribute.Attributes, len(engineHints))
+
+	properties := make(map[string]string)
+	for _, h := range engineHints {
+		assert.Contains(t, attribute.Attributes, h.attribute)
+		assert.Equal(t, h.attrType(), attribute.Attributes[h.attribute].GetType())
+		require.NotEmpty(t, h.properties, h.attribute)
+		for _, key := range h.properties {
+			other, ok := properties[key]
+			assert.False(t, ok, "%s is set by %s and %s", key, h.attribute, other)
+			properties[key] = h.attribute
+		}
+	}
+}
+
+func TestCompileEngineHints(t *testing.T) {
+	assert.Empty(t, compileEngineHints(types.ObjectNull(engineHintsAttributeTypes())))
+	assert.Empty(t, compileEngineHints(types.ObjectUnknown(engineHintsAttributeTypes())))
+	assert.Empty(t, compileEngineHints(testEngineHints(t, nil)))
+
+	hints := testEngineHints(t, map[string]attr.Value{
+		"spark_fanout_enabled":            types.BoolValue(true),
+		"trino_max_partitions_per_writer": types.Int64Value(200),
+		"flink_sink_parallelism":          types.Int64Value(4),
+		"spark_accept_any_schema":         types.BoolUnknown(),
+	})
+	assert.Equal(t, map[string]string{
+		"write.spark.fanout.enabled":      "true",
+		"trino.max-partitions-per-writer": "200",
+		"write-parallelism":               "4",
+		"sink.parallelism":                "4",
+	}, compileEngineHints(hints))
+
+	props := map[string]string{"owner": "data"}
+	assert.Equal(t, props, withEngineHints(props, types.ObjectNull(engineHintsAttributeTypes())))
+	assert.Equal(t, map[string]string{
+		"owner":                           "data",
+		"write.spark.fanout.enabled":      "true",
+		"trino.max-partitions-per-writer": "200",
+		"write-parallelism":               "4",
+		"sink.parallelism":                "4",
+	}, withEngineHints(props, hints))
+	assert.Equal(t, map[string]string{"owner": "data"}, props, "the input must not be modified")
+}
+
+func TestSyncEngineHints(t *testing.T) {
+	hints := testEngineHints(t, map[string]attr.Value{
+		"spark_fanout_enabled":               types.BoolValue(false),
+		"flink_sink_parallelism":             types.Int64Value(8),
+		"flink_max_continuous_empty_commits": types.Int64Value(10),
+	})
+
+	// Compiled properties read back as the same hints.
+	assert.Equal(t, hints, syncEngineHints(hints, compileEngineHints(hints)))
+
+	unmanaged := types.ObjectNull(engineHintsAttributeTypes())
+	assert.Equal(t, unmanaged, syncEngineHints(unmanaged, compileEngineHints(hints)), "unmanaged hints stay null")
+
+	server := map[string]string{
+		"write.spark.fanout.enabled":         "TRUE",
+		"write-parallelism":                  "8",
+		"sink.parallelism":                   "16",
+		"flink.max-continuous-empty-commits": "many",
+		"trino.max-partitions-per-writer":    "100",
+	}
+	assert.Equal(t, testEngineHints(t, map[string]attr.Value{
+		"spark_fanout_enabled": types.BoolValue(true),
+	}), syncEngineHints(hints, server), "mixed and invalid values are planned to be set again, unset hints stay null")
+}
+
+func TestValidateEngineHintsConfig(t *testing.T) {
+	hints := testEngineHints(t, map[string]attr.Value{"flink_sink_parallelism": types.Int64Value(4)})
+	withKey := types.MapValueMust(types.StringType, map[string]attr.Value{"sink.parallelism": types.StringValue("2")})
+	withoutKey := types.MapValueMust(types.StringType, map[string]attr.Value{"write.spark.fanout.enabled": types.StringValue("true")})
+
+	var diags diag.Diagnostics
+	validateEngineHintsConfig(types.ObjectNull(engineHintsAttributeTypes()), withKey, &diags)
+	validateEngineHintsConfig(hints, withoutKey, &diags)
+	validateEngineHintsConfig(hints, types.MapUnknown(types.StringType), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	validateEngineHintsConfig(hints, withKey, &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Conflicting engine_hints configuration", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), "engine_hints.flink_sink_parallelism sets the sink.parallelism property")
+}
+
+func TestAccIcebergTable_EngineHints(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := func(attributes string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "orders" {
+  namespace = ["db"]
+  name      = "orders"
+  %s
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL, attributes)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`engine_hints = { flink_sink_parallelism = 0 }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be at least 1`),
+			},
+			{
+				Config: config(`engine_hints = { spark_fanout_enabled = true }
+  user_properties = {
+    "write.spark.fanout.enabled" = "false"
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Conflicting engine_hints configuration`),
+			},
+			{
+				Config: config(`engine_hints = {
+    spark_fanout_enabled   = true
+    flink_sink_parallelism = 4
+  }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.orders", "engine_hints.spark_fanout_enabled", "true"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "engine_hints.flink_sink_parallelism", "4"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.write.spark.fanout.enabled", "true"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.sink.parallelism", "4"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.write-parallelism", "4"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "server_managed_properties.sink.parallelism"),
+				),
+			},
+			{
+				Config: config(`engine_hints = { spark_fanout_enabled = false }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.write.spark.fanout.enabled", "false"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "server_properties.sink.parallelism"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,117 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The environment variables provider attributes default to. A variable that
+// is empty counts as unset.
+const (
+	catalogURIEnvVar           = "ICEBERG_CATALOG_URI"
+	tokenEnvVar                = "ICEBERG_TOKEN"
+	oauth2ClientIDEnvVar       = "ICEBERG_OAUTH2_CLIENT_ID"
+	oauth2ClientSecretEnvVar   = "ICEBERG_OAUTH2_CLIENT_SECRET"
+	oauth2ServerURIEnvVar      = "ICEBERG_OAUTH2_SERVER_URI"
+	warehouseEnvVar            = "ICEBERG_WAREHOUSE"
+	polarisManagementURIEnvVar = "ICEBERG_POLARIS_MANAGEMENT_URI"
+	headersEnvVar              = "ICEBERG_HEADERS"
+)
+
+// envStringAttribute is a string attribute that defaults to an environment
+// variable.
+type envStringAttribute struct {
+	envVar string
+	value  *types.String
+}
+
+// withEnvironmentDefaults sets the null attributes of data that apply to its
+// type of catalog from their environment variables, so the configuration
+// takes precedence over the environment. Unknown attributes stay unknown
+// until they are known at apply. token isn't set here, see Configure: it only
+// defaults to ICEBERG_TOKEN if the configuration doesn't authenticate
+// otherwise.
+func withEnvironmentDefaults(data *icebergProviderModel, diags *diag.Diagnostics) {
+	var attributes []envStringAttribute
+	switch data.Type.ValueString() {
+	case "glue":
+		return
+	case "sql", "memory":
+		attributes = []envStringAttribute{{warehouseEnvVar, &data.Warehouse}}
+	default:
+		attributes = []envStringAttribute{
+			{catalogURIEnvVar, &data.CatalogURI},
+			{warehouseEnvVar, &data.Warehouse},
+		}
+		// OAuth2 client credentials from the environment would conflict
+		// with a token, credential, basic authentication or an auth header
+		// in the configuration.
+		if data.Token.IsNull() && data.Credential.IsNull() && data.BasicAuthUsername.IsNull() && data.AuthHeaderName.IsNull() {
+			attributes = append(attributes,
+				envStringAttribute{oauth2ClientIDEnvVar, &data.OAuth2ClientID},
+				envStringAttribute{oauth2ClientSecretEnvVar, &data.OAuth2ClientSecret},
+			)
+		}
+		if data.Token.IsNull() && data.AuthHeaderName.IsNull() {
+			attributes = append(attributes, envStringAttribute{oauth2ServerURIEnvVar, &data.OAuth2ServerURI})
+		}
+		if data.Type.ValueString() == "polaris" && data.PolarisSettings == nil && os.Getenv(polarisManagementURIEnvVar) != "" {
+			data.PolarisSettings = &polarisSettingsModel{ManagementURI: types.StringNull(), CatalogName: types.StringNull()}
+		}
+		if data.Type.ValueString() == "polaris" && data.PolarisSettings != nil {
+			attributes = append(attributes, envStringAttribute{polarisManagementURIEnvVar, &data.PolarisSettings.ManagementURI})
+		}
+		headersFromEnvironment(data, diags)
+	}
+
+	for _, a := range attributes {
+		if !a.value.IsNull() {
+			continue
+		}
+		if value := os.Getenv(a.envVar); value != "" {
+			*a.value = types.StringValue(value)
+		}
+	}
+}
+
+// headersFromEnvironment sets the headers of data from ICEBERG_HEADERS, a
+// JSON object of header names and values, if they are null.
+func headersFromEnvironment(data *icebergProviderModel, diags *diag.Diagnostics) {
+	value := os.Getenv(headersEnvVar)
+	if !data.Headers.IsNull() || value == "" {
+		return
+	}
+
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(value), &headers); err != nil {
+		diags.AddError("Invalid "+headersEnvVar,
+			headersEnvVar+" must be a JSON object of header names and string values, as in {\"X-Api-Key\": \"...\"}: "+err.Error()+".")
+
+		return
+	}
+
+	elements := make(map[string]attr.Value, len(headers))
+	for name, v := range headers {
+		elements[name] = types.StringValue(v)
+	}
+	data.Headers = types.MapValueMust(types.StringType, elements)
+}
@@ -0,0 +1,275 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &icebergTableIOCredentialsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &icebergTableIOCredentialsEphemeralResource{}
+)
+
+func NewTableIOCredentialsEphemeralResource() ephemeral.EphemeralResource {
+	return &icebergTableIOCredentialsEphemeralResource{}
+}
+
+type icebergTableIOCredentialsEphemeralResourceModel struct {
+	ID                 types.String `tfsdk:"id"`
+	Namespace          types.List   `tfsdk:"namespace"`
+	Name               types.String `tfsdk:"name"`
+	Config             types.Map    `tfsdk:"config"`
+	StorageCredentials types.List   `tfsdk:"storage_credentials"`
+}
+
+// storageCredentialAttrTypes are the attributes of an element of
+// storage_credentials.
+var storageCredentialAttrTypes = map[string]attr.Type{
+	"prefix": types.StringType,
+	"config": types.MapType{ElemType: types.StringType},
+}
+
+// loadTableCredentials is the part of a load-table response of the REST
+// catalog that configures the FileIO of the table. The catalog client uses it
+// to read the table and discards it, so the table is loaded here.
+type loadTableCredentials struct {
+	Config             map[string]string `json:"config"`
+	StorageCredentials []struct {
+		Prefix string            `json:"prefix"`
+		Config map[string]string `json:"config"`
+	} `json:"storage-credentials"`
+}
+
+// icebergTableIOCredentialsEphemeralResource loads a table from a REST catalog
+// and exposes the storage configuration the catalog returns with it, such as
+// vended credentials. Ephemeral resources are never written to state or plan
+// files, so the temporary credentials can be passed to other resources of an
+// apply, for example one running a loader, without being persisted.
+type icebergTableIOCredentialsEphemeralResource struct {
+	provider *icebergProvider
+}
+
+func (e *icebergTableIOCredentialsEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table_io_credentials"
+}
+
+func (e *icebergTableIOCredentialsEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Loads an Iceberg table from a REST catalog and exposes the storage configuration the catalog returns with it, " +
+			"such as the temporary credentials it vends with access_delegation = \"vended-credentials\". The values are never stored in the state " +
+			"or plan, so they can be passed to other resources of an apply. Requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"namespace": schema.ListAttribute{
+				Description: "The namespace of the table, as in the catalog: the provider's name_prefix isn't added.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the table.",
+				Required:    true,
+			},
+			"config": schema.MapAttribute{
+				Description: "The FileIO properties the catalog returned for the table, for example s3.access-key-id, s3.secret-access-key and s3.session-token.",
+				Computed:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+			},
+			"storage_credentials": schema.ListNestedAttribute{
+				Description: "The storage credentials the catalog returned for the table, each for the locations starting with its prefix. Catalogs that predate them return the credentials in config only.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"prefix": schema.StringAttribute{
+							Description: "The storage location prefix the credentials are for.",
+							Computed:    true,
+						},
+						"config": schema.MapAttribute{
+							Description: "The FileIO properties of the credentials.",
+							Computed:    true,
+							Sensitive:   true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (e *icebergTableIOCredentialsEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *icebergProvider, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	e.provider = provider
+}
+
+func (e *icebergTableIOCredentialsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	if e.provider == nil {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation",
+		)
+
+		return
+	}
+	if e.provider.catalogType != "rest" {
+		resp.Diagnostics.AddError(
+			"Unsupported catalog type",
+			"iceberg_table_io_credentials needs a REST catalog, which returns the storage configuration of the tables it loads. "+
+				"With type = \""+e.provider.catalogType+"\" the provider reads tables with its own storage settings.",
+		)
+
+		return
+	}
+
+	var data icebergTableIOCredentialsEphemeralResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var namespaceName []string
+	resp.Diagnostics.Append(data.Namespace.ElementsAs(ctx, &namespaceName, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tableIdent := table.Identifier(append(namespaceName, data.Name.ValueString()))
+
+	// The catalog prefix of the requests comes from the catalog config, which
+	// creating the catalog client fetches.
+	ctx = withResourceType(ctx, "ephemeral.iceberg_table_io_credentials")
+	if _, err := e.provider.NewCatalog(ctx, "ephemeral.iceberg_table_io_credentials"); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to create catalog",
+			"Failed to create catalog: "+err.Error(),
+		)
+
+		return
+	}
+
+	loaded, err := e.provider.loadTableCredentials(ctx, tableIdent)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to load table", err.Error())
+
+		return
+	}
+
+	data.ID = types.StringValue(encodeIdentifier(tableIdent))
+	data.setCredentials(ctx, loaded, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(loaded.Config) == 0 && len(loaded.StorageCredentials) == 0 {
+		resp.Diagnostics.AddWarning(
+			"No storage configuration returned",
+			"The catalog returned no storage configuration for the table "+encodeIdentifier(tableIdent)+". "+
+				"Catalogs vend credentials only when asked to: set access_delegation = \"vended-credentials\" in the provider configuration.",
+		)
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// loadTableCredentials loads the table ident from the REST catalog and returns
+// the storage configuration of the response. The access delegation header is
+// sent as with every table load.
+func (p *icebergProvider) loadTableCredentials(ctx context.Context, ident table.Identifier) (*loadTableCredentials, error) {
+	if len(ident) < 2 {
+		return nil, fmt.Errorf("%w: missing namespace or invalid identifier %s", catalog.ErrNoSuchTable, encodeIdentifier(ident))
+	}
+
+	status, body, err := p.catalogRequest(ctx, "ephemeral.iceberg_table_io_credentials", http.MethodGet,
+		[]string{"namespaces", strings.Join(catalog.NamespaceFromIdent(ident), "\x1F"), "tables", catalog.TableNameFromIdent(ident)}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK {
+		var errResp catalogErrorResponse
+		_ = json.Unmarshal(body, &errResp)
+		switch {
+		case status == http.StatusNotFound:
+			return nil, fmt.Errorf("%w: %s", catalog.ErrNoSuchTable, errResp.Error.Message)
+		case errResp.Error.Message != "":
+			return nil, fmt.Errorf("%s: %s", errResp.Error.Type, errResp.Error.Message)
+		default:
+			return nil, fmt.Errorf("unexpected status %d %s", status, http.StatusText(status))
+		}
+	}
+
+	var loaded loadTableCredentials
+	if err := json.Unmarshal(body, &loaded); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	return &loaded, nil
+}
+
+// setCredentials sets config and storage_credentials from loaded.
+func (data *icebergTableIOCredentialsEphemeralResourceModel) setCredentials(ctx context.Context, loaded *loadTableCredentials, diags *diag.Diagnostics) {
+	config := loaded.Config
+	if config == nil {
+		config = map[string]string{}
+	}
+	var d diag.Diagnostics
+	data.Config, d = types.MapValueFrom(ctx, types.StringType, config)
+	diags.Append(d...)
+
+	credentials := make([]attr.Value, 0, len(loaded.StorageCredentials))
+	for _, c := range loaded.StorageCredentials {
+		credConfig := c.Config
+		if credConfig == nil {
+			credConfig = map[string]string{}
+		}
+		credConfigValue, d := types.MapValueFrom(ctx, types.StringType, credConfig)
+		diags.Append(d...)
+		credential, d := types.ObjectValue(storageCredentialAttrTypes, map[string]attr.Value{
+			"prefix": types.StringValue(c.Prefix),
+			"config": credConfigValue,
+		})
+		diags.Append(d...)
+		credentials = append(credentials, credential)
+	}
+	data.StorageCredentials, d = types.ListValue(types.ObjectType{AttrTypes: storageCredentialAttrTypes}, credentials)
+	diags.Append(d...)
+}
@@ -0,0 +1,212 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTableIOCredentials opens an iceberg_table_io_credentials ephemeral
+// resource for db.events with a provider configured with providerConfig.
+func openTableIOCredentials(t *testing.T, providerConfig map[string]tftypes.Value) (*tfprotov6.OpenEphemeralResourceResponse, map[string]tftypes.Value) {
+	t.Helper()
+
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: dynamicValue(t, objectValue(schemas.Provider.ValueType(), providerConfig)),
+	})
+	require.NoError(t, err)
+	requireNoErrorDiagnostics(t, configured.Diagnostics)
+
+	resultType := schemas.EphemeralResourceSchemas["iceberg_table_io_credentials"].ValueType()
+	opened, err := server.OpenEphemeralResource(ctx, &tfprotov6.OpenEphemeralResourceRequest{
+		TypeName: "iceberg_table_io_credentials",
+		Config: dynamicValue(t, objectValue(resultType, map[string]tftypes.Value{
+			"namespace": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "db")}),
+			"name":      tftypes.NewValue(tftypes.String, "events"),
+		})),
+	})
+	require.NoError(t, err)
+	if opened.Result == nil {
+		return opened, nil
+	}
+
+	result, err := opened.Result.Unmarshal(resultType)
+	require.NoError(t, err)
+	var values map[string]tftypes.Value
+	require.NoError(t, result.As(&values))
+
+	return opened, values
+}
+
+// stringMapValue returns the map of strings v holds.
+func stringMapValue(t *testing.T, v tftypes.Value) map[string]string {
+	t.Helper()
+
+	var elems map[string]tftypes.Value
+	require.NoError(t, v.As(&elems))
+	m := make(map[string]string, len(elems))
+	for k, elem := range elems {
+		var s string
+		require.NoError(t, elem.As(&s))
+		m[k] = s
+	}
+
+	return m
+}
+
+func TestTableIOCredentials(t *testing.T) {
+	credentials := map[string]string{
+		"s3.access-key-id":     "ASIA123",
+		"s3.secret-access-key": "secret",
+		"s3.session-token":     "token",
+	}
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	m.vendedCredentials = credentials
+	server := m.start(t)
+
+	t.Run("vended credentials", func(t *testing.T) {
+		opened, values := openTableIOCredentials(t, map[string]tftypes.Value{
+			"catalog_uri":       tftypes.NewValue(tftypes.String, server.URL),
+			"access_delegation": tftypes.NewValue(tftypes.String, "vended-credentials"),
+		})
+		require.Empty(t, opened.Diagnostics)
+
+		var id string
+		require.NoError(t, values["id"].As(&id))
+		assert.Equal(t, "db.events", id)
+		assert.Equal(t, credentials, stringMapValue(t, values["config"]))
+
+		var storageCredentials []tftypes.Value
+		require.NoError(t, values["storage_credentials"].As(&storageCredentials))
+		require.Len(t, storageCredentials, 1)
+		var credential map[string]tftypes.Value
+		require.NoError(t, storageCredentials[0].As(&credential))
+		var prefix string
+		require.NoError(t, credential["prefix"].As(&prefix))
+		assert.Contains(t, prefix, "events")
+		assert.Equal(t, credentials, stringMapValue(t, credential["config"]))
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		opened, values := openTableIOCredentials(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+		})
+		require.Len(t, opened.Diagnostics, 1)
+		assert.Equal(t, tfprotov6.DiagnosticSeverityWarning, opened.Diagnostics[0].Severity)
+		assert.Equal(t, "No storage configuration returned", opened.Diagnostics[0].Summary)
+
+		assert.Empty(t, stringMapValue(t, values["config"]))
+	})
+
+	t.Run("table not found", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "other", nil)
+		opened, _ := openTableIOCredentials(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, m.start(t).URL),
+		})
+		require.Len(t, opened.Diagnostics, 1)
+		assert.Equal(t, "failed to load table", opened.Diagnostics[0].Summary)
+		assert.Contains(t, opened.Diagnostics[0].Detail, "NoSuchTableException")
+	})
+
+	t.Run("not a rest catalog", func(t *testing.T) {
+		opened, _ := openTableIOCredentials(t, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "memory"),
+		})
+		require.Len(t, opened.Diagnostics, 1)
+		assert.Equal(t, "Unsupported catalog type", opened.Diagnostics[0].Summary)
+	})
+
+	assert.Empty(t, m.writes)
+}
+
+func TestAccIcebergTableIOCredentials(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	m.vendedCredentials = map[string]string{"s3.session-token": "token"}
+	server := m.start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"iceberg": providerserver.NewProtocol6WithError(New("test")()),
+			"echo":    echoprovider.NewProviderServer(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri       = "%s"
+  access_delegation = "vended-credentials"
+}
+
+ephemeral "iceberg_table_io_credentials" "events" {
+  namespace = ["db"]
+  name      = "events"
+}
+
+provider "echo" {
+  data = ephemeral.iceberg_table_io_credentials.events.config
+}
+
+resource "echo" "credentials" {}
+`, server.URL),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("echo.credentials", tfjsonpath.New("data").AtMapKey("s3.session-token"), knownvalue.StringExact("token")),
+				},
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+ephemeral "iceberg_table_io_credentials" "missing" {
+  namespace = ["db"]
+  name      = "missing"
+}
+
+provider "echo" {
+  data = ephemeral.iceberg_table_io_credentials.missing.config
+}
+
+resource "echo" "credentials" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile(`failed to load table`),
+			},
+		},
+	})
+}
@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+)
+
+// notFoundErrorCodes are the error codes and exception names catalog backends
+// use for missing objects when they don't map them to the iceberg-go sentinels.
+var notFoundErrorCodes = []string{
+	// AWS Glue
+	"EntityNotFoundException",
+	// Hive Metastore
+	"NoSuchObjectException",
+	// Iceberg REST error types
+	"NoSuchTableException",
+	"NoSuchNamespaceException",
+	"NoSuchViewException",
+}
+
+// apiErrorCoder is implemented by AWS SDK errors (smithy.APIError) and allows
+// recognizing them without depending on the SDK here.
+type apiErrorCoder interface {
+	ErrorCode() string
+}
+
+// isNotFoundError reports whether err means the requested catalog object does
+// not exist, regardless of which catalog backend produced it. Read uses it to
+// drop resources from state and Delete to treat the object as already deleted.
+func isNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, catalog.ErrNoSuchTable) ||
+		errors.Is(err, catalog.ErrNoSuchNamespace) ||
+		errors.Is(err, catalog.ErrNoSuchView) ||
+		isPolarisNotFoundError(err) {
+		return true
+	}
+
+	var coder apiErrorCoder
+	if errors.As(err, &coder) {
+		for _, code := range notFoundErrorCodes {
+			if coder.ErrorCode() == code {
+				return true
+			}
+		}
+	}
+
+	msg := err.Error()
+	for _, code := range notFoundErrorCodes {
+		if strings.Contains(msg, code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isInvalidMetadataError reports whether err means iceberg-go rejected the
+// metadata of a table as it parsed it. The REST catalog doesn't wrap the
+// parse error, so it is also recognized by its message.
+func isInvalidMetadataError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, table.ErrInvalidMetadata) || strings.Contains(err.Error(), table.ErrInvalidMetadata.Error()+":")
+}
+
+// isNamespaceNotEmptyError reports whether err means a namespace can't be
+// dropped because it still has tables or views. The REST client doesn't map
+// the error to the iceberg-go sentinel, so it is also recognized by its type.
+func isNamespaceNotEmptyError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	return errors.Is(err, catalog.ErrNamespaceNotEmpty) || strings.Contains(err.Error(), "NamespaceNotEmptyException")
+}
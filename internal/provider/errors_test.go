@@ -0,0 +1,112 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPIError mimics smithy.APIError as returned by the AWS SDK.
+type fakeAPIError struct {
+	code string
+}
+
+func (e *fakeAPIError) Error() string     { return "api error " + e.code + ": something went wrong" }
+func (e *fakeAPIError) ErrorCode() string { return e.code }
+
+func TestIsNotFoundError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rest no such table", fmt.Errorf("%w: Table does not exist: db.t", catalog.ErrNoSuchTable), true},
+		{"rest no such namespace", fmt.Errorf("%w: Namespace does not exist: db", catalog.ErrNoSuchNamespace), true},
+		{"rest no such view", catalog.ErrNoSuchView, true},
+		{"polaris management", &polarisNotFoundError{method: "GET", path: "/principals/p"}, true},
+		{"glue api error", fmt.Errorf("failed to drop table: %w", &fakeAPIError{code: "EntityNotFoundException"}), true},
+		{"glue message only", errors.New("operation error Glue: DeleteTable, EntityNotFoundException: Table t not found"), true},
+		{"hive", errors.New("NoSuchObjectException(message:db.t table not found)"), true},
+		{"rest error type in message", errors.New("NoSuchTableException: Table does not exist: db.t"), true},
+		{"glue other api error", &fakeAPIError{code: "AccessDeniedException"}, false},
+		{"already exists", catalog.ErrTableAlreadyExists, false},
+		{"generic", errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isNotFoundError(tt.err))
+		})
+	}
+}
+
+func TestIsInvalidMetadataError(t *testing.T) {
+	assert.False(t, isInvalidMetadataError(nil))
+	assert.True(t, isInvalidMetadataError(fmt.Errorf("%w: current-schema-id 7 can't be found in any schema", table.ErrInvalidMetadata)))
+	assert.True(t, isInvalidMetadataError(errors.New("REST error: error decoding json payload: `invalid metadata: current-schema-id 7 can't be found in any schema`")))
+	assert.False(t, isInvalidMetadataError(errors.New("connection refused")))
+}
+
+func TestPolarisAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/principals/conflict":
+			writePolarisError(w, http.StatusConflict, "CommitFailedException", "entity version changed")
+		case "/principals/missing":
+			writePolarisError(w, http.StatusNotFound, "NotFoundException", "Principal missing not found")
+		case "/principals/gone":
+			http.NotFound(w, r)
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	p := &icebergProvider{polaris: &polarisConfig{managementURI: server.URL}}
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.GetPrincipal(ctx, "conflict")
+	require.ErrorIs(t, err, errPolarisConflict)
+	assert.EqualError(t, err, "polaris management: conflict: CommitFailedException: entity version changed")
+
+	_, err = client.GetPrincipal(ctx, "missing")
+	assert.True(t, isNotFoundError(err))
+	assert.EqualError(t, err, "polaris management: not found GET /principals/missing: Principal missing not found")
+
+	_, err = client.GetPrincipal(ctx, "gone")
+	assert.True(t, isNotFoundError(err))
+	assert.EqualError(t, err, "polaris management: not found GET /principals/gone")
+
+	_, err = client.GetPrincipal(ctx, "broken")
+	var apiErr *polarisAPIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Status)
+	assert.NotErrorIs(t, err, errPolarisConflict)
+	assert.EqualError(t, err, "polaris management: unexpected status 500: internal error\n")
+}
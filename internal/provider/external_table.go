@@ -0,0 +1,115 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// tableRegisterer is implemented by catalogs that can add an existing table
+// from its metadata file.
+type tableRegisterer interface {
+	RegisterTable(ctx context.Context, identifier table.Identifier, metadataLoc string) (*table.Table, error)
+}
+
+// validateExternalConfig checks the attributes that depend on external. An
+// external table is registered from metadata_location and its schema and
+// layout belong to the system that owns it, so they can't be configured, and
+// its files must never be purged.
+func validateExternalConfig(data *icebergTableResourceModel, diags *diag.Diagnostics) {
+	if data.External.IsUnknown() {
+		return
+	}
+
+	if !data.External.ValueBool() {
+		if data.Schema.IsNull() {
+			diags.AddAttributeError(path.Root("schema"), "Missing schema", "schema is required unless external = true.")
+		}
+		if !data.MetadataLocation.IsNull() {
+			diags.AddAttributeError(path.Root("metadata_location"), "metadata_location without external",
+				"metadata_location registers a table owned by another system and requires external = true.")
+		}
+
+		return
+	}
+
+	if data.MetadataLocation.IsNull() {
+		diags.AddAttributeError(path.Root("metadata_location"), "Missing metadata_location",
+			"External tables are registered from their metadata file: set metadata_location.")
+	}
+	if data.PurgeOnDestroy.ValueBool() {
+		diags.AddAttributeError(path.Root("purge_on_destroy"), "External tables can't be purged",
+			"The files of an external table belong to the system that owns it. Destroying it only removes it from the catalog; unset purge_on_destroy.")
+	}
+	for _, attr := range []struct {
+		name string
+		null bool
+	}{
+		{"schema", data.Schema.IsNull()},
+		{"partition_spec", data.PartitionSpec.IsNull()},
+		{"sort_order", data.SortOrder.IsNull()},
+		{"write_order", data.WriteOrder.IsNull()},
+	} {
+		if !attr.null {
+			diags.AddAttributeError(path.Root(attr.name), "Attribute not allowed on external tables",
+				attr.name+" is read from the catalog for external tables, whose layout is managed by the system that owns them. Remove it from the configuration.")
+		}
+	}
+}
+
+// registerExternalTable registers the table in data from its
+// metadata_location as ident and sets the configured properties on it.
+func (r *icebergTableResource) registerExternalTable(ctx context.Context, ident table.Identifier, data *icebergTableResourceModel, diags *diag.Diagnostics) *table.Table {
+	registerer, ok := r.catalog.(tableRegisterer)
+	if !ok {
+		diags.AddError("failed to register table", "the catalog client doesn't support registering tables")
+
+		return nil
+	}
+
+	tbl, err := registerer.RegisterTable(ctx, ident, data.MetadataLocation.ValueString())
+	if err != nil {
+		diags.AddError("failed to register table", err.Error())
+
+		return nil
+	}
+
+	// The registered table has the properties of its metadata file; those
+	// in the configuration are set like on an update from no properties.
+	delta := r.calculatePropertyUpdates(ctx, data, &icebergTableResourceModel{}, tbl, diags)
+	if diags.HasError() || delta.IsEmpty() {
+		return tbl
+	}
+
+	requirements := []table.Requirement{table.AssertTableUUID(tbl.Metadata().TableUUID())}
+	if _, _, err := r.catalog.CommitTable(ctx, ident, requirements, propertyUpdates(delta)); err != nil {
+		diags.AddError("failed to set properties of registered table",
+			"The table "+encodeIdentifier(ident)+" was registered, but its properties couldn't be set: "+err.Error())
+
+		return nil
+	}
+	if err := tbl.Refresh(ctx); err != nil {
+		diags.AddError("failed to refresh table after commit", err.Error())
+
+		return nil
+	}
+
+	return tbl
+}
@@ -0,0 +1,179 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateExternalConfig(t *testing.T) {
+	schema := types.ObjectValueMust(map[string]attr.Type{}, map[string]attr.Value{})
+	location := types.StringValue("s3://lake/events/metadata/00003.metadata.json")
+
+	for _, tc := range []struct {
+		name       string
+		data       icebergTableResourceModel
+		wantErrors []string
+	}{
+		{
+			name: "managed table",
+			data: icebergTableResourceModel{Schema: schema},
+		},
+		{
+			name:       "managed table without schema",
+			data:       icebergTableResourceModel{Schema: types.ObjectNull(map[string]attr.Type{})},
+			wantErrors: []string{"Missing schema"},
+		},
+		{
+			name:       "managed table with metadata_location",
+			data:       icebergTableResourceModel{Schema: schema, MetadataLocation: location},
+			wantErrors: []string{"metadata_location without external"},
+		},
+		{
+			name: "external table",
+			data: icebergTableResourceModel{External: types.BoolValue(true), MetadataLocation: location, PurgeOnDestroy: types.BoolValue(false)},
+		},
+		{
+			name:       "external table without metadata_location",
+			data:       icebergTableResourceModel{External: types.BoolValue(true)},
+			wantErrors: []string{"Missing metadata_location"},
+		},
+		{
+			name:       "external table purged",
+			data:       icebergTableResourceModel{External: types.BoolValue(true), MetadataLocation: location, PurgeOnDestroy: types.BoolValue(true)},
+			wantErrors: []string{"External tables can't be purged"},
+		},
+		{
+			name:       "external table with schema",
+			data:       icebergTableResourceModel{External: types.BoolValue(true), MetadataLocation: location, Schema: schema},
+			wantErrors: []string{"Attribute not allowed on external tables"},
+		},
+		{
+			name: "unknown external",
+			data: icebergTableResourceModel{External: types.BoolUnknown(), PurgeOnDestroy: types.BoolValue(true)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateExternalConfig(&tc.data, &diags)
+
+			var got []string
+			for _, d := range diags.Errors() {
+				got = append(got, d.Summary())
+			}
+			assert.Equal(t, tc.wantErrors, got)
+		})
+	}
+}
+
+func TestRegisterExternalTable(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	m.addMetadataFile("s3://lake/events/metadata/00003.metadata.json", map[string]string{"owner": "ingest", "gc.enabled": "false"})
+	server := m.start(t)
+
+	r := &icebergTableResource{provider: &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}}
+	var diags diag.Diagnostics
+	r.ConfigureCatalog(ctx, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	data := icebergTableResourceModel{
+		External:         types.BoolValue(true),
+		MetadataLocation: types.StringValue("s3://lake/events/metadata/00003.metadata.json"),
+		UserProperties:   types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("data_eng")}),
+	}
+	tbl := r.registerExternalTable(ctx, table.Identifier{"db", "events"}, &data, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "data_eng", tbl.Properties()["team"])
+	assert.Equal(t, map[string]string{"owner": "ingest", "gc.enabled": "false", "team": "data_eng"}, m.tableProperties("db", "events"))
+
+	t.Run("unknown metadata file", func(t *testing.T) {
+		var diags diag.Diagnostics
+		data := icebergTableResourceModel{External: types.BoolValue(true), MetadataLocation: types.StringValue("s3://lake/missing.metadata.json")}
+		assert.Nil(t, r.registerExternalTable(ctx, table.Identifier{"db", "missing"}, &data, &diags))
+		require.Len(t, diags.Errors(), 1)
+		assert.Contains(t, diags.Errors()[0].Detail(), "Cannot read metadata file s3://lake/missing.metadata.json")
+	})
+}
+
+func TestAccIcebergTable_External(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	m.addMetadataFile("s3://lake/events/metadata/00003.metadata.json", map[string]string{"owner": "ingest"})
+	server := m.start(t)
+
+	config := func(extra string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "events" {
+  namespace         = ["db"]
+  name              = "events"
+  external          = true
+  metadata_location = "s3://lake/events/metadata/00003.metadata.json"
+  %s
+}
+`, server.URL, extra)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if len(m.purged) > 0 {
+				return fmt.Errorf("external tables must not be purged, got %v", m.purged)
+			}
+			if m.tableProperties("db", "events") != nil {
+				return fmt.Errorf("db.events wasn't removed from the catalog")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`purge_on_destroy = true`),
+				ExpectError: regexp.MustCompile("External tables can't be purged"),
+			},
+			{
+				Config: config(`user_properties = { "team" = "data_eng" }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.events", "schema.fields.0.name", "id"),
+					resource.TestCheckResourceAttr("iceberg_table.events", "server_properties.owner", "ingest"),
+					resource.TestCheckResourceAttr("iceberg_table.events", "server_properties.team", "data_eng"),
+				),
+			},
+			{
+				Config: config(`user_properties = { "team" = "platform" }`),
+				Check:  resource.TestCheckResourceAttr("iceberg_table.events", "server_properties.team", "platform"),
+			},
+		},
+	})
+}
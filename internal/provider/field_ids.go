@@ -0,0 +1,183 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// Field IDs are optional in the configuration: the catalog assigns the IDs of
+// fields that have none. Once assigned, an ID never changes, so the plan keeps
+// the ID of every existing field, matched by name, whether the configuration
+// leaves it out or repeats it. Terraform itself would match fields by their
+// position in the list, which gets IDs wrong when fields are inserted or
+// reordered.
+
+// planFieldIDs fills the IDs of existing fields into the planned schema and
+// rejects configured IDs that differ from the assigned ones.
+func (r *icebergTableResource) planFieldIDs(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var configSchema, planSchema, stateSchema types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("schema"), &configSchema)...)
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("schema"), &planSchema)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("schema"), &stateSchema)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Field names and types must be known to match fields; IDs may not be.
+	var config, plan, state icebergTableSchema
+	var d diag.Diagnostics
+	d.Append(configSchema.As(ctx, &config, basetypes.ObjectAsOptions{})...)
+	d.Append(planSchema.As(ctx, &plan, basetypes.ObjectAsOptions{})...)
+	d.Append(stateSchema.As(ctx, &state, basetypes.ObjectAsOptions{})...)
+	if d.HasError() {
+		return
+	}
+
+	reconcileFieldIDs(config.Fields, plan.Fields, state.Fields, path.Root("schema").AtName("fields"), &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, d := types.ObjectValueFrom(ctx, icebergTableSchema{}.AttrTypes(), plan)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() || planned.Equal(planSchema) {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("schema"), planned)...)
+}
+
+// reconcileFieldIDs sets the ID of every planned field that exists in state
+// to the ID in state, and adds an error for each configured ID that differs
+// from it. config and plan are the same fields, so they are matched by
+// position; state fields are matched by name. Fields that don't exist in
+// state get an unknown ID unless one is configured.
+func reconcileFieldIDs(config, plan, state []icebergTableSchemaField, fieldsPath path.Path, diags *diag.Diagnostics) {
+	existing := make(map[string]icebergTableSchemaField, len(state))
+	for _, f := range state {
+		existing[f.Name] = f
+	}
+
+	for i := range plan {
+		var configured icebergTableSchemaField
+		if i < len(config) {
+			configured = config[i]
+		}
+		field := &plan[i]
+		fieldPath := fieldsPath.AtListIndex(i)
+
+		prior, ok := existing[field.Name]
+		if !ok || prior.ID.IsNull() || prior.ID.IsUnknown() {
+			if configured.ID.IsNull() {
+				field.ID = types.Int64Unknown()
+			}
+		} else {
+			switch {
+			case configured.ID.IsNull() || configured.ID.IsUnknown():
+				field.ID = prior.ID
+			case configured.ID.ValueInt64() != prior.ID.ValueInt64():
+				diags.AddAttributeError(
+					fieldPath.AtName("id"),
+					"Field IDs are immutable",
+					fmt.Sprintf("The field %q has ID %d in the table, but the configuration sets ID %d. Field IDs can't be changed: set the ID to %d or remove it.",
+						field.Name, prior.ID.ValueInt64(), configured.ID.ValueInt64(), prior.ID.ValueInt64()),
+				)
+			}
+		}
+
+		if field.StructProperties != nil {
+			var configFields, stateFields []icebergTableSchemaField
+			if configured.StructProperties != nil {
+				configFields = configured.StructProperties.Fields
+			}
+			if ok && prior.StructProperties != nil {
+				stateFields = prior.StructProperties.Fields
+			}
+			reconcileFieldIDs(configFields, field.StructProperties.Fields, stateFields, fieldPath.AtName("struct_properties").AtName("fields"), diags)
+		}
+	}
+}
+
+// assignFieldIDs gives the fields without a known ID the IDs after lastID, in
+// order, and returns the last ID assigned.
+func assignFieldIDs(fields []icebergTableSchemaField, lastID int64) int64 {
+	for i := range fields {
+		if fields[i].ID.IsNull() || fields[i].ID.IsUnknown() {
+			lastID++
+			fields[i].ID = types.Int64Value(lastID)
+		}
+		if fields[i].StructProperties != nil {
+			lastID = assignFieldIDs(fields[i].StructProperties.Fields, lastID)
+		}
+	}
+
+	return lastID
+}
+
+// highestFieldID returns the highest known ID among fields, including the
+// element, key and value IDs of lists and maps.
+func highestFieldID(fields []icebergTableSchemaField) int64 {
+	var highest int64
+	known := func(id types.Int64) {
+		if !id.IsNull() && !id.IsUnknown() {
+			highest = max(highest, id.ValueInt64())
+		}
+	}
+	for _, f := range fields {
+		known(f.ID)
+		if f.ListProperties != nil {
+			known(f.ListProperties.ID)
+		}
+		if f.MapProperties != nil {
+			known(f.MapProperties.KeyID)
+			known(f.MapProperties.ValueID)
+		}
+		if f.StructProperties != nil {
+			highest = max(highest, highestFieldID(f.StructProperties.Fields))
+		}
+	}
+
+	return highest
+}
+
+// withAssignedFieldIDs returns schema with the IDs after lastID given to the
+// fields whose IDs are unknown, so a planned schema that is only waiting for
+// the catalog to assign IDs can be checked at plan time.
+func withAssignedFieldIDs(ctx context.Context, schema types.Object, lastID int64) types.Object {
+	if schema.IsNull() || schema.IsUnknown() {
+		return schema
+	}
+
+	var s icebergTableSchema
+	if d := schema.As(ctx, &s, basetypes.ObjectAsOptions{}); d.HasError() {
+		return schema
+	}
+	assignFieldIDs(s.Fields, max(lastID, highestFieldID(s.Fields)))
+
+	assigned, d := types.ObjectValueFrom(ctx, icebergTableSchema{}.AttrTypes(), s)
+	if d.HasError() {
+		return schema
+	}
+
+	return assigned
+}
@@ -0,0 +1,199 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSchemaField(name string, id types.Int64) icebergTableSchemaField {
+	return icebergTableSchemaField{
+		ID:       id,
+		Name:     name,
+		Type:     "long",
+		Required: false,
+	}
+}
+
+func TestReconcileFieldIDs(t *testing.T) {
+	fieldsPath := path.Root("schema").AtName("fields")
+	state := []icebergTableSchemaField{
+		testSchemaField("id", types.Int64Value(1)),
+		testSchemaField("ts", types.Int64Value(2)),
+	}
+
+	t.Run("unset IDs keep the assigned IDs", func(t *testing.T) {
+		// Inserting a field moves ts to another position, which Terraform
+		// would plan with the ID of the field previously at that position.
+		config := []icebergTableSchemaField{
+			testSchemaField("id", types.Int64Null()),
+			testSchemaField("user", types.Int64Null()),
+			testSchemaField("ts", types.Int64Null()),
+		}
+		plan := []icebergTableSchemaField{
+			testSchemaField("id", types.Int64Value(1)),
+			testSchemaField("user", types.Int64Value(2)),
+			testSchemaField("ts", types.Int64Unknown()),
+		}
+
+		var diags diag.Diagnostics
+		reconcileFieldIDs(config, plan, state, fieldsPath, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, types.Int64Value(1), plan[0].ID)
+		assert.True(t, plan[1].ID.IsUnknown())
+		assert.Equal(t, types.Int64Value(2), plan[2].ID)
+	})
+
+	t.Run("configured IDs equal to the assigned IDs are accepted", func(t *testing.T) {
+		config := []icebergTableSchemaField{
+			testSchemaField("id", types.Int64Value(1)),
+			testSchemaField("ts", types.Int64Value(2)),
+		}
+		plan := []icebergTableSchemaField{
+			testSchemaField("id", types.Int64Value(1)),
+			testSchemaField("ts", types.Int64Value(2)),
+		}
+
+		var diags diag.Diagnostics
+		reconcileFieldIDs(config, plan, state, fieldsPath, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, state, plan)
+	})
+
+	t.Run("configured IDs different from the assigned IDs are rejected", func(t *testing.T) {
+		config := []icebergTableSchemaField{
+			testSchemaField("id", types.Int64Value(1)),
+			testSchemaField("ts", types.Int64Value(5)),
+		}
+		plan := []icebergTableSchemaField{
+			testSchemaField("id", types.Int64Value(1)),
+			testSchemaField("ts", types.Int64Value(5)),
+		}
+
+		var diags diag.Diagnostics
+		reconcileFieldIDs(config, plan, state, fieldsPath, &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Field IDs are immutable", diags.Errors()[0].Summary())
+		assert.Contains(t, diags.Errors()[0].Detail(), `"ts" has ID 2`)
+	})
+
+	t.Run("struct fields are matched within their struct", func(t *testing.T) {
+		state := []icebergTableSchemaField{{
+			ID:               types.Int64Value(1),
+			Name:             "location",
+			Type:             "struct",
+			Required:         false,
+			StructProperties: &icebergTableSchemaFieldStructProperties{Fields: []icebergTableSchemaField{testSchemaField("lat", types.Int64Value(2))}},
+		}}
+		config := []icebergTableSchemaField{{
+			ID:               types.Int64Null(),
+			Name:             "location",
+			Type:             "struct",
+			Required:         false,
+			StructProperties: &icebergTableSchemaFieldStructProperties{Fields: []icebergTableSchemaField{testSchemaField("lat", types.Int64Value(3))}},
+		}}
+		plan := []icebergTableSchemaField{{
+			ID:               types.Int64Unknown(),
+			Name:             "location",
+			Type:             "struct",
+			Required:         false,
+			StructProperties: &icebergTableSchemaFieldStructProperties{Fields: []icebergTableSchemaField{testSchemaField("lat", types.Int64Value(3))}},
+		}}
+
+		var diags diag.Diagnostics
+		reconcileFieldIDs(config, plan, state, fieldsPath, &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, types.Int64Value(1), plan[0].ID)
+		assert.Equal(t, "Field IDs are immutable", diags.Errors()[0].Summary())
+	})
+}
+
+func TestAssignFieldIDs(t *testing.T) {
+	fields := []icebergTableSchemaField{
+		testSchemaField("id", types.Int64Value(1)),
+		testSchemaField("user", types.Int64Unknown()),
+		testSchemaField("ts", types.Int64Null()),
+	}
+
+	assert.Equal(t, int64(7), assignFieldIDs(fields, max(5, highestFieldID(fields))))
+	assert.Equal(t, types.Int64Value(1), fields[0].ID)
+	assert.Equal(t, types.Int64Value(6), fields[1].ID)
+	assert.Equal(t, types.Int64Value(7), fields[2].ID)
+}
+
+func TestAccIcebergTable_ExplicitFieldIDs(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := func(id string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "events" {
+  namespace = ["db"]
+  name      = "events"
+  schema = {
+    fields = [
+      {
+        %s
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL, id)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check:  resource.TestCheckResourceAttr("iceberg_table.events", "schema.fields.0.id", "1"),
+			},
+			{
+				// Pinning the assigned ID changes nothing.
+				Config:   config("id = 1"),
+				PlanOnly: true,
+			},
+			{
+				Config:      config("id = 2"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Field IDs are immutable`),
+			},
+			{
+				// Unpinning it again changes nothing either.
+				Config:   config(""),
+				PlanOnly: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,108 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/iceberg-go"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &mergeSchemasFunction{}
+
+func NewMergeSchemasFunction() function.Function {
+	return &mergeSchemasFunction{}
+}
+
+// mergeSchemasFunction appends shared field lists, such as audit columns, to
+// an Iceberg schema JSON document.
+type mergeSchemasFunction struct{}
+
+func (f *mergeSchemasFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "merge_schemas"
+}
+
+func (f *mergeSchemasFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Merges the fields of Iceberg schemas.",
+		Description: "Returns the Iceberg schema JSON of base_json with the fields of each fragment_json appended, in order, and the schema ID of base_json. " +
+			"Field names must be unique at every level, so a fragment can't add a field that base_json or an earlier fragment has. " +
+			"Field IDs, including list element and map key and value IDs, are kept unless they are missing or already used, by an earlier schema or earlier in the same one. " +
+			"Such IDs are reassigned counting up from the highest ID in use, in the order the fields appear, so the result only changes when the schemas do; with strict_ids they are an error instead. " +
+			"The identifier fields of all schemas are kept.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "base_json",
+				Description: "The schema as Iceberg schema JSON, for example from table metadata or jsonencode. Field IDs may be left out.",
+			},
+			function.BoolParameter{
+				Name:        "strict_ids",
+				Description: "Whether a missing or already used field ID is an error rather than reassigned.",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:        "fragment_json",
+			Description: "A schema with the fields to append as Iceberg schema JSON, for example {\"type\": \"struct\", \"fields\": [...]}. Field IDs may be left out.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *mergeSchemasFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var baseJSON string
+	var strictIDs bool
+	var fragmentJSON []string
+	resp.Error = req.Arguments.Get(ctx, &baseJSON, &strictIDs, &fragmentJSON)
+	if resp.Error != nil {
+		return
+	}
+
+	schemas := make([]*iceberg.Schema, 0, 1+len(fragmentJSON))
+	base, err := parseSchemaFragment(baseJSON)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "base_json isn't a valid Iceberg schema: "+err.Error())
+
+		return
+	}
+	schemas = append(schemas, base)
+	for i, s := range fragmentJSON {
+		fragment, err := parseSchemaFragment(s)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(int64(2+i), fmt.Sprintf("fragment %d isn't a valid Iceberg schema: %s", i+1, err))
+
+			return
+		}
+		schemas = append(schemas, fragment)
+	}
+
+	merged, err := mergeSchemas(schemas, strictIDs)
+	if err != nil {
+		resp.Error = function.NewFuncError("failed to merge schemas: " + err.Error())
+
+		return
+	}
+	written, err := json.Marshal(merged)
+	if err != nil {
+		resp.Error = function.NewFuncError("failed to merge schemas: " + err.Error())
+
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, string(written))
+}
@@ -0,0 +1,127 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mergeSchemasTestAudit = `{"type": "struct", "fields": [
+  {"name": "created_at", "type": "timestamptz", "required": false},
+  {"name": "created_by", "type": "string", "required": false}
+]}`
+
+func runMergeSchemasFunction(t *testing.T, baseJSON string, strictIDs bool, fragmentJSON ...string) (string, *function.FuncError) {
+	t.Helper()
+
+	ctx := context.Background()
+	fragments := make([]attr.Value, 0, len(fragmentJSON))
+	fragmentTypes := make([]attr.Type, 0, len(fragmentJSON))
+	for _, s := range fragmentJSON {
+		fragments = append(fragments, types.StringValue(s))
+		fragmentTypes = append(fragmentTypes, types.StringType)
+	}
+	f := NewMergeSchemasFunction()
+	resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	f.Run(ctx, function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{
+			types.StringValue(baseJSON),
+			types.BoolValue(strictIDs),
+			types.TupleValueMust(fragmentTypes, fragments),
+		}),
+	}, &resp)
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+
+	result, ok := resp.Result.Value().(types.String)
+	require.True(t, ok)
+
+	return result.ValueString(), nil
+}
+
+func TestMergeSchemasFunction(t *testing.T) {
+	got, funcErr := runMergeSchemasFunction(t, mergeSchemasTestBase, false, mergeSchemasTestAudit)
+	require.Nil(t, funcErr)
+	assert.JSONEq(t, `{"type": "struct", "schema-id": 3, "identifier-field-ids": [1], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false},
+  {"id": 5, "name": "created_at", "type": "timestamptz", "required": false},
+  {"id": 6, "name": "created_by", "type": "string", "required": false}
+]}`, got)
+
+	merged, err := parseSchemaJSON(got)
+	require.NoError(t, err, "the result is a valid schema")
+	assert.Equal(t, 6, merged.HighestFieldID())
+
+	t.Run("strict IDs", func(t *testing.T) {
+		_, funcErr := runMergeSchemasFunction(t, mergeSchemasTestBase, true, mergeSchemasTestAudit)
+		require.NotNil(t, funcErr)
+		assert.Equal(t, "failed to merge schemas: fragment 1: created_at has no field ID", funcErr.Text)
+	})
+
+	t.Run("invalid base", func(t *testing.T) {
+		_, funcErr := runMergeSchemasFunction(t, `{"type": "struct", "fields": [{"id": 1, "name": "x", "type": "varchar"}]}`, false)
+		require.NotNil(t, funcErr)
+		assert.Equal(t, int64(0), *funcErr.FunctionArgument)
+		assert.Contains(t, funcErr.Text, "base_json isn't a valid Iceberg schema")
+	})
+
+	t.Run("invalid fragment", func(t *testing.T) {
+		_, funcErr := runMergeSchemasFunction(t, mergeSchemasTestBase, false, mergeSchemasTestAudit, `{"type": "struct", "fields": [`)
+		require.NotNil(t, funcErr)
+		assert.Equal(t, int64(3), *funcErr.FunctionArgument)
+		assert.Contains(t, funcErr.Text, "fragment 2 isn't a valid Iceberg schema")
+	})
+}
+
+func TestAccMergeSchemasFunction(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	want, funcErr := runMergeSchemasFunction(t, mergeSchemasTestBase, false, mergeSchemasTestAudit)
+	require.Nil(t, funcErr)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Provider-defined functions
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(providerConfig, server.URL) + fmt.Sprintf(`
+output "schema" {
+  value = provider::iceberg::merge_schemas(%q, false, %q)
+}
+`, mergeSchemasTestBase, mergeSchemasTestAudit),
+				Check: resource.TestCheckOutput("schema", want),
+			},
+		},
+	})
+}
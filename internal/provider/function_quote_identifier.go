@@ -0,0 +1,131 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &quoteIdentifierFunction{}
+
+func NewQuoteIdentifierFunction() function.Function {
+	return &quoteIdentifierFunction{}
+}
+
+// quoteIdentifierFunction quotes the fully-qualified name of a table for the
+// SQL dialect of an engine, so templates don't have to escape names
+// themselves.
+type quoteIdentifierFunction struct{}
+
+// quoteIdentifierDialects are the dialects quote_identifier supports.
+var quoteIdentifierDialects = []string{"flink", "spark", "trino"}
+
+func (f *quoteIdentifierFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "quote_identifier"
+}
+
+func (f *quoteIdentifierFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Quotes the name of a table for a SQL dialect.",
+		Description: "Returns the fully-qualified name of the table name in namespace, with every part quoted for dialect so that keywords, dots, spaces and quotes in names are safe. " +
+			"trino quotes with double quotes and spark and flink with backticks; quotes inside a name are escaped by doubling them. " +
+			"Trino and Flink have a single level of schemas or databases: Trino names a nested namespace by its levels joined with dots, and Flink doesn't support nested namespaces.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "namespace",
+				Description: "The namespace of the table, one element per level.",
+				ElementType: types.StringType,
+			},
+			function.StringParameter{
+				Name:        "name",
+				Description: "The name of the table.",
+			},
+			function.StringParameter{
+				Name:        "dialect",
+				Description: "The SQL dialect to quote for: " + strings.Join(quoteIdentifierDialects, ", ") + ".",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *quoteIdentifierFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var namespace []string
+	var name, dialect string
+	resp.Error = req.Arguments.Get(ctx, &namespace, &name, &dialect)
+	if resp.Error != nil {
+		return
+	}
+
+	for _, level := range namespace {
+		if level == "" {
+			resp.Error = function.NewArgumentFuncError(0, "namespace levels can't be empty")
+
+			return
+		}
+	}
+	if name == "" {
+		resp.Error = function.NewArgumentFuncError(1, "name can't be empty")
+
+		return
+	}
+
+	quoted, err := quoteIdentifier(namespace, name, dialect)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(2, err.Error())
+
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, quoted)
+}
+
+// quoteIdentifier returns namespace and name as a fully-qualified name in
+// dialect, with every part quoted.
+func quoteIdentifier(namespace []string, name, dialect string) (string, error) {
+	var quote string
+	switch dialect {
+	case "trino":
+		// Trino exposes a nested namespace as one schema named by its levels
+		// joined with dots.
+		quote = `"`
+		if len(namespace) > 1 {
+			namespace = []string{strings.Join(namespace, ".")}
+		}
+	case "spark":
+		quote = "`"
+	case "flink":
+		quote = "`"
+		if len(namespace) > 1 {
+			return "", errors.New("flink doesn't support nested namespaces, got " + strings.Join(namespace, "."))
+		}
+	default:
+		return "", errors.New("dialect must be one of " + strings.Join(quoteIdentifierDialects, ", ") + ", got " + dialect)
+	}
+
+	parts := make([]string, 0, len(namespace)+1)
+	for _, part := range namespace {
+		parts = append(parts, quote+strings.ReplaceAll(part, quote, quote+quote)+quote)
+	}
+	parts = append(parts, quote+strings.ReplaceAll(name, quote, quote+quote)+quote)
+
+	return strings.Join(parts, "."), nil
+}
@@ -0,0 +1,215 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runQuoteIdentifier(t *testing.T, namespace []string, name, dialect string) (string, *function.FuncError) {
+	t.Helper()
+
+	levels := make([]attr.Value, 0, len(namespace))
+	for _, level := range namespace {
+		levels = append(levels, types.StringValue(level))
+	}
+
+	ctx := context.Background()
+	f := NewQuoteIdentifierFunction()
+	resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	f.Run(ctx, function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{
+			types.ListValueMust(types.StringType, levels),
+			types.StringValue(name),
+			types.StringValue(dialect),
+		}),
+	}, &resp)
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+
+	result, ok := resp.Result.Value().(types.String)
+	require.True(t, ok)
+
+	return result.ValueString(), nil
+}
+
+func TestQuoteIdentifierFunction(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		namespace []string
+		table     string
+		want      map[string]string
+	}{
+		{
+			name:      "plain",
+			namespace: []string{"db"},
+			table:     "events",
+			want: map[string]string{
+				"trino": `"db"."events"`,
+				"spark": "`db`.`events`",
+				"flink": "`db`.`events`",
+			},
+		},
+		{
+			name:      "keywords",
+			namespace: []string{"select"},
+			table:     "from",
+			want: map[string]string{
+				"trino": `"select"."from"`,
+				"spark": "`select`.`from`",
+				"flink": "`select`.`from`",
+			},
+		},
+		{
+			name:      "double quotes",
+			namespace: []string{`my "db"`},
+			table:     `a"b`,
+			want: map[string]string{
+				"trino": `"my ""db"""."a""b"`,
+				"spark": "`my \"db\"`.`a\"b`",
+				"flink": "`my \"db\"`.`a\"b`",
+			},
+		},
+		{
+			name:      "backticks",
+			namespace: []string{"db"},
+			table:     "a`b``",
+			want: map[string]string{
+				"trino": "\"db\".\"a`b``\"",
+				"spark": "`db`.`a``b`````",
+				"flink": "`db`.`a``b`````",
+			},
+		},
+		{
+			name:      "dots and spaces",
+			namespace: []string{"sales.eu"},
+			table:     "daily orders",
+			want: map[string]string{
+				"trino": `"sales.eu"."daily orders"`,
+				"spark": "`sales.eu`.`daily orders`",
+				"flink": "`sales.eu`.`daily orders`",
+			},
+		},
+		{
+			name:      "unicode",
+			namespace: []string{"données"},
+			table:     "événements_日本",
+			want: map[string]string{
+				"trino": `"données"."événements_日本"`,
+				"spark": "`données`.`événements_日本`",
+				"flink": "`données`.`événements_日本`",
+			},
+		},
+		{
+			name:  "no namespace",
+			table: "events",
+			want: map[string]string{
+				"trino": `"events"`,
+				"spark": "`events`",
+				"flink": "`events`",
+			},
+		},
+		{
+			name:      "nested namespace",
+			namespace: []string{"lake", "raw"},
+			table:     "events",
+			want: map[string]string{
+				"trino": `"lake.raw"."events"`,
+				"spark": "`lake`.`raw`.`events`",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for dialect, want := range tc.want {
+				got, funcErr := runQuoteIdentifier(t, tc.namespace, tc.table, dialect)
+				require.Nil(t, funcErr, dialect)
+				assert.Equal(t, want, got, dialect)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name      string
+		namespace []string
+		table     string
+		dialect   string
+		wantArg   int64
+		wantError string
+	}{
+		{name: "unknown dialect", namespace: []string{"db"}, table: "events", dialect: "hive", wantArg: 2, wantError: "dialect must be one of flink, spark, trino, got hive"},
+		{name: "nested namespace in flink", namespace: []string{"lake", "raw"}, table: "events", dialect: "flink", wantArg: 2, wantError: "flink doesn't support nested namespaces"},
+		{name: "empty name", namespace: []string{"db"}, dialect: "spark", wantArg: 1, wantError: "name can't be empty"},
+		{name: "empty namespace level", namespace: []string{"db", ""}, table: "events", dialect: "trino", wantArg: 0, wantError: "namespace levels can't be empty"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, funcErr := runQuoteIdentifier(t, tc.namespace, tc.table, tc.dialect)
+			require.NotNil(t, funcErr)
+			assert.Equal(t, tc.wantArg, *funcErr.FunctionArgument)
+			assert.Contains(t, funcErr.Text, tc.wantError)
+		})
+	}
+}
+
+func TestAccQuoteIdentifierFunction(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Provider-defined functions
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(providerConfig, server.URL) + `
+output "trino" {
+  value = provider::iceberg::quote_identifier(["db"], "order \"items\"", "trino")
+}
+
+output "spark" {
+  value = provider::iceberg::quote_identifier(["lake", "raw"], "select", "spark")
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("trino", `"db"."order ""items"""`),
+					resource.TestCheckOutput("spark", "`lake`.`raw`.`select`"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(providerConfig, server.URL) + `
+output "hive" {
+  value = provider::iceberg::quote_identifier(["db"], "events", "hive")
+}
+`,
+				ExpectError: regexp.MustCompile("dialect must be one of"),
+			},
+		},
+	})
+}
@@ -0,0 +1,188 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/iceberg-go"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = &schemaDiffFunction{}
+
+func NewSchemaDiffFunction() function.Function {
+	return &schemaDiffFunction{}
+}
+
+// schemaDiffFunction compares two Iceberg schema JSON documents, so modules
+// can inspect or restrict a schema change at plan time.
+type schemaDiffFunction struct{}
+
+type schemaDiffModel struct {
+	Added   []schemaDiffFieldModel  `tfsdk:"added"`
+	Removed []schemaDiffFieldModel  `tfsdk:"removed"`
+	Renamed []schemaDiffRenameModel `tfsdk:"renamed"`
+	Retyped []schemaDiffRetypeModel `tfsdk:"retyped"`
+}
+
+type schemaDiffFieldModel struct {
+	ID       types.Int64  `tfsdk:"id"`
+	Name     types.String `tfsdk:"name"`
+	Type     types.String `tfsdk:"type"`
+	Required types.Bool   `tfsdk:"required"`
+}
+
+type schemaDiffRenameModel struct {
+	ID      types.Int64  `tfsdk:"id"`
+	OldName types.String `tfsdk:"old_name"`
+	NewName types.String `tfsdk:"new_name"`
+}
+
+type schemaDiffRetypeModel struct {
+	ID      types.Int64  `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	OldType types.String `tfsdk:"old_type"`
+	NewType types.String `tfsdk:"new_type"`
+}
+
+var (
+	schemaDiffFieldAttrTypes = map[string]attr.Type{
+		"id":       types.Int64Type,
+		"name":     types.StringType,
+		"type":     types.StringType,
+		"required": types.BoolType,
+	}
+	schemaDiffAttrTypes = map[string]attr.Type{
+		"added":   types.ListType{ElemType: types.ObjectType{AttrTypes: schemaDiffFieldAttrTypes}},
+		"removed": types.ListType{ElemType: types.ObjectType{AttrTypes: schemaDiffFieldAttrTypes}},
+		"renamed": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+			"id":       types.Int64Type,
+			"old_name": types.StringType,
+			"new_name": types.StringType,
+		}}},
+		"retyped": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+			"id":       types.Int64Type,
+			"name":     types.StringType,
+			"old_type": types.StringType,
+			"new_type": types.StringType,
+		}}},
+	}
+)
+
+func (f *schemaDiffFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "schema_diff"
+}
+
+func (f *schemaDiffFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Compares two Iceberg schemas.",
+		Description: "Returns the fields added, removed, renamed and retyped in new_json compared to old_json. " +
+			"Fields are matched by ID like iceberg_table matches them, or by full name if a field has no ID, so a renamed field isn't reported as removed and added. " +
+			"Nested struct fields are compared one by one and named with dots, for example address.city; the fields of added and removed structs aren't listed separately, and lists and maps are compared as a whole.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "old_json",
+				Description: "The current schema as Iceberg schema JSON, for example from table metadata.",
+			},
+			function.StringParameter{
+				Name:        "new_json",
+				Description: "The proposed schema as Iceberg schema JSON.",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: schemaDiffAttrTypes},
+	}
+}
+
+func (f *schemaDiffFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var oldJSON, newJSON string
+	resp.Error = req.Arguments.Get(ctx, &oldJSON, &newJSON)
+	if resp.Error != nil {
+		return
+	}
+
+	prior, err := parseSchemaJSON(oldJSON)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "old_json isn't a valid Iceberg schema: "+err.Error())
+
+		return
+	}
+	next, err := parseSchemaJSON(newJSON)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "new_json isn't a valid Iceberg schema: "+err.Error())
+
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, diffSchemas(prior, next).model())
+}
+
+func parseSchemaJSON(s string) (_ *iceberg.Schema, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	var schema iceberg.Schema
+	if err := json.Unmarshal([]byte(s), &schema); err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
+
+// model returns the diff as the result of schema_diff. The lists are empty
+// rather than null when nothing changed, so length() works on them.
+func (d schemaDiff) model() schemaDiffModel {
+	m := schemaDiffModel{
+		Added:   make([]schemaDiffFieldModel, 0, len(d.added)),
+		Removed: make([]schemaDiffFieldModel, 0, len(d.removed)),
+		Renamed: make([]schemaDiffRenameModel, 0, len(d.renamed)),
+		Retyped: make([]schemaDiffRetypeModel, 0, len(d.retyped)),
+	}
+	for _, f := range d.added {
+		m.Added = append(m.Added, f.model())
+	}
+	for _, f := range d.removed {
+		m.Removed = append(m.Removed, f.model())
+	}
+	for _, r := range d.renamed {
+		m.Renamed = append(m.Renamed, schemaDiffRenameModel{ID: fieldIDValue(r.id), OldName: types.StringValue(r.oldName), NewName: types.StringValue(r.newName)})
+	}
+	for _, r := range d.retyped {
+		m.Retyped = append(m.Retyped, schemaDiffRetypeModel{ID: fieldIDValue(r.id), Name: types.StringValue(r.name), OldType: types.StringValue(r.oldType), NewType: types.StringValue(r.newType)})
+	}
+
+	return m
+}
+
+func (f schemaDiffField) model() schemaDiffFieldModel {
+	return schemaDiffFieldModel{ID: fieldIDValue(f.id), Name: types.StringValue(f.name), Type: types.StringValue(f.typ), Required: types.BoolValue(f.required)}
+}
+
+// fieldIDValue returns id, null for fields without one.
+func fieldIDValue(id int) types.Int64 {
+	if id == 0 {
+		return types.Int64Null()
+	}
+
+	return types.Int64Value(int64(id))
+}
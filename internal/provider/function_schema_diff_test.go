@@ -0,0 +1,150 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	schemaDiffTestOld = `{"type": "struct", "schema-id": 0, "fields": [
+  {"id": 1, "name": "id", "type": "int", "required": true},
+  {"id": 2, "name": "email", "type": "string", "required": false},
+  {"id": 3, "name": "legacy", "type": "string", "required": false}
+]}`
+	schemaDiffTestNew = `{"type": "struct", "schema-id": 1, "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "email_address", "type": "string", "required": false},
+  {"id": 4, "name": "created_at", "type": "timestamptz", "required": false}
+]}`
+)
+
+func runSchemaDiff(t *testing.T, oldJSON, newJSON string) (types.Object, *function.FuncError) {
+	t.Helper()
+
+	ctx := context.Background()
+	f := NewSchemaDiffFunction()
+	resp := function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(schemaDiffAttrTypes))}
+	f.Run(ctx, function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(oldJSON), types.StringValue(newJSON)}),
+	}, &resp)
+	if resp.Error != nil {
+		return types.Object{}, resp.Error
+	}
+
+	result, ok := resp.Result.Value().(types.Object)
+	require.True(t, ok)
+
+	return result, nil
+}
+
+func TestSchemaDiffFunction(t *testing.T) {
+	result, funcErr := runSchemaDiff(t, schemaDiffTestOld, schemaDiffTestNew)
+	require.Nil(t, funcErr)
+
+	field := func(id int64, name, typ string) attr.Value {
+		return types.ObjectValueMust(schemaDiffFieldAttrTypes, map[string]attr.Value{
+			"id": types.Int64Value(id), "name": types.StringValue(name), "type": types.StringValue(typ), "required": types.BoolValue(false),
+		})
+	}
+	attrs := result.Attributes()
+	assert.Equal(t, []attr.Value{field(4, "created_at", "timestamptz")}, attrs["added"].(types.List).Elements())
+	assert.Equal(t, []attr.Value{field(3, "legacy", "string")}, attrs["removed"].(types.List).Elements())
+	require.Len(t, attrs["renamed"].(types.List).Elements(), 1)
+	renamed := attrs["renamed"].(types.List).Elements()[0].(types.Object).Attributes()
+	assert.Equal(t, types.StringValue("email"), renamed["old_name"])
+	assert.Equal(t, types.StringValue("email_address"), renamed["new_name"])
+	require.Len(t, attrs["retyped"].(types.List).Elements(), 1)
+	retyped := attrs["retyped"].(types.List).Elements()[0].(types.Object).Attributes()
+	assert.Equal(t, types.StringValue("int"), retyped["old_type"])
+	assert.Equal(t, types.StringValue("long"), retyped["new_type"])
+
+	t.Run("no changes", func(t *testing.T) {
+		result, funcErr := runSchemaDiff(t, schemaDiffTestOld, schemaDiffTestOld)
+		require.Nil(t, funcErr)
+		for name, value := range result.Attributes() {
+			list := value.(types.List)
+			assert.False(t, list.IsNull(), name)
+			assert.Empty(t, list.Elements(), name)
+		}
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, funcErr := runSchemaDiff(t, schemaDiffTestOld, `{"type": "struct", "fields": [{"id": 1, "name": "x", "type": "varchar"}]}`)
+		require.NotNil(t, funcErr)
+		assert.Equal(t, int64(1), *funcErr.FunctionArgument)
+		assert.Contains(t, funcErr.Text, "new_json isn't a valid Iceberg schema")
+	})
+}
+
+func TestAccSchemaDiffFunction(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Provider-defined functions
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(providerConfig, server.URL) + fmt.Sprintf(`
+locals {
+  diff = provider::iceberg::schema_diff(%q, %q)
+}
+
+output "removed" {
+  value = join(",", [for f in local.diff.removed : f.name])
+}
+
+output "renamed_to" {
+  value = local.diff.renamed[0].new_name
+}
+`, schemaDiffTestOld, schemaDiffTestNew),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("removed", "legacy"),
+					resource.TestCheckOutput("renamed_to", "email_address"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(providerConfig, server.URL) + fmt.Sprintf(`
+output "drops" {
+  value = provider::iceberg::schema_diff(%q, %q)
+
+  precondition {
+    condition     = length(provider::iceberg::schema_diff(%[1]q, %[2]q).removed) == 0
+    error_message = "Columns can't be dropped."
+  }
+}
+`, schemaDiffTestOld, schemaDiffTestNew),
+				ExpectError: regexp.MustCompile("Columns can't be dropped"),
+			},
+		},
+	})
+}
@@ -0,0 +1,80 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &schemaFingerprintFunction{}
+
+func NewSchemaFingerprintFunction() function.Function {
+	return &schemaFingerprintFunction{}
+}
+
+// schemaFingerprintFunction hashes an Iceberg schema JSON document, so
+// pipelines can tell whether a schema changed by comparing a short string.
+type schemaFingerprintFunction struct{}
+
+func (f *schemaFingerprintFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "schema_fingerprint"
+}
+
+func (f *schemaFingerprintFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Returns a stable hash of an Iceberg schema.",
+		Description: "Returns the SHA-256 of the canonical form of schema_json as a lowercase hex string. " +
+			"The canonical form doesn't depend on the order of the JSON keys, on whitespace or on how types are spelled, for example decimal(10,2) and decimal(10, 2), and leaves out the schema ID, so the fingerprint only changes when a field is added, removed, renamed, retyped or reordered, or its required flag, doc or defaults change. " +
+			"Identifier fields are included by name.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "schema_json",
+				Description: "The schema as Iceberg schema JSON, for example from table metadata.",
+			},
+			function.BoolParameter{
+				Name:        "include_ids",
+				Description: "Whether field IDs are part of the fingerprint. Without them, schemas that only number their fields differently, such as the same schema in two catalogs, have the same fingerprint.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *schemaFingerprintFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var schemaJSON string
+	var includeIDs bool
+	resp.Error = req.Arguments.Get(ctx, &schemaJSON, &includeIDs)
+	if resp.Error != nil {
+		return
+	}
+
+	schema, err := parseSchemaJSON(schemaJSON)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "schema_json isn't a valid Iceberg schema: "+err.Error())
+
+		return
+	}
+	fingerprint, err := schemaFingerprint(schema, includeIDs)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "schema_json isn't a valid Iceberg schema: "+err.Error())
+
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, fingerprint)
+}
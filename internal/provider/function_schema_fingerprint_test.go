@@ -0,0 +1,198 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const schemaFingerprintTestSchema = `{"type": "struct", "schema-id": 0, "identifier-field-ids": [1], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false, "doc": "In cents"},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false},
+  {"id": 5, "name": "attributes", "type": {"type": "map", "key-id": 6, "key": "string", "value-id": 7, "value": {"type": "struct", "fields": [
+    {"id": 8, "name": "value", "type": "string", "required": false}
+  ]}, "value-required": true}, "required": false}
+]}`
+
+func runSchemaFingerprint(t *testing.T, schemaJSON string, includeIDs bool) (string, *function.FuncError) {
+	t.Helper()
+
+	ctx := context.Background()
+	f := NewSchemaFingerprintFunction()
+	resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+	f.Run(ctx, function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(schemaJSON), types.BoolValue(includeIDs)}),
+	}, &resp)
+	if resp.Error != nil {
+		return "", resp.Error
+	}
+
+	result, ok := resp.Result.Value().(types.String)
+	require.True(t, ok)
+
+	return result.ValueString(), nil
+}
+
+func TestSchemaFingerprintFunction(t *testing.T) {
+	want, funcErr := runSchemaFingerprint(t, schemaFingerprintTestSchema, true)
+	require.Nil(t, funcErr)
+	assert.Regexp(t, `^[0-9a-f]{64}$`, want)
+	withoutIDs, funcErr := runSchemaFingerprint(t, schemaFingerprintTestSchema, false)
+	require.Nil(t, funcErr)
+	assert.NotEqual(t, want, withoutIDs)
+
+	t.Run("same schema", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			schema string
+		}{
+			{
+				name: "keys reordered and reformatted",
+				schema: `{"fields":[{"required":true,"type":"long","name":"id","id":1},` +
+					`{"doc":"In cents","type":"decimal(10,2)","name":"amount","id":2},` +
+					`{"name":"tags","id":3,"type":{"element":"string","element-required":false,"element-id":4,"type":"list"}},` +
+					`{"type":{"value-required":true,"value":{"fields":[{"type":"string","name":"value","id":8}],"type":"struct"},"value-id":7,"key":"string","key-id":6,"type":"map"},"name":"attributes","id":5}],` +
+					`"identifier-field-ids":[1],"type":"struct","schema-id":0}`,
+			},
+			{
+				name:   "another schema ID",
+				schema: strings.Replace(schemaFingerprintTestSchema, `"schema-id": 0`, `"schema-id": 7`, 1),
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				got, funcErr := runSchemaFingerprint(t, tc.schema, true)
+				require.Nil(t, funcErr)
+				assert.Equal(t, want, got)
+			})
+		}
+	})
+
+	t.Run("changed schema", func(t *testing.T) {
+		for _, tc := range []struct {
+			name   string
+			schema string
+		}{
+			{
+				name: "field added",
+				schema: strings.Replace(schemaFingerprintTestSchema, "\n]}", `,
+  {"id": 9, "name": "created_at", "type": "timestamptz", "required": false}
+]}`, 1),
+			},
+			{
+				name:   "field retyped",
+				schema: strings.Replace(schemaFingerprintTestSchema, `"decimal(10, 2)"`, `"decimal(12, 2)"`, 1),
+			},
+			{
+				name:   "nested field retyped",
+				schema: strings.Replace(schemaFingerprintTestSchema, `"name": "value", "type": "string"`, `"name": "value", "type": "binary"`, 1),
+			},
+			{
+				name:   "field renamed",
+				schema: strings.Replace(schemaFingerprintTestSchema, `"name": "tags"`, `"name": "labels"`, 1),
+			},
+			{
+				name:   "field made optional",
+				schema: strings.Replace(schemaFingerprintTestSchema, `"type": "long", "required": true`, `"type": "long", "required": false`, 1),
+			},
+			{
+				name:   "identifier fields",
+				schema: strings.Replace(schemaFingerprintTestSchema, `"identifier-field-ids": [1], `, "", 1),
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				require.NotEqual(t, schemaFingerprintTestSchema, tc.schema)
+				for _, includeIDs := range []bool{true, false} {
+					got, funcErr := runSchemaFingerprint(t, tc.schema, includeIDs)
+					require.Nil(t, funcErr)
+					assert.NotEqual(t, want, got)
+					assert.NotEqual(t, withoutIDs, got)
+				}
+			})
+		}
+	})
+
+	t.Run("field IDs", func(t *testing.T) {
+		renumbered := schemaFingerprintTestSchema
+		for _, r := range []struct{ old, new string }{
+			{`"identifier-field-ids": [1]`, `"identifier-field-ids": [11]`},
+			{`"id": 1,`, `"id": 11,`},
+			{`"id": 2,`, `"id": 12,`},
+			{`"element-id": 4`, `"element-id": 14`},
+			{`"value-id": 7`, `"value-id": 17`},
+		} {
+			renumbered = strings.Replace(renumbered, r.old, r.new, 1)
+		}
+
+		got, funcErr := runSchemaFingerprint(t, renumbered, false)
+		require.Nil(t, funcErr)
+		assert.Equal(t, withoutIDs, got, "IDs are left out")
+		got, funcErr = runSchemaFingerprint(t, renumbered, true)
+		require.Nil(t, funcErr)
+		assert.NotEqual(t, want, got, "IDs are included")
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, funcErr := runSchemaFingerprint(t, `{"type": "struct", "fields": [{"id": 1, "name": "x", "type": "varchar"}]}`, true)
+		require.NotNil(t, funcErr)
+		assert.Equal(t, int64(0), *funcErr.FunctionArgument)
+		assert.Contains(t, funcErr.Text, "schema_json isn't a valid Iceberg schema")
+	})
+
+	t.Run("unknown identifier field", func(t *testing.T) {
+		_, funcErr := runSchemaFingerprint(t, `{"type": "struct", "identifier-field-ids": [2], "fields": [{"id": 1, "name": "x", "type": "long", "required": true}]}`, true)
+		require.NotNil(t, funcErr)
+		assert.Contains(t, funcErr.Text, "identifier field ID 2 isn't a field of the schema")
+	})
+}
+
+func TestAccSchemaFingerprintFunction(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	want, funcErr := runSchemaFingerprint(t, schemaFingerprintTestSchema, false)
+	require.Nil(t, funcErr)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// Provider-defined functions
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(providerConfig, server.URL) + fmt.Sprintf(`
+output "fingerprint" {
+  value = provider::iceberg::schema_fingerprint(%q, false)
+}
+`, schemaFingerprintTestSchema),
+				Check: resource.TestCheckOutput("fingerprint", want),
+			},
+		},
+	})
+}
@@ -0,0 +1,177 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The project and OAuth2 token properties, named as in PyIceberg. iceberg-go
+// has no constants for them.
+const (
+	gcsProjectID   = "gcs.project-id"
+	gcsOAuth2Token = "gcs.oauth2.token"
+)
+
+// gcsCredentialsEnvVar is where Application Default Credentials take a key
+// file from. The GCS FileIO of iceberg-go authenticates its HTTP client with
+// them, so a key file is exported to it as well as passed in gcs.keypath.
+const gcsCredentialsEnvVar = "GOOGLE_APPLICATION_CREDENTIALS"
+
+// gcsCredentialTypes are the credential file types the GCS FileIO accepts in
+// gcs.credtype.
+var gcsCredentialTypes = []string{"service_account", "authorized_user", "impersonated_service_account", "external_account"}
+
+// gcsModel is the gcs block, the Google Cloud Storage FileIO configuration
+// the provider writes and reads table metadata files with, like the s3 block.
+type gcsModel struct {
+	ProjectID          types.String `tfsdk:"project_id"`
+	ServiceAccountJSON types.String `tfsdk:"service_account_json"`
+	OAuthToken         types.String `tfsdk:"oauth_token"`
+	Endpoint           types.String `tfsdk:"endpoint"`
+}
+
+// gcsBlock returns the schema of the gcs block.
+func gcsBlock() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "Google Cloud Storage settings for the table metadata files the provider writes and reads itself with type = 'glue', 'sql' or 'memory', for warehouses such as gs://bucket/warehouse. REST catalogs write them on the server, so the block isn't allowed with them. Without it, Application Default Credentials are used.",
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				Description: "The Google Cloud project of the buckets.",
+				Optional:    true,
+			},
+			"service_account_json": schema.StringAttribute{
+				Description: "A credentials file, usually a service account key, either as JSON or as the path of the file. A path is also exported to the GOOGLE_APPLICATION_CREDENTIALS environment variable of the provider process, so all provider configurations with a gcs block must use the same one. Can't be set with oauth_token.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"oauth_token": schema.StringAttribute{
+				Description: "An OAuth2 access token. Can't be set with service_account_json.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"endpoint": schema.StringAttribute{
+				Description: "The URL of a GCS-compatible service such as fake-gcs-server, for example http://localhost:4443/storage/v1/. Objects are then read through the JSON API.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// gcsConfig returns the FileIO properties of the gcs block of data, nil if it
+// isn't set. Invalid settings are reported as errors.
+func gcsConfig(data icebergProviderModel, diags *diag.Diagnostics) iceberg.Properties {
+	if data.GCS == nil {
+		return nil
+	}
+	gcs := data.GCS
+
+	if !gcs.ServiceAccountJSON.IsNull() && !gcs.OAuthToken.IsNull() {
+		diags.AddAttributeError(path.Root("gcs"), "Invalid gcs configuration",
+			"gcs.service_account_json and gcs.oauth_token can't be set together.")
+	}
+	if endpoint := gcs.Endpoint.ValueString(); endpoint != "" {
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			diags.AddAttributeError(path.Root("gcs").AtName("endpoint"), "Invalid gcs configuration",
+				"gcs.endpoint must be an http:// or https:// URL, got "+strconv.Quote(endpoint)+".")
+		}
+	}
+	var credentials iceberg.Properties
+	if key := gcs.ServiceAccountJSON.ValueString(); key != "" {
+		var err error
+		if credentials, err = gcsCredentials(key); err != nil {
+			diags.AddAttributeError(path.Root("gcs").AtName("service_account_json"), "Invalid gcs configuration",
+				"gcs.service_account_json: "+err.Error()+".")
+		}
+	}
+	if diags.HasError() {
+		return nil
+	}
+
+	props := iceberg.Properties{}
+	for key, value := range map[string]types.String{
+		gcsProjectID:      gcs.ProjectID,
+		gcsOAuth2Token:    gcs.OAuthToken,
+		iceio.GCSEndpoint: gcs.Endpoint,
+	} {
+		if value.ValueString() != "" {
+			props[key] = value.ValueString()
+		}
+	}
+	// Emulators serve object reads on the endpoint only through the JSON API.
+	if _, ok := props[iceio.GCSEndpoint]; ok {
+		props[iceio.GCSUseJSONAPI] = "true"
+	}
+	maps.Copy(props, credentials)
+
+	return props
+}
+
+// gcsCredentials returns the credential properties of service_account_json,
+// which is either the JSON of a credentials file or its path.
+func gcsCredentials(key string) (iceberg.Properties, error) {
+	inline := strings.HasPrefix(strings.TrimSpace(key), "{")
+	content := []byte(key)
+	if !inline {
+		var err error
+		if content, err = os.ReadFile(key); err != nil {
+			return nil, fmt.Errorf("failed to read the credentials file: %w", err)
+		}
+	}
+
+	var file struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid credentials JSON: %w", err)
+	}
+	if !slices.Contains(gcsCredentialTypes, file.Type) {
+		return nil, fmt.Errorf("unsupported credentials type %q, expected one of %s", file.Type, strings.Join(gcsCredentialTypes, ", "))
+	}
+
+	props := iceberg.Properties{iceio.GCSCredType: file.Type}
+	if inline {
+		props[iceio.GCSJSONKey] = key
+	} else {
+		props[iceio.GCSKeyPath] = key
+	}
+
+	return props, nil
+}
+
+// exportGCSCredentials sets the environment variable Application Default
+// Credentials read a key file from to the key file of props, if any.
+func exportGCSCredentials(props iceberg.Properties) error {
+	if keyPath, ok := props[iceio.GCSKeyPath]; ok {
+		return os.Setenv(gcsCredentialsEnvVar, keyPath)
+	}
+
+	return nil
+}
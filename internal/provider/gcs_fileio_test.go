@@ -0,0 +1,249 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gcsBlockValue returns a gcs block of the provider schema with attrs set.
+func gcsBlockValue(attrs map[string]tftypes.Value) tftypes.Value {
+	attrTypes := map[string]tftypes.Type{
+		"project_id":           tftypes.String,
+		"service_account_json": tftypes.String,
+		"oauth_token":          tftypes.String,
+		"endpoint":             tftypes.String,
+	}
+	values := make(map[string]tftypes.Value, len(attrTypes))
+	for name, typ := range attrTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	maps.Copy(values, attrs)
+
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: attrTypes}, values)
+}
+
+func TestProviderGCSConfig(t *testing.T) {
+	// A key file is exported to it; t.Setenv restores it.
+	t.Setenv(gcsCredentialsEnvVar, "")
+
+	serviceAccount := `{"type": "service_account", "project_id": "lake", "client_email": "tf@lake.iam.gserviceaccount.com"}`
+	keyPath := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(keyPath, []byte(serviceAccount), 0o600))
+	notJSONPath := filepath.Join(t.TempDir(), "key.txt")
+	require.NoError(t, os.WriteFile(notJSONPath, []byte("not json"), 0o600))
+
+	for _, tc := range []struct {
+		name      string
+		gcs       map[string]tftypes.Value
+		want      iceberg.Properties
+		wantError string
+	}{
+		{
+			name: "inline service account",
+			gcs: map[string]tftypes.Value{
+				"project_id":           tftypes.NewValue(tftypes.String, "lake"),
+				"service_account_json": tftypes.NewValue(tftypes.String, serviceAccount),
+			},
+			want: iceberg.Properties{
+				"gcs.project-id": "lake",
+				"gcs.jsonkey":    serviceAccount,
+				"gcs.credtype":   "service_account",
+			},
+		},
+		{
+			name: "service account file",
+			gcs: map[string]tftypes.Value{
+				"service_account_json": tftypes.NewValue(tftypes.String, keyPath),
+			},
+			want: iceberg.Properties{
+				"gcs.keypath":  keyPath,
+				"gcs.credtype": "service_account",
+			},
+		},
+		{
+			name: "oauth token",
+			gcs: map[string]tftypes.Value{
+				"oauth_token": tftypes.NewValue(tftypes.String, "ya29.token"),
+			},
+			want: iceberg.Properties{
+				"gcs.oauth2.token": "ya29.token",
+			},
+		},
+		{
+			name: "emulator",
+			gcs: map[string]tftypes.Value{
+				"endpoint": tftypes.NewValue(tftypes.String, "http://localhost:4443/storage/v1/"),
+			},
+			want: iceberg.Properties{
+				"gcs.endpoint":   "http://localhost:4443/storage/v1/",
+				"gcs.usejsonapi": "true",
+			},
+		},
+		{
+			name: "empty block",
+			gcs:  map[string]tftypes.Value{},
+			want: iceberg.Properties{},
+		},
+		{
+			name: "service account and oauth token",
+			gcs: map[string]tftypes.Value{
+				"service_account_json": tftypes.NewValue(tftypes.String, serviceAccount),
+				"oauth_token":          tftypes.NewValue(tftypes.String, "ya29.token"),
+			},
+			wantError: "gcs.service_account_json and gcs.oauth_token can't be set together.",
+		},
+		{
+			name: "missing key file",
+			gcs: map[string]tftypes.Value{
+				"service_account_json": tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "missing.json")),
+			},
+			wantError: "gcs.service_account_json: failed to read the credentials file",
+		},
+		{
+			name: "key file isn't JSON",
+			gcs: map[string]tftypes.Value{
+				"service_account_json": tftypes.NewValue(tftypes.String, notJSONPath),
+			},
+			wantError: "gcs.service_account_json: invalid credentials JSON",
+		},
+		{
+			name: "invalid inline JSON",
+			gcs: map[string]tftypes.Value{
+				"service_account_json": tftypes.NewValue(tftypes.String, `{"type": `),
+			},
+			wantError: "gcs.service_account_json: invalid credentials JSON",
+		},
+		{
+			name: "unsupported credentials type",
+			gcs: map[string]tftypes.Value{
+				"service_account_json": tftypes.NewValue(tftypes.String, `{"type": "api_key"}`),
+			},
+			wantError: `gcs.service_account_json: unsupported credentials type "api_key"`,
+		},
+		{
+			name: "invalid endpoint",
+			gcs: map[string]tftypes.Value{
+				"endpoint": tftypes.NewValue(tftypes.String, "localhost:4443"),
+			},
+			wantError: `gcs.endpoint must be an http:// or https:// URL, got "localhost:4443".`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, diags := configureTestProvider(t, map[string]tftypes.Value{
+				"type": tftypes.NewValue(tftypes.String, "memory"),
+				"gcs":  gcsBlockValue(tc.gcs),
+			})
+			if tc.wantError != "" {
+				require.True(t, diags.HasError())
+				assert.Equal(t, "Invalid gcs configuration", diags.Errors()[0].Summary())
+				assert.True(t, strings.HasPrefix(diags.Errors()[0].Detail(), tc.wantError), diags.Errors()[0].Detail())
+
+				return
+			}
+			require.False(t, diags.HasError(), "%v", diags)
+			assert.Equal(t, tc.want, p.fileIO)
+		})
+	}
+
+	t.Run("key file exported", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "memory"),
+			"gcs": gcsBlockValue(map[string]tftypes.Value{
+				"service_account_json": tftypes.NewValue(tftypes.String, keyPath),
+			}),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, keyPath, os.Getenv(gcsCredentialsEnvVar))
+	})
+
+	t.Run("inline key not exported", func(t *testing.T) {
+		t.Setenv(gcsCredentialsEnvVar, "")
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "memory"),
+			"gcs": gcsBlockValue(map[string]tftypes.Value{
+				"service_account_json": tftypes.NewValue(tftypes.String, serviceAccount),
+			}),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Empty(t, os.Getenv(gcsCredentialsEnvVar))
+	})
+
+	t.Run("rest catalog", func(t *testing.T) {
+		var diags diag.Diagnostics
+		validateCatalogTypeConfig(icebergProviderModel{
+			Type:       types.StringValue("rest"),
+			CatalogURI: types.StringValue("http://localhost:8181"),
+			GCS:        &gcsModel{},
+		}, &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Invalid gcs configuration", diags.Errors()[0].Summary())
+	})
+}
+
+func TestAccIcebergTable_GCS(t *testing.T) {
+	endpoint := os.Getenv("ICEBERG_GCS_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("ICEBERG_GCS_ENDPOINT isn't set")
+	}
+
+	// fake-gcs-server starts without buckets.
+	resp, err := http.Post(strings.TrimSuffix(endpoint, "/")+"/b?project=tf-acc", "application/json", strings.NewReader(`{"name": "warehouse"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Contains(t, []int{http.StatusOK, http.StatusConflict}, resp.StatusCode)
+
+	providerCfg := fmt.Sprintf(`
+provider "iceberg" {
+  type      = "sql"
+  sql_dsn   = "sqlite::memory:"
+  warehouse = "gs://warehouse/tf_acc_gcs"
+
+  gcs {
+    project_id = "tf-acc"
+    endpoint   = %q
+  }
+}
+`, endpoint)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIcebergTableResourceConfig(providerCfg, "db1", "gcs_table"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.test", "name", "gcs_table"),
+					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.#", "2"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,210 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"maps"
+	"net/http"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/catalog/glue"
+	// Glue catalogs write table metadata to object storage themselves.
+	_ "github.com/apache/iceberg-go/io/gocloud"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// glueConfig holds what the AWS Glue Data Catalog client needs with
+// type = "glue".
+type glueConfig struct {
+	awsConfig aws.Config
+	// catalogID is the ID of the Glue Data Catalog, empty for the catalog of
+	// the account of the credentials.
+	catalogID string
+}
+
+// loadGlueConfig returns the Glue configuration for the glue_* provider
+// attributes of data. Credentials are the static ones of the glue_*
+// attributes if set, otherwise the standard AWS chain: environment, shared
+// config and credentials files, and container or instance roles.
+func loadGlueConfig(ctx context.Context, data icebergProviderModel, diags *diag.Diagnostics) *glueConfig {
+	var opts []func(*config.LoadOptions) error
+	if region := data.GlueRegion.ValueString(); region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	if key := data.GlueAccessKeyID.ValueString(); key != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(key, data.GlueSecretKey.ValueString(), data.GlueSessionToken.ValueString())))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		diags.AddError("Invalid Glue configuration", "Failed to load the AWS configuration: "+err.Error())
+
+		return nil
+	}
+	if cfg.Region == "" {
+		diags.AddAttributeError(
+			path.Root("glue_region"),
+			"Invalid Glue configuration",
+			"type is 'glue', but no region is set. Set glue_region or the AWS_REGION environment variable.",
+		)
+
+		return nil
+	}
+
+	return &glueConfig{awsConfig: cfg, catalogID: data.GlueCatalogID.ValueString()}
+}
+
+// nullAttribute is a provider attribute and whether it is null.
+type nullAttribute struct {
+	name string
+	null bool
+}
+
+// restOnlyAttributes returns the attributes of data that only apply to REST
+// catalogs.
+func restOnlyAttributes(data icebergProviderModel) []nullAttribute {
+	return []nullAttribute{
+		{"catalog_uri", data.CatalogURI.IsNull()},
+		{"token", data.Token.IsNull()},
+		{"oauth2_client_id", data.OAuth2ClientID.IsNull()},
+		{"credential", data.Credential.IsNull()},
+		{"oauth2_scope", data.OAuth2Scope.IsNull()},
+		{"oauth2_audience", data.OAuth2Audience.IsNull()},
+		{"basic_auth_username", data.BasicAuthUsername.IsNull()},
+		{"basic_auth_password", data.BasicAuthPassword.IsNull()},
+		{"auth_header_name", data.AuthHeaderName.IsNull()},
+		{"auth_header_value", data.AuthHeaderValue.IsNull()},
+		{"sigv4_enabled", data.SigV4Enabled.IsNull()},
+		{"uri_prefix", data.URIPrefix.IsNull()},
+		{"access_delegation", data.AccessDelegation.IsNull()},
+		{"validate_on_plan", data.ValidateOnPlan.IsNull()},
+		{"polaris_settings", data.PolarisSettings == nil},
+	}
+}
+
+// validateCatalogTypeConfig checks that catalog_uri is set for REST
+// catalogs, and that the glue_* attributes are only set with type = "glue",
+// sql_dsn only with type = "sql" and the s3, adls and gcs blocks only with the
+// catalogs whose metadata files the provider writes. The attributes of REST
+// catalogs aren't allowed with "glue", "sql" or "memory".
+func validateCatalogTypeConfig(data icebergProviderModel, diags *diag.Diagnostics) {
+	if data.Type.IsUnknown() {
+		return
+	}
+	switch data.Type.ValueString() {
+	case "glue":
+	case "sql", "memory":
+		validateSQLConfig(data.Type.ValueString(), data, diags)
+
+		return
+	default:
+		if data.CatalogURI.IsNull() {
+			diags.AddAttributeError(path.Root("catalog_uri"), "Missing catalog_uri",
+				"catalog_uri is required unless type is 'glue', 'sql' or 'memory'.")
+		}
+		if glueAttributesSet(data) {
+			diags.AddError("Invalid Glue configuration",
+				"The glue_* attributes only apply with type = 'glue'. Set type or remove them.")
+		}
+		if !data.SQLDSN.IsNull() {
+			diags.AddAttributeError(path.Root("sql_dsn"), "Invalid SQL configuration",
+				"sql_dsn only applies with type = 'sql'. Set type or remove it.")
+		}
+		for _, block := range []nullAttribute{{"s3", data.S3 == nil}, {"adls", data.ADLS == nil}, {"gcs", data.GCS == nil}} {
+			if !block.null {
+				diags.AddAttributeError(path.Root(block.name), "Invalid "+block.name+" configuration",
+					"The "+block.name+" block only applies with type = 'glue', 'sql' or 'memory'. REST catalogs write table metadata files on the server; remove it from the configuration.")
+			}
+		}
+
+		return
+	}
+
+	if !data.SQLDSN.IsNull() {
+		diags.AddAttributeError(path.Root("sql_dsn"), "Invalid SQL configuration",
+			"sql_dsn only applies with type = 'sql'. Set type or remove it.")
+	}
+
+	if data.GlueAccessKeyID.IsNull() != data.GlueSecretKey.IsNull() {
+		diags.AddError(
+			"Invalid Glue configuration",
+			"glue_access_key_id and glue_secret_access_key must be set together.",
+		)
+	}
+	if !data.GlueSessionToken.IsNull() && data.GlueAccessKeyID.IsNull() {
+		diags.AddAttributeError(
+			path.Root("glue_session_token"),
+			"Invalid Glue configuration",
+			"glue_session_token requires glue_access_key_id and glue_secret_access_key.",
+		)
+	}
+
+	for _, attr := range append(restOnlyAttributes(data), nullAttribute{"warehouse", data.Warehouse.IsNull()}) {
+		if !attr.null {
+			diags.AddAttributeError(path.Root(attr.name), "Attribute not supported with type 'glue'",
+				attr.name+" only applies to REST catalogs. The Glue Data Catalog is reached through the AWS API; remove it from the configuration.")
+		}
+	}
+}
+
+// glueAttributesSet reports whether any glue_* attribute is set in data.
+func glueAttributesSet(data icebergProviderModel) bool {
+	return !data.GlueRegion.IsNull() || !data.GlueCatalogID.IsNull() || !data.GlueAccessKeyID.IsNull() ||
+		!data.GlueSecretKey.IsNull() || !data.GlueSessionToken.IsNull()
+}
+
+// newGlueCatalog returns a client of the Glue Data Catalog. Requests go
+// through the base transport, so ca_cert_pem and ca_cert_file apply; the AWS
+// SDK retries throttled requests itself, so with enable_operation_metrics and
+// audit_log_path every attempt is counted and recorded. The metadata files of new tables are written with the s3, adls
+// and gcs blocks; iceberg-go reads those of existing tables with the AWS
+// configuration of the Glue client, which ignores them.
+func (p *icebergProvider) newGlueCatalog(resourceType string) catalog.Catalog {
+	cfg := p.glue.awsConfig.Copy()
+	transport := p.baseTransport()
+	if p.metrics != nil {
+		transport = &metricsTransport{next: transport, metrics: p.metrics, resourceType: resourceType}
+	}
+	cfg.HTTPClient = &http.Client{Transport: p.withAuditLog(transport, resourceType)}
+
+	props := glue.AwsProperties(maps.Clone(p.fileIO))
+	if props == nil {
+		props = glue.AwsProperties{}
+	}
+	if p.glue.catalogID != "" {
+		props[glue.CatalogIdKey] = p.glue.catalogID
+	}
+
+	if p.capabilities != nil {
+		p.capabilities.setServer("glue")
+	}
+
+	return glue.NewCatalog(glue.WithAwsConfig(cfg), glue.WithAwsProperties(props))
+}
+
+// endpoint returns the URL of the Glue API the catalog client calls.
+func (c *glueConfig) endpoint() string {
+	if c.awsConfig.BaseEndpoint != nil {
+		return *c.awsConfig.BaseEndpoint
+	}
+
+	return "https://glue." + c.awsConfig.Region + ".amazonaws.com"
+}
@@ -0,0 +1,265 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/apache/iceberg-go/catalog/glue"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCatalogTypeConfig(t *testing.T) {
+	glueType := types.StringValue("glue")
+
+	for _, tc := range []struct {
+		name       string
+		data       icebergProviderModel
+		wantErrors []string
+	}{
+		{
+			name: "rest",
+			data: icebergProviderModel{CatalogURI: types.StringValue("http://localhost:8181")},
+		},
+		{
+			name:       "rest without catalog_uri",
+			data:       icebergProviderModel{Type: types.StringValue("polaris")},
+			wantErrors: []string{"Missing catalog_uri"},
+		},
+		{
+			name:       "glue attributes on rest",
+			data:       icebergProviderModel{CatalogURI: types.StringValue("http://localhost:8181"), GlueRegion: types.StringValue("eu-west-1")},
+			wantErrors: []string{"Invalid Glue configuration"},
+		},
+		{
+			name: "glue",
+			data: icebergProviderModel{Type: glueType, GlueRegion: types.StringValue("eu-west-1"), GlueAccessKeyID: types.StringValue("AKID"), GlueSecretKey: types.StringValue("SECRET")},
+		},
+		{
+			// read_only is checked by the resources, whatever the catalog.
+			name: "glue with read_only",
+			data: icebergProviderModel{Type: glueType, ReadOnly: types.BoolValue(true)},
+		},
+		{
+			name:       "glue with partial credentials",
+			data:       icebergProviderModel{Type: glueType, GlueAccessKeyID: types.StringValue("AKID"), GlueSessionToken: types.StringValue("TOKEN")},
+			wantErrors: []string{"Invalid Glue configuration"},
+		},
+		{
+			name:       "glue with session token only",
+			data:       icebergProviderModel{Type: glueType, GlueSessionToken: types.StringValue("TOKEN")},
+			wantErrors: []string{"Invalid Glue configuration"},
+		},
+		{
+			name: "glue with rest attributes",
+			data: icebergProviderModel{Type: glueType, CatalogURI: types.StringValue("http://localhost:8181"), Token: types.StringValue("s3cr3t")},
+			wantErrors: []string{
+				"Attribute not supported with type 'glue'",
+				"Attribute not supported with type 'glue'",
+			},
+		},
+		{
+			name: "sql",
+			data: icebergProviderModel{Type: types.StringValue("sql"), SQLDSN: types.StringValue("sqlite::memory:"), Warehouse: types.StringValue("file:///tmp/warehouse")},
+		},
+		{
+			name:       "sql without sql_dsn",
+			data:       icebergProviderModel{Type: types.StringValue("sql")},
+			wantErrors: []string{"Missing sql_dsn"},
+		},
+		{
+			name:       "sql_dsn on rest",
+			data:       icebergProviderModel{CatalogURI: types.StringValue("http://localhost:8181"), SQLDSN: types.StringValue("sqlite::memory:")},
+			wantErrors: []string{"Invalid SQL configuration"},
+		},
+		{
+			name: "sql with rest attributes",
+			data: icebergProviderModel{Type: types.StringValue("sql"), SQLDSN: types.StringValue("sqlite::memory:"), CatalogURI: types.StringValue("http://localhost:8181"), AuditLogPath: types.StringValue("audit.log")},
+			wantErrors: []string{
+				"Attribute not supported with type 'sql'",
+				"Attribute not supported with type 'sql'",
+			},
+		},
+		{
+			name: "memory",
+			data: icebergProviderModel{Type: types.StringValue("memory")},
+		},
+		{
+			name: "memory with sql_dsn and rest attributes",
+			data: icebergProviderModel{Type: types.StringValue("memory"), SQLDSN: types.StringValue("sqlite::memory:"), CatalogURI: types.StringValue("http://localhost:8181")},
+			wantErrors: []string{
+				"Invalid SQL configuration",
+				"Attribute not supported with type 'memory'",
+			},
+		},
+		{
+			name: "unknown type",
+			data: icebergProviderModel{Type: types.StringUnknown()},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateCatalogTypeConfig(tc.data, &diags)
+
+			var got []string
+			for _, d := range diags.Errors() {
+				got = append(got, d.Summary())
+			}
+			assert.Equal(t, tc.wantErrors, got)
+		})
+	}
+}
+
+func TestProviderGlue(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "ENVKEY")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "ENVSECRET")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv(tokenEnvVar, "from-env")
+	ctx := context.Background()
+
+	t.Run("static credentials", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":                   tftypes.NewValue(tftypes.String, "glue"),
+			"glue_region":            tftypes.NewValue(tftypes.String, "eu-west-1"),
+			"glue_catalog_id":        tftypes.NewValue(tftypes.String, "123456789012"),
+			"glue_access_key_id":     tftypes.NewValue(tftypes.String, "AKID"),
+			"glue_secret_access_key": tftypes.NewValue(tftypes.String, "SECRET"),
+			"glue_session_token":     tftypes.NewValue(tftypes.String, "TOKEN"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.NotNil(t, p.glue)
+		assert.Equal(t, "glue", p.catalogType)
+		assert.Equal(t, "eu-west-1", p.glue.awsConfig.Region)
+		assert.Equal(t, "123456789012", p.glue.catalogID)
+		assert.Equal(t, "https://glue.eu-west-1.amazonaws.com", p.glue.endpoint())
+		assert.Empty(t, p.token, "ICEBERG_TOKEN doesn't apply to Glue")
+
+		creds, err := p.glue.awsConfig.Credentials.Retrieve(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "AKID", creds.AccessKeyID)
+		assert.Equal(t, "SECRET", creds.SecretAccessKey)
+		assert.Equal(t, "TOKEN", creds.SessionToken)
+
+		cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+		require.NoError(t, err)
+		assert.IsType(t, &glue.Catalog{}, cat)
+		assert.Equal(t, "glue", p.capabilities.serverImplementation())
+		assert.True(t, p.catalogConfigured())
+	})
+
+	t.Run("default credentials and region from the environment", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "us-west-2")
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "glue"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.NotNil(t, p.glue)
+		assert.Equal(t, "us-west-2", p.glue.awsConfig.Region)
+		assert.Empty(t, p.glue.catalogID)
+
+		creds, err := p.glue.awsConfig.Credentials.Retrieve(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "ENVKEY", creds.AccessKeyID)
+	})
+
+	t.Run("missing region", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "glue"),
+		})
+		require.Len(t, diags.Errors(), 1)
+		assert.Contains(t, diags.Errors()[0].Detail(), "no region is set")
+	})
+
+	t.Run("rest", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, "http://localhost:8181"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Nil(t, p.glue)
+		assert.Equal(t, "rest", p.catalogType)
+	})
+}
+
+// TestAccIcebergNamespace_Glue manages a namespace and a table in the AWS
+// Glue Data Catalog of the account and region of the standard AWS
+// configuration. It only runs with AWS_GLUE_TEST set, and needs the
+// ICEBERG_GLUE_WAREHOUSE environment variable to hold an S3 location the
+// credentials can write table metadata to.
+func TestAccIcebergNamespace_Glue(t *testing.T) {
+	if os.Getenv("AWS_GLUE_TEST") == "" {
+		t.Skip("AWS_GLUE_TEST isn't set")
+	}
+	warehouse := os.Getenv("ICEBERG_GLUE_WAREHOUSE")
+	if warehouse == "" {
+		t.Fatal("ICEBERG_GLUE_WAREHOUSE must be set to an S3 location for AWS_GLUE_TEST")
+	}
+
+	config := func(description string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  type = "glue"
+}
+
+resource "iceberg_namespace" "test" {
+  name = ["tf_acc_glue"]
+  user_properties = {
+    "description" = %q
+    "location"    = "%s/tf_acc_glue.db"
+  }
+}
+
+resource "iceberg_table" "test" {
+  namespace = iceberg_namespace.test.name
+  name      = "events"
+  schema = {
+    fields = [
+      { name = "id", type = "long", required = true },
+      { name = "payload", type = "string", required = false },
+    ]
+  }
+}
+`, description, warehouse)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("created by terraform"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.test", "server_properties.description", "created by terraform"),
+					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.#", "2"),
+				),
+			},
+			{
+				Config: config("updated by terraform"),
+				Check:  resource.TestCheckResourceAttr("iceberg_namespace.test", "server_properties.description", "updated by terraform"),
+			},
+		},
+	})
+}
@@ -0,0 +1,259 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// httpLogRedacted replaces the values of headers, query parameters and body
+// fields that may hold credentials in the logs.
+const httpLogRedacted = "REDACTED"
+
+// httpLogBodyLimit is the number of bytes of a request or response body
+// logged at most.
+const httpLogBodyLimit = 64 << 10
+
+// httpLogLevelEnvVars are the environment variables that set the level of
+// the provider logs, most specific first. TF_LOG_PROVIDER_ICEBERG is named
+// after the provider address, see main.go.
+var httpLogLevelEnvVars = []string{"TF_LOG_PROVIDER_ICEBERG", "TF_LOG_PROVIDER", "TF_LOG"}
+
+// sensitiveNameFragments are the parts of header, parameter and field names,
+// lowercased and without separators, whose values are redacted.
+var sensitiveNameFragments = []string{
+	"authorization", "token", "secret", "password", "credential", "cookie",
+	"signature", "accesskey", "accountkey", "sharedkey", "privatekey", "jsonkey", "apikey",
+}
+
+// sensitiveNames are names whose values are redacted that are too short to be
+// matched as fragments, such as the signature of Azure SAS URLs.
+var sensitiveNames = []string{"sig"}
+
+// traceLogging reports whether the provider logs at TRACE level, which
+// TF_LOG=JSON implies.
+func traceLogging() bool {
+	for _, name := range httpLogLevelEnvVars {
+		if level := os.Getenv(name); level != "" {
+			return strings.EqualFold(level, "TRACE") || strings.EqualFold(level, "JSON")
+		}
+	}
+
+	return false
+}
+
+// httpLogTransport logs the method, URL, status and duration of every request
+// at DEBUG level and, with bodies, its headers and bodies at TRACE level, so
+// catalog incompatibilities can be debugged without a proxy. Credentials are
+// redacted: the Authorization header and other sensitive headers, and the
+// query parameters and the JSON and form fields with sensitive names.
+type httpLogTransport struct {
+	next http.RoundTripper
+	// bodies logs headers and bodies. Bodies are read into memory to be
+	// logged, so this is only set when TRACE logs are written.
+	bodies bool
+	// sensitiveHeaders are the canonical names of headers that are redacted
+	// whatever their name, such as auth_header_name.
+	sensitiveHeaders []string
+}
+
+func (t *httpLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	var requestBody string
+	if t.bodies && req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(ctx)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		requestBody = redactBody(req.Header.Get("Content-Type"), body)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields := map[string]any{
+		"http_method": req.Method,
+		"http_url":    redactURL(req.URL),
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "HTTP request failed", fields)
+
+		return resp, err
+	}
+	fields["http_status"] = resp.StatusCode
+	tflog.Debug(ctx, "HTTP request", fields)
+	if !t.bodies {
+		return resp, nil
+	}
+
+	fields["http_request_headers"] = redactHeaders(req.Header, t.sensitiveHeaders)
+	fields["http_request_body"] = requestBody
+	fields["http_response_headers"] = redactHeaders(resp.Header, t.sensitiveHeaders)
+	if resp.Body != nil {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		fields["http_response_body"] = redactBody(resp.Header.Get("Content-Type"), body)
+	}
+	tflog.Trace(ctx, "HTTP request and response", fields)
+
+	return resp, nil
+}
+
+// sensitiveName reports whether the value of a header, a query parameter or
+// a body field called name may hold credentials.
+func sensitiveName(name string) bool {
+	name = strings.NewReplacer("-", "", "_", "", ".", "").Replace(strings.ToLower(name))
+	if slices.Contains(sensitiveNames, name) {
+		return true
+	}
+	for _, fragment := range sensitiveNameFragments {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactURL returns u with its password and sensitive query parameters
+// redacted.
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	redacted := false
+	for name := range query {
+		if sensitiveName(name) {
+			query.Set(name, httpLogRedacted)
+			redacted = true
+		}
+	}
+	if !redacted {
+		return u.Redacted()
+	}
+
+	c := *u
+	c.RawQuery = query.Encode()
+
+	return c.Redacted()
+}
+
+// redactHeaders returns the headers of h with sensitive values redacted, and
+// those of the headers named in sensitive. The scheme of an Authorization
+// header is kept, so the kind of credentials sent shows.
+func redactHeaders(h http.Header, sensitive []string) map[string]string {
+	headers := make(map[string]string, len(h))
+	for name, values := range h {
+		value := strings.Join(values, ", ")
+		if slices.Contains(sensitive, name) {
+			value = httpLogRedacted
+		} else if sensitiveName(name) {
+			if scheme, _, ok := strings.Cut(value, " "); ok && strings.HasSuffix(name, "Authorization") {
+				value = scheme + " " + httpLogRedacted
+			} else {
+				value = httpLogRedacted
+			}
+		}
+		headers[name] = value
+	}
+
+	return headers
+}
+
+// redactBody returns body as logged: JSON and form bodies with the values of
+// sensitive fields redacted, and only the size of other bodies, which can't be
+// redacted reliably. Bodies longer than httpLogBodyLimit are truncated.
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	var logged string
+	switch {
+	case strings.Contains(mediaType, "json"):
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			return fmt.Sprintf("(%d bytes of invalid JSON)", len(body))
+		}
+		redacted, err := json.Marshal(redactJSON(doc))
+		if err != nil {
+			return fmt.Sprintf("(%d bytes of JSON)", len(body))
+		}
+		logged = string(redacted)
+	case mediaType == "application/x-www-form-urlencoded":
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return fmt.Sprintf("(%d bytes of invalid form data)", len(body))
+		}
+		for name := range form {
+			if sensitiveName(name) {
+				form.Set(name, httpLogRedacted)
+			}
+		}
+		logged = form.Encode()
+	default:
+		return fmt.Sprintf("(%d bytes of %s)", len(body), contentType)
+	}
+
+	if len(logged) > httpLogBodyLimit {
+		logged = logged[:httpLogBodyLimit] + "... (truncated)"
+	}
+
+	return logged
+}
+
+// redactJSON redacts the values of the fields of v with sensitive names, in
+// place, and returns v.
+func redactJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for name, child := range v {
+			if sensitiveName(name) {
+				v[name] = httpLogRedacted
+
+				continue
+			}
+			redactJSON(child)
+		}
+	case []any:
+		for _, child := range v {
+			redactJSON(child)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,101 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// encodeIdentifier turns a namespace or table identifier into the string used
+// as resource ID and import ID. Parts are joined with "."; dots and
+// backslashes inside a part are escaped with a backslash, so identifiers
+// without them keep their familiar "db.schema.table" form and every
+// identifier round-trips through decodeIdentifier.
+func encodeIdentifier(parts []string) string {
+	escaped := make([]string, len(parts))
+	for i, p := range parts {
+		p = strings.ReplaceAll(p, `\`, `\\`)
+		escaped[i] = strings.ReplaceAll(p, ".", `\.`)
+	}
+
+	return strings.Join(escaped, ".")
+}
+
+// decodeIdentifier is the inverse of encodeIdentifier.
+func decodeIdentifier(id string) ([]string, error) {
+	parts, err := splitIdentifier(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("identifier %q has an empty part", id)
+		}
+	}
+
+	return parts, nil
+}
+
+// decodeImportID decodes the import ID of a namespace or table like
+// decodeIdentifier, but drops empty parts, so "a..b" and ".a.b" still import
+// a.b as they did before dots could be escaped.
+func decodeImportID(id string) ([]string, error) {
+	parts, err := splitIdentifier(id)
+	if err != nil {
+		return nil, err
+	}
+
+	parts = slices.DeleteFunc(parts, func(p string) bool { return p == "" })
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("identifier %q has no parts", id)
+	}
+
+	return parts, nil
+}
+
+// splitIdentifier splits id at the unescaped dots and unescapes the parts.
+func splitIdentifier(id string) ([]string, error) {
+	if id == "" {
+		return nil, errors.New("identifier is empty")
+	}
+
+	var (
+		parts   []string
+		current strings.Builder
+	)
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; c {
+		case '\\':
+			if i+1 == len(id) || (id[i+1] != '.' && id[i+1] != '\\') {
+				return nil, fmt.Errorf("invalid escape at position %d in identifier %q", i, id)
+			}
+			i++
+			current.WriteByte(id[i])
+		case '.':
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	parts = append(parts, current.String())
+
+	return parts, nil
+}
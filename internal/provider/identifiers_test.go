@@ -0,0 +1,73 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentifierEncoding(t *testing.T) {
+	tests := []struct {
+		parts   []string
+		encoded string
+	}{
+		{[]string{"db"}, "db"},
+		{[]string{"db", "events"}, "db.events"},
+		{[]string{"a", "b", "c"}, "a.b.c"},
+		{[]string{"v1.2", "events"}, `v1\.2.events`},
+		{[]string{`back\slash`, "t"}, `back\\slash.t`},
+		{[]string{`trailing\`, ".t"}, `trailing\\.\.t`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoded, func(t *testing.T) {
+			assert.Equal(t, tt.encoded, encodeIdentifier(tt.parts))
+
+			decoded, err := decodeIdentifier(tt.encoded)
+			require.NoError(t, err)
+			assert.Equal(t, tt.parts, decoded)
+		})
+	}
+}
+
+func TestDecodeIdentifierInvalid(t *testing.T) {
+	for _, id := range []string{"", "db..t", ".t", "db.", `db\`, `db\x`} {
+		_, err := decodeIdentifier(id)
+		assert.Error(t, err, id)
+	}
+}
+
+func TestDecodeImportID(t *testing.T) {
+	for id, want := range map[string][]string{
+		"db.t":      {"db", "t"},
+		"db..t":     {"db", "t"},
+		".db.t.":    {"db", "t"},
+		`v1\.2..t`:  {"v1.2", "t"},
+		`back\\..t`: {`back\`, "t"},
+	} {
+		got, err := decodeImportID(id)
+		require.NoError(t, err, id)
+		assert.Equal(t, want, got, id)
+	}
+
+	for _, id := range []string{"", "..", `db\`} {
+		_, err := decodeImportID(id)
+		assert.Error(t, err, id)
+	}
+}
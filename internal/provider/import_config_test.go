@@ -0,0 +1,357 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateConfigImport imports id as a resource of typeName, as
+// terraform plan -generate-config-out does, and checks that the configuration
+// Terraform generates from the imported state is valid and plans no changes.
+// It returns the imported state.
+func generateConfigImport(t *testing.T, providerConfig map[string]tftypes.Value, typeName, id string) tftypes.Value {
+	t.Helper()
+
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	require.Empty(t, schemas.Diagnostics)
+	resourceSchema := schemas.ResourceSchemas[typeName]
+	require.NotNil(t, resourceSchema, typeName)
+	resourceType := resourceSchema.ValueType()
+
+	providerType := schemas.Provider.ValueType().(tftypes.Object)
+	providerValues := make(map[string]tftypes.Value, len(providerType.AttributeTypes))
+	for name, typ := range providerType.AttributeTypes {
+		providerValues[name] = tftypes.NewValue(typ, nil)
+	}
+	for name, value := range providerConfig {
+		providerValues[name] = value
+	}
+	configured, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: dynamicValue(t, tftypes.NewValue(providerType, providerValues)),
+	})
+	require.NoError(t, err)
+	requireNoErrorDiagnostics(t, configured.Diagnostics)
+
+	imported, err := server.ImportResourceState(ctx, &tfprotov6.ImportResourceStateRequest{TypeName: typeName, ID: id})
+	require.NoError(t, err)
+	requireNoErrorDiagnostics(t, imported.Diagnostics)
+	require.Len(t, imported.ImportedResources, 1)
+
+	read, err := server.ReadResource(ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: imported.ImportedResources[0].State,
+		Private:      imported.ImportedResources[0].Private,
+	})
+	require.NoError(t, err)
+	requireNoErrorDiagnostics(t, read.Diagnostics)
+	require.NotNil(t, read.NewState, "the imported %s wasn't found", typeName)
+	state, err := read.NewState.Unmarshal(resourceType)
+	require.NoError(t, err)
+
+	assert.Empty(t, missingRequiredAttributes(t, resourceSchema, state), "Required attributes Read left null")
+
+	// Terraform writes every attribute that can be configured, the
+	// computed-only ones are left to the provider.
+	config := generatedConfig(t, resourceSchema, state)
+	validated, err := server.ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: typeName,
+		Config:   dynamicValue(t, config),
+	})
+	require.NoError(t, err)
+	requireNoErrorDiagnostics(t, validated.Diagnostics)
+
+	planned, err := server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       read.NewState,
+		ProposedNewState: read.NewState,
+		Config:           dynamicValue(t, config),
+		PriorPrivate:     read.Private,
+	})
+	require.NoError(t, err)
+	requireNoErrorDiagnostics(t, planned.Diagnostics)
+	assert.Empty(t, planned.RequiresReplace)
+	plannedState, err := planned.PlannedState.Unmarshal(resourceType)
+	require.NoError(t, err)
+	diffs, err := state.Diff(plannedState)
+	require.NoError(t, err)
+	assert.Empty(t, diffs, "the generated configuration plans changes")
+
+	return state
+}
+
+func dynamicValue(t *testing.T, value tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	dv, err := tfprotov6.NewDynamicValue(value.Type(), value)
+	require.NoError(t, err)
+
+	return &dv
+}
+
+func requireNoErrorDiagnostics(t *testing.T, diags []*tfprotov6.Diagnostic) {
+	t.Helper()
+
+	for _, d := range diags {
+		require.NotEqual(t, tfprotov6.DiagnosticSeverityError, d.Severity, "%s: %s", d.Summary, d.Detail)
+	}
+}
+
+// schemaAttributeAt returns the attribute of schema at p, or nil if p isn't
+// an attribute, such as a list element or a block.
+func schemaAttributeAt(schema *tfprotov6.Schema, p *tftypes.AttributePath) *tfprotov6.SchemaAttribute {
+	attributes := schema.Block.Attributes
+	var attribute *tfprotov6.SchemaAttribute
+	for _, step := range p.Steps() {
+		switch step := step.(type) {
+		case tftypes.AttributeName:
+			attribute = nil
+			for _, a := range attributes {
+				if a.Name == string(step) {
+					attribute = a
+				}
+			}
+			if attribute == nil {
+				return nil
+			}
+			attributes = nil
+			if attribute.NestedType != nil {
+				attributes = attribute.NestedType.Attributes
+			}
+		case tftypes.ElementKeyInt, tftypes.ElementKeyString:
+			// Elements of nested attributes have the nested attributes.
+			attribute = nil
+		}
+	}
+
+	return attribute
+}
+
+// missingRequiredAttributes returns the paths of the Required attributes that
+// are null in state, in objects that aren't.
+func missingRequiredAttributes(t *testing.T, schema *tfprotov6.Schema, state tftypes.Value) []string {
+	t.Helper()
+
+	var missing []string
+	require.NoError(t, tftypes.Walk(state, func(p *tftypes.AttributePath, value tftypes.Value) (bool, error) {
+		if a := schemaAttributeAt(schema, p); a != nil && a.Required && value.IsNull() {
+			missing = append(missing, p.String())
+		}
+
+		return true, nil
+	}))
+
+	return missing
+}
+
+// generatedConfig returns the configuration Terraform generates from state:
+// state without the values of computed-only attributes.
+func generatedConfig(t *testing.T, schema *tfprotov6.Schema, state tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	config, err := tftypes.Transform(state, func(p *tftypes.AttributePath, value tftypes.Value) (tftypes.Value, error) {
+		if a := schemaAttributeAt(schema, p); a != nil && a.Computed && !a.Optional && !a.Required {
+			return tftypes.NewValue(value.Type(), nil), nil
+		}
+
+		return value, nil
+	})
+	require.NoError(t, err)
+
+	return config
+}
+
+func TestGenerateConfigImport(t *testing.T) {
+	t.Run("iceberg_namespace", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "events", nil)
+		m.namespaces["db"] = map[string]string{"comment": "Raw events", "owner": "etl", "team": "data"}
+		server := m.start(t)
+
+		state := generateConfigImport(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+		}, "iceberg_namespace", "db")
+
+		var values map[string]tftypes.Value
+		require.NoError(t, state.As(&values))
+		assert.True(t, values["name"].Equal(tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "db")})))
+		assert.Contains(t, values["server_properties"].String(), "Raw events")
+	})
+
+	t.Run("iceberg_table", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "events", map[string]string{"comment": "Raw events", "write.format.default": "parquet"})
+		m.evolveTable("db", "events", iceberg.NewSchema(1,
+			iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+			iceberg.NestedField{ID: 2, Name: "tags", Type: &iceberg.ListType{ElementID: 3, Element: iceberg.PrimitiveTypes.String}},
+			iceberg.NestedField{ID: 4, Name: "counts", Type: &iceberg.MapType{KeyID: 5, KeyType: iceberg.PrimitiveTypes.String, ValueID: 6, ValueType: iceberg.PrimitiveTypes.Int32}},
+			iceberg.NestedField{ID: 7, Name: "device", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+				{ID: 8, Name: "os", Type: iceberg.PrimitiveTypes.String, Doc: "The operating system"},
+			}}},
+		))
+		// The mock only creates unpartitioned, unsorted tables.
+		m.corruptTable("db", "events", func(metadata map[string]any) {
+			metadata["partition-specs"] = []any{map[string]any{
+				"spec-id": 0,
+				"fields":  []any{map[string]any{"source-id": 1, "field-id": 1000, "name": "id_bucket", "transform": "bucket[16]"}},
+			}}
+			metadata["last-partition-id"] = 1000
+			metadata["sort-orders"] = []any{map[string]any{
+				"order-id": 1,
+				"fields":   []any{map[string]any{"source-id": 1, "transform": "identity", "direction": "desc", "null-order": "nulls-last"}},
+			}}
+			metadata["default-sort-order-id"] = 1
+		})
+		server := m.start(t)
+
+		state := generateConfigImport(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+		}, "iceberg_table", "db.events")
+
+		var values map[string]tftypes.Value
+		require.NoError(t, state.As(&values))
+		// Docs aren't configuration the import can tell was intended.
+		assert.NotContains(t, values["schema"].String(), "The operating system")
+		assert.Contains(t, values["server_schema"].String(), "The operating system")
+		assert.Contains(t, values["partition_spec"].String(), "bucket[16]")
+		assert.Contains(t, values["sort_order"].String(), "nulls-last")
+	})
+
+	t.Run("iceberg_polaris_principal", func(t *testing.T) {
+		server := newPolarisPrincipalTestServer(t)
+		t.Cleanup(server.Close)
+		resp, err := http.Post(server.URL+"/api/management/v1/principals", "application/json",
+			strings.NewReader(`{"principal": {"name": "etl", "properties": {"team": "data"}}}`))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		state := generateConfigImport(t, map[string]tftypes.Value{
+			"type":        tftypes.NewValue(tftypes.String, "polaris"),
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+			"token":       tftypes.NewValue(tftypes.String, "tok"),
+			// The server only serves the management API.
+			"validate_connection": tftypes.NewValue(tftypes.Bool, false),
+			"polaris_settings": polarisSettingsValue(map[string]tftypes.Value{
+				"management_uri": tftypes.NewValue(tftypes.String, server.URL+"/api/management/v1"),
+			}),
+		}, "iceberg_polaris_principal", "etl")
+
+		var values map[string]tftypes.Value
+		require.NoError(t, state.As(&values))
+		assert.True(t, values["credential_rotation_required"].Equal(tftypes.NewValue(tftypes.Bool, false)))
+		assert.True(t, values["client_id"].Equal(tftypes.NewValue(tftypes.String, "id-etl")))
+	})
+}
+
+func TestAccGenerateConfigImport(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+	config := fmt.Sprintf(providerConfig, server.URL) + `
+resource "iceberg_namespace" "db" {
+  name = ["db"]
+}
+
+resource "iceberg_table" "events" {
+  namespace = iceberg_namespace.db.name
+  name      = "events"
+  schema = {
+    fields = [
+      { name = "id", type = "long", required = true },
+      { name = "tags", type = "list", required = false, list_properties = { element_type = "string", element_required = false } },
+    ]
+  }
+}
+`
+
+	steps := []resource.TestStep{{Config: config}}
+	for _, name := range []string{"iceberg_namespace.db", "iceberg_table.events"} {
+		steps = append(steps,
+			resource.TestStep{
+				ResourceName:            name,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"server_properties"},
+			},
+			// An import block plans no changes, as with a configuration
+			// generated by terraform plan -generate-config-out.
+			resource.TestStep{
+				Config:          config,
+				ResourceName:    name,
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithID,
+			},
+		)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    steps,
+	})
+}
+
+func TestAccGenerateConfigImport_PolarisPrincipal(t *testing.T) {
+	server := newPolarisPrincipalTestServer(t)
+	defer server.Close()
+	providerCfg := testAccPolarisProviderConfig(server.URL, server.URL+"/api/management/v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolarisPrincipalMockConfig(providerCfg, "data"),
+			},
+			{
+				ResourceName:      "iceberg_polaris_principal.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Polaris only returns the secret when it is created, and the
+				// import doesn't manage any properties.
+				ImportStateVerifyIgnore: []string{"client_secret", "user_properties", "server_managed_properties"},
+			},
+			{
+				// The import doesn't manage any properties, so a configuration
+				// that lists none plans no changes.
+				Config: providerCfg + `
+resource "iceberg_polaris_principal" "test" {
+  name = "principal-mock"
+}
+`,
+				ResourceName:    "iceberg_polaris_principal.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithID,
+			},
+		},
+	})
+}
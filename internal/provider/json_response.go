@@ -0,0 +1,115 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// responsePeekSize is how much of a response body is read to tell whether it
+// is a markup page.
+const responsePeekSize = 512
+
+// nonJSONResponseError is returned for HTML and XML responses, such as the
+// page of a UI or the error page of a load balancer in front of the catalog.
+// They would otherwise fail with a bare JSON syntax error.
+type nonJSONResponseError struct {
+	method      string
+	url         string
+	status      int
+	contentType string
+	firstLine   string
+}
+
+func (e *nonJSONResponseError) Error() string {
+	contentType := e.contentType
+	if contentType == "" {
+		contentType = "no content type"
+	}
+
+	return fmt.Sprintf("%s %s returned status %d with %s instead of JSON, starting with %q. The URI may not point at the catalog or the Polaris management API: check catalog_uri and polaris_settings",
+		e.method, e.url, e.status, contentType, e.firstLine)
+}
+
+// checkJSONResponse returns a nonJSONResponseError if resp is an HTML or XML
+// page: its content type says so or its body starts with '<'. The body of
+// resp stays readable from the start.
+func checkJSONResponse(req *http.Request, resp *http.Response) error {
+	if resp.Body == nil || req.Method == http.MethodHead {
+		return nil
+	}
+
+	peek, err := io.ReadAll(io.LimitReader(resp.Body, responsePeekSize))
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(peek), resp.Body), Closer: resp.Body}
+	if err != nil {
+		return nil
+	}
+
+	trimmed := bytes.TrimSpace(peek)
+	if len(trimmed) == 0 {
+		return nil
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if trimmed[0] != '<' && !isMarkupContentType(contentType) {
+		return nil
+	}
+
+	return &nonJSONResponseError{
+		method:      req.Method,
+		url:         req.URL.Redacted(),
+		status:      resp.StatusCode,
+		contentType: contentType,
+		firstLine:   firstLine(trimmed),
+	}
+}
+
+func isMarkupContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "text/html" || mediaType == "text/xml" || mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// firstLine returns the first non-empty line of body, shortened to fit in an
+// error message.
+func firstLine(body []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			if len(line) > 120 {
+				line = line[:120] + "..."
+			}
+
+			return line
+		}
+	}
+
+	return ""
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,127 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testHTMLPage = `
+<!DOCTYPE html>
+<html>
+<head><title>502 Bad Gateway</title></head>
+<body>Bad Gateway</body>
+</html>
+`
+
+func newHTMLServer(t *testing.T, status int, contentType string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, testHTMLPage)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestCheckJSONResponse(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "https://catalog.example.com/v1/config", nil)
+	response := func(contentType, body string) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     bool
+	}{
+		{name: "json", contentType: "application/json", body: `{"defaults": {}}`},
+		{name: "problem json", contentType: "application/problem+json", body: `{"title": "x"}`},
+		{name: "empty", contentType: "text/html", body: ""},
+		{name: "plain text", contentType: "text/plain", body: "entity version mismatch"},
+		{name: "html", contentType: "text/html; charset=utf-8", body: testHTMLPage, wantErr: true},
+		{name: "html labeled json", contentType: "application/json", body: testHTMLPage, wantErr: true},
+		{name: "xml", contentType: "application/xml", body: "Error", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := response(tc.contentType, tc.body)
+			err := checkJSONResponse(req, resp)
+			if !tc.wantErr {
+				require.NoError(t, err)
+
+				// The body can still be read in full.
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tc.body, string(body))
+
+				return
+			}
+
+			var nonJSON *nonJSONResponseError
+			require.ErrorAs(t, err, &nonJSON)
+			assert.Equal(t, tc.contentType, nonJSON.contentType)
+		})
+	}
+}
+
+func TestNonJSONResponseFromCatalog(t *testing.T) {
+	server := newHTMLServer(t, http.StatusBadGateway, "text/html")
+
+	p := &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}
+	_, err := p.NewCatalog(context.Background(), "iceberg_table")
+	require.Error(t, err)
+
+	var nonJSON *nonJSONResponseError
+	require.ErrorAs(t, err, &nonJSON)
+	assert.Equal(t, http.StatusBadGateway, nonJSON.status)
+	assert.Equal(t, "<!DOCTYPE html>", nonJSON.firstLine)
+	assert.Contains(t, err.Error(), "returned status 502 with text/html instead of JSON")
+	assert.Contains(t, err.Error(), "check catalog_uri")
+}
+
+func TestNonJSONResponseFromPolaris(t *testing.T) {
+	// A UI answers every path, including missing ones, with its HTML page.
+	server := newHTMLServer(t, http.StatusNotFound, "text/html")
+
+	p := &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+
+	_, err = client.GetPrincipal(context.Background(), "someone")
+	require.Error(t, err)
+	assert.False(t, isNotFoundError(err), "an HTML page must not be taken for a missing principal")
+
+	var nonJSON *nonJSONResponseError
+	require.ErrorAs(t, err, &nonJSON)
+	assert.Equal(t, http.StatusNotFound, nonJSON.status)
+	assert.Contains(t, err.Error(), "check catalog_uri and polaris_settings")
+}
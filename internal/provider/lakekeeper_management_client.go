@@ -0,0 +1,219 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file implements a small HTTP client for the Lakekeeper management API
+// (/management/v1/...), for the soft-deleted tables Lakekeeper keeps after a
+// drop. Like the Polaris client, it is only used for what the Iceberg REST
+// catalog spec doesn't cover.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/apache/iceberg-go/table"
+)
+
+type lakekeeperManagementClient struct {
+	// baseURL is the management API, catalogURL the Iceberg REST catalog of
+	// the same server.
+	baseURL    *url.URL
+	catalogURL *url.URL
+	// warehouseID is the prefix of the catalog, which Lakekeeper sets to the
+	// ID of the warehouse.
+	warehouseID string
+	httpClient  *http.Client
+	// token returns the bearer token for a request, if any.
+	token   func(context.Context) (string, error)
+	headers map[string]string
+}
+
+// lakekeeperManagementURL derives the management API from the catalog URI:
+// Lakekeeper serves the catalog under /catalog and the management API under
+// /management/v1 of the same host.
+func lakekeeperManagementURL(catalogURI string) (*url.URL, error) {
+	u, err := url.Parse(catalogURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid catalog_uri %q: %w", catalogURI, err)
+	}
+
+	management := *u
+	management.Path = path.Join(strings.TrimSuffix(strings.TrimRight(u.Path, "/"), "/catalog"), "/management/v1")
+	management.RawQuery = ""
+
+	return &management, nil
+}
+
+func (p *icebergProvider) newLakekeeperManagementClient(resourceType string) (*lakekeeperManagementClient, error) {
+	warehouseID := p.capabilities.catalogPrefix()
+	if warehouseID == "" {
+		return nil, errors.New("the catalog didn't return the warehouse ID as its prefix in the config response")
+	}
+	catalogURL, err := url.Parse(p.catalogURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid catalog_uri %q: %w", p.catalogURI, err)
+	}
+	baseURL, err := lakekeeperManagementURL(p.catalogURI)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lakekeeperManagementClient{
+		baseURL:     baseURL,
+		catalogURL:  catalogURL,
+		warehouseID: warehouseID,
+		httpClient:  &http.Client{Transport: p.managementTransport(resourceType)},
+		token: func(ctx context.Context) (string, error) {
+			return p.accessToken(ctx, resourceType)
+		},
+		headers: p.headers,
+	}, nil
+}
+
+func (c *lakekeeperManagementClient) do(ctx context.Context, method string, base *url.URL, relativePath string, query url.Values, body any, out any) error {
+	u := *base
+	u.Path = path.Join(base.Path, relativePath)
+	u.RawPath = ""
+	u.RawQuery = query.Encode()
+
+	var reqBody io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for k, v := range c.headers {
+		// don't override existing headers if users are also setting it
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("perform request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+		return fmt.Errorf("lakekeeper management: unexpected status %d for %s %s: %s", resp.StatusCode, method, req.URL.Path, string(bodyBytes))
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// lakekeeperDeletedTabular is a soft-deleted table or view. Only the fields
+// the provider uses are decoded.
+type lakekeeperDeletedTabular struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Namespace []string `json:"namespace"`
+	Type      string   `json:"typ"`
+}
+
+type lakekeeperDeletedTabularsResponse struct {
+	Tabulars      []lakekeeperDeletedTabular `json:"tabulars"`
+	NextPageToken string                     `json:"next-page-token"`
+}
+
+type lakekeeperUndropTarget struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type lakekeeperUndropRequest struct {
+	Targets []lakekeeperUndropTarget `json:"targets"`
+}
+
+func (c *lakekeeperManagementClient) deletedTabularsPath() string {
+	return "/warehouse/" + url.PathEscape(c.warehouseID) + "/deleted-tabulars"
+}
+
+// FindDeletedTables returns the soft-deleted tables named ident in the
+// warehouse.
+func (c *lakekeeperManagementClient) FindDeletedTables(ctx context.Context, ident table.Identifier) ([]lakekeeperDeletedTabular, error) {
+	var found []lakekeeperDeletedTabular
+	query := url.Values{}
+	for {
+		var out lakekeeperDeletedTabularsResponse
+		if err := c.do(ctx, http.MethodGet, c.baseURL, c.deletedTabularsPath(), query, nil, &out); err != nil {
+			return nil, err
+		}
+		for _, t := range out.Tabulars {
+			if t.Type == "table" && slices.Equal(append(slices.Clone(t.Namespace), t.Name), ident) {
+				found = append(found, t)
+			}
+		}
+		if out.NextPageToken == "" || len(out.Tabulars) == 0 {
+			return found, nil
+		}
+		query.Set("pageToken", out.NextPageToken)
+	}
+}
+
+// UndropTable restores a soft-deleted table under its name.
+func (c *lakekeeperManagementClient) UndropTable(ctx context.Context, id string) error {
+	req := lakekeeperUndropRequest{Targets: []lakekeeperUndropTarget{{Type: "table", ID: id}}}
+
+	return c.do(ctx, http.MethodPost, c.baseURL, c.deletedTabularsPath()+"/undrop", nil, req, nil)
+}
+
+// ForceDropTable drops and purges a table without soft-deleting it, through
+// the force flag Lakekeeper adds to the drop table endpoint of the catalog.
+func (c *lakekeeperManagementClient) ForceDropTable(ctx context.Context, ident table.Identifier) error {
+	namespace := strings.Join(ident[:len(ident)-1], "\x1f")
+	tablePath := "/v1/" + c.warehouseID + "/namespaces/" + namespace + "/tables/" + ident[len(ident)-1]
+	query := url.Values{"purgeRequested": []string{"true"}, "force": []string{"true"}}
+
+	return c.do(ctx, http.MethodDelete, c.catalogURL, tablePath, query, nil, nil)
+}
@@ -0,0 +1,117 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+
+	sqlcat "github.com/apache/iceberg-go/catalog/sql"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// memoryCatalogKeyPrefix prefixes the warehouse of a memory catalog in the
+// key of its database in openSQLDatabases. The key isn't a valid sql_dsn, so
+// the memory catalog is never shared with a catalog configured with
+// type = "sql".
+const memoryCatalogKeyPrefix = "memory:"
+
+// memoryCatalog is a memory catalog that belongs to one provider instance:
+// the in-memory database and the temporary warehouse of a provider
+// configured without warehouse. The provider removes both when it is closed.
+type memoryCatalog struct {
+	db  *sql.DB
+	dir string
+}
+
+// newMemoryCatalog opens a new in-memory database and creates the temporary
+// directory of its warehouse.
+func newMemoryCatalog() (*memoryCatalog, error) {
+	dir, err := os.MkdirTemp("", "iceberg-memory-catalog-")
+	if err != nil {
+		return nil, err
+	}
+	db, err := openMemoryDatabase()
+	if err != nil {
+		return nil, errors.Join(err, os.RemoveAll(dir))
+	}
+
+	return &memoryCatalog{db: db, dir: dir}, nil
+}
+
+// warehouse returns the location of the temporary warehouse.
+func (m *memoryCatalog) warehouse() string {
+	return "file://" + filepath.ToSlash(m.dir)
+}
+
+func (m *memoryCatalog) close() error {
+	return errors.Join(m.db.Close(), os.RemoveAll(m.dir))
+}
+
+// loadMemoryConfig returns the configuration of the memory catalog, a SQL
+// catalog in an in-memory SQLite database, nil while warehouse is unknown.
+// Provider instances of the process configured with the same warehouse share
+// one database, which lives until the process exits, so that a catalog
+// outlives the provider instance of a single Terraform command in tests that
+// serve the provider in-process. Without warehouse, the database and a
+// temporary warehouse belong to the provider instance.
+func (p *icebergProvider) loadMemoryConfig(data icebergProviderModel, diags *diag.Diagnostics) *sqlConfig {
+	if data.Warehouse.IsUnknown() {
+		return nil
+	}
+
+	cfg := &sqlConfig{dialect: sqlcat.SQLite, endpoint: "memory"}
+	if !data.Warehouse.IsNull() {
+		db, err := openSQLDatabase(memoryCatalogKeyPrefix+data.Warehouse.ValueString(), sqlDSN{driver: "sqlite", dialect: sqlcat.SQLite, driverDSN: ":memory:", memory: true})
+		if err != nil {
+			diags.AddError("Failed to open the memory catalog", err.Error()+".")
+
+			return nil
+		}
+		cfg.db = db
+
+		return cfg
+	}
+
+	if p.memory == nil {
+		m, err := newMemoryCatalog()
+		if err != nil {
+			diags.AddError("Failed to create the memory catalog",
+				"The memory catalog couldn't be created: "+err.Error()+". Set warehouse to a writable location.")
+
+			return nil
+		}
+		p.memory = m
+	}
+	cfg.db = p.memory.db
+	cfg.warehouse = p.memory.warehouse()
+
+	return cfg
+}
+
+// closeMemoryCatalog drops the memory catalog of the provider instance and
+// removes its warehouse.
+func (p *icebergProvider) closeMemoryCatalog() error {
+	if p.memory == nil {
+		return nil
+	}
+	err := p.memory.close()
+	p.memory = nil
+
+	return err
+}
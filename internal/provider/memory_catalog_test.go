@@ -0,0 +1,120 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderMemory(t *testing.T) {
+	ctx := context.Background()
+	memoryType := map[string]tftypes.Value{"type": tftypes.NewValue(tftypes.String, "memory")}
+
+	p, diags := configureTestProvider(t, memoryType)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.NotNil(t, p.sql)
+	assert.Equal(t, "memory", p.catalogType)
+	assert.True(t, strings.HasPrefix(p.sql.warehouse, "file://"), p.sql.warehouse)
+
+	cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+	require.NoError(t, err)
+
+	ns := catalog.ToIdentifier("memory_db")
+	require.NoError(t, cat.CreateNamespace(ctx, ns, iceberg.Properties{"owner": "ci"}))
+	_, err = cat.UpdateNamespaceProperties(ctx, ns, []string{"owner"}, iceberg.Properties{"env": "test"})
+	require.NoError(t, err)
+	props, err := cat.LoadNamespaceProperties(ctx, ns)
+	require.NoError(t, err)
+	assert.Equal(t, "test", props["env"])
+	assert.NotContains(t, props, "owner")
+
+	ident := catalog.ToIdentifier("memory_db", "events")
+	schema := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	_, err = cat.CreateTable(ctx, ident, schema)
+	require.NoError(t, err)
+
+	renamed := catalog.ToIdentifier("memory_db", "renamed")
+	tbl, err := cat.RenameTable(ctx, ident, renamed)
+	require.NoError(t, err)
+	assert.Equal(t, renamed, tbl.Identifier())
+	exists, err := cat.CheckTableExists(ctx, ident)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Another provider instance without warehouse has a catalog of its own.
+	other, diags := configureTestProvider(t, memoryType)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.NotEqual(t, p.sql.warehouse, other.sql.warehouse)
+	otherCat, err := other.NewCatalog(ctx, "iceberg_namespace")
+	require.NoError(t, err)
+	exists, err = otherCat.CheckNamespaceExists(ctx, ns)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Closing the provider drops the catalog and removes its warehouse.
+	warehouseDir := strings.TrimPrefix(p.sql.warehouse, "file://")
+	require.DirExists(t, warehouseDir)
+	require.NoError(t, p.Close())
+	assert.NoDirExists(t, warehouseDir)
+	assert.Nil(t, p.memory)
+
+	t.Run("explicit warehouse", func(t *testing.T) {
+		warehouse := "file://" + t.TempDir()
+		config := map[string]tftypes.Value{
+			"type":      tftypes.NewValue(tftypes.String, "memory"),
+			"warehouse": tftypes.NewValue(tftypes.String, warehouse),
+		}
+		p, diags := configureTestProvider(t, config)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Empty(t, p.sql.warehouse)
+		assert.Equal(t, warehouse, p.warehouse)
+		assert.Nil(t, p.memory)
+
+		cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+		require.NoError(t, err)
+		require.NoError(t, cat.CreateNamespace(ctx, ns, iceberg.Properties{"owner": "ci"}))
+
+		// A new provider instance with the same warehouse, as Terraform
+		// configures for the next command of an in-process test, sees the
+		// same catalog, and one with another warehouse doesn't.
+		next, diags := configureTestProvider(t, config)
+		require.False(t, diags.HasError(), "%v", diags)
+		nextCat, err := next.NewCatalog(ctx, "iceberg_table")
+		require.NoError(t, err)
+		props, err := nextCat.LoadNamespaceProperties(ctx, ns)
+		require.NoError(t, err)
+		assert.Equal(t, "ci", props["owner"])
+
+		other, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":      tftypes.NewValue(tftypes.String, "memory"),
+			"warehouse": tftypes.NewValue(tftypes.String, "file://"+t.TempDir()),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		otherCat, err := other.NewCatalog(ctx, "iceberg_table")
+		require.NoError(t, err)
+		exists, err := otherCat.CheckNamespaceExists(ctx, ns)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
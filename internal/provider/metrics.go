@@ -0,0 +1,170 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// operationKey identifies the calls counted together: those made for one
+// resource or data source type with one HTTP method.
+type operationKey struct {
+	resourceType string
+	method       string
+}
+
+type operationStats struct {
+	calls   int64
+	errors  int64
+	latency time.Duration
+}
+
+// operationMetrics counts the requests the provider sends to the catalog and
+// the Polaris management API, and their cumulative latency.
+type operationMetrics struct {
+	mu    sync.Mutex
+	stats map[operationKey]*operationStats
+	// apiCalls are the calls of the provider by endpoint family, summarized
+	// after the stats.
+	apiCalls *apiCallCounter
+}
+
+func newOperationMetrics() *operationMetrics {
+	return &operationMetrics{stats: make(map[operationKey]*operationStats)}
+}
+
+// record counts a request and returns the updated stats of its resource type
+// and method.
+func (m *operationMetrics) record(resourceType, method string, latency time.Duration, failed bool) operationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := operationKey{resourceType: resourceType, method: method}
+	s, ok := m.stats[key]
+	if !ok {
+		s = &operationStats{}
+		m.stats[key] = s
+	}
+	s.calls++
+	s.latency += latency
+	if failed {
+		s.errors++
+	}
+
+	return *s
+}
+
+// get returns the stats recorded for the resource type and method.
+func (m *operationMetrics) get(resourceType, method string) operationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.stats[operationKey{resourceType: resourceType, method: method}]; ok {
+		return *s
+	}
+
+	return operationStats{}
+}
+
+// writeSummary writes one line per resource type and method, sorted.
+func (m *operationMetrics) writeSummary(w io.Writer) error {
+	m.mu.Lock()
+	keys := make([]operationKey, 0, len(m.stats))
+	for key := range m.stats {
+		keys = append(keys, key)
+	}
+	stats := make(map[operationKey]operationStats, len(m.stats))
+	for key, s := range m.stats {
+		stats[key] = *s
+	}
+	m.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].resourceType != keys[j].resourceType {
+			return keys[i].resourceType < keys[j].resourceType
+		}
+
+		return keys[i].method < keys[j].method
+	})
+
+	for _, key := range keys {
+		s := stats[key]
+		if _, err := fmt.Fprintf(w, "%s %s: %d calls, %d errors, %s total\n", key.resourceType, key.method, s.calls, s.errors, s.latency.Round(time.Millisecond)); err != nil {
+			return err
+		}
+	}
+	if m.apiCalls == nil {
+		return nil
+	}
+
+	return m.apiCalls.writeSummary(w)
+}
+
+// writeOperationMetrics writes the summary of the operation metrics to w. It
+// writes nothing unless enable_operation_metrics is set.
+func (p *icebergProvider) writeOperationMetrics(w io.Writer) error {
+	if p.metrics == nil {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "iceberg provider operation metrics:"); err != nil {
+		return err
+	}
+
+	return p.metrics.writeSummary(w)
+}
+
+// metricsTransport times every request, records it for the resource type of
+// its context, or resourceType, and logs it with the running totals. A
+// request fails if it returns an error or a status of 400 or above.
+type metricsTransport struct {
+	next         http.RoundTripper
+	metrics      *operationMetrics
+	resourceType string
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	resourceType := contextResourceType(req.Context(), t.resourceType)
+	stats := t.metrics.record(resourceType, req.Method, latency, err != nil || status >= http.StatusBadRequest)
+
+	tflog.Debug(req.Context(), "Catalog request", map[string]any{
+		"resource_type":    resourceType,
+		"method":           req.Method,
+		"path":             req.URL.Path,
+		"endpoint":         endpointFamilyNames[requestEndpointFamily(req)],
+		"status":           status,
+		"duration_ms":      latency.Milliseconds(),
+		"total_calls":      stats.calls,
+		"total_errors":     stats.errors,
+		"total_latency_ms": stats.latency.Milliseconds(),
+	})
+
+	return resp, err
+}
@@ -0,0 +1,164 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationMetricsCountCatalogRequests(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	server := m.start(t)
+
+	p := &icebergProvider{
+		catalogURI:   server.URL,
+		catalogType:  "rest",
+		polaris:      &polarisConfig{managementURI: server.URL + "/api/management/v1"},
+		capabilities: newCatalogCapabilities(),
+		metrics:      newOperationMetrics(),
+	}
+
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+	// Creating the catalog fetches its config.
+	assert.Equal(t, int64(1), p.metrics.get("iceberg_table", "GET").calls)
+
+	_, err = cat.LoadTable(ctx, catalog.ToIdentifier("db", "events"))
+	require.NoError(t, err)
+	_, err = cat.LoadTable(ctx, catalog.ToIdentifier("db", "missing"))
+	require.Error(t, err)
+
+	stats := p.metrics.get("iceberg_table", "GET")
+	assert.Equal(t, int64(3), stats.calls)
+	assert.Equal(t, int64(1), stats.errors)
+	assert.Positive(t, stats.latency)
+
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+	require.Error(t, client.DeletePrincipal(ctx, "someone"))
+
+	assert.Equal(t, operationStats{}, p.metrics.get("iceberg_table", "DELETE"))
+	assert.Equal(t, int64(1), p.metrics.get("iceberg_polaris_principal", "DELETE").calls)
+	assert.Equal(t, int64(1), p.metrics.get("iceberg_polaris_principal", "DELETE").errors)
+}
+
+func TestOperationMetricsCountGlueRequests(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		switch r.Header.Get("X-Amz-Target") {
+		case "AWSGlue.GetDatabase":
+			_, _ = io.WriteString(w, `{"Database":{"Name":"db","Parameters":{}}}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"InvalidInputException","message":"unexpected action"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"type":                     tftypes.NewValue(tftypes.String, "glue"),
+		"glue_region":              tftypes.NewValue(tftypes.String, "eu-west-1"),
+		"glue_access_key_id":       tftypes.NewValue(tftypes.String, "AKID"),
+		"glue_secret_access_key":   tftypes.NewValue(tftypes.String, "SECRET"),
+		"enable_operation_metrics": tftypes.NewValue(tftypes.Bool, true),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	p.glue.awsConfig.BaseEndpoint = aws.String(server.URL)
+
+	cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+	require.NoError(t, err)
+	_, err = cat.LoadNamespaceProperties(ctx, table.Identifier{"db"})
+	require.NoError(t, err)
+	_, err = cat.LoadNamespaceProperties(withResourceType(ctx, "data.iceberg_namespace"), table.Identifier{"db"})
+	require.NoError(t, err)
+
+	// The Glue API is called with POST requests.
+	assert.Equal(t, int64(1), p.metrics.get("iceberg_namespace", "POST").calls)
+	assert.Equal(t, int64(1), p.metrics.get("data.iceberg_namespace", "POST").calls)
+}
+
+func TestOperationMetricsDisabled(t *testing.T) {
+	p := &icebergProvider{}
+	_, ok := p.transport("iceberg_table").(*metricsTransport)
+	assert.False(t, ok)
+
+	// In read-only mode, refused requests never reach the metrics.
+	p = &icebergProvider{readOnly: true, metrics: newOperationMetrics()}
+	readOnly, ok := p.transport("iceberg_table").(*readOnlyTransport)
+	require.True(t, ok)
+	_, ok = readOnly.next.(*metricsTransport)
+	assert.True(t, ok)
+}
+
+func TestOperationMetricsSummary(t *testing.T) {
+	metrics := newOperationMetrics()
+	metrics.record("iceberg_table", "POST", 250*time.Millisecond, false)
+	metrics.record("iceberg_namespace", "GET", 100*time.Millisecond, false)
+	metrics.record("iceberg_table", "GET", 40*time.Millisecond, true)
+	metrics.record("iceberg_table", "GET", 60*time.Millisecond, false)
+
+	var b strings.Builder
+	require.NoError(t, metrics.writeSummary(&b))
+	assert.Equal(t, `iceberg_namespace GET: 1 calls, 0 errors, 100ms total
+iceberg_table GET: 2 calls, 1 errors, 100ms total
+iceberg_table POST: 1 calls, 0 errors, 250ms total
+`, b.String())
+}
+
+func TestWriteOperationMetrics(t *testing.T) {
+	enabled := map[string]tftypes.Value{"enable_operation_metrics": tftypes.NewValue(tftypes.Bool, true)}
+
+	var b strings.Builder
+	p, diags := configureTestProvider(t, nil)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.NoError(t, p.writeOperationMetrics(&b))
+	assert.Empty(t, b.String(), "nothing is written without enable_operation_metrics")
+
+	p, diags = configureTestProvider(t, enabled)
+	require.False(t, diags.HasError(), "%v", diags)
+	p.metrics.record("iceberg_table", "GET", 40*time.Millisecond, false)
+
+	// Configuring the provider again keeps the metrics of the instance.
+	var resp provider.ConfigureResponse
+	p.Configure(context.Background(), testProviderConfigureRequest(t, enabled), &resp)
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+
+	other, diags := configureTestProvider(t, enabled)
+	require.False(t, diags.HasError(), "%v", diags)
+	other.metrics.record("iceberg_namespace", "GET", 10*time.Millisecond, false)
+
+	require.NoError(t, p.writeOperationMetrics(&b))
+	assert.Equal(t, `iceberg provider operation metrics:
+iceberg_table GET: 1 calls, 0 errors, 40ms total
+`, b.String(), "each provider instance writes its own metrics")
+}
@@ -0,0 +1,139 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Workspaces that share a catalog keep their objects apart with the provider's
+// name_prefix. It is prepended to the leading namespace level of every object
+// the resources create and, with prefix_table_names, to table names.
+// Configurations and the name attributes in state hold the names without the
+// prefix; resource IDs and import IDs are the identifiers in the catalog, with
+// the prefix. The ID of a new resource is planned, so the prefix shows up in
+// the plan, and an existing resource keeps being looked up by its ID.
+// Changing the prefix would point existing resources at other objects, so
+// planning them after a change is an error.
+
+// prefixNamespace returns ident with the name prefix prepended to its leading
+// level.
+func (p *icebergProvider) prefixNamespace(ident table.Identifier) table.Identifier {
+	if p == nil {
+		return ident
+	}
+
+	return withNamePrefix(ident, p.namePrefix)
+}
+
+// withNamePrefix returns ident with prefix prepended to its leading level.
+func withNamePrefix(ident table.Identifier, prefix string) table.Identifier {
+	if prefix == "" || len(ident) == 0 {
+		return ident
+	}
+
+	return append(table.Identifier{prefix + ident[0]}, ident[1:]...)
+}
+
+// prefixTable is prefixNamespace for table identifiers: the name prefix is
+// also prepended to the table name with prefix_table_names.
+func (p *icebergProvider) prefixTable(ident table.Identifier) table.Identifier {
+	if p == nil || p.namePrefix == "" || len(ident) < 2 {
+		return ident
+	}
+
+	prefixed := p.prefixNamespace(ident)
+	if p.prefixTableNames {
+		prefixed[len(prefixed)-1] = p.namePrefix + prefixed[len(prefixed)-1]
+	}
+
+	return prefixed
+}
+
+// unprefixNamespace is the inverse of prefixNamespace, used to import a
+// namespace by its identifier in the catalog.
+func (p *icebergProvider) unprefixNamespace(ident table.Identifier) (table.Identifier, error) {
+	if p == nil || p.namePrefix == "" {
+		return ident, nil
+	}
+
+	leading, ok := strings.CutPrefix(ident[0], p.namePrefix)
+	if !ok || leading == "" {
+		return nil, fmt.Errorf("%s doesn't start with the name_prefix %q of the provider", encodeIdentifier(ident), p.namePrefix)
+	}
+
+	return append(table.Identifier{leading}, ident[1:]...), nil
+}
+
+// unprefixTable is the inverse of prefixTable.
+func (p *icebergProvider) unprefixTable(ident table.Identifier) (table.Identifier, error) {
+	unprefixed, err := p.unprefixNamespace(ident)
+	if err != nil || p == nil || p.namePrefix == "" || !p.prefixTableNames {
+		return unprefixed, err
+	}
+
+	name, ok := strings.CutPrefix(unprefixed[len(unprefixed)-1], p.namePrefix)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("the table name of %s doesn't start with the name_prefix %q of the provider", encodeIdentifier(ident), p.namePrefix)
+	}
+	unprefixed[len(unprefixed)-1] = name
+
+	return unprefixed, nil
+}
+
+// identifierFromID returns the catalog identifier held in the ID of an
+// existing resource whose name is name. IDs written before identifiers were
+// escaped may not decode to as many levels as name has; name is returned for
+// those, as they were written without a prefix.
+func identifierFromID(id types.String, name table.Identifier) table.Identifier {
+	if id.IsNull() || id.IsUnknown() {
+		return name
+	}
+
+	ident, err := decodeIdentifier(id.ValueString())
+	if err != nil || len(ident) != len(name) {
+		return name
+	}
+
+	return ident
+}
+
+// checkNamePrefix adds an error if an existing resource, whose catalog
+// identifier is held in stateID, would be planned as planned with the current
+// name_prefix and prefix_table_names. IDs that identifierFromID doesn't use
+// are skipped.
+func checkNamePrefix(resourceType string, stateID types.String, planned table.Identifier, diags *diag.Diagnostics) {
+	current := identifierFromID(stateID, planned)
+	if slices.Equal(current, planned) {
+		return
+	}
+
+	diags.AddAttributeError(
+		path.Root("id"),
+		"Name prefix changed",
+		fmt.Sprintf("The %s %s was created with a different name_prefix or prefix_table_names setting and would now be %s. "+
+			"Changing these settings while resources exist would leave the objects created with the old prefix unmanaged: "+
+			"restore the previous settings, or destroy the resources before changing them.",
+			resourceType, encodeIdentifier(current), encodeIdentifier(planned)),
+	)
+}
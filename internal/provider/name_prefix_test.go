@@ -0,0 +1,150 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamePrefix(t *testing.T) {
+	var none *icebergProvider
+	assert.Equal(t, table.Identifier{"db", "orders"}, none.prefixTable(table.Identifier{"db", "orders"}))
+
+	namespaces := &icebergProvider{namePrefix: "dev_"}
+	assert.Equal(t, table.Identifier{"dev_sales", "eu"}, namespaces.prefixNamespace(table.Identifier{"sales", "eu"}))
+	assert.Equal(t, table.Identifier{"dev_db", "orders"}, namespaces.prefixTable(table.Identifier{"db", "orders"}))
+
+	tables := &icebergProvider{namePrefix: "dev_", prefixTableNames: true}
+	ident := table.Identifier{"db", "orders"}
+	assert.Equal(t, table.Identifier{"dev_db", "dev_orders"}, tables.prefixTable(ident))
+	assert.Equal(t, table.Identifier{"db", "orders"}, ident, "the identifier passed in is left alone")
+
+	unprefixed, err := tables.unprefixTable(table.Identifier{"dev_db", "dev_orders"})
+	require.NoError(t, err)
+	assert.Equal(t, table.Identifier{"db", "orders"}, unprefixed)
+
+	_, err = tables.unprefixTable(table.Identifier{"dev_db", "orders"})
+	assert.ErrorContains(t, err, `the table name of dev_db.orders doesn't start with the name_prefix "dev_"`)
+	_, err = namespaces.unprefixNamespace(table.Identifier{"dev_"})
+	assert.ErrorContains(t, err, `dev_ doesn't start with the name_prefix "dev_"`)
+}
+
+func TestIdentifierFromID(t *testing.T) {
+	name := table.Identifier{"db", "orders"}
+
+	assert.Equal(t, table.Identifier{"dev_db", "orders"}, identifierFromID(types.StringValue("dev_db.orders"), name))
+	assert.Equal(t, name, identifierFromID(types.StringUnknown(), name))
+	// Written before dots in names were escaped.
+	assert.Equal(t, table.Identifier{"a.b", "orders"}, identifierFromID(types.StringValue("a.b.orders"), table.Identifier{"a.b", "orders"}))
+}
+
+func TestCheckNamePrefix(t *testing.T) {
+	var diags diag.Diagnostics
+	checkNamePrefix("table", types.StringValue("dev_db.orders"), table.Identifier{"dev_db", "orders"}, &diags)
+	checkNamePrefix("table", types.StringNull(), table.Identifier{"dev_db", "orders"}, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	checkNamePrefix("table", types.StringValue("db.orders"), table.Identifier{"dev_db", "orders"}, &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Name prefix changed", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), "The table db.orders was created with a different name_prefix")
+}
+
+func TestNamespacesApplyNamePrefix(t *testing.T) {
+	m := newMockRESTCatalog()
+	r := newNamespacesTestResource(t, m)
+
+	var diags diag.Diagnostics
+	result := r.apply(context.Background(), "dev_", map[string]map[string]string{
+		"sales":   {"owner": "sales"},
+		"finance": {},
+	}, nil, false, &diags)
+
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, map[string]map[string]string{"sales": {"owner": "sales"}, "finance": {}}, result)
+	assert.Equal(t, map[string]string{"owner": "sales"}, m.namespaceProperties("dev_sales"))
+	assert.NotNil(t, m.namespaceProperties("dev_finance"))
+	assert.Nil(t, m.namespaceProperties("sales"))
+}
+
+func TestAccIcebergTable_NamePrefix(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("dev_db", "other", nil)
+	server := m.start(t)
+
+	config := func(prefix string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri        = "%s"
+  name_prefix        = "%s"
+  prefix_table_names = true
+}
+
+resource "iceberg_table" "orders" {
+  namespace = ["db"]
+  name      = "orders"
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL, prefix)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("dev_"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.orders", "id", "dev_db.dev_orders"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "namespace.0", "db"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "name", "orders"),
+				),
+			},
+			{
+				ResourceName:      "iceberg_table.orders",
+				ImportState:       true,
+				ImportStateId:     "dev_db.dev_orders",
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"server_properties",
+				},
+			},
+			{
+				Config:      config("ci_"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Name prefix changed`),
+			},
+		},
+	})
+}
@@ -0,0 +1,66 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultNamespaceDeleteTimeout is how long a namespace delete waits for the
+// namespace to become empty unless timeouts.delete is set.
+const defaultNamespaceDeleteTimeout = 2 * time.Minute
+
+// namespaceNotEmptyRetryDelay is the delay between attempts to drop a
+// namespace that isn't empty yet. It is a variable for tests.
+var namespaceNotEmptyRetryDelay = time.Second
+
+// errNamespaceStillNotEmpty is returned by dropNamespaceWhenEmpty when the
+// namespace still isn't empty once the timeout passed.
+var errNamespaceStillNotEmpty = errors.New("namespace still isn't empty")
+
+// dropNamespaceWhenEmpty drops namespaceIdent, retrying for up to timeout
+// while the catalog reports it isn't empty. When a whole stack is destroyed,
+// Terraform deletes a namespace as soon as the deletes of its tables
+// returned, but slow catalogs may still be dropping them, or a table may be
+// in a namespace Terraform doesn't know it depends on.
+func dropNamespaceWhenEmpty(ctx context.Context, cat catalog.Catalog, namespaceIdent table.Identifier, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for attempt := 1; ; attempt++ {
+		err := cat.DropNamespace(ctx, namespaceIdent)
+		if !isNamespaceNotEmptyError(err) {
+			return err
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return fmt.Errorf("%w after %s: %w", errNamespaceStillNotEmpty, timeout, err)
+		}
+		wait = min(wait, namespaceNotEmptyRetryDelay)
+		tflog.Debug(ctx, "Namespace isn't empty, retrying the drop", map[string]any{"attempt": attempt, "error": err.Error()})
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
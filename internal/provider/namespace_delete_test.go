@@ -0,0 +1,205 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDropNamespaceWhenEmpty(t *testing.T) {
+	defer func(delay time.Duration) { namespaceNotEmptyRetryDelay = delay }(namespaceNotEmptyRetryDelay)
+	namespaceNotEmptyRetryDelay = 10 * time.Millisecond
+	ctx := context.Background()
+
+	newCatalog := func(t *testing.T, m *mockRESTCatalog) catalog.Catalog {
+		t.Helper()
+
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, m.start(t).URL),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+		require.NoError(t, err)
+
+		return cat
+	}
+
+	t.Run("tables dropped concurrently", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.dropDelay = 200 * time.Millisecond
+		tables := []string{"events", "orders", "users", "clicks"}
+		for _, name := range tables {
+			m.addTable("db", name, nil)
+		}
+		cat := newCatalog(t, m)
+
+		// Like a destroy: the table drops return before they took effect and
+		// the namespace is dropped right after, while they are in flight.
+		var wg sync.WaitGroup
+		for _, name := range tables {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, cat.DropTable(ctx, catalog.ToIdentifier("db", name)))
+			}()
+		}
+		wg.Wait()
+
+		require.NoError(t, dropNamespaceWhenEmpty(ctx, cat, catalog.ToIdentifier("db"), 5*time.Second))
+		assert.Nil(t, m.namespaceProperties("db"))
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		drops := slices.DeleteFunc(slices.Clone(m.writes), func(w string) bool { return w != "DELETE /v1/namespaces/db" })
+		assert.Greater(t, len(drops), 1, "the namespace drop is retried while the tables are being dropped")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "unmanaged", nil)
+		cat := newCatalog(t, m)
+
+		start := time.Now()
+		err := dropNamespaceWhenEmpty(ctx, cat, catalog.ToIdentifier("db"), 50*time.Millisecond)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errNamespaceStillNotEmpty), "%v", err)
+		assert.True(t, isNamespaceNotEmptyError(err), "%v", err)
+		assert.Contains(t, err.Error(), "namespace still isn't empty after 50ms: ")
+		assert.Less(t, time.Since(start), 2*time.Second)
+		assert.NotNil(t, m.namespaceProperties("db"))
+	})
+
+	t.Run("other errors aren't retried", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		cat := newCatalog(t, m)
+
+		err := dropNamespaceWhenEmpty(ctx, cat, catalog.ToIdentifier("missing"), time.Minute)
+		assert.True(t, isNotFoundError(err), "%v", err)
+		assert.Equal(t, []string{"DELETE /v1/namespaces/missing"}, m.writes)
+	})
+}
+
+func TestDeleteTimeout(t *testing.T) {
+	ctx := context.Background()
+	attrTypes := map[string]attr.Type{"delete": types.StringType}
+	timeouts := func(value types.String) types.Object {
+		return types.ObjectValueMust(attrTypes, map[string]attr.Value{"delete": value})
+	}
+
+	var diags diag.Diagnostics
+	assert.Equal(t, time.Minute, deleteTimeout(ctx, types.ObjectNull(attrTypes), time.Minute, &diags))
+	assert.Equal(t, time.Minute, deleteTimeout(ctx, timeouts(types.StringNull()), time.Minute, &diags))
+	assert.Equal(t, 10*time.Minute, deleteTimeout(ctx, timeouts(types.StringValue("10m")), time.Minute, &diags))
+	require.False(t, diags.HasError(), "%v", diags)
+
+	validateTimeoutsConfig(ctx, timeouts(types.StringValue("90s")), &diags)
+	validateTimeoutsConfig(ctx, timeouts(types.StringUnknown()), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	for _, invalid := range []string{"ten minutes", "0s", "-1m"} {
+		diags = nil
+		validateTimeoutsConfig(ctx, timeouts(types.StringValue(invalid)), &diags)
+		require.Len(t, diags.Errors(), 1, invalid)
+		assert.Equal(t, "Invalid timeouts", diags.Errors()[0].Summary())
+	}
+}
+
+func TestAccIcebergNamespace_DestroyWithSlowTableDrops(t *testing.T) {
+	defer func(delay time.Duration) { namespaceNotEmptyRetryDelay = delay }(namespaceNotEmptyRetryDelay)
+	namespaceNotEmptyRetryDelay = 50 * time.Millisecond
+
+	m := newMockRESTCatalog()
+	m.dropDelay = 500 * time.Millisecond
+	server := m.start(t)
+
+	config := func(timeouts string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_namespace" "db" {
+  name = ["db"]
+  %s
+}
+
+resource "iceberg_table" "events" {
+  namespace = iceberg_namespace.db.name
+  name      = "events"
+  schema = {
+    fields = [
+      { name = "id", type = "long", required = true },
+    ]
+  }
+}
+
+resource "iceberg_table" "orders" {
+  namespace = iceberg_namespace.db.name
+  name      = "orders"
+  schema = {
+    fields = [
+      { name = "id", type = "long", required = true },
+    ]
+  }
+}
+`, server.URL, timeouts)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if m.namespaceProperties("db") != nil {
+				return errors.New("namespace db wasn't dropped")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`timeouts { delete = "soon" }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`timeouts.delete must be a positive duration`),
+			},
+			{
+				Config: config(`timeouts { delete = "30s" }`),
+				Check:  resource.TestCheckResourceAttr("iceberg_namespace.db", "timeouts.delete", "30s"),
+			},
+			{
+				// The tables and the namespace are destroyed in one pass.
+				Config:  config(`timeouts { delete = "30s" }`),
+				Destroy: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,170 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Some catalogs return JSON nulls as property values. Decoded into a
+// map[string]string they become empty strings, which are indistinguishable
+// from properties set to "" and show up as confusing diffs. Null values are
+// therefore dropped from the properties of every catalog and Polaris response
+// before it is decoded, so a property with a null value is treated as not set.
+// The dropped keys are recorded in the request context and reported by the
+// resource as a warning.
+
+// nullPropertiesKey is the context key of a *nullProperties.
+type nullPropertiesKey struct{}
+
+// nullProperties collects the keys of properties dropped from the responses
+// to requests made with its context.
+type nullProperties struct {
+	mu   sync.Mutex
+	keys map[string]struct{}
+}
+
+// withNullProperties returns a context that records the property keys whose
+// null values are dropped from responses to requests made with it.
+func withNullProperties(ctx context.Context) (context.Context, *nullProperties) {
+	n := &nullProperties{keys: make(map[string]struct{})}
+
+	return context.WithValue(ctx, nullPropertiesKey{}, n), n
+}
+
+func (n *nullProperties) record(keys []string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, k := range keys {
+		n.keys[k] = struct{}{}
+	}
+}
+
+// addTo appends a warning listing the dropped keys of subject, if any.
+func (n *nullProperties) addTo(subject string, diags *diag.Diagnostics) {
+	n.mu.Lock()
+	keys := make([]string, 0, len(n.keys))
+	for k := range n.keys {
+		keys = append(keys, k)
+	}
+	n.mu.Unlock()
+	if len(keys) == 0 {
+		return
+	}
+	sort.Strings(keys)
+
+	diags.AddWarning(
+		"Null property values ignored",
+		fmt.Sprintf("The catalog returned null values for these properties of %s, which are treated as not set: %s. Properties set to null in the catalog show up as missing in the plan.",
+			subject, strings.Join(keys, ", ")),
+	)
+}
+
+// filterNullProperties drops the null values from the properties in a
+// successful JSON response and records their keys in the nullProperties of
+// the request context. A body that isn't valid JSON is left for the decoder
+// to report.
+func filterNullProperties(req *http.Request, resp *http.Response) {
+	if resp.Body == nil || req.Method == http.MethodHead || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	filtered, keys := dropNullProperties(body)
+	if len(keys) == 0 {
+		return
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(filtered))
+	resp.ContentLength = int64(len(filtered))
+	resp.Header.Del("Content-Length")
+	if n, ok := req.Context().Value(nullPropertiesKey{}).(*nullProperties); ok {
+		n.record(keys)
+	}
+}
+
+// dropNullProperties removes the null values of every "properties" object in
+// the JSON document body and returns the document along with the removed
+// keys, sorted. body is returned unchanged if nothing was removed.
+func dropNullProperties(body []byte) ([]byte, []string) {
+	if !bytes.Contains(body, []byte("null")) {
+		return body, nil
+	}
+
+	// Numbers are kept as written so snapshot IDs don't lose precision.
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return body, nil
+	}
+
+	dropped := make(map[string]struct{})
+	dropNullPropertyValues(doc, dropped)
+	if len(dropped) == 0 {
+		return body, nil
+	}
+
+	filtered, err := json.Marshal(doc)
+	if err != nil {
+		return body, nil
+	}
+
+	keys := make([]string, 0, len(dropped))
+	for k := range dropped {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return filtered, keys
+}
+
+func dropNullPropertyValues(v any, dropped map[string]struct{}) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if props, ok := child.(map[string]any); ok && k == "properties" {
+				for key, value := range props {
+					if value == nil {
+						delete(props, key)
+						dropped[key] = struct{}{}
+					}
+				}
+			}
+			dropNullPropertyValues(child, dropped)
+		}
+	case []any:
+		for _, child := range v {
+			dropNullPropertyValues(child, dropped)
+		}
+	}
+}
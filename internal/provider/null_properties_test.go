@@ -0,0 +1,161 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDropNullProperties(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		body     string
+		want     string
+		wantKeys []string
+	}{
+		{
+			name:     "namespace",
+			body:     `{"namespace": ["db"], "properties": {"owner": null, "location": "s3://bucket/db", "comment": null}}`,
+			want:     `{"namespace": ["db"], "properties": {"location": "s3://bucket/db"}}`,
+			wantKeys: []string{"comment", "owner"},
+		},
+		{
+			name: "table",
+			body: `{"metadata-location": "s3://bucket/t/v1.json", "metadata": {"format-version": 2, "current-snapshot-id": 9223372036854775807, "parent-snapshot-id": null, "properties": {"write.format.default": null, "owner": "alice"}}}`,
+			// The snapshot ID survives without losing precision and nulls
+			// outside of properties are kept.
+			want:     `{"metadata-location": "s3://bucket/t/v1.json", "metadata": {"format-version": 2, "current-snapshot-id": 9223372036854775807, "parent-snapshot-id": null, "properties": {"owner": "alice"}}}`,
+			wantKeys: []string{"write.format.default"},
+		},
+		{
+			name:     "principal",
+			body:     `{"principal": {"name": "etl", "properties": {"team": null}}, "credentials": {"clientId": "id"}}`,
+			want:     `{"principal": {"name": "etl", "properties": {}}, "credentials": {"clientId": "id"}}`,
+			wantKeys: []string{"team"},
+		},
+		{
+			name: "no nulls",
+			body: `{"namespace": ["db"], "properties": {"owner": "alice"}}`,
+			want: `{"namespace": ["db"], "properties": {"owner": "alice"}}`,
+		},
+		{
+			name: "null outside of properties",
+			body: `{"namespace": ["db"], "properties": {}, "next-page-token": null}`,
+			want: `{"namespace": ["db"], "properties": {}, "next-page-token": null}`,
+		},
+		{
+			name: "invalid json",
+			body: `{"properties": {"owner": null`,
+			want: `{"properties": {"owner": null`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, keys := dropNullProperties([]byte(tc.body))
+			assert.Equal(t, tc.wantKeys, keys)
+			if len(tc.wantKeys) == 0 {
+				assert.Equal(t, tc.body, string(got), "a body without null properties is returned unchanged")
+
+				return
+			}
+			assert.JSONEq(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestNullPropertiesWarning(t *testing.T) {
+	_, nulls := withNullProperties(context.Background())
+
+	var diags diag.Diagnostics
+	nulls.addTo("namespace db", &diags)
+	assert.Empty(t, diags, "no warning without null properties")
+
+	nulls.record([]string{"owner", "comment"})
+	nulls.record([]string{"owner"})
+	nulls.addTo("namespace db", &diags)
+	require.Len(t, diags, 1)
+	assert.Equal(t, diag.SeverityWarning, diags[0].Severity())
+	assert.Equal(t, "Null property values ignored", diags[0].Summary())
+	assert.Contains(t, diags[0].Detail(), "namespace db")
+	assert.Contains(t, diags[0].Detail(), ": comment, owner.")
+}
+
+func TestNullPropertiesFromCatalog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/config":
+			_, _ = io.WriteString(w, `{"defaults": {}, "overrides": {}}`)
+		case "/v1/namespaces/db":
+			_, _ = io.WriteString(w, `{"namespace": ["db"], "properties": {"owner": null, "location": "s3://bucket/db"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	p := &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}
+	cat, err := p.NewCatalog(context.Background(), "iceberg_namespace")
+	require.NoError(t, err)
+
+	ctx, nulls := withNullProperties(context.Background())
+	props, err := cat.LoadNamespaceProperties(ctx, []string{"db"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"location": "s3://bucket/db"}, map[string]string(props))
+	var diags diag.Diagnostics
+	nulls.addTo("namespace db", &diags)
+	require.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Detail(), ": owner.")
+}
+
+func TestNullPropertiesFromPolaris(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/management/v1/principals/etl" {
+			http.NotFound(w, r)
+
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"name":       "etl",
+			"properties": map[string]any{"team": nil, "purpose": "ingest"},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	p := &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+
+	ctx, nulls := withNullProperties(context.Background())
+	principal, err := client.GetPrincipal(ctx, "etl")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"purpose": "ingest"}, principal.Properties)
+	var diags diag.Diagnostics
+	nulls.addTo("Polaris principal etl", &diags)
+	require.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Detail(), ": team.")
+}
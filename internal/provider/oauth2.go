@@ -0,0 +1,336 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+const (
+	// oauth2CatalogScope is the scope requested from REST catalogs, as
+	// iceberg-go does.
+	oauth2CatalogScope = "catalog"
+	// oauth2PolarisScope activates all principal roles of the principal in
+	// Polaris, which doesn't know the catalog scope.
+	oauth2PolarisScope = "PRINCIPAL_ROLE:ALL"
+	// oauth2ExpiryMargin is how long before it expires a token is replaced,
+	// so that it doesn't expire while a request is under way.
+	oauth2ExpiryMargin = 30 * time.Second
+)
+
+// oauth2ClientCredentials exchanges a client ID and secret for an access
+// token with the OAuth2 client credentials grant. The token is requested the
+// first time a catalog or management client needs it and is shared by all
+// of them until it expires; a failed request is retried by the next one.
+type oauth2ClientCredentials struct {
+	clientID     string
+	clientSecret string
+	serverURI    string
+	scope        string
+	// audience is sent with the token request unless it is empty.
+	audience string
+
+	mu    sync.Mutex
+	token string
+	// expiry is when token is replaced, zero if the server didn't say when
+	// it expires.
+	expiry time.Time
+}
+
+// validateOAuth2Config checks the oauth2_* and credential provider
+// attributes of data and returns the credentials to use, or nil if OAuth2
+// isn't configured. credential is split into the client ID and secret as
+// pyiceberg does; without a colon it is the ID of a public client, which has
+// no secret. oauth2_server_uri defaults to the token endpoint of the REST
+// catalog and oauth2_scope to scope.
+func validateOAuth2Config(data icebergProviderModel, catalogURI, scope string, diags *diag.Diagnostics) *oauth2ClientCredentials {
+	if data.OAuth2ClientID.IsUnknown() || data.OAuth2ClientSecret.IsUnknown() || data.OAuth2ServerURI.IsUnknown() ||
+		data.OAuth2Scope.IsUnknown() || data.OAuth2Audience.IsUnknown() || data.Credential.IsUnknown() {
+		// Known by the time resources are applied.
+		return nil
+	}
+
+	clientID := data.OAuth2ClientID.ValueString()
+	clientSecret := data.OAuth2ClientSecret.ValueString()
+	serverURI := data.OAuth2ServerURI.ValueString()
+	publicClient := false
+	if !data.Credential.IsNull() {
+		if !data.OAuth2ClientID.IsNull() || !data.OAuth2ClientSecret.IsNull() {
+			diags.AddError(
+				"Conflicting authentication settings",
+				"credential and oauth2_client_id or oauth2_client_secret can't both be set: credential already has the client ID and secret, as client_id:client_secret. Remove one of them.",
+			)
+
+			return nil
+		}
+		var hasSecret bool
+		clientID, clientSecret, hasSecret = strings.Cut(data.Credential.ValueString(), ":")
+		publicClient = !hasSecret
+		if clientID == "" || (hasSecret && clientSecret == "") {
+			diags.AddError(
+				"Invalid OAuth2 configuration",
+				"credential must be a client ID and secret separated by a colon, as client_id:client_secret, or the ID of a public client.",
+			)
+
+			return nil
+		}
+	}
+	if clientID == "" && clientSecret == "" && serverURI == "" {
+		if !data.OAuth2Scope.IsNull() || !data.OAuth2Audience.IsNull() {
+			diags.AddError(
+				"Invalid OAuth2 configuration",
+				"oauth2_scope and oauth2_audience only apply to the token requested for oauth2_client_id or credential. Set one of them or remove oauth2_scope and oauth2_audience.",
+			)
+		}
+
+		return nil
+	}
+
+	switch {
+	case clientID == "" || (clientSecret == "" && !publicClient):
+		diags.AddError(
+			"Invalid OAuth2 configuration",
+			"oauth2_client_id and oauth2_client_secret must be set together.",
+		)
+
+		return nil
+	case data.Token.ValueString() != "":
+		diags.AddError(
+			"Conflicting authentication settings",
+			"token and oauth2_client_id or credential can't both be set: the token would be replaced by the one issued for the client. Remove one of them.",
+		)
+
+		return nil
+	}
+
+	if serverURI == "" {
+		serverURI = strings.TrimRight(catalogURI, "/") + "/v1/oauth/tokens"
+	}
+	if u, err := url.Parse(serverURI); err != nil || u.Scheme == "" || u.Host == "" {
+		diags.AddError(
+			"Invalid OAuth2 configuration",
+			fmt.Sprintf("oauth2_server_uri %q isn't an absolute URL.", serverURI),
+		)
+
+		return nil
+	}
+
+	if !data.OAuth2Scope.IsNull() {
+		scope = data.OAuth2Scope.ValueString()
+	}
+
+	return &oauth2ClientCredentials{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		serverURI:    serverURI,
+		scope:        scope,
+		audience:     data.OAuth2Audience.ValueString(),
+	}
+}
+
+// accessToken returns the bearer token for requests made for resourceType:
+// the token attribute, or one issued for the OAuth2 client.
+func (p *icebergProvider) accessToken(ctx context.Context, resourceType string) (string, error) {
+	if p.oauth2 == nil {
+		return p.token, nil
+	}
+
+	return p.oauth2.accessToken(ctx, &http.Client{Transport: p.tokenTransport(resourceType, p.oauth2.serverURI)})
+}
+
+// tokenTransport returns the transport of token requests to serverURI made
+// for resourceType. The token request is a POST, which read_only doesn't
+// refuse since it doesn't change the catalog.
+func (p *icebergProvider) tokenTransport(resourceType, serverURI string) http.RoundTripper {
+	transport := p.baseTransport()
+	if p.metrics != nil {
+		transport = &metricsTransport{next: transport, metrics: p.metrics, resourceType: resourceType}
+	}
+	transport = p.withRetries(transport)
+	// A token endpoint of the catalog gets the headers of all catalog
+	// requests; those for the catalog aren't sent to other servers.
+	if sameHost(serverURI, p.catalogURI) {
+		transport = &headerRoundTripper{headers: p.headers, next: transport}
+	}
+
+	return transport
+}
+
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+
+	return ua.Host == ub.Host
+}
+
+func (o *oauth2ClientCredentials) accessToken(ctx context.Context, client *http.Client) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.token != "" && (o.expiry.IsZero() || time.Now().Before(o.expiry)) {
+		return o.token, nil
+	}
+
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+		"client_id":  {o.clientID},
+	}
+	// Public clients, from a credential without a secret, only send their ID.
+	if o.clientSecret != "" {
+		form.Set("client_secret", o.clientSecret)
+	}
+	if o.scope != "" {
+		form.Set("scope", o.scope)
+	}
+	if o.audience != "" {
+		form.Set("audience", o.audience)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.serverURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request OAuth2 token from %s: %w", o.serverURI, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read OAuth2 token response from %s: %w", o.serverURI, err)
+	}
+
+	var tok struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	decodeErr := json.Unmarshal(body, &tok)
+
+	if resp.StatusCode != http.StatusOK {
+		if tok.Error == "" {
+			return "", fmt.Errorf("OAuth2 token request to %s failed: %s", o.serverURI, resp.Status)
+		}
+		if tok.ErrorDescription == "" {
+			return "", fmt.Errorf("OAuth2 token request to %s failed: %s: %s", o.serverURI, resp.Status, tok.Error)
+		}
+
+		return "", fmt.Errorf("OAuth2 token request to %s failed: %s: %s: %s", o.serverURI, resp.Status, tok.Error, tok.ErrorDescription)
+	}
+	if decodeErr != nil {
+		return "", fmt.Errorf("decode OAuth2 token response from %s: %w", o.serverURI, decodeErr)
+	}
+	if tok.AccessToken == "" {
+		return "", fmt.Errorf("OAuth2 token response from %s has no access_token", o.serverURI)
+	}
+
+	o.token = tok.AccessToken
+	o.expiry = time.Time{}
+	if tok.ExpiresIn > 0 {
+		o.expiry = time.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - oauth2ExpiryMargin)
+	}
+
+	return o.token, nil
+}
+
+// invalidate drops token, which the server rejected, so that the next call
+// of accessToken requests a new one. It reports whether a retry gets another
+// token, which it doesn't if the request had none.
+func (o *oauth2ClientCredentials) invalidate(token string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if token == "" {
+		return false
+	}
+	if o.token == token {
+		o.token = ""
+	}
+
+	return true
+}
+
+// bearerTokenTransport sets the Authorization header of catalog requests to
+// the bearer token of the provider, which is requested again once it expires.
+// A request the server rejects with 401 is retried once with a new token, as
+// the management client does, since issued tokens can also be revoked.
+type bearerTokenTransport struct {
+	next http.RoundTripper
+	// token returns the token for a request.
+	token func(ctx context.Context) (string, error)
+	// invalidate, if set, drops a token the server rejected, see
+	// oauth2ClientCredentials.invalidate. Static tokens can't be replaced.
+	invalidate func(token string) bool
+}
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, token, err := t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || t.invalidate == nil {
+		return resp, err
+	}
+	body, ok := replayableBody(req)
+	if !ok || !t.invalidate(token) {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	retry.Body = body
+	resp, _, err = t.send(retry)
+
+	return resp, err
+}
+
+// send sends req with the current token and returns the token it was sent
+// with.
+func (t *bearerTokenTransport) send(req *http.Request) (*http.Response, string, error) {
+	token, err := t.token(req.Context())
+	if err != nil {
+		return nil, "", err
+	}
+	if token == "" {
+		resp, err := t.next.RoundTrip(req)
+
+		return resp, "", err
+	}
+
+	// A RoundTripper mustn't modify the request it was given.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := t.next.RoundTrip(req)
+
+	return resp, token, err
+}
@@ -0,0 +1,402 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOAuth2Config(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		data       icebergProviderModel
+		wantURI    string
+		wantScope  string
+		wantID     string
+		wantSecret string
+		wantError  string
+	}{
+		{
+			name: "not configured",
+		},
+		{
+			name:      "default server uri",
+			data:      icebergProviderModel{OAuth2ClientID: types.StringValue("etl"), OAuth2ClientSecret: types.StringValue("s3cr3t")},
+			wantURI:   "http://catalog/api/catalog/v1/oauth/tokens",
+			wantScope: oauth2CatalogScope,
+		},
+		{
+			name: "scope",
+			data: icebergProviderModel{
+				OAuth2ClientID:     types.StringValue("etl"),
+				OAuth2ClientSecret: types.StringValue("s3cr3t"),
+				OAuth2Scope:        types.StringValue("catalog:read catalog:write"),
+			},
+			wantURI:   "http://catalog/api/catalog/v1/oauth/tokens",
+			wantScope: "catalog:read catalog:write",
+		},
+		{
+			name:       "credential",
+			data:       icebergProviderModel{Credential: types.StringValue("etl:s3:cr3t")},
+			wantURI:    "http://catalog/api/catalog/v1/oauth/tokens",
+			wantScope:  oauth2CatalogScope,
+			wantID:     "etl",
+			wantSecret: "s3:cr3t",
+		},
+		{
+			name:      "credential of a public client",
+			data:      icebergProviderModel{Credential: types.StringValue("etl")},
+			wantURI:   "http://catalog/api/catalog/v1/oauth/tokens",
+			wantScope: oauth2CatalogScope,
+			wantID:    "etl",
+		},
+		{
+			name: "credential and client id",
+			data: icebergProviderModel{
+				Credential:     types.StringValue("etl:s3cr3t"),
+				OAuth2ClientID: types.StringValue("etl"),
+			},
+			wantError: "credential and oauth2_client_id or oauth2_client_secret can't both be set",
+		},
+		{
+			name:      "credential without client id",
+			data:      icebergProviderModel{Credential: types.StringValue(":s3cr3t")},
+			wantError: "credential must be a client ID and secret separated by a colon",
+		},
+		{
+			name: "unknown credential",
+			data: icebergProviderModel{Credential: types.StringUnknown()},
+		},
+		{
+			name: "credential and token",
+			data: icebergProviderModel{
+				Token:      types.StringValue("tok"),
+				Credential: types.StringValue("etl:s3cr3t"),
+			},
+			wantError: "can't both be set",
+		},
+		{
+			name:      "audience without client",
+			data:      icebergProviderModel{OAuth2Audience: types.StringValue("https://catalog")},
+			wantError: "only apply to the token requested for oauth2_client_id",
+		},
+		{
+			name: "server uri",
+			data: icebergProviderModel{
+				OAuth2ClientID:     types.StringValue("etl"),
+				OAuth2ClientSecret: types.StringValue("s3cr3t"),
+				OAuth2ServerURI:    types.StringValue("https://idp/token"),
+			},
+			wantURI:   "https://idp/token",
+			wantScope: oauth2CatalogScope,
+		},
+		{
+			name: "unknown secret",
+			data: icebergProviderModel{OAuth2ClientID: types.StringValue("etl"), OAuth2ClientSecret: types.StringUnknown()},
+		},
+		{
+			name:      "missing secret",
+			data:      icebergProviderModel{OAuth2ClientID: types.StringValue("etl")},
+			wantError: "must be set together",
+		},
+		{
+			name: "token",
+			data: icebergProviderModel{
+				Token:              types.StringValue("tok"),
+				OAuth2ClientID:     types.StringValue("etl"),
+				OAuth2ClientSecret: types.StringValue("s3cr3t"),
+			},
+			wantError: "can't both be set",
+		},
+		{
+			name: "relative server uri",
+			data: icebergProviderModel{
+				OAuth2ClientID:     types.StringValue("etl"),
+				OAuth2ClientSecret: types.StringValue("s3cr3t"),
+				OAuth2ServerURI:    types.StringValue("/oauth/tokens"),
+			},
+			wantError: "isn't an absolute URL",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			creds := validateOAuth2Config(tc.data, "http://catalog/api/catalog/", oauth2CatalogScope, &diags)
+			if tc.wantError != "" {
+				require.Len(t, diags.Errors(), 1)
+				assert.Contains(t, diags.Errors()[0].Detail(), tc.wantError)
+				assert.Nil(t, creds)
+
+				return
+			}
+			require.False(t, diags.HasError(), "%v", diags)
+			if tc.wantURI == "" {
+				assert.Nil(t, creds)
+
+				return
+			}
+			require.NotNil(t, creds)
+			assert.Equal(t, tc.wantURI, creds.serverURI)
+			assert.Equal(t, tc.wantScope, creds.scope)
+			if tc.wantID != "" {
+				assert.Equal(t, tc.wantID, creds.clientID)
+				assert.Equal(t, tc.wantSecret, creds.clientSecret)
+			}
+		})
+	}
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var tokenRequests atomic.Int32
+	var catalogAuth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/oauth/tokens":
+			tokenRequests.Add(1)
+			assert.Equal(t, "tenant-1", r.Header.Get("X-Tenant-Id"), "the token endpoint of the catalog gets the catalog headers")
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "PRINCIPAL_ROLE:ALL", r.PostForm.Get("scope"))
+			if r.PostForm.Get("client_id") != "etl" || r.PostForm.Get("client_secret") != "s3cr3t" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = io.WriteString(w, `{"error": "invalid_client", "error_description": "The client is not authorized"}`)
+
+				return
+			}
+			_, _ = io.WriteString(w, `{"access_token": "issued", "token_type": "bearer", "expires_in": 3600}`)
+		case "/v1/config":
+			catalogAuth = append(catalogAuth, r.Header.Get("Authorization"))
+			_, _ = io.WriteString(w, `{"defaults": {}, "overrides": {}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	newProvider := func(secret string) *icebergProvider {
+		var diags diag.Diagnostics
+		creds := validateOAuth2Config(icebergProviderModel{
+			OAuth2ClientID:     types.StringValue("etl"),
+			OAuth2ClientSecret: types.StringValue(secret),
+		}, server.URL, oauth2PolarisScope, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		return &icebergProvider{
+			catalogURI:   server.URL,
+			catalogType:  "rest",
+			oauth2:       creds,
+			headers:      map[string]string{"X-Tenant-Id": "tenant-1"},
+			readOnly:     true,
+			capabilities: newCatalogCapabilities(),
+		}
+	}
+
+	t.Run("token is requested once", func(t *testing.T) {
+		tokenRequests.Store(0)
+		catalogAuth = nil
+		p := newProvider("s3cr3t")
+		assert.Zero(t, tokenRequests.Load(), "no token is requested before a catalog is used")
+
+		for _, resourceType := range []string{"iceberg_namespace", "iceberg_table"} {
+			_, err := p.NewCatalog(context.Background(), resourceType)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), tokenRequests.Load())
+		assert.Equal(t, []string{"Bearer issued", "Bearer issued"}, catalogAuth)
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		tokenRequests.Store(0)
+		catalogAuth = nil
+		r := &icebergNamespaceResource{provider: newProvider("wrong")}
+
+		var diags diag.Diagnostics
+		r.ConfigureCatalog(context.Background(), &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Failed to create catalog", diags.Errors()[0].Summary())
+		assert.Contains(t, diags.Errors()[0].Detail(), "401 Unauthorized: invalid_client: The client is not authorized")
+		assert.Nil(t, r.catalog)
+		assert.Empty(t, catalogAuth, "the catalog isn't called without a token")
+	})
+}
+
+func TestOAuth2TokenRequestScopeAndAudience(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		body = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token": "issued", "token_type": "bearer"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	for _, tc := range []struct {
+		name   string
+		data   icebergProviderModel
+		want   url.Values
+		public bool
+	}{
+		{
+			name: "defaults",
+			want: url.Values{"scope": {oauth2PolarisScope}},
+		},
+		{
+			name: "scope and audience",
+			data: icebergProviderModel{
+				OAuth2Scope:    types.StringValue("PRINCIPAL_ROLE:ALL offline_access"),
+				OAuth2Audience: types.StringValue("https://polaris.example.com"),
+			},
+			want: url.Values{"scope": {"PRINCIPAL_ROLE:ALL offline_access"}, "audience": {"https://polaris.example.com"}},
+		},
+		{
+			name: "empty scope",
+			data: icebergProviderModel{OAuth2Scope: types.StringValue("")},
+			want: url.Values{},
+		},
+		{
+			name: "credential",
+			data: icebergProviderModel{Credential: types.StringValue("etl:s3cr3t")},
+			want: url.Values{"scope": {oauth2PolarisScope}},
+		},
+		{
+			name: "credential of a public client",
+			data: icebergProviderModel{Credential: types.StringValue("etl")},
+			want: url.Values{"scope": {oauth2PolarisScope}},
+			// Public clients have no secret to send.
+			public: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.data.Credential.IsNull() {
+				tc.data.OAuth2ClientID = types.StringValue("etl")
+				tc.data.OAuth2ClientSecret = types.StringValue("s3cr3t")
+			}
+			tc.data.OAuth2ServerURI = types.StringValue(server.URL + "/token")
+
+			var diags diag.Diagnostics
+			creds := validateOAuth2Config(tc.data, "", oauth2PolarisScope, &diags)
+			require.False(t, diags.HasError(), "%v", diags)
+			token, err := creds.accessToken(context.Background(), server.Client())
+			require.NoError(t, err)
+			assert.Equal(t, "issued", token)
+
+			form, err := url.ParseQuery(body)
+			require.NoError(t, err)
+			tc.want.Set("grant_type", "client_credentials")
+			tc.want.Set("client_id", "etl")
+			if !tc.public {
+				tc.want.Set("client_secret", "s3cr3t")
+			}
+			assert.Equal(t, tc.want, form)
+		})
+	}
+}
+
+func TestOAuth2ShortLivedTokens(t *testing.T) {
+	var issued atomic.Int32
+	var expiresIn atomic.Int32
+	// current is the only token the catalog accepts, as if all others had
+	// expired or were revoked.
+	var current atomic.Value
+	var catalogAuth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/oauth/tokens":
+			token := fmt.Sprintf("token-%d", issued.Add(1))
+			current.Store(token)
+			_, _ = fmt.Fprintf(w, `{"access_token": %q, "token_type": "bearer", "expires_in": %d}`, token, expiresIn.Load())
+		case "/v1/config":
+			_, _ = io.WriteString(w, `{"defaults": {}, "overrides": {}}`)
+		case "/v1/namespaces":
+			catalogAuth = append(catalogAuth, r.Header.Get("Authorization"))
+			if r.Header.Get("Authorization") != "Bearer "+current.Load().(string) {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = io.WriteString(w, `{"error": {"message": "token expired", "type": "NotAuthorizedException", "code": 401}}`)
+
+				return
+			}
+			_, _ = io.WriteString(w, `{"namespaces": []}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	newProvider := func() *icebergProvider {
+		var diags diag.Diagnostics
+		creds := validateOAuth2Config(icebergProviderModel{
+			OAuth2ClientID:     types.StringValue("etl"),
+			OAuth2ClientSecret: types.StringValue("s3cr3t"),
+		}, server.URL, oauth2CatalogScope, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		return &icebergProvider{
+			catalogURI:    server.URL,
+			catalogType:   "rest",
+			oauth2:        creds,
+			capabilities:  newCatalogCapabilities(),
+			sharedCatalog: &sharedCatalog{},
+		}
+	}
+
+	t.Run("expired tokens are replaced", func(t *testing.T) {
+		issued.Store(0)
+		catalogAuth = nil
+		// Tokens that expire within oauth2ExpiryMargin are replaced for
+		// every request.
+		expiresIn.Store(1)
+		cat, err := newProvider().NewCatalog(context.Background(), "iceberg_namespace")
+		require.NoError(t, err)
+
+		for range 2 {
+			_, err := cat.ListNamespaces(context.Background(), nil)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, []string{"Bearer token-2", "Bearer token-3"}, catalogAuth)
+	})
+
+	t.Run("rejected tokens are replaced", func(t *testing.T) {
+		issued.Store(0)
+		catalogAuth = nil
+		expiresIn.Store(3600)
+		cat, err := newProvider().NewCatalog(context.Background(), "iceberg_namespace")
+		require.NoError(t, err)
+		_, err = cat.ListNamespaces(context.Background(), nil)
+		require.NoError(t, err)
+
+		// The server revokes the token before it expires.
+		current.Store("revoked")
+		_, err = cat.ListNamespaces(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Bearer token-1", "Bearer token-1", "Bearer token-2"}, catalogAuth)
+		assert.Equal(t, int32(2), issued.Load())
+	})
+}
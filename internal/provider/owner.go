@@ -0,0 +1,85 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ownerProperty is the property catalogs and engines read the owner of a
+// table or namespace from. It is managed through the owner attribute.
+const ownerProperty = "owner"
+
+// withOwner returns a copy of props with the owner attribute added as the
+// owner property. props is returned as is if the owner isn't set.
+func withOwner(props map[string]string, owner types.String) map[string]string {
+	return withAttributeProperty(props, ownerProperty, owner)
+}
+
+// syncOwner returns the owner property of the server as the new value of the
+// owner attribute. An owner that isn't managed by Terraform stays null.
+func syncOwner(owner types.String, server map[string]string) types.String {
+	return syncAttributeProperty(owner, ownerProperty, server)
+}
+
+// validateOwnerConfig rejects configurations that set the owner both with the
+// owner attribute and in user_properties.
+func validateOwnerConfig(owner types.String, userProperties types.Map, diags *diag.Diagnostics) {
+	validateAttributePropertyConfig(ownerProperty, owner, userProperties, diags)
+}
+
+// checkOwnerPrincipal warns when the planned owner isn't a Polaris principal,
+// which is most likely a typo. The check is skipped for other catalogs and
+// with skip_owner_validation, and an owner that is already applied isn't
+// checked again.
+func (p *icebergProvider) checkOwnerPrincipal(ctx context.Context, resourceType string, plan, state types.String, diags *diag.Diagnostics) {
+	if p == nil || p.polaris == nil || p.skipOwnerValidation {
+		return
+	}
+	if plan.IsNull() || plan.IsUnknown() || plan.Equal(state) {
+		return
+	}
+
+	client, err := p.newPolarisManagementClient(resourceType)
+	if err != nil {
+		tflog.Debug(ctx, "Skipping the owner check: "+err.Error())
+
+		return
+	}
+
+	owner := plan.ValueString()
+	_, err = client.GetPrincipal(ctx, owner)
+	switch {
+	case isPolarisNotFoundError(err):
+		diags.AddAttributeWarning(
+			path.Root("owner"),
+			"Owner is not a Polaris principal",
+			fmt.Sprintf("No Polaris principal is named %q. The owner property is set anyway; set skip_owner_validation in the provider configuration if owners aren't principals.", owner),
+		)
+	case err != nil:
+		diags.AddAttributeWarning(
+			path.Root("owner"),
+			"Unable to check owner",
+			fmt.Sprintf("Failed to look up the Polaris principal %q: %s", owner, err),
+		)
+	}
+}
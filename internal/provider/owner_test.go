@@ -0,0 +1,188 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnerProperty(t *testing.T) {
+	props := map[string]string{"comment": "Orders"}
+
+	assert.Equal(t, props, withOwner(props, types.StringNull()))
+	assert.Equal(t, map[string]string{"comment": "Orders", "owner": "data_eng"}, withOwner(props, types.StringValue("data_eng")))
+	assert.True(t, syncOwner(types.StringNull(), map[string]string{"owner": "spark"}).IsNull(), "an unmanaged owner stays null")
+	assert.Equal(t, types.StringValue("spark"), syncOwner(types.StringValue("data_eng"), map[string]string{"owner": "spark"}))
+
+	var diags diag.Diagnostics
+	validateOwnerConfig(types.StringValue("data_eng"), types.MapValueMust(types.StringType, map[string]attr.Value{"owner": types.StringValue("x")}), &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Conflicting owner configuration", diags.Errors()[0].Summary())
+}
+
+func TestCheckOwnerPrincipal(t *testing.T) {
+	ctx := context.Background()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/api/management/v1/principals/data_eng":
+			writeJSON(w, http.StatusOK, map[string]any{"name": "data_eng"})
+		case "/api/management/v1/principals/broken":
+			writeJSON(w, http.StatusInternalServerError, map[string]any{})
+		default:
+			writeJSON(w, http.StatusNotFound, map[string]any{})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	polaris := &polarisConfig{managementURI: server.URL + "/api/management/v1"}
+
+	tests := []struct {
+		name         string
+		provider     *icebergProvider
+		plan, state  types.String
+		wantWarning  string
+		wantRequests int
+	}{
+		{
+			name:         "existing principal",
+			provider:     &icebergProvider{polaris: polaris},
+			plan:         types.StringValue("data_eng"),
+			state:        types.StringNull(),
+			wantRequests: 1,
+		},
+		{
+			name:         "missing principal",
+			provider:     &icebergProvider{polaris: polaris},
+			plan:         types.StringValue("data_engg"),
+			state:        types.StringNull(),
+			wantWarning:  "Owner is not a Polaris principal",
+			wantRequests: 1,
+		},
+		{
+			name:         "failed lookup",
+			provider:     &icebergProvider{polaris: polaris},
+			plan:         types.StringValue("broken"),
+			state:        types.StringValue("data_eng"),
+			wantWarning:  "Unable to check owner",
+			wantRequests: 1,
+		},
+		{
+			name:     "unchanged owner",
+			provider: &icebergProvider{polaris: polaris},
+			plan:     types.StringValue("data_engg"),
+			state:    types.StringValue("data_engg"),
+		},
+		{
+			name:     "skipped",
+			provider: &icebergProvider{polaris: polaris, skipOwnerValidation: true},
+			plan:     types.StringValue("data_engg"),
+			state:    types.StringNull(),
+		},
+		{
+			name:     "not polaris",
+			provider: &icebergProvider{},
+			plan:     types.StringValue("data_engg"),
+			state:    types.StringNull(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 0
+
+			var diags diag.Diagnostics
+			tt.provider.checkOwnerPrincipal(ctx, "iceberg_table", tt.plan, tt.state, &diags)
+			require.False(t, diags.HasError(), "%v", diags)
+			if tt.wantWarning == "" {
+				assert.Empty(t, diags.Warnings())
+			} else {
+				require.Len(t, diags.Warnings(), 1)
+				assert.Equal(t, tt.wantWarning, diags.Warnings()[0].Summary())
+			}
+			assert.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func TestAccIcebergTable_Owner(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := func(owner string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "orders" {
+  namespace = ["db"]
+  name      = "orders"
+  %s
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL, owner)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`owner = "data_eng"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.orders", "owner", "data_eng"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.owner", "data_eng"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "server_managed_properties.owner"),
+				),
+			},
+			{
+				Config: config(`owner = "analytics"`),
+				Check:  resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.owner", "analytics"),
+			},
+			{
+				Config: config(`owner = "analytics"
+  user_properties = {
+    owner = "data_eng"
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Conflicting owner configuration`),
+			},
+		},
+	})
+}
@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// validatePolarisOAuth2Config returns the credentials the management API
+// token is requested with, from client_id and client_secret of
+// polaris_settings, or nil if they aren't set. Polaris grants management
+// privileges through principal roles, so the token is requested with the
+// PRINCIPAL_ROLE:ALL scope from the token endpoint of the catalog, or
+// oauth2_server_uri. A token attribute takes precedence; the credentials are
+// then ignored with a warning.
+func validatePolarisOAuth2Config(data icebergProviderModel, catalogURI string, diags *diag.Diagnostics) *oauth2ClientCredentials {
+	settings := data.PolarisSettings
+	if settings == nil || settings.ClientID.IsUnknown() || settings.ClientSecret.IsUnknown() || data.OAuth2ServerURI.IsUnknown() {
+		return nil
+	}
+	clientID := settings.ClientID.ValueString()
+	clientSecret := settings.ClientSecret.ValueString()
+	if clientID == "" && clientSecret == "" {
+		return nil
+	}
+	if clientID == "" || clientSecret == "" {
+		diags.AddAttributeError(path.Root("polaris_settings"), "Invalid OAuth2 configuration",
+			"polaris_settings.client_id and polaris_settings.client_secret must be set together.")
+
+		return nil
+	}
+	if data.Token.ValueString() != "" {
+		diags.AddAttributeWarning(path.Root("polaris_settings"), "Polaris client credentials not used",
+			"token is set, so it authenticates the requests to the management API and polaris_settings.client_id and polaris_settings.client_secret are ignored. Remove one of them.")
+
+		return nil
+	}
+
+	serverURI := data.OAuth2ServerURI.ValueString()
+	if serverURI == "" {
+		serverURI = strings.TrimRight(catalogURI, "/") + "/v1/oauth/tokens"
+	}
+
+	return &oauth2ClientCredentials{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		serverURI:    serverURI,
+		scope:        oauth2PolarisScope,
+	}
+}
+
+// polarisCredentials returns the OAuth2 credentials management API tokens
+// are issued for: those of polaris_settings, or those of the catalog. It is
+// nil when the token attribute is used instead.
+func (p *icebergProvider) polarisCredentials() *oauth2ClientCredentials {
+	if p.polaris != nil && p.polaris.oauth2 != nil {
+		return p.polaris.oauth2
+	}
+
+	return p.oauth2
+}
+
+// polarisAccessToken returns the bearer token for management API requests
+// made for resourceType.
+func (p *icebergProvider) polarisAccessToken(ctx context.Context, resourceType string) (string, error) {
+	creds := p.polarisCredentials()
+	if creds == nil {
+		return p.token, nil
+	}
+
+	return creds.accessToken(ctx, &http.Client{Transport: p.tokenTransport(resourceType, creds.serverURI)})
+}
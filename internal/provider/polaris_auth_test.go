@@ -0,0 +1,226 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// polarisSettingsValue returns a polaris_settings block of the provider
+// schema with attrs set.
+func polarisSettingsValue(attrs map[string]tftypes.Value) tftypes.Value {
+	attrTypes := map[string]tftypes.Type{
+		"management_uri": tftypes.String,
+		"catalog_name":   tftypes.String,
+		"client_id":      tftypes.String,
+		"client_secret":  tftypes.String,
+	}
+	values := make(map[string]tftypes.Value, len(attrTypes))
+	for name, typ := range attrTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	maps.Copy(values, attrs)
+
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: attrTypes}, values)
+}
+
+// mockPolarisAuthServer serves the token endpoint of a Polaris catalog and
+// the principal etl of its management API, which only accepts the last token
+// issued until expire is called.
+type mockPolarisAuthServer struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	tokenRequests []map[string]string
+	valid         string
+	authorization []string
+}
+
+func newMockPolarisAuthServer(t *testing.T) *mockPolarisAuthServer {
+	t.Helper()
+
+	m := &mockPolarisAuthServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/catalog/v1/oauth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+		m.tokenRequests = append(m.tokenRequests, map[string]string{
+			"client_id":     r.PostForm.Get("client_id"),
+			"client_secret": r.PostForm.Get("client_secret"),
+			"scope":         r.PostForm.Get("scope"),
+		})
+		m.valid = fmt.Sprintf("issued-%d", len(m.tokenRequests))
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": m.valid, "token_type": "bearer", "expires_in": 3600})
+	})
+	mux.HandleFunc("GET /api/management/v1/principals/etl", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		auth := r.Header.Get("Authorization")
+		m.authorization = append(m.authorization, auth)
+		if auth != "Bearer "+m.valid && auth != "Bearer static" {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": map[string]any{"message": "token expired", "type": "NotAuthorizedException", "code": 401}})
+
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"name": "etl", "entityVersion": 1})
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// expire makes the management API reject the tokens issued so far.
+func (m *mockPolarisAuthServer) expire() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.valid = ""
+}
+
+func TestPolarisManagementClientCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("token requested and refreshed", func(t *testing.T) {
+		server := newMockPolarisAuthServer(t)
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":        tftypes.NewValue(tftypes.String, "polaris"),
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL+"/api/catalog"),
+			"polaris_settings": polarisSettingsValue(map[string]tftypes.Value{
+				"client_id":     tftypes.NewValue(tftypes.String, "admin"),
+				"client_secret": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			}),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Empty(t, diags.Warnings())
+
+		client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+		require.NoError(t, err)
+
+		_, err = client.GetPrincipal(ctx, "etl")
+		require.NoError(t, err)
+		_, err = client.GetPrincipal(ctx, "etl")
+		require.NoError(t, err)
+		require.Len(t, server.tokenRequests, 1, "the token is cached")
+		assert.Equal(t, map[string]string{"client_id": "admin", "client_secret": "s3cr3t", "scope": "PRINCIPAL_ROLE:ALL"}, server.tokenRequests[0])
+
+		server.expire()
+		_, err = client.GetPrincipal(ctx, "etl")
+		require.NoError(t, err)
+		require.Len(t, server.tokenRequests, 2)
+		assert.Equal(t, []string{"Bearer issued-1", "Bearer issued-1", "Bearer issued-1", "Bearer issued-2"}, server.authorization)
+	})
+
+	t.Run("rejected token is retried once", func(t *testing.T) {
+		server := newMockPolarisAuthServer(t)
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":        tftypes.NewValue(tftypes.String, "polaris"),
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL+"/api/catalog"),
+			"polaris_settings": polarisSettingsValue(map[string]tftypes.Value{
+				"client_id":     tftypes.NewValue(tftypes.String, "admin"),
+				"client_secret": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			}),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+		require.NoError(t, err)
+
+		// The management API rejects every token from now on.
+		client.token = func(ctx context.Context) (string, error) {
+			tok, err := p.polarisAccessToken(ctx, "iceberg_polaris_principal")
+			server.expire()
+
+			return tok, err
+		}
+		_, err = client.GetPrincipal(ctx, "etl")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "token expired")
+		assert.Len(t, server.authorization, 2)
+	})
+
+	t.Run("static token wins", func(t *testing.T) {
+		server := newMockPolarisAuthServer(t)
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":        tftypes.NewValue(tftypes.String, "polaris"),
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL+"/api/catalog"),
+			"token":       tftypes.NewValue(tftypes.String, "static"),
+			"polaris_settings": polarisSettingsValue(map[string]tftypes.Value{
+				"client_id":     tftypes.NewValue(tftypes.String, "admin"),
+				"client_secret": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			}),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Len(t, diags.Warnings(), 1)
+		assert.Equal(t, "Polaris client credentials not used", diags.Warnings()[0].Summary())
+
+		client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+		require.NoError(t, err)
+		_, err = client.GetPrincipal(ctx, "etl")
+		require.NoError(t, err)
+		assert.Empty(t, server.tokenRequests)
+		assert.Equal(t, []string{"Bearer static"}, server.authorization)
+	})
+
+	t.Run("catalog credentials", func(t *testing.T) {
+		server := newMockPolarisAuthServer(t)
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":                 tftypes.NewValue(tftypes.String, "polaris"),
+			"catalog_uri":          tftypes.NewValue(tftypes.String, server.URL+"/api/catalog"),
+			"oauth2_client_id":     tftypes.NewValue(tftypes.String, "etl"),
+			"oauth2_client_secret": tftypes.NewValue(tftypes.String, "etl-secret"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+
+		client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+		require.NoError(t, err)
+		_, err = client.GetPrincipal(ctx, "etl")
+		require.NoError(t, err)
+		server.expire()
+		_, err = client.GetPrincipal(ctx, "etl")
+		require.NoError(t, err)
+		require.Len(t, server.tokenRequests, 2)
+		assert.Equal(t, "etl", server.tokenRequests[1]["client_id"])
+	})
+
+	t.Run("client secret missing", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":        tftypes.NewValue(tftypes.String, "polaris"),
+			"catalog_uri": tftypes.NewValue(tftypes.String, "http://localhost:8181/api/catalog"),
+			"polaris_settings": polarisSettingsValue(map[string]tftypes.Value{
+				"client_id": tftypes.NewValue(tftypes.String, "admin"),
+			}),
+		})
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "polaris_settings.client_id and polaris_settings.client_secret must be set together.", diags.Errors()[0].Detail())
+	})
+}
@@ -30,21 +30,84 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 )
 
 type polarisManagementClient struct {
 	baseURL    *url.URL
 	httpClient *http.Client
-	token      string
-	headers    map[string]string
+	// token returns the bearer token for a request, if any.
+	token func(context.Context) (string, error)
+	// invalidateToken, if set, drops a token the server rejected, like
+	// oauth2ClientCredentials.invalidate. Static tokens can't be replaced.
+	invalidateToken func(token string) bool
+	headers         map[string]string
+	// capabilities records the version of the server, see polaris_version.go.
+	capabilities *catalogCapabilities
+}
+
+// errPolarisConflict matches errors of the management API rejecting a change
+// because the entity was changed since it was read, or because of the state of
+// related entities.
+var errPolarisConflict = errors.New("polaris management: conflict")
+
+// polarisAPIError is an error response of the management API. Type and
+// Message are taken from the error body when the server sends one.
+type polarisAPIError struct {
+	Status  int
+	Type    string
+	Message string
+}
+
+func (e *polarisAPIError) Error() string {
+	msg := e.Message
+	if e.Type != "" {
+		msg = e.Type + ": " + msg
+	}
+	if e.Status == http.StatusConflict {
+		return fmt.Sprintf("%s: %s", errPolarisConflict, msg)
+	}
+
+	return fmt.Sprintf("polaris management: unexpected status %d: %s", e.Status, msg)
+}
+
+func (e *polarisAPIError) Is(target error) bool {
+	return target == errPolarisConflict && e.Status == http.StatusConflict
+}
+
+// newPolarisAPIError reads the error response of the management API, which
+// has the form {"error": {"message": ..., "type": ..., "code": ...}}. Bodies
+// that aren't in that form are kept as the message.
+func newPolarisAPIError(resp *http.Response) *polarisAPIError {
+	bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	apiErr := &polarisAPIError{Status: resp.StatusCode, Message: string(bodyBytes)}
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(bodyBytes, &body) == nil && body.Error.Message != "" {
+		apiErr.Type = body.Error.Type
+		apiErr.Message = body.Error.Message
+	}
+
+	return apiErr
 }
 
 type polarisNotFoundError struct {
 	method string
 	path   string
+	// message is the reason given by the server, e.g. which entity is missing.
+	message string
 }
 
 func (e *polarisNotFoundError) Error() string {
+	if e.message != "" {
+		return fmt.Sprintf("polaris management: not found %s %s: %s", e.method, e.path, e.message)
+	}
+
 	return fmt.Sprintf("polaris management: not found %s %s", e.method, e.path)
 }
 
@@ -54,7 +117,34 @@ func isPolarisNotFoundError(err error) bool {
 	return errors.As(err, &nf)
 }
 
-func (p *icebergProvider) newPolarisManagementClient() (*polarisManagementClient, error) {
+// derivePolarisManagementURI returns the management API URI of the Polaris
+// server that serves the catalog at catalogURI. Polaris serves the catalog
+// under <base>/api/catalog and the management API under
+// <base>/api/management/v1, where base is empty unless a gateway adds a
+// path; other catalog URIs are taken to be served from the root of the host.
+// It returns "" if catalogURI isn't an absolute URL.
+func derivePolarisManagementURI(catalogURI string) string {
+	u, err := url.Parse(catalogURI)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+
+	base := ""
+	catalogPath := strings.TrimRight(u.Path, "/")
+	if i := strings.LastIndex(catalogPath, "/api/catalog"); i >= 0 {
+		if rest := catalogPath[i+len("/api/catalog"):]; rest == "" || rest == "/v1" {
+			base = catalogPath[:i]
+		}
+	}
+	u.Path = base + "/api/management/v1"
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String()
+}
+
+func (p *icebergProvider) newPolarisManagementClient(resourceType string) (*polarisManagementClient, error) {
 	if p.polaris == nil || p.polaris.managementURI == "" {
 		return nil, errors.New("polaris is not configured: set type = \"polaris\" and ensure polaris_management_uri is set or derivable from catalog_uri")
 	}
@@ -63,63 +153,62 @@ func (p *icebergProvider) newPolarisManagementClient() (*polarisManagementClient
 		return nil, fmt.Errorf("invalid polaris_management_uri %q: %w", p.polaris.managementURI, err)
 	}
 
-	return &polarisManagementClient{
+	client := &polarisManagementClient{
 		baseURL:    u,
-		httpClient: http.DefaultClient,
-		token:      p.token,
-		headers:    p.headers,
-	}, nil
-}
-
-func (c *polarisManagementClient) do(ctx context.Context, method, relativePath string, query url.Values, body any, out any) error {
-	u := *c.baseURL
-
-	u.Path = path.Join(c.baseURL.Path, relativePath)
-	u.RawQuery = query.Encode()
-
-	var reqBody io.Reader
-	if body != nil {
-		buf, err := json.Marshal(body)
-		if err != nil {
-			return fmt.Errorf("marshal request body: %w", err)
-		}
-		reqBody = bytes.NewReader(buf)
+		httpClient: &http.Client{Transport: p.managementTransport(resourceType)},
+		token: func(ctx context.Context) (string, error) {
+			return p.polarisAccessToken(ctx, resourceType)
+		},
+		headers:      p.headers,
+		capabilities: p.capabilities,
 	}
-
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
-	}
-
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if creds := p.polarisCredentials(); creds != nil {
+		client.invalidateToken = creds.invalidate
 	}
 
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
-	}
+	return client, nil
+}
 
-	for k, v := range c.headers {
-		// don't override existing headers if users are also setting it
-		if _, exists := req.Header[k]; !exists {
-			req.Header.Set(k, v)
-		}
+func (c *polarisManagementClient) do(ctx context.Context, method, relativePath string, query url.Values, body any, out any) error {
+	req, err := c.newRequest(ctx, method, relativePath, query, body)
+	if err != nil {
+		return err
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("perform request: %w", err)
 	}
+	// Issued tokens expire; the request is retried once with a new one.
+	if resp.StatusCode == http.StatusUnauthorized && c.invalidateToken != nil &&
+		c.invalidateToken(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")) {
+		resp.Body.Close()
+		if req, err = c.newRequest(ctx, method, relativePath, query, body); err != nil {
+			return err
+		}
+		if resp, err = c.httpClient.Do(req); err != nil {
+			return fmt.Errorf("perform request: %w", err)
+		}
+	}
 	defer resp.Body.Close()
 
+	if err := checkJSONResponse(req, resp); err != nil {
+		return err
+	}
+	warnDuplicateProperties(req, resp)
+	filterNullProperties(req, resp)
+
 	if resp.StatusCode == http.StatusNotFound {
-		return &polarisNotFoundError{method: method, path: u.Path}
+		nf := &polarisNotFoundError{method: method, path: req.URL.Path}
+		if apiErr := newPolarisAPIError(resp); apiErr.Type != "" {
+			nf.message = apiErr.Message
+		}
+
+		return nf
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
-
-		return fmt.Errorf("polaris management: unexpected status %d: %s", resp.StatusCode, string(bodyBytes))
+		return newPolarisAPIError(resp)
 	}
 
 	if out == nil {
@@ -138,6 +227,50 @@ func (c *polarisManagementClient) do(ctx context.Context, method, relativePath s
 	return nil
 }
 
+// newRequest creates a request to the management API with the bearer token
+// and the configured headers.
+func (c *polarisManagementClient) newRequest(ctx context.Context, method, relativePath string, query url.Values, body any) (*http.Request, error) {
+	u := *c.baseURL
+
+	u.Path = path.Join(c.baseURL.Path, relativePath)
+	u.RawQuery = query.Encode()
+
+	var reqBody io.Reader
+	if body != nil {
+		buf, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	for k, v := range c.headers {
+		// don't override existing headers if users are also setting it
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	return req, nil
+}
+
 type polarisPrincipal struct {
 	Name                string            `json:"name"`
 	Properties          map[string]string `json:"properties,omitempty"`
@@ -160,9 +293,11 @@ type polarisCreatePrincipalRequest struct {
 	CredentialRotationRequired *bool            `json:"credentialRotationRequired,omitempty"`
 }
 
+// polarisUpdatePrincipalRequest replaces all properties of a principal, so
+// Properties is always sent: an empty map removes them all.
 type polarisUpdatePrincipalRequest struct {
 	CurrentEntityVersion int64             `json:"currentEntityVersion"`
-	Properties           map[string]string `json:"properties,omitempty"`
+	Properties           map[string]string `json:"properties"`
 }
 
 func (c *polarisManagementClient) CreatePrincipal(ctx context.Context, req polarisCreatePrincipalRequest) (*polarisPrincipalWithCredentials, error) {
@@ -195,3 +330,146 @@ func (c *polarisManagementClient) UpdatePrincipal(ctx context.Context, name stri
 func (c *polarisManagementClient) DeletePrincipal(ctx context.Context, name string) error {
 	return c.do(ctx, http.MethodDelete, "/principals/"+url.PathEscape(name), nil, nil, nil)
 }
+
+// polarisPrincipalRole is a principal role as returned by the management API.
+type polarisPrincipalRole struct {
+	Name                string            `json:"name"`
+	Properties          map[string]string `json:"properties,omitempty"`
+	EntityVersion       int64             `json:"entityVersion,omitempty"`
+	CreateTimestamp     int64             `json:"createTimestamp,omitempty"`
+	LastUpdateTimestamp int64             `json:"lastUpdateTimestamp,omitempty"`
+}
+
+func (r *polarisPrincipalRole) entityProperties() map[string]string {
+	return r.Properties
+}
+
+func (r *polarisPrincipalRole) currentEntityVersion() int64 {
+	return r.EntityVersion
+}
+
+type polarisCreatePrincipalRoleRequest struct {
+	PrincipalRole polarisPrincipalRole `json:"principalRole"`
+}
+
+// polarisUpdatePrincipalRoleRequest replaces all properties of a principal
+// role, so Properties is always sent: an empty map removes them all.
+type polarisUpdatePrincipalRoleRequest struct {
+	CurrentEntityVersion int64             `json:"currentEntityVersion"`
+	Properties           map[string]string `json:"properties"`
+}
+
+func principalRolePath(name string) string {
+	return "/principal-roles/" + url.PathEscape(name)
+}
+
+// CreatePrincipalRole creates the role. Polaris doesn't return it; read it
+// with GetPrincipalRole.
+func (c *polarisManagementClient) CreatePrincipalRole(ctx context.Context, req polarisCreatePrincipalRoleRequest) error {
+	return c.do(ctx, http.MethodPost, "/principal-roles", nil, req, nil)
+}
+
+func (c *polarisManagementClient) GetPrincipalRole(ctx context.Context, name string) (*polarisPrincipalRole, error) {
+	var out polarisPrincipalRole
+	if err := c.do(ctx, http.MethodGet, principalRolePath(name), nil, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (c *polarisManagementClient) UpdatePrincipalRole(ctx context.Context, name string, req polarisUpdatePrincipalRoleRequest) (*polarisPrincipalRole, error) {
+	var out polarisPrincipalRole
+	if err := c.do(ctx, http.MethodPut, principalRolePath(name), nil, req, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (c *polarisManagementClient) DeletePrincipalRole(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, principalRolePath(name), nil, nil, nil)
+}
+
+// polarisCatalog is a catalog as returned by the management API. Only the
+// fields the provider changes are decoded.
+type polarisCatalog struct {
+	Name          string            `json:"name"`
+	Type          string            `json:"type,omitempty"`
+	Properties    map[string]string `json:"properties,omitempty"`
+	EntityVersion int64             `json:"entityVersion,omitempty"`
+}
+
+func (c *polarisCatalog) entityProperties() map[string]string {
+	return c.Properties
+}
+
+func (c *polarisCatalog) currentEntityVersion() int64 {
+	return c.EntityVersion
+}
+
+// polarisUpdateCatalogRequest replaces the properties of a catalog. Storage
+// configuration is left unchanged when omitted.
+type polarisUpdateCatalogRequest struct {
+	CurrentEntityVersion int64             `json:"currentEntityVersion"`
+	Properties           map[string]string `json:"properties"`
+}
+
+func (c *polarisManagementClient) GetCatalog(ctx context.Context, name string) (*polarisCatalog, error) {
+	var out polarisCatalog
+	if err := c.do(ctx, http.MethodGet, "/catalogs/"+url.PathEscape(name), nil, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (c *polarisManagementClient) UpdateCatalog(ctx context.Context, name string, req polarisUpdateCatalogRequest) (*polarisCatalog, error) {
+	var out polarisCatalog
+	if err := c.do(ctx, http.MethodPut, "/catalogs/"+url.PathEscape(name), nil, req, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// polarisGrant is a privilege granted to a catalog role. Which of Namespace,
+// TableName and ViewName are set depends on Type.
+type polarisGrant struct {
+	Type      string   `json:"type"`
+	Privilege string   `json:"privilege"`
+	Namespace []string `json:"namespace,omitempty"`
+	TableName string   `json:"tableName,omitempty"`
+	ViewName  string   `json:"viewName,omitempty"`
+}
+
+type polarisGrantRequest struct {
+	Grant polarisGrant `json:"grant"`
+}
+
+type polarisGrantsResponse struct {
+	Grants []polarisGrant `json:"grants"`
+}
+
+func catalogRoleGrantsPath(catalogName, catalogRoleName string) string {
+	return "/catalogs/" + url.PathEscape(catalogName) + "/catalog-roles/" + url.PathEscape(catalogRoleName) + "/grants"
+}
+
+func (c *polarisManagementClient) ListGrantsForCatalogRole(ctx context.Context, catalogName, catalogRoleName string) ([]polarisGrant, error) {
+	var out polarisGrantsResponse
+	if err := c.do(ctx, http.MethodGet, catalogRoleGrantsPath(catalogName, catalogRoleName), nil, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return out.Grants, nil
+}
+
+func (c *polarisManagementClient) AddGrantToCatalogRole(ctx context.Context, catalogName, catalogRoleName string, grant polarisGrant) error {
+	return c.do(ctx, http.MethodPut, catalogRoleGrantsPath(catalogName, catalogRoleName), nil, polarisGrantRequest{Grant: grant}, nil)
+}
+
+func (c *polarisManagementClient) RevokeGrantFromCatalogRole(ctx context.Context, catalogName, catalogRoleName string, grant polarisGrant) error {
+	query := url.Values{"cascade": []string{"false"}}
+
+	return c.do(ctx, http.MethodPost, catalogRoleGrantsPath(catalogName, catalogRoleName), query, polarisGrantRequest{Grant: grant}, nil)
+}
@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerivePolarisManagementURI(t *testing.T) {
+	for _, tc := range []struct {
+		catalogURI string
+		want       string
+	}{
+		{"https://polaris.example.com/api/catalog", "https://polaris.example.com/api/management/v1"},
+		{"https://polaris.example.com/api/catalog/", "https://polaris.example.com/api/management/v1"},
+		{"https://polaris.example.com/api/catalog/v1", "https://polaris.example.com/api/management/v1"},
+		{"http://localhost:8181/api/catalog", "http://localhost:8181/api/management/v1"},
+		{"https://gateway.example.com/lakehouse/polaris/api/catalog", "https://gateway.example.com/lakehouse/polaris/api/management/v1"},
+		{"https://gateway.example.com/api/catalog/api/catalog", "https://gateway.example.com/api/catalog/api/management/v1"},
+		{"https://polaris.example.com/api/catalog?tenant=a#x", "https://polaris.example.com/api/management/v1"},
+		// Not a Polaris catalog path: the management API is taken to be on
+		// the root of the host.
+		{"https://polaris.example.com", "https://polaris.example.com/api/management/v1"},
+		{"https://polaris.example.com/iceberg", "https://polaris.example.com/api/management/v1"},
+		{"https://polaris.example.com/api/catalogs", "https://polaris.example.com/api/management/v1"},
+		// Can't be derived.
+		{"polaris.example.com/api/catalog", ""},
+		{"/api/catalog", ""},
+		{"http://[::1", ""},
+	} {
+		t.Run(tc.catalogURI, func(t *testing.T) {
+			assert.Equal(t, tc.want, derivePolarisManagementURI(tc.catalogURI))
+		})
+	}
+}
+
+func TestProviderPolarisManagementURI(t *testing.T) {
+	t.Setenv(polarisManagementURIEnvVar, "")
+
+	t.Run("derived", func(t *testing.T) {
+		var buf bytes.Buffer
+		p, diags := configureTestProviderContext(tflogtest.RootLogger(context.Background(), &buf), t, map[string]tftypes.Value{
+			"type":        tftypes.NewValue(tftypes.String, "polaris"),
+			"catalog_uri": tftypes.NewValue(tftypes.String, "https://gateway.example.com/polaris/api/catalog"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, "https://gateway.example.com/polaris/api/management/v1", p.polaris.managementURI)
+
+		entries, err := tflogtest.MultilineJSONDecode(&buf)
+		require.NoError(t, err)
+		var logged bool
+		for _, entry := range entries {
+			if entry["@message"] == "Derived the Polaris management API URI from catalog_uri" {
+				logged = true
+				assert.Equal(t, "https://gateway.example.com/polaris/api/management/v1", entry["management_uri"])
+			}
+		}
+		assert.True(t, logged, "%v", entries)
+	})
+
+	t.Run("explicit", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":        tftypes.NewValue(tftypes.String, "polaris"),
+			"catalog_uri": tftypes.NewValue(tftypes.String, "https://polaris.example.com/api/catalog"),
+			"polaris_settings": polarisSettingsValue(map[string]tftypes.Value{
+				"management_uri": tftypes.NewValue(tftypes.String, "https://admin.example.com/management/v1/"),
+			}),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, "https://admin.example.com/management/v1", p.polaris.managementURI)
+	})
+
+	t.Run("not derivable", func(t *testing.T) {
+		p := &icebergProvider{catalogType: "rest", polaris: &polarisConfig{managementURI: derivePolarisManagementURI("polaris:8181")}}
+		_, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "polaris is not configured")
+	})
+}
@@ -0,0 +1,83 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// polarisUpdateAttempts bounds the number of times an update of the
+// properties of a Polaris entity is retried after another client changed the
+// entity concurrently.
+const polarisUpdateAttempts = 3
+
+// polarisEntity is an entity of the management API whose properties are
+// replaced as a whole on update, guarded by its entity version.
+type polarisEntity interface {
+	entityProperties() map[string]string
+	currentEntityVersion() int64
+}
+
+// polarisPropertyView selects the properties of an entity a resource manages.
+// managed returns them from all properties of the entity, keyed as in the
+// configuration; apply returns a copy of all properties with a delta of the
+// managed ones applied.
+type polarisPropertyView struct {
+	managed func(props map[string]string) map[string]string
+	apply   func(props map[string]string, delta properties.Delta) map[string]string
+}
+
+// updatePolarisProperties sets the desired properties on the entity get
+// returns and removes the keys of managed that aren't desired, leaving all
+// other properties alone. The properties put sends are based on those just
+// read, with their entity version, so the update is retried if the entity
+// changed in between. The entity is returned as read if nothing changes.
+func updatePolarisProperties[E polarisEntity](
+	ctx context.Context,
+	name string,
+	view polarisPropertyView,
+	desired, managed map[string]string,
+	get func(ctx context.Context) (E, error),
+	put func(ctx context.Context, entityVersion int64, props map[string]string) (E, error),
+) (E, error) {
+	var zero E
+	var err error
+	for range polarisUpdateAttempts {
+		var entity E
+		entity, err = get(ctx)
+		if err != nil {
+			return zero, err
+		}
+
+		props := entity.entityProperties()
+		delta := properties.ComputeDelta(desired, managed, view.managed(props))
+		if delta.IsEmpty() {
+			return entity, nil
+		}
+
+		entity, err = put(ctx, entity.currentEntityVersion(), view.apply(props, delta))
+		if !errors.Is(err, errPolarisConflict) {
+			return entity, err
+		}
+		tflog.Debug(ctx, "Polaris entity changed concurrently, retrying the update", map[string]any{"name": name})
+	}
+
+	return zero, err
+}
@@ -0,0 +1,163 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// polarisVersionHeader is the response header Polaris reports its release
+// in. Servers that don't send it are identified by their Server header, if
+// at all.
+const polarisVersionHeader = "X-Polaris-Version"
+
+// polarisFeature is a request field of the management API that older Polaris
+// releases reject. name is what the warning for those releases calls it.
+type polarisFeature struct {
+	name       string
+	minVersion string
+}
+
+var polarisFeatureCredentialRotation = polarisFeature{
+	name:       "credential_rotation_required of iceberg_polaris_principal",
+	minVersion: "1.0.0",
+}
+
+// serverVersion returns the Polaris release of the management API, or "" if
+// it couldn't be identified. The first call asks the server and the answer is
+// shared by all clients of the provider.
+func (c *polarisManagementClient) serverVersion(ctx context.Context) string {
+	if c.capabilities == nil {
+		return ""
+	}
+
+	c.capabilities.polarisVersionOnce.Do(func() {
+		version := c.fetchServerVersion(ctx)
+		tflog.Debug(ctx, "Identified Polaris management API version", map[string]any{"version": version})
+
+		c.capabilities.mu.Lock()
+		defer c.capabilities.mu.Unlock()
+		c.capabilities.polarisVersion = version
+	})
+
+	c.capabilities.mu.Lock()
+	defer c.capabilities.mu.Unlock()
+
+	return c.capabilities.polarisVersion
+}
+
+// fetchServerVersion reads the version from the response headers of a GET
+// of the API root, whatever its status. A failed request leaves the version
+// unknown; the request that needed it reports the problem.
+func (c *polarisManagementClient) fetchServerVersion(ctx context.Context) string {
+	req, err := c.newRequest(ctx, http.MethodGet, "/", nil, nil)
+	if err != nil {
+		return ""
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ""
+	}
+	resp.Body.Close()
+
+	return polarisVersionFromHeader(resp.Header)
+}
+
+func polarisVersionFromHeader(h http.Header) string {
+	if v := strings.TrimSpace(h.Get(polarisVersionHeader)); v != "" {
+		return v
+	}
+
+	// For example "Polaris/1.1.0-incubating".
+	name, version, ok := strings.Cut(h.Get("Server"), "/")
+	if fields := strings.Fields(version); ok && len(fields) > 0 && strings.EqualFold(strings.TrimSpace(name), "polaris") {
+		return fields[0]
+	}
+
+	return ""
+}
+
+// supports reports whether the server accepts f. Servers whose version is
+// unknown are assumed to accept everything.
+func (c *polarisManagementClient) supports(ctx context.Context, f polarisFeature) bool {
+	version := c.serverVersion(ctx)
+
+	return version == "" || versionAtLeast(version, f.minVersion)
+}
+
+// checkFeature is supports with a warning that explains the value isn't sent
+// if the server is older than f needs.
+func (c *polarisManagementClient) checkFeature(ctx context.Context, f polarisFeature, diags *diag.Diagnostics) bool {
+	if c.supports(ctx, f) {
+		return true
+	}
+
+	diags.AddWarning(
+		"Unsupported Polaris feature",
+		fmt.Sprintf("The Polaris server is version %s, but %s needs Polaris %s or later. The value isn't sent to the server; upgrade Polaris to apply it.", c.serverVersion(ctx), f.name, f.minVersion),
+	)
+
+	return false
+}
+
+// versionAtLeast reports whether version is minimum or later, comparing the
+// numeric parts of both; suffixes such as "-incubating" are ignored. A
+// version that doesn't start with a number counts as later than any.
+func versionAtLeast(version, minimum string) bool {
+	v, ok := versionNumbers(version)
+	if !ok {
+		return true
+	}
+	m, _ := versionNumbers(minimum)
+
+	for i := range max(len(v), len(m)) {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(m) {
+			b = m[i]
+		}
+		if a != b {
+			return a > b
+		}
+	}
+
+	return true
+}
+
+func versionNumbers(version string) ([]int, bool) {
+	version, _, _ = strings.Cut(strings.TrimPrefix(version, "v"), "-")
+
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+
+	return numbers, len(numbers) > 0
+}
@@ -0,0 +1,146 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// polarisVersionTestServer is a management API of the given version that
+// records the probes and the principals it is asked to create.
+type polarisVersionTestServer struct {
+	version string
+	probes  int
+	created []map[string]any
+}
+
+func (m *polarisVersionTestServer) start(t *testing.T) *icebergProvider {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(polarisVersionHeader, m.version)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/management/v1":
+			m.probes++
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/management/v1/principals":
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			m.created = append(m.created, body)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"principal": body["principal"]})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &icebergProvider{
+		polaris:      &polarisConfig{managementURI: server.URL + "/api/management/v1"},
+		capabilities: newCatalogCapabilities(),
+	}
+}
+
+// createTestPrincipal creates a principal the way the principal resource
+// does and returns the diagnostics.
+func createTestPrincipal(t *testing.T, p *icebergProvider, rotationRequired bool) diag.Diagnostics {
+	t.Helper()
+
+	ctx := context.Background()
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+	r := &polarisPrincipalResource{provider: p, managementClient: client}
+
+	var diags diag.Diagnostics
+	_, err = client.CreatePrincipal(ctx, polarisCreatePrincipalRequest{
+		Principal:                  polarisPrincipal{Name: "etl"},
+		CredentialRotationRequired: r.credentialRotationRequired(ctx, types.BoolValue(rotationRequired), &diags),
+	})
+	require.NoError(t, err)
+
+	return diags
+}
+
+func TestPolarisServerVersionGatesFields(t *testing.T) {
+	t.Run("current server", func(t *testing.T) {
+		m := &polarisVersionTestServer{version: "1.1.0-incubating"}
+		p := m.start(t)
+
+		diags := createTestPrincipal(t, p, true)
+		assert.Empty(t, diags.Warnings())
+		createTestPrincipal(t, p, false)
+
+		require.Len(t, m.created, 2)
+		assert.Equal(t, true, m.created[0]["credentialRotationRequired"])
+		assert.Equal(t, false, m.created[1]["credentialRotationRequired"])
+		assert.Equal(t, 1, m.probes, "the version is looked up once per provider")
+	})
+
+	t.Run("older server", func(t *testing.T) {
+		m := &polarisVersionTestServer{version: "0.9.0-incubating"}
+		p := m.start(t)
+
+		diags := createTestPrincipal(t, p, true)
+		require.Len(t, diags.Warnings(), 1)
+		assert.Contains(t, diags.Warnings()[0].Detail(), "version 0.9.0-incubating, but credential_rotation_required")
+
+		diags = createTestPrincipal(t, p, false)
+		assert.Empty(t, diags.Warnings(), "false is what older servers do anyway")
+
+		require.Len(t, m.created, 2)
+		for _, body := range m.created {
+			assert.NotContains(t, body, "credentialRotationRequired")
+		}
+		assert.Equal(t, 1, m.probes)
+	})
+}
+
+func TestPolarisVersionFromHeader(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		header http.Header
+		want   string
+	}{
+		{name: "version header", header: http.Header{"X-Polaris-Version": {"1.0.1"}}, want: "1.0.1"},
+		{name: "server header", header: http.Header{"Server": {"Polaris/0.9.0-incubating (Quarkus)"}}, want: "0.9.0-incubating"},
+		{name: "other server", header: http.Header{"Server": {"nginx/1.25"}}},
+		{name: "no version", header: http.Header{"Server": {"Polaris"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, polarisVersionFromHeader(tc.header))
+		})
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	assert.True(t, versionAtLeast("1.0.0", "1.0.0"))
+	assert.True(t, versionAtLeast("1.1.0-incubating", "1.0.0"))
+	assert.True(t, versionAtLeast("1.0", "1.0.0"))
+	assert.True(t, versionAtLeast("v2", "1.0.0"))
+	assert.True(t, versionAtLeast("main-SNAPSHOT", "1.0.0"), "unparseable versions are assumed current")
+	assert.False(t, versionAtLeast("0.9.0-incubating", "1.0.0"))
+	assert.False(t, versionAtLeast("1.0.0-rc1", "1.0.1"))
+}
@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// Keys for values kept in the framework's private resource state. The state is
+// opaque to users, so server-assigned bookkeeping lives here instead of in
+// computed attributes. Keys carry a version suffix; when the encoding of a
+// value changes, introduce a new key rather than reinterpreting the old one.
+const (
+	privateKeyEntityVersion    = "entity_version.v1"
+	privateKeyTableUUID        = "table_uuid.v1"
+	privateKeyMetadataLocation = "metadata_location.v1"
+	privateKeyLastUpdate       = "last_update_timestamp.v1"
+	privateKeyClaimToken       = "claim_token.v1"
+)
+
+// privateStateReader is implemented by the Private field of the framework's
+// read, update, delete and import requests.
+type privateStateReader interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// privateStateWriter is implemented by the Private field of the framework's
+// create, read, update and import responses.
+type privateStateWriter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// getPrivateValue decodes the value stored under key. The boolean result is
+// false if the key is not set, e.g. for state written by an older provider.
+func getPrivateValue[T any](ctx context.Context, private privateStateReader, key string, diags *diag.Diagnostics) (T, bool) {
+	var value T
+	if private == nil {
+		return value, false
+	}
+
+	b, d := private.GetKey(ctx, key)
+	diags.Append(d...)
+	if diags.HasError() || len(b) == 0 {
+		return value, false
+	}
+
+	if err := json.Unmarshal(b, &value); err != nil {
+		diags.AddError("failed to decode private state "+key, err.Error())
+
+		return value, false
+	}
+
+	return value, true
+}
+
+// setPrivateValue stores value under key.
+func setPrivateValue[T any](ctx context.Context, private privateStateWriter, key string, value T, diags *diag.Diagnostics) {
+	if private == nil {
+		return
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		diags.AddError("failed to encode private state "+key, err.Error())
+
+		return
+	}
+
+	diags.Append(private.SetKey(ctx, key, b)...)
+}
@@ -0,0 +1,77 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePrivateState map[string][]byte
+
+func (f fakePrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return f[key], nil
+}
+
+func (f fakePrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	f[key] = value
+
+	return nil
+}
+
+func TestPrivateValueRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	private := fakePrivateState{}
+
+	var diags diag.Diagnostics
+	setPrivateValue(ctx, private, privateKeyEntityVersion, int64(7), &diags)
+	setPrivateValue(ctx, private, privateKeyTableUUID, "5b1c7f5e-0000-4000-8000-000000000000", &diags)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "7", string(private[privateKeyEntityVersion]))
+
+	version, ok := getPrivateValue[int64](ctx, private, privateKeyEntityVersion, &diags)
+	assert.True(t, ok)
+	assert.Equal(t, int64(7), version)
+
+	uuid, ok := getPrivateValue[string](ctx, private, privateKeyTableUUID, &diags)
+	assert.True(t, ok)
+	assert.Equal(t, "5b1c7f5e-0000-4000-8000-000000000000", uuid)
+	assert.False(t, diags.HasError())
+}
+
+func TestPrivateValueMissing(t *testing.T) {
+	var diags diag.Diagnostics
+
+	version, ok := getPrivateValue[int64](context.Background(), fakePrivateState{}, privateKeyEntityVersion, &diags)
+	assert.False(t, ok)
+	assert.Zero(t, version)
+	assert.False(t, diags.HasError())
+
+	_, ok = getPrivateValue[int64](context.Background(), nil, privateKeyEntityVersion, &diags)
+	assert.False(t, ok)
+}
+
+func TestPrivateValueMismatchedEncoding(t *testing.T) {
+	var diags diag.Diagnostics
+
+	private := fakePrivateState{privateKeyEntityVersion: []byte(`"not-a-number"`)}
+	_, ok := getPrivateValue[int64](context.Background(), private, privateKeyEntityVersion, &diags)
+	assert.False(t, ok)
+	assert.True(t, diags.HasError())
+}
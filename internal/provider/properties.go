@@ -0,0 +1,87 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// validatePropertyKeyCollisions rejects user_properties with keys that keys
+// can't tell apart, as they would manage the same property on the server.
+func validatePropertyKeyCollisions(keys properties.Keys, userProperties types.Map, diags *diag.Diagnostics) {
+	if userProperties.IsNull() || userProperties.IsUnknown() {
+		return
+	}
+
+	for _, c := range keys.Collisions(mapKeys(userProperties)) {
+		diags.AddAttributeError(
+			path.Root("user_properties"),
+			"Duplicate property key",
+			fmt.Sprintf("The user_properties keys %q and %q differ only in case, which case_insensitive_property_keys treats as the same property. Remove one of them.", c.First, c.Second),
+		)
+	}
+}
+
+// mapKeys returns the keys of the elements of m.
+func mapKeys(m types.Map) []string {
+	keys := make([]string, 0, len(m.Elements()))
+	for k := range m.Elements() {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// propertyUpdates returns the table updates that apply delta.
+func propertyUpdates(delta properties.Delta) []table.Update {
+	updates := make([]table.Update, 0, 2)
+	if len(delta.Updates) > 0 {
+		updates = append(updates, table.NewSetPropertiesUpdate(delta.Updates))
+	}
+	if len(delta.Removals) > 0 {
+		updates = append(updates, table.NewRemovePropertiesUpdate(delta.Removals))
+	}
+
+	return updates
+}
+
+// checkPropertyRemovals reports the removals of delta that server, the
+// properties of subject read back after the catalog accepted delta, still
+// has. Some catalogs answer removals with success without removing anything,
+// and the properties would otherwise show up as drift on the next refresh.
+// This is an error unless warn_on_ignored_property_removals is set.
+func (p *icebergProvider) checkPropertyRemovals(subject string, delta properties.Delta, server map[string]string, diags *diag.Diagnostics) {
+	unremoved := delta.Unremoved(server)
+	if len(unremoved) == 0 {
+		return
+	}
+
+	summary := "Property removal ignored by the catalog"
+	detail := fmt.Sprintf("The catalog accepted the removal of these properties of %s but still returns them: %s. It likely doesn't implement property removals and ignores them without an error.", subject, strings.Join(unremoved, ", "))
+	if p.warnOnIgnoredRemovals {
+		diags.AddWarning(summary, detail+" The properties show up as drift until they are removed by other means.")
+
+		return
+	}
+	diags.AddError(summary, detail+" Remove them by other means, or set warn_on_ignored_property_removals in the provider configuration to make this a warning.")
+}
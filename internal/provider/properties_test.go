@@ -0,0 +1,133 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePropertyKeyCollisions(t *testing.T) {
+	props := types.MapValueMust(types.StringType, map[string]attr.Value{
+		"Description": types.StringValue("a"),
+		"description": types.StringValue("b"),
+		"owner":       types.StringValue("c"),
+	})
+
+	var diags diag.Diagnostics
+	validatePropertyKeyCollisions(properties.Keys{}, props, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	validatePropertyKeyCollisions(properties.Keys{IgnoreCase: true}, props, &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Duplicate property key", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), `"Description" and "description" differ only in case`)
+}
+
+func TestCheckPropertyRemovals(t *testing.T) {
+	p := &icebergProvider{}
+	delta := properties.Delta{Updates: map[string]string{"owner": "etl"}, Removals: []string{"comment", "retention"}}
+
+	var diags diag.Diagnostics
+	p.checkPropertyRemovals("namespace db", delta, map[string]string{"owner": "etl"}, &diags)
+	require.Empty(t, diags)
+
+	p.checkPropertyRemovals("namespace db", delta, map[string]string{"owner": "etl", "comment": "x", "retention": "7"}, &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Property removal ignored by the catalog", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), "properties of namespace db but still returns them: comment, retention.")
+
+	p.warnOnIgnoredRemovals = true
+	diags = nil
+	p.checkPropertyRemovals("namespace db", delta, map[string]string{"retention": "7"}, &diags)
+	require.Empty(t, diags.Errors())
+	require.Len(t, diags.Warnings(), 1)
+	assert.Contains(t, diags.Warnings()[0].Detail(), "still returns them: retention.")
+}
+
+func TestIgnoredPropertyRemovals(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("namespace", func(t *testing.T) {
+		for _, tc := range []struct {
+			name           string
+			ignoreRemovals bool
+			warn           bool
+		}{
+			{name: "removed"},
+			{name: "ignored", ignoreRemovals: true},
+			{name: "ignored with warning", ignoreRemovals: true, warn: true},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				m := newMockRESTCatalog()
+				m.addTable("db", "events", nil)
+				m.namespaces["db"]["owner"] = "etl"
+				m.namespaces["db"]["comment"] = "raw events"
+				m.ignoreRemovals = tc.ignoreRemovals
+				server := m.start(t)
+
+				p := &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities(), warnOnIgnoredRemovals: tc.warn}
+				cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+				require.NoError(t, err)
+
+				var diags diag.Diagnostics
+				applyNamespacePropertyDelta(ctx, cat, p, []string{"db"}, properties.Delta{Removals: []string{"comment"}}, &diags)
+
+				switch {
+				case !tc.ignoreRemovals:
+					require.Empty(t, diags)
+					assert.Equal(t, map[string]string{"owner": "etl"}, m.namespaceProperties("db"))
+				case tc.warn:
+					require.False(t, diags.HasError(), "%v", diags)
+					require.Len(t, diags.Warnings(), 1)
+					assert.Contains(t, diags.Warnings()[0].Detail(), "namespace db but still returns them: comment.")
+				default:
+					require.Len(t, diags.Errors(), 1)
+					assert.Equal(t, "Property removal ignored by the catalog", diags.Errors()[0].Summary())
+					assert.Contains(t, diags.Errors()[0].Detail(), "namespace db but still returns them: comment.")
+				}
+			})
+		}
+	})
+
+	t.Run("table", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("db", "events", map[string]string{"owner": "etl", "comment": "raw events"})
+		m.ignoreRemovals = true
+		r := newOverlayTestResource(t, m)
+
+		var diags diag.Diagnostics
+		_, err := r.enforce(ctx, []string{"db", "events"}, map[string]string{"owner": "ingest"}, map[string]string{"owner": "etl", "comment": "raw events"}, &diags)
+		require.NoError(t, err)
+		require.Len(t, diags.Errors(), 1)
+		assert.Contains(t, diags.Errors()[0].Detail(), "table db.events but still returns them: comment.")
+		assert.Equal(t, "ingest", m.tableProperties("db", "events")["owner"], "updates are still applied")
+
+		r.provider.warnOnIgnoredRemovals = true
+		diags = nil
+		_, err = r.enforce(ctx, []string{"db", "events"}, map[string]string{"owner": "ingest"}, map[string]string{"owner": "etl", "comment": "raw events"}, &diags)
+		require.NoError(t, err)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Len(t, diags.Warnings(), 1)
+	})
+}
@@ -17,25 +17,42 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/catalog"
 	"github.com/apache/iceberg-go/catalog/rest"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
 )
 
-var _ provider.Provider = &icebergProvider{}
+var (
+	_ provider.Provider                       = &icebergProvider{}
+	_ provider.ProviderWithEphemeralResources = &icebergProvider{}
+	_ provider.ProviderWithFunctions          = &icebergProvider{}
+	_ provider.ProviderWithValidateConfig     = &icebergProvider{}
+	_ io.Closer                               = &icebergProvider{}
+)
 
 // New is a helper function to simplify provider server and testing implementation.
-func New() func() provider.Provider {
+func New(version string) func() provider.Provider {
 	return func() provider.Provider {
-		return &icebergProvider{}
+		return &icebergProvider{version: providerVersion(version)}
 	}
 }
 
@@ -43,37 +60,169 @@ func New() func() provider.Provider {
 type polarisSettingsModel struct {
 	ManagementURI types.String `tfsdk:"management_uri"`
 	CatalogName   types.String `tfsdk:"catalog_name"`
+	ClientID      types.String `tfsdk:"client_id"`
+	ClientSecret  types.String `tfsdk:"client_secret"`
 }
 
 // polarisConfig holds Polaris-specific runtime configuration derived from polaris_settings.
 type polarisConfig struct {
 	managementURI string
 	catalogName   string
+	// oauth2 is set when client_id and client_secret are, see
+	// polaris_auth.go.
+	oauth2 *oauth2ClientCredentials
 }
 
 // icebergProvider is the provider implementation.
 type icebergProvider struct {
+	// version is the provider version, see user_agent.go.
+	version     string
 	catalogURI  string
 	catalogType string
 	token       string
-	warehouse   string
-	headers     map[string]string
-	polaris     *polarisConfig
+	// oauth2 is set when the oauth2_* attributes are, see oauth2.go.
+	oauth2 *oauth2ClientCredentials
+	// basicAuth is set when the basic_auth_* attributes are, see
+	// basic_auth.go.
+	basicAuth *basicAuthCredentials
+	// authHeader is set when the auth_header_* attributes are, see
+	// auth_header.go.
+	authHeader *authHeader
+	// sigv4 is set when sigv4_enabled is, see sigv4.go.
+	sigv4 *sigv4Config
+	// glue is set with type = "glue", see glue.go.
+	glue *glueConfig
+	// sql is set with type = "sql", see sql_catalog.go.
+	sql       *sqlConfig
+	warehouse string
+	// fileIO holds the FileIO properties of the s3, adls and gcs blocks, see
+	// s3_fileio.go, adls_fileio.go and gcs_fileio.go.
+	fileIO iceberg.Properties
+	// uriPrefix replaces the prefix from the config response, see
+	// uri_prefix.go.
+	uriPrefix string
+	// accessDelegation is sent in the X-Iceberg-Access-Delegation header of
+	// table loads and creates, see access_delegation.go.
+	accessDelegation string
+	headers          map[string]string
+	polaris          *polarisConfig
+	warnOnDrift      bool
+	readOnly         bool
+	// userAgentExtra is appended to the User-Agent of all requests, see
+	// user_agent.go.
+	userAgentExtra string
+	// validateOnPlan makes new tables go through a staged create at plan time.
+	validateOnPlan bool
+	// restrictMapKeys limits map keys of table schemas to the types all
+	// engines support.
+	restrictMapKeys bool
+	// forbidTimestampWithoutZone rejects table schemas with timestamps
+	// without a time zone.
+	forbidTimestampWithoutZone bool
+	// forbidDuplicateTables makes two iceberg_table resources managing the
+	// same table an error rather than a warning.
+	forbidDuplicateTables bool
+	// warnOnIgnoredRemovals makes property removals the catalog ignored a
+	// warning rather than an error, see checkPropertyRemovals.
+	warnOnIgnoredRemovals bool
+	// verifyAfterApply makes resources read back what they created or
+	// updated and fail on differences, see apply_verification.go.
+	verifyAfterApply bool
+	// skipOwnerValidation turns off the check that owners are Polaris
+	// principals.
+	skipOwnerValidation bool
+	// namePrefix is prepended to the leading namespace level of managed
+	// objects, and to table names with prefixTableNames, see name_prefix.go.
+	namePrefix       string
+	prefixTableNames bool
+	// capabilities is shared by all catalogs created by this provider.
+	capabilities *catalogCapabilities
+	// resourceClaims holds the tables the resources of the current walk
+	// manage, see duplicate_resources.go.
+	resourceClaims *resourceClaims
+	// sharedCatalog holds the catalog client of the configuration, see
+	// catalog_client.go.
+	sharedCatalog *sharedCatalog
+	// metrics is set when enable_operation_metrics is.
+	metrics *operationMetrics
+	// apiCalls counts the requests sent, see api_calls.go.
+	apiCalls *apiCallCounter
+	// auditLog is set when audit_log_path is, see audit.go.
+	auditLog *auditLog
+	// memory is set for type = "memory" without warehouse, see
+	// memory_catalog.go.
+	memory *memoryCatalog
+	// httpTransport sends all requests, see tls.go. Nil means
+	// http.DefaultTransport.
+	httpTransport http.RoundTripper
+	// maxRetries and retryMaxBackoff configure retries of rate limited and
+	// failed requests, see retry.go.
+	maxRetries      int
+	retryMaxBackoff time.Duration
+	// rateLimiter paces all requests with max_requests_per_second, see
+	// rate_limit.go. Nil means unlimited.
+	rateLimiter *rate.Limiter
 }
 
 // icebergProviderModel maps provider schema data to a Go type.
 type icebergProviderModel struct {
-	CatalogURI      types.String          `tfsdk:"catalog_uri"`
-	Type            types.String          `tfsdk:"type"`
-	Token           types.String          `tfsdk:"token"`
-	Warehouse       types.String          `tfsdk:"warehouse"`
-	Headers         types.Map             `tfsdk:"headers"`
-	PolarisSettings *polarisSettingsModel `tfsdk:"polaris_settings"`
+	CatalogURI                 types.String          `tfsdk:"catalog_uri"`
+	Type                       types.String          `tfsdk:"type"`
+	Token                      types.String          `tfsdk:"token"`
+	CACertPEM                  types.String          `tfsdk:"ca_cert_pem"`
+	CACertFile                 types.String          `tfsdk:"ca_cert_file"`
+	InsecureSkipVerify         types.Bool            `tfsdk:"tls_insecure_skip_verify"`
+	OAuth2ClientID             types.String          `tfsdk:"oauth2_client_id"`
+	Credential                 types.String          `tfsdk:"credential"`
+	OAuth2ClientSecret         types.String          `tfsdk:"oauth2_client_secret"`
+	OAuth2ServerURI            types.String          `tfsdk:"oauth2_server_uri"`
+	OAuth2Scope                types.String          `tfsdk:"oauth2_scope"`
+	OAuth2Audience             types.String          `tfsdk:"oauth2_audience"`
+	BasicAuthUsername          types.String          `tfsdk:"basic_auth_username"`
+	BasicAuthPassword          types.String          `tfsdk:"basic_auth_password"`
+	AuthHeaderName             types.String          `tfsdk:"auth_header_name"`
+	AuthHeaderValue            types.String          `tfsdk:"auth_header_value"`
+	SigV4Enabled               types.Bool            `tfsdk:"sigv4_enabled"`
+	SigV4Region                types.String          `tfsdk:"sigv4_region"`
+	SigV4Service               types.String          `tfsdk:"sigv4_service"`
+	GlueRegion                 types.String          `tfsdk:"glue_region"`
+	GlueCatalogID              types.String          `tfsdk:"glue_catalog_id"`
+	GlueAccessKeyID            types.String          `tfsdk:"glue_access_key_id"`
+	GlueSecretKey              types.String          `tfsdk:"glue_secret_access_key"`
+	GlueSessionToken           types.String          `tfsdk:"glue_session_token"`
+	SQLDSN                     types.String          `tfsdk:"sql_dsn"`
+	Warehouse                  types.String          `tfsdk:"warehouse"`
+	URIPrefix                  types.String          `tfsdk:"uri_prefix"`
+	AccessDelegation           types.String          `tfsdk:"access_delegation"`
+	Headers                    types.Map             `tfsdk:"headers"`
+	UserAgentExtra             types.String          `tfsdk:"user_agent_extra"`
+	WarnOnDrift                types.Bool            `tfsdk:"warn_on_drift"`
+	ReadOnly                   types.Bool            `tfsdk:"read_only"`
+	ValidateOnPlan             types.Bool            `tfsdk:"validate_on_plan"`
+	ValidateConnection         types.Bool            `tfsdk:"validate_connection"`
+	OperationMetrics           types.Bool            `tfsdk:"enable_operation_metrics"`
+	AuditLogPath               types.String          `tfsdk:"audit_log_path"`
+	RestrictMapKeys            types.Bool            `tfsdk:"restrict_map_keys"`
+	ForbidTimestampWithoutZone types.Bool            `tfsdk:"forbid_timestamp_without_zone"`
+	ForbidDuplicateTables      types.Bool            `tfsdk:"forbid_duplicate_tables"`
+	WarnOnIgnoredRemovals      types.Bool            `tfsdk:"warn_on_ignored_property_removals"`
+	VerifyAfterApply           types.Bool            `tfsdk:"verify_after_apply"`
+	SkipOwnerCheck             types.Bool            `tfsdk:"skip_owner_validation"`
+	NamePrefix                 types.String          `tfsdk:"name_prefix"`
+	PrefixTableNames           types.Bool            `tfsdk:"prefix_table_names"`
+	MaxRetries                 types.Int64           `tfsdk:"max_retries"`
+	RetryMaxBackoff            types.String          `tfsdk:"retry_max_backoff"`
+	MaxRequestsPerSecond       types.Int64           `tfsdk:"max_requests_per_second"`
+	PolarisSettings            *polarisSettingsModel `tfsdk:"polaris_settings"`
+	S3                         *s3Model              `tfsdk:"s3"`
+	ADLS                       *adlsModel            `tfsdk:"adls"`
+	GCS                        *gcsModel             `tfsdk:"gcs"`
 }
 
 // Metadata returns the provider type name.
 func (p *icebergProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "iceberg"
+	resp.Version = p.version
 }
 
 // Schema defines the provider-level schema for configuration data.
@@ -82,47 +231,252 @@ func (p *icebergProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 		Description: "Use Terraform to interact with Iceberg REST Catalog instances.",
 		Attributes: map[string]schema.Attribute{
 			"catalog_uri": schema.StringAttribute{
-				Description: "The URI of the Iceberg REST catalog.",
-				Required:    true,
+				Description: "The URI of the Iceberg REST catalog. Required unless type is 'glue', 'sql' or 'memory'. Defaults to the ICEBERG_CATALOG_URI environment variable.",
+				Optional:    true,
 			},
 			"type": schema.StringAttribute{
-				Description: "The type of catalog. Use 'rest' for a plain REST catalog, or 'polaris' for Polaris (REST catalog with Polaris management).",
+				Description: "The type of catalog. Use 'rest' for a plain REST catalog, 'polaris' for Polaris (REST catalog with Polaris management), 'glue' for the AWS Glue Data Catalog through the AWS API, 'sql' for a SQL catalog stored in the database of sql_dsn, or 'memory' for a catalog held in the memory of the provider process, for tests. Defaults to 'rest'.",
 				Optional:    true,
 			},
 			"token": schema.StringAttribute{
-				Description: "The token to use for authentication.",
+				Description: "The token to use for authentication. Defaults to the ICEBERG_TOKEN environment variable unless oauth2_client_id, basic_auth_username or auth_header_name is set.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Description: "PEM-encoded CA certificates to trust, in addition to the system roots, for TLS connections to the catalog, the Polaris management API and the OAuth2 token endpoint.",
+				Optional:    true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Description: "The path of a file with PEM-encoded CA certificates to trust like those of ca_cert_pem. Both can be set.",
+				Optional:    true,
+			},
+			"tls_insecure_skip_verify": schema.BoolAttribute{
+				Description: "If true, the certificates of the catalog, the Polaris management API and the OAuth2 token endpoint aren't verified, for development catalogs with self-signed certificates. The provider warns when it is set. Never use it in production; prefer ca_cert_pem or ca_cert_file.",
+				Optional:    true,
+			},
+			"credential": schema.StringAttribute{
+				Description: "The OAuth2 client ID and secret as one string, client_id:client_secret, as pyiceberg's credential property takes them. It is used like oauth2_client_id and oauth2_client_secret, which can't be set with it. Without a colon, it is the ID of a public client and no secret is sent.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"oauth2_client_id": schema.StringAttribute{
+				Description: "The client ID to request an access token with the OAuth2 client credentials grant. The token is requested with oauth2_scope and oauth2_audience when the provider checks the connection or a resource or data source first uses the catalog, and can't be combined with token. Defaults to the ICEBERG_OAUTH2_CLIENT_ID environment variable unless token, basic_auth_username or auth_header_name is set.",
+				Optional:    true,
+			},
+			"oauth2_client_secret": schema.StringAttribute{
+				Description: "The client secret for oauth2_client_id. Defaults to the ICEBERG_OAUTH2_CLIENT_SECRET environment variable unless token, basic_auth_username or auth_header_name is set.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"oauth2_audience": schema.StringAttribute{
+				Description: "The audience of the access token requested for oauth2_client_id, sent as the audience parameter of the token request, as some identity providers require. No audience is sent by default.",
+				Optional:    true,
+			},
+			"oauth2_scope": schema.StringAttribute{
+				Description: "The scope of the access token requested for oauth2_client_id, such as a space-separated list of scopes. Defaults to 'catalog', or 'PRINCIPAL_ROLE:ALL' with type = 'polaris'.",
+				Optional:    true,
+			},
+			"oauth2_server_uri": schema.StringAttribute{
+				Description: "The OAuth2 token endpoint. Defaults to the ICEBERG_OAUTH2_SERVER_URI environment variable unless token or auth_header_name is set, then to the token endpoint of the REST catalog, catalog_uri followed by '/v1/oauth/tokens'.",
+				Optional:    true,
+			},
+			"basic_auth_username": schema.StringAttribute{
+				Description: "The user name to authenticate catalog requests with HTTP basic authentication, for REST catalogs behind a reverse proxy that requires it. Requires basic_auth_password and can't be combined with token, oauth2_client_id, credential or sigv4_enabled.",
+				Optional:    true,
+			},
+			"basic_auth_password": schema.StringAttribute{
+				Description: "The password for basic_auth_username.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"auth_header_name": schema.StringAttribute{
+				Description: "The name of a header, such as X-Api-Key, to authenticate catalog and Polaris management requests with auth_header_value instead of the Authorization header, for catalogs behind an API gateway that takes a key. No Authorization header is sent then. Requires auth_header_value and can't be combined with token, the oauth2_* attributes, credential, basic_auth_username, sigv4_enabled or polaris_settings.client_id.",
+				Optional:    true,
+			},
+			"auth_header_value": schema.StringAttribute{
+				Description: "The value of the auth_header_name header, such as the API key.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"sigv4_enabled": schema.BoolAttribute{
+				Description: "If true, requests to the catalog are signed with AWS SigV4, as the AWS Glue and S3 Tables REST endpoints require. Credentials come from the standard AWS credential chain. Requests with a token, from token or oauth2_client_id, aren't signed.",
+				Optional:    true,
+			},
+			"sigv4_region": schema.StringAttribute{
+				Description: "The AWS region to sign requests for. Defaults to the region of the AWS configuration, such as the AWS_REGION environment variable.",
+				Optional:    true,
+			},
+			"sigv4_service": schema.StringAttribute{
+				Description: "The service name to sign requests for, 'glue' for AWS Glue or 's3tables' for S3 Tables. Defaults to 'glue'.",
+				Optional:    true,
+			},
+			"glue_region": schema.StringAttribute{
+				Description: "The AWS region of the Glue Data Catalog with type = 'glue'. Defaults to the region of the AWS configuration, such as the AWS_REGION environment variable.",
+				Optional:    true,
+			},
+			"glue_catalog_id": schema.StringAttribute{
+				Description: "The ID of the Glue Data Catalog with type = 'glue', the AWS account ID of another account's catalog. Defaults to the catalog of the account of the credentials.",
+				Optional:    true,
+			},
+			"glue_access_key_id": schema.StringAttribute{
+				Description: "The AWS access key ID to call Glue with, with type = 'glue'. Defaults to the standard AWS credential chain.",
+				Optional:    true,
+			},
+			"glue_secret_access_key": schema.StringAttribute{
+				Description: "The AWS secret access key for glue_access_key_id.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"glue_session_token": schema.StringAttribute{
+				Description: "The AWS session token for temporary credentials in glue_access_key_id and glue_secret_access_key.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"sql_dsn": schema.StringAttribute{
+				Description: "The database of the SQL catalog with type = 'sql'. The scheme selects the driver: postgres:// or postgresql:// for PostgreSQL, sqlite: for SQLite, as in sqlite:///var/lib/iceberg.db. sqlite::memory: is an in-memory database that lives as long as the provider process, for tests. The catalog tables are created if they don't exist.",
 				Optional:    true,
 				Sensitive:   true,
 			},
 			"warehouse": schema.StringAttribute{
-				Description: "The warehouse to use for the Iceberg REST catalog. This will be passed as `warehouse` property in the catalog properties.",
+				Description: "The warehouse to use for the Iceberg REST catalog. It is sent as the `warehouse` parameter of the config request, and all later requests use the path prefix the catalog returns for it. Multi-warehouse catalogs such as Polaris and Lakekeeper require it. With type = 'sql' or 'memory', the location new tables are created under, such as file:///tmp/warehouse or s3://bucket/warehouse; with type = 'memory', provider instances of the process configured with the same warehouse share one catalog, and without it the catalog and a temporary warehouse belong to the provider instance and are removed when it shuts down. Defaults to the ICEBERG_WAREHOUSE environment variable unless type is 'glue'.",
+				Optional:    true,
+			},
+			"uri_prefix": schema.StringAttribute{
+				Description: "The path prefix of namespace and table routes, which are sent to `<catalog_uri>/v1/<uri_prefix>/namespaces/...`. It replaces the prefix the catalog returns in its config response, for catalogs mounted by a gateway under another path such as `lakehouse/api/catalog`. Leading and trailing slashes are ignored.",
 				Optional:    true,
 			},
 			"headers": schema.MapAttribute{
-				Description: "The headers to use for authentication.",
+				Description: "Headers to send with every request to the catalog and the Polaris management API, for example for authentication by a gateway. Headers the provider sets itself, such as Authorization and Content-Type, aren't replaced. Defaults to the ICEBERG_HEADERS environment variable, a JSON object such as {\"X-Tenant-Id\": \"analytics\"}.",
 				Optional:    true,
 				Sensitive:   true,
 				ElementType: types.StringType,
 			},
+			"user_agent_extra": schema.StringAttribute{
+				Description: "Text appended to the User-Agent header of every request to the catalog and the Polaris management API, which is apache-iceberg-terraform/<version> terraform-plugin-framework/<version> otherwise, for example to tell the traffic of several pipelines apart in the access logs of the catalog.",
+				Optional:    true,
+			},
+			"warn_on_drift": schema.BoolAttribute{
+				Description: "If true, refreshing a resource emits a warning summarizing any user-managed values that were changed outside of Terraform.",
+				Optional:    true,
+			},
+			"read_only": schema.BoolAttribute{
+				Description: "If true, the provider never changes the catalog: creating, updating or deleting any resource fails, with any type of catalog. Requests to REST catalogs and the management APIs are also limited to GET and HEAD. Refresh, plan and data sources work as usual.",
+				Optional:    true,
+			},
+			"validate_on_plan": schema.BoolAttribute{
+				Description: "If true, planning a new iceberg_table sends a staged create to the catalog, which isn't committed, so schema, partition spec, sort order and property errors are reported at plan time. Catalogs without staged creates skip the check.",
+				Optional:    true,
+			},
+			"validate_connection": schema.BoolAttribute{
+				Description: "If true, configuring the provider sends a config request to the REST catalog, so a wrong catalog_uri, warehouse or credentials fail with a diagnostic naming the status code rather than in the first resource that uses the catalog. With other catalog types, the catalog client is created instead. The check is skipped while the provider configuration has unknown values. Defaults to true.",
+				Optional:    true,
+			},
+			"enable_operation_metrics": schema.BoolAttribute{
+				Description: "If true, every request to the catalog and the Polaris management API is logged at debug level with its duration and the running call count and latency of the resource type that sent it. A summary per resource type, and of the calls per endpoint family as iceberg_provider_info reports them, is written to the provider's stderr when it shuts down.",
+				Optional:    true,
+			},
+			"access_delegation": schema.StringAttribute{
+				Description: "The X-Iceberg-Access-Delegation header sent when the REST catalog loads or creates a table, 'vended-credentials' or 'remote-signing'. Some catalogs return different metadata depending on it. If unset, no header is sent.",
+				Optional:    true,
+			},
+			"audit_log_path": schema.StringAttribute{
+				Description: "A file the provider appends a JSON line to for every request that changes the catalog, the Polaris management API or the Glue Data Catalog, with its timestamp, resource type, operation, identifier, outcome, status and duration in milliseconds. Headers, properties and other request contents are never written. The file is created if it doesn't exist and synced to disk when the provider shuts down.",
+				Optional:    true,
+			},
+			"restrict_map_keys": schema.BoolAttribute{
+				Description: "If true, iceberg_table schemas may only use string, int, long and date map keys, including in nested structs. Other key types are valid in Iceberg but not supported by several query engines.",
+				Optional:    true,
+			},
+			"forbid_timestamp_without_zone": schema.BoolAttribute{
+				Description: "If true, iceberg_table schemas may not use timestamp or timestamp_ns, including as list elements, map keys and values and in nested structs. All offending fields are listed in one error at plan time; use timestamptz or timestamptz_ns instead. Combines with restrict_map_keys.",
+				Optional:    true,
+			},
+			"forbid_duplicate_tables": schema.BoolAttribute{
+				Description: "If true, two iceberg_table resources managing the same table is an error at plan time. By default it is a warning. Resources of other provider configurations aren't compared.",
+				Optional:    true,
+			},
+			"warn_on_ignored_property_removals": schema.BoolAttribute{
+				Description: "If true, properties of a namespace or table that the catalog still returns after accepting their removal are reported as a warning rather than failing the apply. Some catalogs answer removals with success without removing anything, which would otherwise show up as drift on the next refresh.",
+				Optional:    true,
+			},
+			"verify_after_apply": schema.BoolAttribute{
+				Description: "If true, every create and update reads the object back once more when it is done and fails the apply if the managed values differ from the configuration, listing each difference. Values the catalog normalizes, such as the case of boolean properties, aren't differences. This catches catalogs that accept changes but store something else or apply them only partially, at the cost of one more request per object.",
+				Optional:    true,
+			},
+			"skip_owner_validation": schema.BoolAttribute{
+				Description: "If true, the owner of iceberg_table and iceberg_namespace resources isn't checked against the Polaris principals at plan time. Owners are only checked with type = 'polaris'.",
+				Optional:    true,
+			},
+			"name_prefix": schema.StringAttribute{
+				Description: "A prefix prepended to the first level of the namespace of every iceberg_namespace, iceberg_namespaces, iceberg_table and iceberg_table_properties_overlay, so several workspaces can share one catalog. Names in the configuration and in state don't include the prefix; resource IDs and import IDs do. Changing the prefix while these resources exist is an error. Data sources, ephemeral resources and Polaris resources use names as given.",
+				Optional:    true,
+			},
+			"prefix_table_names": schema.BoolAttribute{
+				Description: "If true, name_prefix is also prepended to the names of iceberg_table resources and of the tables listed in iceberg_table_properties_overlay.",
+				Optional:    true,
+			},
+			"max_retries": schema.Int64Attribute{
+				Description: "How often a request to the catalog, the Polaris management API or the OAuth2 token endpoint is retried when the server responds with status 429, 502, 503 or 504. Requests that may have been applied, such as commits, are only retried when the response has a Retry-After header or the request an Idempotency-Key. Defaults to 3; 0 turns retries off.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_backoff": schema.StringAttribute{
+				Description: "The longest wait before a retry, as a duration such as 30s. Retries wait exponentially longer, starting at 500ms, or as long as the Retry-After header of the response asks, up to this limit. Defaults to 30s.",
+				Optional:    true,
+			},
+			"max_requests_per_second": schema.Int64Attribute{
+				Description: "The most requests per second the provider sends to the catalog, the Polaris management API and the OAuth2 token endpoint, for catalogs that rate limit clients, such as Snowflake Open Catalog. Requests are spaced evenly and shared by all resources and data sources, however many Terraform runs in parallel; retries count as requests. Defaults to 0, which is unlimited.",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"polaris_settings": schema.SingleNestedBlock{
 				Description: "Settings specific to Polaris when type = 'polaris'.",
 				Attributes: map[string]schema.Attribute{
 					"management_uri": schema.StringAttribute{
-						Description: "The base URI for the Polaris Management API. If omitted, it will be derived from catalog_uri by appending '/api/management/v1'.",
+						Description: "The base URI for the Polaris Management API. If omitted, it is taken from the ICEBERG_POLARIS_MANAGEMENT_URI environment variable, or derived from catalog_uri: a catalog_uri of <base>/api/catalog, such as https://host/polaris/api/catalog, gives <base>/api/management/v1, and any other catalog_uri the '/api/management/v1' path on its host.",
 						Optional:    true,
 					},
 					"catalog_name": schema.StringAttribute{
 						Description: "Default Polaris catalog name for RBAC resources.",
 						Optional:    true,
 					},
+					"client_id": schema.StringAttribute{
+						Description: "The client ID of a Polaris principal to request management API tokens for, with the PRINCIPAL_ROLE:ALL scope, from the token endpoint of the catalog or oauth2_server_uri. Tokens are requested again when the management API rejects them. Without it, the management API gets the token of the catalog. Ignored if token is set. Must be set with client_secret.",
+						Optional:    true,
+					},
+					"client_secret": schema.StringAttribute{
+						Description: "The client secret of client_id.",
+						Optional:    true,
+						Sensitive:   true,
+					},
 				},
 			},
+			"s3":   s3Block(),
+			"adls": adlsBlock(),
+			"gcs":  gcsBlock(),
 		},
 	}
 }
 
+// ValidateConfig checks the attributes that depend on the type of catalog.
+func (p *icebergProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var data icebergProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	withEnvironmentDefaults(&data, &resp.Diagnostics)
+	validateCatalogTypeConfig(data, &resp.Diagnostics)
+}
+
 // Configure prepares a Iceberg API client for data sources and resources.
 func (p *icebergProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data icebergProviderModel
@@ -130,6 +484,7 @@ func (p *icebergProvider) Configure(ctx context.Context, req provider.ConfigureR
 	diags := req.Config.Get(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 
+	withEnvironmentDefaults(&data, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -140,16 +495,39 @@ func (p *icebergProvider) Configure(ctx context.Context, req provider.ConfigureR
 
 	p.catalogURI = data.CatalogURI.ValueString()
 
-	// Determine catalog type: support "rest" and "polaris".
+	// Determine catalog type: support "rest", "polaris", "glue", "sql" and "memory".
 	catalogType := "rest"
 	if !data.Type.IsNull() && !data.Type.IsUnknown() {
 		catalogType = data.Type.ValueString()
 	}
 
+	p.glue = nil
+	p.sql = nil
 	switch catalogType {
 	case "rest":
 		p.catalogType = "rest"
 		p.polaris = nil
+	case "glue":
+		p.catalogType = "glue"
+		p.polaris = nil
+		p.glue = loadGlueConfig(ctx, data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	case "sql":
+		p.catalogType = "sql"
+		p.polaris = nil
+		p.sql = loadSQLConfig(data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	case "memory":
+		p.catalogType = "memory"
+		p.polaris = nil
+		p.sql = p.loadMemoryConfig(data, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	case "polaris":
 		// Under the hood this is still a REST catalog; Polaris is an overlay for management APIs.
 		p.catalogType = "rest"
@@ -166,17 +544,18 @@ func (p *icebergProvider) Configure(ctx context.Context, req provider.ConfigureR
 
 		// Derive management URI from catalog_uri if not explicitly set.
 		if cfg.managementURI == "" && p.catalogURI != "" {
-			if u, err := url.Parse(p.catalogURI); err == nil {
-				u.Path = "/api/management/v1"
-				cfg.managementURI = strings.TrimRight(u.String(), "/")
-			}
+			cfg.managementURI = derivePolarisManagementURI(p.catalogURI)
+			tflog.Debug(ctx, "Derived the Polaris management API URI from catalog_uri", map[string]any{
+				"catalog_uri":    p.catalogURI,
+				"management_uri": cfg.managementURI,
+			})
 		}
 
 		p.polaris = cfg
 	default:
 		resp.Diagnostics.AddError(
 			"Unsupported Catalog Type",
-			"The provider supports 'rest' and 'polaris'. Got: "+catalogType,
+			"The provider supports 'rest', 'polaris', 'glue', 'sql' and 'memory'. Got: "+catalogType,
 		)
 
 		return
@@ -186,10 +565,59 @@ func (p *icebergProvider) Configure(ctx context.Context, req provider.ConfigureR
 		p.token = data.Token.ValueString()
 	}
 
+	scope := oauth2CatalogScope
+	if p.polaris != nil {
+		scope = oauth2PolarisScope
+	}
+	p.oauth2 = validateOAuth2Config(data, p.catalogURI, scope, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	p.basicAuth = validateBasicAuthConfig(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	p.authHeader = validateAuthHeaderConfig(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if p.polaris != nil {
+		p.polaris.oauth2 = validatePolarisOAuth2Config(data, p.catalogURI, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// The environment only provides a token if the configuration doesn't
+	// authenticate otherwise.
+	if p.token == "" && p.oauth2 == nil && data.Token.IsNull() && data.BasicAuthUsername.IsNull() && data.AuthHeaderName.IsNull() && p.glue == nil && p.sql == nil {
+		p.token = os.Getenv(tokenEnvVar)
+	}
+
+	p.sigv4 = loadSigV4Config(ctx, data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !data.Warehouse.IsNull() && !data.Warehouse.IsUnknown() {
 		p.warehouse = data.Warehouse.ValueString()
 	}
 
+	p.fileIO = fileIOConfig(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p.uriPrefix = uriPrefixConfig(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p.accessDelegation = accessDelegationConfig(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !data.Headers.IsNull() && !data.Headers.IsUnknown() {
 		headers := make(map[string]string)
 		resp.Diagnostics.Append(data.Headers.ElementsAs(ctx, &headers, false)...)
@@ -199,48 +627,346 @@ func (p *icebergProvider) Configure(ctx context.Context, req provider.ConfigureR
 
 		p.headers = headers
 	}
+	p.userAgentExtra = strings.TrimSpace(data.UserAgentExtra.ValueString())
+
+	if !data.WarnOnDrift.IsNull() && !data.WarnOnDrift.IsUnknown() {
+		p.warnOnDrift = data.WarnOnDrift.ValueBool()
+	}
+
+	if !data.ReadOnly.IsNull() && !data.ReadOnly.IsUnknown() {
+		p.readOnly = data.ReadOnly.ValueBool()
+	}
+
+	if !data.ValidateOnPlan.IsNull() && !data.ValidateOnPlan.IsUnknown() {
+		p.validateOnPlan = data.ValidateOnPlan.ValueBool()
+	}
+
+	if !data.RestrictMapKeys.IsNull() && !data.RestrictMapKeys.IsUnknown() {
+		p.restrictMapKeys = data.RestrictMapKeys.ValueBool()
+	}
+
+	if !data.ForbidTimestampWithoutZone.IsNull() && !data.ForbidTimestampWithoutZone.IsUnknown() {
+		p.forbidTimestampWithoutZone = data.ForbidTimestampWithoutZone.ValueBool()
+	}
+
+	if !data.ForbidDuplicateTables.IsNull() && !data.ForbidDuplicateTables.IsUnknown() {
+		p.forbidDuplicateTables = data.ForbidDuplicateTables.ValueBool()
+	}
+
+	if !data.WarnOnIgnoredRemovals.IsNull() && !data.WarnOnIgnoredRemovals.IsUnknown() {
+		p.warnOnIgnoredRemovals = data.WarnOnIgnoredRemovals.ValueBool()
+	}
+
+	if !data.VerifyAfterApply.IsNull() && !data.VerifyAfterApply.IsUnknown() {
+		p.verifyAfterApply = data.VerifyAfterApply.ValueBool()
+	}
+
+	if !data.SkipOwnerCheck.IsNull() && !data.SkipOwnerCheck.IsUnknown() {
+		p.skipOwnerValidation = data.SkipOwnerCheck.ValueBool()
+	}
+
+	if !data.NamePrefix.IsNull() && !data.NamePrefix.IsUnknown() {
+		p.namePrefix = data.NamePrefix.ValueString()
+	}
+
+	if !data.PrefixTableNames.IsNull() && !data.PrefixTableNames.IsUnknown() {
+		p.prefixTableNames = data.PrefixTableNames.ValueBool()
+	}
+
+	if p.apiCalls == nil {
+		p.apiCalls = &apiCallCounter{}
+	}
+	if !data.OperationMetrics.IsNull() && !data.OperationMetrics.IsUnknown() && data.OperationMetrics.ValueBool() && p.metrics == nil {
+		p.metrics = newOperationMetrics()
+		p.metrics.apiCalls = p.apiCalls
+	}
+
+	p.configureAuditLog(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p.maxRetries, p.retryMaxBackoff = retryConfig(data, &resp.Diagnostics)
+	p.rateLimiter = rateLimitConfig(data)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p.httpTransport = newHTTPTransport(data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	p.capabilities = newCatalogCapabilities()
+	p.resourceClaims = newResourceClaims()
+	p.sharedCatalog = &sharedCatalog{}
+
+	// During plan, the configuration can still hold values of resources that
+	// aren't created yet; the check then waits for the apply.
+	if data.ValidateConnection.ValueBool() || data.ValidateConnection.IsNull() {
+		if req.Config.Raw.IsFullyKnown() && p.catalogConfigured() {
+			p.checkConnection(ctx, &resp.Diagnostics)
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	resp.DataSourceData = p
+	resp.EphemeralResourceData = p
 	resp.ResourceData = p
 }
 
-func (p *icebergProvider) NewCatalog(ctx context.Context) (catalog.Catalog, error) {
-	opts := make([]rest.Option, 0)
-	if p.token != "" {
-		opts = append(opts, rest.WithOAuthToken(p.token))
+// catalogConfigured reports whether the catalog is known, which it isn't
+// while catalog_uri is unknown during plan.
+func (p *icebergProvider) catalogConfigured() bool {
+	return p.catalogURI != "" || p.glue != nil || p.sql != nil
+}
+
+// Close releases what the provider holds once Terraform is done with it: it
+// writes the summary of the operation metrics to stderr, closes the audit log
+// and drops the memory catalog of the instance. The provider server calls it
+// when it stops.
+func (p *icebergProvider) Close() error {
+	return errors.Join(p.writeOperationMetrics(os.Stderr), p.closeAuditLog(), p.closeMemoryCatalog())
+}
+
+// NewCatalog returns the catalog client of the provider configuration, which
+// all resources and data sources share, creating it on first use. Its
+// requests are counted for the resource type of their context with
+// enable_operation_metrics, see withResourceType, and for resourceType, the
+// type of the resource or data source that created it, if it has none.
+func (p *icebergProvider) NewCatalog(ctx context.Context, resourceType string) (catalog.Catalog, error) {
+	return p.sharedCatalog.get(func() (catalog.Catalog, error) {
+		return p.newCatalog(ctx, resourceType)
+	})
+}
+
+// newCatalog creates a catalog client.
+func (p *icebergProvider) newCatalog(ctx context.Context, resourceType string) (catalog.Catalog, error) {
+	if p.glue != nil {
+		return p.newGlueCatalog(resourceType), nil
+	}
+	if p.sql != nil {
+		return p.newSQLCatalog()
 	}
 
+	// The bearer token is set by catalogTransport, so the client keeps
+	// working once an issued token expires.
+	opts := make([]rest.Option, 0)
 	if p.warehouse != "" {
 		opts = append(opts, rest.WithWarehouseLocation(p.warehouse))
 	}
 
-	opts = append(opts, rest.WithCustomTransport(&headerRoundTripper{headers: p.headers}))
+	opts = append(opts, rest.WithCustomTransport(p.catalogTransport(resourceType)))
 
 	return rest.NewCatalog(ctx, p.catalogType, p.catalogURI, opts...)
 }
 
+// catalogTransport returns the transport of catalog clients for
+// resourceType: transport, signed with sigv4_enabled or authenticated with
+// basic_auth_username, the bearer token or auth_header_name, with the
+// configured headers.
+func (p *icebergProvider) catalogTransport(resourceType string) http.RoundTripper {
+	next := p.transport(resourceType)
+	if p.sigv4 != nil {
+		next = newSigV4Transport(p.sigv4, next)
+	}
+	if p.basicAuth != nil {
+		next = &basicAuthTransport{next: next, credentials: p.basicAuth}
+	}
+	if p.oauth2 != nil || p.token != "" {
+		bearer := &bearerTokenTransport{next: next, token: func(ctx context.Context) (string, error) {
+			return p.accessToken(ctx, contextResourceType(ctx, resourceType))
+		}}
+		if p.oauth2 != nil {
+			bearer.invalidate = p.oauth2.invalidate
+		}
+		next = bearer
+	}
+	if p.authHeader != nil {
+		next = &authHeaderTransport{next: next, header: p.authHeader}
+	}
+
+	return &headerRoundTripper{headers: p.headers, prefix: p.uriPrefix, accessDelegation: p.accessDelegation, capabilities: p.capabilities, next: next}
+}
+
+// managementTransport returns the transport of management API clients for
+// resourceType: transport, authenticated with auth_header_name if it is set.
+// Otherwise the clients set the Authorization header themselves.
+func (p *icebergProvider) managementTransport(resourceType string) http.RoundTripper {
+	next := p.transport(resourceType)
+	if p.authHeader != nil {
+		next = &authHeaderTransport{next: next, header: p.authHeader}
+	}
+
+	return next
+}
+
+// transport returns the transport used for all requests to the catalog and
+// the management API made for resourceType.
+func (p *icebergProvider) transport(resourceType string) http.RoundTripper {
+	next := p.baseTransport()
+	if p.metrics != nil {
+		next = &metricsTransport{next: next, metrics: p.metrics, resourceType: resourceType}
+	}
+	next = p.withAuditLog(p.withRetries(next), resourceType)
+	if p.readOnly {
+		return &readOnlyTransport{next: next}
+	}
+
+	return next
+}
+
+// baseTransport returns the transport requests are sent with once they
+// passed all others, setting their User-Agent, pacing them with
+// max_requests_per_second, counting them and logging them.
+func (p *icebergProvider) baseTransport() http.RoundTripper {
+	var next http.RoundTripper = http.DefaultTransport
+	if p.httpTransport != nil {
+		next = p.httpTransport
+	}
+	logTransport := &httpLogTransport{next: next, bodies: traceLogging()}
+	if p.authHeader != nil {
+		// The header can have any name, see authHeaderTransport.
+		logTransport.sensitiveHeaders = []string{p.authHeader.name}
+	}
+	next = logTransport
+	next = &userAgentTransport{next: next, userAgent: userAgent(providerVersion(p.version)), extra: p.userAgentExtra}
+	if p.rateLimiter != nil {
+		next = &rateLimitTransport{next: next, limiter: p.rateLimiter}
+	}
+	if p.apiCalls == nil {
+		return next
+	}
+
+	return &apiCallTransport{next: next, counter: p.apiCalls}
+}
+
+// catalogName returns the name of the catalog: catalog_name in
+// polaris_settings, or for Polaris the path prefix from the config overrides,
+// which is the name of the catalog the warehouse selected. It is empty for
+// other catalogs, whose prefixes aren't names, and for Polaris until a
+// catalog client fetched the config.
+func (p *icebergProvider) catalogName() string {
+	if p.polaris != nil && p.polaris.catalogName != "" {
+		return p.polaris.catalogName
+	}
+	if p.polaris == nil && p.capabilities.serverImplementation() != "polaris" {
+		return ""
+	}
+
+	name, err := url.PathUnescape(p.capabilities.catalogPrefix())
+	if err != nil {
+		return ""
+	}
+
+	return name
+}
+
+// catalogNameDescription describes the catalog_name attribute of resources and
+// data sources.
+const catalogNameDescription = "The name of the catalog, for example for spark.sql.catalog.<name> settings: catalog_name in polaris_settings, or for Polaris the name in the path prefix the catalog returns in its config. Null if the catalog has no name."
+
+// catalogNameValue returns catalogName as the value of a catalog_name
+// attribute, null if the catalog has no name.
+func (p *icebergProvider) catalogNameValue() types.String {
+	if name := p.catalogName(); name != "" {
+		return types.StringValue(name)
+	}
+
+	return types.StringNull()
+}
+
 type headerRoundTripper struct {
 	headers map[string]string
+	// prefix replaces the prefix in the config response if it isn't empty.
+	prefix string
+	// accessDelegation is the access delegation header of table loads and
+	// creates, none if it is empty.
+	accessDelegation string
+	capabilities     *catalogCapabilities
+	next             http.RoundTripper
 }
 
 func (h *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	setAccessDelegation(req, h.accessDelegation)
 	for k, v := range h.headers {
-		req.Header.Add(k, v)
+		// Headers the catalog client sets itself, such as Authorization and
+		// Content-Type, win over configured ones.
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	next := h.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	check := observeConfigRequest(req)
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if err := checkJSONResponse(req, resp); err != nil {
+		resp.Body.Close()
+
+		return nil, err
+	}
+	check.observe(resp)
+	warnDuplicateProperties(req, resp)
+	filterNullProperties(req, resp)
+	if err := overridePrefix(req, resp, h.prefix); err != nil {
+		return nil, err
+	}
+	if h.capabilities == nil {
+		return resp, nil
 	}
 
-	return http.DefaultTransport.RoundTrip(req)
+	return h.capabilities.observe(req, resp)
 }
 
 // DataSources defines the data sources implemented in the provider.
 func (p *icebergProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewNamespaceDataSource,
+		NewTablesDataSource,
+		NewManagedInventoryDataSource,
+		NewProviderInfoDataSource,
+		NewTableComplianceDataSource,
+		NewTableSchemaDataSource,
+	}
+}
+
+// EphemeralResources defines the ephemeral resources implemented in the
+// provider.
+func (p *icebergProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewTableIOCredentialsEphemeralResource,
+	}
+}
+
+// Functions defines the functions implemented in the provider.
+func (p *icebergProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewMergeSchemasFunction,
+		NewQuoteIdentifierFunction,
+		NewSchemaDiffFunction,
+		NewSchemaFingerprintFunction,
+	}
 }
 
 // Resources defines the resources implemented in the provider.
 func (p *icebergProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewNamespaceResource,
+		NewNamespacesResource,
 		NewTableResource,
+		NewTablePropertiesOverlayResource,
 		NewPolarisPrincipalResource,
+		NewPolarisPrincipalRoleResource,
+		NewPolarisGrantsResource,
+		NewPolarisCatalogDefaultsResource,
 	}
 }
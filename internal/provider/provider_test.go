@@ -16,11 +16,504 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProvider(t *testing.T) {
-	assert.NotNil(t, New()())
+	assert.NotNil(t, New("test")())
+}
+
+func TestProviderCatalogName(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		polaris *polarisConfig
+		server  string
+		prefix  string
+		want    string
+	}{
+		{name: "rest without prefix"},
+		{name: "rest prefix isn't a name", prefix: "ws/9f2c", server: "lakekeeper"},
+		{name: "polaris settings", polaris: &polarisConfig{catalogName: "analytics"}, prefix: "other", want: "analytics"},
+		{name: "polaris prefix", polaris: &polarisConfig{}, prefix: "sales%20eu", want: "sales eu"},
+		{name: "polaris server", server: "polaris", prefix: "analytics", want: "analytics"},
+		{name: "polaris before config", polaris: &polarisConfig{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &icebergProvider{polaris: tc.polaris, capabilities: newCatalogCapabilities()}
+			p.capabilities.setServer(tc.server)
+			p.capabilities.setPrefix(tc.prefix)
+
+			assert.Equal(t, tc.want, p.catalogName())
+			assert.Equal(t, tc.want == "", p.catalogNameValue().IsNull())
+		})
+	}
+}
+
+// TestAccProvider_MultipleCatalogs manages two catalogs in one configuration,
+// each through its own provider instance, and checks that neither sees the
+// settings or objects of the other.
+func TestAccProvider_MultipleCatalogs(t *testing.T) {
+	primary := newMockRESTCatalog()
+	primaryServer := primary.start(t)
+	secondary := newMockRESTCatalog()
+	secondaryServer := secondary.start(t)
+
+	config := fmt.Sprintf(`
+terraform {
+  required_providers {
+    iceberg = {
+      source = "registry.terraform.io/hashicorp/iceberg"
+    }
+    iceberg-secondary = {
+      source = "registry.terraform.io/hashicorp/iceberg-secondary"
+    }
+  }
+}
+
+provider "iceberg" {
+  alias       = "primary"
+  catalog_uri = "%s"
+}
+
+provider "iceberg-secondary" {
+  alias       = "secondary"
+  catalog_uri = "%s"
+  name_prefix = "dr_"
+}
+
+resource "iceberg_namespace" "primary" {
+  provider = iceberg.primary
+  name     = ["sales"]
+  user_properties = {
+    region = "eu"
+  }
+}
+
+resource "iceberg_namespace" "secondary" {
+  provider = iceberg-secondary.secondary
+  name     = ["sales"]
+  user_properties = {
+    region = "us"
+  }
+}
+
+data "iceberg_provider_info" "primary" {
+  provider = iceberg.primary
+}
+
+data "iceberg_provider_info" "secondary" {
+  provider = iceberg-secondary.secondary
+}
+`, primaryServer.URL, secondaryServer.URL)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesFor("iceberg", "iceberg-secondary"),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.primary", "id", "sales"),
+					resource.TestCheckResourceAttr("iceberg_namespace.secondary", "id", "dr_sales"),
+					resource.TestCheckResourceAttr("data.iceberg_provider_info.primary", "catalog_host", strings.TrimPrefix(primaryServer.URL, "http://")),
+					resource.TestCheckResourceAttr("data.iceberg_provider_info.secondary", "catalog_host", strings.TrimPrefix(secondaryServer.URL, "http://")),
+					func(_ *terraform.State) error {
+						if got := primary.namespaceProperties("sales")["region"]; got != "eu" {
+							return fmt.Errorf("expected region eu in the primary catalog, got %q", got)
+						}
+						if got := secondary.namespaceProperties("dr_sales")["region"]; got != "us" {
+							return fmt.Errorf("expected region us in the secondary catalog, got %q", got)
+						}
+						if primary.namespaceProperties("dr_sales") != nil || secondary.namespaceProperties("sales") != nil {
+							return fmt.Errorf("a namespace was created in the other catalog")
+						}
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+// configureTestProvider configures a new provider with the given provider
+// attributes, leaving all others unset. The connection check is off unless
+// attributes set validate_connection, so catalog_uri needn't point at a
+// catalog.
+func configureTestProvider(t *testing.T, attributes map[string]tftypes.Value) (*icebergProvider, diag.Diagnostics) {
+	t.Helper()
+
+	return configureTestProviderContext(context.Background(), t, attributes)
+}
+
+// configureTestProviderContext is configureTestProvider with ctx, for
+// example to capture the logs of Configure.
+func configureTestProviderContext(ctx context.Context, t *testing.T, attributes map[string]tftypes.Value) (*icebergProvider, diag.Diagnostics) {
+	t.Helper()
+
+	p := &icebergProvider{}
+	t.Cleanup(func() { _ = p.closeMemoryCatalog() })
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, testProviderConfigureRequest(t, attributes), &resp)
+
+	return p, resp.Diagnostics
+}
+
+// testProviderConfigureRequest returns the request configuring the provider
+// with attributes, the others null and validate_connection false.
+func testProviderConfigureRequest(t *testing.T, attributes map[string]tftypes.Value) provider.ConfigureRequest {
+	t.Helper()
+
+	ctx := context.Background()
+	var schemaResp provider.SchemaResponse
+	(&icebergProvider{}).Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, typ := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	values["validate_connection"] = tftypes.NewValue(tftypes.Bool, false)
+	maps.Copy(values, attributes)
+
+	return provider.ConfigureRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+	}
+}
+
+func TestProviderTokenFromEnvironment(t *testing.T) {
+	var auth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		writeJSON(w, http.StatusOK, map[string]any{"defaults": map[string]string{}, "overrides": map[string]string{}})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv(tokenEnvVar, "from-env")
+
+	for _, tc := range []struct {
+		name       string
+		attributes map[string]tftypes.Value
+		want       string
+		wantError  string
+	}{
+		{
+			name: "attribute unset",
+			want: "Bearer from-env",
+		},
+		{
+			name:       "attribute set",
+			attributes: map[string]tftypes.Value{"token": tftypes.NewValue(tftypes.String, "from-config")},
+			want:       "Bearer from-config",
+		},
+		{
+			name: "oauth2",
+			attributes: map[string]tftypes.Value{
+				"oauth2_client_id":     tftypes.NewValue(tftypes.String, "etl"),
+				"oauth2_client_secret": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			},
+		},
+		{
+			name: "oauth2 and token",
+			attributes: map[string]tftypes.Value{
+				"token":                tftypes.NewValue(tftypes.String, "from-config"),
+				"oauth2_client_id":     tftypes.NewValue(tftypes.String, "etl"),
+				"oauth2_client_secret": tftypes.NewValue(tftypes.String, "s3cr3t"),
+			},
+			wantError: "Conflicting authentication settings",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			attributes := map[string]tftypes.Value{"catalog_uri": tftypes.NewValue(tftypes.String, server.URL)}
+			maps.Copy(attributes, tc.attributes)
+			p, diags := configureTestProvider(t, attributes)
+			if tc.wantError != "" {
+				require.Len(t, diags.Errors(), 1)
+				assert.Equal(t, tc.wantError, diags.Errors()[0].Summary())
+
+				return
+			}
+			require.False(t, diags.HasError(), "%v", diags)
+			if tc.want == "" {
+				assert.Empty(t, p.token, "the environment doesn't override OAuth2")
+
+				return
+			}
+
+			auth = nil
+			_, err := p.NewCatalog(context.Background(), "iceberg_namespace")
+			require.NoError(t, err)
+			assert.Equal(t, []string{tc.want}, auth)
+
+			p.polaris = &polarisConfig{managementURI: server.URL + "/api/management/v1"}
+			client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+			require.NoError(t, err)
+			auth = nil
+			_ = client.do(context.Background(), http.MethodGet, "/principals", nil, nil, nil)
+			assert.Equal(t, []string{tc.want}, auth, "the Polaris client sends the same token")
+		})
+	}
+}
+
+// TestProviderWarehouse checks that warehouse is sent with the config request
+// and that later requests go to the prefix the catalog returns for it, like
+// multi-warehouse catalogs such as Polaris and Lakekeeper need.
+func TestProviderWarehouse(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	catalogHandler := m.start(t).Config.Handler
+
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/config" {
+			warehouse := r.URL.Query().Get("warehouse")
+			if warehouse == "" {
+				writeRESTErrorMessage(w, http.StatusBadRequest, "BadRequestException", "warehouse is required")
+
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{
+				"defaults":  map[string]string{},
+				"overrides": map[string]string{"prefix": "wh-" + warehouse},
+			})
+
+			return
+		}
+
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if !strings.HasPrefix(r.URL.Path, "/v1/wh-analytics/") {
+			http.NotFound(w, r)
+
+			return
+		}
+		r.URL.Path = strings.Replace(r.URL.Path, "/v1/wh-analytics", "/v1", 1)
+		catalogHandler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+		"warehouse":   tftypes.NewValue(tftypes.String, "analytics"),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+
+	ctx := context.Background()
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+	_, err = cat.CreateTable(ctx, table.Identifier{"db", "events"}, mockTableSchema())
+	require.NoError(t, err)
+
+	assert.Contains(t, paths, "POST /v1/wh-analytics/namespaces/db/tables")
+	assert.NotNil(t, m.tableProperties("db", "events"))
+
+	t.Run("without warehouse", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+
+		_, err := p.NewCatalog(ctx, "iceberg_table")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "warehouse is required")
+	})
+}
+
+func TestProviderHeaders(t *testing.T) {
+	received := make(map[string]http.Header)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received[r.Method+" "+r.URL.Path] = r.Header.Clone()
+		switch r.URL.Path {
+		case "/v1/config":
+			writeJSON(w, http.StatusOK, map[string]any{"defaults": map[string]string{}, "overrides": map[string]string{}})
+		case "/v1/namespaces":
+			writeJSON(w, http.StatusOK, map[string]any{"namespace": []string{"db"}, "properties": map[string]string{}})
+		case "/api/management/v1/principals":
+			writeJSON(w, http.StatusCreated, map[string]any{"principal": map[string]any{"name": "etl"}, "credentials": map[string]string{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	p := &icebergProvider{
+		catalogURI:  server.URL,
+		catalogType: "rest",
+		token:       "tok",
+		headers: map[string]string{
+			"X-Tenant-Id":   "tenant-1",
+			"Authorization": "Bearer other",
+			"content-type":  "text/plain",
+		},
+		polaris:      &polarisConfig{managementURI: server.URL + "/api/management/v1"},
+		capabilities: newCatalogCapabilities(),
+	}
+	ctx := context.Background()
+
+	cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+	require.NoError(t, err)
+	require.NoError(t, cat.CreateNamespace(ctx, []string{"db"}, nil))
+
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+	_, err = client.CreatePrincipal(ctx, polarisCreatePrincipalRequest{Principal: polarisPrincipal{Name: "etl"}})
+	require.NoError(t, err)
+
+	for _, request := range []string{"POST /v1/namespaces", "POST /api/management/v1/principals"} {
+		headers, ok := received[request]
+		require.True(t, ok, request)
+		assert.Equal(t, []string{"tenant-1"}, headers.Values("X-Tenant-Id"), request)
+		assert.Equal(t, []string{"Bearer tok"}, headers.Values("Authorization"), request)
+		assert.Equal(t, []string{"application/json"}, headers.Values("Content-Type"), request)
+	}
+}
+
+// setProviderEnvironment sets the environment variables of provider
+// attributes to env and clears all others.
+func setProviderEnvironment(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	for _, name := range []string{
+		catalogURIEnvVar, tokenEnvVar, oauth2ClientIDEnvVar, oauth2ClientSecretEnvVar, oauth2ServerURIEnvVar,
+		warehouseEnvVar, polarisManagementURIEnvVar, headersEnvVar,
+	} {
+		t.Setenv(name, env[name])
+	}
+}
+
+func TestProviderEnvironmentDefaults(t *testing.T) {
+	env := map[string]string{
+		catalogURIEnvVar:           "http://env-catalog:8181",
+		oauth2ClientIDEnvVar:       "env-client",
+		oauth2ClientSecretEnvVar:   "env-secret",
+		oauth2ServerURIEnvVar:      "http://env-idp/token",
+		warehouseEnvVar:            "env_warehouse",
+		polarisManagementURIEnvVar: "http://env-polaris/api/management/v1",
+		headersEnvVar:              `{"X-Tenant-Id": "env-tenant"}`,
+	}
+
+	t.Run("environment", func(t *testing.T) {
+		setProviderEnvironment(t, env)
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "polaris"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, "http://env-catalog:8181", p.catalogURI)
+		require.NotNil(t, p.oauth2)
+		assert.Equal(t, "env-client", p.oauth2.clientID)
+		assert.Equal(t, "http://env-idp/token", p.oauth2.serverURI)
+		assert.Equal(t, "env_warehouse", p.warehouse)
+		assert.Equal(t, "http://env-polaris/api/management/v1", p.polaris.managementURI)
+		assert.Equal(t, map[string]string{"X-Tenant-Id": "env-tenant"}, p.headers)
+	})
+
+	t.Run("configuration takes precedence", func(t *testing.T) {
+		setProviderEnvironment(t, env)
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":             tftypes.NewValue(tftypes.String, "polaris"),
+			"catalog_uri":      tftypes.NewValue(tftypes.String, "http://config-catalog:8181"),
+			"oauth2_client_id": tftypes.NewValue(tftypes.String, "config-client"),
+			"warehouse":        tftypes.NewValue(tftypes.String, "config_warehouse"),
+			"headers": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+				"X-Tenant-Id": tftypes.NewValue(tftypes.String, "config-tenant"),
+			}),
+			"polaris_settings": polarisSettingsValue(map[string]tftypes.Value{
+				"management_uri": tftypes.NewValue(tftypes.String, "http://config-polaris/api/management/v1"),
+			}),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, "http://config-catalog:8181", p.catalogURI)
+		require.NotNil(t, p.oauth2)
+		assert.Equal(t, "config-client", p.oauth2.clientID, "the secret still comes from the environment")
+		assert.Equal(t, "config_warehouse", p.warehouse)
+		assert.Equal(t, "http://config-polaris/api/management/v1", p.polaris.managementURI)
+		assert.Equal(t, map[string]string{"X-Tenant-Id": "config-tenant"}, p.headers)
+	})
+
+	t.Run("token in configuration", func(t *testing.T) {
+		setProviderEnvironment(t, env)
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"token": tftypes.NewValue(tftypes.String, "config-token"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Nil(t, p.oauth2, "OAuth2 credentials from the environment don't conflict with token")
+		assert.Equal(t, "config-token", p.token)
+	})
+
+	t.Run("credential in configuration", func(t *testing.T) {
+		setProviderEnvironment(t, env)
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"credential": tftypes.NewValue(tftypes.String, "config-client:config-secret"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.NotNil(t, p.oauth2, "the client from the environment doesn't conflict with credential")
+		assert.Equal(t, "config-client", p.oauth2.clientID)
+		assert.Equal(t, "config-secret", p.oauth2.clientSecret)
+		assert.Equal(t, "http://env-idp/token", p.oauth2.serverURI)
+	})
+
+	t.Run("unknown catalog_uri", func(t *testing.T) {
+		setProviderEnvironment(t, env)
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.False(t, p.catalogConfigured(), "configuration is deferred until catalog_uri is known")
+	})
+
+	t.Run("not applicable to glue", func(t *testing.T) {
+		setProviderEnvironment(t, env)
+		t.Setenv("AWS_REGION", "eu-west-1")
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type": tftypes.NewValue(tftypes.String, "glue"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Empty(t, p.catalogURI)
+		assert.Empty(t, p.warehouse)
+		assert.Nil(t, p.oauth2)
+	})
+
+	t.Run("invalid headers", func(t *testing.T) {
+		setProviderEnvironment(t, map[string]string{catalogURIEnvVar: "http://env-catalog:8181", headersEnvVar: `["X-Tenant-Id"]`})
+		_, diags := configureTestProvider(t, nil)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Invalid ICEBERG_HEADERS", diags.Errors()[0].Summary())
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		setProviderEnvironment(t, map[string]string{catalogURIEnvVar: "http://env-catalog:8181"})
+		ctx := context.Background()
+		p := &icebergProvider{}
+		var schemaResp provider.SchemaResponse
+		p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+		objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+		values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+		for name, typ := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(typ, nil)
+		}
+
+		var resp provider.ValidateConfigResponse
+		p.ValidateConfig(ctx, provider.ValidateConfigRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+		}, &resp)
+		assert.False(t, resp.Diagnostics.HasError(), "catalog_uri from the environment satisfies validation: %v", resp.Diagnostics)
+	})
 }
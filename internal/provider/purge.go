@@ -0,0 +1,75 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/apache/iceberg-go/table"
+)
+
+const (
+	// gcEnabledProperty set to false marks a table whose files may be shared
+	// with other tables, so they must not be deleted.
+	gcEnabledProperty = "gc.enabled"
+	// externalProperty set to true marks a table whose files are owned by
+	// another system.
+	externalProperty = "external"
+)
+
+// tablePurger is implemented by catalogs that can drop a table along with its
+// data and metadata files.
+type tablePurger interface {
+	PurgeTable(ctx context.Context, identifier table.Identifier) error
+}
+
+// purgeRefusal returns why a table with props must not be purged, or "" if it
+// may be. Purging a table with gc.enabled=false can delete files that other
+// tables still reference, and the files of an external table belong to
+// another system.
+func purgeRefusal(props map[string]string) string {
+	var reasons []string
+	if strings.EqualFold(props[gcEnabledProperty], "false") {
+		reasons = append(reasons, gcEnabledProperty+"=false")
+	}
+	for k, v := range props {
+		if strings.EqualFold(k, externalProperty) && strings.EqualFold(v, "true") {
+			reasons = append(reasons, k+"="+v)
+
+			break
+		}
+	}
+
+	return strings.Join(reasons, " and ")
+}
+
+// decidePurge reports whether a table with props is purged on destroy, given
+// purge_on_destroy and override_gc_check, and why a requested purge was
+// refused.
+func decidePurge(purgeOnDestroy, overrideGCCheck bool, props map[string]string) (purge bool, refusal string) {
+	if !purgeOnDestroy {
+		return false, ""
+	}
+	if overrideGCCheck {
+		return true, ""
+	}
+	if refusal := purgeRefusal(props); refusal != "" {
+		return false, refusal
+	}
+
+	return true, ""
+}
@@ -0,0 +1,152 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecidePurge(t *testing.T) {
+	gcValues := []string{"", "true", "false", "FALSE"}
+	externalValues := []string{"", "true", "false", "TRUE"}
+
+	for _, purgeOnDestroy := range []bool{false, true} {
+		for _, override := range []bool{false, true} {
+			for _, gc := range gcValues {
+				for _, external := range externalValues {
+					props := make(map[string]string)
+					if gc != "" {
+						props["gc.enabled"] = gc
+					}
+					if external != "" {
+						props["external"] = external
+					}
+
+					unsafe := gc == "false" || gc == "FALSE" || external == "true" || external == "TRUE"
+					name := fmt.Sprintf("purge=%t override=%t gc=%q external=%q", purgeOnDestroy, override, gc, external)
+					t.Run(name, func(t *testing.T) {
+						purge, refusal := decidePurge(purgeOnDestroy, override, props)
+
+						assert.Equal(t, purgeOnDestroy && (override || !unsafe), purge)
+						assert.Equal(t, purgeOnDestroy && !override && unsafe, refusal != "", "refusal %q", refusal)
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestPurgeRefusal(t *testing.T) {
+	assert.Equal(t, "", purgeRefusal(map[string]string{"gc.enabled": "true"}))
+	assert.Equal(t, "gc.enabled=false", purgeRefusal(map[string]string{"gc.enabled": "false"}))
+	assert.Equal(t, "EXTERNAL=TRUE", purgeRefusal(map[string]string{"EXTERNAL": "TRUE"}))
+	assert.Equal(t, "gc.enabled=false and external=true", purgeRefusal(map[string]string{"gc.enabled": "false", "external": "true"}))
+}
+
+func TestTableDropTable(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	m.addTable("db", "shared", nil)
+	server := m.start(t)
+
+	r := &icebergTableResource{provider: &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}}
+	var diags diag.Diagnostics
+	r.ConfigureCatalog(ctx, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	require.NoError(t, r.dropTable(ctx, table.Identifier{"db", "events"}, true))
+	require.NoError(t, r.dropTable(ctx, table.Identifier{"db", "shared"}, false))
+
+	assert.Equal(t, []string{"db.events"}, m.purged)
+	assert.Nil(t, m.tableProperties("db", "shared"))
+}
+
+func TestAccIcebergTable_PurgeOnDestroy(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "events" {
+  namespace        = ["db"]
+  name             = "events"
+  purge_on_destroy = true
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+
+resource "iceberg_table" "shared" {
+  namespace        = ["db"]
+  name             = "shared"
+  purge_on_destroy = true
+  user_properties = {
+    "gc.enabled" = "false"
+  }
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if !slices.Equal(m.purged, []string{"db.events"}) {
+				return fmt.Errorf("expected only db.events to be purged, got %v", m.purged)
+			}
+			if m.tableProperties("db", "shared") != nil {
+				return fmt.Errorf("db.shared wasn't dropped")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  resource.TestCheckResourceAttr("iceberg_table.shared", "server_properties.gc.enabled", "false"),
+			},
+		},
+	})
+}
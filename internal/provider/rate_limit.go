@@ -0,0 +1,51 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitConfig returns the limiter of max_requests_per_second, nil if it
+// is unset or 0. Every Configure creates a new one, which all resources and
+// data sources of the walk share, so the limit holds for the provider
+// configuration however many of them Terraform runs in parallel.
+func rateLimitConfig(data icebergProviderModel) *rate.Limiter {
+	if data.MaxRequestsPerSecond.IsNull() || data.MaxRequestsPerSecond.IsUnknown() || data.MaxRequestsPerSecond.ValueInt64() <= 0 {
+		return nil
+	}
+
+	// A burst of one spaces requests evenly rather than sending a second's
+	// worth at once, which catalogs that limit shorter windows would reject.
+	return rate.NewLimiter(rate.Limit(data.MaxRequestsPerSecond.ValueInt64()), 1)
+}
+
+// rateLimitTransport waits for the limiter before sending a request. It sits
+// below retries, so every attempt of a request is limited.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{})
+	}))
+	t.Cleanup(server.Close)
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"catalog_uri":             tftypes.NewValue(tftypes.String, server.URL),
+		"max_requests_per_second": tftypes.NewValue(tftypes.Number, 20),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+
+	// Resources of different types run in parallel and share the limit.
+	const requests = 10
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range requests {
+		resourceType := "iceberg_table"
+		if i%2 == 1 {
+			resourceType = "iceberg_namespace"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := (&http.Client{Transport: p.transport(resourceType)}).Get(server.URL + "/v1/namespaces")
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The first request is sent at once, the others 50ms apart.
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	require.Len(t, arrivals, requests)
+	slices.SortFunc(arrivals, func(a, b time.Time) int { return a.Compare(b) })
+	for i := 1; i < len(arrivals); i++ {
+		assert.GreaterOrEqual(t, arrivals[i].Sub(arrivals[i-1]), 30*time.Millisecond, "request %d", i)
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		for _, value := range []tftypes.Value{tftypes.NewValue(tftypes.Number, nil), tftypes.NewValue(tftypes.Number, 0)} {
+			p, diags := configureTestProvider(t, map[string]tftypes.Value{
+				"catalog_uri":             tftypes.NewValue(tftypes.String, server.URL),
+				"max_requests_per_second": value,
+			})
+			require.False(t, diags.HasError(), "%v", diags)
+			assert.Nil(t, p.rateLimiter)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+var errReadOnly = errors.New("the provider is configured with read_only = true")
+
+// checkWritable reports whether resources may change the catalog. In
+// read-only mode it adds the same error for every create, update and delete,
+// before the resource talks to the catalog.
+func (p *icebergProvider) checkWritable(resourceType, operation string, diags *diag.Diagnostics) bool {
+	if p == nil || !p.readOnly {
+		return true
+	}
+
+	diags.AddError(
+		"Provider is read-only",
+		fmt.Sprintf("Cannot %s %s: %s. Only reads and data sources are allowed; unset read_only to apply changes.", operation, resourceType, errReadOnly),
+	)
+
+	return false
+}
+
+// readOnlyTransport refuses every request that isn't a GET or HEAD. It backs
+// up checkWritable in read-only mode, so a code path that misses the check
+// still can't change the catalog.
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return nil, fmt.Errorf("refusing %s %s: %w", req.Method, req.URL.Path, errReadOnly)
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,178 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	testresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReadOnlyTestProvider(t *testing.T, m *mockRESTCatalog) *icebergProvider {
+	t.Helper()
+
+	server := m.start(t)
+
+	return &icebergProvider{
+		catalogURI:   server.URL,
+		catalogType:  "rest",
+		polaris:      &polarisConfig{managementURI: server.URL + "/api/management/v1"},
+		readOnly:     true,
+		capabilities: newCatalogCapabilities(),
+	}
+}
+
+func TestReadOnlyResourcesRefuseChanges(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	p := newReadOnlyTestProvider(t, m)
+
+	for _, newResource := range p.Resources(ctx) {
+		r := newResource()
+
+		var metadata resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "iceberg"}, &metadata)
+
+		t.Run(metadata.TypeName, func(t *testing.T) {
+			configurable, ok := r.(resource.ResourceWithConfigure)
+			require.True(t, ok)
+			var configured resource.ConfigureResponse
+			configurable.Configure(ctx, resource.ConfigureRequest{ProviderData: p}, &configured)
+			require.False(t, configured.Diagnostics.HasError(), "%v", configured.Diagnostics)
+
+			var create resource.CreateResponse
+			r.Create(ctx, resource.CreateRequest{}, &create)
+			var update resource.UpdateResponse
+			r.Update(ctx, resource.UpdateRequest{}, &update)
+			var del resource.DeleteResponse
+			r.Delete(ctx, resource.DeleteRequest{}, &del)
+
+			for op, diags := range map[string]diag.Diagnostics{"create": create.Diagnostics, "update": update.Diagnostics, "delete": del.Diagnostics} {
+				require.Len(t, diags.Errors(), 1, op)
+				assert.Equal(t, "Provider is read-only", diags.Errors()[0].Summary(), op)
+				assert.Contains(t, diags.Errors()[0].Detail(), fmt.Sprintf("Cannot %s %s", op, metadata.TypeName))
+			}
+		})
+	}
+
+	assert.Empty(t, m.writes)
+}
+
+func TestReadOnlyCatalogTypes(t *testing.T) {
+	ctx := context.Background()
+	for name, attributes := range map[string]map[string]tftypes.Value{
+		"glue": {
+			"type":                   tftypes.NewValue(tftypes.String, "glue"),
+			"glue_region":            tftypes.NewValue(tftypes.String, "eu-west-1"),
+			"glue_access_key_id":     tftypes.NewValue(tftypes.String, "AKID"),
+			"glue_secret_access_key": tftypes.NewValue(tftypes.String, "SECRET"),
+		},
+		"sql": {
+			"type":    tftypes.NewValue(tftypes.String, "sql"),
+			"sql_dsn": tftypes.NewValue(tftypes.String, "sqlite:"+filepath.Join(t.TempDir(), "catalog.db")),
+		},
+		"memory": {
+			"type": tftypes.NewValue(tftypes.String, "memory"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			attributes["read_only"] = tftypes.NewValue(tftypes.Bool, true)
+			p, diags := configureTestProvider(t, attributes)
+			require.False(t, diags.HasError(), "%v", diags)
+
+			r := &icebergNamespaceResource{provider: p}
+			var create resource.CreateResponse
+			r.Create(ctx, resource.CreateRequest{}, &create)
+			require.Len(t, create.Diagnostics.Errors(), 1)
+			assert.Equal(t, "Provider is read-only", create.Diagnostics.Errors()[0].Summary())
+		})
+	}
+}
+
+func TestReadOnlyTransport(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", map[string]string{"owner": "data"})
+	p := newReadOnlyTestProvider(t, m)
+
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+
+	tbl, err := cat.LoadTable(ctx, catalog.ToIdentifier("db", "events"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", tbl.Properties()["owner"])
+
+	err = cat.CreateNamespace(ctx, catalog.ToIdentifier("new_db"), nil)
+	require.ErrorIs(t, err, errReadOnly)
+	err = cat.DropTable(ctx, catalog.ToIdentifier("db", "events"))
+	require.ErrorIs(t, err, errReadOnly)
+
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+	err = client.DeletePrincipal(ctx, "someone")
+	require.ErrorIs(t, err, errReadOnly)
+
+	assert.Empty(t, m.writes)
+}
+
+func TestAccReadOnlyProvider(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	server := m.start(t)
+
+	providerCfg := fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+  read_only   = true
+}
+`, server.URL)
+
+	testresource.Test(t, testresource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []testresource.TestStep{
+			{
+				Config: providerCfg + `
+data "iceberg_tables" "db" {
+  namespace = ["db"]
+}
+`,
+				Check: testresource.TestCheckResourceAttr("data.iceberg_tables.db", "tables.0.id", "db.events"),
+			},
+			{
+				Config: providerCfg + `
+resource "iceberg_namespace" "new" {
+  name = ["new_db"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Provider is read-only`),
+			},
+		},
+	})
+
+	assert.Empty(t, m.writes)
+}
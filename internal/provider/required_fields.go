@@ -0,0 +1,123 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// totalRecordsProperty is the snapshot summary property with the number of
+// rows in the table.
+const totalRecordsProperty = "total-records"
+
+// tableRowCount returns the number of rows in the current snapshot of tbl as
+// reported by its summary. A table without a snapshot has no rows. ok is
+// false if the summary doesn't report the count.
+func tableRowCount(tbl *table.Table) (rows int64, ok bool) {
+	snapshot := tbl.CurrentSnapshot()
+	if snapshot == nil {
+		return 0, true
+	}
+	if snapshot.Summary == nil {
+		return 0, false
+	}
+
+	rows, err := strconv.ParseInt(snapshot.Summary.Properties[totalRecordsProperty], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return rows, true
+}
+
+// addedRequiredFields returns the full names of the required fields in plan
+// that have no initial default and aren't in prior. Fields are matched by ID,
+// or by name if they have none. The children of an added field are covered
+// by the field itself and not reported.
+func addedRequiredFields(plan, prior *iceberg.Schema) []string {
+	var added []string
+
+	var walk func(prefix string, fields []iceberg.NestedField)
+	walk = func(prefix string, fields []iceberg.NestedField) {
+		for _, field := range fields {
+			name := prefix + field.Name
+
+			var exists bool
+			if field.ID != 0 {
+				_, exists = prior.FindFieldByID(field.ID)
+			} else {
+				_, exists = prior.FindFieldByName(name)
+			}
+
+			if !exists {
+				if field.Required && field.InitialDefault == nil {
+					added = append(added, name)
+				}
+
+				continue
+			}
+			if nested, ok := field.Type.(*iceberg.StructType); ok {
+				walk(name+".", nested.FieldList)
+			}
+		}
+	}
+	walk("", plan.Fields())
+
+	return added
+}
+
+// checkRequiredFieldAdditions fails the plan when it adds required fields
+// without an initial default to a table with rows, which have no value for
+// them. With force set, it only warns.
+func checkRequiredFieldAdditions(plan, prior *iceberg.Schema, tbl *table.Table, force bool, diags *diag.Diagnostics) {
+	added := addedRequiredFields(plan, prior)
+	if len(added) == 0 {
+		return
+	}
+
+	rows, ok := tableRowCount(tbl)
+	if ok && rows == 0 {
+		return
+	}
+
+	tableName := encodeIdentifier(tbl.Identifier())
+	contents := fmt.Sprintf("Table %s has %d rows", tableName, rows)
+	if !ok {
+		contents = fmt.Sprintf("Table %s has a current snapshot that doesn't report its row count and may contain rows", tableName)
+	}
+	fields := strings.Join(added, ", ")
+
+	if force {
+		diags.AddWarning(
+			"Adding required fields to a table with rows",
+			fmt.Sprintf("%s, which have no value for the required fields %s. Readers may fail on those rows.", contents, fields),
+		)
+
+		return
+	}
+
+	diags.AddError(
+		"Required fields added to a table with rows",
+		fmt.Sprintf("%s, which have no value for the required fields %s. Set initial_default on the fields, make them optional, "+
+			"or set force_required_add = true on the table to add them anyway.", contents, fields),
+	)
+}
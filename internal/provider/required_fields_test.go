@@ -0,0 +1,163 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tableWithSnapshot returns a mock table whose current snapshot has the given
+// summary properties, or no snapshot if summary is nil.
+func tableWithSnapshot(t *testing.T, summary iceberg.Properties) *table.Table {
+	t.Helper()
+
+	metadata, err := mockTableMetadata("db", "events", nil)
+	require.NoError(t, err)
+
+	if summary != nil {
+		builder, err := table.MetadataBuilderFromBase(metadata, "")
+		require.NoError(t, err)
+		require.NoError(t, builder.AddSnapshot(&table.Snapshot{
+			SnapshotID:     1,
+			SequenceNumber: 1,
+			TimestampMs:    time.Now().UnixMilli(),
+			ManifestList:   metadata.Location() + "/metadata/snap-1.avro",
+			Summary:        &table.Summary{Operation: table.OpAppend, Properties: summary},
+		}))
+		require.NoError(t, builder.SetSnapshotRef(table.MainBranch, 1, table.BranchRef))
+		metadata, err = builder.Build()
+		require.NoError(t, err)
+	}
+
+	return table.New(table.Identifier{"db", "events"}, metadata, metadata.Location()+"/metadata/00000.metadata.json", nil, nil)
+}
+
+func TestTableRowCount(t *testing.T) {
+	rows, ok := tableRowCount(tableWithSnapshot(t, nil))
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), rows)
+
+	rows, ok = tableRowCount(tableWithSnapshot(t, iceberg.Properties{"total-records": "42"}))
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), rows)
+
+	_, ok = tableRowCount(tableWithSnapshot(t, iceberg.Properties{}))
+	assert.False(t, ok)
+}
+
+func TestAddedRequiredFields(t *testing.T) {
+	prior := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "address", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 3, Name: "city", Type: iceberg.PrimitiveTypes.String},
+		}}},
+	)
+	plan := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "address", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 3, Name: "city", Type: iceberg.PrimitiveTypes.String},
+			{Name: "zip", Type: iceberg.PrimitiveTypes.String, Required: true},
+		}}},
+		iceberg.NestedField{Name: "country", Type: iceberg.PrimitiveTypes.String, Required: true},
+		iceberg.NestedField{Name: "region", Type: iceberg.PrimitiveTypes.String, Required: true, InitialDefault: "unknown"},
+		iceberg.NestedField{Name: "comment", Type: iceberg.PrimitiveTypes.String},
+		iceberg.NestedField{Name: "geo", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{Name: "lat", Type: iceberg.PrimitiveTypes.Float64, Required: true},
+		}}},
+	)
+
+	assert.Equal(t, []string{"address.zip", "country"}, addedRequiredFields(plan, prior))
+	assert.Empty(t, addedRequiredFields(prior, prior))
+}
+
+func TestCheckRequiredFieldAdditions(t *testing.T) {
+	prior := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	plan := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{Name: "country", Type: iceberg.PrimitiveTypes.String, Required: true},
+	)
+
+	tests := []struct {
+		name        string
+		summary     iceberg.Properties
+		force       bool
+		wantError   string
+		wantWarning bool
+	}{
+		{name: "no snapshot"},
+		{name: "empty table", summary: iceberg.Properties{"total-records": "0"}},
+		{name: "table with rows", summary: iceberg.Properties{"total-records": "42"}, wantError: "Table db.events has 42 rows, which have no value for the required fields country."},
+		{name: "row count unknown", summary: iceberg.Properties{}, wantError: "doesn't report its row count"},
+		{name: "forced", summary: iceberg.Properties{"total-records": "42"}, force: true, wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			checkRequiredFieldAdditions(plan, prior, tableWithSnapshot(t, tt.summary), tt.force, &diags)
+
+			if tt.wantError == "" {
+				assert.False(t, diags.HasError(), "%v", diags)
+			} else {
+				require.Len(t, diags.Errors(), 1)
+				assert.Equal(t, "Required fields added to a table with rows", diags.Errors()[0].Summary())
+				assert.Contains(t, diags.Errors()[0].Detail(), tt.wantError)
+				assert.Contains(t, diags.Errors()[0].Detail(), "force_required_add")
+			}
+			assert.Equal(t, tt.wantWarning, len(diags.Warnings()) == 1)
+		})
+	}
+}
+
+func TestInitialDefaultJSON(t *testing.T) {
+	tests := []struct {
+		typeStr string
+		value   string
+		want    string
+	}{
+		{"boolean", "true", `true`},
+		{"int", "0", `0`},
+		{"long", "-12", `-12`},
+		{"double", "1.5", `1.5`},
+		{"string", "unknown", `"unknown"`},
+		{"date", "2024-01-01", `"2024-01-01"`},
+		{"decimal(10,2)", "1.50", `"1.50"`},
+	}
+	for _, tt := range tests {
+		got, err := initialDefaultJSON(tt.typeStr, tt.value)
+		require.NoError(t, err, tt.typeStr)
+		assert.JSONEq(t, tt.want, string(got), tt.typeStr)
+	}
+
+	for typeStr, value := range map[string]string{"boolean": "yes", "int": "1.5", "double": "NaN", "struct": "{}"} {
+		_, err := initialDefaultJSON(typeStr, value)
+		assert.Error(t, err, typeStr)
+	}
+
+	// The default survives the round trip through the catalog's field JSON.
+	var field icebergTableSchemaField
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"name":"n","type":"int","required":true,"initial-default":7}`), &field))
+	require.NotNil(t, field.InitialDefault)
+	assert.Equal(t, "7", *field.InitialDefault)
+}
@@ -18,10 +18,12 @@ package provider
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
-	"github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/apache/iceberg-terraform/internal/properties"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -33,17 +35,36 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var _ resource.Resource = &icebergNamespaceResource{}
+var (
+	_ resource.Resource                   = &icebergNamespaceResource{}
+	_ resource.ResourceWithModifyPlan     = &icebergNamespaceResource{}
+	_ resource.ResourceWithUpgradeState   = &icebergNamespaceResource{}
+	_ resource.ResourceWithValidateConfig = &icebergNamespaceResource{}
+)
 
 func NewNamespaceResource() resource.Resource {
 	return &icebergNamespaceResource{}
 }
 
 type icebergNamespaceResourceModel struct {
-	ID               types.String `tfsdk:"id"`
-	Name             types.List   `tfsdk:"name"`
-	UserProperties   types.Map    `tfsdk:"user_properties"`
-	ServerProperties types.Map    `tfsdk:"server_properties"`
+	ID                          types.String `tfsdk:"id"`
+	Name                        types.List   `tfsdk:"name"`
+	Comment                     types.String `tfsdk:"comment"`
+	Owner                       types.String `tfsdk:"owner"`
+	CaseInsensitivePropertyKeys types.Bool   `tfsdk:"case_insensitive_property_keys"`
+	UserProperties              types.Map    `tfsdk:"user_properties"`
+	ServerProperties            types.Map    `tfsdk:"server_properties"`
+	ServerManagedProperties     types.Map    `tfsdk:"server_managed_properties"`
+	Properties                  types.Map    `tfsdk:"properties"`
+	TrackServerProperties       types.Bool   `tfsdk:"track_server_properties"`
+	CatalogName                 types.String `tfsdk:"catalog_name"`
+	Timeouts                    types.Object `tfsdk:"timeouts"`
+}
+
+// propertyKeys returns how the user_properties keys of m are matched with the
+// properties on the server.
+func (m *icebergNamespaceResourceModel) propertyKeys() properties.Keys {
+	return properties.Keys{IgnoreCase: m.CaseInsensitivePropertyKeys.ValueBool()}
 }
 
 type icebergNamespaceResource struct {
@@ -57,10 +78,12 @@ func (r *icebergNamespaceResource) Metadata(_ context.Context, req resource.Meta
 
 func (r *icebergNamespaceResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     1,
 		Description: "A resource for managing Iceberg namespaces.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				Computed: true,
+				Description: "The name of the namespace in the catalog, with its levels joined by '.', including the name_prefix of the provider.",
+				Computed:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -73,6 +96,18 @@ func (r *icebergNamespaceResource) Schema(_ context.Context, _ resource.SchemaRe
 					listplanmodifier.RequiresReplace(),
 				},
 			},
+			"comment": schema.StringAttribute{
+				Description: "The description of the namespace, stored in its comment property. Conflicts with a comment key in user_properties. If unset, a comment written by other clients is left alone.",
+				Optional:    true,
+			},
+			"owner": schema.StringAttribute{
+				Description: "The owner of the namespace, stored in its owner property. Conflicts with an owner key in user_properties. If unset, an owner written by other clients is left alone. With type = 'polaris', planning an owner that isn't a Polaris principal emits a warning unless skip_owner_validation is set.",
+				Optional:    true,
+			},
+			"case_insensitive_property_keys": schema.BoolAttribute{
+				Description: "If true, user_properties keys match properties on the server regardless of case, for catalogs such as Glue that lowercase property keys. The keys keep the casing of the configuration in state, so plans don't show them being removed and added again. Defaults to false.",
+				Optional:    true,
+			},
 			"user_properties": schema.MapAttribute{
 				Description: "User-defined properties for the namespace. Only properties listed in Terraform will be changed. All others on the server will stay the same",
 				Optional:    true,
@@ -83,10 +118,59 @@ func (r *icebergNamespaceResource) Schema(_ context.Context, _ resource.SchemaRe
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+			"server_managed_properties": schema.MapAttribute{
+				Description: "Properties set on the namespace by the server or other clients, i.e. server_properties without the keys managed through user_properties.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"properties": schema.MapAttribute{
+				Description: "The properties of the namespace on the server, read again when planning, so lifecycle preconditions of other resources check them as they are at plan time, even with -refresh=false. Unknown while the plan creates the namespace or changes its properties.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"track_server_properties": schema.BoolAttribute{
+				Description: "If false, server_properties, server_managed_properties and properties aren't stored and are null, which keeps properties that other clients change often out of plans and state. The properties are still read for user_properties. Setting it back to true fills them in again on apply. Defaults to true.",
+				Optional:    true,
+			},
+			"catalog_name": schema.StringAttribute{
+				Description: catalogNameDescription,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": deleteTimeoutBlock("How long deleting the namespace retries while the catalog reports that it isn't empty, as a duration such as 5m. Tables destroyed in the same run may still be being dropped by slow catalogs. Defaults to 2m."),
+		},
+	}
+}
+
+func (r *icebergNamespaceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var current resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &current)
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   priorSchemaWithout(current.Schema, 0, "server_managed_properties", "comment", "owner", "case_insensitive_property_keys", "catalog_name", "track_server_properties", "properties"),
+			StateUpgrader: upgradeServerManagedProperties,
 		},
 	}
 }
 
+func (r *icebergNamespaceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data icebergNamespaceResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateCommentConfig(data.Comment, data.UserProperties, &resp.Diagnostics)
+	validateOwnerConfig(data.Owner, data.UserProperties, &resp.Diagnostics)
+	validatePropertyKeyCollisions(data.propertyKeys(), data.UserProperties, &resp.Diagnostics)
+	validateTimeoutsConfig(ctx, data.Timeouts, &resp.Diagnostics)
+}
+
 func (r *icebergNamespaceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -119,13 +203,13 @@ func (r *icebergNamespaceResource) ConfigureCatalog(ctx context.Context, diags *
 		return
 	}
 
-	if r.provider.catalogURI == "" {
+	if !r.provider.catalogConfigured() {
 		// The provider might not be fully configured yet (e.g. during plan if URI is unknown)
 
 		return
 	}
 
-	catalog, err := r.provider.NewCatalog(ctx)
+	catalog, err := r.provider.NewCatalog(ctx, "iceberg_namespace")
 	if err != nil {
 		diags.AddError(
 			"Failed to create catalog",
@@ -138,6 +222,11 @@ func (r *icebergNamespaceResource) ConfigureCatalog(ctx context.Context, diags *
 }
 
 func (r *icebergNamespaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !r.provider.checkWritable("iceberg_namespace", "create", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_namespace")
 	r.ConfigureCatalog(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -159,7 +248,7 @@ func (r *icebergNamespaceResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	namespaceIdent := catalog.ToIdentifier(namespaceName...)
+	namespaceIdent := r.provider.prefixNamespace(catalog.ToIdentifier(namespaceName...))
 
 	userProperties := make(map[string]string)
 	if !data.UserProperties.IsNull() {
@@ -170,14 +259,15 @@ func (r *icebergNamespaceResource) Create(ctx context.Context, req resource.Crea
 		}
 	}
 
-	err := r.catalog.CreateNamespace(ctx, namespaceIdent, userProperties)
+	intended := withOwner(withComment(userProperties, data.Comment), data.Owner)
+	err := createNamespace(ctx, r.catalog, namespaceIdent, intended)
 	if err != nil {
 		resp.Diagnostics.AddError("failed to create namespace", err.Error())
 
 		return
 	}
 
-	data.ID = types.StringValue(strings.Join(namespaceIdent, "."))
+	data.ID = types.StringValue(encodeIdentifier(namespaceIdent))
 
 	nsProps, err := r.catalog.LoadNamespaceProperties(ctx, namespaceIdent)
 	if err != nil {
@@ -196,12 +286,7 @@ func (r *icebergNamespaceResource) Create(ctx context.Context, req resource.Crea
 
 	// Update UserProperties to match what we sent/expected, but values confirmed from server
 	// We only keep keys that were in the original plan (User managed)
-	managedProps := make(map[string]string)
-	for k := range userProperties {
-		if v, ok := nsProps[k]; ok {
-			managedProps[k] = v
-		}
-	}
+	managedProps := data.propertyKeys().Reconcile(userProperties, nsProps)
 	// If the user didn't set any properties, UserProperties should be null or empty based on input.
 	// However, if we sent it, we expect it back.
 	if !data.UserProperties.IsNull() {
@@ -209,11 +294,24 @@ func (r *icebergNamespaceResource) Create(ctx context.Context, req resource.Crea
 		resp.Diagnostics.Append(diags...)
 	}
 
+	data.Comment = syncComment(data.Comment, nsProps)
+	data.Owner = syncOwner(data.Owner, nsProps)
+	data.ServerManagedProperties, diags = types.MapValueFrom(ctx, types.StringType, data.propertyKeys().ServerManaged(withOwner(withComment(userProperties, data.Comment), data.Owner), nsProps))
+	resp.Diagnostics.Append(diags...)
+	data.Properties = loadedFullProperties
+	data.CatalogName = r.provider.catalogNameValue()
+	data.clearUntracked()
+
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, namespaceIdent, data.propertyKeys(), intended, &resp.Diagnostics)
+	}
 }
 
 func (r *icebergNamespaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withResourceType(ctx, "iceberg_namespace")
 	r.ConfigureCatalog(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -236,11 +334,12 @@ func (r *icebergNamespaceResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	namespaceIdent := catalog.ToIdentifier(namespaceName...)
+	namespaceIdent := identifierFromID(data.ID, catalog.ToIdentifier(namespaceName...))
 
+	ctx, nulls := withNullProperties(ctx)
 	nsProps, err := r.catalog.LoadNamespaceProperties(ctx, namespaceIdent)
 	if err != nil {
-		if errors.Is(err, catalog.ErrNoSuchNamespace) {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 
 			return
@@ -249,6 +348,7 @@ func (r *icebergNamespaceResource) Read(ctx context.Context, req resource.ReadRe
 
 		return
 	}
+	nulls.addTo("namespace "+strings.Join(namespaceIdent, "."), &resp.Diagnostics)
 
 	// ServerProperties gets everything
 	fullProperties, diags := types.MapValueFrom(ctx, types.StringType, nsProps)
@@ -259,31 +359,48 @@ func (r *icebergNamespaceResource) Read(ctx context.Context, req resource.ReadRe
 	data.ServerProperties = fullProperties
 
 	// UserProperties only updates keys that are already tracked in the state
+	stateProperties := make(map[string]string)
 	if !data.UserProperties.IsNull() {
-		stateProperties := make(map[string]string)
 		diags = data.UserProperties.ElementsAs(ctx, &stateProperties, false)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
 
-		managedProps := make(map[string]string)
-		for k := range stateProperties {
-			if v, ok := nsProps[k]; ok {
-				managedProps[k] = v
-			}
-			// If key is missing in nsProps, it was removed from server, so we drop it from managedProps
-			// which effectively sets it to null/removed in the new state, matching reality.
-		}
+		// If a key is missing in nsProps, it was removed from the server, so it is dropped
+		// which effectively sets it to null/removed in the new state, matching reality.
+		managedProps := data.propertyKeys().Reconcile(stateProperties, nsProps)
 		data.UserProperties, diags = types.MapValueFrom(ctx, types.StringType, managedProps)
 		resp.Diagnostics.Append(diags...)
 	}
 
+	managed := withOwner(withComment(stateProperties, data.Comment), data.Owner)
+	if r.provider.warnOnDrift && (!data.UserProperties.IsNull() || !data.Comment.IsNull() || !data.Owner.IsNull()) {
+		report := newDriftReport("Namespace " + encodeIdentifier(namespaceIdent))
+		// Compared under the managed keys, which may differ in case from
+		// the server's.
+		report.compareProperties(managed, data.propertyKeys().Reconcile(managed, nsProps))
+		report.addTo(&resp.Diagnostics)
+	}
+
+	data.Comment = syncComment(data.Comment, nsProps)
+	data.Owner = syncOwner(data.Owner, nsProps)
+	data.ServerManagedProperties, diags = types.MapValueFrom(ctx, types.StringType, data.propertyKeys().ServerManaged(managed, nsProps))
+	resp.Diagnostics.Append(diags...)
+	data.Properties = fullProperties
+	data.CatalogName = r.provider.catalogNameValue()
+	data.clearUntracked()
+
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 }
 
 func (r *icebergNamespaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !r.provider.checkWritable("iceberg_namespace", "update", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_namespace")
 	r.ConfigureCatalog(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -301,9 +418,6 @@ func (r *icebergNamespaceResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	updates := make(iceberg.Properties)
-	removals := make([]string, 0)
-
 	// Get current state properties
 	stateProps := make(map[string]string)
 	if !state.UserProperties.IsNull() {
@@ -318,77 +432,310 @@ func (r *icebergNamespaceResource) Update(ctx context.Context, req resource.Upda
 		resp.Diagnostics.Append(diags...)
 	}
 
+	var namespaceName []string
+	diags = plan.Name.ElementsAs(ctx, &namespaceName, false)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Calculate updates: keys in plan that differ from state
-	for k, v := range planProps {
-		if oldV, ok := stateProps[k]; !ok || oldV != v {
-			updates[k] = v
-		}
+	namespaceIdent := identifierFromID(state.ID, catalog.ToIdentifier(namespaceName...))
+
+	// Compare against what the server has right now rather than the prior state, so
+	// values the server already holds (or normalized) don't produce redundant calls.
+	nsProps, err := r.catalog.LoadNamespaceProperties(ctx, namespaceIdent)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to read namespace properties", err.Error())
+
+		return
 	}
 
-	// Calculate removals: keys in state that are NOT in plan
-	for k := range stateProps {
-		if _, ok := planProps[k]; !ok {
-			removals = append(removals, k)
+	intended := withOwner(withComment(planProps, plan.Comment), plan.Owner)
+	delta := plan.propertyKeys().Delta(intended, withOwner(withComment(stateProps, state.Comment), state.Owner), properties.Known(nsProps))
+	if !delta.IsEmpty() {
+		r.applyPropertyDelta(ctx, namespaceIdent, delta, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		nsProps, err = r.catalog.LoadNamespaceProperties(ctx, namespaceIdent)
+		if err != nil {
+			resp.Diagnostics.AddError("failed to read namespace properties", err.Error())
+
+			return
 		}
 	}
 
-	if len(updates) == 0 && len(removals) == 0 {
+	// Update ServerProperties
+	loadedFullProperties, diags := types.MapValueFrom(ctx, types.StringType, nsProps)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	plan.ServerProperties = loadedFullProperties
 
-	var namespaceName []string
-	diags = plan.Name.ElementsAs(ctx, &namespaceName, false)
+	// Update UserProperties to match reality for tracked keys
+	// We reconstruct plan.UserProperties to ensure it reflects what's actually on the server for the keys we care about
+	managedProps := plan.propertyKeys().Reconcile(planProps, nsProps)
+	if !plan.UserProperties.IsNull() {
+		plan.UserProperties, diags = types.MapValueFrom(ctx, types.StringType, managedProps)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	plan.Comment = syncComment(plan.Comment, nsProps)
+	plan.Owner = syncOwner(plan.Owner, nsProps)
+	plan.ServerManagedProperties, diags = types.MapValueFrom(ctx, types.StringType, plan.propertyKeys().ServerManaged(withOwner(withComment(planProps, plan.Comment), plan.Owner), nsProps))
 	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+	// Properties read when planning are kept, as planned.
+	if plan.Properties.IsUnknown() {
+		plan.Properties = loadedFullProperties
+	}
+	plan.CatalogName = r.provider.catalogNameValue()
+	plan.clearUntracked()
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, namespaceIdent, plan.propertyKeys(), intended, &resp.Diagnostics)
+	}
+}
+
+// verifyApplied reads the namespace back and compares the properties it
+// manages with intended, see apply_verification.go.
+func (r *icebergNamespaceResource) verifyApplied(ctx context.Context, namespaceIdent table.Identifier, keys properties.Keys, intended map[string]string, diags *diag.Diagnostics) {
+	nsProps, err := r.catalog.LoadNamespaceProperties(ctx, namespaceIdent)
+	if err != nil {
+		diags.AddError("failed to read namespace back after the apply", err.Error())
+
 		return
 	}
 
-	namespaceIdent := catalog.ToIdentifier(namespaceName...)
+	report := newDriftReport("Namespace " + encodeIdentifier(namespaceIdent))
+	// Compared under the managed keys, which may differ in case from the
+	// server's.
+	report.compareProperties(intended, keys.Reconcile(intended, nsProps))
+	report.addMismatchTo(diags)
+}
+
+// createNamespace creates the namespace, retrying commit conflicts. A
+// namespace that turns out to exist after a conflict is taken to be created
+// by an earlier attempt if it has the given properties.
+func createNamespace(ctx context.Context, cat catalog.Catalog, namespaceIdent table.Identifier, props map[string]string) error {
+	return createWithRetry(ctx, "namespace "+encodeIdentifier(namespaceIdent), false,
+		func() error {
+			return cat.CreateNamespace(ctx, namespaceIdent, props)
+		},
+		func() (bool, error) {
+			serverProps, err := cat.LoadNamespaceProperties(ctx, namespaceIdent)
+			if err != nil {
+				return false, err
+			}
+			for k, v := range props {
+				if serverProps[k] != v {
+					return false, nil
+				}
+			}
 
-	_, err := r.catalog.UpdateNamespaceProperties(ctx, namespaceIdent, removals, updates)
+			return true, nil
+		},
+	)
+}
+
+func (r *icebergNamespaceResource) applyPropertyDelta(ctx context.Context, namespaceIdent table.Identifier, delta properties.Delta, diags *diag.Diagnostics) {
+	applyNamespacePropertyDelta(ctx, r.catalog, r.provider, namespaceIdent, delta, diags)
+}
+
+// applyNamespacePropertyDelta sends delta to the catalog. Catalogs that accept
+// property updates but not removals still get the updates; the removed keys
+// are left on the server and reported in a warning. Removals the catalog
+// accepted are checked to have taken effect, see checkPropertyRemovals.
+func applyNamespacePropertyDelta(ctx context.Context, cat catalog.Catalog, p *icebergProvider, namespaceIdent table.Identifier, delta properties.Delta, diags *diag.Diagnostics) {
+	caps := p.capabilities
+	if !caps.check(featureNamespaceProperties, diags) {
+		return
+	}
+
+	removals := delta.Removals
+	if len(removals) > 0 && !caps.supports(featureNamespacePropertyRemoval) {
+		addSkippedRemovalsWarning(removals, diags)
+		removals = nil
+	}
+	if len(removals) == 0 && len(delta.Updates) == 0 {
+		return
+	}
+
+	_, err := cat.UpdateNamespaceProperties(ctx, namespaceIdent, removals, delta.Updates)
+	if isUnsupportedOperationError(err) && len(removals) > 0 {
+		// Find out whether only the removals are unsupported.
+		caps.markUnsupported(featureNamespacePropertyRemoval)
+		addSkippedRemovalsWarning(removals, diags)
+		if len(delta.Updates) == 0 {
+			return
+		}
+		removals = nil
+		_, err = cat.UpdateNamespaceProperties(ctx, namespaceIdent, nil, delta.Updates)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("failed to update namespace properties", err.Error())
+		if isUnsupportedOperationError(err) {
+			caps.markUnsupported(featureNamespaceProperties)
+			caps.check(featureNamespaceProperties, diags)
 
+			return
+		}
+		diags.AddError("failed to update namespace properties", err.Error())
+
+		return
+	}
+	if len(removals) == 0 {
 		return
 	}
 
-	nsProps, err := r.catalog.LoadNamespaceProperties(ctx, namespaceIdent)
+	current, err := cat.LoadNamespaceProperties(ctx, namespaceIdent)
 	if err != nil {
-		resp.Diagnostics.AddError("failed to read namespace properties", err.Error())
+		diags.AddError("failed to read namespace properties", err.Error())
 
 		return
 	}
+	p.checkPropertyRemovals("namespace "+encodeIdentifier(namespaceIdent), properties.Delta{Removals: removals}, current, diags)
+}
 
-	// Update ServerProperties
-	loadedFullProperties, diags := types.MapValueFrom(ctx, types.StringType, nsProps)
-	resp.Diagnostics.Append(diags...)
+func addSkippedRemovalsWarning(removals []string, diags *diag.Diagnostics) {
+	diags.AddWarning(
+		"Namespace properties not removed",
+		"The configured catalog doesn't support removing namespace properties. "+
+			"These properties are no longer managed by Terraform but remain on the server: "+strings.Join(removals, ", "),
+	)
+}
+
+// ModifyPlan reads the properties of an existing namespace again, and
+// reports at plan time when a property change needs a catalog feature that
+// isn't available, and when the owner isn't a Polaris principal.
+func (r *icebergNamespaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan icebergNamespaceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	plan.ServerProperties = loadedFullProperties
+	planUntracked(ctx, plan.untrackedAttributes(), &resp.Plan, &resp.Diagnostics)
 
-	// Update UserProperties to match reality for tracked keys
-	// We reconstruct plan.UserProperties to ensure it reflects what's actually on the server for the keys we care about
-	managedProps := make(map[string]string)
-	for k := range planProps {
-		if v, ok := nsProps[k]; ok {
-			managedProps[k] = v
+	// The name of a new namespace can hold values of resources that aren't
+	// created yet, such as the output of another resource. The ID is then
+	// left unknown rather than built from the known levels alone.
+	nameKnown := fullyKnown(ctx, plan.Name)
+	var planned table.Identifier
+	if nameKnown {
+		var namespaceName []string
+		resp.Diagnostics.Append(plan.Name.ElementsAs(ctx, &namespaceName, false)...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
+		planned = r.provider.prefixNamespace(catalog.ToIdentifier(namespaceName...))
+	}
+
+	if req.State.Raw.IsNull() {
+		if nameKnown {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), encodeIdentifier(planned))...)
+		}
+		r.provider.checkOwnerPrincipal(ctx, "iceberg_namespace", plan.Owner, types.StringNull(), &resp.Diagnostics)
+
+		return
+	}
+
+	var state icebergNamespaceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if nameKnown && plan.Name.Equal(state.Name) {
+		checkNamePrefix("namespace", state.ID, planned, &resp.Diagnostics)
+	}
+	r.provider.checkOwnerPrincipal(ctx, "iceberg_namespace", plan.Owner, state.Owner, &resp.Diagnostics)
+
+	propertiesChanged := !plan.UserProperties.Equal(state.UserProperties) || !plan.Comment.Equal(state.Comment) || !plan.Owner.Equal(state.Owner)
+	if nameKnown && plan.Name.Equal(state.Name) && !propertiesChanged && tracked(plan.TrackServerProperties) {
+		r.planProperties(ctx, identifierFromID(state.ID, planned), &state, resp)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !fullyKnown(ctx, plan.UserProperties, plan.Comment, plan.Owner) || !propertiesChanged {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_namespace")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() || r.catalog == nil {
+		return
+	}
+
+	caps := r.provider.capabilities
+	if !caps.check(featureNamespaceProperties, &resp.Diagnostics) {
+		return
+	}
+
+	stateProps := make(map[string]string)
+	planProps := make(map[string]string)
+	if !state.UserProperties.IsNull() {
+		resp.Diagnostics.Append(state.UserProperties.ElementsAs(ctx, &stateProps, false)...)
 	}
 	if !plan.UserProperties.IsNull() {
-		plan.UserProperties, diags = types.MapValueFrom(ctx, types.StringType, managedProps)
-		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(plan.UserProperties.ElementsAs(ctx, &planProps, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	diags = resp.State.Set(ctx, &plan)
+	if removals := plan.propertyKeys().Delta(withOwner(withComment(planProps, plan.Comment), plan.Owner), withOwner(withComment(stateProps, state.Comment), state.Owner), nil).Removals; len(removals) > 0 && !caps.supports(featureNamespacePropertyRemoval) {
+		addSkippedRemovalsWarning(removals, &resp.Diagnostics)
+	}
+}
+
+// planProperties plans the properties of the namespace as they are on the
+// server now, for lifecycle preconditions that check them. If they changed
+// since the state was read, server_properties and server_managed_properties
+// are read again on apply.
+func (r *icebergNamespaceResource) planProperties(ctx context.Context, namespaceIdent table.Identifier, state *icebergNamespaceResourceModel, resp *resource.ModifyPlanResponse) {
+	ctx = withResourceType(ctx, "iceberg_namespace")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() || r.catalog == nil {
+		return
+	}
+
+	nsProps, err := r.catalog.LoadNamespaceProperties(ctx, namespaceIdent)
+	if err != nil {
+		if isNotFoundError(err) {
+			return
+		}
+		resp.Diagnostics.AddError("failed to read namespace properties", err.Error())
+
+		return
+	}
+
+	current, diags := types.MapValueFrom(ctx, types.StringType, nsProps)
 	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("properties"), current)...)
+	if current.Equal(state.Properties) {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("server_properties"), types.MapUnknown(types.StringType))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("server_managed_properties"), types.MapUnknown(types.StringType))...)
 }
 
 func (r *icebergNamespaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !r.provider.checkWritable("iceberg_namespace", "delete", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_namespace")
 	r.ConfigureCatalog(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -410,15 +757,27 @@ func (r *icebergNamespaceResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
-	namespaceIdent := catalog.ToIdentifier(namespaceName...)
+	namespaceIdent := identifierFromID(data.ID, catalog.ToIdentifier(namespaceName...))
+
+	timeout := deleteTimeout(ctx, data.Timeouts, defaultNamespaceDeleteTimeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	err := r.catalog.DropNamespace(ctx, namespaceIdent)
+	err := dropNamespaceWhenEmpty(ctx, r.catalog, namespaceIdent, timeout)
 	if err != nil {
-		if errors.Is(err, catalog.ErrNoSuchNamespace) {
+		if isNotFoundError(err) {
 			// If the namespace is already gone, we don't need to do anything.
 
 			return
 		}
+		if errors.Is(err, errNamespaceStillNotEmpty) {
+			resp.Diagnostics.AddError("failed to drop namespace",
+				fmt.Sprintf("Failed to drop namespace %s: %s. Drop the tables and views Terraform doesn't manage first, or increase timeouts.delete if the catalog takes longer to drop tables.",
+					encodeIdentifier(namespaceIdent), err))
+
+			return
+		}
 		resp.Diagnostics.AddError("failed to drop namespace", err.Error())
 
 		return
@@ -426,18 +785,29 @@ func (r *icebergNamespaceResource) Delete(ctx context.Context, req resource.Dele
 }
 
 func (r *icebergNamespaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	nameParts, err := decodeImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"The import ID should be the dot-separated namespace name, with dots inside a part escaped as \\.: "+err.Error(),
+		)
 
-	// FieldsFunc is used to split by dot and filter out empty segments (e.g. "a..b" -> ["a", "b"])
-	nameParts := strings.FieldsFunc(req.ID, func(r rune) bool {
-		return r == '.'
-	})
+		return
+	}
+
+	name, err := r.provider.unprefixNamespace(nameParts)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", "The import ID should be the namespace name in the catalog, including the name prefix: "+err.Error())
+
+		return
+	}
 
-	nameList, diags := types.ListValueFrom(ctx, types.StringType, nameParts)
+	nameList, diags := types.ListValueFrom(ctx, types.StringType, name)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), encodeIdentifier(nameParts))...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), nameList)...)
 }
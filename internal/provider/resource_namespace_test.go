@@ -16,13 +16,27 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -30,48 +44,237 @@ const (
 provider "iceberg" {
   catalog_uri = "%s"
 }
+`
+	memoryProviderConfig = `
+provider "iceberg" {
+  type      = "memory"
+  warehouse = "%s"
+}
 `
 )
 
+// testAccCatalogConfig returns the provider configuration of acceptance tests
+// that run against a real catalog: the REST catalog at ICEBERG_CATALOG_URI, or
+// a memory catalog of the provider process if it isn't set, so the tests
+// need no server.
+func testAccCatalogConfig(t *testing.T) string {
+	t.Helper()
+
+	if catalogURI := os.Getenv("ICEBERG_CATALOG_URI"); catalogURI != "" {
+		return fmt.Sprintf(providerConfig, catalogURI)
+	}
+
+	return fmt.Sprintf(memoryProviderConfig, testAccWarehouse(t))
+}
+
+// testAccWarehouses holds the warehouse of the memory catalog of every
+// acceptance test by its *testing.T.
+var testAccWarehouses sync.Map
+
+// testAccWarehouse returns the warehouse of the memory catalog of the
+// acceptance test t, a temporary directory of its own. The provider
+// instances Terraform starts for the commands of the test share the memory
+// catalog of the warehouse, and no other test sees it.
+func testAccWarehouse(t *testing.T) string {
+	t.Helper()
+
+	if warehouse, ok := testAccWarehouses.Load(t); ok {
+		return warehouse.(string)
+	}
+	warehouse := "file://" + filepath.ToSlash(t.TempDir())
+	testAccWarehouses.Store(t, warehouse)
+
+	return warehouse
+}
+
 func testAccPreCheck(t *testing.T) {
 }
 
-var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-	"iceberg": providerserver.NewProtocol6WithError(New()()),
+// testAccNamespace returns a namespace name of its own for the acceptance
+// test t, made of a tf_acc_ prefix, the test name and a random suffix, so
+// tests can run in parallel and against a shared catalog without colliding
+// with each other or with leftovers of earlier runs. The namespace is dropped
+// with all its tables and child namespaces when the test ends, even if it
+// failed before Terraform destroyed them.
+func testAccNamespace(t *testing.T) string {
+	t.Helper()
+
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+
+		return '_'
+	}, strings.ToLower(strings.TrimPrefix(t.Name(), "TestAcc")))
+	name = fmt.Sprintf("tf_acc_%s_%s", name, acctest.RandString(8))
+
+	// The warehouse is created before the cleanup is registered, so it is
+	// removed only after the namespace was dropped.
+	testAccWarehouse(t)
+	t.Cleanup(func() {
+		cat, err := testAccCatalog(t)
+		if err != nil {
+			t.Errorf("Failed to clean up the namespace %s: %v", name, err)
+
+			return
+		}
+		if err := testAccDropNamespace(context.Background(), cat, table.Identifier{name}); err != nil {
+			t.Errorf("Failed to clean up the namespace %s: %v", name, err)
+		}
+	})
+
+	return name
+}
+
+// testAccCatalog returns a client of the catalog of testAccCatalogConfig.
+func testAccCatalog(t *testing.T) (catalog.Catalog, error) {
+	t.Helper()
+
+	attributes := map[string]tftypes.Value{
+		"type":      tftypes.NewValue(tftypes.String, "memory"),
+		"warehouse": tftypes.NewValue(tftypes.String, testAccWarehouse(t)),
+	}
+	if catalogURI := os.Getenv("ICEBERG_CATALOG_URI"); catalogURI != "" {
+		attributes = map[string]tftypes.Value{"catalog_uri": tftypes.NewValue(tftypes.String, catalogURI)}
+	}
+	p, diags := configureTestProvider(t, attributes)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed to configure the provider: %v", diags)
+	}
+
+	return p.NewCatalog(context.Background(), "iceberg_namespace")
 }
 
-func TestAccIcebergNamespace(t *testing.T) {
-	catalogURI := os.Getenv("ICEBERG_CATALOG_URI")
-	if catalogURI == "" {
-		catalogURI = "http://localhost:8181"
+// testAccDropNamespace drops ident with its tables, purging them where the
+// catalog supports it, and its child namespaces. A namespace that doesn't
+// exist is ignored.
+func testAccDropNamespace(ctx context.Context, cat catalog.Catalog, ident table.Identifier) error {
+	children, err := cat.ListNamespaces(ctx, ident)
+	if errors.Is(err, catalog.ErrNoSuchNamespace) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		// SQL catalogs list the namespace itself and all namespaces whose
+		// name starts with its name as well.
+		if len(child) != len(ident)+1 || !slices.Equal(child[:len(ident)], ident) {
+			continue
+		}
+		if err := testAccDropNamespace(ctx, cat, child); err != nil {
+			return err
+		}
 	}
 
-	providerCfg := fmt.Sprintf(providerConfig, catalogURI)
+	var tables []table.Identifier
+	for tableIdent, err := range cat.ListTables(ctx, ident) {
+		if errors.Is(err, catalog.ErrNoSuchNamespace) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		tables = append(tables, tableIdent)
+	}
+	for _, tableIdent := range tables {
+		var err error
+		if purger, ok := cat.(tablePurger); ok {
+			err = purger.PurgeTable(ctx, tableIdent)
+		} else {
+			err = cat.DropTable(ctx, tableIdent)
+		}
+		if err != nil && !errors.Is(err, catalog.ErrNoSuchTable) {
+			return err
+		}
+	}
+
+	if err := cat.DropNamespace(ctx, ident); err != nil && !errors.Is(err, catalog.ErrNoSuchNamespace) {
+		return err
+	}
+
+	return nil
+}
+
+var testAccProtoV6ProviderFactories = testAccProtoV6ProviderFactoriesFor("iceberg")
+
+// testAccProtoV6ProviderFactoriesFor returns a factory for every name that
+// serves a new, independently configured instance of the provider. Aliases of
+// one provider name share its provider server, and with it the configuration
+// of whichever alias was configured last, so tests that need several catalogs
+// configure them under separate names, see TestAccProvider_MultipleCatalogs.
+func testAccProtoV6ProviderFactoriesFor(names ...string) map[string]func() (tfprotov6.ProviderServer, error) {
+	factories := make(map[string]func() (tfprotov6.ProviderServer, error), len(names))
+	for _, name := range names {
+		factories[name] = func() (tfprotov6.ProviderServer, error) {
+			return providerserver.NewProtocol6WithError(New("test")())()
+		}
+	}
+
+	return factories
+}
+
+func TestNamespaceCleanup(t *testing.T) {
+	t.Setenv("ICEBERG_CATALOG_URI", "")
+	ctx := context.Background()
+	cat, err := testAccCatalog(t)
+	require.NoError(t, err)
+
+	ns := table.Identifier{"tf_acc_cleanup"}
+	child := table.Identifier{"tf_acc_cleanup", "staging"}
+	require.NoError(t, cat.CreateNamespace(ctx, ns, nil))
+	require.NoError(t, cat.CreateNamespace(ctx, child, nil))
+	sibling := table.Identifier{"tf_acc_cleanup_other"}
+	require.NoError(t, cat.CreateNamespace(ctx, sibling, nil))
+	schema := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	for _, ident := range []table.Identifier{{"tf_acc_cleanup", "events"}, {"tf_acc_cleanup", "staging", "events"}} {
+		_, err := cat.CreateTable(ctx, ident, schema)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, testAccDropNamespace(ctx, cat, ns))
+	exists, err := cat.CheckNamespaceExists(ctx, ns)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = cat.CheckNamespaceExists(ctx, sibling)
+	require.NoError(t, err)
+	assert.True(t, exists, "namespaces that only share the prefix are kept")
+	require.NoError(t, cat.DropNamespace(ctx, sibling))
+
+	// Namespaces Terraform already destroyed are skipped.
+	require.NoError(t, testAccDropNamespace(ctx, cat, ns))
+}
+
+func TestAccIcebergNamespace(t *testing.T) {
+	t.Parallel()
+
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIcebergNamespaceResourceConfig(providerCfg, "test description"),
+				Config: testAccIcebergNamespaceResourceConfig(providerCfg, namespace, "test description"),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("iceberg_namespace.test", "name.0", "db1"),
+					resource.TestCheckResourceAttr("iceberg_namespace.test", "name.0", namespace),
 					resource.TestCheckResourceAttr("iceberg_namespace.test", "user_properties.description", "test description"),
 					resource.TestCheckResourceAttr("iceberg_namespace.test", "server_properties.description", "test description"),
 				),
 			},
 			{
-				Config: testAccIcebergNamespaceResourceConfig(providerCfg, "updated description"),
+				Config: testAccIcebergNamespaceResourceConfig(providerCfg, namespace, "updated description"),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("iceberg_namespace.test", "name.0", "db1"),
+					resource.TestCheckResourceAttr("iceberg_namespace.test", "name.0", namespace),
 					resource.TestCheckResourceAttr("iceberg_namespace.test", "user_properties.description", "updated description"),
 					resource.TestCheckResourceAttr("iceberg_namespace.test", "server_properties.description", "updated description"),
 				),
 			},
 			{
-				Config: testAccIcebergNamespaceResourceConfig(providerCfg, ""),
+				Config: testAccIcebergNamespaceResourceConfig(providerCfg, namespace, ""),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("iceberg_namespace.test", "name.0", "db1"),
+					resource.TestCheckResourceAttr("iceberg_namespace.test", "name.0", namespace),
 					resource.TestCheckNoResourceAttr("iceberg_namespace.test", "user_properties.description"),
 					resource.TestCheckNoResourceAttr("iceberg_namespace.test", "server_properties.description"),
 				),
@@ -88,7 +291,168 @@ func TestAccIcebergNamespace(t *testing.T) {
 	})
 }
 
-func testAccIcebergNamespaceResourceConfig(providerCfg string, description string) string {
+func TestAccIcebergNamespace_CaseInsensitivePropertyKeys(t *testing.T) {
+	t.Parallel()
+
+	m := newMockRESTCatalog()
+	m.lowercaseKeys = true
+	server := m.start(t)
+
+	config := func(ignoreCase bool, properties string) string {
+		return fmt.Sprintf(providerConfig, server.URL) + fmt.Sprintf(`
+resource "iceberg_namespace" "sales" {
+  name                           = ["sales"]
+  case_insensitive_property_keys = %t
+  user_properties = {
+    %s
+  }
+}
+`, ignoreCase, properties)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(true, "Description = \"a\"\n    description = \"b\""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Duplicate property key`),
+			},
+			{
+				// The plan after the apply is checked to be empty.
+				Config: config(true, `Description = "Sales"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.sales", "user_properties.Description", "Sales"),
+					resource.TestCheckResourceAttr("iceberg_namespace.sales", "server_properties.description", "Sales"),
+					resource.TestCheckNoResourceAttr("iceberg_namespace.sales", "server_managed_properties.description"),
+				),
+			},
+			{
+				Config: config(true, `Description = "Sales EU"`),
+				Check:  resource.TestCheckResourceAttr("iceberg_namespace.sales", "server_properties.description", "Sales EU"),
+			},
+			{
+				Config: config(true, ``),
+				Check:  resource.TestCheckNoResourceAttr("iceberg_namespace.sales", "server_properties.description"),
+			},
+		},
+	})
+}
+
+func TestAccIcebergNamespace_CatalogName(t *testing.T) {
+	t.Parallel()
+
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(providerConfig, server.URL) + `
+resource "iceberg_namespace" "sales" {
+  name = ["sales"]
+}
+`,
+				Check: resource.TestCheckNoResourceAttr("iceberg_namespace.sales", "catalog_name"),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+  type        = "polaris"
+
+  polaris_settings {
+    catalog_name = "analytics"
+  }
+}
+
+resource "iceberg_namespace" "sales" {
+  name = ["sales"]
+}
+`, server.URL),
+				Check: resource.TestCheckResourceAttr("iceberg_namespace.sales", "catalog_name", "analytics"),
+			},
+		},
+	})
+}
+
+func TestAccIcebergNamespace_PropertiesPrecondition(t *testing.T) {
+	t.Parallel()
+
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
+
+	config := func(withTable bool) string {
+		tableCfg := ""
+		if withTable {
+			tableCfg = `
+resource "iceberg_table" "precondition" {
+  namespace = iceberg_namespace.precondition.name
+  name      = "gold_only"
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+
+  lifecycle {
+    precondition {
+      condition     = lookup(iceberg_namespace.precondition.properties, "tier", "") == "gold"
+      error_message = "Tables may only be created in gold namespaces."
+    }
+  }
+}
+`
+		}
+
+		return providerCfg + fmt.Sprintf(`
+resource "iceberg_namespace" "precondition" {
+  name = [%q]
+}
+%s`, namespace, tableCfg)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(false),
+				Check:  resource.TestCheckNoResourceAttr("iceberg_namespace.precondition", "properties.tier"),
+			},
+			{
+				// The properties are known at plan time, so the precondition
+				// fails before the table is created.
+				Config:      config(true),
+				ExpectError: regexp.MustCompile(`Resource precondition failed`),
+			},
+			{
+				// The tier is set by another client, outside of Terraform.
+				PreConfig: func() {
+					cat, err := testAccCatalog(t)
+					require.NoError(t, err)
+					_, err = cat.UpdateNamespaceProperties(context.Background(), table.Identifier{namespace}, nil, iceberg.Properties{"tier": "gold"})
+					require.NoError(t, err)
+				},
+				Config: config(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.precondition", "properties.tier", "gold"),
+					resource.TestCheckResourceAttr("iceberg_table.precondition", "name", "gold_only"),
+				),
+			},
+		},
+	})
+}
+
+func testAccIcebergNamespaceResourceConfig(providerCfg, namespace, description string) string {
 	propsStr := ""
 	if description != "" {
 		propsStr = fmt.Sprintf(`user_properties = {
@@ -98,8 +462,8 @@ func testAccIcebergNamespaceResourceConfig(providerCfg string, description strin
 
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "test" {
-  name        = ["db1"]
+  name        = [%q]
   %s
 }
-`, propsStr)
+`, namespace, propsStr)
 }
@@ -0,0 +1,578 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// namespacesParallelism bounds the number of concurrent catalog calls made
+// while applying a change to iceberg_namespaces.
+const namespacesParallelism = 8
+
+var (
+	_ resource.Resource                   = &icebergNamespacesResource{}
+	_ resource.ResourceWithConfigure      = &icebergNamespacesResource{}
+	_ resource.ResourceWithValidateConfig = &icebergNamespacesResource{}
+	_ resource.ResourceWithModifyPlan     = &icebergNamespacesResource{}
+)
+
+func NewNamespacesResource() resource.Resource {
+	return &icebergNamespacesResource{}
+}
+
+// icebergNamespacesResource manages a set of namespaces and their properties
+// as one resource, so many namespaces can be onboarded with a single map.
+type icebergNamespacesResource struct {
+	catalog  catalog.Catalog
+	provider *icebergProvider
+}
+
+type icebergNamespacesResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Namespaces       types.Map    `tfsdk:"namespaces"`
+	AllowDeletes     types.Bool   `tfsdk:"allow_deletes"`
+	ServerProperties types.Map    `tfsdk:"server_properties"`
+	NamePrefix       types.String `tfsdk:"name_prefix"`
+}
+
+// namespacePropertiesType is the type of the namespaces and server_properties
+// attributes: properties by encoded namespace name.
+var namespacePropertiesType = types.MapType{ElemType: types.StringType}
+
+func (r *icebergNamespacesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_namespaces"
+}
+
+func (r *icebergNamespacesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A resource for managing many Iceberg namespaces and their properties at once.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"namespaces": schema.MapAttribute{
+				Description: "The user-defined properties of each namespace, by namespace name. Nested namespaces are named by their levels joined with '.', e.g. 'sales.eu'; dots within a level are escaped as '\\.'. Only the listed properties are changed, all others on the server stay the same.",
+				Required:    true,
+				ElementType: namespacePropertiesType,
+			},
+			"allow_deletes": schema.BoolAttribute{
+				Description: "If true, namespaces removed from namespaces, or all of them when the resource is destroyed, are dropped from the catalog. Otherwise they are left in the catalog and no longer managed.",
+				Optional:    true,
+			},
+			"server_properties": schema.MapAttribute{
+				Description: "Full properties returned by the server for each namespace.",
+				Computed:    true,
+				ElementType: namespacePropertiesType,
+			},
+			"name_prefix": schema.StringAttribute{
+				Description: "The name_prefix of the provider, prepended to the first level of each namespace in the catalog. Namespaces keep the prefix they were created with: changing name_prefix while the resource exists is an error.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *icebergNamespacesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var namespaces types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("namespaces"), &namespaces)...)
+	if resp.Diagnostics.HasError() || namespaces.IsNull() || namespaces.IsUnknown() {
+		return
+	}
+
+	for name := range namespaces.Elements() {
+		if _, err := decodeIdentifier(name); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("namespaces").AtMapKey(name),
+				"Invalid namespace name",
+				err.Error(),
+			)
+		}
+	}
+}
+
+// ModifyPlan records the name prefix of a new resource in the plan and
+// rejects plans of an existing one after the name prefix changed.
+func (r *icebergNamespacesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.provider == nil {
+		return
+	}
+
+	prefix := types.StringNull()
+	if r.provider.namePrefix != "" {
+		prefix = types.StringValue(r.provider.namePrefix)
+	}
+
+	if !req.State.Raw.IsNull() {
+		var statePrefix types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name_prefix"), &statePrefix)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if statePrefix.ValueString() != r.provider.namePrefix {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("name_prefix"),
+				"Name prefix changed",
+				fmt.Sprintf("The namespaces were created with the name_prefix %q, but the provider now has %q. "+
+					"Changing name_prefix while resources exist would leave the namespaces created with the old prefix unmanaged: "+
+					"restore the previous name_prefix, or destroy the resource before changing it.",
+					statePrefix.ValueString(), r.provider.namePrefix),
+			)
+
+			return
+		}
+		prefix = statePrefix
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name_prefix"), prefix)...)
+}
+
+func (r *icebergNamespacesResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			"Expected *icebergProvider, got: %T. Please report this issue to the provider developers.",
+		)
+
+		return
+	}
+
+	r.provider = provider
+}
+
+func (r *icebergNamespacesResource) ConfigureCatalog(ctx context.Context, diags *diag.Diagnostics) {
+	if r.catalog != nil {
+		return
+	}
+
+	if r.provider == nil {
+		diags.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation",
+		)
+
+		return
+	}
+
+	if !r.provider.catalogConfigured() {
+		return
+	}
+
+	catalog, err := r.provider.NewCatalog(ctx, "iceberg_namespaces")
+	if err != nil {
+		diags.AddError(
+			"Failed to create catalog",
+			"Failed to create catalog: "+err.Error(),
+		)
+
+		return
+	}
+	r.catalog = catalog
+}
+
+func (r *icebergNamespacesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !r.provider.checkWritable("iceberg_namespaces", "create", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_namespaces")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergNamespacesResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired := namespacePropertiesFromMap(ctx, data.Namespaces, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(uuid.NewString())
+	managed := r.apply(ctx, data.NamePrefix.ValueString(), desired, nil, false, &resp.Diagnostics)
+	r.refresh(ctx, &data, managed, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, data.NamePrefix.ValueString(), desired, &resp.Diagnostics)
+	}
+}
+
+func (r *icebergNamespacesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withResourceType(ctx, "iceberg_namespaces")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergNamespacesResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prior := namespacePropertiesFromMap(ctx, data.Namespaces, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.refresh(ctx, &data, prior, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.provider.warnOnDrift {
+		current := namespacePropertiesFromMap(ctx, data.Namespaces, &resp.Diagnostics)
+		for _, name := range slices.Sorted(maps.Keys(current)) {
+			report := newDriftReport("Namespace " + name)
+			report.compareProperties(prior[name], current[name])
+			report.addTo(&resp.Diagnostics)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *icebergNamespacesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !r.provider.checkWritable("iceberg_namespaces", "update", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_namespaces")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state icebergNamespacesResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired := namespacePropertiesFromMap(ctx, plan.Namespaces, &resp.Diagnostics)
+	prior := namespacePropertiesFromMap(ctx, state.Namespaces, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := r.apply(ctx, state.NamePrefix.ValueString(), desired, prior, plan.AllowDeletes.ValueBool(), &resp.Diagnostics)
+	r.refresh(ctx, &plan, managed, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, state.NamePrefix.ValueString(), desired, &resp.Diagnostics)
+	}
+}
+
+func (r *icebergNamespacesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !r.provider.checkWritable("iceberg_namespaces", "delete", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_namespaces")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergNamespacesResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	prior := namespacePropertiesFromMap(ctx, data.Namespaces, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remaining := r.apply(ctx, data.NamePrefix.ValueString(), nil, prior, data.AllowDeletes.ValueBool(), &resp.Diagnostics)
+	if len(remaining) > 0 {
+		// Keep the namespaces that failed to drop, so destroying again
+		// retries them.
+		r.refresh(ctx, &data, remaining, &resp.Diagnostics)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	}
+}
+
+// apply creates, updates and drops namespaces so the catalog matches desired,
+// given the properties managed before the change, with prefix prepended to
+// their names in the catalog. Namespaces are created
+// parents first and dropped children first; within a level the calls run
+// concurrently. Namespaces no longer desired are dropped only if
+// allowDeletes is set. The returned map holds the properties managed after
+// the change: a namespace that failed to create is left out and one that
+// failed to update or drop keeps its previous properties, so the next apply
+// retries them. Each failure is reported as an error naming its namespace.
+func (r *icebergNamespacesResource) apply(ctx context.Context, prefix string, desired, managed map[string]map[string]string, allowDeletes bool, diags *diag.Diagnostics) map[string]map[string]string {
+	result := make(map[string]map[string]string, len(desired))
+	var creates, updates, drops []string
+	for name, props := range desired {
+		prior, ok := managed[name]
+		switch {
+		case !ok:
+			creates = append(creates, name)
+		case !maps.Equal(props, prior):
+			result[name] = prior
+			updates = append(updates, name)
+		default:
+			result[name] = prior
+		}
+	}
+	for name := range managed {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		if allowDeletes {
+			result[name] = managed[name]
+			drops = append(drops, name)
+		}
+	}
+	if !allowDeletes {
+		addUnmanagedNamespacesWarning(managed, desired, diags)
+	}
+
+	tflog.Info(ctx, "Applying namespaces", map[string]any{"creates": len(creates), "updates": len(updates), "drops": len(drops)})
+
+	for _, level := range namespaceLevels(creates, false) {
+		errs := r.forEachNamespace(ctx, prefix, level, func(i int, ident table.Identifier, d *diag.Diagnostics) {
+			name := level[i]
+			if err := createNamespace(ctx, r.catalog, ident, desired[name]); err != nil {
+				d.AddError("Failed to create namespace "+name, err.Error())
+			}
+		}, diags)
+		for i, name := range level {
+			if !errs[i] {
+				result[name] = desired[name]
+			}
+		}
+	}
+
+	errs := r.forEachNamespace(ctx, prefix, updates, func(i int, ident table.Identifier, d *diag.Diagnostics) {
+		name := updates[i]
+		current, err := r.catalog.LoadNamespaceProperties(ctx, ident)
+		if err != nil {
+			d.AddError("Failed to read namespace "+name, err.Error())
+
+			return
+		}
+		delta := properties.ComputeDelta(desired[name], managed[name], properties.Known(current))
+		if !delta.IsEmpty() {
+			applyNamespacePropertyDelta(ctx, r.catalog, r.provider, ident, delta, d)
+		}
+	}, diags)
+	for i, name := range updates {
+		if !errs[i] {
+			result[name] = desired[name]
+		}
+	}
+
+	for _, level := range namespaceLevels(drops, true) {
+		errs := r.forEachNamespace(ctx, prefix, level, func(i int, ident table.Identifier, d *diag.Diagnostics) {
+			if err := r.catalog.DropNamespace(ctx, ident); err != nil && !isNotFoundError(err) {
+				d.AddError("Failed to drop namespace "+level[i], err.Error())
+			}
+		}, diags)
+		for i, name := range level {
+			if !errs[i] {
+				delete(result, name)
+			}
+		}
+	}
+
+	return result
+}
+
+// verifyApplied reads back every namespace of desired, with prefix prepended
+// to its name in the catalog, and compares its properties with the desired
+// ones, see apply_verification.go.
+func (r *icebergNamespacesResource) verifyApplied(ctx context.Context, prefix string, desired map[string]map[string]string, diags *diag.Diagnostics) {
+	names := slices.Sorted(maps.Keys(desired))
+	r.forEachNamespace(ctx, prefix, names, func(i int, ident table.Identifier, d *diag.Diagnostics) {
+		props, err := r.catalog.LoadNamespaceProperties(ctx, ident)
+		if err != nil {
+			d.AddError("Failed to read namespace "+names[i]+" back after the apply", err.Error())
+
+			return
+		}
+
+		report := newDriftReport("Namespace " + names[i])
+		report.compareProperties(desired[names[i]], props)
+		report.addMismatchTo(d)
+	}, diags)
+}
+
+// forEachNamespace calls fn for the namespace at every index of names, with
+// prefix prepended to its name in the catalog, with bounded concurrency. The diagnostics of the calls are appended to diags in
+// the order of names; the result reports for each name whether its call
+// failed.
+func (r *icebergNamespacesResource) forEachNamespace(ctx context.Context, prefix string, names []string, fn func(i int, ident table.Identifier, d *diag.Diagnostics), diags *diag.Diagnostics) []bool {
+	results := make([]diag.Diagnostics, len(names))
+	runBounded(len(names), namespacesParallelism, func(i int) {
+		ident, err := decodeIdentifier(names[i])
+		if err != nil {
+			results[i].AddError("Invalid namespace name "+names[i], err.Error())
+
+			return
+		}
+		fn(i, withNamePrefix(ident, prefix), &results[i])
+	})
+
+	failed := make([]bool, len(names))
+	for i, d := range results {
+		diags.Append(d...)
+		failed[i] = d.HasError()
+	}
+
+	return failed
+}
+
+// refresh loads every namespace in managed and stores its managed and server
+// properties in data. Namespaces that no longer exist are left out.
+func (r *icebergNamespacesResource) refresh(ctx context.Context, data *icebergNamespacesResourceModel, managed map[string]map[string]string, diags *diag.Diagnostics) {
+	names := slices.Sorted(maps.Keys(managed))
+	loaded := make([]map[string]string, len(names))
+	failed := r.forEachNamespace(ctx, data.NamePrefix.ValueString(), names, func(i int, ident table.Identifier, d *diag.Diagnostics) {
+		props, err := r.catalog.LoadNamespaceProperties(ctx, ident)
+		switch {
+		case isNotFoundError(err):
+		case err != nil:
+			d.AddError("Failed to read namespace "+names[i], err.Error())
+		default:
+			loaded[i] = properties.Known(props)
+		}
+	}, diags)
+
+	namespaces := make(map[string]map[string]string, len(names))
+	server := make(map[string]map[string]string, len(names))
+	for i, name := range names {
+		switch {
+		case failed[i]:
+			// Keep what is known rather than dropping the namespace.
+			namespaces[name] = managed[name]
+			server[name] = managed[name]
+		case loaded[i] != nil:
+			namespaces[name] = properties.Reconcile(managed[name], loaded[i])
+			server[name] = loaded[i]
+		}
+	}
+
+	var d diag.Diagnostics
+	data.Namespaces, d = types.MapValueFrom(ctx, namespacePropertiesType, namespaces)
+	diags.Append(d...)
+	data.ServerProperties, d = types.MapValueFrom(ctx, namespacePropertiesType, server)
+	diags.Append(d...)
+}
+
+// namespacePropertiesFromMap returns the properties by namespace name held in
+// a namespaces or server_properties value.
+func namespacePropertiesFromMap(ctx context.Context, value types.Map, diags *diag.Diagnostics) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+	if value.IsNull() || value.IsUnknown() {
+		return result
+	}
+
+	diags.Append(value.ElementsAs(ctx, &result, false)...)
+	for name, props := range result {
+		if props == nil {
+			result[name] = make(map[string]string)
+		}
+	}
+
+	return result
+}
+
+// namespaceLevels groups names by the number of levels of the namespace, in
+// increasing order of depth, or decreasing if deepestFirst is set, so parents
+// are created before and dropped after their children.
+func namespaceLevels(names []string, deepestFirst bool) [][]string {
+	byDepth := make(map[int][]string)
+	for _, name := range names {
+		depth := 1
+		if ident, err := decodeIdentifier(name); err == nil {
+			depth = len(ident)
+		}
+		byDepth[depth] = append(byDepth[depth], name)
+	}
+
+	depths := slices.Sorted(maps.Keys(byDepth))
+	if deepestFirst {
+		slices.Reverse(depths)
+	}
+
+	levels := make([][]string, 0, len(depths))
+	for _, depth := range depths {
+		level := byDepth[depth]
+		slices.Sort(level)
+		levels = append(levels, level)
+	}
+
+	return levels
+}
+
+// addUnmanagedNamespacesWarning warns about the namespaces in managed that
+// aren't desired anymore and are left in the catalog because deletes aren't
+// allowed.
+func addUnmanagedNamespacesWarning(managed, desired map[string]map[string]string, diags *diag.Diagnostics) {
+	var left []string
+	for name := range managed {
+		if _, ok := desired[name]; !ok {
+			left = append(left, name)
+		}
+	}
+	if len(left) == 0 {
+		return
+	}
+	slices.Sort(left)
+
+	diags.AddWarning(
+		"Namespaces not dropped",
+		"allow_deletes isn't set, so these namespaces are no longer managed by Terraform but remain in the catalog: "+strings.Join(left, ", "),
+	)
+}
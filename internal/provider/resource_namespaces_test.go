@@ -0,0 +1,192 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newNamespacesTestResource(t *testing.T, m *mockRESTCatalog) *icebergNamespacesResource {
+	t.Helper()
+
+	server := m.start(t)
+	r := &icebergNamespacesResource{provider: &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}}
+
+	var diags diag.Diagnostics
+	r.ConfigureCatalog(context.Background(), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	return r
+}
+
+func TestNamespacesApply(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("failed creates are left out", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.failNamespaces["broken"] = true
+		r := newNamespacesTestResource(t, m)
+
+		var diags diag.Diagnostics
+		result := r.apply(ctx, "", map[string]map[string]string{
+			"sales":   {"owner": "sales"},
+			"finance": {},
+			"broken":  {},
+		}, nil, false, &diags)
+
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Failed to create namespace broken", diags.Errors()[0].Summary())
+		assert.Equal(t, map[string]map[string]string{"sales": {"owner": "sales"}, "finance": {}}, result)
+		assert.Equal(t, map[string]string{"owner": "sales"}, m.namespaceProperties("sales"))
+	})
+
+	t.Run("managed properties are updated", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.namespaces["sales"] = map[string]string{"owner": "sales", "tier": "gold", "location": "s3://sales"}
+		r := newNamespacesTestResource(t, m)
+
+		var diags diag.Diagnostics
+		result := r.apply(ctx, "",
+			map[string]map[string]string{"sales": {"owner": "revenue"}},
+			map[string]map[string]string{"sales": {"owner": "sales", "tier": "gold"}},
+			false, &diags)
+
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, map[string]map[string]string{"sales": {"owner": "revenue"}}, result)
+		assert.Equal(t, map[string]string{"owner": "revenue", "location": "s3://sales"}, m.namespaceProperties("sales"))
+	})
+
+	t.Run("removed namespaces are dropped with allow_deletes", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.namespaces["sales"] = map[string]string{}
+		m.namespaces["finance"] = map[string]string{}
+		r := newNamespacesTestResource(t, m)
+		managed := map[string]map[string]string{"sales": {}, "finance": {}}
+
+		var diags diag.Diagnostics
+		result := r.apply(ctx, "", map[string]map[string]string{"sales": {}}, managed, true, &diags)
+
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Equal(t, map[string]map[string]string{"sales": {}}, result)
+		assert.Nil(t, m.namespaceProperties("finance"))
+	})
+
+	t.Run("removed namespaces are left without allow_deletes", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.namespaces["sales"] = map[string]string{}
+		m.namespaces["finance"] = map[string]string{}
+		r := newNamespacesTestResource(t, m)
+		managed := map[string]map[string]string{"sales": {}, "finance": {}}
+
+		var diags diag.Diagnostics
+		result := r.apply(ctx, "", map[string]map[string]string{"sales": {}}, managed, false, &diags)
+
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Len(t, diags.Warnings(), 1)
+		assert.Contains(t, diags.Warnings()[0].Detail(), "finance")
+		assert.Equal(t, map[string]map[string]string{"sales": {}}, result)
+		assert.NotNil(t, m.namespaceProperties("finance"))
+	})
+
+	t.Run("namespaces that fail to drop stay managed", func(t *testing.T) {
+		m := newMockRESTCatalog()
+		m.addTable("finance", "ledger", nil)
+		r := newNamespacesTestResource(t, m)
+
+		var diags diag.Diagnostics
+		result := r.apply(ctx, "", nil, map[string]map[string]string{"finance": {}}, true, &diags)
+
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Failed to drop namespace finance", diags.Errors()[0].Summary())
+		assert.Equal(t, map[string]map[string]string{"finance": {}}, result)
+	})
+}
+
+func TestNamespacesRefresh(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.namespaces["sales"] = map[string]string{"owner": "SALES", "location": "s3://sales"}
+	r := newNamespacesTestResource(t, m)
+
+	var data icebergNamespacesResourceModel
+	var diags diag.Diagnostics
+	r.refresh(context.Background(), &data, map[string]map[string]string{
+		"sales":   {"owner": "sales"},
+		"dropped": {},
+	}, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	assert.Equal(t, map[string]map[string]string{"sales": {"owner": "SALES"}}, namespacePropertiesFromMap(context.Background(), data.Namespaces, &diags))
+	assert.Equal(t, map[string]map[string]string{"sales": {"owner": "SALES", "location": "s3://sales"}}, namespacePropertiesFromMap(context.Background(), data.ServerProperties, &diags))
+}
+
+func TestNamespaceLevels(t *testing.T) {
+	names := []string{"sales.eu", "finance", "sales", `a\.b`, "sales.eu.de"}
+
+	assert.Equal(t, [][]string{{`a\.b`, "finance", "sales"}, {"sales.eu"}, {"sales.eu.de"}}, namespaceLevels(names, false))
+	assert.Equal(t, [][]string{{"sales.eu.de"}, {"sales.eu"}, {`a\.b`, "finance", "sales"}}, namespaceLevels(names, true))
+}
+
+func TestAccIcebergNamespaces(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	config := func(namespaces string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_namespaces" "teams" {
+  allow_deletes = true
+  namespaces = {
+    %s
+  }
+}
+`, server.URL, namespaces)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`sales = { owner = "sales" }
+    finance = {}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespaces.teams", "namespaces.sales.owner", "sales"),
+					resource.TestCheckResourceAttr("iceberg_namespaces.teams", "server_properties.sales.owner", "sales"),
+					resource.TestCheckResourceAttr("iceberg_namespaces.teams", "server_properties.%", "2"),
+				),
+			},
+			{
+				Config: config(`sales = { owner = "revenue" }
+    marketing = {}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespaces.teams", "server_properties.sales.owner", "revenue"),
+					resource.TestCheckResourceAttr("iceberg_namespaces.teams", "server_properties.%", "2"),
+					resource.TestCheckNoResourceAttr("iceberg_namespaces.teams", "server_properties.finance"),
+				),
+			},
+		},
+	})
+}
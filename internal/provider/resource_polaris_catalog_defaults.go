@@ -0,0 +1,384 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// polarisDefaultPropertyPrefix marks the catalog properties that new
+// namespaces of the catalog inherit.
+const polarisDefaultPropertyPrefix = "default."
+
+// catalogDefaultsView selects the default namespace properties among the
+// properties of a catalog.
+var catalogDefaultsView = polarisPropertyView{
+	managed: catalogDefaultProperties,
+	apply:   applyCatalogDefaultsDelta,
+}
+
+var (
+	_ resource.Resource                = &polarisCatalogDefaultsResource{}
+	_ resource.ResourceWithImportState = &polarisCatalogDefaultsResource{}
+)
+
+func NewPolarisCatalogDefaultsResource() resource.Resource {
+	return &polarisCatalogDefaultsResource{}
+}
+
+// polarisCatalogDefaultsResource manages the default namespace properties of
+// a Polaris catalog, stored as catalog properties with the default. prefix.
+// Other catalog properties, and defaults not in the configuration, are left
+// alone.
+type polarisCatalogDefaultsResource struct {
+	provider         *icebergProvider
+	managementClient *polarisManagementClient
+}
+
+type polarisCatalogDefaultsResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	CatalogName types.String `tfsdk:"catalog_name"`
+	Properties  types.Map    `tfsdk:"properties"`
+}
+
+func (r *polarisCatalogDefaultsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_polaris_catalog_defaults"
+}
+
+func (r *polarisCatalogDefaultsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A resource for managing the properties new namespaces of a Polaris catalog inherit. They are stored as catalog properties prefixed with `default.`; other catalog properties and defaults that aren't listed are left unchanged.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"catalog_name": schema.StringAttribute{
+				Description: "The name of the Polaris catalog.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"properties": schema.MapAttribute{
+				Description: "The default namespace properties, keyed without the `default.` prefix. For example, owner = \"data-platform\" is stored as the catalog property default.owner.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (r *polarisCatalogDefaultsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			"Expected *icebergProvider, got a different type: %T. Please report this issue to the provider developers.",
+		)
+	}
+	r.provider = provider
+}
+
+func (r *polarisCatalogDefaultsResource) ensureManagementClient(ctx context.Context, diags *diag.Diagnostics) {
+	if r.managementClient != nil {
+		return
+	}
+	if r.provider == nil {
+		diags.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation")
+
+		return
+	}
+	client, err := r.provider.newPolarisManagementClient("iceberg_polaris_catalog_defaults")
+	if err != nil {
+		diags.AddError("Failed to create Polaris management API client", err.Error())
+
+		return
+	}
+	r.managementClient = client
+}
+
+func (r *polarisCatalogDefaultsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !r.provider.checkWritable("iceberg_polaris_catalog_defaults", "create", &resp.Diagnostics) {
+		return
+	}
+
+	r.ensureManagementClient(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data polarisCatalogDefaultsResourceModel
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired := make(map[string]string)
+	resp.Diagnostics.Append(data.Properties.ElementsAs(ctx, &desired, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = data.CatalogName
+	r.apply(ctx, &data, desired, nil, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, data.CatalogName.ValueString(), desired, &resp.Diagnostics)
+	}
+}
+
+func (r *polarisCatalogDefaultsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	r.ensureManagementClient(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data polarisCatalogDefaultsResourceModel
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	catalogName := data.CatalogName.ValueString()
+
+	tflog.Info(ctx, "Reading Polaris catalog defaults", map[string]any{"catalog": catalogName})
+
+	cat, err := r.managementClient.GetCatalog(ctx, catalogName)
+	if err != nil {
+		if isNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read Polaris catalog", err.Error())
+
+		return
+	}
+
+	defaults := catalogDefaultProperties(cat.Properties)
+	// An imported resource takes over all defaults of the catalog; otherwise
+	// only the managed keys are tracked.
+	if !data.Properties.IsNull() {
+		managed := make(map[string]string)
+		resp.Diagnostics.Append(data.Properties.ElementsAs(ctx, &managed, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		defaults = properties.Reconcile(managed, defaults)
+	}
+
+	data.Properties, diags = types.MapValueFrom(ctx, types.StringType, defaults)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *polarisCatalogDefaultsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !r.provider.checkWritable("iceberg_polaris_catalog_defaults", "update", &resp.Diagnostics) {
+		return
+	}
+
+	r.ensureManagementClient(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state polarisCatalogDefaultsResourceModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired := make(map[string]string)
+	managed := make(map[string]string)
+	resp.Diagnostics.Append(plan.Properties.ElementsAs(ctx, &desired, false)...)
+	if !state.Properties.IsNull() {
+		resp.Diagnostics.Append(state.Properties.ElementsAs(ctx, &managed, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, desired, managed, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, plan.CatalogName.ValueString(), desired, &resp.Diagnostics)
+	}
+}
+
+func (r *polarisCatalogDefaultsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !r.provider.checkWritable("iceberg_polaris_catalog_defaults", "delete", &resp.Diagnostics) {
+		return
+	}
+
+	r.ensureManagementClient(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data polarisCatalogDefaultsResourceModel
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := make(map[string]string)
+	resp.Diagnostics.Append(data.Properties.ElementsAs(ctx, &managed, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.updateDefaults(ctx, data.CatalogName.ValueString(), map[string]string{}, managed)
+	if err != nil && !isNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to remove Polaris catalog defaults", err.Error())
+	}
+}
+
+func (r *polarisCatalogDefaultsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("catalog_name"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("properties"), types.MapNull(types.StringType))...)
+}
+
+// apply brings the defaults of the catalog in line with desired, removing the
+// keys of managed that are no longer desired. On return data.Properties holds
+// the desired defaults as the catalog stored them.
+func (r *polarisCatalogDefaultsResource) apply(ctx context.Context, data *polarisCatalogDefaultsResourceModel, desired, managed map[string]string, diags *diag.Diagnostics) {
+	catalogName := data.CatalogName.ValueString()
+
+	tflog.Info(ctx, "Applying Polaris catalog defaults", map[string]any{"catalog": catalogName, "count": len(desired)})
+
+	cat, err := r.updateDefaults(ctx, catalogName, desired, managed)
+	if err != nil {
+		diags.AddError("Failed to update Polaris catalog defaults", err.Error())
+
+		return
+	}
+
+	var d diag.Diagnostics
+	data.Properties, d = types.MapValueFrom(ctx, types.StringType, properties.Reconcile(desired, catalogDefaultProperties(cat.Properties)))
+	diags.Append(d...)
+}
+
+// verifyApplied reads the catalog back and compares its defaults with
+// desired, see apply_verification.go.
+func (r *polarisCatalogDefaultsResource) verifyApplied(ctx context.Context, catalogName string, desired map[string]string, diags *diag.Diagnostics) {
+	cat, err := r.managementClient.GetCatalog(ctx, catalogName)
+	if err != nil {
+		diags.AddError("Failed to read Polaris catalog after the apply", err.Error())
+
+		return
+	}
+
+	report := newDriftReport("The defaults of Polaris catalog " + catalogName)
+	report.compareProperties(desired, catalogDefaultProperties(cat.Properties))
+	report.addMismatchTo(diags)
+}
+
+// updateDefaults sets the desired defaults on the catalog and removes the
+// keys of managed that aren't desired. The management API replaces all
+// catalog properties at once, so the update is based on the properties just
+// read and retried if the catalog changed in between.
+func (r *polarisCatalogDefaultsResource) updateDefaults(ctx context.Context, catalogName string, desired, managed map[string]string) (*polarisCatalog, error) {
+	return updatePolarisProperties(ctx, catalogName, catalogDefaultsView, desired, managed,
+		func(ctx context.Context) (*polarisCatalog, error) {
+			return r.managementClient.GetCatalog(ctx, catalogName)
+		},
+		func(ctx context.Context, entityVersion int64, props map[string]string) (*polarisCatalog, error) {
+			return r.managementClient.UpdateCatalog(ctx, catalogName, polarisUpdateCatalogRequest{
+				CurrentEntityVersion: entityVersion,
+				Properties:           props,
+			})
+		},
+	)
+}
+
+// catalogDefaultProperties returns the default namespace properties among the
+// catalog properties, without their prefix.
+func catalogDefaultProperties(props map[string]string) map[string]string {
+	defaults := make(map[string]string)
+	for k, v := range props {
+		if key, ok := strings.CutPrefix(k, polarisDefaultPropertyPrefix); ok {
+			defaults[key] = v
+		}
+	}
+
+	return defaults
+}
+
+// applyCatalogDefaultsDelta returns a copy of the catalog properties with the
+// delta of default namespace properties applied.
+func applyCatalogDefaultsDelta(props map[string]string, delta properties.Delta) map[string]string {
+	result := maps.Clone(props)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	for k, v := range delta.Updates {
+		result[polarisDefaultPropertyPrefix+k] = v
+	}
+	for _, k := range delta.Removals {
+		delete(result, polarisDefaultPropertyPrefix+k)
+	}
+
+	return result
+}
@@ -0,0 +1,199 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// polarisCatalogTestServer serves a single Polaris catalog named "cat" whose
+// properties are replaced as a whole on update, like the management API.
+type polarisCatalogTestServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	catalog polarisCatalog
+	// conflicts is the number of updates to reject as concurrent changes.
+	conflicts int
+	updates   int
+}
+
+func newPolarisCatalogTestServer(t *testing.T, props map[string]string) *polarisCatalogTestServer {
+	t.Helper()
+
+	s := &polarisCatalogTestServer{catalog: polarisCatalog{Name: "cat", Type: "INTERNAL", Properties: props, EntityVersion: 1}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/management/v1/catalogs/cat", func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		writeJSON(w, http.StatusOK, s.catalog)
+	})
+	mux.HandleFunc("PUT /api/management/v1/catalogs/cat", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		var req polarisUpdateCatalogRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+		if s.conflicts > 0 {
+			// Another client changed the catalog in the meantime.
+			s.conflicts--
+			s.catalog.EntityVersion++
+			s.catalog.Properties["other"] = fmt.Sprint(s.catalog.EntityVersion)
+		}
+		if req.CurrentEntityVersion != s.catalog.EntityVersion {
+			http.Error(w, "entity version mismatch", http.StatusConflict)
+
+			return
+		}
+
+		s.updates++
+		s.catalog.Properties = req.Properties
+		s.catalog.EntityVersion++
+		writeJSON(w, http.StatusOK, s.catalog)
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *polarisCatalogTestServer) properties() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return maps.Clone(s.catalog.Properties)
+}
+
+func TestCatalogDefaultProperties(t *testing.T) {
+	props := map[string]string{
+		"default-base-location": "s3://warehouse",
+		"default.owner":         "data",
+		"default.team":          "platform",
+	}
+
+	assert.Equal(t, map[string]string{"owner": "data", "team": "platform"}, catalogDefaultProperties(props))
+
+	updated := applyCatalogDefaultsDelta(props, properties.Delta{Updates: map[string]string{"owner": "analytics"}, Removals: []string{"team"}})
+	assert.Equal(t, map[string]string{"default-base-location": "s3://warehouse", "default.owner": "analytics"}, updated)
+	assert.Equal(t, "data", props["default.owner"], "the input must not be modified")
+}
+
+func TestPolarisCatalogDefaultsUpdateRetriesConflicts(t *testing.T) {
+	server := newPolarisCatalogTestServer(t, map[string]string{"default-base-location": "s3://warehouse", "default.team": "platform"})
+	server.conflicts = 2
+
+	p := &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}
+	r := &polarisCatalogDefaultsResource{provider: p}
+	client, err := p.newPolarisManagementClient("iceberg_polaris_catalog_defaults")
+	require.NoError(t, err)
+	r.managementClient = client
+
+	_, err = r.updateDefaults(context.Background(), "cat", map[string]string{"owner": "data"}, map[string]string{"team": "platform"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"default-base-location": "s3://warehouse", "default.owner": "data", "other": "3"}, server.properties())
+
+	// Nothing to change: no update is sent.
+	updates := server.updates
+	_, err = r.updateDefaults(context.Background(), "cat", map[string]string{"owner": "data"}, map[string]string{"owner": "data"})
+	require.NoError(t, err)
+	assert.Equal(t, updates, server.updates)
+
+	// Conflicts beyond the retry limit are returned.
+	server.conflicts = polarisUpdateAttempts
+	_, err = r.updateDefaults(context.Background(), "cat", map[string]string{"owner": "analytics"}, nil)
+	require.ErrorIs(t, err, errPolarisConflict)
+}
+
+func testAccPolarisCatalogDefaultsConfig(providerCfg, properties string) string {
+	return providerCfg + fmt.Sprintf(`
+resource "iceberg_polaris_catalog_defaults" "test" {
+  catalog_name = "cat"
+
+  properties = {
+%s
+  }
+}
+`, properties)
+}
+
+func TestAccPolarisCatalogDefaults(t *testing.T) {
+	server := newPolarisCatalogTestServer(t, map[string]string{
+		"default-base-location": "s3://warehouse",
+		"default.retention":     "30d",
+	})
+
+	providerCfg := testAccPolarisProviderConfig(server.URL, server.URL+"/api/management/v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolarisCatalogDefaultsConfig(providerCfg, `    owner = "data"
+    team  = "platform"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_polaris_catalog_defaults.test", "id", "cat"),
+					resource.TestCheckResourceAttr("iceberg_polaris_catalog_defaults.test", "properties.%", "2"),
+					resource.TestCheckResourceAttr("iceberg_polaris_catalog_defaults.test", "properties.owner", "data"),
+				),
+			},
+			{
+				Config: testAccPolarisCatalogDefaultsConfig(providerCfg, `    owner = "analytics"`),
+				Check: func(_ *terraform.State) error {
+					want := map[string]string{
+						"default-base-location": "s3://warehouse",
+						"default.retention":     "30d",
+						"default.owner":         "analytics",
+					}
+					if got := server.properties(); !maps.Equal(want, got) {
+						return fmt.Errorf("catalog properties: want %v, got %v", want, got)
+					}
+
+					return nil
+				},
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			want := map[string]string{"default-base-location": "s3://warehouse", "default.retention": "30d"}
+			if got := server.properties(); !maps.Equal(want, got) {
+				return fmt.Errorf("catalog properties after destroy: want %v, got %v", want, got)
+			}
+
+			return nil
+		},
+	})
+}
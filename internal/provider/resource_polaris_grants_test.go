@@ -0,0 +1,275 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPolarisGrantsTestServer serves the grants endpoints of one catalog role.
+// Adding a grant on the "locked" table fails, and the "dropped" table no
+// longer exists.
+func newPolarisGrantsTestServer(t *testing.T, initial []polarisGrant) (*httptest.Server, func() []polarisGrant) {
+	t.Helper()
+
+	var mu sync.Mutex
+	grants := make(map[string]polarisGrant)
+	for _, g := range initial {
+		grants[g.key()] = g
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/management/v1/catalogs/cat/catalog-roles/role/grants", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodGet {
+			out := polarisGrantsResponse{Grants: make([]polarisGrant, 0, len(grants))}
+			for _, g := range grants {
+				out.Grants = append(out.Grants, g)
+			}
+			_ = json.NewEncoder(w).Encode(out)
+
+			return
+		}
+
+		var req polarisGrantRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			if req.Grant.TableName == "locked" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+
+				return
+			}
+			grants[req.Grant.key()] = req.Grant
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodPost:
+			if req.Grant.TableName == "dropped" {
+				delete(grants, req.Grant.key())
+				writePolarisError(w, http.StatusNotFound, "NoSuchTableException", "Table does not exist: db.dropped")
+
+				return
+			}
+			delete(grants, req.Grant.key())
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	snapshot := func() []polarisGrant {
+		mu.Lock()
+		defer mu.Unlock()
+
+		out := make([]polarisGrant, 0, len(grants))
+		for _, g := range grants {
+			out = append(out, g)
+		}
+
+		return out
+	}
+
+	return server, snapshot
+}
+
+// writePolarisError writes an error response in the form of the Polaris
+// management API.
+func writePolarisError(w http.ResponseWriter, status int, typ, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, `{"error": {"message": %q, "type": %q, "code": %d}}`, message, typ, status)
+}
+
+func TestComputeGrantChanges(t *testing.T) {
+	read := polarisGrant{Type: "table", Privilege: "TABLE_READ_DATA", Namespace: []string{"db"}, TableName: "t"}
+	write := polarisGrant{Type: "table", Privilege: "TABLE_WRITE_DATA", Namespace: []string{"db"}, TableName: "t"}
+	list := polarisGrant{Type: "namespace", Privilege: "TABLE_LIST", Namespace: []string{"db"}}
+
+	changes := computeGrantChanges(
+		[]polarisGrant{read, {Type: "TABLE", Privilege: "table_write_data", Namespace: []string{"db"}, TableName: "t"}},
+		[]polarisGrant{write, list},
+	)
+
+	assert.Equal(t, []polarisGrantChange{
+		{grant: read},
+		{grant: list, revoke: true},
+	}, changes)
+}
+
+func TestPolarisGrantsApplyPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	stale := polarisGrant{Type: "catalog", Privilege: "CATALOG_MANAGE_CONTENT"}
+	server, snapshot := newPolarisGrantsTestServer(t, []polarisGrant{stale})
+
+	p := &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}
+	r := &polarisGrantsResource{provider: p}
+	var diags diag.Diagnostics
+	r.ensureManagementClient(ctx, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	ok := polarisGrant{Type: "table", Privilege: "TABLE_READ_DATA", Namespace: []string{"db"}, TableName: "t"}
+	locked := polarisGrant{Type: "table", Privilege: "TABLE_READ_DATA", Namespace: []string{"db"}, TableName: "locked"}
+	grants, d := polarisGrantsToSet(ctx, []polarisGrant{ok, locked})
+	require.False(t, d.HasError(), "%v", d)
+
+	data := polarisGrantsResourceModel{
+		CatalogName:     types.StringValue("cat"),
+		CatalogRoleName: types.StringValue("role"),
+		Grants:          grants,
+	}
+	r.apply(ctx, &data, &diags)
+
+	require.True(t, diags.HasError())
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Failed to add grant TABLE_READ_DATA on table db.locked", diags.Errors()[0].Summary())
+
+	// State reflects what the server actually has: the stale grant was
+	// revoked, the successful grant added and the failed one left out.
+	got, d := polarisGrantsFromSet(ctx, data.Grants)
+	require.False(t, d.HasError(), "%v", d)
+	assert.ElementsMatch(t, []polarisGrant{ok}, got)
+	assert.ElementsMatch(t, []polarisGrant{ok}, snapshot())
+}
+
+func TestPolarisGrantsApplyRevokesDroppedSecurable(t *testing.T) {
+	ctx := context.Background()
+	ok := polarisGrant{Type: "table", Privilege: "TABLE_READ_DATA", Namespace: []string{"db"}, TableName: "t"}
+	dropped := polarisGrant{Type: "table", Privilege: "TABLE_READ_DATA", Namespace: []string{"db"}, TableName: "dropped"}
+	server, snapshot := newPolarisGrantsTestServer(t, []polarisGrant{ok, dropped})
+
+	r := &polarisGrantsResource{provider: &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}}
+	var diags diag.Diagnostics
+	r.ensureManagementClient(ctx, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	grants, d := polarisGrantsToSet(ctx, []polarisGrant{ok})
+	require.False(t, d.HasError(), "%v", d)
+
+	data := polarisGrantsResourceModel{
+		CatalogName:     types.StringValue("cat"),
+		CatalogRoleName: types.StringValue("role"),
+		Grants:          grants,
+	}
+	r.apply(ctx, &data, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.ElementsMatch(t, []polarisGrant{ok}, snapshot())
+}
+
+func TestPolarisGrantsDeleteAfterRole(t *testing.T) {
+	ctx := context.Background()
+
+	// The catalog role was deleted by a parallel destroy before its grants.
+	var revokes int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		revokes++
+		mu.Unlock()
+		writePolarisError(w, http.StatusNotFound, "NotFoundException", "CatalogRole role not found")
+	}))
+	t.Cleanup(server.Close)
+
+	r := &polarisGrantsResource{provider: &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+
+	grants, diags := polarisGrantsToSet(ctx, []polarisGrant{
+		{Type: "catalog", Privilege: "CATALOG_MANAGE_CONTENT"},
+		{Type: "namespace", Privilege: "TABLE_LIST", Namespace: []string{"db"}},
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	diags.Append(state.Set(ctx, &polarisGrantsResourceModel{
+		ID:              types.StringValue("cat/role"),
+		CatalogName:     types.StringValue("cat"),
+		CatalogRoleName: types.StringValue("role"),
+		Grants:          grants,
+	})...)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	resp := fwresource.DeleteResponse{State: state}
+	r.Delete(ctx, fwresource.DeleteRequest{State: state}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), "%v", resp.Diagnostics)
+	assert.Equal(t, 2, revokes)
+}
+
+func TestPolarisGrantsApplyListFailure(t *testing.T) {
+	ctx := context.Background()
+
+	// Nothing changes when the grants of the role can't be listed, so
+	// neither the planned grants nor an empty set may be saved.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writePolarisError(w, http.StatusServiceUnavailable, "ServiceUnavailableException", "try again later")
+	}))
+	t.Cleanup(server.Close)
+
+	r := &polarisGrantsResource{provider: &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	nullState := tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+
+	grants, diags := polarisGrantsToSet(ctx, []polarisGrant{{Type: "catalog", Privilege: "CATALOG_MANAGE_CONTENT"}})
+	require.False(t, diags.HasError(), "%v", diags)
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: nullState}
+	diags.Append(plan.Set(ctx, &polarisGrantsResourceModel{
+		ID:              types.StringValue("cat/role"),
+		CatalogName:     types.StringValue("cat"),
+		CatalogRoleName: types.StringValue("role"),
+		Grants:          grants,
+	})...)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	t.Run("create", func(t *testing.T) {
+		resp := fwresource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: nullState}}
+		r.Create(ctx, fwresource.CreateRequest{Plan: plan}, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "Failed to read Polaris catalog role grants", resp.Diagnostics.Errors()[0].Summary())
+		assert.True(t, resp.State.Raw.IsNull(), "the planned grants were saved")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		resp := fwresource.UpdateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: nullState}}
+		r.Update(ctx, fwresource.UpdateRequest{Plan: plan}, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "Failed to read Polaris catalog role grants", resp.Diagnostics.Errors()[0].Summary())
+		assert.True(t, resp.State.Raw.IsNull(), "the planned grants replaced the prior state")
+	})
+}
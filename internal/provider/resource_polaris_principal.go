@@ -17,8 +17,10 @@ package provider
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/apache/iceberg-terraform/internal/properties"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -26,13 +28,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var (
-	_ resource.Resource                = &polarisPrincipalResource{}
-	_ resource.ResourceWithImportState = &polarisPrincipalResource{}
+	_ resource.Resource                 = &polarisPrincipalResource{}
+	_ resource.ResourceWithImportState  = &polarisPrincipalResource{}
+	_ resource.ResourceWithUpgradeState = &polarisPrincipalResource{}
 )
 
 func NewPolarisPrincipalResource() resource.Resource {
@@ -48,7 +52,9 @@ type polarisPrincipalResource struct {
 type polarisPrincipalResourceModel struct {
 	ID                         types.String `tfsdk:"id"`
 	Name                       types.String `tfsdk:"name"`
-	Properties                 types.Map    `tfsdk:"properties"`
+	UserProperties             types.Map    `tfsdk:"user_properties"`
+	ServerProperties           types.Map    `tfsdk:"server_properties"`
+	ServerManagedProperties    types.Map    `tfsdk:"server_managed_properties"`
 	CredentialRotationRequired types.Bool   `tfsdk:"credential_rotation_required"`
 	ClientID                   types.String `tfsdk:"client_id"`
 	ClientSecret               types.String `tfsdk:"client_secret"`
@@ -61,6 +67,7 @@ func (r *polarisPrincipalResource) Metadata(_ context.Context, req resource.Meta
 func (r *polarisPrincipalResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Description: "A resource for managing Polaris principals and their client credentials.",
+		Version:     1,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed: true,
@@ -75,13 +82,23 @@ func (r *polarisPrincipalResource) Schema(_ context.Context, _ resource.SchemaRe
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
-			"properties": schema.MapAttribute{
-				Description: "Arbitrary metadata properties for the principal.",
+			"user_properties": schema.MapAttribute{
+				Description: "Properties of the principal, such as team = \"data\". Only properties listed in Terraform are changed; others on the server stay the same. Keys removed from the map are removed from the principal.",
 				Optional:    true,
 				ElementType: types.StringType,
 			},
+			"server_properties": schema.MapAttribute{
+				Description: "All properties of the principal returned by Polaris, including those set through user_properties.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"server_managed_properties": schema.MapAttribute{
+				Description: "Properties set on the principal by Polaris or other clients, i.e. server_properties without the keys managed through user_properties.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
 			"credential_rotation_required": schema.BoolAttribute{
-				Description: "If true, the initial credentials can only be used to call rotateCredentials.",
+				Description: "If true, the initial credentials can only be used to call rotateCredentials. Needs Polaris 1.0.0 or later; older servers create the principal without it and a warning.",
 				Optional:    true,
 				Computed:    true,
 				Default:     booldefault.StaticBool(false),
@@ -100,6 +117,26 @@ func (r *polarisPrincipalResource) Schema(_ context.Context, _ resource.SchemaRe
 	}
 }
 
+// UpgradeState upgrades state of schema version 0, which stored all
+// properties of the principal in properties.
+func (r *polarisPrincipalResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var current resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &current)
+
+	prior := priorSchemaWithout(current.Schema, 0, "user_properties", "server_properties", "server_managed_properties")
+	prior.Attributes["properties"] = schema.MapAttribute{
+		Optional:    true,
+		ElementType: types.StringType,
+	}
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   prior,
+			StateUpgrader: upgradePrincipalProperties,
+		},
+	}
+}
+
 func (r *polarisPrincipalResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -126,7 +163,7 @@ func (r *polarisPrincipalResource) ensureManagementClient(ctx context.Context, d
 
 		return
 	}
-	client, err := r.provider.newPolarisManagementClient()
+	client, err := r.provider.newPolarisManagementClient("iceberg_polaris_principal")
 	if err != nil {
 		diags.AddError("Failed to create Polaris management API client", err.Error())
 
@@ -136,6 +173,10 @@ func (r *polarisPrincipalResource) ensureManagementClient(ctx context.Context, d
 }
 
 func (r *polarisPrincipalResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !r.provider.checkWritable("iceberg_polaris_principal", "create", &resp.Diagnostics) {
+		return
+	}
+
 	r.ensureManagementClient(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -152,26 +193,20 @@ func (r *polarisPrincipalResource) Create(ctx context.Context, req resource.Crea
 	name := data.Name.ValueString()
 
 	props := make(map[string]string)
-	if !data.Properties.IsNull() && !data.Properties.IsUnknown() {
-		diags = data.Properties.ElementsAs(ctx, &props, false)
+	if !data.UserProperties.IsNull() && !data.UserProperties.IsUnknown() {
+		diags = data.UserProperties.ElementsAs(ctx, &props, false)
 		resp.Diagnostics.Append(diags...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
 
-	var rotationRequired *bool
-	if !data.CredentialRotationRequired.IsNull() && !data.CredentialRotationRequired.IsUnknown() {
-		v := data.CredentialRotationRequired.ValueBool()
-		rotationRequired = &v
-	}
-
 	reqBody := polarisCreatePrincipalRequest{
 		Principal: polarisPrincipal{
 			Name:       name,
 			Properties: props,
 		},
-		CredentialRotationRequired: rotationRequired,
+		CredentialRotationRequired: r.credentialRotationRequired(ctx, data.CredentialRotationRequired, &resp.Diagnostics),
 	}
 
 	tflog.Info(ctx, "Creating Polaris principal", map[string]any{"name": name})
@@ -185,23 +220,43 @@ func (r *polarisPrincipalResource) Create(ctx context.Context, req resource.Crea
 
 	data.ID = types.StringValue(created.Principal.Name)
 	data.Name = types.StringValue(created.Principal.Name)
-
-	if len(created.Principal.Properties) > 0 {
-		propsVal, diags := types.MapValueFrom(ctx, types.StringType, created.Principal.Properties)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		data.Properties = propsVal
-	} else {
-		data.Properties = types.MapNull(types.StringType)
+	setPrincipalProperties(ctx, &data, props, created.Principal.Properties, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	data.ClientID = types.StringValue(created.Credentials.ClientID)
 	data.ClientSecret = types.StringValue(created.Credentials.ClientSecret)
 
+	recordPrincipalPrivateState(ctx, &created.Principal, resp.Private, &resp.Diagnostics)
+
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, name, props, &resp.Diagnostics)
+	}
+}
+
+// credentialRotationRequired returns the credentialRotationRequired field of
+// a create request for value, nil if it isn't sent.
+func (r *polarisPrincipalResource) credentialRotationRequired(ctx context.Context, value types.Bool, diags *diag.Diagnostics) *bool {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+
+	required := value.ValueBool()
+	if !r.managementClient.supports(ctx, polarisFeatureCredentialRotation) {
+		// Older servers don't know the field; false is what they do without
+		// it, so only true is worth a warning.
+		if required {
+			r.managementClient.checkFeature(ctx, polarisFeatureCredentialRotation, diags)
+		}
+
+		return nil
+	}
+
+	return &required
 }
 
 func (r *polarisPrincipalResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -210,21 +265,18 @@ func (r *polarisPrincipalResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	var data polarisPrincipalResourceModel
-
-	diags := req.State.Get(ctx, &data)
-	resp.Diagnostics.Append(diags...)
+	var name types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	name := data.Name.ValueString()
-
-	tflog.Info(ctx, "Reading Polaris principal", map[string]any{"name": name})
+	tflog.Info(ctx, "Reading Polaris principal", map[string]any{"name": name.ValueString()})
 
-	principal, err := r.managementClient.GetPrincipal(ctx, name)
+	ctx, nulls := withNullProperties(ctx)
+	principal, err := r.managementClient.GetPrincipal(ctx, name.ValueString())
 	if err != nil {
-		if isPolarisNotFoundError(err) {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 
 			return
@@ -233,24 +285,74 @@ func (r *polarisPrincipalResource) Read(ctx context.Context, req resource.ReadRe
 
 		return
 	}
+	nulls.addTo("Polaris principal "+name.ValueString(), &resp.Diagnostics)
 
-	// Update properties; credentials are not returned on GET so keep from state.
-	if len(principal.Properties) > 0 {
-		propsVal, diags := types.MapValueFrom(ctx, types.StringType, principal.Properties)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
+	r.readPrincipal(ctx, principal, &resp.State, req.Private, resp.Private, &resp.Diagnostics)
+}
+
+// readPrincipal stores principal in state. A principal whose
+// lastUpdateTimestamp matches the one recorded in private state hasn't
+// changed since it was last stored, so state is left exactly as it is, which
+// keeps refreshing many principals cheap.
+func (r *polarisPrincipalResource) readPrincipal(ctx context.Context, principal *polarisPrincipal, state *tfsdk.State, priorPrivate privateStateReader, private privateStateWriter, diags *diag.Diagnostics) {
+	lastUpdate, ok := getPrivateValue[int64](ctx, priorPrivate, privateKeyLastUpdate, diags)
+	if diags.HasError() {
+		return
+	}
+	if ok && principal.LastUpdateTimestamp != 0 && principal.LastUpdateTimestamp == lastUpdate {
+		tflog.Debug(ctx, "Polaris principal unchanged since the last refresh", map[string]any{"name": principal.Name})
+
+		return
+	}
+
+	var data polarisPrincipalResourceModel
+	diags.Append(state.Get(ctx, &data)...)
+	if diags.HasError() {
+		return
+	}
+	name := data.Name.ValueString()
+
+	managed := make(map[string]string)
+	if !data.UserProperties.IsNull() && !data.UserProperties.IsUnknown() {
+		diags.Append(data.UserProperties.ElementsAs(ctx, &managed, false)...)
+		if diags.HasError() {
 			return
 		}
-		data.Properties = propsVal
-	} else {
-		data.Properties = types.MapNull(types.StringType)
 	}
 
-	diags = resp.State.Set(ctx, &data)
-	resp.Diagnostics.Append(diags...)
+	if r.provider.warnOnDrift && !data.UserProperties.IsNull() {
+		report := newDriftReport("Polaris principal " + name)
+		report.compareProperties(managed, principal.Properties)
+		report.addTo(diags)
+	}
+
+	// Update properties; credentials are not returned on GET so keep from state.
+	setPrincipalProperties(ctx, &data, managed, principal.Properties, diags)
+	if diags.HasError() {
+		return
+	}
+
+	// Imported principals have no client ID or rotation flag in state yet.
+	// The client ID is read back; the flag only applies when a principal is
+	// created and takes its default, so configurations generated for the
+	// import plan no changes. The secret is only returned on create.
+	if data.ClientID.IsNull() && principal.ClientID != "" {
+		data.ClientID = types.StringValue(principal.ClientID)
+	}
+	if data.CredentialRotationRequired.IsNull() {
+		data.CredentialRotationRequired = types.BoolValue(false)
+	}
+
+	recordPrincipalPrivateState(ctx, principal, private, diags)
+
+	diags.Append(state.Set(ctx, &data)...)
 }
 
 func (r *polarisPrincipalResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !r.provider.checkWritable("iceberg_polaris_principal", "update", &resp.Diagnostics) {
+		return
+	}
+
 	r.ensureManagementClient(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -270,40 +372,51 @@ func (r *polarisPrincipalResource) Update(ctx context.Context, req resource.Upda
 
 	name := state.Name.ValueString()
 
-	// Fetch current entity version from API so we don't require users to track it; Terraform manages it internally.
-	current, err := r.managementClient.GetPrincipal(ctx, name)
-	if err != nil {
-		var nf *polarisNotFoundError
-		if errors.As(err, &nf) {
-			resp.State.RemoveResource(ctx)
-
-			return
-		}
-		resp.Diagnostics.AddError("Failed to read Polaris principal for update", err.Error())
-
+	// The entity version seen on the last refresh is kept in private state, so a
+	// concurrent change since then is rejected by Polaris instead of overwritten.
+	// State written by older provider versions doesn't have it; fetch it instead.
+	// The properties in state are those Polaris returned at that version.
+	server := make(map[string]string)
+	if !state.ServerProperties.IsNull() && !state.ServerProperties.IsUnknown() {
+		resp.Diagnostics.Append(state.ServerProperties.ElementsAs(ctx, &server, false)...)
+	}
+	entityVersion, ok := getPrivateValue[int64](ctx, req.Private, privateKeyEntityVersion, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	if !ok {
+		current, err := r.managementClient.GetPrincipal(ctx, name)
+		if err != nil {
+			if isNotFoundError(err) {
+				resp.State.RemoveResource(ctx)
+
+				return
+			}
+			resp.Diagnostics.AddError("Failed to read Polaris principal for update", err.Error())
 
-	props := make(map[string]string)
-	if !plan.Properties.IsNull() && !plan.Properties.IsUnknown() {
-		diags = plan.Properties.ElementsAs(ctx, &props, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
 			return
 		}
+		entityVersion = current.EntityVersion
+		server = properties.Known(current.Properties)
 	}
 
-	updateReq := polarisUpdatePrincipalRequest{
-		CurrentEntityVersion: current.EntityVersion,
-		Properties:           props,
+	desired := make(map[string]string)
+	if !plan.UserProperties.IsNull() && !plan.UserProperties.IsUnknown() {
+		resp.Diagnostics.Append(plan.UserProperties.ElementsAs(ctx, &desired, false)...)
+	}
+	managed := make(map[string]string)
+	if !state.UserProperties.IsNull() && !state.UserProperties.IsUnknown() {
+		resp.Diagnostics.Append(state.UserProperties.ElementsAs(ctx, &managed, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	tflog.Info(ctx, "Updating Polaris principal", map[string]any{"name": name})
 
-	updated, err := r.managementClient.UpdatePrincipal(ctx, name, updateReq)
+	updated, err := r.updatePrincipal(ctx, name, entityVersion, desired, managed, server)
 	if err != nil {
-		var nf *polarisNotFoundError
-		if errors.As(err, &nf) {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 
 			return
@@ -313,24 +426,74 @@ func (r *polarisPrincipalResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	if len(updated.Properties) > 0 {
-		propsVal, diags := types.MapValueFrom(ctx, types.StringType, updated.Properties)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
-		}
-		state.Properties = propsVal
-	} else {
-		state.Properties = types.MapNull(types.StringType)
+	state.UserProperties = plan.UserProperties
+	setPrincipalProperties(ctx, &state, desired, updated.Properties, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
+	recordPrincipalPrivateState(ctx, updated, resp.Private, &resp.Diagnostics)
+
 	// Polaris doesn't return credentials on update, and setting the secret requires
 	// a dedicated resetCredentials call after create. Preserve everything from state.
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, name, desired, &resp.Diagnostics)
+	}
+}
+
+// verifyApplied reads the principal back and compares its properties with
+// desired, see apply_verification.go.
+func (r *polarisPrincipalResource) verifyApplied(ctx context.Context, name string, desired map[string]string, diags *diag.Diagnostics) {
+	principal, err := r.managementClient.GetPrincipal(ctx, name)
+	if err != nil {
+		diags.AddError("Failed to read Polaris principal after the apply", err.Error())
+
+		return
+	}
+
+	report := newDriftReport("Polaris principal " + name)
+	report.compareProperties(desired, properties.Reconcile(desired, properties.Known(principal.Properties)))
+	report.addMismatchTo(diags)
+}
+
+// updatePrincipal sets the desired properties on the principal and removes
+// the keys of managed that aren't desired. server holds all properties of the
+// principal, as Polaris returned them at entityVersion. Polaris replaces all
+// properties of a principal at once, so the update sends server with the
+// changes applied, leaving out the removed keys, and the returned principal
+// is checked for removed keys Polaris kept.
+func (r *polarisPrincipalResource) updatePrincipal(ctx context.Context, name string, entityVersion int64, desired, managed, server map[string]string) (*polarisPrincipal, error) {
+	delta := properties.ComputeDelta(desired, managed, server)
+
+	updated, err := r.managementClient.UpdatePrincipal(ctx, name, polarisUpdatePrincipalRequest{
+		CurrentEntityVersion: entityVersion,
+		Properties:           properties.Apply(server, delta),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []string
+	for _, k := range delta.Removals {
+		if _, ok := updated.Properties[k]; ok {
+			kept = append(kept, k)
+		}
+	}
+	if len(kept) > 0 {
+		return nil, fmt.Errorf("the update of Polaris principal %s kept the removed properties %s", name, strings.Join(kept, ", "))
+	}
+
+	return updated, nil
 }
 
 func (r *polarisPrincipalResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !r.provider.checkWritable("iceberg_polaris_principal", "delete", &resp.Diagnostics) {
+		return
+	}
+
 	r.ensureManagementClient(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -349,7 +512,7 @@ func (r *polarisPrincipalResource) Delete(ctx context.Context, req resource.Dele
 	tflog.Info(ctx, "Deleting Polaris principal", map[string]any{"name": name})
 
 	err := r.managementClient.DeletePrincipal(ctx, name)
-	if err != nil && !isPolarisNotFoundError(err) {
+	if err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError("Failed to delete Polaris principal", err.Error())
 
 		return
@@ -357,7 +520,35 @@ func (r *polarisPrincipalResource) Delete(ctx context.Context, req resource.Dele
 }
 
 func (r *polarisPrincipalResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Import by principal name; set both id and name.
+	// Import by principal name. user_properties stays null, so no properties
+	// are managed until the configuration lists them.
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_properties"), types.MapNull(types.StringType))...)
+}
+
+// setPrincipalProperties stores the properties of the principal in data.
+// user_properties keeps the managed keys, with the values the server holds,
+// and stays null if it is.
+func setPrincipalProperties(ctx context.Context, data *polarisPrincipalResourceModel, managed, server map[string]string, diags *diag.Diagnostics) {
+	server = properties.Known(server)
+
+	var d diag.Diagnostics
+	if !data.UserProperties.IsNull() {
+		data.UserProperties, d = types.MapValueFrom(ctx, types.StringType, properties.Reconcile(managed, server))
+		diags.Append(d...)
+	}
+	data.ServerProperties, d = types.MapValueFrom(ctx, types.StringType, server)
+	diags.Append(d...)
+	data.ServerManagedProperties, d = types.MapValueFrom(ctx, types.StringType, properties.ServerManaged(managed, server))
+	diags.Append(d...)
+}
+
+// recordPrincipalPrivateState keeps the entity version and last update
+// timestamp of principal in private state. The entity version guards updates
+// against concurrent changes; the timestamp lets refreshes skip principals
+// that didn't change.
+func recordPrincipalPrivateState(ctx context.Context, principal *polarisPrincipal, private privateStateWriter, diags *diag.Diagnostics) {
+	setPrivateValue(ctx, private, privateKeyEntityVersion, principal.EntityVersion, diags)
+	setPrivateValue(ctx, private, privateKeyLastUpdate, principal.LastUpdateTimestamp, diags)
 }
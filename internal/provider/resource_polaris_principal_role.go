@@ -0,0 +1,430 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// polarisReservedPropertyPrefix marks the properties Polaris keeps for its
+// own settings, which the provider never changes.
+const polarisReservedPropertyPrefix = "polaris."
+
+// principalRoleKeys matches the user_properties keys of a principal role,
+// protecting the reserved ones.
+var principalRoleKeys = properties.Keys{ReservedPrefixes: []string{polarisReservedPropertyPrefix}}
+
+// principalRoleView selects the properties of a principal role that
+// user_properties can manage: all but the reserved ones.
+var principalRoleView = polarisPropertyView{
+	managed: principalRoleKeys.WithoutReserved,
+	apply:   properties.Apply,
+}
+
+var (
+	_ resource.Resource                   = &polarisPrincipalRoleResource{}
+	_ resource.ResourceWithImportState    = &polarisPrincipalRoleResource{}
+	_ resource.ResourceWithValidateConfig = &polarisPrincipalRoleResource{}
+)
+
+func NewPolarisPrincipalRoleResource() resource.Resource {
+	return &polarisPrincipalRoleResource{}
+}
+
+// polarisPrincipalRoleResource manages a Polaris principal role and the
+// properties in its configuration, such as those attribute-based access
+// policies match. Properties set by other clients are left alone.
+type polarisPrincipalRoleResource struct {
+	provider         *icebergProvider
+	managementClient *polarisManagementClient
+}
+
+type polarisPrincipalRoleResourceModel struct {
+	ID                      types.String `tfsdk:"id"`
+	Name                    types.String `tfsdk:"name"`
+	UserProperties          types.Map    `tfsdk:"user_properties"`
+	ServerProperties        types.Map    `tfsdk:"server_properties"`
+	ServerManagedProperties types.Map    `tfsdk:"server_managed_properties"`
+}
+
+func (r *polarisPrincipalRoleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_polaris_principal_role"
+}
+
+func (r *polarisPrincipalRoleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A resource for managing a Polaris principal role and its properties, for example those attribute-based access policies match.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the principal role.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user_properties": schema.MapAttribute{
+				Description: "Properties of the principal role, such as env = \"prod\". Only properties listed in Terraform are changed; others on the server stay the same. Keys starting with `polaris.` are reserved by Polaris and can't be set.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"server_properties": schema.MapAttribute{
+				Description: "All properties of the principal role returned by Polaris, including those set through user_properties.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"server_managed_properties": schema.MapAttribute{
+				Description: "Properties set on the principal role by Polaris or other clients, i.e. server_properties without the keys managed through user_properties.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (r *polarisPrincipalRoleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var userProperties types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("user_properties"), &userProperties)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateReservedPropertyKeys(userProperties, &resp.Diagnostics)
+}
+
+// validateReservedPropertyKeys rejects user_properties keys Polaris reserves.
+func validateReservedPropertyKeys(userProperties types.Map, diags *diag.Diagnostics) {
+	if userProperties.IsNull() || userProperties.IsUnknown() {
+		return
+	}
+
+	for _, k := range principalRoleKeys.ReservedKeys(mapKeys(userProperties)) {
+		diags.AddAttributeError(
+			path.Root("user_properties").AtMapKey(k),
+			"Reserved property key",
+			fmt.Sprintf("Properties starting with %q are reserved by Polaris and can't be managed: remove %q from user_properties.", polarisReservedPropertyPrefix, k),
+		)
+	}
+}
+
+func (r *polarisPrincipalRoleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			"Expected *icebergProvider, got a different type: %T. Please report this issue to the provider developers.",
+		)
+	}
+	r.provider = provider
+}
+
+func (r *polarisPrincipalRoleResource) ensureManagementClient(ctx context.Context, diags *diag.Diagnostics) {
+	if r.managementClient != nil {
+		return
+	}
+	if r.provider == nil {
+		diags.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation")
+
+		return
+	}
+	client, err := r.provider.newPolarisManagementClient("iceberg_polaris_principal_role")
+	if err != nil {
+		diags.AddError("Failed to create Polaris management API client", err.Error())
+
+		return
+	}
+	r.managementClient = client
+}
+
+func (r *polarisPrincipalRoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !r.provider.checkWritable("iceberg_polaris_principal_role", "create", &resp.Diagnostics) {
+		return
+	}
+
+	r.ensureManagementClient(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data polarisPrincipalRoleResourceModel
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Name.ValueString()
+	desired := make(map[string]string)
+	if !data.UserProperties.IsNull() {
+		resp.Diagnostics.Append(data.UserProperties.ElementsAs(ctx, &desired, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	tflog.Info(ctx, "Creating Polaris principal role", map[string]any{"name": name})
+
+	err := r.managementClient.CreatePrincipalRole(ctx, polarisCreatePrincipalRoleRequest{
+		PrincipalRole: polarisPrincipalRole{Name: name, Properties: desired},
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create Polaris principal role", err.Error())
+
+		return
+	}
+
+	role, err := r.managementClient.GetPrincipalRole(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read Polaris principal role after create", err.Error())
+
+		return
+	}
+
+	data.ID = types.StringValue(name)
+	r.setProperties(ctx, &data, desired, role.Properties, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, name, desired, &resp.Diagnostics)
+	}
+}
+
+func (r *polarisPrincipalRoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	r.ensureManagementClient(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data polarisPrincipalRoleResourceModel
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Name.ValueString()
+
+	tflog.Info(ctx, "Reading Polaris principal role", map[string]any{"name": name})
+
+	ctx, nulls := withNullProperties(ctx)
+	role, err := r.managementClient.GetPrincipalRole(ctx, name)
+	if err != nil {
+		if isNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read Polaris principal role", err.Error())
+
+		return
+	}
+	nulls.addTo("Polaris principal role "+name, &resp.Diagnostics)
+
+	managed := make(map[string]string)
+	if !data.UserProperties.IsNull() {
+		resp.Diagnostics.Append(data.UserProperties.ElementsAs(ctx, &managed, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if r.provider.warnOnDrift {
+		report := newDriftReport("Polaris principal role " + name)
+		report.compareProperties(managed, role.Properties)
+		report.addTo(&resp.Diagnostics)
+	}
+
+	r.setProperties(ctx, &data, managed, role.Properties, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *polarisPrincipalRoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !r.provider.checkWritable("iceberg_polaris_principal_role", "update", &resp.Diagnostics) {
+		return
+	}
+
+	r.ensureManagementClient(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state polarisPrincipalRoleResourceModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	desired := make(map[string]string)
+	if !plan.UserProperties.IsNull() {
+		resp.Diagnostics.Append(plan.UserProperties.ElementsAs(ctx, &desired, false)...)
+	}
+	managed := make(map[string]string)
+	if !state.UserProperties.IsNull() {
+		resp.Diagnostics.Append(state.UserProperties.ElementsAs(ctx, &managed, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := state.Name.ValueString()
+
+	tflog.Info(ctx, "Updating Polaris principal role", map[string]any{"name": name})
+
+	role, err := r.updateProperties(ctx, name, desired, managed)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update Polaris principal role", err.Error())
+
+		return
+	}
+
+	plan.ID = state.ID
+	r.setProperties(ctx, &plan, desired, role.Properties, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, name, desired, &resp.Diagnostics)
+	}
+}
+
+// verifyApplied reads the principal role back and compares the properties it
+// manages with desired, see apply_verification.go.
+func (r *polarisPrincipalRoleResource) verifyApplied(ctx context.Context, name string, desired map[string]string, diags *diag.Diagnostics) {
+	role, err := r.managementClient.GetPrincipalRole(ctx, name)
+	if err != nil {
+		diags.AddError("Failed to read Polaris principal role after the apply", err.Error())
+
+		return
+	}
+
+	report := newDriftReport("Polaris principal role " + name)
+	report.compareProperties(desired, principalRoleKeys.Reconcile(desired, properties.Known(role.Properties)))
+	report.addMismatchTo(diags)
+}
+
+// updateProperties sets the desired properties on the principal role and
+// removes the keys of managed that aren't desired. The management API
+// replaces all properties of a role at once, so the update is based on the
+// properties just read and retried if the role changed in between. Reserved
+// properties are sent back as they were read.
+func (r *polarisPrincipalRoleResource) updateProperties(ctx context.Context, name string, desired, managed map[string]string) (*polarisPrincipalRole, error) {
+	return updatePolarisProperties(ctx, name, principalRoleView, desired, managed,
+		func(ctx context.Context) (*polarisPrincipalRole, error) {
+			return r.managementClient.GetPrincipalRole(ctx, name)
+		},
+		func(ctx context.Context, entityVersion int64, props map[string]string) (*polarisPrincipalRole, error) {
+			return r.managementClient.UpdatePrincipalRole(ctx, name, polarisUpdatePrincipalRoleRequest{
+				CurrentEntityVersion: entityVersion,
+				Properties:           props,
+			})
+		},
+	)
+}
+
+// setProperties stores the properties of the principal role in data.
+// user_properties keeps the managed keys, with the values the server holds,
+// and stays null if it is.
+func (r *polarisPrincipalRoleResource) setProperties(ctx context.Context, data *polarisPrincipalRoleResourceModel, managed, server map[string]string, diags *diag.Diagnostics) {
+	server = properties.Known(server)
+
+	var d diag.Diagnostics
+	if !data.UserProperties.IsNull() {
+		data.UserProperties, d = types.MapValueFrom(ctx, types.StringType, principalRoleKeys.Reconcile(managed, server))
+		diags.Append(d...)
+	}
+	data.ServerProperties, d = types.MapValueFrom(ctx, types.StringType, server)
+	diags.Append(d...)
+	data.ServerManagedProperties, d = types.MapValueFrom(ctx, types.StringType, principalRoleKeys.ServerManaged(managed, server))
+	diags.Append(d...)
+}
+
+func (r *polarisPrincipalRoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !r.provider.checkWritable("iceberg_polaris_principal_role", "delete", &resp.Diagnostics) {
+		return
+	}
+
+	r.ensureManagementClient(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data polarisPrincipalRoleResourceModel
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Name.ValueString()
+
+	tflog.Info(ctx, "Deleting Polaris principal role", map[string]any{"name": name})
+
+	err := r.managementClient.DeletePrincipalRole(ctx, name)
+	if err != nil && !isNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to delete Polaris principal role", err.Error())
+	}
+}
+
+func (r *polarisPrincipalRoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import by role name. user_properties stays null, so no properties are
+	// managed until the configuration lists them.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_properties"), types.MapNull(types.StringType))...)
+}
@@ -0,0 +1,288 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// polarisPrincipalRoleTestServer serves the principal roles of the management
+// API, whose properties are replaced as a whole on update.
+type polarisPrincipalRoleTestServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	roles map[string]*polarisPrincipalRole
+	// conflicts is the number of updates to reject as concurrent changes.
+	conflicts int
+	updates   int
+}
+
+func newPolarisPrincipalRoleTestServer(t *testing.T) *polarisPrincipalRoleTestServer {
+	t.Helper()
+
+	s := &polarisPrincipalRoleTestServer{roles: make(map[string]*polarisPrincipalRole)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/management/v1/principal-roles", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		var req polarisCreatePrincipalRoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+		if _, ok := s.roles[req.PrincipalRole.Name]; ok {
+			http.Error(w, "principal role already exists", http.StatusConflict)
+
+			return
+		}
+
+		role := req.PrincipalRole
+		role.Properties = maps.Clone(properties.Known(role.Properties))
+		role.EntityVersion = 1
+		s.roles[role.Name] = &role
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("GET /api/management/v1/principal-roles/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		role, ok := s.roles[r.PathValue("name")]
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+		writeJSON(w, http.StatusOK, role)
+	})
+	mux.HandleFunc("PUT /api/management/v1/principal-roles/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		role, ok := s.roles[r.PathValue("name")]
+		if !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+		var req polarisUpdatePrincipalRoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+		if s.conflicts > 0 {
+			// Another client changed the role in the meantime.
+			s.conflicts--
+			role.EntityVersion++
+			role.Properties["other"] = strconv.FormatInt(role.EntityVersion, 10)
+		}
+		if req.CurrentEntityVersion != role.EntityVersion {
+			http.Error(w, "entity version mismatch", http.StatusConflict)
+
+			return
+		}
+
+		s.updates++
+		role.Properties = req.Properties
+		role.EntityVersion++
+		writeJSON(w, http.StatusOK, role)
+	})
+	mux.HandleFunc("DELETE /api/management/v1/principal-roles/{name}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if _, ok := s.roles[r.PathValue("name")]; !ok {
+			http.NotFound(w, r)
+
+			return
+		}
+		delete(s.roles, r.PathValue("name"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// properties returns the properties of the role, nil if it doesn't exist.
+func (s *polarisPrincipalRoleTestServer) properties(name string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	role, ok := s.roles[name]
+	if !ok {
+		return nil
+	}
+
+	return maps.Clone(role.Properties)
+}
+
+// setProperty sets a property like another client would.
+func (s *polarisPrincipalRoleTestServer) setProperty(name, key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	role := s.roles[name]
+	role.Properties[key] = value
+	role.EntityVersion++
+}
+
+func TestValidateReservedPropertyKeys(t *testing.T) {
+	var diags diag.Diagnostics
+	validateReservedPropertyKeys(types.MapValueMust(types.StringType, map[string]attr.Value{
+		"env":                   types.StringValue("prod"),
+		"polaris.internal.type": types.StringValue("x"),
+		"polarisish":            types.StringValue("y"),
+	}), &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Reserved property key", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), `remove "polaris.internal.type"`)
+
+	diags = nil
+	validateReservedPropertyKeys(types.MapUnknown(types.StringType), &diags)
+	validateReservedPropertyKeys(types.MapNull(types.StringType), &diags)
+	assert.False(t, diags.HasError())
+}
+
+func TestPolarisPrincipalRoleUpdateProperties(t *testing.T) {
+	ctx := context.Background()
+	server := newPolarisPrincipalRoleTestServer(t)
+	server.roles["analyst"] = &polarisPrincipalRole{
+		Name:          "analyst",
+		Properties:    map[string]string{"env": "dev", "team": "bi", "polaris.internal.source": "ldap", "owner": "iam"},
+		EntityVersion: 1,
+	}
+	server.conflicts = 2
+
+	p := &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}
+	r := &polarisPrincipalRoleResource{provider: p}
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal_role")
+	require.NoError(t, err)
+	r.managementClient = client
+
+	role, err := r.updateProperties(ctx, "analyst", map[string]string{"env": "prod"}, map[string]string{"env": "dev", "team": "bi"})
+	require.NoError(t, err)
+	want := map[string]string{"env": "prod", "polaris.internal.source": "ldap", "owner": "iam", "other": "3"}
+	assert.Equal(t, want, role.Properties)
+	assert.Equal(t, want, server.properties("analyst"), "reserved and unmanaged properties are kept")
+
+	// Nothing to change: no update is sent.
+	updates := server.updates
+	_, err = r.updateProperties(ctx, "analyst", map[string]string{"env": "prod"}, map[string]string{"env": "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, updates, server.updates)
+
+	// Conflicts beyond the retry limit are returned.
+	server.conflicts = polarisUpdateAttempts
+	_, err = r.updateProperties(ctx, "analyst", map[string]string{"env": "staging"}, nil)
+	require.ErrorIs(t, err, errPolarisConflict)
+
+	_, err = r.updateProperties(ctx, "missing", map[string]string{"env": "prod"}, nil)
+	assert.True(t, isNotFoundError(err))
+}
+
+func testAccPolarisPrincipalRoleConfig(providerCfg, properties string) string {
+	return providerCfg + fmt.Sprintf(`
+resource "iceberg_polaris_principal_role" "analyst" {
+  name = "analyst"
+%s
+}
+`, properties)
+}
+
+func TestAccPolarisPrincipalRole(t *testing.T) {
+	server := newPolarisPrincipalRoleTestServer(t)
+	providerCfg := testAccPolarisProviderConfig(server.URL, server.URL+"/api/management/v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPolarisPrincipalRoleConfig(providerCfg, `  user_properties = { "polaris.internal.owner" = "me" }`),
+				ExpectError: regexp.MustCompile("Reserved property key"),
+			},
+			{
+				Config: testAccPolarisPrincipalRoleConfig(providerCfg, `  user_properties = { env = "prod", team = "bi" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_polaris_principal_role.analyst", "id", "analyst"),
+					resource.TestCheckResourceAttr("iceberg_polaris_principal_role.analyst", "user_properties.env", "prod"),
+					resource.TestCheckResourceAttr("iceberg_polaris_principal_role.analyst", "server_properties.%", "2"),
+					resource.TestCheckResourceAttr("iceberg_polaris_principal_role.analyst", "server_managed_properties.%", "0"),
+					func(_ *terraform.State) error {
+						// Other clients and Polaris itself add properties.
+						server.setProperty("analyst", "owner", "iam")
+						server.setProperty("analyst", "polaris.internal.source", "ldap")
+
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccPolarisPrincipalRoleConfig(providerCfg, `  user_properties = { env = "staging" }`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_polaris_principal_role.analyst", "user_properties.%", "1"),
+					resource.TestCheckResourceAttr("iceberg_polaris_principal_role.analyst", "server_managed_properties.owner", "iam"),
+					func(_ *terraform.State) error {
+						want := map[string]string{"env": "staging", "owner": "iam", "polaris.internal.source": "ldap"}
+						if got := server.properties("analyst"); !maps.Equal(want, got) {
+							return fmt.Errorf("principal role properties: want %v, got %v", want, got)
+						}
+
+						return nil
+					},
+				),
+			},
+			{
+				ResourceName:            "iceberg_polaris_principal_role.analyst",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"user_properties", "server_managed_properties"},
+			},
+		},
+		CheckDestroy: func(_ *terraform.State) error {
+			if props := server.properties("analyst"); props != nil {
+				return fmt.Errorf("principal role analyst still exists with properties %v", props)
+			}
+
+			return nil
+		},
+	})
+}
@@ -16,18 +16,30 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-//lint:ignore U1000 test helper for future use
 func testAccPolarisProviderConfig(catalogURI, managementURI string) string {
 	return testAccPolarisProviderConfigWithToken(catalogURI, managementURI, "")
 }
@@ -38,10 +50,13 @@ func testAccPolarisProviderConfigWithToken(catalogURI, managementURI, token stri
 		tokenAttr = fmt.Sprintf("\n  token = %q", token)
 	}
 
+	// The mocks only serve the management API, and Polaris serves the
+	// catalog below /api/catalog rather than at catalogURI.
 	return fmt.Sprintf(`
 provider "iceberg" {
-  type        = "polaris"
-  catalog_uri = "%s"
+  type                = "polaris"
+  catalog_uri         = "%s"
+  validate_connection = false
 
   polaris_settings {
     management_uri = "%s"
@@ -50,7 +65,6 @@ provider "iceberg" {
 `, catalogURI, managementURI, tokenAttr)
 }
 
-//lint:ignore U1000 test helper for future use
 func newPolarisPrincipalTestServer(t *testing.T) *httptest.Server {
 	t.Helper()
 
@@ -80,9 +94,11 @@ func newPolarisPrincipalTestServer(t *testing.T) *httptest.Server {
 		}
 
 		p := polarisPrincipal{
-			Name:          name,
-			Properties:    req.Principal.Properties,
-			EntityVersion: 1,
+			Name:                name,
+			Properties:          req.Principal.Properties,
+			EntityVersion:       1,
+			ClientID:            "id-" + name,
+			LastUpdateTimestamp: 1700000000000,
 		}
 		principals[name] = p
 
@@ -113,6 +129,33 @@ func newPolarisPrincipalTestServer(t *testing.T) *httptest.Server {
 
 				return
 			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(p)
+		case http.MethodPut:
+			p, ok := principals[name]
+			if !ok {
+				http.NotFound(w, r)
+
+				return
+			}
+
+			var req polarisUpdatePrincipalRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+
+				return
+			}
+			if req.CurrentEntityVersion != p.EntityVersion {
+				http.Error(w, "entity version mismatch", http.StatusConflict)
+
+				return
+			}
+
+			p.Properties = req.Properties
+			p.EntityVersion++
+			p.LastUpdateTimestamp++
+			principals[name] = p
+
 			w.Header().Set("Content-Type", "application/json")
 			_ = json.NewEncoder(w).Encode(p)
 		case http.MethodDelete:
@@ -131,6 +174,221 @@ func newPolarisPrincipalTestServer(t *testing.T) *httptest.Server {
 	return httptest.NewServer(mux)
 }
 
+func testAccPolarisPrincipalMockConfig(providerCfg, team string) string {
+	return providerCfg + fmt.Sprintf(`
+resource "iceberg_polaris_principal" "test" {
+  name = "principal-mock"
+
+  user_properties = {
+    team = %q
+  }
+}
+`, team)
+}
+
+// TestAccPolarisPrincipal_EntityVersionRefresh checks that the entity version
+// kept in private state survives refresh. The mock server rejects updates that
+// don't send the version of the last read.
+func TestAccPolarisPrincipal_EntityVersionRefresh(t *testing.T) {
+	server := newPolarisPrincipalTestServer(t)
+	defer server.Close()
+
+	providerCfg := testAccPolarisProviderConfig(server.URL, server.URL+"/api/management/v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPolarisPrincipalMockConfig(providerCfg, "data"),
+			},
+			{
+				RefreshState: true,
+			},
+			{
+				Config: testAccPolarisPrincipalMockConfig(providerCfg, "platform"),
+				Check:  resource.TestCheckResourceAttr("iceberg_polaris_principal.test", "user_properties.team", "platform"),
+			},
+			{
+				Config: testAccPolarisPrincipalMockConfig(providerCfg, "analytics"),
+				Check:  resource.TestCheckResourceAttr("iceberg_polaris_principal.test", "user_properties.team", "analytics"),
+			},
+		},
+	})
+}
+
+// TestAccPolarisPrincipal_EntityVersionImport checks that an imported principal
+// can be updated right away, i.e. import populates the private state.
+func TestAccPolarisPrincipal_EntityVersionImport(t *testing.T) {
+	server := newPolarisPrincipalTestServer(t)
+	defer server.Close()
+
+	body := `{"principal": {"name": "principal-mock", "properties": {"team": "data"}}}`
+	res, err := http.Post(server.URL+"/api/management/v1/principals", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	providerCfg := testAccPolarisProviderConfig(server.URL, server.URL+"/api/management/v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				ResourceName:       "iceberg_polaris_principal.test",
+				Config:             testAccPolarisPrincipalMockConfig(providerCfg, "data"),
+				ImportState:        true,
+				ImportStateId:      "principal-mock",
+				ImportStatePersist: true,
+			},
+			{
+				Config: testAccPolarisPrincipalMockConfig(providerCfg, "platform"),
+				Check:  resource.TestCheckResourceAttr("iceberg_polaris_principal.test", "user_properties.team", "platform"),
+			},
+		},
+	})
+}
+
+// principalStateBytes returns the msgpack encoding of state, the form in
+// which Terraform stores it.
+func principalStateBytes(t *testing.T, state tfsdk.State) []byte {
+	t.Helper()
+
+	value, err := tfprotov6.NewDynamicValue(state.Schema.Type().TerraformType(context.Background()), state.Raw)
+	require.NoError(t, err)
+
+	return value.MsgPack
+}
+
+func TestPolarisPrincipalReadSkipsUnchanged(t *testing.T) {
+	ctx := context.Background()
+	r := &polarisPrincipalResource{provider: &icebergProvider{warnOnDrift: true}}
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+
+	var diags diag.Diagnostics
+	diags.Append(state.Set(ctx, &polarisPrincipalResourceModel{
+		ID:                         types.StringValue("etl"),
+		Name:                       types.StringValue("etl"),
+		UserProperties:             types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("data")}),
+		ServerProperties:           types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("data")}),
+		ServerManagedProperties:    types.MapValueMust(types.StringType, map[string]attr.Value{}),
+		CredentialRotationRequired: types.BoolValue(false),
+		ClientID:                   types.StringValue("id-etl"),
+		ClientSecret:               types.StringValue("secret-etl"),
+	})...)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	private := fakePrivateState{}
+	principal := &polarisPrincipal{Name: "etl", Properties: map[string]string{"team": "data"}, EntityVersion: 3, LastUpdateTimestamp: 1700000000000}
+	r.readPrincipal(ctx, principal, &state, private, private, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	stored := principalStateBytes(t, state)
+
+	t.Run("unchanged principal", func(t *testing.T) {
+		// Properties the timestamp says didn't change aren't even looked at.
+		unchanged := *principal
+		unchanged.Properties = map[string]string{"team": "ignored"}
+
+		var diags diag.Diagnostics
+		r.readPrincipal(ctx, &unchanged, &state, private, private, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Empty(t, diags.Warnings())
+		assert.Equal(t, stored, principalStateBytes(t, state))
+	})
+
+	t.Run("updated principal", func(t *testing.T) {
+		updated := *principal
+		updated.Properties = map[string]string{"team": "platform"}
+		updated.EntityVersion = 4
+		updated.LastUpdateTimestamp++
+
+		var diags diag.Diagnostics
+		r.readPrincipal(ctx, &updated, &state, private, private, &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Len(t, diags.Warnings(), 1, "the drift is reported")
+
+		var props types.Map
+		diags.Append(state.GetAttribute(ctx, path.Root("user_properties"), &props)...)
+		assert.Equal(t, types.StringValue("platform"), props.Elements()["team"])
+
+		version, _ := getPrivateValue[int64](ctx, private, privateKeyEntityVersion, &diags)
+		lastUpdate, _ := getPrivateValue[int64](ctx, private, privateKeyLastUpdate, &diags)
+		assert.Equal(t, int64(4), version)
+		assert.Equal(t, updated.LastUpdateTimestamp, lastUpdate)
+	})
+}
+
+func TestPolarisPrincipalUpdateProperties(t *testing.T) {
+	ctx := context.Background()
+	server := newPolarisPrincipalTestServer(t)
+	t.Cleanup(server.Close)
+
+	p := &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+	r := &polarisPrincipalResource{provider: p, managementClient: client}
+
+	created, err := client.CreatePrincipal(ctx, polarisCreatePrincipalRequest{
+		Principal: polarisPrincipal{Name: "etl", Properties: map[string]string{"team": "data", "tier": "gold", "owner": "iam"}},
+	})
+	require.NoError(t, err)
+
+	// owner is set by another client and never managed.
+	managed := map[string]string{"team": "data", "tier": "gold"}
+	serverProps := created.Principal.Properties
+	version := created.Principal.EntityVersion
+	for _, step := range []struct {
+		name    string
+		desired map[string]string
+	}{
+		{name: "add", desired: map[string]string{"team": "data", "tier": "gold", "purpose": "ingest"}},
+		{name: "change", desired: map[string]string{"team": "platform", "tier": "gold", "purpose": "ingest"}},
+		{name: "remove", desired: map[string]string{"team": "platform"}},
+		{name: "remove all", desired: map[string]string{}},
+	} {
+		updated, err := r.updatePrincipal(ctx, "etl", version, step.desired, managed, serverProps)
+		require.NoError(t, err, step.name)
+
+		stored, err := client.GetPrincipal(ctx, "etl")
+		require.NoError(t, err, step.name)
+		want := map[string]string{"owner": "iam"}
+		maps.Copy(want, step.desired)
+		assert.Equal(t, want, properties.Known(stored.Properties), step.name)
+		assert.Equal(t, stored.Properties, updated.Properties, step.name)
+
+		managed = step.desired
+		serverProps = updated.Properties
+		version = updated.EntityVersion
+	}
+}
+
+func TestPolarisPrincipalUpdateKeepsRemovedProperty(t *testing.T) {
+	// A server that ignores removals must not make the update look successful.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(polarisPrincipal{
+			Name:          "etl",
+			Properties:    map[string]string{"team": "data", "tier": "gold"},
+			EntityVersion: 2,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	p := &icebergProvider{polaris: &polarisConfig{managementURI: server.URL + "/api/management/v1"}}
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+	r := &polarisPrincipalResource{provider: p, managementClient: client}
+
+	_, err = r.updatePrincipal(context.Background(), "etl", 1, map[string]string{"team": "data"}, map[string]string{"team": "data", "tier": "gold"}, map[string]string{"team": "data", "tier": "gold"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "kept the removed properties tier")
+}
+
 // TestAccPolarisPrincipal_Full runs against a real Polaris deployment
 func TestAccPolarisPrincipal_Full(t *testing.T) {
 	catalogURI := os.Getenv("POLARIS_CATALOG_URI")
@@ -151,14 +409,14 @@ func TestAccPolarisPrincipal_Full(t *testing.T) {
 resource "iceberg_polaris_principal" "test" {
   name = "principal-real"
 
-  properties = {
+  user_properties = {
     team = "data"
   }
 }
 `,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_polaris_principal.test", "name", "principal-real"),
-					resource.TestCheckResourceAttr("iceberg_polaris_principal.test", "properties.team", "data"),
+					resource.TestCheckResourceAttr("iceberg_polaris_principal.test", "user_properties.team", "data"),
 					resource.TestCheckResourceAttrSet("iceberg_polaris_principal.test", "client_id"),
 					resource.TestCheckResourceAttrSet("iceberg_polaris_principal.test", "client_secret"),
 				),
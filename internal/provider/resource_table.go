@@ -19,17 +19,23 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/catalog"
 	"github.com/apache/iceberg-go/table"
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	rscschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -38,21 +44,58 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var _ resource.Resource = &icebergTableResource{}
+var (
+	_ resource.Resource                     = &icebergTableResource{}
+	_ resource.ResourceWithModifyPlan       = &icebergTableResource{}
+	_ resource.ResourceWithUpgradeState     = &icebergTableResource{}
+	_ resource.ResourceWithValidateConfig   = &icebergTableResource{}
+	_ resource.ResourceWithConfigValidators = &icebergTableResource{}
+)
 
 func NewTableResource() resource.Resource {
 	return &icebergTableResource{}
 }
 
 type icebergTableResourceModel struct {
-	ID               types.String `tfsdk:"id"`
-	Namespace        types.List   `tfsdk:"namespace"`
-	Name             types.String `tfsdk:"name"`
-	Schema           types.Object `tfsdk:"schema"`
-	PartitionSpec    types.Object `tfsdk:"partition_spec"`
-	SortOrder        types.Object `tfsdk:"sort_order"`
-	UserProperties   types.Map    `tfsdk:"user_properties"`
-	ServerProperties types.Map    `tfsdk:"server_properties"`
+	ID                       types.String `tfsdk:"id"`
+	Namespace                types.List   `tfsdk:"namespace"`
+	Name                     types.String `tfsdk:"name"`
+	Comment                  types.String `tfsdk:"comment"`
+	Owner                    types.String `tfsdk:"owner"`
+	RowLevelOperationMode    types.String `tfsdk:"row_level_operation_mode"`
+	EngineHints              types.Object `tfsdk:"engine_hints"`
+	Schema                   types.Object `tfsdk:"schema"`
+	ServerSchema             types.Object `tfsdk:"server_schema"`
+	PartitionSpec            types.Object `tfsdk:"partition_spec"`
+	SortOrder                types.Object `tfsdk:"sort_order"`
+	WriteOrder               types.List   `tfsdk:"write_order"`
+	ForceRequiredAdd         types.Bool   `tfsdk:"force_required_add"`
+	PurgeOnDestroy           types.Bool   `tfsdk:"purge_on_destroy"`
+	OverrideGCCheck          types.Bool   `tfsdk:"override_gc_check"`
+	External                 types.Bool   `tfsdk:"external"`
+	MetadataLocation         types.String `tfsdk:"metadata_location"`
+	CreateNamespaceIfMissing types.Bool   `tfsdk:"create_namespace_if_missing"`
+	PurgeSoftDeleted         types.Bool   `tfsdk:"purge_soft_deleted"`
+	RequiredFeatures         types.List   `tfsdk:"required_features"`
+	LastCommitTimestampMs    types.Int64  `tfsdk:"last_commit_timestamp_ms"`
+	MaxStalenessCheck        types.String `tfsdk:"max_staleness_check"`
+	UserProperties           types.Map    `tfsdk:"user_properties"`
+	ServerProperties         types.Map    `tfsdk:"server_properties"`
+	ServerManagedProperties  types.Map    `tfsdk:"server_managed_properties"`
+	TrackServerProperties    types.Bool   `tfsdk:"track_server_properties"`
+	TrackStatistics          types.Bool   `tfsdk:"track_statistics"`
+	CatalogName              types.String `tfsdk:"catalog_name"`
+	// SensitivePropertyKeys lists the properties that are managed and shown
+	// through the sensitive maps, see sensitive_properties.go.
+	SensitivePropertyKeys     types.Set `tfsdk:"sensitive_property_keys"`
+	SensitiveUserProperties   types.Map `tfsdk:"sensitive_user_properties"`
+	SensitiveServerProperties types.Map `tfsdk:"sensitive_server_properties"`
+}
+
+// withAttributeProperties returns props with the properties set by the
+// comment, owner, row_level_operation_mode and engine_hints attributes.
+func (m *icebergTableResourceModel) withAttributeProperties(props map[string]string) map[string]string {
+	return withEngineHints(withRowLevelOperationMode(withOwner(withComment(props, m.Comment), m.Owner), m.RowLevelOperationMode), m.EngineHints)
 }
 
 type icebergTableResource struct {
@@ -66,10 +109,12 @@ func (r *icebergTableResource) Metadata(_ context.Context, req resource.Metadata
 
 func (r *icebergTableResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = rscschema.Schema{
+		Version:     2,
 		Description: "A resource for managing Iceberg tables.",
 		Attributes: map[string]rscschema.Attribute{
 			"id": rscschema.StringAttribute{
-				Computed: true,
+				Description: "The identifier of the table in the catalog, its namespace levels and name joined by '.', including the name_prefix of the provider.",
+				Computed:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -89,9 +134,29 @@ func (r *icebergTableResource) Schema(_ context.Context, _ resource.SchemaReques
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"comment": rscschema.StringAttribute{
+				Description: "The description of the table, stored in its comment property. Conflicts with a comment key in user_properties. If unset, a comment written by other clients is left alone.",
+				Optional:    true,
+			},
+			"owner": rscschema.StringAttribute{
+				Description: "The owner of the table, stored in its owner property. Conflicts with an owner key in user_properties. If unset, an owner written by other clients is left alone. With type = 'polaris', planning an owner that isn't a Polaris principal emits a warning unless skip_owner_validation is set.",
+				Optional:    true,
+			},
+			"row_level_operation_mode": rscschema.StringAttribute{
+				Description: "How deletes, updates and merges change the table, 'copy-on-write' or 'merge-on-read'. Sets the write.delete.mode, write.update.mode and write.merge.mode properties, which then can't be set in user_properties. If unset, modes written by other clients are left alone.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(rowLevelModeCopyOnWrite, rowLevelModeMergeOnRead),
+				},
+			},
+			"engine_hints": engineHintsAttribute(),
 			"schema": rscschema.SingleNestedAttribute{
-				Description: "The schema of the table.",
-				Required:    true,
+				Description: "The schema of the table. Required unless external is true; the schema of external tables is read from the catalog and can't be set.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.UseStateForUnknown(),
+				},
 				Attributes: map[string]rscschema.Attribute{
 					"id": rscschema.Int64Attribute{
 						Description: "The schema ID.",
@@ -102,11 +167,26 @@ func (r *icebergTableResource) Schema(_ context.Context, _ resource.SchemaReques
 						Description: "The fields of the schema",
 						Required:    true,
 						NestedObject: rscschema.NestedAttributeObject{
-							Attributes: schemaFieldAttributes(4),
+							Attributes: schemaFieldAttributes(schemaMaxDepth),
 						},
 					},
 				},
 			},
+			"server_schema": rscschema.SingleNestedAttribute{
+				Description: "The current schema of the table as the catalog has it, including the field docs and initial defaults that schema leaves unset.",
+				Computed:    true,
+				Attributes: computedAttributes(map[string]rscschema.Attribute{
+					"id": rscschema.Int64Attribute{
+						Description: "The schema ID.",
+					},
+					"fields": rscschema.ListNestedAttribute{
+						Description: "The fields of the schema.",
+						NestedObject: rscschema.NestedAttributeObject{
+							Attributes: schemaFieldAttributes(schemaMaxDepth),
+						},
+					},
+				}),
+			},
 			"partition_spec": rscschema.SingleNestedAttribute{
 				Description: "The partition spec of the table.",
 				Optional:    true,
@@ -185,6 +265,61 @@ func (r *icebergTableResource) Schema(_ context.Context, _ resource.SchemaReques
 					},
 				},
 			},
+			"write_order": rscschema.ListAttribute{
+				Description: "A shorthand for sort_order: the sort columns in order, each optionally followed by asc or desc and nulls_first or nulls_last, e.g. [\"event_date\", \"user_id desc\"]. Columns are sorted ascending by default; nulls come first when ascending and last when descending. Conflicts with sort_order, which is computed from this list.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ConflictsWith(path.MatchRoot("sort_order")),
+					listvalidator.ValueStringsAre(stringvalidator.RegexMatches(writeOrderTermPattern, "must be a column name followed by optional asc, desc, nulls_first or nulls_last")),
+				},
+			},
+			"required_features": rscschema.ListAttribute{
+				Description: "Format features the table must support, from " + strings.Join(tableFeatureKeys(), ", ") + ". " +
+					"Planning fails if the format version of the table, or the format-version in user_properties, is too low for one of them, " +
+					"and creating the table fails if the catalog creates it with a lower format version, instead of the feature silently having no effect.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(tableFeatureKeys()...)),
+				},
+			},
+			"force_required_add": rscschema.BoolAttribute{
+				Description: "Allow adding required fields without an initial_default to a table that has rows. Readers may fail on the existing rows, which have no value for the new fields.",
+				Optional:    true,
+			},
+			"create_namespace_if_missing": rscschema.BoolAttribute{
+				Description: "If true, creating the table creates its namespace, and missing parent namespaces, with empty properties when it doesn't exist, with a warning. The namespace isn't managed by the table and is left in place when the table is destroyed. Defaults to false.",
+				Optional:    true,
+			},
+			"purge_soft_deleted": rscschema.BoolAttribute{
+				Description: "If true and the catalog is Lakekeeper, a create that fails because a soft-deleted table has the same name purges that table, deleting its files, and creates the table again. Without it, such a create fails with an explanation. Defaults to false.",
+				Optional:    true,
+			},
+			"purge_on_destroy": rscschema.BoolAttribute{
+				Description: "If true, destroying the table asks the catalog to purge it, deleting its data and metadata files. Tables with gc.enabled=false or external=true are dropped without purging, with a warning, unless override_gc_check is set.",
+				Optional:    true,
+			},
+			"override_gc_check": rscschema.BoolAttribute{
+				Description: "If true, purge_on_destroy purges the table even if its gc.enabled property is false or its external property is true. Purging such a table can delete files that other tables or systems still use.",
+				Optional:    true,
+			},
+			"external": rscschema.BoolAttribute{
+				Description: "If true, the table is owned by another system and Terraform only manages its registration in the catalog and its properties. The table is registered from metadata_location, its schema, partition spec and sort order are read from the catalog and can't be set, purge_on_destroy isn't allowed and destroying it only removes it from the catalog. Defaults to false.",
+				Optional:    true,
+			},
+			"metadata_location": rscschema.StringAttribute{
+				Description: "The location of the metadata file an external table is registered from. Required if external is true. It is only read when the table is registered; later commits by the system that owns the table don't change it.",
+				Optional:    true,
+			},
+			"last_commit_timestamp_ms": rscschema.Int64Attribute{
+				Description: "The time of the last commit to the table, the timestamp of its current snapshot in milliseconds since the epoch. Null if the table has no snapshots.",
+				Computed:    true,
+			},
+			"max_staleness_check": rscschema.StringAttribute{
+				Description: "A duration such as 36h. If set, refreshing the table emits a warning when its last commit is older than this, or it has no commits. Stale tables never fail a refresh; use last_commit_timestamp_ms in a postcondition for that.",
+				Optional:    true,
+			},
 			"user_properties": rscschema.MapAttribute{
 				Description: "User-defined properties for the table.",
 				Optional:    true,
@@ -195,6 +330,43 @@ func (r *icebergTableResource) Schema(_ context.Context, _ resource.SchemaReques
 				Computed:    true,
 				ElementType: types.StringType,
 			},
+			"server_managed_properties": rscschema.MapAttribute{
+				Description: "Properties set on the table by the server or other clients, i.e. server_properties without the keys managed through user_properties.",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"track_server_properties": rscschema.BoolAttribute{
+				Description: "If false, server_properties, server_managed_properties and sensitive_server_properties aren't stored and are null, which keeps properties that other clients change often out of plans and state. The properties are still read for user_properties. Setting it back to true fills them in again on apply. Defaults to true.",
+				Optional:    true,
+			},
+			"track_statistics": rscschema.BoolAttribute{
+				Description: "If false, last_commit_timestamp_ms isn't stored and is null, so commits by writers don't show up in plans. Can't be false with max_staleness_check. Setting it back to true fills it in again on apply. Defaults to true.",
+				Optional:    true,
+			},
+			"catalog_name": rscschema.StringAttribute{
+				Description: catalogNameDescription,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sensitive_property_keys": rscschema.SetAttribute{
+				Description: "Property keys whose values are sensitive, such as encryption.key-id. They are set through sensitive_user_properties and shown in sensitive_server_properties instead of user_properties, server_properties and server_managed_properties.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"sensitive_user_properties": rscschema.MapAttribute{
+				Description: "User-defined properties whose keys are listed in sensitive_property_keys.",
+				Optional:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+			},
+			"sensitive_server_properties": rscschema.MapAttribute{
+				Description: "Properties returned by the server whose keys are listed in sensitive_property_keys.",
+				Computed:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+			},
 		},
 	}
 }
@@ -202,8 +374,9 @@ func (r *icebergTableResource) Schema(_ context.Context, _ resource.SchemaReques
 func schemaFieldAttributes(depth int) map[string]rscschema.Attribute {
 	attrs := map[string]rscschema.Attribute{
 		"id": rscschema.Int64Attribute{
-			Description: "The field ID.",
+			Description: "The field ID. Assigned by the catalog if not set. Field IDs can't be changed once assigned, but the ID of an existing field can be set to the assigned ID or removed again without changes to the table.",
 			Optional:    true,
+			Computed:    true,
 		},
 		"name": rscschema.StringAttribute{
 			Description: "The field name.",
@@ -218,7 +391,11 @@ func schemaFieldAttributes(depth int) map[string]rscschema.Attribute {
 			Required:    true,
 		},
 		"doc": rscschema.StringAttribute{
-			Description: "The field documentation.",
+			Description: "The field documentation. If unset, a doc written by other clients or filled in by the catalog is left alone and only shown in server_schema; set it to \"\" to remove one.",
+			Optional:    true,
+		},
+		"initial_default": rscschema.StringAttribute{
+			Description: "The value of the field in rows written before the field was added, e.g. '0', 'true' or 'unknown'. Required to add a required field to a table that has rows, unless force_required_add is set. If unset, the initial default of an existing field is left alone and only shown in server_schema.",
 			Optional:    true,
 		},
 		"list_properties": rscschema.SingleNestedAttribute{
@@ -301,6 +478,73 @@ func schemaFieldAttributes(depth int) map[string]rscschema.Attribute {
 	return attrs
 }
 
+// computedAttributes returns attrs, and the attributes nested in them, as
+// computed-only attributes, for outputs that mirror configurable attributes.
+func computedAttributes(attrs map[string]rscschema.Attribute) map[string]rscschema.Attribute {
+	result := make(map[string]rscschema.Attribute, len(attrs))
+	for name, a := range attrs {
+		switch a := a.(type) {
+		case rscschema.Int64Attribute:
+			result[name] = rscschema.Int64Attribute{Description: a.Description, Computed: true}
+		case rscschema.StringAttribute:
+			result[name] = rscschema.StringAttribute{Description: a.Description, Computed: true}
+		case rscschema.BoolAttribute:
+			result[name] = rscschema.BoolAttribute{Description: a.Description, Computed: true}
+		case rscschema.SingleNestedAttribute:
+			result[name] = rscschema.SingleNestedAttribute{Description: a.Description, Computed: true, Attributes: computedAttributes(a.Attributes)}
+		case rscschema.ListNestedAttribute:
+			result[name] = rscschema.ListNestedAttribute{
+				Description:  a.Description,
+				Computed:     true,
+				NestedObject: rscschema.NestedAttributeObject{Attributes: computedAttributes(a.NestedObject.Attributes)},
+			}
+		default:
+			panic(fmt.Sprintf("computedAttributes: unsupported attribute type %T", a))
+		}
+	}
+
+	return result
+}
+
+func (r *icebergTableResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var current resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &current)
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   priorSchemaWithout(current.Schema, 0, "server_managed_properties", "write_order", "force_required_add", "comment", "sensitive_property_keys", "sensitive_user_properties", "sensitive_server_properties", "owner", "purge_on_destroy", "override_gc_check", "create_namespace_if_missing", "purge_soft_deleted", "required_features", "last_commit_timestamp_ms", "max_staleness_check", "catalog_name", "row_level_operation_mode", "external", "metadata_location", "engine_hints", "server_schema", "track_server_properties", "track_statistics"),
+			StateUpgrader: upgradeTableState,
+		},
+		1: {
+			PriorSchema:   priorSchemaWithout(current.Schema, 1, "server_schema", "track_server_properties", "track_statistics"),
+			StateUpgrader: upgradeServerSchema,
+		},
+	}
+}
+
+func (r *icebergTableResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		schemaPoliciesValidator{provider: r.provider},
+	}
+}
+
+func (r *icebergTableResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data icebergTableResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateCommentConfig(data.Comment, data.UserProperties, &resp.Diagnostics)
+	validateOwnerConfig(data.Owner, data.UserProperties, &resp.Diagnostics)
+	validateRowLevelOperationModeConfig(data.RowLevelOperationMode, data.UserProperties, &resp.Diagnostics)
+	validateEngineHintsConfig(data.EngineHints, data.UserProperties, &resp.Diagnostics)
+	validateSensitivePropertiesConfig(ctx, data.SensitivePropertyKeys, data.UserProperties, data.SensitiveUserProperties, &resp.Diagnostics)
+	validateMaxStalenessConfig(data.MaxStalenessCheck, &resp.Diagnostics)
+	validateExternalConfig(&data, &resp.Diagnostics)
+	validateTrackingConfig(&data, &resp.Diagnostics)
+}
+
 func (r *icebergTableResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
@@ -333,13 +577,13 @@ func (r *icebergTableResource) ConfigureCatalog(ctx context.Context, diags *diag
 		return
 	}
 
-	if r.provider.catalogURI == "" {
+	if !r.provider.catalogConfigured() {
 		// The provider might not be fully configured yet (e.g. during plan if URI is unknown)
 
 		return
 	}
 
-	catalog, err := r.provider.NewCatalog(ctx)
+	catalog, err := r.provider.NewCatalog(ctx, "iceberg_table")
 	if err != nil {
 		diags.AddError(
 			"Failed to create catalog",
@@ -352,6 +596,11 @@ func (r *icebergTableResource) ConfigureCatalog(ctx context.Context, diags *diag
 }
 
 func (r *icebergTableResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !r.provider.checkWritable("iceberg_table", "create", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_table")
 	r.ConfigureCatalog(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -366,94 +615,286 @@ func (r *icebergTableResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
-	var namespaceName []string
-	diags = data.Namespace.ElementsAs(ctx, &namespaceName, false)
-	resp.Diagnostics.Append(diags...)
+	if data.External.ValueBool() {
+		r.createExternal(ctx, &data, resp)
+
+		return
+	}
+	intended := data
+
+	args := tableCreateArgsFromModel(ctx, &data, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	tableIdent := r.provider.prefixTable(args.ident)
+
+	tbl, err := r.createTable(ctx, tableIdent, args)
+	if errors.Is(err, catalog.ErrNoSuchNamespace) && data.CreateNamespaceIfMissing.ValueBool() {
+		// Only done after createTable retried the missing namespace, so a
+		// sibling iceberg_namespace created in the same apply usually wins.
+		namespaceIdent := catalog.NamespaceFromIdent(tableIdent)
+		if err = createMissingNamespace(ctx, r.catalog, namespaceIdent); err == nil {
+			resp.Diagnostics.AddWarning(
+				"Namespace created",
+				fmt.Sprintf("The namespace %s of table %s didn't exist and was created with empty properties because create_namespace_if_missing is set. It isn't managed by Terraform and is left in place when the table is destroyed.",
+					encodeIdentifier(namespaceIdent), encodeIdentifier(tableIdent)),
+			)
+			tbl, err = r.createTable(ctx, tableIdent, args)
+		}
+	}
+	if err != nil && r.isSoftDeletedConflict(ctx, tableIdent, err) {
+		if !data.PurgeSoftDeleted.ValueBool() {
+			resp.Diagnostics.AddError("Table name held by a soft-deleted table", softDeletedConflictDetail(tableIdent, err))
+
+			return
+		}
+		if err = r.provider.purgeSoftDeletedTable(ctx, "iceberg_table", tableIdent); err == nil {
+			resp.Diagnostics.AddWarning(
+				"Soft-deleted table purged",
+				fmt.Sprintf("A soft-deleted table named %s was purged, with its files, because purge_soft_deleted is set.", encodeIdentifier(tableIdent)),
+			)
+			tbl, err = r.createTable(ctx, tableIdent, args)
+		}
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("failed to create table", err.Error())
+
+		return
+	}
 
-	tableName := data.Name.ValueString()
-	tableIdent := append(namespaceName, tableName)
+	tbl, mismatches := r.verifyCreatedSchema(ctx, tableIdent, args.schema, tbl)
+	if len(mismatches) > 0 {
+		resp.Diagnostics.AddError(
+			"Catalog returned a different schema",
+			fmt.Sprintf("The table %s was created, but the catalog returned a schema that differs from the one sent:\n\n- %s\n\n"+
+				"The table isn't added to the state so the difference isn't persisted. Check the catalog and any proxy in front of it, "+
+				"then import the table or drop it and apply again.",
+				encodeIdentifier(tableIdent), strings.Join(mismatches, "\n- ")),
+		)
 
-	var schema icebergTableSchema
-	diags = data.Schema.As(ctx, &schema, basetypes.ObjectAsOptions{})
+		return
+	}
+
+	verifyCreatedFeatures(ctx, data.RequiredFeatures, tbl, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(encodeIdentifier(tableIdent))
+
+	r.syncTableToModel(ctx, tbl, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	recordTablePrivateState(ctx, tbl, resp.Private, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, tableIdent, &intended, &resp.Diagnostics)
+	}
+}
+
+// createExternal registers the external table planned in data and stores it
+// in the state of resp.
+func (r *icebergTableResource) createExternal(ctx context.Context, data *icebergTableResourceModel, resp *resource.CreateResponse) {
+	ident, ok := r.plannedIdentifier(ctx, data.Namespace, data.Name, &resp.Diagnostics)
+	if !ok {
+		return
+	}
+
+	intended := *data
+	tbl := r.registerExternalTable(ctx, ident, data, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tblSchema, err := schema.ToIceberg()
-	if err != nil {
-		resp.Diagnostics.AddError("failed to convert schema", err.Error())
+	validateRequiredFeatures(ctx, data, tbl, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	data.ID = types.StringValue(encodeIdentifier(ident))
+
+	r.syncTableToModel(ctx, tbl, data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	userProps := make(map[string]string)
+	recordTablePrivateState(ctx, tbl, resp.Private, &resp.Diagnostics)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, ident, &intended, &resp.Diagnostics)
+	}
+}
+
+// createTable creates the table described by args as ident. Tables created
+// in parallel in a new namespace race each other and the namespace creation,
+// see createWithRetry.
+func (r *icebergTableResource) createTable(ctx context.Context, ident table.Identifier, args *tableCreateArgs) (*table.Table, error) {
+	var tbl *table.Table
+	err := createWithRetry(ctx, "table "+encodeIdentifier(ident), true,
+		func() error {
+			var err error
+			tbl, err = r.catalog.CreateTable(ctx, ident, args.schema, args.options()...)
+
+			return err
+		},
+		func() (bool, error) {
+			var err error
+			tbl, err = r.catalog.LoadTable(ctx, ident)
+			if err != nil {
+				return false, err
+			}
+
+			return isCreatedTable(tbl, args.schema), nil
+		},
+	)
+
+	return tbl, err
+}
+
+// tableCreateArgs holds what is sent to the catalog to create a table.
+type tableCreateArgs struct {
+	ident      table.Identifier
+	schema     *iceberg.Schema
+	spec       *iceberg.PartitionSpec
+	sortOrder  *table.SortOrder
+	properties map[string]string
+}
+
+// tableCreateArgsFromModel converts the planned table into the arguments of a
+// create request. The namespace and the name must be known: an unknown value
+// would otherwise turn into an empty level of the identifier.
+func tableCreateArgsFromModel(ctx context.Context, data *icebergTableResourceModel, diags *diag.Diagnostics) *tableCreateArgs {
+	if !fullyKnown(ctx, data.Namespace, data.Name) {
+		diags.AddError("Unknown table identifier",
+			"The namespace or the name of the table isn't known, so the table can't be created. Terraform passes known values to an apply, so please report this.")
+
+		return nil
+	}
+
+	var namespaceName []string
+	diags.Append(data.Namespace.ElementsAs(ctx, &namespaceName, false)...)
+	if diags.HasError() {
+		return nil
+	}
+
+	args := &tableCreateArgs{
+		ident:      append(namespaceName, data.Name.ValueString()),
+		properties: make(map[string]string),
+	}
+
+	var schema icebergTableSchema
+	diags.Append(data.Schema.As(ctx, &schema, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil
+	}
+
+	var err error
+	args.schema, err = schema.ToIceberg()
+	if err != nil {
+		diags.AddError("failed to convert schema", err.Error())
+
+		return nil
+	}
+
 	if !data.UserProperties.IsNull() && !data.UserProperties.IsUnknown() {
-		diags = data.UserProperties.ElementsAs(ctx, &userProps, false)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
+		diags.Append(data.UserProperties.ElementsAs(ctx, &args.properties, false)...)
+		if diags.HasError() {
+			return nil
 		}
 	}
-
-	createOpts := []catalog.CreateTableOpt{
-		catalog.WithProperties(userProps),
+	if !data.SensitiveUserProperties.IsNull() && !data.SensitiveUserProperties.IsUnknown() {
+		sensitive := make(map[string]string)
+		diags.Append(data.SensitiveUserProperties.ElementsAs(ctx, &sensitive, false)...)
+		if diags.HasError() {
+			return nil
+		}
+		args.properties = withSensitiveProperties(args.properties, sensitive)
 	}
+	args.properties = data.withAttributeProperties(args.properties)
 
 	if !data.PartitionSpec.IsNull() && !data.PartitionSpec.IsUnknown() {
 		var spec icebergTablePartitionSpec
-		diags = data.PartitionSpec.As(ctx, &spec, basetypes.ObjectAsOptions{})
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
+		diags.Append(data.PartitionSpec.As(ctx, &spec, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return nil
 		}
 		icebergSpec, err := spec.ToIceberg()
 		if err != nil {
-			resp.Diagnostics.AddError("failed to convert partition spec", err.Error())
+			diags.AddError("failed to convert partition spec", err.Error())
 
-			return
+			return nil
 		}
-		createOpts = append(createOpts, catalog.WithPartitionSpec(icebergSpec))
+		args.spec = icebergSpec
+	}
+
+	if !data.WriteOrder.IsNull() && !data.WriteOrder.IsUnknown() {
+		var terms []string
+		diags.Append(data.WriteOrder.ElementsAs(ctx, &terms, false)...)
+		if diags.HasError() {
+			return nil
+		}
+
+		// Sort fields refer to field IDs, so a schema configured without IDs
+		// gets them assigned here, the same way the catalog would.
+		if args.schema.HighestFieldID() == 0 {
+			args.schema, err = iceberg.AssignFreshSchemaIDs(args.schema, nil)
+			if err != nil {
+				diags.AddError("failed to assign schema field IDs", err.Error())
+
+				return nil
+			}
+		}
+
+		order, err := newWriteOrder(1, terms, args.schema)
+		if err != nil {
+			diags.AddAttributeError(path.Root("write_order"), "invalid write_order", err.Error())
+
+			return nil
+		}
+		args.sortOrder = &order
 	}
 
 	if !data.SortOrder.IsNull() && !data.SortOrder.IsUnknown() {
 		var order icebergTableSortOrder
-		diags = data.SortOrder.As(ctx, &order, basetypes.ObjectAsOptions{})
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
+		diags.Append(data.SortOrder.As(ctx, &order, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return nil
 		}
 		icebergOrder, err := order.ToIceberg()
 		if err != nil {
-			resp.Diagnostics.AddError("failed to convert sort order", err.Error())
+			diags.AddError("failed to convert sort order", err.Error())
 
-			return
+			return nil
 		}
-		createOpts = append(createOpts, catalog.WithSortOrder(icebergOrder))
+		args.sortOrder = &icebergOrder
 	}
 
-	tbl, err := r.catalog.CreateTable(ctx, tableIdent, tblSchema, createOpts...)
-	if err != nil {
-		resp.Diagnostics.AddError("failed to create table", err.Error())
+	return args
+}
 
-		return
+func (a *tableCreateArgs) options() []catalog.CreateTableOpt {
+	opts := []catalog.CreateTableOpt{
+		catalog.WithProperties(a.properties),
 	}
-
-	data.ID = types.StringValue(strings.Join(tableIdent, "."))
-
-	r.syncTableToModel(ctx, tbl, &data, &resp.Diagnostics)
-	if resp.Diagnostics.HasError() {
-		return
+	if a.spec != nil {
+		opts = append(opts, catalog.WithPartitionSpec(a.spec))
+	}
+	if a.sortOrder != nil {
+		opts = append(opts, catalog.WithSortOrder(*a.sortOrder))
 	}
 
-	diags = resp.State.Set(ctx, &data)
-	resp.Diagnostics.Append(diags...)
+	return opts
 }
 
 func (r *icebergTableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withResourceType(ctx, "iceberg_table")
 	r.ConfigureCatalog(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -476,32 +917,131 @@ func (r *icebergTableResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	tableName := data.Name.ValueString()
-	tableIdent := append(namespaceName, tableName)
+	tableIdent := identifierFromID(data.ID, append(namespaceName, data.Name.ValueString()))
 
+	ctx, nulls := withNullProperties(ctx)
 	tbl, err := r.catalog.LoadTable(ctx, tableIdent)
 	if err != nil {
-		if errors.Is(err, catalog.ErrNoSuchTable) {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 
 			return
 		}
+		if isInvalidMetadataError(err) {
+			addInconsistentMetadataError(encodeIdentifier(tableIdent), err, &resp.Diagnostics)
+
+			return
+		}
 		resp.Diagnostics.AddError("failed to load table", err.Error())
 
 		return
 	}
+	nulls.addTo("table "+strings.Join(tableIdent, "."), &resp.Diagnostics)
+
+	prior := data
+	priorUUID, _ := getPrivateValue[string](ctx, req.Private, privateKeyTableUUID, &resp.Diagnostics)
 
 	r.syncTableToModel(ctx, tbl, &data, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if r.provider.warnOnDrift {
+		r.reportDrift(ctx, encodeIdentifier(tableIdent), &prior, &data, priorUUID, tbl.Metadata().TableUUID().String(), &resp.Diagnostics)
+	}
+	checkStaleness(encodeIdentifier(tableIdent), data.MaxStalenessCheck, data.LastCommitTimestampMs, time.Now(), &resp.Diagnostics)
+
+	recordTablePrivateState(ctx, tbl, resp.Private, &resp.Diagnostics)
+
 	diags = resp.State.Set(ctx, &data)
 	resp.Diagnostics.Append(diags...)
 }
 
+// reportDrift compares the prior state with the freshly loaded model and warns
+// about user-managed properties and columns that changed outside of Terraform.
+func (r *icebergTableResource) reportDrift(ctx context.Context, name string, prior, current *icebergTableResourceModel, priorUUID, currentUUID string, diags *diag.Diagnostics) {
+	report := newDriftReport("Table " + name)
+
+	report.compareIdentity(priorUUID, currentUUID)
+	compareTableModels(ctx, report, prior, current, diags)
+
+	report.addTo(diags)
+}
+
+// verifyApplied reads the table back and compares it with intended, the
+// planned model, see apply_verification.go.
+func (r *icebergTableResource) verifyApplied(ctx context.Context, tableIdent table.Identifier, intended *icebergTableResourceModel, diags *diag.Diagnostics) {
+	tbl, err := r.catalog.LoadTable(ctx, tableIdent)
+	if err != nil {
+		diags.AddError("failed to read table back after the apply", err.Error())
+
+		return
+	}
+
+	actual := *intended
+	r.syncTableToModel(ctx, tbl, &actual, diags)
+	if diags.HasError() {
+		return
+	}
+
+	report := newDriftReport("Table " + encodeIdentifier(tableIdent))
+	compareTableModels(ctx, report, intended, &actual, diags)
+	report.addMismatchTo(diags)
+}
+
+// compareTableModels records in report how the user-managed properties and
+// columns of current differ from those of prior.
+func compareTableModels(ctx context.Context, report *driftReport, prior, current *icebergTableResourceModel, diags *diag.Diagnostics) {
+	if (!prior.UserProperties.IsNull() && !prior.UserProperties.IsUnknown()) || !prior.Comment.IsNull() || !prior.Owner.IsNull() || !prior.RowLevelOperationMode.IsNull() || !prior.EngineHints.IsNull() {
+		priorProps := make(map[string]string)
+		currentProps := make(map[string]string)
+		if !prior.UserProperties.IsNull() && !prior.UserProperties.IsUnknown() {
+			diags.Append(prior.UserProperties.ElementsAs(ctx, &priorProps, false)...)
+		}
+		if !current.UserProperties.IsNull() {
+			diags.Append(current.UserProperties.ElementsAs(ctx, &currentProps, false)...)
+		}
+		if diags.HasError() {
+			return
+		}
+		report.compareProperties(
+			prior.withAttributeProperties(priorProps),
+			current.withAttributeProperties(currentProps),
+		)
+	}
+
+	if !prior.SensitiveUserProperties.IsNull() && !prior.SensitiveUserProperties.IsUnknown() {
+		priorProps := make(map[string]string)
+		currentProps := make(map[string]string)
+		diags.Append(prior.SensitiveUserProperties.ElementsAs(ctx, &priorProps, false)...)
+		if !current.SensitiveUserProperties.IsNull() {
+			diags.Append(current.SensitiveUserProperties.ElementsAs(ctx, &currentProps, false)...)
+		}
+		if diags.HasError() {
+			return
+		}
+		report.compareSensitiveProperties(priorProps, currentProps)
+	}
+
+	// Imported resources have no prior schema to compare against.
+	if !prior.Schema.IsNull() && !prior.Schema.IsUnknown() {
+		var priorSchema, currentSchema icebergTableSchema
+		diags.Append(prior.Schema.As(ctx, &priorSchema, basetypes.ObjectAsOptions{})...)
+		diags.Append(current.Schema.As(ctx, &currentSchema, basetypes.ObjectAsOptions{})...)
+		if diags.HasError() {
+			return
+		}
+		report.compareSchemaFields(priorSchema.Fields, currentSchema.Fields)
+	}
+}
+
 func (r *icebergTableResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !r.provider.checkWritable("iceberg_table", "update", &resp.Diagnostics) {
+		return
+	}
+
 	tflog.Info(ctx, "Inside table update")
+	ctx = withResourceType(ctx, "iceberg_table")
 	r.ConfigureCatalog(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -526,8 +1066,8 @@ func (r *icebergTableResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
-	tableName := state.Name.ValueString()
-	tableIdent := append(namespaceName, tableName)
+	tableIdent := identifierFromID(state.ID, append(namespaceName, state.Name.ValueString()))
+	intended := plan
 
 	tbl, err := r.catalog.LoadTable(ctx, tableIdent)
 	if err != nil {
@@ -542,18 +1082,32 @@ func (r *icebergTableResource) Update(ctx context.Context, req resource.UpdateRe
 
 	updates := make([]table.Update, 0)
 
-	updates = append(updates, r.calculatePropertyUpdates(ctx, &plan, &state, &resp.Diagnostics)...)
-	updates = append(updates, r.calculateSchemaUpdates(ctx, &plan, &state, tbl, &resp.Diagnostics)...)
-	updates = append(updates, r.calculatePartitionUpdates(ctx, &plan, tbl, &resp.Diagnostics)...)
-	updates = append(updates, r.calculateSortOrderUpdates(ctx, &plan, tbl, &resp.Diagnostics)...)
+	propertyDelta := r.calculatePropertyUpdates(ctx, &plan, &state, tbl, &resp.Diagnostics)
+	updates = append(updates, propertyUpdates(propertyDelta)...)
+	// The schema and layout of external tables are only read.
+	if !plan.External.ValueBool() {
+		updates = append(updates, r.calculateSchemaUpdates(ctx, &plan, &state, tbl, &resp.Diagnostics)...)
+		updates = append(updates, r.calculatePartitionUpdates(ctx, &plan, tbl, &resp.Diagnostics)...)
+		updates = append(updates, r.calculateSortOrderUpdates(ctx, &plan, tbl, &resp.Diagnostics)...)
+	}
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	if len(updates) > 0 {
+		if !r.provider.capabilities.check(featureUpdateTable, &resp.Diagnostics) {
+			return
+		}
+
 		_, _, err = r.catalog.CommitTable(ctx, tableIdent, requirements, updates)
 		if err != nil {
+			if isUnsupportedOperationError(err) {
+				r.provider.capabilities.markUnsupported(featureUpdateTable)
+				r.provider.capabilities.check(featureUpdateTable, &resp.Diagnostics)
+
+				return
+			}
 			resp.Diagnostics.AddError("failed to commit table updates", err.Error())
 
 			return
@@ -566,6 +1120,10 @@ func (r *icebergTableResource) Update(ctx context.Context, req resource.UpdateRe
 
 			return
 		}
+		r.provider.checkPropertyRemovals("table "+encodeIdentifier(tableIdent), propertyDelta, tbl.Properties(), &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
 	}
 
 	r.syncTableToModel(ctx, tbl, &plan, &resp.Diagnostics)
@@ -573,15 +1131,182 @@ func (r *icebergTableResource) Update(ctx context.Context, req resource.UpdateRe
 		return
 	}
 
+	recordTablePrivateState(ctx, tbl, resp.Private, &resp.Diagnostics)
+
 	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, tableIdent, &intended, &resp.Diagnostics)
+	}
 }
 
-func (r *icebergTableResource) calculatePropertyUpdates(ctx context.Context, plan, state *icebergTableResourceModel, diags *diag.Diagnostics) []table.Update {
-	updates := make([]table.Update, 0)
-	userUpdates := make(iceberg.Properties)
-	removals := make([]string, 0)
+// ModifyPlan reports at plan time when the catalog can't apply in-place
+// updates, when a schema update is incompatible with the rows in the table,
+// when the owner isn't a Polaris principal and, with validate_on_plan, when
+// the catalog would reject a new table.
+func (r *icebergTableResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planned icebergTableResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &planned)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	planUntracked(ctx, planned.untrackedAttributes(), &resp.Plan, &resp.Diagnostics)
+
+	if req.State.Raw.IsNull() {
+		data := planned
+
+		if ident, ok := r.plannedIdentifier(ctx, data.Namespace, data.Name, &resp.Diagnostics); ok {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), encodeIdentifier(ident))...)
+			r.provider.claimTable(ident, claimToken(ctx, req, resp), &resp.Diagnostics)
+		}
+		r.provider.checkOwnerPrincipal(ctx, "iceberg_table", data.Owner, types.StringNull(), &resp.Diagnostics)
+		// External tables are registered with the format version and schema
+		// of their metadata file, which are checked once it is read.
+		if !data.External.ValueBool() {
+			validateRequiredFeatures(ctx, &data, nil, &resp.Diagnostics)
+			r.validateCreate(ctx, &data, &resp.Diagnostics)
+		}
+
+		return
+	}
+
+	var planOwner, stateOwner, planName, stateName, stateID types.String
+	var planNamespace, stateNamespace types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("owner"), &planOwner)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("owner"), &stateOwner)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &planName)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &stateName)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("namespace"), &planNamespace)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("namespace"), &stateNamespace)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &stateID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if ident, ok := r.plannedIdentifier(ctx, planNamespace, planName, &resp.Diagnostics); ok {
+		if planName.Equal(stateName) && planNamespace.Equal(stateNamespace) {
+			checkNamePrefix("table", stateID, ident, &resp.Diagnostics)
+		}
+		r.provider.claimTable(ident, claimToken(ctx, req, resp), &resp.Diagnostics)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.provider.checkOwnerPrincipal(ctx, "iceberg_table", planOwner, stateOwner, &resp.Diagnostics)
+
+	var external types.Bool
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("external"), &external)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !external.ValueBool() {
+		r.planFieldIDs(ctx, req, resp)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if resp.Plan.Raw.Equal(req.State.Raw) || len(resp.RequiresReplace) > 0 {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_table")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() || r.catalog == nil {
+		return
+	}
+
+	if !r.provider.capabilities.check(featureUpdateTable, &resp.Diagnostics) {
+		return
+	}
+
+	r.validatePlannedFeatures(ctx, req, resp)
+	if !external.ValueBool() {
+		r.validateSchemaUpdate(ctx, req, resp)
+	}
+}
+
+// validatePlannedFeatures checks required_features against the format version
+// of the table in the catalog.
+func (r *icebergTableResource) validatePlannedFeatures(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state icebergTableResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.RequiredFeatures.IsNull() {
+		return
+	}
+
+	var namespaceName []string
+	resp.Diagnostics.Append(state.Namespace.ElementsAs(ctx, &namespaceName, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tbl, err := r.catalog.LoadTable(ctx, identifierFromID(state.ID, append(namespaceName, state.Name.ValueString())))
+	if err != nil {
+		resp.Diagnostics.AddError("failed to load table", err.Error())
+
+		return
+	}
+
+	validateRequiredFeatures(ctx, &plan, tbl, &resp.Diagnostics)
+}
+
+// plannedIdentifier returns the identifier in the catalog of the table with the
+// planned namespace and name, if they are known.
+func (r *icebergTableResource) plannedIdentifier(ctx context.Context, namespace types.List, name types.String, diags *diag.Diagnostics) (table.Identifier, bool) {
+	if !fullyKnown(ctx, namespace, name) {
+		return nil, false
+	}
+
+	var namespaceName []string
+	diags.Append(namespace.ElementsAs(ctx, &namespaceName, false)...)
+	if diags.HasError() {
+		return nil, false
+	}
+
+	return r.provider.prefixTable(append(namespaceName, name.ValueString())), true
+}
+
+// validateSchemaUpdate runs the schema checks of an update at plan time, so
+// for example adding a required field to a table with rows fails the plan
+// rather than the apply.
+func (r *icebergTableResource) validateSchemaUpdate(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var plan, state icebergTableResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Schema = withAssignedFieldIDs(ctx, plan.Schema, 0)
+	if !fullyKnown(ctx, plan.Schema) || plan.Schema.Equal(state.Schema) {
+		return
+	}
+
+	var namespaceName []string
+	resp.Diagnostics.Append(state.Namespace.ElementsAs(ctx, &namespaceName, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tbl, err := r.catalog.LoadTable(ctx, identifierFromID(state.ID, append(namespaceName, state.Name.ValueString())))
+	if err != nil {
+		resp.Diagnostics.AddError("failed to load table", err.Error())
+
+		return
+	}
+
+	r.calculateSchemaUpdates(ctx, &plan, &state, tbl, &resp.Diagnostics)
+}
 
+// calculatePropertyUpdates returns the property changes from state to plan;
+// see propertyUpdates for the table updates applying them.
+func (r *icebergTableResource) calculatePropertyUpdates(ctx context.Context, plan, state *icebergTableResourceModel, tbl *table.Table, diags *diag.Diagnostics) properties.Delta {
 	stateProps := make(map[string]string)
 	if !state.UserProperties.IsNull() {
 		d := state.UserProperties.ElementsAs(ctx, &stateProps, false)
@@ -594,30 +1319,25 @@ func (r *icebergTableResource) calculatePropertyUpdates(ctx context.Context, pla
 		diags.Append(d...)
 	}
 
-	if diags.HasError() {
-		return nil
-	}
-
-	for k, v := range planProps {
-		if oldV, ok := stateProps[k]; !ok || oldV != v {
-			userUpdates[k] = v
-		}
+	stateSensitive := make(map[string]string)
+	if !state.SensitiveUserProperties.IsNull() {
+		diags.Append(state.SensitiveUserProperties.ElementsAs(ctx, &stateSensitive, false)...)
 	}
 
-	for k := range stateProps {
-		if _, ok := planProps[k]; !ok {
-			removals = append(removals, k)
-		}
+	planSensitive := make(map[string]string)
+	if !plan.SensitiveUserProperties.IsNull() {
+		diags.Append(plan.SensitiveUserProperties.ElementsAs(ctx, &planSensitive, false)...)
 	}
 
-	if len(userUpdates) > 0 {
-		updates = append(updates, table.NewSetPropertiesUpdate(userUpdates))
-	}
-	if len(removals) > 0 {
-		updates = append(updates, table.NewRemovePropertiesUpdate(removals))
+	if diags.HasError() {
+		return properties.Delta{}
 	}
 
-	return updates
+	return properties.ComputeDelta(
+		plan.withAttributeProperties(withSensitiveProperties(planProps, planSensitive)),
+		state.withAttributeProperties(withSensitiveProperties(stateProps, stateSensitive)),
+		properties.Known(tbl.Properties()),
+	)
 }
 
 func (r *icebergTableResource) calculateSchemaUpdates(ctx context.Context, plan, state *icebergTableResourceModel, tbl *table.Table, diags *diag.Diagnostics) []table.Update {
@@ -631,6 +1351,15 @@ func (r *icebergTableResource) calculateSchemaUpdates(ctx context.Context, plan,
 		return nil
 	}
 
+	// New fields without an ID get the IDs after the highest ever assigned.
+	assignFieldIDs(planSchema.Fields, max(int64(tbl.Metadata().LastColumnID()), highestFieldID(planSchema.Fields)))
+	// Docs and initial defaults that aren't configured keep the catalog's.
+	if err := fillUnsetFieldValues(planSchema.Fields, tbl.Schema()); err != nil {
+		diags.AddError("failed to convert table schema", err.Error())
+
+		return nil
+	}
+
 	planIceberg, err := planSchema.ToIceberg()
 	if err != nil {
 		diags.AddError("failed to convert plan schema", err.Error())
@@ -647,10 +1376,17 @@ func (r *icebergTableResource) calculateSchemaUpdates(ctx context.Context, plan,
 	// Normalize by comparing the JSON of the fields list only.
 	// This ignores the top-level schema-id and any other schema-level metadata
 	// while ensuring every field change (name, type, id, etc.) is detected.
+	// The plan is compared with the table rather than the state, which
+	// doesn't hold the values the configuration leaves unset.
 	planFieldsJson, _ := json.Marshal(planIceberg.Fields())
-	stateFieldsJson, _ := json.Marshal(stateIceberg.Fields())
+	tableFieldsJson, _ := json.Marshal(tbl.Schema().Fields())
 
-	if string(planFieldsJson) == string(stateFieldsJson) {
+	if string(planFieldsJson) == string(tableFieldsJson) {
+		return nil
+	}
+
+	checkRequiredFieldAdditions(planIceberg, stateIceberg, tbl, plan.ForceRequiredAdd.ValueBool(), diags)
+	if diags.HasError() {
 		return nil
 	}
 
@@ -727,6 +1463,10 @@ func (r *icebergTableResource) calculatePartitionUpdates(ctx context.Context, pl
 }
 
 func (r *icebergTableResource) calculateSortOrderUpdates(ctx context.Context, plan *icebergTableResourceModel, tbl *table.Table, diags *diag.Diagnostics) []table.Update {
+	if !plan.WriteOrder.IsNull() && !plan.WriteOrder.IsUnknown() {
+		return r.calculateWriteOrderUpdates(ctx, plan, tbl, diags)
+	}
+
 	if plan.SortOrder.IsUnknown() {
 		if tbl.SortOrder().OrderID() != 0 {
 			// Create a new unsorted order and set it as default
@@ -771,29 +1511,114 @@ func (r *icebergTableResource) calculateSortOrderUpdates(ctx context.Context, pl
 	return nil
 }
 
+// calculateWriteOrderUpdates compiles write_order against the planned schema,
+// falling back to the current one for columns without a configured ID, and
+// replaces the default sort order if it differs.
+func (r *icebergTableResource) calculateWriteOrderUpdates(ctx context.Context, plan *icebergTableResourceModel, tbl *table.Table, diags *diag.Diagnostics) []table.Update {
+	var terms []string
+	diags.Append(plan.WriteOrder.ElementsAs(ctx, &terms, false)...)
+
+	var planSchema icebergTableSchema
+	diags.Append(plan.Schema.As(ctx, &planSchema, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil
+	}
+
+	planIceberg, err := planSchema.ToIceberg()
+	if err != nil {
+		diags.AddError("failed to convert plan schema", err.Error())
+
+		return nil
+	}
+
+	fields, err := compileWriteOrder(terms, planIceberg, tbl.Schema())
+	if err != nil {
+		diags.AddAttributeError(path.Root("write_order"), "invalid write_order", err.Error())
+
+		return nil
+	}
+	if slices.Equal(fields, slices.Collect(tbl.SortOrder().Fields())) {
+		return nil
+	}
+
+	if len(fields) == 0 {
+		unsortedOrder := table.UnsortedSortOrder
+
+		return []table.Update{
+			table.NewAddSortOrderUpdate(&unsortedOrder),
+			table.NewSetDefaultSortOrderUpdate(0),
+		}
+	}
+
+	maxOrderID := 0
+	for _, o := range tbl.Metadata().SortOrders() {
+		maxOrderID = max(maxOrderID, o.OrderID())
+	}
+	newOrder, err := table.NewSortOrder(maxOrderID+1, fields)
+	if err != nil {
+		diags.AddAttributeError(path.Root("write_order"), "invalid write_order", err.Error())
+
+		return nil
+	}
+
+	return []table.Update{
+		table.NewAddSortOrderUpdate(&newOrder),
+		table.NewSetDefaultSortOrderUpdate(-1),
+	}
+}
+
 func (r *icebergTableResource) syncTableToModel(ctx context.Context, tbl *table.Table, model *icebergTableResourceModel, diags *diag.Diagnostics) {
-	// Update ServerProperties
-	serverProperties, d := types.MapValueFrom(ctx, types.StringType, tbl.Properties())
+	// Inconsistent metadata would fail or panic in the conversions below.
+	if err := checkTableMetadata(tbl.Metadata()); err != nil {
+		addInconsistentMetadataError(encodeIdentifier(tbl.Identifier()), err, diags)
+
+		return
+	}
+
+	// Update ServerProperties, keeping sensitive values apart
+	sensitiveKeys := sensitivePropertyKeys(ctx, model.SensitivePropertyKeys, diags)
+	if diags.HasError() {
+		return
+	}
+	plainProperties, sensitiveProperties := splitSensitiveProperties(tbl.Properties(), sensitiveKeys)
+
+	serverProperties, d := types.MapValueFrom(ctx, types.StringType, plainProperties)
 	diags.Append(d...)
 	if diags.HasError() {
 		return
 	}
 	model.ServerProperties = serverProperties
 
-	// Update Schema from the table to capture any server-assigned IDs
-	icebergSchema := tbl.Schema()
-	var updatedSchema icebergTableSchema
-	if err := updatedSchema.FromIceberg(icebergSchema); err != nil {
-		diags.AddError("failed to convert iceberg schema to terraform schema", err.Error())
+	model.LastCommitTimestampMs = types.Int64PointerValue(lastCommitTimestampMs(tbl))
+	model.CatalogName = r.provider.catalogNameValue()
 
+	model.SensitiveServerProperties, d = types.MapValueFrom(ctx, types.StringType, sensitiveProperties)
+	diags.Append(d...)
+	if diags.HasError() {
 		return
 	}
+
+	// Update Schema from the table to capture any server-assigned IDs. The
+	// schema of external tables isn't configured, so it is taken as is.
 	var d2 diag.Diagnostics
-	model.Schema, d2 = types.ObjectValueFrom(ctx, icebergTableSchema{}.AttrTypes(), updatedSchema)
+	model.ServerSchema, d2 = schemaObjectValue(ctx, tbl.Schema())
 	diags.Append(d2...)
 	if diags.HasError() {
 		return
 	}
+	if model.External.ValueBool() {
+		model.Schema = model.ServerSchema
+	} else {
+		var prior icebergTableSchema
+		if !model.Schema.IsNull() && !model.Schema.IsUnknown() {
+			diags.Append(model.Schema.As(ctx, &prior, basetypes.ObjectAsOptions{UnhandledNullAsEmpty: true, UnhandledUnknownAsEmpty: true})...)
+		}
+		model.Schema, d2 = syncedSchemaObjectValue(ctx, tbl.Schema(), prior.Fields)
+		diags.Append(d2...)
+	}
+	if diags.HasError() {
+		return
+	}
 
 	// Update PartitionSpec
 	icebergSpec := tbl.Spec()
@@ -827,27 +1652,77 @@ func (r *icebergTableResource) syncTableToModel(ctx context.Context, tbl *table.
 		model.SortOrder = types.ObjectNull(icebergTableSortOrder{}.AttrTypes())
 	}
 
+	// Keep write_order as configured while it still describes the table's sort
+	// order; otherwise show the order the table has, or null if write_order
+	// can't express it.
+	if !model.WriteOrder.IsNull() && !model.WriteOrder.IsUnknown() {
+		var terms []string
+		diags.Append(model.WriteOrder.ElementsAs(ctx, &terms, false)...)
+		if diags.HasError() {
+			return
+		}
+
+		if !writeOrderMatches(terms, icebergOrder, tbl.Schema()) {
+			if current, ok := writeOrderTerms(icebergOrder, tbl.Schema()); ok {
+				var d diag.Diagnostics
+				model.WriteOrder, d = types.ListValueFrom(ctx, types.StringType, current)
+				diags.Append(d...)
+			} else {
+				model.WriteOrder = types.ListNull(types.StringType)
+			}
+		}
+	}
+
 	// Update UserProperties to match reality for tracked keys
+	planProps := make(map[string]string)
 	if !model.UserProperties.IsNull() {
-		planProps := make(map[string]string)
 		d := model.UserProperties.ElementsAs(ctx, &planProps, false)
 		diags.Append(d...)
 		if diags.HasError() {
 			return
 		}
 
-		managedProps := make(map[string]string)
-		for k := range planProps {
-			if v, ok := tbl.Properties()[k]; ok {
-				managedProps[k] = v
-			}
-		}
+		managedProps := properties.Reconcile(planProps, tbl.Properties())
 		model.UserProperties, d = types.MapValueFrom(ctx, types.StringType, managedProps)
 		diags.Append(d...)
 	}
+
+	if !model.SensitiveUserProperties.IsNull() {
+		sensitiveProps := make(map[string]string)
+		d := model.SensitiveUserProperties.ElementsAs(ctx, &sensitiveProps, false)
+		diags.Append(d...)
+		if diags.HasError() {
+			return
+		}
+
+		model.SensitiveUserProperties, d = types.MapValueFrom(ctx, types.StringType, properties.Reconcile(sensitiveProps, sensitiveProperties))
+		diags.Append(d...)
+	}
+
+	model.Comment = syncComment(model.Comment, tbl.Properties())
+	model.Owner = syncOwner(model.Owner, tbl.Properties())
+	model.RowLevelOperationMode = syncRowLevelOperationMode(model.RowLevelOperationMode, tbl.Properties())
+	model.EngineHints = syncEngineHints(model.EngineHints, tbl.Properties())
+
+	var d5 diag.Diagnostics
+	model.ServerManagedProperties, d5 = types.MapValueFrom(ctx, types.StringType, properties.ServerManaged(model.withAttributeProperties(planProps), plainProperties))
+	diags.Append(d5...)
+	model.clearUntracked()
+}
+
+// recordTablePrivateState keeps server-assigned table bookkeeping in private
+// state so it can be compared on the next refresh without showing up in plans.
+func recordTablePrivateState(ctx context.Context, tbl *table.Table, private privateStateWriter, diags *diag.Diagnostics) {
+	setPrivateValue(ctx, private, privateKeyTableUUID, tbl.Metadata().TableUUID().String(), diags)
+	setPrivateValue(ctx, private, privateKeyMetadataLocation, tbl.MetadataLocation(), diags)
 }
 
 func (r *icebergTableResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !r.provider.checkWritable("iceberg_table", "delete", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_table")
 	r.ConfigureCatalog(ctx, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -869,12 +1744,35 @@ func (r *icebergTableResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
-	tableName := data.Name.ValueString()
-	tableIdent := append(namespaceName, tableName)
+	tableIdent := identifierFromID(data.ID, append(namespaceName, data.Name.ValueString()))
+
+	// External tables are only removed from the catalog.
+	purge := false
+	if data.PurgeOnDestroy.ValueBool() && !data.External.ValueBool() {
+		tbl, err := r.catalog.LoadTable(ctx, tableIdent)
+		if err != nil {
+			if isNotFoundError(err) {
+				return
+			}
+			resp.Diagnostics.AddError("failed to load table", err.Error())
+
+			return
+		}
+
+		var refusal string
+		purge, refusal = decidePurge(true, data.OverrideGCCheck.ValueBool(), tbl.Properties())
+		if refusal != "" {
+			resp.Diagnostics.AddWarning(
+				"Table not purged",
+				fmt.Sprintf("The table %s has %s, so its files may still be used by other tables or systems. It was dropped without deleting its files. Set override_gc_check to purge it anyway.",
+					encodeIdentifier(tableIdent), refusal),
+			)
+		}
+	}
 
-	err := r.catalog.DropTable(ctx, tableIdent)
+	err := r.dropTable(ctx, tableIdent, purge)
 	if err != nil {
-		if errors.Is(err, catalog.ErrNoSuchTable) {
+		if isNotFoundError(err) {
 			// If the table is already gone, we don't need to do anything.
 			return
 		}
@@ -884,25 +1782,40 @@ func (r *icebergTableResource) Delete(ctx context.Context, req resource.DeleteRe
 	}
 }
 
-func (r *icebergTableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+// dropTable drops the table, purging its files if purge is set.
+func (r *icebergTableResource) dropTable(ctx context.Context, ident table.Identifier, purge bool) error {
+	if !purge {
+		return r.catalog.DropTable(ctx, ident)
+	}
 
-	// FieldsFunc is used to split by dot and filter out empty segments (e.g. "a..b" -> ["a", "b"])
-	parts := strings.FieldsFunc(req.ID, func(r rune) bool {
-		return r == '.'
-	})
+	purger, ok := r.catalog.(tablePurger)
+	if !ok {
+		return errors.New("the catalog client doesn't support purging tables")
+	}
 
-	if len(parts) < 2 {
-		resp.Diagnostics.AddError(
-			"Invalid Import ID",
-			"The import ID should be a dot-separated full identifier (namespace + name).",
-		)
+	return purger.PurgeTable(ctx, ident)
+}
+
+func (r *icebergTableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts, err := decodeImportID(req.ID)
+	if err != nil || len(parts) < 2 {
+		detail := "The import ID should be a dot-separated full identifier (namespace + name)."
+		if err != nil {
+			detail += " " + err.Error()
+		}
+		resp.Diagnostics.AddError("Invalid Import ID", detail)
 
 		return
 	}
 
-	tableName := parts[len(parts)-1]
-	namespaceParts := parts[:len(parts)-1]
+	name, err := r.provider.unprefixTable(parts)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", "The import ID should be the identifier of the table in the catalog, including the name prefix: "+err.Error())
+
+		return
+	}
+	tableName := name[len(name)-1]
+	namespaceParts := name[:len(name)-1]
 
 	namespaceList, diags := types.ListValueFrom(ctx, types.StringType, namespaceParts)
 	resp.Diagnostics.Append(diags...)
@@ -910,6 +1823,7 @@ func (r *icebergTableResource) ImportState(ctx context.Context, req resource.Imp
 		return
 	}
 
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), encodeIdentifier(parts))...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), types.StringValue(tableName))...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), namespaceList)...)
 }
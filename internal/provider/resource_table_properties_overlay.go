@@ -0,0 +1,571 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ resource.Resource                = &icebergTablePropertiesOverlayResource{}
+	_ resource.ResourceWithImportState = &icebergTablePropertiesOverlayResource{}
+	_ resource.ResourceWithModifyPlan  = &icebergTablePropertiesOverlayResource{}
+)
+
+func NewTablePropertiesOverlayResource() resource.Resource {
+	return &icebergTablePropertiesOverlayResource{}
+}
+
+// icebergTablePropertiesOverlayResource enforces a set of properties on every
+// table of a namespace, or on an explicit list of tables in it. Read only keeps
+// the properties that hold on all tables in state, so a table created or
+// changed outside Terraform shows up as a diff and the next apply re-asserts
+// the properties.
+type icebergTablePropertiesOverlayResource struct {
+	catalog  catalog.Catalog
+	provider *icebergProvider
+}
+
+type icebergTablePropertiesOverlayResourceModel struct {
+	ID                        types.String `tfsdk:"id"`
+	Namespace                 types.List   `tfsdk:"namespace"`
+	Tables                    types.List   `tfsdk:"tables"`
+	Properties                types.Map    `tfsdk:"properties"`
+	RemovePropertiesOnDestroy types.Bool   `tfsdk:"remove_properties_on_destroy"`
+}
+
+func (r *icebergTablePropertiesOverlayResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table_properties_overlay"
+}
+
+func (r *icebergTablePropertiesOverlayResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "A resource for enforcing a set of properties on all tables of a namespace.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "The name of the namespace in the catalog, with its levels joined by '.', including the name_prefix of the provider.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"namespace": schema.ListAttribute{
+				Description: "The namespace containing the tables.",
+				Required:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"tables": schema.ListAttribute{
+				Description: "Names of the tables in the namespace to enforce the properties on. If not set, all tables in the namespace are used, including tables created later.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"properties": schema.MapAttribute{
+				Description: "The properties to set on each table. Other properties of the tables are left alone.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"remove_properties_on_destroy": schema.BoolAttribute{
+				Description: "If true, properties are removed from the tables when they are removed from the configuration or the resource is destroyed. Defaults to false, which leaves them in place.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+		},
+	}
+}
+
+func (r *icebergTablePropertiesOverlayResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	provider, ok := req.ProviderData.(*icebergProvider)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			"Expected *icebergProvider, got: %T. Please report this issue to the provider developers.",
+		)
+
+		return
+	}
+
+	r.provider = provider
+}
+
+func (r *icebergTablePropertiesOverlayResource) ConfigureCatalog(ctx context.Context, diags *diag.Diagnostics) {
+	if r.catalog != nil {
+		return
+	}
+
+	if r.provider == nil {
+		diags.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before this operation",
+		)
+
+		return
+	}
+
+	catalog, err := r.provider.NewCatalog(ctx, "iceberg_table_properties_overlay")
+	if err != nil {
+		diags.AddError(
+			"Failed to create catalog",
+			"Failed to create catalog: "+err.Error(),
+		)
+
+		return
+	}
+	r.catalog = catalog
+}
+
+func (r *icebergTablePropertiesOverlayResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !r.provider.checkWritable("iceberg_table_properties_overlay", "create", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_table_properties_overlay")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergTablePropertiesOverlayResourceModel
+
+	diags := req.Plan.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	intended := data
+	r.apply(ctx, &data, nil, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, &intended, &resp.Diagnostics)
+	}
+}
+
+func (r *icebergTablePropertiesOverlayResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = withResourceType(ctx, "iceberg_table_properties_overlay")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data icebergTablePropertiesOverlayResourceModel
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespaceIdent, tableNames, desired := r.targets(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tables, err := r.resolveTables(ctx, namespaceIdent, tableNames)
+	if err != nil {
+		if isNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+
+			return
+		}
+		resp.Diagnostics.AddError("failed to list tables", err.Error())
+
+		return
+	}
+
+	compliant, err := r.compliantProperties(ctx, tables, desired)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to load table", err.Error())
+
+		return
+	}
+
+	data.Properties, diags = types.MapValueFrom(ctx, types.StringType, compliant)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *icebergTablePropertiesOverlayResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !r.provider.checkWritable("iceberg_table_properties_overlay", "update", &resp.Diagnostics) {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_table_properties_overlay")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan, state icebergTablePropertiesOverlayResourceModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keys dropped from the configuration are only removed from the tables if
+	// the user asked for it; otherwise they are simply no longer enforced.
+	var removed map[string]string
+	if plan.RemovePropertiesOnDestroy.ValueBool() {
+		removed = make(map[string]string)
+		diags = state.Properties.ElementsAs(ctx, &removed, false)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	intended := plan
+	r.apply(ctx, &plan, removed, &resp.Diagnostics)
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if r.provider.verifyAfterApply && !resp.Diagnostics.HasError() {
+		r.verifyApplied(ctx, &intended, &resp.Diagnostics)
+	}
+}
+
+func (r *icebergTablePropertiesOverlayResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !r.provider.checkWritable("iceberg_table_properties_overlay", "delete", &resp.Diagnostics) {
+		return
+	}
+
+	var data icebergTablePropertiesOverlayResourceModel
+
+	diags := req.State.Get(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || !data.RemovePropertiesOnDestroy.ValueBool() {
+		return
+	}
+
+	ctx = withResourceType(ctx, "iceberg_table_properties_overlay")
+	r.ConfigureCatalog(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespaceIdent, tableNames, managed := r.targets(ctx, &data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tables, err := r.resolveTables(ctx, namespaceIdent, tableNames)
+	if err != nil {
+		if isNotFoundError(err) {
+			return
+		}
+		resp.Diagnostics.AddError("failed to list tables", err.Error())
+
+		return
+	}
+
+	for _, ident := range tables {
+		if _, err := r.enforce(ctx, ident, map[string]string{}, managed, &resp.Diagnostics); err != nil && !isNotFoundError(err) {
+			resp.Diagnostics.AddError("failed to remove properties from table "+strings.Join(ident, "."), err.Error())
+		}
+	}
+}
+
+// ModifyPlan plans the ID of a new overlay, so the name prefix shows up in the
+// plan, and rejects plans of an existing overlay after the name prefix changed.
+func (r *icebergTablePropertiesOverlayResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var planNamespace, stateNamespace types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("namespace"), &planNamespace)...)
+	if resp.Diagnostics.HasError() || !fullyKnown(ctx, planNamespace) {
+		return
+	}
+
+	var namespaceName []string
+	resp.Diagnostics.Append(planNamespace.ElementsAs(ctx, &namespaceName, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	planned := r.provider.prefixNamespace(catalog.ToIdentifier(namespaceName...))
+
+	if req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), encodeIdentifier(planned))...)
+
+		return
+	}
+
+	var stateID types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("namespace"), &stateNamespace)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &stateID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if planNamespace.Equal(stateNamespace) {
+		checkNamePrefix("table properties overlay", stateID, planned, &resp.Diagnostics)
+	}
+}
+
+// ImportState takes the namespace as import ID. The overlay is imported for
+// all tables of the namespace with no properties; the next plan shows the
+// configured properties being asserted.
+func (r *icebergTablePropertiesOverlayResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	nameParts, err := decodeImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"The import ID should be the dot-separated namespace name, with dots inside a part escaped as \\.: "+err.Error(),
+		)
+
+		return
+	}
+
+	name, err := r.provider.unprefixNamespace(nameParts)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", "The import ID should be the namespace name in the catalog, including the name prefix: "+err.Error())
+
+		return
+	}
+
+	namespaceList, diags := types.ListValueFrom(ctx, types.StringType, name)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), encodeIdentifier(nameParts))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("namespace"), namespaceList)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("remove_properties_on_destroy"), false)...)
+}
+
+// apply sets the configured properties on every target table and, if removed
+// is non-nil, removes the keys of removed that are no longer configured. A
+// failure on one table doesn't stop the others; each one is reported
+// separately. Afterwards data.Properties holds only the properties that are in
+// place on all tables, so failed tables are retried by the next apply.
+func (r *icebergTablePropertiesOverlayResource) apply(ctx context.Context, data *icebergTablePropertiesOverlayResourceModel, removed map[string]string, diags *diag.Diagnostics) {
+	namespaceIdent, tableNames, desired := r.targets(ctx, data, diags)
+	if diags.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(encodeIdentifier(namespaceIdent))
+
+	tables, err := r.resolveTables(ctx, namespaceIdent, tableNames)
+	if err != nil {
+		diags.AddError("failed to list tables", err.Error())
+
+		return
+	}
+
+	managed := maps.Clone(desired)
+	for k, v := range removed {
+		if _, ok := managed[k]; !ok {
+			managed[k] = v
+		}
+	}
+
+	tflog.Info(ctx, "Enforcing table properties", map[string]any{"namespace": data.ID.ValueString(), "tables": len(tables)})
+
+	compliant := maps.Clone(desired)
+	for _, ident := range tables {
+		props, err := r.enforce(ctx, ident, desired, managed, diags)
+		if err != nil {
+			diags.AddError("failed to set properties on table "+strings.Join(ident, "."), err.Error())
+		}
+		keepCompliant(compliant, props)
+	}
+
+	var d diag.Diagnostics
+	data.Properties, d = types.MapValueFrom(ctx, types.StringType, compliant)
+	diags.Append(d...)
+}
+
+// enforce commits the changes needed for the table to have the desired
+// properties and returns the properties the table has afterwards, nil if it
+// couldn't be loaded. Keys in managed but not in desired are removed;
+// removals the catalog ignored are reported to diags.
+func (r *icebergTablePropertiesOverlayResource) enforce(ctx context.Context, ident table.Identifier, desired, managed map[string]string, diags *diag.Diagnostics) (iceberg.Properties, error) {
+	tbl, err := r.catalog.LoadTable(ctx, ident)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := properties.ComputeDelta(desired, managed, properties.Known(tbl.Properties()))
+	if delta.IsEmpty() {
+		return tbl.Properties(), nil
+	}
+
+	requirements := []table.Requirement{
+		table.AssertTableUUID(tbl.Metadata().TableUUID()),
+	}
+
+	metadata, _, err := r.catalog.CommitTable(ctx, ident, requirements, propertyUpdates(delta))
+	if err != nil {
+		return tbl.Properties(), err
+	}
+	r.provider.checkPropertyRemovals("table "+encodeIdentifier(ident), delta, metadata.Properties(), diags)
+
+	return metadata.Properties(), nil
+}
+
+// verifyApplied reads back the target tables of intended, the planned
+// overlay, and compares their properties with the configured ones, see
+// apply_verification.go.
+func (r *icebergTablePropertiesOverlayResource) verifyApplied(ctx context.Context, intended *icebergTablePropertiesOverlayResourceModel, diags *diag.Diagnostics) {
+	namespaceIdent, tableNames, desired := r.targets(ctx, intended, diags)
+	if diags.HasError() {
+		return
+	}
+
+	tables, err := r.resolveTables(ctx, namespaceIdent, tableNames)
+	if err != nil {
+		diags.AddError("failed to list tables", err.Error())
+
+		return
+	}
+
+	for _, ident := range tables {
+		tbl, err := r.catalog.LoadTable(ctx, ident)
+		if err != nil {
+			diags.AddError("failed to read table "+encodeIdentifier(ident)+" back after the apply", err.Error())
+
+			continue
+		}
+
+		report := newDriftReport("Table " + encodeIdentifier(ident))
+		report.compareProperties(desired, tbl.Properties())
+		report.addMismatchTo(diags)
+	}
+}
+
+// compliantProperties returns the desired properties that are set with the
+// desired value on every table.
+func (r *icebergTablePropertiesOverlayResource) compliantProperties(ctx context.Context, tables []table.Identifier, desired map[string]string) (map[string]string, error) {
+	compliant := maps.Clone(desired)
+	for _, ident := range tables {
+		tbl, err := r.catalog.LoadTable(ctx, ident)
+		if err != nil {
+			if isNotFoundError(err) {
+				// An explicitly listed table that doesn't exist satisfies nothing.
+				clear(compliant)
+
+				continue
+			}
+
+			return nil, err
+		}
+
+		keepCompliant(compliant, tbl.Properties())
+	}
+
+	return compliant, nil
+}
+
+// keepCompliant deletes the properties of compliant that props doesn't have
+// with the same value.
+func keepCompliant(compliant map[string]string, props iceberg.Properties) {
+	for k, v := range compliant {
+		if current, ok := props[k]; !ok || !properties.ValuesEqual(v, current) {
+			delete(compliant, k)
+		}
+	}
+}
+
+// targets decodes the namespace, the explicit table names (nil if all tables
+// of the namespace are targeted) and the properties of data. The namespace
+// and table names are the ones in the catalog, with the name prefix; an
+// existing overlay keeps using the namespace held in its ID.
+func (r *icebergTablePropertiesOverlayResource) targets(ctx context.Context, data *icebergTablePropertiesOverlayResourceModel, diags *diag.Diagnostics) (table.Identifier, []string, map[string]string) {
+	var namespaceName []string
+	diags.Append(data.Namespace.ElementsAs(ctx, &namespaceName, false)...)
+
+	var tableNames []string
+	if !data.Tables.IsNull() && !data.Tables.IsUnknown() {
+		tableNames = make([]string, 0)
+		diags.Append(data.Tables.ElementsAs(ctx, &tableNames, false)...)
+		if r.provider != nil && r.provider.prefixTableNames {
+			for i, name := range tableNames {
+				tableNames[i] = r.provider.namePrefix + name
+			}
+		}
+	}
+
+	properties := make(map[string]string)
+	if !data.Properties.IsNull() && !data.Properties.IsUnknown() {
+		diags.Append(data.Properties.ElementsAs(ctx, &properties, false)...)
+	}
+
+	return identifierFromID(data.ID, r.provider.prefixNamespace(catalog.ToIdentifier(namespaceName...))), tableNames, properties
+}
+
+// resolveTables returns the identifiers of the target tables. With no explicit
+// table names, all tables currently in the namespace are listed.
+func (r *icebergTablePropertiesOverlayResource) resolveTables(ctx context.Context, namespaceIdent table.Identifier, tableNames []string) ([]table.Identifier, error) {
+	if tableNames != nil {
+		tables := make([]table.Identifier, 0, len(tableNames))
+		for _, name := range tableNames {
+			tables = append(tables, append(append(table.Identifier{}, namespaceIdent...), name))
+		}
+
+		return tables, nil
+	}
+
+	tables := make([]table.Identifier, 0)
+	for ident, err := range r.catalog.ListTables(ctx, namespaceIdent) {
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, ident)
+	}
+
+	return tables, nil
+}
@@ -0,0 +1,149 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOverlayTestResource(t *testing.T, m *mockRESTCatalog) *icebergTablePropertiesOverlayResource {
+	t.Helper()
+
+	server := m.start(t)
+	r := &icebergTablePropertiesOverlayResource{
+		provider: &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()},
+	}
+
+	var diags diag.Diagnostics
+	r.ConfigureCatalog(context.Background(), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	return r
+}
+
+func overlayModel(t *testing.T, tables []string, props map[string]string) icebergTablePropertiesOverlayResourceModel {
+	t.Helper()
+	ctx := context.Background()
+
+	data := icebergTablePropertiesOverlayResourceModel{
+		Namespace:                 types.ListValueMust(types.StringType, []attr.Value{types.StringValue("db")}),
+		Tables:                    types.ListNull(types.StringType),
+		RemovePropertiesOnDestroy: types.BoolValue(false),
+	}
+	if tables != nil {
+		var d diag.Diagnostics
+		data.Tables, d = types.ListValueFrom(ctx, types.StringType, tables)
+		require.False(t, d.HasError(), "%v", d)
+	}
+
+	var d diag.Diagnostics
+	data.Properties, d = types.MapValueFrom(ctx, types.StringType, props)
+	require.False(t, d.HasError(), "%v", d)
+
+	return data
+}
+
+func TestTablePropertiesOverlayApply(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "a", map[string]string{"owner": "x"})
+	m.addTable("db", "b", map[string]string{"gc.enabled": "true"})
+	m.addTable("db", "locked", nil)
+	m.failCommits["db.locked"] = true
+	r := newOverlayTestResource(t, m)
+
+	desired := map[string]string{"gc.enabled": "true", "write.target-file-size-bytes": "536870912"}
+	data := overlayModel(t, nil, desired)
+
+	var diags diag.Diagnostics
+	r.apply(ctx, &data, nil, &diags)
+
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "failed to set properties on table db.locked", diags.Errors()[0].Summary())
+
+	assert.Equal(t, map[string]string{"owner": "x", "gc.enabled": "true", "write.target-file-size-bytes": "536870912"}, m.tableProperties("db", "a"))
+	assert.Equal(t, map[string]string{"gc.enabled": "true", "write.target-file-size-bytes": "536870912"}, m.tableProperties("db", "b"))
+
+	// Nothing holds on the failed table, so nothing is recorded as enforced and
+	// the next plan re-asserts all properties.
+	assert.Empty(t, data.Properties.Elements())
+	assert.Equal(t, "db", data.ID.ValueString())
+}
+
+func TestTablePropertiesOverlayApplyLoadsTablesOnce(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "a", map[string]string{"gc.enabled": "true"})
+	m.addTable("db", "b", nil)
+	r := newOverlayTestResource(t, m)
+
+	data := overlayModel(t, nil, map[string]string{"gc.enabled": "true"})
+
+	var diags diag.Diagnostics
+	r.apply(ctx, &data, nil, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	// The compliance of each table is taken from its load or commit.
+	assert.Equal(t, 2, m.tableLoads)
+	assert.Equal(t, map[string]attr.Value{"gc.enabled": types.StringValue("true")}, data.Properties.Elements())
+}
+
+func TestTablePropertiesOverlayExplicitTablesAndRemoval(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "a", map[string]string{"tier": "gold", "owner": "x"})
+	m.addTable("db", "b", nil)
+	r := newOverlayTestResource(t, m)
+
+	data := overlayModel(t, []string{"a"}, map[string]string{"gc.enabled": "true"})
+
+	var diags diag.Diagnostics
+	r.apply(ctx, &data, map[string]string{"tier": "gold"}, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	assert.Equal(t, map[string]string{"owner": "x", "gc.enabled": "true"}, m.tableProperties("db", "a"))
+	assert.Empty(t, m.tableProperties("db", "b"))
+
+	var props map[string]string
+	require.False(t, data.Properties.ElementsAs(ctx, &props, false).HasError())
+	assert.Equal(t, map[string]string{"gc.enabled": "true"}, props)
+}
+
+func TestTablePropertiesOverlayCompliance(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "a", map[string]string{"gc.enabled": "TRUE", "format-version": "2"})
+	m.addTable("db", "b", map[string]string{"gc.enabled": "true", "format-version": "1"})
+	r := newOverlayTestResource(t, m)
+
+	tables, err := r.resolveTables(ctx, []string{"db"}, nil)
+	require.NoError(t, err)
+
+	compliant, err := r.compliantProperties(ctx, tables, map[string]string{"gc.enabled": "true", "format-version": "2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"gc.enabled": "true"}, compliant)
+
+	compliant, err = r.compliantProperties(ctx, append(tables, []string{"db", "missing"}), map[string]string{"gc.enabled": "true"})
+	require.NoError(t, err)
+	assert.Empty(t, compliant)
+}
@@ -16,29 +16,27 @@ package provider
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"testing"
 
+	"github.com/apache/iceberg-go"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 func TestAccIcebergTable(t *testing.T) {
-	catalogURI := os.Getenv("ICEBERG_CATALOG_URI")
-	if catalogURI == "" {
-		catalogURI = "http://localhost:8181"
-	}
+	t.Parallel()
 
-	providerCfg := fmt.Sprintf(providerConfig, catalogURI)
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIcebergTableResourceConfig(providerCfg, "test_table"),
+				Config: testAccIcebergTableResourceConfig(providerCfg, namespace, "test_table"),
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("iceberg_table.test", "namespace.0", "db1"),
+					resource.TestCheckResourceAttr("iceberg_table.test", "namespace.0", namespace),
 					resource.TestCheckResourceAttr("iceberg_table.test", "name", "test_table"),
 					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.0.name", "id"),
 					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.0.type", "long"),
@@ -56,10 +54,10 @@ func TestAccIcebergTable(t *testing.T) {
 	})
 }
 
-func testAccIcebergTableResourceConfig(providerCfg string, tableName string) string {
+func testAccIcebergTableResourceConfig(providerCfg, namespace, tableName string) string {
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "db1" {
-  name = ["db1"]
+  name = [%q]
 }
 
 resource "iceberg_table" "test" {
@@ -82,13 +80,13 @@ resource "iceberg_table" "test" {
     ]
   }
 }
-`, tableName)
+`, namespace, tableName)
 }
 
-func testAccIcebergTableUpdateConfig(providerCfg string, tableName string) string {
+func testAccIcebergTableUpdateConfig(providerCfg, namespace, tableName string) string {
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "db1" {
-  name = ["db1"]
+  name = [%q]
 }
 
 resource "iceberg_table" "test" {
@@ -117,16 +115,14 @@ resource "iceberg_table" "test" {
     ]
   }
 }
-`, tableName)
+`, namespace, tableName)
 }
 
 func TestAccIcebergTableUpdate(t *testing.T) {
-	catalogURI := os.Getenv("ICEBERG_CATALOG_URI")
-	if catalogURI == "" {
-		catalogURI = "http://localhost:8181"
-	}
+	t.Parallel()
 
-	providerCfg := fmt.Sprintf(providerConfig, catalogURI)
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
 	tableName := "update_test_table"
 
 	resource.Test(t, resource.TestCase{
@@ -134,7 +130,7 @@ func TestAccIcebergTableUpdate(t *testing.T) {
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIcebergTableResourceConfig(providerCfg, tableName),
+				Config: testAccIcebergTableResourceConfig(providerCfg, namespace, tableName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "name", tableName),
 					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.#", "2"),
@@ -142,7 +138,7 @@ func TestAccIcebergTableUpdate(t *testing.T) {
 				),
 			},
 			{
-				Config: testAccIcebergTableUpdateConfig(providerCfg, tableName),
+				Config: testAccIcebergTableUpdateConfig(providerCfg, namespace, tableName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "name", tableName),
 					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.#", "3"),
@@ -156,12 +152,10 @@ func TestAccIcebergTableUpdate(t *testing.T) {
 }
 
 func TestAccIcebergTablePropertiesUpdate(t *testing.T) {
-	catalogURI := os.Getenv("ICEBERG_CATALOG_URI")
-	if catalogURI == "" {
-		catalogURI = "http://localhost:8181"
-	}
+	t.Parallel()
 
-	providerCfg := fmt.Sprintf(providerConfig, catalogURI)
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
 	tableName := "prop_update_test_table"
 
 	resource.Test(t, resource.TestCase{
@@ -169,20 +163,20 @@ func TestAccIcebergTablePropertiesUpdate(t *testing.T) {
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIcebergTablePropertiesConfig(providerCfg, tableName, `owner = "initial"`),
+				Config: testAccIcebergTablePropertiesConfig(providerCfg, namespace, tableName, `owner = "initial"`),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "user_properties.owner", "initial"),
 				),
 			},
 			{
-				Config: testAccIcebergTablePropertiesConfig(providerCfg, tableName, `owner = "updated", new_prop = "added"`),
+				Config: testAccIcebergTablePropertiesConfig(providerCfg, namespace, tableName, `owner = "updated", new_prop = "added"`),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "user_properties.owner", "updated"),
 					resource.TestCheckResourceAttr("iceberg_table.test", "user_properties.new_prop", "added"),
 				),
 			},
 			{
-				Config: testAccIcebergTablePropertiesConfig(providerCfg, tableName, `owner = "updated"`),
+				Config: testAccIcebergTablePropertiesConfig(providerCfg, namespace, tableName, `owner = "updated"`),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "user_properties.owner", "updated"),
 					resource.TestCheckNoResourceAttr("iceberg_table.test", "user_properties.new_prop"),
@@ -192,10 +186,10 @@ func TestAccIcebergTablePropertiesUpdate(t *testing.T) {
 	})
 }
 
-func testAccIcebergTablePropertiesConfig(providerCfg string, tableName string, props string) string {
+func testAccIcebergTablePropertiesConfig(providerCfg, namespace, tableName, props string) string {
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "db2" {
-  name = ["db2"]
+  name = [%q]
 }
 
 resource "iceberg_table" "test" {
@@ -215,26 +209,24 @@ resource "iceberg_table" "test" {
     ]
   }
 }
-`, tableName, props)
+`, namespace, tableName, props)
 }
 
 func TestAccIcebergTableFull(t *testing.T) {
-	catalogURI := os.Getenv("ICEBERG_CATALOG_URI")
-	if catalogURI == "" {
-		catalogURI = "http://localhost:8181"
-	}
+	t.Parallel()
 
-	providerCfg := fmt.Sprintf(providerConfig, catalogURI)
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIcebergTableFullConfig(providerCfg, "full_test_table"),
+				Config: testAccIcebergTableFullConfig(providerCfg, namespace, "full_test_table"),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.full", "name", "full_test_table"),
-					resource.TestCheckResourceAttr("iceberg_table.full", "namespace.0", "full_db"),
+					resource.TestCheckResourceAttr("iceberg_table.full", "namespace.0", namespace),
 					resource.TestCheckResourceAttr("iceberg_table.full", "user_properties.owner", "terraform"),
 					resource.TestCheckResourceAttr("iceberg_table.full", "user_properties.env", "test"),
 
@@ -292,10 +284,10 @@ func TestAccIcebergTableFull(t *testing.T) {
 	})
 }
 
-func testAccIcebergTableFullConfig(providerCfg string, tableName string) string {
+func testAccIcebergTableFullConfig(providerCfg, namespace, tableName string) string {
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "full_db" {
-  name = ["full_db"]
+  name = [%q]
 }
 
 resource "iceberg_table" "full" {
@@ -383,16 +375,14 @@ resource "iceberg_table" "full" {
     ]
   }
 }
-`, tableName)
+`, namespace, tableName)
 }
 
 func TestAccIcebergTablePartitionSpecAndSortOrder(t *testing.T) {
-	catalogURI := os.Getenv("ICEBERG_CATALOG_URI")
-	if catalogURI == "" {
-		catalogURI = "http://localhost:8181"
-	}
+	t.Parallel()
 
-	providerCfg := fmt.Sprintf(providerConfig, catalogURI)
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
 	tableName := "partition_sort_test_table"
 
 	resource.Test(t, resource.TestCase{
@@ -400,7 +390,7 @@ func TestAccIcebergTablePartitionSpecAndSortOrder(t *testing.T) {
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIcebergTablePartitionSortConfig(providerCfg, tableName, "bucket[16]", "asc"),
+				Config: testAccIcebergTablePartitionSortConfig(providerCfg, namespace, tableName, "bucket[16]", "asc"),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "name", tableName),
 					resource.TestCheckResourceAttr("iceberg_table.test", "partition_spec.fields.#", "1"),
@@ -417,7 +407,7 @@ func TestAccIcebergTablePartitionSpecAndSortOrder(t *testing.T) {
 				),
 			},
 			{
-				Config: testAccIcebergTablePartitionSortConfig(providerCfg, tableName, "bucket[32]", "desc"),
+				Config: testAccIcebergTablePartitionSortConfig(providerCfg, namespace, tableName, "bucket[32]", "desc"),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "name", tableName),
 					resource.TestCheckResourceAttr("iceberg_table.test", "partition_spec.fields.#", "1"),
@@ -427,15 +417,15 @@ func TestAccIcebergTablePartitionSpecAndSortOrder(t *testing.T) {
 				),
 			},
 			{
-				Config:      testAccIcebergTablePartitionSortConfig(providerCfg, tableName, "bucket[32]", "invalid"),
+				Config:      testAccIcebergTablePartitionSortConfig(providerCfg, namespace, tableName, "bucket[32]", "invalid"),
 				ExpectError: regexp.MustCompile(`(?s)Attribute sort_order\.fields\[0\]\.direction value must be one of:.*"asc".*"desc".*got: "invalid"`),
 			},
 			{
-				Config:      testAccIcebergTableInvalidNullOrderConfig(providerCfg, tableName),
+				Config:      testAccIcebergTableInvalidNullOrderConfig(providerCfg, namespace, tableName),
 				ExpectError: regexp.MustCompile(`(?s)Attribute sort_order\.fields\[0\]\.null_order value must be one of:.*"nulls-first".*"nulls-last".*got: "invalid"`),
 			},
 			{
-				Config: testAccIcebergTableNoPartitionSortConfig(providerCfg, tableName),
+				Config: testAccIcebergTableNoPartitionSortConfig(providerCfg, namespace, tableName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "name", tableName),
 					resource.TestCheckResourceAttr("iceberg_table.test", "partition_spec.fields.#", "0"),
@@ -446,10 +436,10 @@ func TestAccIcebergTablePartitionSpecAndSortOrder(t *testing.T) {
 	})
 }
 
-func testAccIcebergTableNoPartitionSortConfig(providerCfg string, tableName string) string {
+func testAccIcebergTableNoPartitionSortConfig(providerCfg, namespace, tableName string) string {
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "db3" {
-  name = ["db3"]
+  name = [%q]
 }
 
 resource "iceberg_table" "test" {
@@ -466,13 +456,13 @@ resource "iceberg_table" "test" {
     ]
   }
 }
-`, tableName)
+`, namespace, tableName)
 }
 
-func testAccIcebergTableInvalidNullOrderConfig(providerCfg string, tableName string) string {
+func testAccIcebergTableInvalidNullOrderConfig(providerCfg, namespace, tableName string) string {
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "db3" {
-  name = ["db3"]
+  name = [%q]
 }
 
 resource "iceberg_table" "test" {
@@ -499,13 +489,13 @@ resource "iceberg_table" "test" {
     ]
   }
 }
-`, tableName)
+`, namespace, tableName)
 }
 
-func testAccIcebergTablePartitionSortConfig(providerCfg string, tableName string, partitionTransform string, sortDirection string) string {
+func testAccIcebergTablePartitionSortConfig(providerCfg, namespace, tableName, partitionTransform, sortDirection string) string {
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "db3" {
-  name = ["db3"]
+  name = [%q]
 }
 
 resource "iceberg_table" "test" {
@@ -542,29 +532,27 @@ resource "iceberg_table" "test" {
     ]
   }
 }
-`, tableName, partitionTransform, sortDirection)
+`, namespace, tableName, partitionTransform, sortDirection)
 }
 
 func TestAccIcebergTableAddPartitionSpec(t *testing.T) {
-	catalogURI := os.Getenv("ICEBERG_CATALOG_URI")
-	if catalogURI == "" {
-		catalogURI = "http://localhost:8181"
-	}
+	t.Parallel()
 
-	providerCfg := fmt.Sprintf(providerConfig, catalogURI)
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIcebergTableResourceConfig(providerCfg, "partition_add"),
+				Config: testAccIcebergTableResourceConfig(providerCfg, namespace, "partition_add"),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "name", "partition_add"),
 				),
 			},
 			{
-				Config: testAccIcebergTablePartitionConfig(providerCfg, "partition_add"),
+				Config: testAccIcebergTablePartitionConfig(providerCfg, namespace, "partition_add"),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "partition_spec.fields.#", "1"),
 					resource.TestCheckResourceAttr("iceberg_table.test", "partition_spec.fields.0.name", "id_partition"),
@@ -575,26 +563,24 @@ func TestAccIcebergTableAddPartitionSpec(t *testing.T) {
 }
 
 func TestAccIcebergTableRename(t *testing.T) {
-	catalogURI := os.Getenv("ICEBERG_CATALOG_URI")
-	if catalogURI == "" {
-		catalogURI = "http://localhost:8181"
-	}
+	t.Parallel()
 
-	providerCfg := fmt.Sprintf(providerConfig, catalogURI)
+	providerCfg := testAccCatalogConfig(t)
+	namespace := testAccNamespace(t)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccIcebergTableResourceConfig(providerCfg, "rename_test"),
+				Config: testAccIcebergTableResourceConfig(providerCfg, namespace, "rename_test"),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "name", "rename_test"),
 					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.0.name", "id"),
 				),
 			},
 			{
-				Config: testAccIcebergTableRenameConfig(providerCfg, "rename_test_new", "id_new"),
+				Config: testAccIcebergTableRenameConfig(providerCfg, namespace, "rename_test_new", "id_new"),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("iceberg_table.test", "name", "rename_test_new"),
 					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.0.name", "id_new"),
@@ -604,10 +590,10 @@ func TestAccIcebergTableRename(t *testing.T) {
 	})
 }
 
-func testAccIcebergTablePartitionConfig(providerCfg string, tableName string) string {
+func testAccIcebergTablePartitionConfig(providerCfg, namespace, tableName string) string {
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "db_partition" {
-  name = ["db_partition"]
+  name = [%q]
 }
 
 resource "iceberg_table" "test" {
@@ -633,13 +619,13 @@ resource "iceberg_table" "test" {
     ]
   }
 }
-`, tableName)
+`, namespace, tableName)
 }
 
-func testAccIcebergTableRenameConfig(providerCfg string, tableName string, colName string) string {
+func testAccIcebergTableRenameConfig(providerCfg, namespace, tableName, colName string) string {
 	return providerCfg + fmt.Sprintf(`
 resource "iceberg_namespace" "db_rename" {
-  name = ["db_rename"]
+  name = [%q]
 }
 
 resource "iceberg_table" "test" {
@@ -662,5 +648,56 @@ resource "iceberg_table" "test" {
     ]
   }
 }
-`, tableName, colName)
+`, namespace, tableName, colName)
+}
+
+func TestAccIcebergTable_ServerFieldDocs(t *testing.T) {
+	t.Parallel()
+
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	config := fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_namespace" "db" {
+  name = ["db"]
+}
+
+resource "iceberg_table" "events" {
+  namespace = iceberg_namespace.db.name
+  name      = "events"
+  schema = {
+    fields = [
+      { id = 1, name = "id", type = "long", required = true },
+    ]
+  }
+}
+`, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				// The catalog documents the column; the configuration
+				// doesn't, so the plan stays empty.
+				PreConfig: func() {
+					m.evolveTable("db", "events", iceberg.NewSchema(1,
+						iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true, Doc: "Assigned by the ingest service"},
+					))
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("iceberg_table.events", "schema.fields.0.doc"),
+					resource.TestCheckResourceAttr("iceberg_table.events", "server_schema.fields.0.doc", "Assigned by the ingest service"),
+				),
+			},
+		},
+	})
 }
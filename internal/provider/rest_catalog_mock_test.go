@@ -0,0 +1,697 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/google/uuid"
+)
+
+// mockRESTCatalog is a minimal in-memory Iceberg REST catalog for unit tests.
+// It supports single-level namespaces and tables with a fixed one-column
+// schema whose properties can be read and changed.
+type mockRESTCatalog struct {
+	mu         sync.Mutex
+	namespaces map[string]map[string]string
+	tables     map[string]map[string]map[string]string
+	views      map[string]map[string]map[string]string
+	// noViews makes view endpoints fail as not implemented.
+	noViews bool
+	// dropDelay makes table drops take effect that long after they are
+	// answered, like a catalog that drops tables in the background.
+	dropDelay time.Duration
+	// failCommits lists "<namespace>.<table>" identifiers whose commits fail.
+	failCommits map[string]bool
+	// configRequests is the number of config requests.
+	configRequests int
+	// tableLoads is the number of table loads.
+	tableLoads int
+	// writes records the method and path of every request that isn't a GET or HEAD.
+	writes []string
+	// rejectCreates, if set, is the message of the error returned for creates.
+	rejectCreates string
+	// noStageCreate makes staged creates fail as not implemented.
+	noStageCreate bool
+	// ignoreStageCreate makes staged creates commit the table, like a catalog
+	// that doesn't know the stage-create flag.
+	ignoreStageCreate bool
+	// conflictCreates is the number of table and namespace creates to fail
+	// with a commit conflict, as if they raced a concurrent create.
+	conflictCreates int
+	// lostCreates is the number of table creates that are committed but
+	// answered with a commit conflict.
+	lostCreates int
+	// failNamespaces lists namespaces whose creates fail with a server error.
+	failNamespaces map[string]bool
+	// purged records the "<namespace>.<table>" identifiers of tables dropped
+	// with purgeRequested.
+	purged []string
+	// mangleSchemas makes table responses carry the id column as a string,
+	// like a proxy that rewrote them.
+	mangleSchemas bool
+	// lowercaseKeys makes namespace property keys lowercase when they are
+	// stored, like Glue.
+	lowercaseKeys bool
+	// ignoreRemovals makes namespace and table property removals succeed
+	// without removing anything, namespace removals being reported as done.
+	ignoreRemovals bool
+	// alteredProperties holds property values returned in place of the
+	// stored ones when namespaces and tables are loaded, like a catalog that
+	// rewrites values some time after accepting them. Write responses carry
+	// the stored values.
+	alteredProperties map[string]string
+	// metadataFiles holds the properties of the tables in the metadata files
+	// that can be registered, by location.
+	metadataFiles map[string]map[string]string
+	// evolved holds the schemas added to tables after the mock schema, by
+	// "<namespace>.<table>". The last one is the current schema.
+	evolved map[string][]*iceberg.Schema
+	// vendedCredentials, if set, is the config returned with the tables
+	// loaded with vended credentials requested, and the config of their
+	// storage credentials.
+	vendedCredentials map[string]string
+	// corrupted holds functions that edit the metadata of tables as it is
+	// sent, by "<namespace>.<table>", to serve inconsistent metadata.
+	corrupted map[string]func(metadata map[string]any)
+}
+
+func newMockRESTCatalog() *mockRESTCatalog {
+	return &mockRESTCatalog{
+		namespaces:     make(map[string]map[string]string),
+		tables:         make(map[string]map[string]map[string]string),
+		views:          make(map[string]map[string]map[string]string),
+		failCommits:    make(map[string]bool),
+		failNamespaces: make(map[string]bool),
+		metadataFiles:  make(map[string]map[string]string),
+		evolved:        make(map[string][]*iceberg.Schema),
+		corrupted:      make(map[string]func(map[string]any)),
+	}
+}
+
+func (m *mockRESTCatalog) addTable(namespace, name string, props map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.namespaces[namespace]; !ok {
+		m.namespaces[namespace] = make(map[string]string)
+		m.tables[namespace] = make(map[string]map[string]string)
+	}
+	m.tables[namespace][name] = maps.Clone(props)
+	if m.tables[namespace][name] == nil {
+		m.tables[namespace][name] = make(map[string]string)
+	}
+}
+
+// evolveTable adds schemas to the table, in order, making the last one its
+// current schema. The mock schema stays in the metadata as schema 0.
+func (m *mockRESTCatalog) evolveTable(namespace, name string, schemas ...*iceberg.Schema) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evolved[namespace+"."+name] = append(m.evolved[namespace+"."+name], schemas...)
+}
+
+// corruptTable makes corrupt edit the JSON metadata of the table every time it
+// is loaded.
+func (m *mockRESTCatalog) corruptTable(namespace, name string, corrupt func(metadata map[string]any)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.corrupted[namespace+"."+name] = corrupt
+}
+
+// addMetadataFile adds a metadata file of a table with props at location,
+// from which the table can be registered.
+func (m *mockRESTCatalog) addMetadataFile(location string, props map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.metadataFiles[location] = maps.Clone(props)
+}
+
+func (m *mockRESTCatalog) addView(namespace, name string, props map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.namespaces[namespace]; !ok {
+		m.namespaces[namespace] = make(map[string]string)
+		m.tables[namespace] = make(map[string]map[string]string)
+	}
+	if m.views[namespace] == nil {
+		m.views[namespace] = make(map[string]map[string]string)
+	}
+	m.views[namespace][name] = maps.Clone(props)
+}
+
+func (m *mockRESTCatalog) namespaceProperties(namespace string) map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return maps.Clone(m.namespaces[namespace])
+}
+
+func (m *mockRESTCatalog) tableProperties(namespace, name string) map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return maps.Clone(m.tables[namespace][name])
+}
+
+func (m *mockRESTCatalog) start(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/config", func(w http.ResponseWriter, _ *http.Request) {
+		m.mu.Lock()
+		m.configRequests++
+		m.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"defaults": map[string]string{}, "overrides": map[string]string{}})
+	})
+	mux.HandleFunc("GET /v1/namespaces/{ns}", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		props, ok := m.namespaces[r.PathValue("ns")]
+		if !ok {
+			writeRESTError(w, http.StatusNotFound, "NoSuchNamespaceException")
+
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"namespace": []string{r.PathValue("ns")}, "properties": m.altered(props)})
+	})
+	mux.HandleFunc("POST /v1/namespaces", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.writes = append(m.writes, r.Method+" "+r.URL.Path)
+
+		var body struct {
+			Namespace  []string          `json:"namespace"`
+			Properties map[string]string `json:"properties"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Namespace) != 1 {
+			writeRESTError(w, http.StatusBadRequest, "BadRequestException")
+
+			return
+		}
+
+		ns := body.Namespace[0]
+		switch {
+		case m.namespaces[ns] != nil:
+			writeRESTError(w, http.StatusConflict, "AlreadyExistsException")
+		case m.failNamespaces[ns]:
+			writeRESTError(w, http.StatusInternalServerError, "ServerError")
+		case m.conflictCreates > 0:
+			m.conflictCreates--
+			writeRESTError(w, http.StatusConflict, "CommitFailedException")
+		default:
+			m.namespaces[ns] = m.storedKeys(body.Properties)
+			if m.namespaces[ns] == nil {
+				m.namespaces[ns] = make(map[string]string)
+			}
+			m.tables[ns] = make(map[string]map[string]string)
+			writeJSON(w, http.StatusOK, map[string]any{"namespace": body.Namespace, "properties": m.namespaces[ns]})
+		}
+	})
+	mux.HandleFunc("POST /v1/namespaces/{ns}/properties", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.writes = append(m.writes, r.Method+" "+r.URL.Path)
+
+		props, ok := m.namespaces[r.PathValue("ns")]
+		if !ok {
+			writeRESTError(w, http.StatusNotFound, "NoSuchNamespaceException")
+
+			return
+		}
+
+		var body struct {
+			Removals []string          `json:"removals"`
+			Updates  map[string]string `json:"updates"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeRESTError(w, http.StatusBadRequest, "BadRequestException")
+
+			return
+		}
+
+		removed := []string{}
+		for _, k := range body.Removals {
+			if m.lowercaseKeys {
+				k = strings.ToLower(k)
+			}
+			if _, ok := props[k]; ok {
+				if !m.ignoreRemovals {
+					delete(props, k)
+				}
+				removed = append(removed, k)
+			}
+		}
+		maps.Copy(props, m.storedKeys(body.Updates))
+		writeJSON(w, http.StatusOK, map[string]any{"updated": slices.Collect(maps.Keys(body.Updates)), "removed": removed, "missing": []string{}})
+	})
+	mux.HandleFunc("DELETE /v1/namespaces/{ns}", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		ns := r.PathValue("ns")
+		m.writes = append(m.writes, r.Method+" "+r.URL.Path)
+		switch {
+		case m.namespaces[ns] == nil:
+			writeRESTError(w, http.StatusNotFound, "NoSuchNamespaceException")
+		case len(m.tables[ns]) > 0:
+			writeRESTError(w, http.StatusConflict, "NamespaceNotEmptyException")
+		default:
+			delete(m.namespaces, ns)
+			delete(m.tables, ns)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	mux.HandleFunc("GET /v1/namespaces/{ns}/tables", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		ns := r.PathValue("ns")
+		tables, ok := m.tables[ns]
+		if !ok {
+			writeRESTError(w, http.StatusNotFound, "NoSuchNamespaceException")
+
+			return
+		}
+		names := make([]string, 0, len(tables))
+		for name := range tables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		identifiers := make([]map[string]any, 0, len(names))
+		for _, name := range names {
+			identifiers = append(identifiers, map[string]any{"namespace": []string{ns}, "name": name})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"identifiers": identifiers})
+	})
+	mux.HandleFunc("POST /v1/namespaces/{ns}/tables", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		ns := r.PathValue("ns")
+		m.writes = append(m.writes, r.Method+" "+r.URL.Path)
+
+		var body struct {
+			Name        string            `json:"name"`
+			StageCreate bool              `json:"stage-create"`
+			Properties  map[string]string `json:"properties"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeRESTError(w, http.StatusBadRequest, "BadRequestException")
+
+			return
+		}
+
+		switch {
+		case body.StageCreate && m.noStageCreate:
+			writeRESTError(w, http.StatusNotImplemented, "UnsupportedOperationException")
+		case m.tables[ns] == nil:
+			writeRESTError(w, http.StatusNotFound, "NoSuchNamespaceException")
+		case m.tables[ns][body.Name] != nil:
+			writeRESTError(w, http.StatusConflict, "AlreadyExistsException")
+		case m.rejectCreates != "":
+			writeRESTErrorMessage(w, http.StatusBadRequest, "BadRequestException", m.rejectCreates)
+		case !body.StageCreate && m.conflictCreates > 0:
+			m.conflictCreates--
+			writeRESTError(w, http.StatusConflict, "CommitFailedException")
+		case !body.StageCreate && m.lostCreates > 0:
+			m.lostCreates--
+			m.tables[ns][body.Name] = maps.Clone(body.Properties)
+			if m.tables[ns][body.Name] == nil {
+				m.tables[ns][body.Name] = make(map[string]string)
+			}
+			writeRESTError(w, http.StatusConflict, "CommitFailedException")
+		case body.StageCreate && !m.ignoreStageCreate:
+			metadata, err := mockTableMetadata(ns, body.Name, body.Properties)
+			if err != nil {
+				t.Errorf("failed to build table metadata: %v", err)
+				writeRESTError(w, http.StatusInternalServerError, "ServerError")
+
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"metadata": metadata, "config": map[string]string{}})
+		default:
+			m.tables[ns][body.Name] = maps.Clone(body.Properties)
+			if m.tables[ns][body.Name] == nil {
+				m.tables[ns][body.Name] = make(map[string]string)
+			}
+			m.writeTable(t, w, r, ns, body.Name)
+		}
+	})
+	mux.HandleFunc("POST /v1/namespaces/{ns}/register", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		ns := r.PathValue("ns")
+		m.writes = append(m.writes, r.Method+" "+r.URL.Path)
+
+		var body struct {
+			Name             string `json:"name"`
+			MetadataLocation string `json:"metadata-location"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeRESTError(w, http.StatusBadRequest, "BadRequestException")
+
+			return
+		}
+
+		props, ok := m.metadataFiles[body.MetadataLocation]
+		switch {
+		case m.tables[ns] == nil:
+			writeRESTError(w, http.StatusNotFound, "NoSuchNamespaceException")
+		case m.tables[ns][body.Name] != nil:
+			writeRESTError(w, http.StatusConflict, "AlreadyExistsException")
+		case !ok:
+			writeRESTErrorMessage(w, http.StatusBadRequest, "BadRequestException", "Cannot read metadata file "+body.MetadataLocation)
+		default:
+			m.tables[ns][body.Name] = maps.Clone(props)
+			if m.tables[ns][body.Name] == nil {
+				m.tables[ns][body.Name] = make(map[string]string)
+			}
+			m.writeTable(t, w, r, ns, body.Name)
+		}
+	})
+	mux.HandleFunc("DELETE /v1/namespaces/{ns}/tables/{table}", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		ns, name := r.PathValue("ns"), r.PathValue("table")
+		m.writes = append(m.writes, r.Method+" "+r.URL.Path)
+		if _, ok := m.tables[ns][name]; !ok {
+			writeRESTError(w, http.StatusNotFound, "NoSuchTableException")
+
+			return
+		}
+		if m.dropDelay > 0 {
+			time.AfterFunc(m.dropDelay, func() {
+				m.mu.Lock()
+				defer m.mu.Unlock()
+
+				delete(m.tables[ns], name)
+			})
+		} else {
+			delete(m.tables[ns], name)
+		}
+		if r.URL.Query().Get("purgeRequested") == "true" {
+			m.purged = append(m.purged, ns+"."+name)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /v1/namespaces/{ns}/tables/{table}", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.tableLoads++
+		m.writeTable(t, w, r, r.PathValue("ns"), r.PathValue("table"))
+	})
+	mux.HandleFunc("POST /v1/namespaces/{ns}/tables/{table}", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		ns, name := r.PathValue("ns"), r.PathValue("table")
+		m.writes = append(m.writes, r.Method+" "+r.URL.Path)
+		if m.failCommits[ns+"."+name] {
+			writeRESTError(w, http.StatusForbidden, "ForbiddenException")
+
+			return
+		}
+
+		props, ok := m.tables[ns][name]
+		if !ok {
+			writeRESTError(w, http.StatusNotFound, "NoSuchTableException")
+
+			return
+		}
+
+		var body struct {
+			Updates []struct {
+				Action   string            `json:"action"`
+				Updates  map[string]string `json:"updates"`
+				Removals []string          `json:"removals"`
+			} `json:"updates"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeRESTError(w, http.StatusBadRequest, "BadRequestException")
+
+			return
+		}
+		for _, u := range body.Updates {
+			switch u.Action {
+			case "set-properties":
+				maps.Copy(props, u.Updates)
+			case "remove-properties":
+				if m.ignoreRemovals {
+					continue
+				}
+				for _, k := range u.Removals {
+					delete(props, k)
+				}
+			}
+		}
+
+		m.writeTable(t, w, r, ns, name)
+	})
+	mux.HandleFunc("GET /v1/namespaces/{ns}/views", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		ns := r.PathValue("ns")
+		if m.noViews {
+			writeRESTError(w, http.StatusNotImplemented, "UnsupportedOperationException")
+
+			return
+		}
+		if _, ok := m.namespaces[ns]; !ok {
+			writeRESTError(w, http.StatusNotFound, "NoSuchNamespaceException")
+
+			return
+		}
+		names := slices.Sorted(maps.Keys(m.views[ns]))
+
+		identifiers := make([]map[string]any, 0, len(names))
+		for _, name := range names {
+			identifiers = append(identifiers, map[string]any{"namespace": []string{ns}, "name": name})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"identifiers": identifiers})
+	})
+	mux.HandleFunc("GET /v1/namespaces/{ns}/views/{view}", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		ns, name := r.PathValue("ns"), r.PathValue("view")
+		props, ok := m.views[ns][name]
+		if !ok || m.noViews {
+			writeRESTError(w, http.StatusNotFound, "NoSuchViewException")
+
+			return
+		}
+
+		location := "file:///tmp/warehouse/" + ns + "/" + name
+		writeJSON(w, http.StatusOK, map[string]any{
+			"metadata-location": location + "/metadata/00000.metadata.json",
+			"metadata": map[string]any{
+				"view-uuid":          uuid.NewSHA1(uuid.NameSpaceURL, []byte(ns+"."+name)).String(),
+				"format-version":     1,
+				"location":           location,
+				"current-version-id": 1,
+				"properties":         props,
+				"versions": []map[string]any{{
+					"version-id":        1,
+					"timestamp-ms":      0,
+					"schema-id":         0,
+					"default-namespace": []string{ns},
+					"summary":           map[string]string{},
+					"representations":   []map[string]any{{"type": "sql", "sql": "SELECT 1", "dialect": "spark"}},
+				}},
+				"schemas":     []map[string]any{{"schema-id": 0, "type": "struct", "fields": []any{}}},
+				"version-log": []map[string]any{{"timestamp-ms": 0, "version-id": 1}},
+			},
+		})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			m.writes = append(m.writes, r.Method+" "+r.URL.Path)
+		}
+		writeRESTError(w, http.StatusNotImplemented, "UnsupportedOperationException")
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// altered returns props as loaded, with the values of alteredProperties in
+// place of the stored ones.
+func (m *mockRESTCatalog) altered(props map[string]string) map[string]string {
+	if len(m.alteredProperties) == 0 || props == nil {
+		return props
+	}
+
+	result := maps.Clone(props)
+	for k, v := range m.alteredProperties {
+		if _, ok := result[k]; ok {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+// storedKeys returns a copy of props as the mock stores them.
+func (m *mockRESTCatalog) storedKeys(props map[string]string) map[string]string {
+	if !m.lowercaseKeys || props == nil {
+		return maps.Clone(props)
+	}
+
+	stored := make(map[string]string, len(props))
+	for k, v := range props {
+		stored[strings.ToLower(k)] = v
+	}
+
+	return stored
+}
+
+// writeTable responds with the load-table result for the table.
+func (m *mockRESTCatalog) writeTable(t *testing.T, w http.ResponseWriter, r *http.Request, ns, name string) {
+	props, ok := m.tables[ns][name]
+	if !ok {
+		writeRESTError(w, http.StatusNotFound, "NoSuchTableException")
+
+		return
+	}
+
+	schema := mockTableSchema()
+	if m.mangleSchemas {
+		schema = iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.String, Required: true})
+	}
+
+	if r.Method == http.MethodGet {
+		props = m.altered(props)
+	}
+
+	metadata, err := mockTableMetadataWithSchema(ns, name, props, schema)
+	if evolved := m.evolved[ns+"."+name]; err == nil && len(evolved) > 0 {
+		metadata, err = evolveMetadata(metadata, evolved)
+	}
+	if err != nil {
+		t.Errorf("failed to build table metadata: %v", err)
+		writeRESTError(w, http.StatusInternalServerError, "ServerError")
+
+		return
+	}
+
+	var body any = metadata
+	if corrupt := m.corrupted[ns+"."+name]; corrupt != nil {
+		raw, err := json.Marshal(metadata)
+		var edited map[string]any
+		if err == nil {
+			err = json.Unmarshal(raw, &edited)
+		}
+		if err != nil {
+			t.Errorf("failed to encode table metadata: %v", err)
+			writeRESTError(w, http.StatusInternalServerError, "ServerError")
+
+			return
+		}
+		corrupt(edited)
+		body = edited
+	}
+
+	resp := map[string]any{
+		"metadata-location": metadata.Location() + "/metadata/00000.metadata.json",
+		"metadata":          body,
+		"config":            map[string]string{},
+	}
+	if m.vendedCredentials != nil && r.Header.Get(accessDelegationHeader) == "vended-credentials" {
+		resp["config"] = m.vendedCredentials
+		resp["storage-credentials"] = []map[string]any{{"prefix": metadata.Location(), "config": m.vendedCredentials}}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// mockTableMetadata builds the metadata of a mock table. The table UUID is
+// derived from its name so it is stable across requests.
+func mockTableMetadata(ns, name string, props map[string]string) (table.Metadata, error) {
+	return mockTableMetadataWithSchema(ns, name, props, mockTableSchema())
+}
+
+// evolveMetadata returns metadata with schemas added and the last one made
+// current.
+func evolveMetadata(metadata table.Metadata, schemas []*iceberg.Schema) (table.Metadata, error) {
+	b, err := table.MetadataBuilderFromBase(metadata, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, schema := range schemas {
+		if err := b.AddSchema(schema); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.SetCurrentSchemaID(-1); err != nil {
+		return nil, err
+	}
+
+	return b.Build()
+}
+
+// mockTableSchema returns the schema of every mock table.
+func mockTableSchema() *iceberg.Schema {
+	return iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+}
+
+func mockTableMetadataWithSchema(ns, name string, props map[string]string, schema *iceberg.Schema) (table.Metadata, error) {
+	location := "file:///tmp/warehouse/" + ns + "/" + name
+
+	return table.NewMetadataWithUUID(schema, iceberg.UnpartitionedSpec, table.UnsortedSortOrder, location,
+		maps.Clone(props), uuid.NewSHA1(uuid.NameSpaceURL, []byte(ns+"."+name)))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeRESTError(w http.ResponseWriter, status int, errType string) {
+	writeRESTErrorMessage(w, status, errType, errType)
+}
+
+func writeRESTErrorMessage(w http.ResponseWriter, status int, errType, message string) {
+	writeJSON(w, status, map[string]any{"error": map[string]any{"message": message, "type": errType, "code": status}})
+}
@@ -0,0 +1,195 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// defaultMaxRetries is how often a request is retried unless max_retries
+	// is set.
+	defaultMaxRetries = 3
+	// defaultRetryMaxBackoff is the longest wait between retries unless
+	// retry_max_backoff is set.
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// retryBaseDelay is the wait before the first retry; each further retry waits
+// twice as long, up to the maximum backoff. It is a variable for tests.
+var retryBaseDelay = 500 * time.Millisecond
+
+// retryConfig returns max_retries and retry_max_backoff with their defaults.
+// An invalid retry_max_backoff is reported as an error.
+func retryConfig(data icebergProviderModel, diags *diag.Diagnostics) (int, time.Duration) {
+	maxRetries := defaultMaxRetries
+	if !data.MaxRetries.IsNull() && !data.MaxRetries.IsUnknown() {
+		maxRetries = int(data.MaxRetries.ValueInt64())
+	}
+
+	maxBackoff := defaultRetryMaxBackoff
+	if !data.RetryMaxBackoff.IsNull() && !data.RetryMaxBackoff.IsUnknown() {
+		d, err := time.ParseDuration(data.RetryMaxBackoff.ValueString())
+		if err != nil || d <= 0 {
+			diags.AddAttributeError(
+				path.Root("retry_max_backoff"),
+				"Invalid retry_max_backoff",
+				fmt.Sprintf("retry_max_backoff must be a positive duration such as 30s or 2m, got %q.", data.RetryMaxBackoff.ValueString()),
+			)
+
+			return maxRetries, defaultRetryMaxBackoff
+		}
+		maxBackoff = d
+	}
+
+	return maxRetries, maxBackoff
+}
+
+// retryTransport retries requests the server rejected because it is
+// overloaded or rate limits the client: responses with status 429, 502, 503
+// or 504. It waits exponentially longer between attempts, or as long as the
+// Retry-After header of the response asks, but never longer than maxBackoff.
+//
+// Requests that aren't idempotent, such as the POST of a commit, may have
+// been applied even though the server failed. They are only retried when the
+// server says that is safe by sending Retry-After, or when they carry an
+// Idempotency-Key the server deduplicates them by.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	maxBackoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	for attempt := 0; attempt < t.maxRetries; attempt++ {
+		if err != nil || !shouldRetry(req, resp) {
+			return resp, err
+		}
+
+		body, ok := replayableBody(req)
+		if !ok {
+			return resp, nil
+		}
+
+		delay := t.backoff(attempt, resp)
+		tflog.Debug(req.Context(), "Retrying request", map[string]any{
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"status":  resp.StatusCode,
+			"attempt": attempt + 2,
+			"delay":   delay.String(),
+		})
+
+		// Drain the body so the connection can be reused.
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		retry := req.Clone(req.Context())
+		retry.Body = body
+		resp, err = t.next.RoundTrip(retry)
+	}
+
+	return resp, err
+}
+
+// shouldRetry reports whether resp is a transient failure that retrying req
+// can't make worse.
+func shouldRetry(req *http.Request, resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return resp.Header.Get("Retry-After") != "" || req.Header.Get("Idempotency-Key") != ""
+	}
+}
+
+// replayableBody returns a fresh copy of the body of req for a retry, false
+// if the body can't be read again.
+func replayableBody(req *http.Request) (io.ReadCloser, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req.Body, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+
+	return body, true
+}
+
+// backoff returns how long to wait before retry attempt+1: what Retry-After
+// asks for, otherwise retryBaseDelay doubled for every earlier retry, at most
+// maxBackoff.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	delay := retryBaseDelay << attempt
+	if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		delay = after
+	}
+
+	return min(delay, t.maxBackoff)
+}
+
+// parseRetryAfter parses a Retry-After header, either a number of seconds or
+// an HTTP date, into the time to wait from now.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// withRetries wraps next in a retryTransport unless retries are turned off.
+func (p *icebergProvider) withRetries(next http.RoundTripper) http.RoundTripper {
+	if p.maxRetries <= 0 {
+		return next
+	}
+
+	return &retryTransport{next: next, maxRetries: p.maxRetries, maxBackoff: p.retryMaxBackoff}
+}
@@ -0,0 +1,237 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setRetryBaseDelay(t *testing.T, d time.Duration) {
+	t.Helper()
+
+	previous := retryBaseDelay
+	retryBaseDelay = d
+	t.Cleanup(func() { retryBaseDelay = previous })
+}
+
+// newFlakyServer returns a server that responds to the first failures
+// requests with status, setting Retry-After to retryAfter if it isn't empty,
+// and to later ones with 200. It records the body of every request.
+func newFlakyServer(t *testing.T, failures, status int, retryAfter string) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		n := len(bodies)
+		mu.Unlock()
+
+		if n <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			http.Error(w, http.StatusText(status), status)
+
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]string(nil), bodies...)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	setRetryBaseDelay(t, time.Millisecond)
+	ctx := context.Background()
+	client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport, maxRetries: 3, maxBackoff: time.Second}}
+
+	for _, tc := range []struct {
+		name         string
+		method       string
+		failures     int
+		status       int
+		retryAfter   string
+		idempotent   bool
+		wantStatus   int
+		wantAttempts int
+	}{
+		{name: "rate limited twice", method: http.MethodGet, failures: 2, status: http.StatusTooManyRequests, wantStatus: http.StatusOK, wantAttempts: 3},
+		{name: "gateway timeout", method: http.MethodDelete, failures: 1, status: http.StatusGatewayTimeout, wantStatus: http.StatusOK, wantAttempts: 2},
+		{name: "retries exhausted", method: http.MethodGet, failures: 5, status: http.StatusServiceUnavailable, wantStatus: http.StatusServiceUnavailable, wantAttempts: 4},
+		{name: "not transient", method: http.MethodGet, failures: 1, status: http.StatusInternalServerError, wantStatus: http.StatusInternalServerError, wantAttempts: 1},
+		{name: "commit", method: http.MethodPost, failures: 1, status: http.StatusServiceUnavailable, wantStatus: http.StatusServiceUnavailable, wantAttempts: 1},
+		{name: "commit with retry-after", method: http.MethodPost, failures: 2, status: http.StatusTooManyRequests, retryAfter: "0", wantStatus: http.StatusOK, wantAttempts: 3},
+		{name: "commit with idempotency key", method: http.MethodPost, failures: 1, status: http.StatusBadGateway, idempotent: true, wantStatus: http.StatusOK, wantAttempts: 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server, bodies := newFlakyServer(t, tc.failures, tc.status, tc.retryAfter)
+
+			req, err := http.NewRequestWithContext(ctx, tc.method, server.URL, strings.NewReader(`{"updates":[]}`))
+			require.NoError(t, err)
+			if tc.idempotent {
+				req.Header.Set("Idempotency-Key", "0190ad8e-8d8c-7d0b-b1b8-5d5b1e6e0f3a")
+			}
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tc.wantStatus, resp.StatusCode)
+			got := bodies()
+			assert.Len(t, got, tc.wantAttempts)
+			for _, body := range got {
+				assert.Equal(t, `{"updates":[]}`, body, "every attempt sends the whole body")
+			}
+		})
+	}
+
+	t.Run("canceled", func(t *testing.T) {
+		server, _ := newFlakyServer(t, 1, http.StatusTooManyRequests, "60")
+
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		_, err = client.Do(req)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	setRetryBaseDelay(t, 500*time.Millisecond)
+	transport := &retryTransport{maxBackoff: 3 * time.Second}
+	noHeader := &http.Response{Header: http.Header{}}
+
+	assert.Equal(t, 500*time.Millisecond, transport.backoff(0, noHeader))
+	assert.Equal(t, time.Second, transport.backoff(1, noHeader))
+	assert.Equal(t, 2*time.Second, transport.backoff(2, noHeader))
+	assert.Equal(t, 3*time.Second, transport.backoff(3, noHeader), "capped at the maximum backoff")
+	assert.Equal(t, 2*time.Second, transport.backoff(0, &http.Response{Header: http.Header{"Retry-After": {"2"}}}))
+	assert.Equal(t, 3*time.Second, transport.backoff(0, &http.Response{Header: http.Header{"Retry-After": {"120"}}}))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: ""},
+		{value: "5", want: 5 * time.Second, wantOK: true},
+		{value: "Sun, 01 Jun 2025 12:00:10 GMT", want: 10 * time.Second, wantOK: true},
+		{value: "Sun, 01 Jun 2025 11:59:00 GMT", want: 0, wantOK: true},
+		{value: "-1"},
+		{value: "soon"},
+	} {
+		got, ok := parseRetryAfter(tc.value, now)
+		assert.Equal(t, tc.wantOK, ok, tc.value)
+		assert.Equal(t, tc.want, got, tc.value)
+	}
+}
+
+func TestProviderRetries(t *testing.T) {
+	setRetryBaseDelay(t, time.Millisecond)
+
+	m := newMockRESTCatalog()
+	m.addTable("db", "events", nil)
+	server := m.start(t)
+
+	// The catalog rate limits the first two loads of the table.
+	var mu sync.Mutex
+	var limited int
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		limit := r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/tables/events") && limited < 2
+		if limit {
+			limited++
+		}
+		mu.Unlock()
+		if limit {
+			writeRESTErrorMessage(w, http.StatusTooManyRequests, "SlowDownException", "rate limit exceeded")
+
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		mu.Lock()
+		limited = 0
+		mu.Unlock()
+
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+
+		cat, err := p.NewCatalog(context.Background(), "iceberg_table")
+		require.NoError(t, err)
+		_, err = cat.LoadTable(context.Background(), table.Identifier{"db", "events"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, limited)
+	})
+
+	t.Run("turned off", func(t *testing.T) {
+		mu.Lock()
+		limited = 0
+		mu.Unlock()
+
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+			"max_retries": tftypes.NewValue(tftypes.Number, 0),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+
+		cat, err := p.NewCatalog(context.Background(), "iceberg_table")
+		require.NoError(t, err)
+		_, err = cat.LoadTable(context.Background(), table.Identifier{"db", "events"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rate limit exceeded")
+	})
+
+	t.Run("invalid backoff", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri":       tftypes.NewValue(tftypes.String, server.URL),
+			"retry_max_backoff": tftypes.NewValue(tftypes.String, "forever"),
+		})
+		require.True(t, diags.HasError())
+		assert.Equal(t, "Invalid retry_max_backoff", diags.Errors()[0].Summary())
+	})
+}
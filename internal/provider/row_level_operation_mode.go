@@ -0,0 +1,96 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Row-level operation modes, the values of the write.*.mode properties.
+const (
+	rowLevelModeCopyOnWrite = "copy-on-write"
+	rowLevelModeMergeOnRead = "merge-on-read"
+)
+
+// rowLevelOperationModeProperties are the properties the
+// row_level_operation_mode attribute of a table sets, all to the same mode.
+var rowLevelOperationModeProperties = []string{"write.delete.mode", "write.update.mode", "write.merge.mode"}
+
+// withRowLevelOperationMode returns a copy of props with every
+// rowLevelOperationModeProperties key set to mode. props is returned as is if
+// the mode isn't set.
+func withRowLevelOperationMode(props map[string]string, mode types.String) map[string]string {
+	if mode.IsNull() || mode.IsUnknown() {
+		return props
+	}
+
+	result := maps.Clone(props)
+	if result == nil {
+		result = make(map[string]string)
+	}
+	for _, key := range rowLevelOperationModeProperties {
+		result[key] = mode.ValueString()
+	}
+
+	return result
+}
+
+// syncRowLevelOperationMode returns the mode of the properties of the server
+// as the new value of the row_level_operation_mode attribute. If the
+// properties don't all hold the same mode, for example because one was
+// changed by another client, the attribute is null so the plan sets them all
+// again. A mode that isn't managed by Terraform stays null.
+func syncRowLevelOperationMode(mode types.String, server map[string]string) types.String {
+	if mode.IsNull() {
+		return mode
+	}
+
+	value, ok := server[rowLevelOperationModeProperties[0]]
+	if !ok {
+		return types.StringNull()
+	}
+	for _, key := range rowLevelOperationModeProperties[1:] {
+		if server[key] != value {
+			return types.StringNull()
+		}
+	}
+
+	return types.StringValue(value)
+}
+
+// validateRowLevelOperationModeConfig rejects configurations that set the
+// mode with the row_level_operation_mode attribute and any of the properties
+// it expands to in user_properties.
+func validateRowLevelOperationModeConfig(mode types.String, userProperties types.Map, diags *diag.Diagnostics) {
+	if mode.IsNull() || userProperties.IsNull() || userProperties.IsUnknown() {
+		return
+	}
+
+	for _, key := range rowLevelOperationModeProperties {
+		if _, ok := userProperties.Elements()[key]; ok {
+			diags.AddAttributeError(
+				path.Root("row_level_operation_mode"),
+				"Conflicting row_level_operation_mode configuration",
+				fmt.Sprintf("row_level_operation_mode sets the %s property, which user_properties sets as well. Remove %[1]s from user_properties.", key),
+			)
+		}
+	}
+}
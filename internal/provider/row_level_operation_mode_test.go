@@ -0,0 +1,150 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRowLevelOperationMode(t *testing.T) {
+	props := map[string]string{"owner": "data"}
+
+	assert.Equal(t, props, withRowLevelOperationMode(props, types.StringNull()))
+	assert.Equal(t, map[string]string{
+		"owner":             "data",
+		"write.delete.mode": "merge-on-read",
+		"write.update.mode": "merge-on-read",
+		"write.merge.mode":  "merge-on-read",
+	}, withRowLevelOperationMode(props, types.StringValue("merge-on-read")))
+	assert.Equal(t, map[string]string{"owner": "data"}, props, "the input must not be modified")
+}
+
+func TestSyncRowLevelOperationMode(t *testing.T) {
+	server := map[string]string{
+		"write.delete.mode": "copy-on-write",
+		"write.update.mode": "copy-on-write",
+		"write.merge.mode":  "copy-on-write",
+	}
+	mixed := map[string]string{
+		"write.delete.mode": "copy-on-write",
+		"write.update.mode": "merge-on-read",
+		"write.merge.mode":  "copy-on-write",
+	}
+
+	assert.True(t, syncRowLevelOperationMode(types.StringNull(), server).IsNull(), "an unmanaged mode stays null")
+	assert.Equal(t, types.StringValue("copy-on-write"), syncRowLevelOperationMode(types.StringValue("merge-on-read"), server))
+	assert.True(t, syncRowLevelOperationMode(types.StringValue("copy-on-write"), mixed).IsNull(), "mixed modes are planned to be set again")
+	assert.True(t, syncRowLevelOperationMode(types.StringValue("copy-on-write"), map[string]string{}).IsNull())
+}
+
+func TestValidateRowLevelOperationModeConfig(t *testing.T) {
+	withKey := types.MapValueMust(types.StringType, map[string]attr.Value{"write.merge.mode": types.StringValue("copy-on-write")})
+	withoutKey := types.MapValueMust(types.StringType, map[string]attr.Value{"write.format.default": types.StringValue("parquet")})
+
+	var diags diag.Diagnostics
+	validateRowLevelOperationModeConfig(types.StringNull(), withKey, &diags)
+	validateRowLevelOperationModeConfig(types.StringValue("merge-on-read"), withoutKey, &diags)
+	validateRowLevelOperationModeConfig(types.StringValue("merge-on-read"), types.MapUnknown(types.StringType), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	validateRowLevelOperationModeConfig(types.StringValue("merge-on-read"), withKey, &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Conflicting row_level_operation_mode configuration", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), "write.merge.mode")
+}
+
+func TestAccIcebergTable_RowLevelOperationMode(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := func(attributes string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "orders" {
+  namespace = ["db"]
+  name      = "orders"
+  %s
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL, attributes)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`row_level_operation_mode = "merge_on_read"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`value must be one of`),
+			},
+			{
+				Config: config(`row_level_operation_mode = "merge-on-read"
+  user_properties = {
+    "write.update.mode" = "copy-on-write"
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Conflicting row_level_operation_mode configuration`),
+			},
+			{
+				Config: config(`row_level_operation_mode = "merge-on-read"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.orders", "row_level_operation_mode", "merge-on-read"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.write.delete.mode", "merge-on-read"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.write.update.mode", "merge-on-read"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.write.merge.mode", "merge-on-read"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "server_managed_properties.write.merge.mode"),
+				),
+			},
+			{
+				Config: config(`row_level_operation_mode = "copy-on-write"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.write.delete.mode", "copy-on-write"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.write.merge.mode", "copy-on-write"),
+				),
+			},
+			{
+				Config: config(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "row_level_operation_mode"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "server_properties.write.update.mode"),
+				),
+			},
+		},
+	})
+}
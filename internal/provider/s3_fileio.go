@@ -0,0 +1,156 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"maps"
+	"net/url"
+	"strconv"
+
+	"github.com/apache/iceberg-go"
+	iceio "github.com/apache/iceberg-go/io"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// s3Model is the s3 block, the S3 FileIO configuration the provider writes
+// and reads table metadata files with when the catalog doesn't do it
+// itself, with type = "glue", "sql" or "memory".
+type s3Model struct {
+	AccessKeyID     types.String `tfsdk:"access_key_id"`
+	SecretAccessKey types.String `tfsdk:"secret_access_key"`
+	SessionToken    types.String `tfsdk:"session_token"`
+	Region          types.String `tfsdk:"region"`
+	Endpoint        types.String `tfsdk:"endpoint"`
+	PathStyleAccess types.Bool   `tfsdk:"path_style_access"`
+}
+
+// s3Block returns the schema of the s3 block.
+func s3Block() schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "S3 settings for the table metadata files the provider writes and reads itself with type = 'glue', 'sql' or 'memory'. REST catalogs write them on the server, so the block isn't allowed with them. Without it, the standard AWS configuration is used. With type = 'glue', it applies to the files of new tables; those of existing tables are read with the Glue credentials and region.",
+		Attributes: map[string]schema.Attribute{
+			"access_key_id": schema.StringAttribute{
+				Description: "The AWS access key ID. Requires secret_access_key.",
+				Optional:    true,
+			},
+			"secret_access_key": schema.StringAttribute{
+				Description: "The AWS secret access key. Requires access_key_id.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"session_token": schema.StringAttribute{
+				Description: "The AWS session token of temporary credentials. Requires access_key_id and secret_access_key.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"region": schema.StringAttribute{
+				Description: "The region of the buckets.",
+				Optional:    true,
+			},
+			"endpoint": schema.StringAttribute{
+				Description: "The URL of an S3-compatible service such as MinIO, for example http://localhost:9000.",
+				Optional:    true,
+			},
+			"path_style_access": schema.BoolAttribute{
+				Description: "If true, buckets are addressed in the path of the URL rather than in the host name, as MinIO requires unless it is configured with a domain. Defaults to true.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// fileIOConfig returns the FileIO properties of the s3, adls and gcs blocks
+// of data, nil if none is set. A service principal of the adls block and a
+// key file of the gcs block are exported to the environment.
+func fileIOConfig(data icebergProviderModel, diags *diag.Diagnostics) iceberg.Properties {
+	props := s3Config(data, diags)
+	adls := adlsConfig(data, diags)
+	gcs := gcsConfig(data, diags)
+	if diags.HasError() {
+		return nil
+	}
+	if err := exportADLSServicePrincipal(adls); err != nil {
+		diags.AddAttributeError(path.Root("adls"), "Invalid adls configuration",
+			"Failed to export the service principal to the environment: "+err.Error()+".")
+
+		return nil
+	}
+	if err := exportGCSCredentials(gcs); err != nil {
+		diags.AddAttributeError(path.Root("gcs"), "Invalid gcs configuration",
+			"Failed to export the key file to the environment: "+err.Error()+".")
+
+		return nil
+	}
+	for _, block := range []iceberg.Properties{adls, gcs} {
+		if block == nil {
+			continue
+		}
+		if props == nil {
+			props = iceberg.Properties{}
+		}
+		maps.Copy(props, block)
+	}
+
+	return props
+}
+
+// s3Config returns the FileIO properties of the s3 block of data, nil if it
+// isn't set. Invalid settings are reported as errors.
+func s3Config(data icebergProviderModel, diags *diag.Diagnostics) iceberg.Properties {
+	if data.S3 == nil {
+		return nil
+	}
+	s3 := data.S3
+
+	if s3.AccessKeyID.IsNull() != s3.SecretAccessKey.IsNull() {
+		diags.AddAttributeError(path.Root("s3"), "Invalid s3 configuration",
+			"s3.access_key_id and s3.secret_access_key must be set together.")
+	}
+	if !s3.SessionToken.IsNull() && s3.AccessKeyID.IsNull() {
+		diags.AddAttributeError(path.Root("s3").AtName("session_token"), "Invalid s3 configuration",
+			"s3.session_token requires s3.access_key_id and s3.secret_access_key.")
+	}
+	if endpoint := s3.Endpoint.ValueString(); endpoint != "" {
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			diags.AddAttributeError(path.Root("s3").AtName("endpoint"), "Invalid s3 configuration",
+				"s3.endpoint must be an http:// or https:// URL, got "+strconv.Quote(endpoint)+".")
+		}
+	}
+	if diags.HasError() {
+		return nil
+	}
+
+	props := iceberg.Properties{}
+	for key, value := range map[string]types.String{
+		iceio.S3AccessKeyID:     s3.AccessKeyID,
+		iceio.S3SecretAccessKey: s3.SecretAccessKey,
+		iceio.S3SessionToken:    s3.SessionToken,
+		iceio.S3Region:          s3.Region,
+		iceio.S3EndpointURL:     s3.Endpoint,
+	} {
+		if value.ValueString() != "" {
+			props[key] = value.ValueString()
+		}
+	}
+	if !s3.PathStyleAccess.IsNull() && !s3.PathStyleAccess.IsUnknown() {
+		props[iceio.S3ForceVirtualAddressing] = strconv.FormatBool(!s3.PathStyleAccess.ValueBool())
+	}
+
+	return props
+}
@@ -0,0 +1,275 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// s3BlockValue returns an s3 block of the provider schema with attrs set.
+func s3BlockValue(attrs map[string]tftypes.Value) tftypes.Value {
+	attrTypes := map[string]tftypes.Type{
+		"access_key_id":     tftypes.String,
+		"secret_access_key": tftypes.String,
+		"session_token":     tftypes.String,
+		"region":            tftypes.String,
+		"endpoint":          tftypes.String,
+		"path_style_access": tftypes.Bool,
+	}
+	values := make(map[string]tftypes.Value, len(attrTypes))
+	for name, typ := range attrTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	maps.Copy(values, attrs)
+
+	return tftypes.NewValue(tftypes.Object{AttributeTypes: attrTypes}, values)
+}
+
+func TestProviderS3Config(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		s3        map[string]tftypes.Value
+		want      iceberg.Properties
+		wantError string
+	}{
+		{
+			name: "all settings",
+			s3: map[string]tftypes.Value{
+				"access_key_id":     tftypes.NewValue(tftypes.String, "admin"),
+				"secret_access_key": tftypes.NewValue(tftypes.String, "password"),
+				"session_token":     tftypes.NewValue(tftypes.String, "session"),
+				"region":            tftypes.NewValue(tftypes.String, "us-east-1"),
+				"endpoint":          tftypes.NewValue(tftypes.String, "http://localhost:9000"),
+				"path_style_access": tftypes.NewValue(tftypes.Bool, false),
+			},
+			want: iceberg.Properties{
+				"s3.access-key-id":            "admin",
+				"s3.secret-access-key":        "password",
+				"s3.session-token":            "session",
+				"s3.region":                   "us-east-1",
+				"s3.endpoint":                 "http://localhost:9000",
+				"s3.force-virtual-addressing": "true",
+			},
+		},
+		{
+			name: "path style access",
+			s3: map[string]tftypes.Value{
+				"path_style_access": tftypes.NewValue(tftypes.Bool, true),
+			},
+			want: iceberg.Properties{"s3.force-virtual-addressing": "false"},
+		},
+		{
+			name: "empty block",
+			s3:   map[string]tftypes.Value{},
+			want: iceberg.Properties{},
+		},
+		{
+			name: "access key without secret",
+			s3: map[string]tftypes.Value{
+				"access_key_id": tftypes.NewValue(tftypes.String, "admin"),
+			},
+			wantError: "s3.access_key_id and s3.secret_access_key must be set together.",
+		},
+		{
+			name: "session token without keys",
+			s3: map[string]tftypes.Value{
+				"session_token": tftypes.NewValue(tftypes.String, "session"),
+			},
+			wantError: "s3.session_token requires s3.access_key_id and s3.secret_access_key.",
+		},
+		{
+			name: "endpoint without scheme",
+			s3: map[string]tftypes.Value{
+				"endpoint": tftypes.NewValue(tftypes.String, "localhost:9000"),
+			},
+			wantError: `s3.endpoint must be an http:// or https:// URL, got "localhost:9000".`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, diags := configureTestProvider(t, map[string]tftypes.Value{
+				"type": tftypes.NewValue(tftypes.String, "memory"),
+				"s3":   s3BlockValue(tc.s3),
+			})
+			if tc.wantError != "" {
+				require.True(t, diags.HasError())
+				assert.Equal(t, "Invalid s3 configuration", diags.Errors()[0].Summary())
+				assert.Equal(t, tc.wantError, diags.Errors()[0].Detail())
+
+				return
+			}
+			require.False(t, diags.HasError(), "%v", diags)
+			assert.Equal(t, tc.want, p.fileIO)
+		})
+	}
+
+	t.Run("rest catalog", func(t *testing.T) {
+		var diags diag.Diagnostics
+		validateCatalogTypeConfig(icebergProviderModel{
+			Type:       types.StringValue("rest"),
+			CatalogURI: types.StringValue("http://localhost:8181"),
+			S3:         &s3Model{},
+		}, &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Equal(t, "Invalid s3 configuration", diags.Errors()[0].Summary())
+	})
+}
+
+// fakeS3 is an S3 endpoint storing objects in memory, addressed in the path
+// of the URL. It records the access key of every request.
+type fakeS3 struct {
+	mu         sync.Mutex
+	objects    map[string][]byte
+	accessKeys []string
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	auth := r.Header.Get("Authorization")
+	if _, credential, ok := strings.Cut(auth, "Credential="); ok {
+		key, _, _ := strings.Cut(credential, "/")
+		s.accessKeys = append(s.accessKeys, key)
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+		s.objects[r.URL.Path] = body
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		body, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(body)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestProviderS3SQLCatalog(t *testing.T) {
+	ctx := context.Background()
+	s3 := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(s3)
+	t.Cleanup(server.Close)
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"type":      tftypes.NewValue(tftypes.String, "sql"),
+		"sql_dsn":   tftypes.NewValue(tftypes.String, "sqlite:"+t.TempDir()+"/catalog.db"),
+		"warehouse": tftypes.NewValue(tftypes.String, "s3://warehouse"),
+		"s3": s3BlockValue(map[string]tftypes.Value{
+			"access_key_id":     tftypes.NewValue(tftypes.String, "admin"),
+			"secret_access_key": tftypes.NewValue(tftypes.String, "password"),
+			"region":            tftypes.NewValue(tftypes.String, "us-east-1"),
+			"endpoint":          tftypes.NewValue(tftypes.String, server.URL),
+			"path_style_access": tftypes.NewValue(tftypes.Bool, true),
+		}),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+	require.NoError(t, cat.CreateNamespace(ctx, catalog.ToIdentifier("db"), nil))
+
+	ident := catalog.ToIdentifier("db", "events")
+	schema := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	_, err = cat.CreateTable(ctx, ident, schema)
+	require.NoError(t, err)
+	tbl, err := cat.LoadTable(ctx, ident)
+	require.NoError(t, err)
+	assert.Equal(t, 1, tbl.Schema().NumFields())
+
+	s3.mu.Lock()
+	defer s3.mu.Unlock()
+	require.Len(t, s3.objects, 1)
+	for key := range s3.objects {
+		assert.True(t, strings.HasPrefix(key, "/warehouse/db.db/events/metadata/"), key)
+	}
+	assert.NotEmpty(t, s3.accessKeys)
+	for _, key := range s3.accessKeys {
+		assert.Equal(t, "admin", key)
+	}
+}
+
+// TestAccIcebergTable_S3 manages a table of a SQL catalog whose metadata
+// files are written to the MinIO of the Docker services. It only runs with
+// ICEBERG_S3_ENDPOINT set, as make test-integration-exec does.
+func TestAccIcebergTable_S3(t *testing.T) {
+	endpoint := os.Getenv("ICEBERG_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("ICEBERG_S3_ENDPOINT isn't set")
+	}
+
+	providerCfg := fmt.Sprintf(`
+provider "iceberg" {
+  type      = "sql"
+  sql_dsn   = "sqlite::memory:"
+  warehouse = "s3://warehouse/tf_acc_s3"
+
+  s3 {
+    access_key_id     = "admin"
+    secret_access_key = "password"
+    region            = "us-east-1"
+    endpoint          = %q
+    path_style_access = true
+  }
+}
+`, endpoint)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIcebergTableResourceConfig(providerCfg, "db1", "s3_table"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.test", "name", "s3_table"),
+					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.#", "2"),
+				),
+			},
+		},
+	})
+}
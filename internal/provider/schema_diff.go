@@ -0,0 +1,135 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"github.com/apache/iceberg-go"
+)
+
+// schemaDiff describes how a schema differs from an earlier version of it.
+// Fields are matched like the table resource matches them: by ID, or by full
+// name if either field has no ID. A field with a new name but the same ID is
+// renamed, not removed and added. The children of added and removed structs
+// aren't listed separately.
+type schemaDiff struct {
+	added   []schemaDiffField
+	removed []schemaDiffField
+	renamed []schemaDiffRename
+	retyped []schemaDiffRetype
+}
+
+type schemaDiffField struct {
+	id       int
+	name     string
+	typ      string
+	required bool
+}
+
+type schemaDiffRename struct {
+	id      int
+	oldName string
+	newName string
+}
+
+type schemaDiffRetype struct {
+	id      int
+	name    string
+	oldType string
+	newType string
+}
+
+// diffSchemas compares the fields of next with those of prior, descending
+// into structs. Lists and maps are compared as a whole.
+func diffSchemas(prior, next *iceberg.Schema) schemaDiff {
+	var diff schemaDiff
+
+	priorByName := make(map[string]iceberg.NestedField)
+	priorNames := make(map[int]string)
+	walkStructFields("", prior.Fields(), func(name string, f iceberg.NestedField) bool {
+		priorByName[name] = f
+		if f.ID != 0 {
+			priorNames[f.ID] = name
+		}
+
+		return true
+	})
+
+	// matched holds the prior names of matched fields, with true for the
+	// structs whose fields were compared.
+	matched := make(map[string]bool)
+	match := func(name string, f iceberg.NestedField) (iceberg.NestedField, string, bool) {
+		if priorName, ok := priorNames[f.ID]; ok && f.ID != 0 {
+			return priorByName[priorName], priorName, true
+		}
+		if p, ok := priorByName[name]; ok && (p.ID == 0 || f.ID == 0) {
+			return p, name, true
+		}
+
+		return iceberg.NestedField{}, "", false
+	}
+
+	walkStructFields("", next.Fields(), func(name string, f iceberg.NestedField) bool {
+		p, priorName, ok := match(name, f)
+		if !ok {
+			diff.added = append(diff.added, schemaDiffField{id: f.ID, name: name, typ: f.Type.String(), required: f.Required})
+
+			return false
+		}
+		matched[priorName] = false
+
+		if p.Name != f.Name {
+			diff.renamed = append(diff.renamed, schemaDiffRename{id: f.ID, oldName: priorName, newName: name})
+		}
+
+		_, priorStruct := p.Type.(*iceberg.StructType)
+		_, nextStruct := f.Type.(*iceberg.StructType)
+		if priorStruct && nextStruct {
+			matched[priorName] = true
+
+			return true
+		}
+		if !p.Type.Equals(f.Type) {
+			diff.retyped = append(diff.retyped, schemaDiffRetype{id: f.ID, name: name, oldType: p.Type.String(), newType: f.Type.String()})
+		}
+
+		return false
+	})
+
+	walkStructFields("", prior.Fields(), func(name string, f iceberg.NestedField) bool {
+		descend, ok := matched[name]
+		if !ok {
+			diff.removed = append(diff.removed, schemaDiffField{id: f.ID, name: name, typ: f.Type.String(), required: f.Required})
+		}
+
+		return descend
+	})
+
+	return diff
+}
+
+// walkStructFields calls visit with the full name of every field, and of the
+// fields of struct fields for which visit returns true.
+func walkStructFields(prefix string, fields []iceberg.NestedField, visit func(name string, f iceberg.NestedField) bool) {
+	for _, f := range fields {
+		name := prefix + f.Name
+		if !visit(name, f) {
+			continue
+		}
+		if nested, ok := f.Type.(*iceberg.StructType); ok {
+			walkStructFields(name+".", nested.FieldList, visit)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	address := func(fields ...iceberg.NestedField) iceberg.NestedField {
+		return iceberg.NestedField{ID: 3, Name: "address", Type: &iceberg.StructType{FieldList: fields}}
+	}
+	prior := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int32, Required: true},
+		iceberg.NestedField{ID: 2, Name: "email", Type: iceberg.PrimitiveTypes.String},
+		address(
+			iceberg.NestedField{ID: 4, Name: "city", Type: iceberg.PrimitiveTypes.String},
+			iceberg.NestedField{ID: 5, Name: "zip", Type: iceberg.PrimitiveTypes.String},
+		),
+		iceberg.NestedField{ID: 6, Name: "legacy", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 7, Name: "flag", Type: iceberg.PrimitiveTypes.Bool},
+		}}},
+	)
+
+	for _, tc := range []struct {
+		name string
+		next *iceberg.Schema
+		want schemaDiff
+	}{
+		{
+			name: "unchanged",
+			next: prior,
+		},
+		{
+			name: "evolved",
+			next: iceberg.NewSchema(1,
+				iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+				iceberg.NestedField{ID: 2, Name: "email_address", Type: iceberg.PrimitiveTypes.String},
+				address(
+					iceberg.NestedField{ID: 4, Name: "town", Type: iceberg.PrimitiveTypes.String},
+					iceberg.NestedField{ID: 8, Name: "country", Type: iceberg.PrimitiveTypes.String, Required: true},
+				),
+				iceberg.NestedField{ID: 9, Name: "tags", Type: &iceberg.ListType{ElementID: 10, Element: iceberg.PrimitiveTypes.String}},
+			),
+			want: schemaDiff{
+				added: []schemaDiffField{
+					{id: 8, name: "address.country", typ: "string", required: true},
+					{id: 9, name: "tags", typ: "list<string>"},
+				},
+				removed: []schemaDiffField{
+					{id: 5, name: "address.zip", typ: "string"},
+					{id: 6, name: "legacy", typ: "struct<7: flag: optional boolean>"},
+				},
+				renamed: []schemaDiffRename{
+					{id: 2, oldName: "email", newName: "email_address"},
+					{id: 4, oldName: "address.city", newName: "address.town"},
+				},
+				retyped: []schemaDiffRetype{
+					{id: 1, name: "id", oldType: "int", newType: "long"},
+				},
+			},
+		},
+		{
+			name: "dropped and re-added under the same name",
+			next: iceberg.NewSchema(1,
+				iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int32, Required: true},
+				iceberg.NestedField{ID: 11, Name: "email", Type: iceberg.PrimitiveTypes.String},
+				address(
+					iceberg.NestedField{ID: 4, Name: "city", Type: iceberg.PrimitiveTypes.String},
+					iceberg.NestedField{ID: 5, Name: "zip", Type: iceberg.PrimitiveTypes.String},
+				),
+				iceberg.NestedField{ID: 6, Name: "legacy", Type: iceberg.PrimitiveTypes.String},
+			),
+			want: schemaDiff{
+				added:   []schemaDiffField{{id: 11, name: "email", typ: "string"}},
+				removed: []schemaDiffField{{id: 2, name: "email", typ: "string"}},
+				retyped: []schemaDiffRetype{{id: 6, name: "legacy", oldType: "struct<7: flag: optional boolean>", newType: "string"}},
+			},
+		},
+		{
+			name: "fields without IDs",
+			next: iceberg.NewSchema(1,
+				iceberg.NestedField{Name: "id", Type: iceberg.PrimitiveTypes.Int32, Required: true},
+				iceberg.NestedField{Name: "phone", Type: iceberg.PrimitiveTypes.String},
+			),
+			want: schemaDiff{
+				added: []schemaDiffField{{name: "phone", typ: "string"}},
+				removed: []schemaDiffField{
+					{id: 2, name: "email", typ: "string"},
+					{id: 3, name: "address", typ: "struct<4: city: optional string, 5: zip: optional string>"},
+					{id: 6, name: "legacy", typ: "struct<7: flag: optional boolean>"},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, diffSchemas(prior, tc.next))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/apache/iceberg-go"
+)
+
+// schemaFieldIDKeys are the keys of the Iceberg schema JSON that hold field
+// IDs.
+var schemaFieldIDKeys = []string{"id", "element-id", "key-id", "value-id"}
+
+// schemaFingerprint returns the SHA-256 of the canonical JSON of schema as a
+// hex string. The canonical JSON is the schema as iceberg-go writes it, which
+// normalizes types such as decimal(10,2), with sorted keys and without the
+// schema ID, which only numbers the schemas of a table. Identifier fields are
+// listed by name, sorted, and with includeIDs false the field IDs are left out,
+// so schemas that only number their fields differently have the same
+// fingerprint. The order of the fields is kept, as it is part of the schema.
+func schemaFingerprint(schema *iceberg.Schema, includeIDs bool) (string, error) {
+	identifierFields := make([]string, 0, len(schema.IdentifierFieldIDs))
+	for _, id := range schema.IdentifierFieldIDs {
+		name, ok := schema.FindColumnName(id)
+		if !ok {
+			return "", fmt.Errorf("identifier field ID %d isn't a field of the schema", id)
+		}
+		identifierFields = append(identifierFields, name)
+	}
+	sort.Strings(identifierFields)
+
+	written, err := json.Marshal(schema)
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(bytes.NewReader(written))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return "", err
+	}
+	delete(doc, "schema-id")
+	delete(doc, "identifier-field-ids")
+	doc["identifier-fields"] = identifierFields
+	if !includeIDs {
+		dropSchemaFieldIDs(doc)
+	}
+
+	// Maps are written with sorted keys.
+	canonical, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// dropSchemaFieldIDs removes the field IDs from the schema JSON v, in place.
+func dropSchemaFieldIDs(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, key := range schemaFieldIDKeys {
+			delete(v, key)
+		}
+		for _, child := range v {
+			dropSchemaFieldIDs(child)
+		}
+	case []any:
+		for _, child := range v {
+			dropSchemaFieldIDs(child)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+)
+
+// schemaMismatches compares the schema the catalog returned for a new table
+// with the schema sent to create it and describes every difference. Field IDs
+// are assigned by the catalog and aren't compared; everything else must
+// match, so a response that lost fields, for example to a proxy, isn't taken
+// for the table.
+func schemaMismatches(sent, got *iceberg.Schema) []string {
+	return fieldMismatches("", sent.Fields(), got.Fields())
+}
+
+func fieldMismatches(prefix string, sent, got []iceberg.NestedField) []string {
+	if len(sent) != len(got) {
+		return []string{fmt.Sprintf("%s has %d fields, the catalog returned %d", structName(prefix), len(sent), len(got))}
+	}
+
+	var mismatches []string
+	for i, f := range sent {
+		name := prefix + f.Name
+		switch {
+		case got[i].Name != f.Name:
+			mismatches = append(mismatches, fmt.Sprintf("field %d of %s is %s, the catalog returned %s", i+1, structName(prefix), name, prefix+got[i].Name))
+
+			continue
+		case got[i].Required != f.Required:
+			mismatches = append(mismatches, fmt.Sprintf("%s is required=%t, the catalog returned required=%t", name, f.Required, got[i].Required))
+		}
+		mismatches = append(mismatches, typeMismatches(name, f.Type, got[i].Type)...)
+	}
+
+	return mismatches
+}
+
+func typeMismatches(name string, sent, got iceberg.Type) []string {
+	if got == nil {
+		return []string{fmt.Sprintf("%s has type %s, the catalog returned no type", name, sent)}
+	}
+
+	switch s := sent.(type) {
+	case *iceberg.StructType:
+		if g, ok := got.(*iceberg.StructType); ok {
+			return fieldMismatches(name+".", s.FieldList, g.FieldList)
+		}
+	case *iceberg.ListType:
+		if g, ok := got.(*iceberg.ListType); ok {
+			mismatches := typeMismatches(name+".element", s.Element, g.Element)
+			if s.ElementRequired != g.ElementRequired {
+				mismatches = append(mismatches, fmt.Sprintf("%s.element is required=%t, the catalog returned required=%t", name, s.ElementRequired, g.ElementRequired))
+			}
+
+			return mismatches
+		}
+	case *iceberg.MapType:
+		if g, ok := got.(*iceberg.MapType); ok {
+			mismatches := typeMismatches(name+".key", s.KeyType, g.KeyType)
+			mismatches = append(mismatches, typeMismatches(name+".value", s.ValueType, g.ValueType)...)
+			if s.ValueRequired != g.ValueRequired {
+				mismatches = append(mismatches, fmt.Sprintf("%s.value is required=%t, the catalog returned required=%t", name, s.ValueRequired, g.ValueRequired))
+			}
+
+			return mismatches
+		}
+	default:
+		if sent.Equals(got) {
+			return nil
+		}
+	}
+
+	return []string{fmt.Sprintf("%s has type %s, the catalog returned %s", name, sent.Type(), got.Type())}
+}
+
+func structName(prefix string) string {
+	if prefix == "" {
+		return "the schema"
+	}
+
+	return prefix[:len(prefix)-1]
+}
+
+// verifyCreatedSchema checks the schema of tbl, just created as ident, against
+// sent. A differing response is given a second chance by loading the table, in
+// case only the create response was garbled; the mismatches that remain are
+// returned along with the table to use.
+func (r *icebergTableResource) verifyCreatedSchema(ctx context.Context, ident table.Identifier, sent *iceberg.Schema, tbl *table.Table) (*table.Table, []string) {
+	mismatches := schemaMismatches(sent, tbl.Schema())
+	if len(mismatches) == 0 {
+		return tbl, nil
+	}
+
+	loaded, err := r.catalog.LoadTable(ctx, ident)
+	if err != nil {
+		return tbl, mismatches
+	}
+
+	return loaded, schemaMismatches(sent, loaded.Schema())
+}
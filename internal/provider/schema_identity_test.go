@@ -0,0 +1,154 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaMismatches(t *testing.T) {
+	sent := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "tags", Type: &iceberg.ListType{ElementID: 3, Element: iceberg.PrimitiveTypes.String, ElementRequired: true}},
+		iceberg.NestedField{ID: 4, Name: "address", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 5, Name: "city", Type: iceberg.PrimitiveTypes.String},
+			{ID: 6, Name: "scores", Type: &iceberg.MapType{KeyID: 7, KeyType: iceberg.PrimitiveTypes.String, ValueID: 8, ValueType: iceberg.DecimalTypeOf(10, 2)}},
+		}}},
+	)
+
+	renumbered := iceberg.NewSchema(3,
+		iceberg.NestedField{ID: 11, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 12, Name: "tags", Type: &iceberg.ListType{ElementID: 13, Element: iceberg.PrimitiveTypes.String, ElementRequired: true}},
+		iceberg.NestedField{ID: 14, Name: "address", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 15, Name: "city", Type: iceberg.PrimitiveTypes.String},
+			{ID: 16, Name: "scores", Type: &iceberg.MapType{KeyID: 17, KeyType: iceberg.PrimitiveTypes.String, ValueID: 18, ValueType: iceberg.DecimalTypeOf(10, 2)}},
+		}}},
+	)
+	assert.Empty(t, schemaMismatches(sent, renumbered), "field IDs aren't compared")
+
+	mangled := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int32, Required: false},
+		iceberg.NestedField{ID: 2, Name: "tags", Type: &iceberg.ListType{ElementID: 3, Element: iceberg.PrimitiveTypes.String}},
+		iceberg.NestedField{ID: 4, Name: "address", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 5, Name: "town", Type: iceberg.PrimitiveTypes.String},
+			{ID: 6, Name: "scores", Type: &iceberg.MapType{KeyID: 7, KeyType: iceberg.PrimitiveTypes.String, ValueID: 8, ValueType: iceberg.DecimalTypeOf(12, 2)}},
+		}}},
+	)
+	assert.Equal(t, []string{
+		"id is required=true, the catalog returned required=false",
+		"id has type long, the catalog returned int",
+		"tags.element is required=true, the catalog returned required=false",
+		"field 1 of address is address.city, the catalog returned address.town",
+		"address.scores.value has type decimal(10, 2), the catalog returned decimal(12, 2)",
+	}, schemaMismatches(sent, mangled))
+
+	truncated := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	assert.Equal(t, []string{"the schema has 3 fields, the catalog returned 1"}, schemaMismatches(sent, truncated))
+
+	listed := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true},
+		iceberg.NestedField{ID: 2, Name: "tags", Type: iceberg.PrimitiveTypes.String},
+		iceberg.NestedField{ID: 4, Name: "address", Type: &iceberg.StructType{}},
+	)
+	assert.Equal(t, []string{
+		"tags has type list, the catalog returned string",
+		"address has 2 fields, the catalog returned 0",
+	}, schemaMismatches(sent, listed))
+}
+
+func TestVerifyCreatedSchema(t *testing.T) {
+	for _, mangle := range []bool{false, true} {
+		t.Run(fmt.Sprintf("mangle=%t", mangle), func(t *testing.T) {
+			ctx := context.Background()
+			m := newMockRESTCatalog()
+			m.addTable("db", "other", nil)
+			m.mangleSchemas = mangle
+			server := m.start(t)
+
+			r := &icebergTableResource{provider: &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}}
+			var diags diag.Diagnostics
+			r.ConfigureCatalog(ctx, &diags)
+			require.False(t, diags.HasError(), "%v", diags)
+
+			ident := table.Identifier{"db", "events"}
+			tbl, err := r.catalog.CreateTable(ctx, ident, mockTableSchema())
+			require.NoError(t, err)
+
+			_, mismatches := r.verifyCreatedSchema(ctx, ident, mockTableSchema(), tbl)
+			if mangle {
+				assert.Equal(t, []string{"id has type long, the catalog returned string"}, mismatches)
+			} else {
+				assert.Empty(t, mismatches)
+			}
+		})
+	}
+}
+
+func TestAccIcebergTable_SchemaMismatch(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	m.mangleSchemas = true
+	server := m.start(t)
+
+	config := fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "events" {
+  namespace = ["db"]
+  name      = "events"
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(_ *terraform.State) error {
+			if m.tableProperties("db", "events") == nil {
+				return fmt.Errorf("db.events wasn't created")
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`Catalog returned a different schema`),
+			},
+		},
+	})
+}
@@ -0,0 +1,282 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/iceberg-go"
+)
+
+// schemaSourceName names the schema at index i of the arguments of
+// merge_schemas in errors.
+func schemaSourceName(i int) string {
+	if i == 0 {
+		return "base_json"
+	}
+
+	return fmt.Sprintf("fragment %d", i)
+}
+
+// parseSchemaFragment parses Iceberg schema JSON like parseSchemaJSON, but
+// allows field IDs to be left out, which leaves them 0. Fragments are often
+// written by hand, and mergeSchemas assigns their IDs anyway.
+func parseSchemaFragment(s string) (*iceberg.Schema, error) {
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	fillMissingFieldIDs(doc)
+	filled, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSchemaJSON(string(filled))
+}
+
+// fillMissingFieldIDs sets the field, list element and map key and value IDs
+// missing from the Iceberg type JSON typ to 0.
+func fillMissingFieldIDs(typ any) {
+	obj, ok := typ.(map[string]any)
+	if !ok {
+		return
+	}
+	setMissing := func(obj map[string]any, key string) {
+		if _, ok := obj[key]; !ok {
+			obj[key] = 0
+		}
+	}
+
+	switch obj["type"] {
+	case "struct":
+		fields, _ := obj["fields"].([]any)
+		for _, f := range fields {
+			if field, ok := f.(map[string]any); ok {
+				setMissing(field, "id")
+				fillMissingFieldIDs(field["type"])
+			}
+		}
+	case "list":
+		setMissing(obj, "element-id")
+		fillMissingFieldIDs(obj["element"])
+	case "map":
+		setMissing(obj, "key-id")
+		setMissing(obj, "value-id")
+		fillMissingFieldIDs(obj["key"])
+		fillMissingFieldIDs(obj["value"])
+	}
+}
+
+// mergeSchemas returns a schema with the fields of schemas, in order, and the
+// schema ID of the first one. Field names must be unique at every level.
+// Field IDs, including list element and map key and value IDs, are kept
+// unless they are missing or already used, by an earlier schema or earlier in
+// the same one; such IDs are reassigned counting up from the highest ID in
+// use, in the order the fields appear, or are an error with strictIDs. The
+// identifier fields of all schemas are kept.
+func mergeSchemas(schemas []*iceberg.Schema, strictIDs bool) (_ *iceberg.Schema, err error) {
+	// iceberg-go panics on some invalid types.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	owners := make(map[string]string)
+	for i, schema := range schemas {
+		if err := checkFieldNames(schema.Fields(), ""); err != nil {
+			return nil, fmt.Errorf("%s: %w", schemaSourceName(i), err)
+		}
+		for _, field := range schema.Fields() {
+			if owner, ok := owners[field.Name]; ok {
+				return nil, fmt.Errorf("%s: field %q is already in %s", schemaSourceName(i), field.Name, owner)
+			}
+			owners[field.Name] = schemaSourceName(i)
+		}
+	}
+
+	// used records the source and name of the fields by ID.
+	used := make(map[int]string)
+	maxID := 0
+	var fields []iceberg.NestedField
+	var identifierFields []string
+	for i, schema := range schemas {
+		source := schemaSourceName(i)
+		for _, id := range schema.IdentifierFieldIDs {
+			name, ok := schema.FindColumnName(id)
+			if !ok {
+				return nil, fmt.Errorf("%s: identifier field ID %d isn't a field of the schema", source, id)
+			}
+			identifierFields = append(identifierFields, name)
+		}
+
+		// The first pass finds the IDs to reassign, so that those kept are
+		// known before new ones are counted up.
+		var reassign []bool
+		if _, err := renumberFields(schema.Fields(), "", func(id int, name string) (int, error) {
+			owner, taken := used[id]
+			switch {
+			case strictIDs && id <= 0:
+				return 0, fmt.Errorf("%s: %s has no field ID", source, name)
+			case strictIDs && taken:
+				return 0, fmt.Errorf("%s: field ID %d of %s is already used by %s", source, id, name, owner)
+			}
+			reassign = append(reassign, id <= 0 || taken)
+			if id > 0 && !taken {
+				used[id] = source + " " + name
+				maxID = max(maxID, id)
+			}
+
+			return id, nil
+		}); err != nil {
+			return nil, err
+		}
+
+		next := 0
+		renumbered, err := renumberFields(schema.Fields(), "", func(id int, name string) (int, error) {
+			defer func() { next++ }()
+			if !reassign[next] {
+				return id, nil
+			}
+			maxID++
+			used[maxID] = source + " " + name
+
+			return maxID, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, renumbered...)
+	}
+
+	merged := iceberg.NewSchema(schemas[0].ID, fields...)
+	identifierIDs := make([]int, 0, len(identifierFields))
+	for _, name := range identifierFields {
+		field, ok := merged.FindFieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("identifier field %s isn't a field of the merged schema", name)
+		}
+		identifierIDs = append(identifierIDs, field.ID)
+	}
+
+	return iceberg.NewSchemaWithIdentifiers(schemas[0].ID, identifierIDs, fields...), nil
+}
+
+// checkFieldNames reports a name used twice by fields or the fields of their
+// nested structs. prefix is the full name of the struct of fields.
+func checkFieldNames(fields []iceberg.NestedField, prefix string) error {
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if seen[field.Name] {
+			return fmt.Errorf("field %q appears twice", prefix+field.Name)
+		}
+		seen[field.Name] = true
+		if err := checkTypeFieldNames(field.Type, prefix+field.Name+"."); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkTypeFieldNames(typ iceberg.Type, prefix string) error {
+	switch t := typ.(type) {
+	case *iceberg.StructType:
+		return checkFieldNames(t.FieldList, prefix)
+	case *iceberg.ListType:
+		return checkTypeFieldNames(t.Element, prefix+"element.")
+	case *iceberg.MapType:
+		if err := checkTypeFieldNames(t.KeyType, prefix+"key."); err != nil {
+			return err
+		}
+
+		return checkTypeFieldNames(t.ValueType, prefix+"value.")
+	}
+
+	return nil
+}
+
+// renumberFields returns a copy of fields with every field ID, including the
+// element IDs of lists and the key and value IDs of maps, replaced by the
+// result of assign for it and the full name of the field, such as
+// tags.element. assign is called in the order the IDs appear in the schema
+// JSON.
+func renumberFields(fields []iceberg.NestedField, prefix string, assign func(id int, name string) (int, error)) ([]iceberg.NestedField, error) {
+	renumbered := make([]iceberg.NestedField, 0, len(fields))
+	for _, field := range fields {
+		name := prefix + field.Name
+		id, err := assign(field.ID, name)
+		if err != nil {
+			return nil, err
+		}
+		field.ID = id
+		if field.Type, err = renumberType(field.Type, name, assign); err != nil {
+			return nil, err
+		}
+		renumbered = append(renumbered, field)
+	}
+
+	return renumbered, nil
+}
+
+func renumberType(typ iceberg.Type, name string, assign func(id int, name string) (int, error)) (iceberg.Type, error) {
+	switch t := typ.(type) {
+	case *iceberg.StructType:
+		fields, err := renumberFields(t.FieldList, name+".", assign)
+		if err != nil {
+			return nil, err
+		}
+
+		return &iceberg.StructType{FieldList: fields}, nil
+	case *iceberg.ListType:
+		elementID, err := assign(t.ElementID, name+".element")
+		if err != nil {
+			return nil, err
+		}
+		element, err := renumberType(t.Element, name+".element", assign)
+		if err != nil {
+			return nil, err
+		}
+
+		return &iceberg.ListType{ElementID: elementID, Element: element, ElementRequired: t.ElementRequired}, nil
+	case *iceberg.MapType:
+		keyID, err := assign(t.KeyID, name+".key")
+		if err != nil {
+			return nil, err
+		}
+		keyType, err := renumberType(t.KeyType, name+".key", assign)
+		if err != nil {
+			return nil, err
+		}
+		valueID, err := assign(t.ValueID, name+".value")
+		if err != nil {
+			return nil, err
+		}
+		valueType, err := renumberType(t.ValueType, name+".value", assign)
+		if err != nil {
+			return nil, err
+		}
+
+		return &iceberg.MapType{KeyID: keyID, KeyType: keyType, ValueID: valueID, ValueType: valueType, ValueRequired: t.ValueRequired}, nil
+	}
+
+	return typ, nil
+}
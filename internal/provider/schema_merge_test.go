@@ -0,0 +1,266 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mergeSchemasTestBase = `{"type": "struct", "schema-id": 3, "identifier-field-ids": [1], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false}
+]}`
+
+func runMergeSchemas(t *testing.T, strictIDs bool, schemaJSON ...string) (string, error) {
+	t.Helper()
+
+	schemas := make([]*iceberg.Schema, 0, len(schemaJSON))
+	for _, s := range schemaJSON {
+		schema, err := parseSchemaFragment(s)
+		require.NoError(t, err, s)
+		schemas = append(schemas, schema)
+	}
+	merged, err := mergeSchemas(schemas, strictIDs)
+	if err != nil {
+		return "", err
+	}
+	written, err := json.Marshal(merged)
+	require.NoError(t, err)
+
+	return string(written), nil
+}
+
+func TestMergeSchemas(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		fragments []string
+		want      string
+	}{
+		{
+			name:      "no fragments",
+			fragments: nil,
+			want:      mergeSchemasTestBase,
+		},
+		{
+			name: "free IDs kept",
+			fragments: []string{`{"type": "struct", "fields": [
+  {"id": 10, "name": "created_at", "type": "timestamptz", "required": false, "doc": "Audit"}
+]}`},
+			want: `{"type": "struct", "schema-id": 3, "identifier-field-ids": [1], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false},
+  {"id": 10, "name": "created_at", "type": "timestamptz", "required": false, "doc": "Audit"}
+]}`,
+		},
+		{
+			name: "colliding IDs reassigned after the highest ID",
+			fragments: []string{`{"type": "struct", "fields": [
+  {"id": 1, "name": "created_at", "type": "timestamptz", "required": false},
+  {"id": 7, "name": "created_by", "type": "string", "required": false},
+  {"id": 4, "name": "deleted", "type": "boolean", "required": true}
+]}`},
+			want: `{"type": "struct", "schema-id": 3, "identifier-field-ids": [1], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false},
+  {"id": 8, "name": "created_at", "type": "timestamptz", "required": false},
+  {"id": 7, "name": "created_by", "type": "string", "required": false},
+  {"id": 9, "name": "deleted", "type": "boolean", "required": true}
+]}`,
+		},
+		{
+			name: "nested IDs reassigned in order",
+			fragments: []string{`{"type": "struct", "fields": [
+  {"id": 1, "name": "audit", "type": {"type": "struct", "fields": [
+    {"id": 2, "name": "by", "type": "string", "required": false},
+    {"id": 3, "name": "history", "type": {"type": "list", "element-id": 4, "element": {"type": "struct", "fields": [
+      {"id": 5, "name": "at", "type": "timestamptz", "required": true}
+    ]}, "element-required": true}, "required": false}
+  ]}, "required": false},
+  {"id": 6, "name": "labels", "type": {"type": "map", "key-id": 1, "key": "string", "value-id": 2, "value": {"type": "list", "element-id": 3, "element": "string", "element-required": false}, "value-required": false}, "required": false}
+]}`},
+			want: `{"type": "struct", "schema-id": 3, "identifier-field-ids": [1], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false},
+  {"id": 7, "name": "audit", "type": {"type": "struct", "fields": [
+    {"id": 8, "name": "by", "type": "string", "required": false},
+    {"id": 9, "name": "history", "type": {"type": "list", "element-id": 10, "element": {"type": "struct", "fields": [
+      {"id": 5, "name": "at", "type": "timestamptz", "required": true}
+    ]}, "element-required": true}, "required": false}
+  ]}, "required": false},
+  {"id": 6, "name": "labels", "type": {"type": "map", "key-id": 11, "key": "string", "value-id": 12, "value": {"type": "list", "element-id": 13, "element": "string", "element-required": false}, "value-required": false}, "required": false}
+]}`,
+		},
+		{
+			name: "IDs left out",
+			fragments: []string{`{"type": "struct", "fields": [
+  {"name": "deleted", "type": "boolean", "required": true},
+  {"name": "reasons", "type": {"type": "map", "key": "string", "value": "string", "value-required": false}, "required": false}
+]}`},
+			want: `{"type": "struct", "schema-id": 3, "identifier-field-ids": [1], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false},
+  {"id": 5, "name": "deleted", "type": "boolean", "required": true},
+  {"id": 6, "name": "reasons", "type": {"type": "map", "key-id": 7, "key": "string", "value-id": 8, "value": "string", "value-required": false}, "required": false}
+]}`,
+		},
+		{
+			name: "ID used twice in a fragment",
+			fragments: []string{`{"type": "struct", "fields": [
+  {"id": 20, "name": "created_at", "type": "timestamptz", "required": false},
+  {"id": 20, "name": "updated_at", "type": "timestamptz", "required": false}
+]}`},
+			want: `{"type": "struct", "schema-id": 3, "identifier-field-ids": [1], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false},
+  {"id": 20, "name": "created_at", "type": "timestamptz", "required": false},
+  {"id": 21, "name": "updated_at", "type": "timestamptz", "required": false}
+]}`,
+		},
+		{
+			name: "later fragment collides with a reassigned ID",
+			fragments: []string{
+				`{"type": "struct", "fields": [{"id": 1, "name": "created_at", "type": "timestamptz", "required": false}]}`,
+				`{"type": "struct", "fields": [{"id": 5, "name": "deleted", "type": "boolean", "required": true}]}`,
+			},
+			want: `{"type": "struct", "schema-id": 3, "identifier-field-ids": [1], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false},
+  {"id": 5, "name": "created_at", "type": "timestamptz", "required": false},
+  {"id": 6, "name": "deleted", "type": "boolean", "required": true}
+]}`,
+		},
+		{
+			name: "identifier fields of fragments follow their fields",
+			fragments: []string{`{"type": "struct", "identifier-field-ids": [2], "fields": [
+  {"id": 1, "name": "tenant", "type": "string", "required": false},
+  {"id": 2, "name": "region", "type": "string", "required": true}
+]}`},
+			want: `{"type": "struct", "schema-id": 3, "identifier-field-ids": [1, 6], "fields": [
+  {"id": 1, "name": "id", "type": "long", "required": true},
+  {"id": 2, "name": "amount", "type": "decimal(10, 2)", "required": false},
+  {"id": 3, "name": "tags", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false},
+  {"id": 5, "name": "tenant", "type": "string", "required": false},
+  {"id": 6, "name": "region", "type": "string", "required": true}
+]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := runMergeSchemas(t, false, append([]string{mergeSchemasTestBase}, tc.fragments...)...)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.want, got)
+
+			again, err := runMergeSchemas(t, false, append([]string{mergeSchemasTestBase}, tc.fragments...)...)
+			require.NoError(t, err)
+			assert.Equal(t, got, again, "deterministic")
+		})
+	}
+}
+
+func TestMergeSchemasStrictIDs(t *testing.T) {
+	free := `{"type": "struct", "fields": [{"id": 10, "name": "created_at", "type": "timestamptz", "required": false}]}`
+	_, err := runMergeSchemas(t, true, mergeSchemasTestBase, free)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		fragment string
+		wantErr  string
+	}{
+		{
+			name:     "top-level ID",
+			fragment: `{"type": "struct", "fields": [{"id": 2, "name": "created_at", "type": "timestamptz", "required": false}]}`,
+			wantErr:  "fragment 1: field ID 2 of created_at is already used by base_json amount",
+		},
+		{
+			name:     "list element ID",
+			fragment: `{"type": "struct", "fields": [{"id": 10, "name": "labels", "type": {"type": "list", "element-id": 4, "element": "string", "element-required": false}, "required": false}]}`,
+			wantErr:  "fragment 1: field ID 4 of labels.element is already used by base_json tags.element",
+		},
+		{
+			name:     "missing ID",
+			fragment: `{"type": "struct", "fields": [{"name": "created_at", "type": "timestamptz", "required": false}]}`,
+			wantErr:  "fragment 1: created_at has no field ID",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := runMergeSchemas(t, true, mergeSchemasTestBase, tc.fragment)
+			require.Error(t, err)
+			assert.Equal(t, tc.wantErr, err.Error())
+		})
+	}
+}
+
+func TestMergeSchemasNames(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		fragments []string
+		wantErr   string
+	}{
+		{
+			name:      "field of the base",
+			fragments: []string{`{"type": "struct", "fields": [{"id": 10, "name": "amount", "type": "long", "required": false}]}`},
+			wantErr:   `fragment 1: field "amount" is already in base_json`,
+		},
+		{
+			name: "field of an earlier fragment",
+			fragments: []string{
+				`{"type": "struct", "fields": [{"id": 10, "name": "created_at", "type": "timestamptz", "required": false}]}`,
+				`{"type": "struct", "fields": [{"id": 11, "name": "created_at", "type": "timestamptz", "required": false}]}`,
+			},
+			wantErr: `fragment 2: field "created_at" is already in fragment 1`,
+		},
+		{
+			name: "nested field twice",
+			fragments: []string{`{"type": "struct", "fields": [{"id": 10, "name": "audit", "type": {"type": "list", "element-id": 11, "element": {"type": "struct", "fields": [
+  {"id": 12, "name": "by", "type": "string", "required": false},
+  {"id": 13, "name": "by", "type": "string", "required": false}
+]}, "element-required": true}, "required": false}]}`},
+			wantErr: `fragment 1: field "audit.element.by" appears twice`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := runMergeSchemas(t, false, append([]string{mergeSchemasTestBase}, tc.fragments...)...)
+			require.Error(t, err)
+			assert.Equal(t, tc.wantErr, err.Error())
+		})
+	}
+
+	t.Run("same nested name in different structs", func(t *testing.T) {
+		_, err := runMergeSchemas(t, false, mergeSchemasTestBase, `{"type": "struct", "fields": [
+  {"id": 10, "name": "created", "type": {"type": "struct", "fields": [{"id": 11, "name": "by", "type": "string", "required": false}]}, "required": false},
+  {"id": 12, "name": "updated", "type": {"type": "struct", "fields": [{"id": 13, "name": "by", "type": "string", "required": false}]}, "required": false}
+]}`)
+		require.NoError(t, err)
+	})
+}
+
+func TestMergeSchemasUnknownIdentifierField(t *testing.T) {
+	_, err := runMergeSchemas(t, false, mergeSchemasTestBase, `{"type": "struct", "identifier-field-ids": [9], "fields": [{"id": 10, "name": "created_at", "type": "timestamptz", "required": true}]}`)
+	require.Error(t, err)
+	assert.Equal(t, "fragment 1: identifier field ID 9 isn't a field of the schema", err.Error())
+}
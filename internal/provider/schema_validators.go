@@ -0,0 +1,219 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// allowedMapKeyTypes are the map key types restrict_map_keys allows.
+var allowedMapKeyTypes = []string{"string", "int", "long", "date"}
+
+// timestampWithoutZoneTypes are the types forbid_timestamp_without_zone
+// rejects.
+var timestampWithoutZoneTypes = []string{"timestamp", "timestamp_ns"}
+
+// schemaPolicy is a check of the fields of table schemas that the provider
+// turns on. The policies of a provider share one pass over the schema tree.
+type schemaPolicy interface {
+	// checkField is called for every field of the schema, nested fields
+	// included, with the field's attributes and its path.
+	checkField(field schemaPolicyField, diags *diag.Diagnostics)
+	// finish is called once all fields were checked, for policies that
+	// report the fields together.
+	finish(fieldsPath path.Path, diags *diag.Diagnostics)
+}
+
+// schemaPolicyField is a field of a table schema in the configuration.
+type schemaPolicyField struct {
+	// name is the name of the field, prefixed by the names of the structs it
+	// is nested in, such as "details.created_at".
+	name  string
+	path  path.Path
+	attrs map[string]attr.Value
+}
+
+// schemaPoliciesValidator runs the schema policies the provider sets on the
+// table schema.
+type schemaPoliciesValidator struct {
+	provider *icebergProvider
+}
+
+var _ resource.ConfigValidator = schemaPoliciesValidator{}
+
+func (v schemaPoliciesValidator) Description(_ context.Context) string {
+	return "the table schema must follow the schema policies of the provider"
+}
+
+func (v schemaPoliciesValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v schemaPoliciesValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	// The provider isn't configured yet when Terraform validates the
+	// configuration on its own; the check then runs again at plan time.
+	if v.provider == nil {
+		return
+	}
+	policies := v.provider.schemaPolicies()
+	if len(policies) == 0 {
+		return
+	}
+
+	var schema types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("schema"), &schema)...)
+	if resp.Diagnostics.HasError() || schema.IsNull() || schema.IsUnknown() {
+		return
+	}
+
+	validateSchemaPolicies(schema.Attributes()["fields"], path.Root("schema").AtName("fields"), policies, &resp.Diagnostics)
+}
+
+// schemaPolicies returns the schema policies the provider configuration
+// turns on.
+func (p *icebergProvider) schemaPolicies() []schemaPolicy {
+	var policies []schemaPolicy
+	if p.restrictMapKeys {
+		policies = append(policies, mapKeyTypesPolicy{})
+	}
+	if p.forbidTimestampWithoutZone {
+		policies = append(policies, &timestampWithoutZonePolicy{})
+	}
+
+	return policies
+}
+
+// validateSchemaPolicies checks a list of schema fields and the fields of
+// nested structs against policies.
+func validateSchemaPolicies(fields attr.Value, fieldsPath path.Path, policies []schemaPolicy, diags *diag.Diagnostics) {
+	walkSchemaFields(fields, fieldsPath, "", func(field schemaPolicyField) {
+		for _, policy := range policies {
+			policy.checkField(field, diags)
+		}
+	})
+	for _, policy := range policies {
+		policy.finish(fieldsPath, diags)
+	}
+}
+
+// walkSchemaFields calls fn for every field of a list of schema fields and of
+// the fields of nested structs, parents first. Unknown values are skipped.
+func walkSchemaFields(fields attr.Value, fieldsPath path.Path, prefix string, fn func(schemaPolicyField)) {
+	list, ok := fields.(types.List)
+	if !ok || list.IsNull() || list.IsUnknown() {
+		return
+	}
+
+	for i, elem := range list.Elements() {
+		obj, ok := elem.(types.Object)
+		if !ok || obj.IsNull() || obj.IsUnknown() {
+			continue
+		}
+		field := schemaPolicyField{name: prefix, path: fieldsPath.AtListIndex(i), attrs: obj.Attributes()}
+		if name, ok := field.attrs["name"].(types.String); ok && !name.IsNull() && !name.IsUnknown() {
+			field.name += name.ValueString()
+		} else {
+			field.name += fmt.Sprintf("[%d]", i)
+		}
+		fn(field)
+
+		if structProps := knownObject(field.attrs["struct_properties"]); structProps != nil {
+			walkSchemaFields(structProps["fields"], field.path.AtName("struct_properties").AtName("fields"), field.name+".", fn)
+		}
+	}
+}
+
+// knownObject returns the attributes of value if it is a known object, nil
+// otherwise.
+func knownObject(value attr.Value) map[string]attr.Value {
+	obj, ok := value.(types.Object)
+	if !ok || obj.IsNull() || obj.IsUnknown() {
+		return nil
+	}
+
+	return obj.Attributes()
+}
+
+// knownString returns value and true if it is a known string.
+func knownString(value attr.Value) (string, bool) {
+	s, ok := value.(types.String)
+	if !ok || s.IsNull() || s.IsUnknown() {
+		return "", false
+	}
+
+	return s.ValueString(), true
+}
+
+// mapKeyTypesPolicy rejects map keys outside of allowedMapKeyTypes when the
+// provider sets restrict_map_keys.
+type mapKeyTypesPolicy struct{}
+
+func (mapKeyTypesPolicy) checkField(field schemaPolicyField, diags *diag.Diagnostics) {
+	keyType, ok := knownString(knownObject(field.attrs["map_properties"])["key_type"])
+	if !ok || slices.Contains(allowedMapKeyTypes, keyType) {
+		return
+	}
+
+	diags.AddAttributeError(
+		field.path.AtName("map_properties").AtName("key_type"),
+		"Unsupported map key type",
+		fmt.Sprintf("Map key type %q is not allowed because restrict_map_keys is set in the provider. Use one of: %s.", keyType, strings.Join(allowedMapKeyTypes, ", ")),
+	)
+}
+
+func (mapKeyTypesPolicy) finish(path.Path, *diag.Diagnostics) {}
+
+// timestampWithoutZonePolicy rejects timestamp and timestamp_ns fields, list
+// elements and map keys and values when the provider sets
+// forbid_timestamp_without_zone. The fields are reported in one error.
+type timestampWithoutZonePolicy struct {
+	offending []string
+}
+
+func (p *timestampWithoutZonePolicy) checkField(field schemaPolicyField, _ *diag.Diagnostics) {
+	check := func(name string, value attr.Value) {
+		if typ, ok := knownString(value); ok && slices.Contains(timestampWithoutZoneTypes, typ) {
+			p.offending = append(p.offending, fmt.Sprintf("%s (%s)", name, typ))
+		}
+	}
+
+	check(field.name, field.attrs["type"])
+	check(field.name+".element", knownObject(field.attrs["list_properties"])["element_type"])
+	mapProps := knownObject(field.attrs["map_properties"])
+	check(field.name+".key", mapProps["key_type"])
+	check(field.name+".value", mapProps["value_type"])
+}
+
+func (p *timestampWithoutZonePolicy) finish(fieldsPath path.Path, diags *diag.Diagnostics) {
+	if len(p.offending) == 0 {
+		return
+	}
+
+	diags.AddAttributeError(
+		fieldsPath,
+		"Timestamp without time zone",
+		fmt.Sprintf("The schema has timestamps without a time zone, which forbid_timestamp_without_zone in the provider doesn't allow: %s. Use timestamptz or timestamptz_ns instead.", strings.Join(p.offending, ", ")),
+	)
+}
@@ -0,0 +1,224 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mapField(id int64, name, keyType string) icebergTableSchemaField {
+	return icebergTableSchemaField{
+		ID:   types.Int64Value(id),
+		Name: name,
+		Type: "map",
+		MapProperties: &icebergTableSchemaFieldMapProperties{
+			KeyID:     types.Int64Value(id + 100),
+			KeyType:   keyType,
+			ValueID:   types.Int64Value(id + 200),
+			ValueType: "string",
+		},
+	}
+}
+
+func TestMapKeyTypesPolicy(t *testing.T) {
+	ctx := context.Background()
+	schema := icebergTableSchema{
+		ID: types.Int64Null(),
+		Fields: []icebergTableSchemaField{
+			mapField(1, "tags", "string"),
+			mapField(2, "scores", "double"),
+			{
+				ID:   types.Int64Value(3),
+				Name: "details",
+				Type: "struct",
+				StructProperties: &icebergTableSchemaFieldStructProperties{
+					Fields: []icebergTableSchemaField{
+						mapField(4, "by_day", "date"),
+						mapField(5, "by_hash", "binary"),
+					},
+				},
+			},
+		},
+	}
+	value, d := types.ObjectValueFrom(ctx, schema.AttrTypes(), schema)
+	require.False(t, d.HasError(), "%v", d)
+
+	var diags diag.Diagnostics
+	validateSchemaPolicies(value.Attributes()["fields"], path.Root("schema").AtName("fields"), []schemaPolicy{mapKeyTypesPolicy{}}, &diags)
+
+	require.Len(t, diags.Errors(), 2)
+	for i, want := range []path.Path{
+		path.Root("schema").AtName("fields").AtListIndex(1).AtName("map_properties").AtName("key_type"),
+		path.Root("schema").AtName("fields").AtListIndex(2).AtName("struct_properties").AtName("fields").AtListIndex(1).AtName("map_properties").AtName("key_type"),
+	} {
+		withPath, ok := diags.Errors()[i].(diag.DiagnosticWithPath)
+		require.True(t, ok)
+		assert.Equal(t, want, withPath.Path())
+		assert.Equal(t, "Unsupported map key type", diags.Errors()[i].Summary())
+	}
+}
+
+func TestSchemaPolicies(t *testing.T) {
+	ctx := context.Background()
+	elementType := func(typ string) *icebergTableSchemaFieldListProperties {
+		return &icebergTableSchemaFieldListProperties{ID: types.Int64Value(10), Type: typ}
+	}
+	schema := icebergTableSchema{
+		ID: types.Int64Null(),
+		Fields: []icebergTableSchemaField{
+			{ID: types.Int64Value(1), Name: "created_at", Type: "timestamp"},
+			{ID: types.Int64Value(2), Name: "updated_at", Type: "timestamptz"},
+			{ID: types.Int64Value(3), Name: "events", Type: "list", ListProperties: elementType("timestamp_ns")},
+			mapField(4, "by_time", "timestamp"),
+			{
+				ID:   types.Int64Value(5),
+				Name: "details",
+				Type: "struct",
+				StructProperties: &icebergTableSchemaFieldStructProperties{
+					Fields: []icebergTableSchemaField{
+						{ID: types.Int64Value(6), Name: "seen_at", Type: "timestamp_ns"},
+						{ID: types.Int64Value(7), Name: "seen_at_tz", Type: "timestamptz_ns"},
+					},
+				},
+			},
+		},
+	}
+	value, d := types.ObjectValueFrom(ctx, schema.AttrTypes(), schema)
+	require.False(t, d.HasError(), "%v", d)
+	fieldsPath := path.Root("schema").AtName("fields")
+
+	var diags diag.Diagnostics
+	validateSchemaPolicies(value.Attributes()["fields"], fieldsPath, nil, &diags)
+	assert.False(t, diags.HasError())
+
+	p := &icebergProvider{restrictMapKeys: true, forbidTimestampWithoutZone: true}
+	validateSchemaPolicies(value.Attributes()["fields"], fieldsPath, p.schemaPolicies(), &diags)
+
+	require.Len(t, diags.Errors(), 2)
+	assert.Equal(t, "Unsupported map key type", diags.Errors()[0].Summary())
+	assert.Equal(t, fieldsPath.AtListIndex(3).AtName("map_properties").AtName("key_type"), diags.Errors()[0].(diag.DiagnosticWithPath).Path())
+
+	assert.Equal(t, "Timestamp without time zone", diags.Errors()[1].Summary())
+	assert.Equal(t, fieldsPath, diags.Errors()[1].(diag.DiagnosticWithPath).Path())
+	assert.Contains(t, diags.Errors()[1].Detail(), ": created_at (timestamp), events.element (timestamp_ns), by_time.key (timestamp), details.seen_at (timestamp_ns). Use timestamptz")
+}
+
+func TestAccIcebergTable_ForbidTimestampWithoutZone(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	config := func(forbid bool) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri                   = "%s"
+  restrict_map_keys             = true
+  forbid_timestamp_without_zone = %t
+}
+
+resource "iceberg_table" "events" {
+  namespace = ["db"]
+  name      = "events"
+  schema = {
+    fields = [
+      { id = 1, name = "id", type = "long", required = true },
+      { id = 2, name = "created_at", type = "timestamp", required = false },
+      { id = 3, name = "updated_at", type = "timestamptz", required = false },
+    ]
+  }
+}
+`, server.URL, forbid)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(true),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Timestamp without time zone.*created_at \(timestamp\)`),
+			},
+			{
+				Config:             config(false),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccIcebergTable_RestrictMapKeys(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	config := func(restrict bool) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri       = "%s"
+  restrict_map_keys = %t
+}
+
+resource "iceberg_table" "scores" {
+  namespace = ["db"]
+  name      = "scores"
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "by_score"
+        type     = "map"
+        required = false
+        map_properties = {
+          key_id         = 2
+          key_type       = "double"
+          value_id       = 3
+          value_type     = "string"
+          value_required = false
+        }
+      }
+    ]
+  }
+}
+`, server.URL, restrict)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(true),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Map key type "double" is not allowed`),
+			},
+			{
+				Config:             config(false),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
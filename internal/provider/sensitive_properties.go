@@ -0,0 +1,114 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Terraform decides per attribute whether values are sensitive, so the table
+// properties whose keys are listed in sensitive_property_keys are kept apart
+// from the other properties: they are managed through
+// sensitive_user_properties and shown in sensitive_server_properties instead
+// of user_properties, server_properties and server_managed_properties.
+
+// sensitivePropertyKeys returns the keys listed in sensitive_property_keys.
+func sensitivePropertyKeys(ctx context.Context, keys types.Set, diags *diag.Diagnostics) map[string]bool {
+	result := make(map[string]bool)
+	if keys.IsNull() || keys.IsUnknown() {
+		return result
+	}
+
+	var list []string
+	diags.Append(keys.ElementsAs(ctx, &list, false)...)
+	for _, k := range list {
+		result[k] = true
+	}
+
+	return result
+}
+
+// splitSensitiveProperties splits props into the properties whose keys aren't
+// sensitive and those whose keys are.
+func splitSensitiveProperties(props map[string]string, sensitiveKeys map[string]bool) (plain, sensitive map[string]string) {
+	plain = make(map[string]string, len(props))
+	sensitive = make(map[string]string)
+	for k, v := range props {
+		if sensitiveKeys[k] {
+			sensitive[k] = v
+		} else {
+			plain[k] = v
+		}
+	}
+
+	return plain, sensitive
+}
+
+// withSensitiveProperties returns a copy of props with the sensitive user
+// properties added.
+func withSensitiveProperties(props, sensitive map[string]string) map[string]string {
+	if len(sensitive) == 0 {
+		return props
+	}
+
+	result := maps.Clone(props)
+	if result == nil {
+		result = make(map[string]string, len(sensitive))
+	}
+	maps.Copy(result, sensitive)
+
+	return result
+}
+
+// validateSensitivePropertiesConfig rejects sensitive keys set in
+// user_properties, whose values would be shown in plans, and keys of
+// sensitive_user_properties that aren't listed as sensitive.
+func validateSensitivePropertiesConfig(ctx context.Context, keys types.Set, userProperties, sensitiveUserProperties types.Map, diags *diag.Diagnostics) {
+	if !fullyKnown(ctx, keys) {
+		return
+	}
+	sensitiveKeys := sensitivePropertyKeys(ctx, keys, diags)
+
+	if !userProperties.IsNull() && !userProperties.IsUnknown() {
+		for k := range userProperties.Elements() {
+			if sensitiveKeys[k] {
+				diags.AddAttributeError(
+					path.Root("user_properties").AtMapKey(k),
+					"Sensitive property in user_properties",
+					fmt.Sprintf("The property %q is listed in sensitive_property_keys, so its value would be shown in plans. Set it in sensitive_user_properties instead.", k),
+				)
+			}
+		}
+	}
+
+	if !sensitiveUserProperties.IsNull() && !sensitiveUserProperties.IsUnknown() {
+		for k := range sensitiveUserProperties.Elements() {
+			if !sensitiveKeys[k] {
+				diags.AddAttributeError(
+					path.Root("sensitive_user_properties"),
+					"Property not listed as sensitive",
+					fmt.Sprintf("The property %q is set in sensitive_user_properties but isn't listed in sensitive_property_keys. Add it to sensitive_property_keys.", k),
+				)
+			}
+		}
+	}
+}
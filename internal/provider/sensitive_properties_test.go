@@ -0,0 +1,179 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitSensitiveProperties(t *testing.T) {
+	ctx := context.Background()
+	keys := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("encryption.key-id")})
+
+	var diags diag.Diagnostics
+	sensitiveKeys := sensitivePropertyKeys(ctx, keys, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	plain, sensitive := splitSensitiveProperties(map[string]string{
+		"encryption.key-id": "arn:aws:kms:key/1",
+		"owner":             "data",
+	}, sensitiveKeys)
+	assert.Equal(t, map[string]string{"owner": "data"}, plain)
+	assert.Equal(t, map[string]string{"encryption.key-id": "arn:aws:kms:key/1"}, sensitive)
+
+	assert.Empty(t, sensitivePropertyKeys(ctx, types.SetNull(types.StringType), &diags))
+}
+
+func TestWithSensitiveProperties(t *testing.T) {
+	props := map[string]string{"owner": "data"}
+
+	assert.Equal(t, props, withSensitiveProperties(props, nil))
+	assert.Equal(t, map[string]string{"owner": "data", "encryption.key-id": "k"}, withSensitiveProperties(props, map[string]string{"encryption.key-id": "k"}))
+	assert.Equal(t, map[string]string{"owner": "data"}, props, "the input must not be modified")
+}
+
+func TestValidateSensitivePropertiesConfig(t *testing.T) {
+	ctx := context.Background()
+	keys := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("encryption.key-id")})
+	plain := types.MapValueMust(types.StringType, map[string]attr.Value{"owner": types.StringValue("data")})
+	sensitive := types.MapValueMust(types.StringType, map[string]attr.Value{"encryption.key-id": types.StringValue("k")})
+
+	var diags diag.Diagnostics
+	validateSensitivePropertiesConfig(ctx, keys, plain, sensitive, &diags)
+	validateSensitivePropertiesConfig(ctx, types.SetUnknown(types.StringType), sensitive, plain, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	validateSensitivePropertiesConfig(ctx, keys, sensitive, types.MapNull(types.StringType), &diags)
+	validateSensitivePropertiesConfig(ctx, types.SetNull(types.StringType), plain, sensitive, &diags)
+	require.Len(t, diags.Errors(), 2)
+	assert.Equal(t, "Sensitive property in user_properties", diags.Errors()[0].Summary())
+	assert.Equal(t, "Property not listed as sensitive", diags.Errors()[1].Summary())
+	for _, d := range diags.Errors() {
+		assert.NotContains(t, d.Detail(), `"k"`, "errors must not show sensitive values")
+	}
+}
+
+func TestDriftReportSensitiveProperties(t *testing.T) {
+	report := newDriftReport("Table db.events")
+	report.compareSensitiveProperties(
+		map[string]string{"encryption.key-id": "old", "encryption.kms-type": "aws"},
+		map[string]string{"encryption.key-id": "new"},
+	)
+
+	assert.Equal(t, []string{
+		"sensitive property encryption.key-id changed",
+		"sensitive property encryption.kms-type removed",
+	}, report.changes)
+}
+
+func TestAccIcebergTable_SensitiveProperties(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := func(keyID string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "encrypted" {
+  namespace = ["db"]
+  name      = "encrypted"
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+
+  sensitive_property_keys = ["encryption.key-id"]
+  sensitive_user_properties = {
+    "encryption.key-id" = "%s"
+  }
+  user_properties = {
+    owner = "data"
+  }
+}
+`, server.URL, keyID)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config("key-1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.encrypted", "sensitive_user_properties.encryption.key-id", "key-1"),
+					resource.TestCheckResourceAttr("iceberg_table.encrypted", "sensitive_server_properties.encryption.key-id", "key-1"),
+					resource.TestCheckNoResourceAttr("iceberg_table.encrypted", "server_properties.encryption.key-id"),
+					resource.TestCheckNoResourceAttr("iceberg_table.encrypted", "server_managed_properties.encryption.key-id"),
+					resource.TestCheckResourceAttr("iceberg_table.encrypted", "server_properties.owner", "data"),
+				),
+			},
+			{
+				Config: config("key-2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_table.encrypted", "sensitive_server_properties.encryption.key-id", "key-2"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "encrypted" {
+  namespace = ["db"]
+  name      = "encrypted"
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+
+  sensitive_property_keys = ["encryption.key-id"]
+  user_properties = {
+    "encryption.key-id" = "key-2"
+  }
+}
+`, server.URL),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Sensitive property in user_properties`),
+			},
+		},
+	})
+}
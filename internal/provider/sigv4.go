@@ -0,0 +1,141 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// sigv4DefaultService is the signing name of the Glue Data Catalog REST
+// endpoint. S3 Tables uses s3tables.
+const sigv4DefaultService = "glue"
+
+// sigv4Config holds what signing catalog requests with AWS SigV4 needs.
+type sigv4Config struct {
+	credentials aws.CredentialsProvider
+	region      string
+	service     string
+}
+
+// loadSigV4Config returns the signing configuration for the sigv4_*
+// provider attributes of data, or nil if signing isn't enabled. Credentials
+// come from the standard AWS chain: environment, shared config and
+// credentials files, and container or instance roles. They are only
+// retrieved when the first request is signed.
+func loadSigV4Config(ctx context.Context, data icebergProviderModel, diags *diag.Diagnostics) *sigv4Config {
+	if !data.SigV4Enabled.ValueBool() {
+		return nil
+	}
+
+	var opts []func(*config.LoadOptions) error
+	if region := data.SigV4Region.ValueString(); region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		diags.AddError("Invalid SigV4 configuration", "Failed to load the AWS configuration: "+err.Error())
+
+		return nil
+	}
+	if cfg.Region == "" {
+		diags.AddError(
+			"Invalid SigV4 configuration",
+			"sigv4_enabled is true, but no region is set. Set sigv4_region or the AWS_REGION environment variable.",
+		)
+
+		return nil
+	}
+
+	service := data.SigV4Service.ValueString()
+	if service == "" {
+		service = sigv4DefaultService
+	}
+
+	return &sigv4Config{credentials: cfg.Credentials, region: cfg.Region, service: service}
+}
+
+// sigv4Transport signs requests with AWS SigV4. Requests that already carry
+// credentials, such as a bearer token, are sent as they are.
+type sigv4Transport struct {
+	next   http.RoundTripper
+	config *sigv4Config
+	signer *v4.Signer
+	// now returns the signing time, it is replaced in tests.
+	now func() time.Time
+}
+
+func newSigV4Transport(cfg *sigv4Config, next http.RoundTripper) *sigv4Transport {
+	return &sigv4Transport{next: next, config: cfg, signer: v4.NewSigner(), now: time.Now}
+}
+
+func (t *sigv4Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if hasAuthorization(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	// A RoundTripper mustn't modify the request it was given.
+	signed := req.Clone(req.Context())
+	signed.Header.Del("Authorization")
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body to sign: %w", err)
+		}
+		signed.Body = io.NopCloser(bytes.NewReader(body))
+		signed.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	hash := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(hash[:])
+	signed.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	creds, err := t.config.credentials.Retrieve(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("retrieve AWS credentials to sign the request: %w", err)
+	}
+	if err := t.signer.SignHTTP(req.Context(), creds, signed, payloadHash, t.config.service, t.config.region, t.now()); err != nil {
+		return nil, fmt.Errorf("sign request: %w", err)
+	}
+
+	return t.next.RoundTrip(signed)
+}
+
+// hasAuthorization reports whether req carries credentials. The catalog
+// client sends "Bearer" without a token when none is configured, which
+// doesn't count.
+func hasAuthorization(req *http.Request) bool {
+	auth := strings.TrimSpace(req.Header.Get("Authorization"))
+
+	return auth != "" && !strings.EqualFold(auth, "Bearer")
+}
@@ -0,0 +1,156 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTransport keeps the last request instead of sending it.
+type recordingTransport struct {
+	req  *http.Request
+	body string
+}
+
+func (r *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.req = req
+	r.body = ""
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		r.body = string(body)
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestSigV4Transport(t *testing.T) {
+	next := &recordingTransport{}
+	transport := newSigV4Transport(&sigv4Config{
+		credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+		}),
+		region:  "us-east-1",
+		service: "glue",
+	}, next)
+	transport.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	t.Run("signs requests without a token", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "https://glue.us-east-1.amazonaws.com/iceberg/v1/catalogs/123/namespaces", strings.NewReader(`{"namespace":["db"]}`))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer ")
+
+		_, err = transport.RoundTrip(req)
+		require.NoError(t, err)
+
+		auth := next.req.Header.Get("Authorization")
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20260102/us-east-1/glue/aws4_request, SignedHeaders="), auth)
+		assert.Contains(t, auth, "Signature=")
+		assert.Equal(t, "20260102T030405Z", next.req.Header.Get("X-Amz-Date"))
+		hash := sha256.Sum256([]byte(`{"namespace":["db"]}`))
+		assert.Equal(t, hex.EncodeToString(hash[:]), next.req.Header.Get("X-Amz-Content-Sha256"))
+		assert.Equal(t, `{"namespace":["db"]}`, next.body, "the body is sent after signing")
+		assert.Equal(t, "Bearer ", req.Header.Get("Authorization"), "the original request isn't changed")
+	})
+
+	t.Run("same request same signature", func(t *testing.T) {
+		sign := func() string {
+			req, err := http.NewRequest(http.MethodGet, "https://glue.us-east-1.amazonaws.com/iceberg/v1/config", nil)
+			require.NoError(t, err)
+			_, err = transport.RoundTrip(req)
+			require.NoError(t, err)
+
+			return next.req.Header.Get("Authorization")
+		}
+		assert.Equal(t, sign(), sign())
+	})
+
+	t.Run("requests with a token aren't signed", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "https://glue.us-east-1.amazonaws.com/iceberg/v1/config", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer tok")
+
+		_, err = transport.RoundTrip(req)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Bearer tok", next.req.Header.Get("Authorization"))
+		assert.Empty(t, next.req.Header.Get("X-Amz-Date"))
+	})
+}
+
+func TestProviderSigV4Config(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	t.Run("defaults", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"sigv4_enabled": tftypes.NewValue(tftypes.Bool, true),
+			"sigv4_region":  tftypes.NewValue(tftypes.String, "eu-west-1"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.NotNil(t, p.sigv4)
+		assert.Equal(t, "eu-west-1", p.sigv4.region)
+		assert.Equal(t, "glue", p.sigv4.service)
+
+		creds, err := p.sigv4.credentials.Retrieve(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "AKID", creds.AccessKeyID)
+	})
+
+	t.Run("region from the environment", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "us-west-2")
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"sigv4_enabled": tftypes.NewValue(tftypes.Bool, true),
+			"sigv4_service": tftypes.NewValue(tftypes.String, "s3tables"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.NotNil(t, p.sigv4)
+		assert.Equal(t, "us-west-2", p.sigv4.region)
+		assert.Equal(t, "s3tables", p.sigv4.service)
+	})
+
+	t.Run("missing region", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"sigv4_enabled": tftypes.NewValue(tftypes.Bool, true),
+		})
+		require.Len(t, diags.Errors(), 1)
+		assert.Contains(t, diags.Errors()[0].Detail(), "no region is set")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		p, diags := configureTestProvider(t, nil)
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.Nil(t, p.sigv4)
+	})
+}
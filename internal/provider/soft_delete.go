@@ -0,0 +1,77 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+)
+
+// isSoftDeletedConflict reports whether err, returned by creating ident, was
+// caused by a soft-deleted table of the same name. Lakekeeper keeps dropped
+// tables until they expire and rejects creates of their names as already
+// existing, although loading the name finds no table.
+func (r *icebergTableResource) isSoftDeletedConflict(ctx context.Context, ident table.Identifier, err error) bool {
+	if !isAlreadyExists(err) || r.provider.capabilities.serverImplementation() != "lakekeeper" {
+		return false
+	}
+	_, loadErr := r.catalog.LoadTable(ctx, ident)
+
+	return errors.Is(loadErr, catalog.ErrNoSuchTable)
+}
+
+// softDeletedConflictDetail explains the error of a create that failed
+// because of a soft-deleted table.
+func softDeletedConflictDetail(ident table.Identifier, err error) string {
+	return fmt.Sprintf("The catalog rejected the table %s as already existing, but it can't be loaded: %s\n\n"+
+		"Lakekeeper soft-deletes dropped tables and keeps their names until they expire, so a dropped table can't be created again right away. "+
+		"Set purge_soft_deleted = true to purge the soft-deleted table before creating this one, or undrop it with the Lakekeeper management API and import it.",
+		encodeIdentifier(ident), err)
+}
+
+// purgeSoftDeletedTable removes the soft-deleted tables named ident, so the
+// name can be created again. Lakekeeper can't purge a soft-deleted table
+// directly: it is undropped, then dropped with force, which skips the soft
+// delete.
+func (p *icebergProvider) purgeSoftDeletedTable(ctx context.Context, resourceType string, ident table.Identifier) error {
+	client, err := p.newLakekeeperManagementClient(resourceType)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := client.FindDeletedTables(ctx, ident)
+	if err != nil {
+		return fmt.Errorf("list soft-deleted tables: %w", err)
+	}
+	if len(deleted) == 0 {
+		return fmt.Errorf("no soft-deleted table %s found", encodeIdentifier(ident))
+	}
+
+	for _, t := range deleted {
+		if err := client.UndropTable(ctx, t.ID); err != nil {
+			return fmt.Errorf("undrop soft-deleted table %s: %w", t.ID, err)
+		}
+		if err := client.ForceDropTable(ctx, ident); err != nil {
+			return fmt.Errorf("purge soft-deleted table %s: %w", t.ID, err)
+		}
+	}
+
+	return nil
+}
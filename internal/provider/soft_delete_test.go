@@ -0,0 +1,264 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockWarehouseID = "8b0f7c9e-2d4a-4e1b-9c3f-5a6d7e8f9a0b"
+
+// mockLakekeeper serves a mockRESTCatalog the way Lakekeeper does: under
+// /catalog with the warehouse ID as prefix, next to a management API for the
+// tables it soft-deletes.
+type mockLakekeeper struct {
+	catalog *mockRESTCatalog
+
+	mu sync.Mutex
+	// deleted maps the IDs of soft-deleted tables to "<namespace>.<table>".
+	deleted map[string]string
+	// forced records the paths of tables dropped with force.
+	forced []string
+}
+
+func newMockLakekeeper() *mockLakekeeper {
+	return &mockLakekeeper{catalog: newMockRESTCatalog(), deleted: make(map[string]string)}
+}
+
+func (m *mockLakekeeper) isDeleted(ident string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, deleted := range m.deleted {
+		if deleted == ident {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *mockLakekeeper) start(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	catalogHandler := m.catalog.start(t).Config.Handler
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /catalog/v1/config", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Server", "Lakekeeper/0.9.1")
+		writeJSON(w, http.StatusOK, map[string]any{
+			"defaults":  map[string]string{},
+			"overrides": map[string]string{"prefix": mockWarehouseID},
+		})
+	})
+	mux.HandleFunc("/catalog/v1/{warehouse}/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/tables") {
+			body, _ := io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			var create struct {
+				Name string `json:"name"`
+			}
+			_ = json.Unmarshal(body, &create)
+			ns := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/catalog/v1/"+mockWarehouseID+"/namespaces/"), "/tables")
+			if m.isDeleted(ns + "." + create.Name) {
+				writeRESTError(w, http.StatusConflict, "AlreadyExistsException")
+
+				return
+			}
+		}
+		if r.Method == http.MethodDelete && r.URL.Query().Get("force") == "true" {
+			m.mu.Lock()
+			m.forced = append(m.forced, r.URL.Path)
+			m.mu.Unlock()
+		}
+
+		r.URL.Path = strings.Replace(r.URL.Path, "/catalog/v1/"+r.PathValue("warehouse"), "/v1", 1)
+		r.URL.RawPath = ""
+		catalogHandler.ServeHTTP(w, r)
+	})
+	mux.HandleFunc("GET /management/v1/warehouse/{warehouse}/deleted-tabulars", func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		// One tabular per page, so paging is exercised.
+		page, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+		ids := slices.Sorted(maps.Keys(m.deleted))
+		var tabulars []map[string]any
+		if page < len(ids) {
+			ns, name, _ := strings.Cut(m.deleted[ids[page]], ".")
+			tabulars = append(tabulars, map[string]any{"id": ids[page], "name": name, "namespace": []string{ns}, "typ": "table"})
+		}
+		resp := map[string]any{"tabulars": tabulars}
+		if page+1 < len(ids) {
+			resp["next-page-token"] = strconv.Itoa(page + 1)
+		}
+		writeJSON(w, http.StatusOK, resp)
+	})
+	mux.HandleFunc("POST /management/v1/warehouse/{warehouse}/deleted-tabulars/undrop", func(w http.ResponseWriter, r *http.Request) {
+		var body lakekeeperUndropRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.PathValue("warehouse") != mockWarehouseID {
+			writeRESTError(w, http.StatusBadRequest, "BadRequestException")
+
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for _, target := range body.Targets {
+			ident, ok := m.deleted[target.ID]
+			if !ok {
+				writeRESTError(w, http.StatusNotFound, "NoSuchTabularException")
+
+				return
+			}
+			delete(m.deleted, target.ID)
+			ns, name, _ := strings.Cut(ident, ".")
+			m.catalog.addTable(ns, name, nil)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestLakekeeperManagementURL(t *testing.T) {
+	for _, tc := range []struct {
+		catalogURI string
+		want       string
+	}{
+		{catalogURI: "http://localhost:8181/catalog", want: "http://localhost:8181/management/v1"},
+		{catalogURI: "https://lakekeeper.example.com/catalog/", want: "https://lakekeeper.example.com/management/v1"},
+		{catalogURI: "https://example.com/lakekeeper/catalog", want: "https://example.com/lakekeeper/management/v1"},
+		{catalogURI: "https://lakekeeper.example.com", want: "https://lakekeeper.example.com/management/v1"},
+	} {
+		t.Run(tc.catalogURI, func(t *testing.T) {
+			u, err := lakekeeperManagementURL(tc.catalogURI)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, u.String())
+		})
+	}
+}
+
+func TestPurgeSoftDeletedTable(t *testing.T) {
+	ctx := context.Background()
+	m := newMockLakekeeper()
+	m.catalog.namespaces["db"] = map[string]string{}
+	m.catalog.tables["db"] = map[string]map[string]string{}
+	m.deleted["0f1e2d3c-0000-4000-8000-000000000001"] = "db.other"
+	m.deleted["0f1e2d3c-0000-4000-8000-000000000002"] = "db.events"
+	server := m.start(t)
+
+	r := &icebergTableResource{provider: &icebergProvider{catalogURI: server.URL + "/catalog", catalogType: "rest", capabilities: newCatalogCapabilities()}}
+	var diags diag.Diagnostics
+	r.ConfigureCatalog(ctx, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	ident := catalog.ToIdentifier("db", "events")
+	args := &tableCreateArgs{ident: ident, schema: mockTableSchema(), properties: map[string]string{}}
+	_, err := r.createTable(ctx, ident, args)
+	require.Error(t, err)
+	require.True(t, r.isSoftDeletedConflict(ctx, ident, err))
+	assert.Contains(t, softDeletedConflictDetail(ident, err), "purge_soft_deleted")
+
+	require.NoError(t, r.provider.purgeSoftDeletedTable(ctx, "iceberg_table", ident))
+	assert.Equal(t, []string{"/catalog/v1/" + mockWarehouseID + "/namespaces/db/tables/events"}, m.forced)
+	assert.Equal(t, []string{"db.events"}, m.catalog.purged)
+	assert.False(t, m.isDeleted("db.events"))
+	assert.True(t, m.isDeleted("db.other"), "other soft-deleted tables are kept")
+
+	_, err = r.createTable(ctx, ident, args)
+	require.NoError(t, err)
+
+	t.Run("existing table", func(t *testing.T) {
+		_, err := r.createTable(ctx, ident, args)
+		require.Error(t, err)
+		assert.False(t, r.isSoftDeletedConflict(ctx, ident, err))
+	})
+
+	t.Run("nothing to purge", func(t *testing.T) {
+		err := r.provider.purgeSoftDeletedTable(ctx, "iceberg_table", catalog.ToIdentifier("db", "missing"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no soft-deleted table db.missing found")
+	})
+}
+
+func TestAccIcebergTable_SoftDeleted(t *testing.T) {
+	m := newMockLakekeeper()
+	m.catalog.namespaces["db"] = map[string]string{}
+	m.catalog.tables["db"] = map[string]map[string]string{}
+	m.deleted["0f1e2d3c-0000-4000-8000-000000000002"] = "db.events"
+	server := m.start(t)
+
+	config := func(purge bool) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s/catalog"
+}
+
+resource "iceberg_table" "events" {
+  namespace          = ["db"]
+  name               = "events"
+  purge_soft_deleted = %t
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL, purge)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(false),
+				ExpectError: regexp.MustCompile("Lakekeeper soft-deletes dropped tables"),
+			},
+			{
+				Config: config(true),
+				Check:  resource.TestCheckResourceAttr("iceberg_table.events", "id", "db.events"),
+			},
+		},
+	})
+}
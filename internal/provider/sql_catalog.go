@@ -0,0 +1,235 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	sqlcat "github.com/apache/iceberg-go/catalog/sql"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	// The database drivers for the schemes sql_dsn supports.
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// sqlCatalogName is the catalog name the SQL catalog stores namespaces and
+// tables under. Other clients sharing the database, such as PyIceberg, see
+// them in their catalog of the same name.
+const sqlCatalogName = "default"
+
+// sqlConfig holds what the SQL catalog client needs with type = "sql".
+type sqlConfig struct {
+	dialect sqlcat.SupportedDialect
+	// db is shared by the catalog clients of all provider instances with the
+	// same DSN, so an in-memory SQLite database lives as long as the
+	// provider process. The database of a memory catalog without warehouse
+	// belongs to the provider instance, see memory_catalog.go.
+	db *sql.DB
+	// endpoint is sql_dsn without its password.
+	endpoint string
+	// warehouse is the warehouse used if the warehouse attribute isn't set.
+	warehouse string
+}
+
+// sqlDSN is sql_dsn parsed into what database/sql needs.
+type sqlDSN struct {
+	driver    string
+	dialect   sqlcat.SupportedDialect
+	driverDSN string
+	// memory is set for in-memory SQLite databases.
+	memory bool
+}
+
+// parseSQLDSN infers the driver from the scheme of dsn: postgres:// and
+// postgresql:// for PostgreSQL, sqlite: for SQLite. SQLite DSNs name a file,
+// as in sqlite:///var/lib/iceberg.db or sqlite:iceberg.db, or an in-memory
+// database with sqlite::memory:.
+func parseSQLDSN(dsn string) (sqlDSN, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return sqlDSN{}, fmt.Errorf("sql_dsn isn't a valid URL: %w", err)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "postgres", "postgresql":
+		if u.Host == "" {
+			return sqlDSN{}, errors.New("sql_dsn has no host")
+		}
+
+		return sqlDSN{driver: "postgres", dialect: sqlcat.Postgres, driverDSN: dsn}, nil
+	case "sqlite", "sqlite3":
+		file := u.Opaque
+		if file == "" {
+			file = u.Host + u.Path
+		}
+		switch file {
+		case "":
+			return sqlDSN{}, errors.New("sql_dsn names no SQLite database file; use sqlite::memory: for an in-memory database")
+		case ":memory:":
+			return sqlDSN{driver: "sqlite", dialect: sqlcat.SQLite, driverDSN: ":memory:", memory: true}, nil
+		}
+		driverDSN := "file:" + file
+		if u.RawQuery != "" {
+			driverDSN += "?" + u.RawQuery
+		}
+
+		return sqlDSN{driver: "sqlite", dialect: sqlcat.SQLite, driverDSN: driverDSN}, nil
+	case "":
+		return sqlDSN{}, errors.New("sql_dsn has no scheme; it must start with postgres://, postgresql:// or sqlite:")
+	default:
+		return sqlDSN{}, fmt.Errorf("unsupported sql_dsn scheme %q; use postgres://, postgresql:// or sqlite:", u.Scheme)
+	}
+}
+
+// openSQLDatabases holds the databases opened by this process by DSN.
+var openSQLDatabases struct {
+	mu  sync.Mutex
+	dbs map[string]*sql.DB
+}
+
+// openSQLDatabase returns the database of dsn, opening it if no provider
+// instance did yet. sql.Open doesn't connect, so a database that can't be
+// reached is reported by the first catalog operation.
+func openSQLDatabase(dsn string, parsed sqlDSN) (*sql.DB, error) {
+	openSQLDatabases.mu.Lock()
+	defer openSQLDatabases.mu.Unlock()
+
+	if db, ok := openSQLDatabases.dbs[dsn]; ok {
+		return db, nil
+	}
+
+	var db *sql.DB
+	var err error
+	if parsed.memory {
+		db, err = openMemoryDatabase()
+	} else {
+		db, err = sql.Open(parsed.driver, parsed.driverDSN)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if openSQLDatabases.dbs == nil {
+		openSQLDatabases.dbs = make(map[string]*sql.DB)
+	}
+	openSQLDatabases.dbs[dsn] = db
+
+	return db, nil
+}
+
+// openMemoryDatabase opens a new in-memory SQLite database. Every connection
+// to :memory: has a database of its own; a single connection that is never
+// closed keeps the one catalog.
+func openMemoryDatabase() (*sql.DB, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+
+	return db, nil
+}
+
+// loadSQLConfig returns the SQL catalog configuration for sql_dsn, nil while
+// it is unknown. An invalid DSN is reported as an error.
+func loadSQLConfig(data icebergProviderModel, diags *diag.Diagnostics) *sqlConfig {
+	if data.SQLDSN.IsUnknown() {
+		return nil
+	}
+
+	dsn := data.SQLDSN.ValueString()
+	parsed, err := parseSQLDSN(dsn)
+	if err != nil {
+		diags.AddAttributeError(path.Root("sql_dsn"), "Invalid sql_dsn", err.Error()+".")
+
+		return nil
+	}
+	db, err := openSQLDatabase(dsn, parsed)
+	if err != nil {
+		diags.AddAttributeError(path.Root("sql_dsn"), "Invalid sql_dsn", "Failed to open the database: "+err.Error()+".")
+
+		return nil
+	}
+
+	endpoint := dsn
+	if u, err := url.Parse(dsn); err == nil {
+		endpoint = u.Redacted()
+	}
+
+	return &sqlConfig{dialect: parsed.dialect, db: db, endpoint: endpoint}
+}
+
+// validateSQLConfig checks the attributes of data with type = "sql" or
+// "memory", both SQL catalogs: sql_dsn is required for "sql" and not allowed
+// for "memory", and the attributes of REST catalogs aren't allowed.
+func validateSQLConfig(catalogType string, data icebergProviderModel, diags *diag.Diagnostics) {
+	switch {
+	case catalogType == "sql" && data.SQLDSN.IsNull():
+		diags.AddAttributeError(path.Root("sql_dsn"), "Missing sql_dsn", "sql_dsn is required with type = 'sql'.")
+	case catalogType != "sql" && !data.SQLDSN.IsNull():
+		diags.AddAttributeError(path.Root("sql_dsn"), "Invalid SQL configuration",
+			"sql_dsn only applies with type = 'sql'. Set type or remove it.")
+	}
+	if glueAttributesSet(data) {
+		diags.AddError("Invalid Glue configuration",
+			"The glue_* attributes only apply with type = 'glue'. Set type or remove them.")
+	}
+
+	stored := "The SQL catalog is stored in the database of sql_dsn"
+	if catalogType == "memory" {
+		stored = "The memory catalog is stored in the provider process"
+	}
+	for _, attr := range append(restOnlyAttributes(data), nullAttribute{"audit_log_path", data.AuditLogPath.IsNull()}) {
+		if !attr.null {
+			diags.AddAttributeError(path.Root(attr.name), fmt.Sprintf("Attribute not supported with type '%s'", catalogType),
+				attr.name+" only applies to catalogs reached over HTTP. "+stored+"; remove it from the configuration.")
+		}
+	}
+}
+
+// newSQLCatalog returns a client of the SQL catalog in the database of
+// sql_dsn, or the in-memory database with type = "memory", creating its
+// tables if they don't exist. New tables are created below warehouse, and
+// their metadata files are written and read with the s3, adls and gcs blocks.
+func (p *icebergProvider) newSQLCatalog() (catalog.Catalog, error) {
+	props := maps.Clone(p.fileIO)
+	if props == nil {
+		props = iceberg.Properties{}
+	}
+	switch {
+	case p.warehouse != "":
+		props["warehouse"] = p.warehouse
+	case p.sql.warehouse != "":
+		props["warehouse"] = p.sql.warehouse
+	}
+
+	if p.capabilities != nil {
+		p.capabilities.setServer(p.catalogType)
+	}
+
+	return sqlcat.NewCatalog(sqlCatalogName, p.sql.db, p.sql.dialect, props)
+}
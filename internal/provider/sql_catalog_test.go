@@ -0,0 +1,191 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	sqlcat "github.com/apache/iceberg-go/catalog/sql"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSQLDSN(t *testing.T) {
+	for _, tc := range []struct {
+		dsn     string
+		want    sqlDSN
+		wantErr string
+	}{
+		{
+			dsn:  "postgres://iceberg:secret@db:5432/catalog?sslmode=disable",
+			want: sqlDSN{driver: "postgres", dialect: sqlcat.Postgres, driverDSN: "postgres://iceberg:secret@db:5432/catalog?sslmode=disable"},
+		},
+		{
+			dsn:  "postgresql://db/catalog",
+			want: sqlDSN{driver: "postgres", dialect: sqlcat.Postgres, driverDSN: "postgresql://db/catalog"},
+		},
+		{
+			dsn:  "sqlite:///var/lib/iceberg.db",
+			want: sqlDSN{driver: "sqlite", dialect: sqlcat.SQLite, driverDSN: "file:/var/lib/iceberg.db"},
+		},
+		{
+			dsn:  "sqlite:iceberg.db?_pragma=busy_timeout(5000)",
+			want: sqlDSN{driver: "sqlite", dialect: sqlcat.SQLite, driverDSN: "file:iceberg.db?_pragma=busy_timeout(5000)"},
+		},
+		{
+			dsn:  "sqlite::memory:",
+			want: sqlDSN{driver: "sqlite", dialect: sqlcat.SQLite, driverDSN: ":memory:", memory: true},
+		},
+		{dsn: "postgres:///catalog", wantErr: "no host"},
+		{dsn: "sqlite:", wantErr: "names no SQLite database file"},
+		{dsn: "iceberg.db", wantErr: "no scheme"},
+		{dsn: "mysql://db/catalog", wantErr: `unsupported sql_dsn scheme "mysql"`},
+	} {
+		t.Run(tc.dsn, func(t *testing.T) {
+			got, err := parseSQLDSN(tc.dsn)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestProviderSQL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid dsn", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":    tftypes.NewValue(tftypes.String, "sql"),
+			"sql_dsn": tftypes.NewValue(tftypes.String, "mysql://db/catalog"),
+		})
+		require.True(t, diags.HasError())
+		assert.Equal(t, "Invalid sql_dsn", diags.Errors()[0].Summary())
+	})
+
+	for name, dsn := range map[string]string{
+		"sqlite file":      "sqlite:" + filepath.Join(t.TempDir(), "catalog.db"),
+		"sqlite in memory": "sqlite::memory:",
+	} {
+		t.Run(name, func(t *testing.T) {
+			testSQLCatalogLifecycle(ctx, t, dsn)
+		})
+	}
+
+	t.Run("redacted endpoint", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"type":    tftypes.NewValue(tftypes.String, "sql"),
+			"sql_dsn": tftypes.NewValue(tftypes.String, "postgres://iceberg:s3cr3t@db:5432/catalog"),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		assert.NotContains(t, p.sql.endpoint, "s3cr3t")
+	})
+}
+
+// testSQLCatalogLifecycle manages a namespace and a table in the SQL catalog
+// of dsn through two provider instances.
+func testSQLCatalogLifecycle(ctx context.Context, t *testing.T, dsn string) {
+	t.Helper()
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"type":      tftypes.NewValue(tftypes.String, "sql"),
+		"sql_dsn":   tftypes.NewValue(tftypes.String, dsn),
+		"warehouse": tftypes.NewValue(tftypes.String, "file://"+t.TempDir()),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	require.NotNil(t, p.sql)
+	assert.Equal(t, "sql", p.catalogType)
+
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+	assert.Equal(t, catalog.SQL, cat.CatalogType())
+
+	ns := catalog.ToIdentifier("db1")
+	require.NoError(t, cat.CreateNamespace(ctx, ns, iceberg.Properties{"owner": "ci"}))
+	props, err := cat.LoadNamespaceProperties(ctx, ns)
+	require.NoError(t, err)
+	assert.Equal(t, "ci", props["owner"])
+
+	ident := catalog.ToIdentifier("db1", "events")
+	schema := iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true})
+	_, err = cat.CreateTable(ctx, ident, schema)
+	require.NoError(t, err)
+
+	// Another provider instance with the same DSN sees the same catalog.
+	other, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"type":    tftypes.NewValue(tftypes.String, "sql"),
+		"sql_dsn": tftypes.NewValue(tftypes.String, dsn),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	otherCat, err := other.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+	tbl, err := otherCat.LoadTable(ctx, ident)
+	require.NoError(t, err)
+	assert.Equal(t, 1, tbl.Schema().NumFields())
+
+	require.NoError(t, cat.DropTable(ctx, ident))
+	require.NoError(t, cat.DropNamespace(ctx, ns))
+}
+
+// TestAccIcebergTable_SQL manages a namespace and a table in an in-memory
+// SQLite catalog, which needs no server at all.
+func TestAccIcebergTable_SQL(t *testing.T) {
+	providerCfg := fmt.Sprintf(`
+provider "iceberg" {
+  type      = "sql"
+  sql_dsn   = "sqlite::memory:"
+  warehouse = "file://%s"
+}
+`, t.TempDir())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIcebergTableResourceConfig(providerCfg, "db1", "test_table"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.db1", "name.0", "db1"),
+					resource.TestCheckResourceAttr("iceberg_table.test", "name", "test_table"),
+					resource.TestCheckResourceAttr("iceberg_table.test", "schema.fields.#", "2"),
+				),
+			},
+			{
+				Config: testAccIcebergTableResourceConfig(providerCfg, "db1", "renamed_table"),
+				Check:  resource.TestCheckResourceAttr("iceberg_table.test", "name", "renamed_table"),
+			},
+			{
+				ResourceName:      "iceberg_table.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"server_properties",
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// lastCommitTimestampMs returns the timestamp of the current snapshot of tbl,
+// in milliseconds since the epoch, or nil if the table has no snapshot yet.
+func lastCommitTimestampMs(tbl *table.Table) *int64 {
+	snapshot := tbl.CurrentSnapshot()
+	if snapshot == nil {
+		return nil
+	}
+
+	return &snapshot.TimestampMs
+}
+
+// validateMaxStalenessConfig rejects a max_staleness_check that isn't a
+// positive duration.
+func validateMaxStalenessConfig(maxStaleness types.String, diags *diag.Diagnostics) {
+	if maxStaleness.IsNull() || maxStaleness.IsUnknown() {
+		return
+	}
+
+	if d, err := time.ParseDuration(maxStaleness.ValueString()); err != nil || d <= 0 {
+		diags.AddAttributeError(
+			path.Root("max_staleness_check"),
+			"Invalid max_staleness_check",
+			fmt.Sprintf("max_staleness_check must be a positive duration such as 36h or 90m, got %q.", maxStaleness.ValueString()),
+		)
+	}
+}
+
+// checkStaleness warns if the last commit to the table name, lastCommit, is
+// older than maxStaleness at now. It never adds errors, so a stale table
+// doesn't break refreshes; a table without snapshots counts as stale.
+func checkStaleness(name string, maxStaleness types.String, lastCommit types.Int64, now time.Time, diags *diag.Diagnostics) {
+	if maxStaleness.IsNull() || maxStaleness.IsUnknown() {
+		return
+	}
+	limit, err := time.ParseDuration(maxStaleness.ValueString())
+	if err != nil || limit <= 0 {
+		return
+	}
+
+	if lastCommit.IsNull() {
+		diags.AddWarning(
+			"Table is stale",
+			fmt.Sprintf("Table %s has no commits yet, so it is older than its max_staleness_check of %s.", name, maxStaleness.ValueString()),
+		)
+
+		return
+	}
+
+	committed := time.UnixMilli(lastCommit.ValueInt64())
+	if age := now.Sub(committed); age > limit {
+		diags.AddWarning(
+			"Table is stale",
+			fmt.Sprintf("The last commit to table %s was at %s, %s ago, which is longer than its max_staleness_check of %s.",
+				name, committed.UTC().Format(time.RFC3339), age.Round(time.Second), maxStaleness.ValueString()),
+		)
+	}
+}
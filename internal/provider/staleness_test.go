@@ -0,0 +1,137 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMaxStalenessConfig(t *testing.T) {
+	for _, valid := range []types.String{types.StringNull(), types.StringUnknown(), types.StringValue("36h"), types.StringValue("1h30m")} {
+		var diags diag.Diagnostics
+		validateMaxStalenessConfig(valid, &diags)
+		assert.False(t, diags.HasError(), "%s: %v", valid, diags)
+	}
+
+	for _, invalid := range []string{"", "2d", "-1h", "0s"} {
+		var diags diag.Diagnostics
+		validateMaxStalenessConfig(types.StringValue(invalid), &diags)
+		require.Len(t, diags.Errors(), 1, invalid)
+		assert.Equal(t, "Invalid max_staleness_check", diags.Errors()[0].Summary())
+	}
+}
+
+func TestCheckStaleness(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	committed := types.Int64Value(now.Add(-48 * time.Hour).UnixMilli())
+
+	tests := []struct {
+		name         string
+		maxStaleness types.String
+		lastCommit   types.Int64
+		wantDetail   string
+	}{
+		{name: "not checked", maxStaleness: types.StringNull(), lastCommit: committed},
+		{name: "fresh", maxStaleness: types.StringValue("72h"), lastCommit: committed},
+		{
+			name:         "stale",
+			maxStaleness: types.StringValue("36h"),
+			lastCommit:   committed,
+			wantDetail:   "The last commit to table db.events was at 2024-05-30T12:00:00Z, 48h0m0s ago, which is longer than its max_staleness_check of 36h.",
+		},
+		{
+			name:         "no commits",
+			maxStaleness: types.StringValue("36h"),
+			lastCommit:   types.Int64Null(),
+			wantDetail:   "Table db.events has no commits yet, so it is older than its max_staleness_check of 36h.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			checkStaleness("db.events", tt.maxStaleness, tt.lastCommit, now, &diags)
+
+			require.False(t, diags.HasError(), "%v", diags)
+			if tt.wantDetail == "" {
+				assert.Empty(t, diags)
+
+				return
+			}
+			require.Len(t, diags.Warnings(), 1)
+			assert.Equal(t, "Table is stale", diags.Warnings()[0].Summary())
+			assert.Equal(t, tt.wantDetail, diags.Warnings()[0].Detail())
+		})
+	}
+}
+
+func TestAccIcebergTable_MaxStalenessCheck(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := func(maxStaleness string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "events" {
+  namespace           = ["db"]
+  name                = "events"
+  max_staleness_check = "%s"
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL, maxStaleness)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("2d"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid max_staleness_check`),
+			},
+			{
+				// The new table has no commits, which only warns.
+				Config: config("36h"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("iceberg_table.events", "last_commit_timestamp_ms"),
+					resource.TestCheckResourceAttr("iceberg_table.events", "max_staleness_check", "36h"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,166 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"maps"
+
+	"github.com/apache/iceberg-terraform/internal/properties"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// priorSchemaWithout returns a copy of current as it looked before the given
+// attributes were added, for use as the PriorSchema of a state upgrader.
+func priorSchemaWithout(current schema.Schema, version int64, attributes ...string) *schema.Schema {
+	prior := current
+	prior.Version = version
+	prior.Attributes = maps.Clone(current.Attributes)
+	for _, name := range attributes {
+		delete(prior.Attributes, name)
+	}
+
+	return &prior
+}
+
+// upgradeServerManagedProperties upgrades state written before the
+// server_managed_properties attribute existed. The new attribute is derived
+// from the stored server_properties and user_properties so the first plan
+// after the upgrade doesn't show a diff.
+func upgradeServerManagedProperties(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var userProperties, serverProperties types.Map
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("user_properties"), &userProperties)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("server_properties"), &serverProperties)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := make(map[string]string)
+	if !userProperties.IsNull() && !userProperties.IsUnknown() {
+		resp.Diagnostics.Append(userProperties.ElementsAs(ctx, &managed, false)...)
+	}
+	server := make(map[string]string)
+	if !serverProperties.IsNull() && !serverProperties.IsUnknown() {
+		resp.Diagnostics.Append(serverProperties.ElementsAs(ctx, &server, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serverManaged, diags := types.MapValueFrom(ctx, types.StringType, properties.ServerManaged(managed, server))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serverManagedValue, err := serverManaged.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to upgrade resource state", err.Error())
+
+		return
+	}
+
+	var values map[string]tftypes.Value
+	if err := req.State.Raw.As(&values); err != nil {
+		resp.Diagnostics.AddError("failed to upgrade resource state", err.Error())
+
+		return
+	}
+	values["server_managed_properties"] = serverManagedValue
+
+	// Attributes added after server_managed_properties start out null.
+	setUpgradedState(ctx, values, resp)
+}
+
+// upgradeTableState upgrades iceberg_table state of schema version 0, which
+// needs both upgradeServerManagedProperties and upgradeServerSchema.
+func upgradeTableState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	upgradeServerManagedProperties(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgradeServerSchema(ctx, resource.UpgradeStateRequest{State: &resp.State}, resp)
+}
+
+// upgradePrincipalProperties upgrades iceberg_polaris_principal state of
+// schema version 0, whose properties held all properties of the principal
+// and removed those the configuration didn't list. They move to
+// user_properties, so the same keys stay managed, and to server_properties,
+// and upgradeServerManagedProperties derives server_managed_properties.
+func upgradePrincipalProperties(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var values map[string]tftypes.Value
+	if err := req.State.Raw.As(&values); err != nil {
+		resp.Diagnostics.AddError("failed to upgrade resource state", err.Error())
+
+		return
+	}
+
+	props := values["properties"]
+	delete(values, "properties")
+	values["user_properties"] = props
+	if props.IsNull() {
+		// Principals without properties stored null.
+		props = tftypes.NewValue(props.Type(), map[string]tftypes.Value{})
+	}
+	values["server_properties"] = props
+
+	setUpgradedState(ctx, values, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	upgradeServerManagedProperties(ctx, resource.UpgradeStateRequest{State: &resp.State}, resp)
+}
+
+// upgradeServerSchema upgrades iceberg_table state written before the
+// server_schema attribute existed, when the schema attribute held the docs
+// and initial defaults of the catalog whether or not they were configured.
+// They are copied to server_schema so the first plan after the upgrade
+// doesn't show a diff. Those in schema that the configuration doesn't set
+// turn null with the next apply, which leaves them as they are on the table.
+func upgradeServerSchema(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var values map[string]tftypes.Value
+	if err := req.State.Raw.As(&values); err != nil {
+		resp.Diagnostics.AddError("failed to upgrade resource state", err.Error())
+
+		return
+	}
+	if schemaValue, ok := values["schema"]; ok {
+		values["server_schema"] = schemaValue
+	}
+
+	setUpgradedState(ctx, values, resp)
+}
+
+// setUpgradedState sets the upgraded state to values, with the attributes
+// they don't have null.
+func setUpgradedState(ctx context.Context, values map[string]tftypes.Value, resp *resource.UpgradeStateResponse) {
+	currentType := resp.State.Schema.Type().TerraformType(ctx)
+	if objectType, ok := currentType.(tftypes.Object); ok {
+		for name, attrType := range objectType.AttributeTypes {
+			if _, ok := values[name]; !ok {
+				values[name] = tftypes.NewValue(attrType, nil)
+			}
+		}
+	}
+
+	resp.State.Raw = tftypes.NewValue(currentType, values)
+}
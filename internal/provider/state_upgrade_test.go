@@ -0,0 +1,153 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeTableState(t *testing.T) {
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	resourceType := schemas.ResourceSchemas["iceberg_table"].ValueType()
+
+	for _, tc := range []struct {
+		name    string
+		version int64
+		state   string
+	}{
+		{
+			name:    "version 0",
+			version: 0,
+			state:   `{"id": "db.events", "namespace": ["db"], "name": "events", "user_properties": {"owner": "etl"}, "server_properties": {"owner": "etl", "location": "s3://bucket/events"}, "schema": {"id": 0, "fields": [{"id": 1, "name": "id", "type": "long", "required": true, "doc": "The ID"}]}}`,
+		},
+		{
+			name:    "version 1",
+			version: 1,
+			state:   `{"id": "db.events", "namespace": ["db"], "name": "events", "schema": {"id": 0, "fields": [{"id": 1, "name": "id", "type": "long", "required": true, "doc": "The ID"}]}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "iceberg_table",
+				Version:  tc.version,
+				RawState: &tfprotov6.RawState{JSON: []byte(tc.state)},
+			})
+			require.NoError(t, err)
+			requireNoErrorDiagnostics(t, resp.Diagnostics)
+
+			upgraded, err := resp.UpgradedState.Unmarshal(resourceType)
+			require.NoError(t, err)
+			var values map[string]tftypes.Value
+			require.NoError(t, upgraded.As(&values))
+			assert.True(t, values["server_schema"].Equal(values["schema"]), "server_schema is the schema the state held")
+			assert.Contains(t, values["server_schema"].String(), "The ID")
+			if tc.version == 0 {
+				assert.Contains(t, values["server_managed_properties"].String(), "s3://bucket/events")
+			}
+		})
+	}
+}
+
+func TestUpgradeNamespaceState(t *testing.T) {
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	resourceType := schemas.ResourceSchemas["iceberg_namespace"].ValueType()
+
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "iceberg_namespace",
+		Version:  0,
+		RawState: &tfprotov6.RawState{JSON: []byte(`{"id": "db", "name": ["db"], "user_properties": {"owner": "etl"}, "server_properties": {"owner": "etl", "location": "s3://bucket/db"}}`)},
+	})
+	require.NoError(t, err)
+	requireNoErrorDiagnostics(t, resp.Diagnostics)
+
+	upgraded, err := resp.UpgradedState.Unmarshal(resourceType)
+	require.NoError(t, err)
+	var values map[string]tftypes.Value
+	require.NoError(t, upgraded.As(&values))
+	assert.True(t, values["server_managed_properties"].Equal(tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		"location": tftypes.NewValue(tftypes.String, "s3://bucket/db"),
+	})))
+	assert.True(t, values["comment"].IsNull(), "attributes added later start out null")
+}
+
+func TestUpgradePolarisPrincipalState(t *testing.T) {
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	resourceType := schemas.ResourceSchemas["iceberg_polaris_principal"].ValueType()
+	mapType := tftypes.Map{ElementType: tftypes.String}
+	team := tftypes.NewValue(mapType, map[string]tftypes.Value{"team": tftypes.NewValue(tftypes.String, "data")})
+	empty := tftypes.NewValue(mapType, map[string]tftypes.Value{})
+
+	for _, tc := range []struct {
+		name                    string
+		state                   string
+		userProperties          tftypes.Value
+		serverProperties        tftypes.Value
+		serverManagedProperties tftypes.Value
+	}{
+		{
+			name:                    "properties",
+			state:                   `{"id": "etl", "name": "etl", "properties": {"team": "data"}, "credential_rotation_required": false, "client_id": "id-etl", "client_secret": "secret-etl"}`,
+			userProperties:          team,
+			serverProperties:        team,
+			serverManagedProperties: empty,
+		},
+		{
+			name:                    "no properties",
+			state:                   `{"id": "etl", "name": "etl", "properties": null, "credential_rotation_required": false, "client_id": "id-etl", "client_secret": "secret-etl"}`,
+			userProperties:          tftypes.NewValue(mapType, nil),
+			serverProperties:        empty,
+			serverManagedProperties: empty,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+				TypeName: "iceberg_polaris_principal",
+				Version:  0,
+				RawState: &tfprotov6.RawState{JSON: []byte(tc.state)},
+			})
+			require.NoError(t, err)
+			requireNoErrorDiagnostics(t, resp.Diagnostics)
+
+			upgraded, err := resp.UpgradedState.Unmarshal(resourceType)
+			require.NoError(t, err)
+			var values map[string]tftypes.Value
+			require.NoError(t, upgraded.As(&values))
+			assert.True(t, values["user_properties"].Equal(tc.userProperties), "user_properties: %s", values["user_properties"])
+			assert.True(t, values["server_properties"].Equal(tc.serverProperties), "server_properties: %s", values["server_properties"])
+			assert.True(t, values["server_managed_properties"].Equal(tc.serverManagedProperties), "server_managed_properties: %s", values["server_managed_properties"])
+			assert.True(t, values["client_secret"].Equal(tftypes.NewValue(tftypes.String, "secret-etl")), "the credentials are kept")
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// tableFeature is a feature of the table format that required_features can
+// demand. A table has it if its format version is at least minFormatVersion.
+type tableFeature struct {
+	key              string
+	name             string
+	minFormatVersion int
+}
+
+// tableFeatures lists the features required_features accepts. Add an entry
+// here to support a new one.
+var tableFeatures = []tableFeature{
+	{key: "row-level-deletes", name: "position and equality delete files", minFormatVersion: 2},
+	{key: "deletion-vectors", name: "deletion vectors", minFormatVersion: 3},
+	{key: "row-lineage", name: "row lineage", minFormatVersion: 3},
+	{key: "default-values", name: "column default values", minFormatVersion: 3},
+	{key: "variant-type", name: "the variant type", minFormatVersion: 3},
+	{key: "nanosecond-timestamps", name: "nanosecond timestamps", minFormatVersion: 3},
+}
+
+// tableFeatureKeys returns the keys of all features, for validation.
+func tableFeatureKeys() []string {
+	keys := make([]string, 0, len(tableFeatures))
+	for _, f := range tableFeatures {
+		keys = append(keys, f.key)
+	}
+
+	return keys
+}
+
+func lookupTableFeature(key string) (tableFeature, bool) {
+	for _, f := range tableFeatures {
+		if f.key == key {
+			return f, true
+		}
+	}
+
+	return tableFeature{}, false
+}
+
+// plannedFormatVersion returns the format version a table with props is
+// created or upgraded to: the format-version property, or current if it isn't
+// set or lower. current is table.DefaultFormatVersion for a new table.
+func plannedFormatVersion(props map[string]string, current int) (int, error) {
+	value, ok := props[table.PropertyFormatVersion]
+	if !ok {
+		return current, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s property %q", table.PropertyFormatVersion, value)
+	}
+
+	return max(version, current), nil
+}
+
+// unsupportedTableFeatures returns why each of the required features isn't
+// available in a table of formatVersion.
+func unsupportedTableFeatures(required []string, formatVersion int) []string {
+	var problems []string
+	for _, key := range required {
+		f, ok := lookupTableFeature(key)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s isn't a known feature", key))
+
+			continue
+		}
+		if formatVersion < f.minFormatVersion {
+			problems = append(problems, fmt.Sprintf("%s (%s) needs format version %d, the table has %d", f.key, f.name, f.minFormatVersion, formatVersion))
+		}
+	}
+
+	return problems
+}
+
+// checkRequiredFeatures adds an error if a feature of requiredFeatures isn't
+// available in a table of formatVersion. what describes the table, for
+// example "The planned table" or "The table a.b".
+func checkRequiredFeatures(ctx context.Context, requiredFeatures types.List, formatVersion int, what string, diags *diag.Diagnostics) {
+	problems := unsupportedTableFeatures(requiredFeatureKeys(ctx, requiredFeatures, diags), formatVersion)
+	if len(problems) == 0 {
+		return
+	}
+	diags.AddAttributeError(
+		path.Root("required_features"),
+		"Required table features not supported",
+		fmt.Sprintf("%s doesn't support all of required_features:\n\n- %s\n\n"+
+			"Set the format-version property in user_properties to upgrade the table, or remove the features from required_features.",
+			what, strings.Join(problems, "\n- ")),
+	)
+}
+
+// verifyCreatedFeatures adds an error if tbl, just created, lacks a feature of
+// required_features because the catalog created it with a lower format
+// version than requested, for example because it doesn't support the newer
+// one.
+func verifyCreatedFeatures(ctx context.Context, requiredFeatures types.List, tbl *table.Table, diags *diag.Diagnostics) {
+	problems := unsupportedTableFeatures(requiredFeatureKeys(ctx, requiredFeatures, diags), tbl.Metadata().Version())
+	if len(problems) == 0 {
+		return
+	}
+	diags.AddAttributeError(
+		path.Root("required_features"),
+		"Catalog created an older table format",
+		fmt.Sprintf("The table %s was created, but the catalog gave it format version %d, so it doesn't support all of required_features:\n\n- %s\n\n"+
+			"The catalog most likely doesn't support the requested format version. The table isn't added to the state; "+
+			"drop it, then use a catalog that supports the features or remove them from required_features.",
+			encodeIdentifier(tbl.Identifier()), tbl.Metadata().Version(), strings.Join(problems, "\n- ")),
+	)
+}
+
+// requiredFeatureKeys returns the elements of required_features, none if it
+// is null or unknown.
+func requiredFeatureKeys(ctx context.Context, requiredFeatures types.List, diags *diag.Diagnostics) []string {
+	if requiredFeatures.IsNull() || !fullyKnown(ctx, requiredFeatures) {
+		return nil
+	}
+	var required []string
+	diags.Append(requiredFeatures.ElementsAs(ctx, &required, false)...)
+
+	return required
+}
+
+// validateRequiredFeatures checks the required_features of data against the
+// format version the table has once the plan is applied. tbl is the table in
+// the catalog, nil for a new table.
+func validateRequiredFeatures(ctx context.Context, data *icebergTableResourceModel, tbl *table.Table, diags *diag.Diagnostics) {
+	if data.RequiredFeatures.IsNull() || !fullyKnown(ctx, data.UserProperties) {
+		return
+	}
+
+	props := make(map[string]string)
+	if !data.UserProperties.IsNull() {
+		diags.Append(data.UserProperties.ElementsAs(ctx, &props, false)...)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	current, what := table.DefaultFormatVersion, "The planned table"
+	if tbl != nil {
+		current, what = tbl.Metadata().Version(), "The table "+encodeIdentifier(tbl.Identifier())
+	}
+	formatVersion, err := plannedFormatVersion(props, current)
+	if err != nil {
+		diags.AddAttributeError(path.Root("user_properties"), "Invalid format version", err.Error())
+
+		return
+	}
+
+	checkRequiredFeatures(ctx, data.RequiredFeatures, formatVersion, what, diags)
+}
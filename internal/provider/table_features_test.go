@@ -0,0 +1,172 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableFeatures(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, f := range tableFeatures {
+		assert.False(t, seen[f.key], "duplicate feature %s", f.key)
+		seen[f.key] = true
+		assert.NotEmpty(t, f.name, f.key)
+		assert.GreaterOrEqual(t, f.minFormatVersion, 1, f.key)
+	}
+}
+
+func TestUnsupportedTableFeatures(t *testing.T) {
+	assert.Empty(t, unsupportedTableFeatures([]string{"row-level-deletes"}, 2))
+	assert.Empty(t, unsupportedTableFeatures([]string{"deletion-vectors", "row-lineage"}, 3))
+	assert.Equal(t, []string{
+		"deletion-vectors (deletion vectors) needs format version 3, the table has 2",
+		"row-lineage (row lineage) needs format version 3, the table has 2",
+	}, unsupportedTableFeatures([]string{"deletion-vectors", "row-lineage", "row-level-deletes"}, 2))
+	assert.Equal(t, []string{"time-travel isn't a known feature"}, unsupportedTableFeatures([]string{"time-travel"}, 3))
+}
+
+func TestPlannedFormatVersion(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		props   map[string]string
+		current int
+		want    int
+		wantErr bool
+	}{
+		{name: "default", current: 2, want: 2},
+		{name: "upgrade", props: map[string]string{"format-version": "3"}, current: 2, want: 3},
+		{name: "no downgrade", props: map[string]string{"format-version": "1"}, current: 2, want: 2},
+		{name: "invalid", props: map[string]string{"format-version": "three"}, current: 2, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := plannedFormatVersion(tc.props, tc.current)
+			if tc.wantErr {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestValidateRequiredFeatures(t *testing.T) {
+	ctx := context.Background()
+	features := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("row-lineage")})
+	v3 := types.MapValueMust(types.StringType, map[string]attr.Value{"format-version": types.StringValue("3")})
+
+	newTable := func(formatVersion string) *table.Table {
+		metadata, err := mockTableMetadata("db", "events", map[string]string{"format-version": formatVersion})
+		require.NoError(t, err)
+
+		return table.New(table.Identifier{"db", "events"}, metadata, metadata.Location()+"/metadata/00000.metadata.json", nil, nil)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		properties types.Map
+		tbl        *table.Table
+		wantError  string
+	}{
+		{name: "new table", properties: types.MapNull(types.StringType), wantError: "The planned table doesn't support all of required_features"},
+		{name: "new v3 table", properties: v3},
+		{name: "existing v2 table", properties: types.MapNull(types.StringType), tbl: newTable("2"), wantError: "The table db.events doesn't support"},
+		{name: "existing v2 table upgraded", properties: v3, tbl: newTable("2")},
+		{name: "existing v3 table", properties: types.MapNull(types.StringType), tbl: newTable("3")},
+		{name: "unknown properties", properties: types.MapUnknown(types.StringType)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateRequiredFeatures(ctx, &icebergTableResourceModel{RequiredFeatures: features, UserProperties: tc.properties}, tc.tbl, &diags)
+			if tc.wantError == "" {
+				require.False(t, diags.HasError(), "%v", diags)
+
+				return
+			}
+			require.Len(t, diags.Errors(), 1)
+			assert.Equal(t, "Required table features not supported", diags.Errors()[0].Summary())
+			assert.Contains(t, diags.Errors()[0].Detail(), tc.wantError)
+		})
+	}
+
+	t.Run("created with an older format", func(t *testing.T) {
+		var diags diag.Diagnostics
+		verifyCreatedFeatures(ctx, features, newTable("3"), &diags)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		verifyCreatedFeatures(ctx, features, newTable("2"), &diags)
+		require.Len(t, diags.Errors(), 1)
+		assert.Contains(t, diags.Errors()[0].Detail(), "the catalog gave it format version 2")
+	})
+}
+
+func TestAccIcebergTable_RequiredFeatures(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := func(properties string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "events" {
+  namespace         = ["db"]
+  name              = "events"
+  required_features = ["deletion-vectors", "row-lineage"]
+  user_properties   = %s
+  schema = {
+    fields = [
+      {
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL, properties)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config("{}"),
+				ExpectError: regexp.MustCompile("row-lineage \\(row lineage\\) needs format version 3"),
+			},
+			{
+				Config: config(`{ "format-version" = "3" }`),
+				Check:  resource.TestCheckResourceAttr("iceberg_table.events", "required_features.#", "2"),
+			},
+		},
+	})
+}
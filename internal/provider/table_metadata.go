@@ -0,0 +1,94 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// checkTableMetadata returns an error describing the first inconsistency in
+// the metadata of a loaded table that the conversion to the resource model
+// can't handle: a current schema ID that matches no schema, or a default
+// partition spec or sort order that is missing or references columns that
+// aren't in the current schema. iceberg-go checks some of these when it
+// parses metadata but not all, and panics on a missing current schema.
+func checkTableMetadata(metadata table.Metadata) error {
+	schema, err := currentSchema(metadata)
+	if err != nil {
+		return err
+	}
+
+	spec := metadata.PartitionSpecByID(metadata.DefaultPartitionSpec())
+	if spec == nil {
+		return fmt.Errorf("the default partition spec %d doesn't exist", metadata.DefaultPartitionSpec())
+	}
+	for field := range spec.Fields() {
+		if _, ok := schema.FindFieldByID(field.SourceID); !ok {
+			return fmt.Errorf("partition field %q of spec %d references column %d, which isn't in the current schema %d",
+				field.Name, spec.ID(), field.SourceID, schema.ID)
+		}
+	}
+
+	if metadata.DefaultSortOrder() == table.UnsortedSortOrderID {
+		return nil
+	}
+	for _, order := range metadata.SortOrders() {
+		if order.OrderID() != metadata.DefaultSortOrder() {
+			continue
+		}
+		for field := range order.Fields() {
+			if _, ok := schema.FindFieldByID(field.SourceID); !ok {
+				return fmt.Errorf("sort order %d references column %d, which isn't in the current schema %d",
+					order.OrderID(), field.SourceID, schema.ID)
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("the default sort order %d doesn't exist", metadata.DefaultSortOrder())
+}
+
+// currentSchema returns the current schema of metadata, or an error rather
+// than the panic of metadata.CurrentSchema if no schema has its ID.
+func currentSchema(metadata table.Metadata) (schema *iceberg.Schema, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			schema, err = nil, errors.New("the current schema ID doesn't match any of its schemas")
+		}
+	}()
+
+	schema = metadata.CurrentSchema()
+	if schema == nil {
+		return nil, errors.New("the table has no current schema")
+	}
+
+	return schema, nil
+}
+
+// addInconsistentMetadataError reports that the metadata of the table tableName
+// is inconsistent. Only the resource or data source of the table fails; the
+// provider keeps serving every other one.
+func addInconsistentMetadataError(tableName string, err error, diags *diag.Diagnostics) {
+	diags.AddError("Inconsistent table metadata",
+		fmt.Sprintf("The metadata of table %s loaded from the catalog is inconsistent: %s. The table must be repaired in the catalog, for example by restoring a previous metadata file, before Terraform can manage it.",
+			tableName, err))
+}
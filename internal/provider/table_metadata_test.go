@@ -0,0 +1,167 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partitionByMissingColumn makes the default partition spec of the metadata
+// reference a column that no schema has.
+func partitionByMissingColumn(metadata map[string]any) {
+	metadata["partition-specs"] = []any{map[string]any{
+		"spec-id": 0,
+		"fields":  []any{map[string]any{"source-id": 99, "field-id": 1000, "name": "gone", "transform": "identity"}},
+	}}
+	metadata["last-partition-id"] = 1000
+}
+
+func TestCheckTableMetadata(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "healthy", nil)
+	m.addTable("db", "missing_schema", nil)
+	m.corruptTable("db", "missing_schema", func(metadata map[string]any) { metadata["current-schema-id"] = 7 })
+	m.addTable("db", "missing_spec_column", nil)
+	m.corruptTable("db", "missing_spec_column", partitionByMissingColumn)
+	server := m.start(t)
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	cat, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+
+	tbl, err := cat.LoadTable(ctx, table.Identifier{"db", "healthy"})
+	require.NoError(t, err)
+	assert.NoError(t, checkTableMetadata(tbl.Metadata()))
+
+	// iceberg-go rejects a current schema ID without a schema when it parses
+	// the metadata; Read reports it like the inconsistencies it misses.
+	_, err = cat.LoadTable(ctx, table.Identifier{"db", "missing_schema"})
+	require.Error(t, err)
+	assert.True(t, isInvalidMetadataError(err), "%v", err)
+
+	tbl, err = cat.LoadTable(ctx, table.Identifier{"db", "missing_spec_column"})
+	require.NoError(t, err)
+	err = checkTableMetadata(tbl.Metadata())
+	require.Error(t, err)
+	assert.Equal(t, `partition field "gone" of spec 0 references column 99, which isn't in the current schema 0`, err.Error())
+
+	diags = nil
+	addInconsistentMetadataError(encodeIdentifier(tbl.Identifier()), err, &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Inconsistent table metadata", diags.Errors()[0].Summary())
+	assert.Contains(t, diags.Errors()[0].Detail(), "The metadata of table db.missing_spec_column loaded from the catalog is inconsistent: partition field")
+}
+
+func TestCurrentSchema(t *testing.T) {
+	metadata, err := mockTableMetadata("db", "events", nil)
+	require.NoError(t, err)
+	schema, err := currentSchema(metadata)
+	require.NoError(t, err)
+	assert.Equal(t, 0, schema.ID)
+
+	_, err = currentSchema(panickingMetadata{metadata})
+	require.Error(t, err)
+	assert.Equal(t, "the current schema ID doesn't match any of its schemas", err.Error())
+
+	var diags diag.Diagnostics
+	addInconsistentMetadataError("db.events", checkTableMetadata(panickingMetadata{metadata}), &diags)
+	assert.Contains(t, diags.Errors()[0].Detail(), "db.events")
+}
+
+// panickingMetadata is metadata whose current schema ID matches no schema,
+// which iceberg-go only loads without error from catalogs that skip its
+// checks.
+type panickingMetadata struct {
+	table.Metadata
+}
+
+func (panickingMetadata) CurrentSchema() *iceberg.Schema {
+	panic("should never get here")
+}
+
+func TestAccIcebergTable_InconsistentMetadata(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	config := fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "healthy" {
+  namespace = ["db"]
+  name      = "healthy"
+  schema = {
+    fields = [
+      { name = "id", type = "long", required = true },
+    ]
+  }
+}
+
+resource "iceberg_table" "broken" {
+  namespace = ["db"]
+  name      = "broken"
+  schema = {
+    fields = [
+      { name = "id", type = "long", required = true },
+    ]
+  }
+}
+`, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					m.corruptTable("db", "broken", func(metadata map[string]any) { metadata["current-schema-id"] = 7 })
+				},
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Inconsistent table metadata.*db\.broken`),
+			},
+			{
+				PreConfig:   func() { m.corruptTable("db", "broken", partitionByMissingColumn) },
+				Config:      config,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`(?s)Inconsistent table metadata.*db\.broken.*references column 99`),
+			},
+			{
+				PreConfig: func() { m.corruptTable("db", "broken", nil) },
+				Config:    config,
+			},
+		},
+	})
+}
@@ -16,12 +16,16 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/apache/iceberg-go"
 	"github.com/apache/iceberg-go/table"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -30,15 +34,38 @@ type icebergTableSchema struct {
 	Fields []icebergTableSchemaField `tfsdk:"fields" json:"fields"`
 }
 
-func (s icebergTableSchema) AttrTypes() map[string]attr.Type {
-	return map[string]attr.Type{
+// schemaMaxDepth is how many levels of structs can be nested in a column of
+// the schema attribute.
+const schemaMaxDepth = 4
+
+// schemaAttrTypes and schemaFieldAttrTypes hold the attribute types of the
+// schema attribute and of its fields by depth. They are built once: every
+// conversion of a schema needs them, and building them allocates a map per
+// nesting level.
+var (
+	schemaAttrTypes = map[string]attr.Type{
 		"id": types.Int64Type,
 		"fields": types.ListType{
-			ElemType: types.ObjectType{
-				AttrTypes: icebergTableSchemaField{}.AttrTypes(4),
-			},
+			ElemType: types.ObjectType{AttrTypes: schemaFieldAttrTypes[schemaMaxDepth]},
 		},
 	}
+	schemaFieldAttrTypes = func() [schemaMaxDepth + 1]map[string]attr.Type {
+		var byDepth [schemaMaxDepth + 1]map[string]attr.Type
+		byDepth[0] = icebergTableSchemaField{}.newAttrTypes(nil)
+		for depth := 1; depth <= schemaMaxDepth; depth++ {
+			byDepth[depth] = icebergTableSchemaField{}.newAttrTypes(byDepth[depth-1])
+		}
+
+		return byDepth
+	}()
+	schemaListPropertiesAttrTypes = icebergTableSchemaFieldListProperties{}.AttrTypes()
+	schemaMapPropertiesAttrTypes  = icebergTableSchemaFieldMapProperties{}.AttrTypes()
+)
+
+// AttrTypes returns the attribute types of the schema attribute. The map is
+// shared and must not be modified.
+func (s icebergTableSchema) AttrTypes() map[string]attr.Type {
+	return schemaAttrTypes
 }
 
 func (s icebergTableSchema) MarshalJSON() ([]byte, error) {
@@ -79,7 +106,13 @@ func (s *icebergTableSchema) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (s *icebergTableSchema) ToIceberg() (*iceberg.Schema, error) {
+func (s *icebergTableSchema) ToIceberg() (_ *iceberg.Schema, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unexpected error converting the schema: %v", r)
+		}
+	}()
+
 	b, err := json.Marshal(s)
 	if err != nil {
 		return nil, err
@@ -101,6 +134,229 @@ func (s *icebergTableSchema) FromIceberg(icebergSchema *iceberg.Schema) error {
 	return json.Unmarshal(b, s)
 }
 
+// schemaObjectValue converts an Iceberg schema into the canonical object value
+// of a schema attribute, with every value the catalog has. Everything that
+// exposes a table schema should go through it so that all of them produce
+// identical structures.
+func schemaObjectValue(_ context.Context, icebergSchema *iceberg.Schema) (types.Object, diag.Diagnostics) {
+	return newSchemaObjectValue(icebergSchema, nil)
+}
+
+// syncedSchemaObjectValue is schemaObjectValue for the schema attribute of a
+// table resource that held the fields prior. The docs and initial defaults
+// the configuration doesn't set stay null, see keepUnsetFieldValues; those of
+// the catalog are in server_schema.
+func syncedSchemaObjectValue(_ context.Context, icebergSchema *iceberg.Schema, prior []icebergTableSchemaField) (types.Object, diag.Diagnostics) {
+	return newSchemaObjectValue(icebergSchema, func(fields []icebergTableSchemaField) {
+		keepUnsetFieldValues(prior, fields)
+	})
+}
+
+// newSchemaObjectValue converts icebergSchema to a schema attribute value,
+// passing its fields to adjust first unless adjust is nil.
+//
+// Tables can have thousands of columns, so the value is built directly
+// rather than through reflection, which converts every field to a Terraform
+// value and back. A panic while converting is reported as an error rather
+// than taking down the provider with the state of every other resource.
+func newSchemaObjectValue(icebergSchema *iceberg.Schema, adjust func([]icebergTableSchemaField)) (obj types.Object, diags diag.Diagnostics) {
+	defer func() {
+		if r := recover(); r != nil {
+			obj = types.ObjectNull(schemaAttrTypes)
+			diags.AddError("failed to convert iceberg schema to terraform schema", fmt.Sprintf("unexpected error converting the schema: %v", r))
+		}
+	}()
+
+	var s icebergTableSchema
+	if err := s.FromIceberg(icebergSchema); err != nil {
+		diags.AddError("failed to convert iceberg schema to terraform schema", err.Error())
+
+		return types.ObjectNull(schemaAttrTypes), diags
+	}
+	if adjust != nil {
+		adjust(s.Fields)
+	}
+
+	fields, d := schemaFieldsValue(s.Fields, schemaMaxDepth)
+	diags.Append(d...)
+	if diags.HasError() {
+		return types.ObjectNull(schemaAttrTypes), diags
+	}
+
+	obj, d = types.ObjectValue(schemaAttrTypes, map[string]attr.Value{
+		"id":     s.ID,
+		"fields": fields,
+	})
+	diags.Append(d...)
+
+	return obj, diags
+}
+
+// schemaFieldsValue converts fields that can have depth levels of structs
+// nested in them to the value of a fields attribute. It only recurses into
+// structs, so at most schemaMaxDepth times.
+func schemaFieldsValue(fields []icebergTableSchemaField, depth int) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	fieldAttrTypes := schemaFieldAttrTypes[depth]
+	elemType := types.ObjectType{AttrTypes: fieldAttrTypes}
+	structAttrTypes := fieldAttrTypes["struct_properties"].(types.ObjectType).AttrTypes
+
+	elems := make([]attr.Value, 0, len(fields))
+	for _, f := range fields {
+		structProperties := types.ObjectNull(structAttrTypes)
+		if f.StructProperties != nil {
+			if depth == 0 {
+				diags.AddError("failed to convert iceberg schema to terraform schema", fmt.Sprintf("Field %s nests structs deeper than %d levels.", f.Name, schemaMaxDepth))
+
+				return types.ListNull(elemType), diags
+			}
+			nested, d := schemaFieldsValue(f.StructProperties.Fields, depth-1)
+			diags.Append(d...)
+			structProperties, d = types.ObjectValue(structAttrTypes, map[string]attr.Value{"fields": nested})
+			diags.Append(d...)
+		}
+
+		listProperties := types.ObjectNull(schemaListPropertiesAttrTypes)
+		if p := f.ListProperties; p != nil {
+			var d diag.Diagnostics
+			listProperties, d = types.ObjectValue(schemaListPropertiesAttrTypes, map[string]attr.Value{
+				"element_id":       p.ID,
+				"element_type":     types.StringValue(p.Type),
+				"element_required": types.BoolValue(p.ElementRequired),
+			})
+			diags.Append(d...)
+		}
+
+		mapProperties := types.ObjectNull(schemaMapPropertiesAttrTypes)
+		if p := f.MapProperties; p != nil {
+			var d diag.Diagnostics
+			mapProperties, d = types.ObjectValue(schemaMapPropertiesAttrTypes, map[string]attr.Value{
+				"key_id":         p.KeyID,
+				"key_type":       types.StringValue(p.KeyType),
+				"value_id":       p.ValueID,
+				"value_type":     types.StringValue(p.ValueType),
+				"value_required": types.BoolValue(p.ValueRequired),
+			})
+			diags.Append(d...)
+		}
+		if diags.HasError() {
+			return types.ListNull(elemType), diags
+		}
+
+		elem, d := types.ObjectValue(fieldAttrTypes, map[string]attr.Value{
+			"id":                f.ID,
+			"name":              types.StringValue(f.Name),
+			"type":              types.StringValue(f.Type),
+			"required":          types.BoolValue(f.Required),
+			"doc":               types.StringPointerValue(f.Doc),
+			"initial_default":   types.StringPointerValue(f.InitialDefault),
+			"list_properties":   listProperties,
+			"map_properties":    mapProperties,
+			"struct_properties": structProperties,
+		})
+		diags.Append(d...)
+		if diags.HasError() {
+			return types.ListNull(elemType), diags
+		}
+		elems = append(elems, elem)
+	}
+
+	list, d := types.ListValue(elemType, elems)
+	diags.Append(d...)
+
+	return list, diags
+}
+
+// keepUnsetFieldValues clears the docs and initial defaults of the fields
+// read from the catalog that are null in the field of prior with the same
+// name, or that have no such field, as for imported tables. Values the
+// catalog or other clients fill in then aren't recorded as configuration, in
+// the same way as the comment and owner attributes. Iceberg doesn't tell an
+// empty doc from a missing one, so docs that are empty in prior stay empty.
+// Nested struct fields are matched the same way.
+func keepUnsetFieldValues(prior, fields []icebergTableSchemaField) {
+	priorByName := make(map[string]icebergTableSchemaField, len(prior))
+	for _, f := range prior {
+		priorByName[f.Name] = f
+	}
+
+	for i := range fields {
+		p, ok := priorByName[fields[i].Name]
+		switch {
+		case !ok || p.Doc == nil:
+			fields[i].Doc = nil
+		case fields[i].Doc == nil && *p.Doc == "":
+			empty := ""
+			fields[i].Doc = &empty
+		}
+		if !ok || p.InitialDefault == nil {
+			fields[i].InitialDefault = nil
+		}
+		if fields[i].StructProperties != nil {
+			var priorNested []icebergTableSchemaField
+			if ok && p.StructProperties != nil {
+				priorNested = p.StructProperties.Fields
+			}
+			keepUnsetFieldValues(priorNested, fields[i].StructProperties.Fields)
+		}
+	}
+}
+
+// fillUnsetFieldValues sets the null docs and initial defaults of fields to
+// those of the field with the same ID in current, so that committing fields
+// as a new schema leaves the values the configuration doesn't set as they
+// are in the catalog. Fields without an ID are new and left alone.
+func fillUnsetFieldValues(fields []icebergTableSchemaField, current *iceberg.Schema) error {
+	var s icebergTableSchema
+	if err := s.FromIceberg(current); err != nil {
+		return err
+	}
+	byID := make(map[int64]icebergTableSchemaField)
+	indexFieldsByID(s.Fields, byID)
+	fillUnsetFieldValuesByID(fields, byID)
+
+	return nil
+}
+
+func indexFieldsByID(fields []icebergTableSchemaField, out map[int64]icebergTableSchemaField) {
+	for _, f := range fields {
+		out[f.ID.ValueInt64()] = f
+		if f.StructProperties != nil {
+			indexFieldsByID(f.StructProperties.Fields, out)
+		}
+	}
+}
+
+func fillUnsetFieldValuesByID(fields []icebergTableSchemaField, current map[int64]icebergTableSchemaField) {
+	for i := range fields {
+		if fields[i].ID.IsNull() || fields[i].ID.IsUnknown() {
+			continue
+		}
+		if c, ok := current[fields[i].ID.ValueInt64()]; ok {
+			if fields[i].Doc == nil {
+				fields[i].Doc = c.Doc
+			}
+			if fields[i].InitialDefault == nil {
+				fields[i].InitialDefault = c.InitialDefault
+			}
+		}
+		if fields[i].StructProperties != nil {
+			fillUnsetFieldValuesByID(fields[i].StructProperties.Fields, current)
+		}
+	}
+}
+
+// fieldDocsEqual reports whether two field docs are the same in the catalog,
+// where an empty doc is the same as none.
+func fieldDocsEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return (a == nil || *a == "") && (b == nil || *b == "")
+	}
+
+	return *a == *b
+}
+
 type icebergTablePartitionSpec struct {
 	SpecID types.Int64                  `tfsdk:"spec_id" json:"spec-id"`
 	Fields []icebergTablePartitionField `tfsdk:"fields" json:"fields"`
@@ -325,24 +581,36 @@ type icebergTableSchemaField struct {
 	Type             string                                   `tfsdk:"type" json:"-"`
 	Required         bool                                     `tfsdk:"required" json:"required"`
 	Doc              *string                                  `tfsdk:"doc" json:"doc,omitempty"`
+	InitialDefault   *string                                  `tfsdk:"initial_default" json:"-"`
 	ListProperties   *icebergTableSchemaFieldListProperties   `tfsdk:"list_properties" json:"-"`
 	MapProperties    *icebergTableSchemaFieldMapProperties    `tfsdk:"map_properties" json:"-"`
 	StructProperties *icebergTableSchemaFieldStructProperties `tfsdk:"struct_properties" json:"-"`
 }
 
+// AttrTypes returns the attribute types of a field that can have depth
+// levels of structs nested in it. The map is shared and must not be modified.
 func (icebergTableSchemaField) AttrTypes(depth int) map[string]attr.Type {
+	return schemaFieldAttrTypes[depth]
+}
+
+// newAttrTypes builds the attribute types of a field whose struct fields have
+// the attribute types nested, or can't have struct fields if nested is nil.
+func (icebergTableSchemaField) newAttrTypes(nested map[string]attr.Type) map[string]attr.Type {
 	res := map[string]attr.Type{
 		"id":              types.Int64Type,
 		"name":            types.StringType,
 		"type":            types.StringType,
 		"required":        types.BoolType,
 		"doc":             types.StringType,
-		"list_properties": types.ObjectType{AttrTypes: icebergTableSchemaFieldListProperties{}.AttrTypes()},
-		"map_properties":  types.ObjectType{AttrTypes: icebergTableSchemaFieldMapProperties{}.AttrTypes()},
+		"initial_default": types.StringType,
+		"list_properties": types.ObjectType{AttrTypes: schemaListPropertiesAttrTypes},
+		"map_properties":  types.ObjectType{AttrTypes: schemaMapPropertiesAttrTypes},
 	}
 
-	if depth > 0 {
-		res["struct_properties"] = types.ObjectType{AttrTypes: icebergTableSchemaFieldStructProperties{}.AttrTypes(depth - 1)}
+	if nested != nil {
+		res["struct_properties"] = types.ObjectType{AttrTypes: map[string]attr.Type{
+			"fields": types.ListType{ElemType: types.ObjectType{AttrTypes: nested}},
+		}}
 	} else {
 		// At max depth, we still need the attribute defined but it won't have fields
 		res["struct_properties"] = types.ObjectType{AttrTypes: map[string]attr.Type{
@@ -354,11 +622,11 @@ func (icebergTableSchemaField) AttrTypes(depth int) map[string]attr.Type {
 }
 
 func (f icebergTableSchemaField) MarshalJSON() ([]byte, error) {
-	return marshalFieldJSON(f.ID, f.Name, f.Type, f.Required, f.Doc, f.ListProperties, f.MapProperties, f.StructProperties)
+	return marshalFieldJSON(f.ID, f.Name, f.Type, f.Required, f.Doc, f.InitialDefault, f.ListProperties, f.MapProperties, f.StructProperties)
 }
 
 func (f *icebergTableSchemaField) UnmarshalJSON(b []byte) error {
-	return unmarshalFieldJSON(b, &f.ID, &f.Name, &f.Type, &f.Required, &f.Doc, &f.ListProperties, &f.MapProperties, &f.StructProperties)
+	return unmarshalFieldJSON(b, &f.ID, &f.Name, &f.Type, &f.Required, &f.Doc, &f.InitialDefault, &f.ListProperties, &f.MapProperties, &f.StructProperties)
 }
 
 type icebergTableSchemaFieldListProperties struct {
@@ -512,13 +780,14 @@ func (s *icebergTableSchemaFieldStructProperties) UnmarshalJSON(b []byte) error
 
 // Helpers for shared logic
 
-func marshalFieldJSON(id types.Int64, name, typeStr string, required bool, doc *string, listProps, mapProps, structProps interface{}) ([]byte, error) {
+func marshalFieldJSON(id types.Int64, name, typeStr string, required bool, doc, initialDefault *string, listProps, mapProps, structProps interface{}) ([]byte, error) {
 	type Field struct {
-		ID       int64       `json:"id"`
-		Name     string      `json:"name"`
-		Type     interface{} `json:"type"`
-		Required bool        `json:"required"`
-		Doc      *string     `json:"doc,omitempty"`
+		ID             int64           `json:"id"`
+		Name           string          `json:"name"`
+		Type           interface{}     `json:"type"`
+		Required       bool            `json:"required"`
+		Doc            *string         `json:"doc,omitempty"`
+		InitialDefault json.RawMessage `json:"initial-default,omitempty"`
 	}
 
 	var idVal int64
@@ -533,6 +802,14 @@ func marshalFieldJSON(id types.Int64, name, typeStr string, required bool, doc *
 		Doc:      doc,
 	}
 
+	if initialDefault != nil {
+		value, err := initialDefaultJSON(typeStr, *initialDefault)
+		if err != nil {
+			return nil, err
+		}
+		f.InitialDefault = value
+	}
+
 	switch typeStr {
 	case "list":
 		f.Type = listProps
@@ -547,13 +824,14 @@ func marshalFieldJSON(id types.Int64, name, typeStr string, required bool, doc *
 	return json.Marshal(f)
 }
 
-func unmarshalFieldJSON(b []byte, id *types.Int64, name, typeStr *string, required *bool, doc **string, listProps, mapProps, structProps interface{}) error {
+func unmarshalFieldJSON(b []byte, id *types.Int64, name, typeStr *string, required *bool, doc, initialDefault **string, listProps, mapProps, structProps interface{}) error {
 	var raw struct {
-		ID       int64           `json:"id"`
-		Name     string          `json:"name"`
-		Type     json.RawMessage `json:"type"`
-		Required bool            `json:"required"`
-		Doc      *string         `json:"doc"`
+		ID             int64           `json:"id"`
+		Name           string          `json:"name"`
+		Type           json.RawMessage `json:"type"`
+		Required       bool            `json:"required"`
+		Doc            *string         `json:"doc"`
+		InitialDefault json.RawMessage `json:"initial-default"`
 	}
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return err
@@ -562,6 +840,16 @@ func unmarshalFieldJSON(b []byte, id *types.Int64, name, typeStr *string, requir
 	*name = raw.Name
 	*required = raw.Required
 	*doc = raw.Doc
+	*initialDefault = nil
+	if len(raw.InitialDefault) > 0 && string(raw.InitialDefault) != "null" {
+		value := string(raw.InitialDefault)
+		if raw.InitialDefault[0] == '"' {
+			if err := json.Unmarshal(raw.InitialDefault, &value); err != nil {
+				return err
+			}
+		}
+		*initialDefault = &value
+	}
 
 	if len(raw.Type) > 0 && raw.Type[0] == '"' {
 		var s string
@@ -590,3 +878,33 @@ func unmarshalFieldJSON(b []byte, id *types.Int64, name, typeStr *string, requir
 
 	return nil
 }
+
+// initialDefaultJSON encodes the initial_default of a field of the given type
+// as its JSON single-value serialization: booleans and integer and floating
+// point numbers as JSON literals, everything else as a string.
+func initialDefaultJSON(typeStr, value string) (json.RawMessage, error) {
+	var err error
+	switch typeStr {
+	case "boolean":
+		if value != "true" && value != "false" {
+			err = strconv.ErrSyntax
+		}
+	case "int":
+		_, err = strconv.ParseInt(value, 10, 32)
+	case "long":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "float":
+		_, err = strconv.ParseFloat(value, 32)
+	case "double":
+		_, err = strconv.ParseFloat(value, 64)
+	case "list", "map", "struct":
+		return nil, fmt.Errorf("initial_default is not supported for %s fields", typeStr)
+	default:
+		return json.Marshal(value)
+	}
+	if err != nil || !json.Valid([]byte(value)) {
+		return nil, fmt.Errorf("initial_default %q is not a valid %s", value, typeStr)
+	}
+
+	return json.RawMessage(value), nil
+}
@@ -0,0 +1,436 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The goldens pin the exact shape the provider produces for schemas. Changing
+// them is a breaking change for anything consuming the state, so they are only
+// rewritten when running `go test ./internal/provider -run Golden -update`.
+var updateGoldens = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// goldenSchema contains every Iceberg type the provider supports, including
+// nested list, map and struct fields.
+func goldenSchema() *iceberg.Schema {
+	doc := "the event id"
+
+	return iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "boolean_col", Type: iceberg.PrimitiveTypes.Bool, Required: true},
+		iceberg.NestedField{ID: 2, Name: "int_col", Type: iceberg.PrimitiveTypes.Int32},
+		iceberg.NestedField{ID: 3, Name: "long_col", Type: iceberg.PrimitiveTypes.Int64, Doc: doc},
+		iceberg.NestedField{ID: 4, Name: "float_col", Type: iceberg.PrimitiveTypes.Float32},
+		iceberg.NestedField{ID: 5, Name: "double_col", Type: iceberg.PrimitiveTypes.Float64},
+		iceberg.NestedField{ID: 6, Name: "decimal_col", Type: iceberg.DecimalTypeOf(10, 2)},
+		iceberg.NestedField{ID: 7, Name: "date_col", Type: iceberg.PrimitiveTypes.Date},
+		iceberg.NestedField{ID: 8, Name: "time_col", Type: iceberg.PrimitiveTypes.Time},
+		iceberg.NestedField{ID: 9, Name: "timestamp_col", Type: iceberg.PrimitiveTypes.Timestamp},
+		iceberg.NestedField{ID: 10, Name: "timestamptz_col", Type: iceberg.PrimitiveTypes.TimestampTz},
+		iceberg.NestedField{ID: 11, Name: "timestamp_ns_col", Type: iceberg.PrimitiveTypes.TimestampNs},
+		iceberg.NestedField{ID: 12, Name: "timestamptz_ns_col", Type: iceberg.PrimitiveTypes.TimestampTzNs},
+		iceberg.NestedField{ID: 13, Name: "string_col", Type: iceberg.PrimitiveTypes.String, InitialDefault: "unknown"},
+		iceberg.NestedField{ID: 14, Name: "uuid_col", Type: iceberg.PrimitiveTypes.UUID},
+		iceberg.NestedField{ID: 15, Name: "fixed_col", Type: iceberg.FixedTypeOf(16)},
+		iceberg.NestedField{ID: 16, Name: "binary_col", Type: iceberg.PrimitiveTypes.Binary},
+		iceberg.NestedField{ID: 17, Name: "list_col", Type: &iceberg.ListType{
+			ElementID: 20, Element: iceberg.PrimitiveTypes.String, ElementRequired: true,
+		}},
+		iceberg.NestedField{ID: 18, Name: "map_col", Type: &iceberg.MapType{
+			KeyID: 21, KeyType: iceberg.PrimitiveTypes.String,
+			ValueID: 22, ValueType: iceberg.PrimitiveTypes.Int64, ValueRequired: false,
+		}},
+		iceberg.NestedField{ID: 19, Name: "struct_col", Type: &iceberg.StructType{
+			FieldList: []iceberg.NestedField{
+				{ID: 23, Name: "nested_string", Type: iceberg.PrimitiveTypes.String, Required: true},
+				{ID: 24, Name: "nested_struct", Type: &iceberg.StructType{
+					FieldList: []iceberg.NestedField{
+						{ID: 25, Name: "leaf", Type: iceberg.PrimitiveTypes.Date},
+					},
+				}},
+			},
+		}},
+	)
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *updateGoldens {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden file, run the test with -update to create it")
+	assert.Equal(t, string(want), string(got), "output differs from %s, run the test with -update if the change is intended", path)
+}
+
+func TestSchemaConversionGolden(t *testing.T) {
+	var s icebergTableSchema
+	require.NoError(t, s.FromIceberg(goldenSchema()))
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	require.NoError(t, err)
+	assertGolden(t, "schema_conversion.json", append(b, '\n'))
+
+	obj, diags := schemaObjectValue(context.Background(), goldenSchema())
+	require.False(t, diags.HasError(), "%v", diags)
+	assertGolden(t, "schema_object_value.golden", []byte(obj.String()+"\n"))
+}
+
+func TestSchemaConversionRoundTrip(t *testing.T) {
+	original := goldenSchema()
+
+	var s icebergTableSchema
+	require.NoError(t, s.FromIceberg(original))
+
+	converted, err := s.ToIceberg()
+	require.NoError(t, err)
+
+	require.Len(t, converted.Fields(), len(original.Fields()))
+	for i, want := range original.Fields() {
+		got := converted.Fields()[i]
+		assert.Equal(t, want.ID, got.ID, want.Name)
+		assert.Equal(t, want.Name, got.Name)
+		assert.Equal(t, want.Required, got.Required, want.Name)
+		assert.Equal(t, want.Doc, got.Doc, want.Name)
+		assert.Equal(t, want.InitialDefault, got.InitialDefault, want.Name)
+		assert.True(t, want.Type.Equals(got.Type), "%s: want %s, got %s", want.Name, want.Type, got.Type)
+	}
+}
+
+// Field docs may carry structured data, such as JSON lineage hints, so they
+// must survive the conversions byte for byte.
+func TestSchemaFieldDocRoundTrip(t *testing.T) {
+	docs := []string{
+		"{\"lineage\": {\"source\": \"orders.id\", \"write_order\": 1}}",
+		"first line\nsecond line\r\n\ttabbed",
+		"  leading and trailing whitespace  ",
+		"unicode: ünïcödé, 日本語, emoji 🧊, escapes <&> \\u0041",
+		" ",
+	}
+
+	fields := make([]iceberg.NestedField, 0, len(docs))
+	for i, doc := range docs {
+		fields = append(fields, iceberg.NestedField{ID: i + 1, Name: fmt.Sprintf("f%d", i), Type: iceberg.PrimitiveTypes.String, Doc: doc})
+	}
+	original := iceberg.NewSchema(0, fields...)
+
+	obj, diags := schemaObjectValue(context.Background(), original)
+	require.False(t, diags.HasError(), "%v", diags)
+	var s icebergTableSchema
+	require.False(t, obj.As(context.Background(), &s, basetypes.ObjectAsOptions{}).HasError())
+
+	for i, doc := range docs {
+		require.NotNil(t, s.Fields[i].Doc, "field %d", i)
+		assert.Equal(t, doc, *s.Fields[i].Doc, "field %d", i)
+	}
+
+	converted, err := s.ToIceberg()
+	require.NoError(t, err)
+	for i, doc := range docs {
+		assert.Equal(t, doc, converted.Fields()[i].Doc, "field %d", i)
+	}
+}
+
+func TestSyncedSchemaEmptyDocs(t *testing.T) {
+	ctx := context.Background()
+	empty, set := "", "the id"
+
+	prior := []icebergTableSchemaField{
+		{Name: "id", Type: "long", Doc: &empty},
+		{Name: "name", Type: "string"},
+		{Name: "addr", Type: "struct", StructProperties: &icebergTableSchemaFieldStructProperties{
+			Fields: []icebergTableSchemaField{{Name: "zip", Type: "int", Doc: &empty}},
+		}},
+		{Name: "note", Type: "string", Doc: &empty},
+	}
+	icebergSchema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64},
+		iceberg.NestedField{ID: 2, Name: "name", Type: iceberg.PrimitiveTypes.String},
+		iceberg.NestedField{ID: 3, Name: "addr", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 5, Name: "zip", Type: iceberg.PrimitiveTypes.Int32},
+		}}},
+		iceberg.NestedField{ID: 4, Name: "note", Type: iceberg.PrimitiveTypes.String, Doc: set},
+	)
+
+	obj, diags := syncedSchemaObjectValue(ctx, icebergSchema, prior)
+	require.False(t, diags.HasError(), "%v", diags)
+	var s icebergTableSchema
+	require.False(t, obj.As(ctx, &s, basetypes.ObjectAsOptions{}).HasError())
+
+	assert.Equal(t, &empty, s.Fields[0].Doc, "an empty doc stays empty")
+	assert.Nil(t, s.Fields[1].Doc, "a missing doc stays null")
+	assert.Equal(t, &empty, s.Fields[2].StructProperties.Fields[0].Doc)
+	assert.Equal(t, &set, s.Fields[3].Doc, "a doc set by others is taken from the catalog")
+
+	obj, diags = schemaObjectValue(ctx, icebergSchema)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.False(t, obj.As(ctx, &s, basetypes.ObjectAsOptions{}).HasError())
+	assert.Nil(t, s.Fields[0].Doc, "without a prior schema, empty docs are null")
+
+	assert.True(t, fieldDocsEqual(nil, &empty))
+	assert.True(t, fieldDocsEqual(&empty, nil))
+	assert.False(t, fieldDocsEqual(&set, nil))
+	assert.False(t, fieldDocsEqual(&set, &empty))
+}
+
+func TestSyncedSchemaUnsetValues(t *testing.T) {
+	ctx := context.Background()
+	doc, initialDefault := "the id", "0"
+
+	icebergSchema := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Doc: "Filled in by the catalog"},
+		iceberg.NestedField{ID: 2, Name: "count", Type: iceberg.PrimitiveTypes.Int32, InitialDefault: int32(0)},
+		iceberg.NestedField{ID: 3, Name: "addr", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 4, Name: "zip", Type: iceberg.PrimitiveTypes.Int32, Doc: "Postal code"},
+		}}},
+		iceberg.NestedField{ID: 5, Name: "note", Type: iceberg.PrimitiveTypes.String, Doc: "A note"},
+	)
+
+	fieldsOf := func(prior []icebergTableSchemaField) []icebergTableSchemaField {
+		obj, diags := syncedSchemaObjectValue(ctx, icebergSchema, prior)
+		require.False(t, diags.HasError(), "%v", diags)
+		var s icebergTableSchema
+		require.False(t, obj.As(ctx, &s, basetypes.ObjectAsOptions{}).HasError())
+
+		return s.Fields
+	}
+
+	fields := fieldsOf([]icebergTableSchemaField{
+		{Name: "id", Type: "long"},
+		{Name: "count", Type: "int"},
+		{Name: "addr", Type: "struct", StructProperties: &icebergTableSchemaFieldStructProperties{
+			Fields: []icebergTableSchemaField{{Name: "zip", Type: "int"}},
+		}},
+		{Name: "note", Type: "string", Doc: &doc},
+	})
+	assert.Nil(t, fields[0].Doc, "a doc the configuration doesn't set stays null")
+	assert.Nil(t, fields[1].InitialDefault, "an initial default the configuration doesn't set stays null")
+	assert.Nil(t, fields[2].StructProperties.Fields[0].Doc)
+	require.NotNil(t, fields[3].Doc)
+	assert.Equal(t, "A note", *fields[3].Doc, "a configured doc is taken from the catalog")
+
+	fields = fieldsOf([]icebergTableSchemaField{
+		{Name: "count", Type: "int", InitialDefault: &initialDefault},
+	})
+	require.NotNil(t, fields[1].InitialDefault)
+	assert.Equal(t, "0", *fields[1].InitialDefault)
+
+	for _, f := range fieldsOf(nil) {
+		assert.Nil(t, f.Doc, "imported fields have no docs: %s", f.Name)
+		assert.Nil(t, f.InitialDefault, "imported fields have no initial defaults: %s", f.Name)
+	}
+
+	obj, diags := schemaObjectValue(ctx, icebergSchema)
+	require.False(t, diags.HasError(), "%v", diags)
+	var s icebergTableSchema
+	require.False(t, obj.As(ctx, &s, basetypes.ObjectAsOptions{}).HasError())
+	require.NotNil(t, s.Fields[0].Doc)
+	assert.Equal(t, "Filled in by the catalog", *s.Fields[0].Doc, "schemaObjectValue has every value of the catalog")
+}
+
+func TestFillUnsetFieldValues(t *testing.T) {
+	current := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Doc: "The ID"},
+		iceberg.NestedField{ID: 2, Name: "count", Type: iceberg.PrimitiveTypes.Int32, InitialDefault: int32(7)},
+		iceberg.NestedField{ID: 3, Name: "addr", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 4, Name: "zip", Type: iceberg.PrimitiveTypes.Int32, Doc: "Postal code"},
+		}}},
+	)
+	empty, set := "", "Row count"
+	fields := []icebergTableSchemaField{
+		{ID: types.Int64Value(1), Name: "id", Type: "long", Doc: &empty},
+		{ID: types.Int64Value(2), Name: "count", Type: "int", Doc: &set},
+		{ID: types.Int64Value(3), Name: "addr", Type: "struct", StructProperties: &icebergTableSchemaFieldStructProperties{
+			Fields: []icebergTableSchemaField{{ID: types.Int64Value(4), Name: "zip", Type: "int"}},
+		}},
+		{ID: types.Int64Null(), Name: "new", Type: "string"},
+	}
+
+	require.NoError(t, fillUnsetFieldValues(fields, current))
+	assert.Equal(t, &empty, fields[0].Doc, "a configured empty doc removes the catalog's")
+	assert.Equal(t, &set, fields[1].Doc)
+	require.NotNil(t, fields[1].InitialDefault)
+	assert.Equal(t, "7", *fields[1].InitialDefault)
+	require.NotNil(t, fields[2].StructProperties.Fields[0].Doc)
+	assert.Equal(t, "Postal code", *fields[2].StructProperties.Fields[0].Doc)
+	assert.Nil(t, fields[3].Doc)
+}
+
+func TestCalculateSchemaUpdatesUnsetValues(t *testing.T) {
+	ctx := context.Background()
+	current := iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true, Doc: "Filled in by the catalog"},
+	)
+	metadata, err := mockTableMetadataWithSchema("db", "events", nil, current)
+	require.NoError(t, err)
+	tbl := table.New(table.Identifier{"db", "events"}, metadata, metadata.Location()+"/metadata/00000.metadata.json", nil, nil)
+
+	modelWith := func(fields ...icebergTableSchemaField) *icebergTableResourceModel {
+		obj, diags := types.ObjectValueFrom(ctx, schemaAttrTypes, icebergTableSchema{ID: types.Int64Value(0), Fields: fields})
+		require.False(t, diags.HasError(), "%v", diags)
+
+		return &icebergTableResourceModel{Schema: obj}
+	}
+	state := modelWith(icebergTableSchemaField{ID: types.Int64Value(1), Name: "id", Type: "long", Required: true})
+	r := &icebergTableResource{}
+
+	var diags diag.Diagnostics
+	assert.Empty(t, r.calculateSchemaUpdates(ctx, state, state, tbl, &diags), "an unset doc leaves the catalog's alone")
+	require.False(t, diags.HasError(), "%v", diags)
+
+	empty := ""
+	plan := modelWith(icebergTableSchemaField{ID: types.Int64Value(1), Name: "id", Type: "long", Required: true, Doc: &empty})
+	updates := r.calculateSchemaUpdates(ctx, plan, state, tbl, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Len(t, updates, 2, "an empty doc removes the catalog's")
+
+	plan = modelWith(
+		icebergTableSchemaField{ID: types.Int64Value(1), Name: "id", Type: "long", Required: true},
+		icebergTableSchemaField{ID: types.Int64Null(), Name: "name", Type: "string"},
+	)
+	updates = r.calculateSchemaUpdates(ctx, plan, state, tbl, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Len(t, updates, 2)
+	added, err := json.Marshal(updates[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(added), "Filled in by the catalog", "the new schema keeps the catalog's doc")
+}
+
+// largeSchema generates a schema with the given number of top-level columns,
+// every tenth of them a struct nested three levels deep and the others
+// cycling through primitive, list and map types.
+func largeSchema(columns int) *iceberg.Schema {
+	id := 0
+	nextID := func() int {
+		id++
+
+		return id
+	}
+
+	var nested func(level int) iceberg.Type
+	nested = func(level int) iceberg.Type {
+		fields := []iceberg.NestedField{
+			{ID: nextID(), Name: "name", Type: iceberg.PrimitiveTypes.String, Required: true, Doc: "a nested column"},
+			{ID: nextID(), Name: "amount", Type: iceberg.DecimalTypeOf(18, 2)},
+		}
+		if level < 3 {
+			fields = append(fields, iceberg.NestedField{ID: nextID(), Name: "child", Type: nested(level + 1)})
+		}
+
+		return &iceberg.StructType{FieldList: fields}
+	}
+
+	fields := make([]iceberg.NestedField, 0, columns)
+	for i := range columns {
+		f := iceberg.NestedField{ID: nextID(), Name: fmt.Sprintf("col_%d", i)}
+		switch {
+		case i%10 == 9:
+			f.Type = nested(0)
+		case i%10 == 8:
+			f.Type = &iceberg.ListType{ElementID: nextID(), Element: iceberg.PrimitiveTypes.Int64, ElementRequired: true}
+		case i%10 == 7:
+			f.Type = &iceberg.MapType{KeyID: nextID(), KeyType: iceberg.PrimitiveTypes.String, ValueID: nextID(), ValueType: iceberg.PrimitiveTypes.Float64}
+		case i%2 == 0:
+			f.Type = iceberg.PrimitiveTypes.String
+			f.Doc = "a column"
+		default:
+			f.Type = iceberg.PrimitiveTypes.Timestamp
+			f.Required = true
+		}
+		fields = append(fields, f)
+	}
+
+	return iceberg.NewSchema(0, fields...)
+}
+
+func TestSchemaObjectValueLargeSchema(t *testing.T) {
+	obj, diags := schemaObjectValue(context.Background(), largeSchema(1500))
+	require.False(t, diags.HasError(), "%v", diags)
+
+	fields := obj.Attributes()["fields"].(basetypes.ListValue).Elements()
+	require.Len(t, fields, 1500)
+	nested := fields[9].(basetypes.ObjectValue)
+	for range 3 {
+		structProperties := nested.Attributes()["struct_properties"].(basetypes.ObjectValue)
+		require.False(t, structProperties.IsNull())
+		nested = structProperties.Attributes()["fields"].(basetypes.ListValue).Elements()[2].(basetypes.ObjectValue)
+	}
+	assert.Equal(t, `"child"`, nested.Attributes()["name"].String())
+}
+
+func TestSchemaObjectValueErrors(t *testing.T) {
+	tooDeep := iceberg.Type(iceberg.PrimitiveTypes.String)
+	for i := range schemaMaxDepth + 1 {
+		tooDeep = &iceberg.StructType{FieldList: []iceberg.NestedField{{ID: 100 + i, Name: fmt.Sprintf("level_%d", i), Type: tooDeep}}}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		schema *iceberg.Schema
+		want   string
+	}{
+		{
+			name:   "nested too deep",
+			schema: iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "root", Type: tooDeep}),
+			want:   "nests structs deeper than 4 levels",
+		},
+		{
+			// Converting the field panics, which must not crash the provider.
+			name:   "field without type",
+			schema: iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "broken"}),
+			want:   "unexpected error converting the schema",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			obj, diags := schemaObjectValue(context.Background(), tc.schema)
+			require.Len(t, diags.Errors(), 1)
+			assert.Contains(t, diags.Errors()[0].Detail(), tc.want)
+			assert.True(t, obj.IsNull())
+		})
+	}
+}
+
+// BenchmarkSchemaObjectValue converts a 1,500 column schema, which every
+// refresh of such a table does.
+func BenchmarkSchemaObjectValue(b *testing.B) {
+	ctx := context.Background()
+	s := largeSchema(1500)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, diags := schemaObjectValue(ctx, s); diags.HasError() {
+			b.Fatal(diags)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var errStageCreateUnsupported = errors.New("the catalog doesn't support staged table creation")
+
+type stageCreateTableRequest struct {
+	Name          string                 `json:"name"`
+	Schema        *iceberg.Schema        `json:"schema"`
+	PartitionSpec *iceberg.PartitionSpec `json:"partition-spec,omitempty"`
+	WriteOrder    *table.SortOrder       `json:"write-order,omitempty"`
+	StageCreate   bool                   `json:"stage-create"`
+	Properties    map[string]string      `json:"properties,omitempty"`
+}
+
+// validateCreate runs a staged create of the planned table when
+// validate_on_plan is set, so the catalog rejects an invalid schema, partition
+// spec, sort order or property at plan time. A staged create isn't committed,
+// so nothing is left behind in the catalog.
+func (r *icebergTableResource) validateCreate(ctx context.Context, data *icebergTableResourceModel, diags *diag.Diagnostics) {
+	if r.provider == nil || !r.provider.validateOnPlan {
+		return
+	}
+	if r.provider.readOnly {
+		tflog.Debug(ctx, "Skipping plan-time validation of table creation in read-only mode")
+
+		return
+	}
+	// The catalog assigns the IDs of a new table's fields, so IDs not set in
+	// the configuration don't prevent validation.
+	data.Schema = withAssignedFieldIDs(ctx, data.Schema, 0)
+	if !fullyKnown(ctx, data.Namespace, data.Name, data.Schema, data.PartitionSpec, data.SortOrder, data.UserProperties, data.SensitiveUserProperties, data.Comment, data.Owner) {
+		tflog.Debug(ctx, "Skipping plan-time validation of table creation, the planned table has unknown values")
+
+		return
+	}
+
+	args := tableCreateArgsFromModel(ctx, data, diags)
+	if diags.HasError() {
+		return
+	}
+	args.ident = r.provider.prefixTable(args.ident)
+
+	ctx = withResourceType(ctx, "iceberg_table")
+	r.ConfigureCatalog(ctx, diags)
+	if diags.HasError() {
+		return
+	}
+
+	committed, err := r.provider.stageCreateTable(ctx, args)
+	switch {
+	case errors.Is(err, errStageCreateUnsupported):
+		tflog.Debug(ctx, "Skipping plan-time validation of table creation: "+err.Error())
+	case errors.Is(err, catalog.ErrNoSuchNamespace):
+		// The namespace is most likely created by the same apply.
+		tflog.Debug(ctx, "Skipping plan-time validation of table creation, the namespace doesn't exist yet")
+	case err != nil:
+		diags.AddError(
+			"Catalog rejected table "+encodeIdentifier(args.ident),
+			"Creating the table would fail: "+err.Error(),
+		)
+	}
+
+	if committed {
+		// The catalog ignored stage-create and created the table. Drop it again
+		// so the plan leaves the catalog as it was.
+		tflog.Warn(ctx, "The catalog doesn't honor stage-create, dropping the table created during validation")
+		if err := r.catalog.DropTable(ctx, args.ident); err != nil {
+			diags.AddError("failed to drop table created during plan-time validation", err.Error())
+		}
+	}
+}
+
+// stageCreateTable sends a create request with stage-create set. The catalog
+// client doesn't expose staged creates without committing them, so the
+// request is built here. committed reports whether the catalog created the
+// table anyway, which it signals by returning a metadata location.
+func (p *icebergProvider) stageCreateTable(ctx context.Context, args *tableCreateArgs) (committed bool, err error) {
+	writeOrder := args.sortOrder
+	if writeOrder == nil {
+		writeOrder = &table.UnsortedSortOrder
+	}
+	body, err := json.Marshal(stageCreateTableRequest{
+		Name:          catalog.TableNameFromIdent(args.ident),
+		Schema:        args.schema,
+		PartitionSpec: args.spec,
+		WriteOrder:    writeOrder,
+		StageCreate:   true,
+		Properties:    args.properties,
+	})
+	if err != nil {
+		return false, fmt.Errorf("marshal request body: %w", err)
+	}
+
+	status, respBody, err := p.catalogRequest(ctx, "iceberg_table", http.MethodPost,
+		[]string{"namespaces", strings.Join(catalog.NamespaceFromIdent(args.ident), "\x1F"), "tables"}, body)
+	if err != nil {
+		return false, err
+	}
+
+	if status == http.StatusOK {
+		var loaded struct {
+			MetadataLocation string `json:"metadata-location"`
+		}
+		if err := json.Unmarshal(respBody, &loaded); err != nil {
+			return false, fmt.Errorf("decode response: %w", err)
+		}
+
+		return loaded.MetadataLocation != "", nil
+	}
+
+	var errResp catalogErrorResponse
+	_ = json.Unmarshal(respBody, &errResp)
+
+	switch {
+	case status == http.StatusNotImplemented || errResp.Error.Type == "UnsupportedOperationException":
+		return false, errStageCreateUnsupported
+	case status == http.StatusNotFound:
+		return false, fmt.Errorf("%w: %s", catalog.ErrNoSuchNamespace, errResp.Error.Message)
+	case errResp.Error.Message != "":
+		return false, fmt.Errorf("%s: %s", errResp.Error.Type, errResp.Error.Message)
+	default:
+		return false, fmt.Errorf("unexpected status %d %s", status, http.StatusText(status))
+	}
+}
+
+// fullyKnown reports whether none of values is or contains an unknown value.
+func fullyKnown(ctx context.Context, values ...attr.Value) bool {
+	for _, v := range values {
+		tfValue, err := v.ToTerraformValue(ctx)
+		if err != nil || !tfValue.IsFullyKnown() {
+			return false
+		}
+	}
+
+	return true
+}
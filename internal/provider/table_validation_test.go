@@ -0,0 +1,368 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func plannedTableModel(t *testing.T, namespace, name string) *icebergTableResourceModel {
+	t.Helper()
+
+	schema, diags := schemaObjectValue(context.Background(),
+		iceberg.NewSchema(0, iceberg.NestedField{ID: 1, Name: "id", Type: iceberg.PrimitiveTypes.Int64, Required: true}))
+	require.False(t, diags.HasError(), "%v", diags)
+
+	return &icebergTableResourceModel{
+		ID:                      types.StringUnknown(),
+		Namespace:               types.ListValueMust(types.StringType, []attr.Value{types.StringValue(namespace)}),
+		Name:                    types.StringValue(name),
+		Schema:                  schema,
+		PartitionSpec:           types.ObjectNull(icebergTablePartitionSpec{}.AttrTypes()),
+		SortOrder:               types.ObjectNull(icebergTableSortOrder{}.AttrTypes()),
+		UserProperties:          types.MapValueMust(types.StringType, map[string]attr.Value{"owner": types.StringValue("data")}),
+		SensitiveUserProperties: types.MapNull(types.StringType),
+	}
+}
+
+func TestTableValidateCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(m *mockRESTCatalog)
+		namespace string
+		wantError string
+	}{
+		{
+			name:      "accepted",
+			namespace: "db",
+		},
+		{
+			name:      "rejected",
+			setup:     func(m *mockRESTCatalog) { m.rejectCreates = "Unsupported format-version: 9" },
+			namespace: "db",
+			wantError: "Unsupported format-version: 9",
+		},
+		{
+			name:      "already exists",
+			setup:     func(m *mockRESTCatalog) { m.addTable("db", "events", nil) },
+			namespace: "db",
+			wantError: "AlreadyExistsException",
+		},
+		{
+			name:      "stage-create not implemented",
+			setup:     func(m *mockRESTCatalog) { m.noStageCreate = true; m.rejectCreates = "not checked" },
+			namespace: "db",
+		},
+		{
+			name:      "namespace created by the same apply",
+			namespace: "new_db",
+		},
+		{
+			name:      "stage-create ignored",
+			setup:     func(m *mockRESTCatalog) { m.ignoreStageCreate = true },
+			namespace: "db",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m := newMockRESTCatalog()
+			m.addTable("db", "other", nil)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+			server := m.start(t)
+
+			r := &icebergTableResource{provider: &icebergProvider{
+				catalogURI:     server.URL,
+				catalogType:    "rest",
+				validateOnPlan: true,
+				capabilities:   newCatalogCapabilities(),
+			}}
+
+			var diags diag.Diagnostics
+			r.validateCreate(ctx, plannedTableModel(t, tt.namespace, "events"), &diags)
+
+			if tt.wantError == "" {
+				require.False(t, diags.HasError(), "%v", diags)
+				// Validation never leaves a new table behind.
+				assert.Nil(t, m.tableProperties(tt.namespace, "events"))
+			} else {
+				require.Len(t, diags.Errors(), 1)
+				assert.Equal(t, "Catalog rejected table "+tt.namespace+".events", diags.Errors()[0].Summary())
+				assert.Contains(t, diags.Errors()[0].Detail(), tt.wantError)
+			}
+		})
+	}
+}
+
+func TestTableValidateCreateSkipped(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	m.rejectCreates = "must not be sent"
+	server := m.start(t)
+
+	p := &icebergProvider{catalogURI: server.URL, catalogType: "rest", capabilities: newCatalogCapabilities()}
+	r := &icebergTableResource{provider: p}
+
+	// Disabled.
+	var diags diag.Diagnostics
+	r.validateCreate(ctx, plannedTableModel(t, "db", "events"), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	// Unknown values in the plan.
+	p.validateOnPlan = true
+	data := plannedTableModel(t, "db", "events")
+	data.Name = types.StringUnknown()
+	r.validateCreate(ctx, data, &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	// Read-only mode.
+	p.readOnly = true
+	r.validateCreate(ctx, plannedTableModel(t, "db", "events"), &diags)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	assert.Empty(t, m.writes)
+}
+
+func TestAccIcebergTable_ValidateOnPlan(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	m.rejectCreates = "Cannot create table: unsupported property value"
+	server := m.start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri      = "%s"
+  validate_on_plan = true
+}
+
+resource "iceberg_table" "rejected" {
+  namespace = ["db"]
+  name      = "rejected"
+  schema = {
+    fields = [
+      {
+        id       = 1
+        name     = "id"
+        type     = "long"
+        required = true
+      }
+    ]
+  }
+}
+`, server.URL),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`unsupported property value`),
+			},
+		},
+	})
+}
+
+// objectValue returns a value of typ with attrs set and every other attribute
+// null.
+func objectValue(typ tftypes.Type, attrs map[string]tftypes.Value) tftypes.Value {
+	attrTypes := typ.(tftypes.Object).AttributeTypes
+	values := make(map[string]tftypes.Value, len(attrTypes))
+	for name, attrType := range attrTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	maps.Copy(values, attrs)
+
+	return tftypes.NewValue(typ, values)
+}
+
+func TestPlanUnknownIdentifier(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	m.rejectCreates = "must not be sent"
+	server := m.start(t)
+
+	provider, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	schemas, err := provider.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	configured, err := provider.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		Config: dynamicValue(t, objectValue(schemas.Provider.ValueType(), map[string]tftypes.Value{
+			"catalog_uri":      tftypes.NewValue(tftypes.String, server.URL),
+			"validate_on_plan": tftypes.NewValue(tftypes.Bool, true),
+		})),
+	})
+	require.NoError(t, err)
+	requireNoErrorDiagnostics(t, configured.Diagnostics)
+
+	stringList := tftypes.List{ElementType: tftypes.String}
+	stringMap := tftypes.Map{ElementType: tftypes.String}
+	tableType := schemas.ResourceSchemas["iceberg_table"].ValueType()
+	schemaType := tableType.(tftypes.Object).AttributeTypes["schema"]
+	fieldsType := schemaType.(tftypes.Object).AttributeTypes["fields"].(tftypes.List)
+	tableSchema := objectValue(schemaType, map[string]tftypes.Value{
+		"fields": tftypes.NewValue(fieldsType, []tftypes.Value{
+			objectValue(fieldsType.ElementType, map[string]tftypes.Value{
+				"name":     tftypes.NewValue(tftypes.String, "id"),
+				"type":     tftypes.NewValue(tftypes.String, "long"),
+				"required": tftypes.NewValue(tftypes.Bool, true),
+			}),
+		}),
+	})
+	// The namespace of a namespace resource that isn't created yet: its
+	// levels are known, but not its values.
+	unknownNamespace := tftypes.NewValue(stringList, tftypes.UnknownValue)
+	partialNamespace := tftypes.NewValue(stringList, []tftypes.Value{tftypes.NewValue(tftypes.String, tftypes.UnknownValue)})
+	prefixedNamespace := tftypes.NewValue(stringList, []tftypes.Value{
+		tftypes.NewValue(tftypes.String, "db"),
+		tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+
+	for _, tc := range []struct {
+		name     string
+		typeName string
+		attrs    map[string]tftypes.Value
+	}{
+		{
+			name:     "namespace with unknown name",
+			typeName: "iceberg_namespace",
+			attrs:    map[string]tftypes.Value{"name": unknownNamespace},
+		},
+		{
+			name:     "namespace with unknown level",
+			typeName: "iceberg_namespace",
+			attrs: map[string]tftypes.Value{
+				"name": prefixedNamespace,
+				"user_properties": tftypes.NewValue(stringMap, map[string]tftypes.Value{
+					"owner": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				}),
+			},
+		},
+		{
+			name:     "table in unknown namespace",
+			typeName: "iceberg_table",
+			attrs: map[string]tftypes.Value{
+				"namespace": unknownNamespace,
+				"name":      tftypes.NewValue(tftypes.String, "events"),
+				"schema":    tableSchema,
+			},
+		},
+		{
+			name:     "table in namespace with unknown level",
+			typeName: "iceberg_table",
+			attrs: map[string]tftypes.Value{
+				"namespace":         partialNamespace,
+				"name":              tftypes.NewValue(tftypes.String, "events"),
+				"schema":            tableSchema,
+				"required_features": tftypes.NewValue(stringList, []tftypes.Value{tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}),
+				"user_properties": tftypes.NewValue(stringMap, map[string]tftypes.Value{
+					"format-version": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				}),
+			},
+		},
+		{
+			name:     "table with unknown name",
+			typeName: "iceberg_table",
+			attrs: map[string]tftypes.Value{
+				"namespace": tftypes.NewValue(stringList, []tftypes.Value{tftypes.NewValue(tftypes.String, "db")}),
+				"name":      tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				"schema":    tableSchema,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resourceType := schemas.ResourceSchemas[tc.typeName].ValueType()
+			config := dynamicValue(t, objectValue(resourceType, tc.attrs))
+			planned, err := provider.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+				TypeName:         tc.typeName,
+				PriorState:       dynamicValue(t, tftypes.NewValue(resourceType, nil)),
+				ProposedNewState: config,
+				Config:           config,
+			})
+			require.NoError(t, err)
+			requireNoErrorDiagnostics(t, planned.Diagnostics)
+
+			plannedState, err := planned.PlannedState.Unmarshal(resourceType)
+			require.NoError(t, err)
+			var values map[string]tftypes.Value
+			require.NoError(t, plannedState.As(&values))
+			assert.False(t, values["id"].IsKnown(), "the ID is planned from unknown values")
+		})
+	}
+
+	assert.Empty(t, m.writes)
+}
+
+func TestAccIcebergTable_UnknownNamespace(t *testing.T) {
+	server := newMockRESTCatalog().start(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri      = "%s"
+  validate_on_plan = true
+}
+
+resource "terraform_data" "namespace" {
+  input = "db"
+}
+
+resource "iceberg_namespace" "db" {
+  name = [terraform_data.namespace.output]
+}
+
+resource "iceberg_table" "events" {
+  namespace = iceberg_namespace.db.name
+  name      = "events"
+  schema = {
+    fields = [
+      { name = "id", type = "long", required = true },
+    ]
+  }
+}
+`, server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.db", "id", "db"),
+					resource.TestCheckResourceAttr("iceberg_table.events", "id", "db.events"),
+					resource.TestCheckResourceAttr("iceberg_table.events", "namespace.0", "db"),
+				),
+			},
+		},
+	})
+}
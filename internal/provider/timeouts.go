@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// timeoutsModel is the timeouts block of resources whose operations wait
+// for the catalog.
+type timeoutsModel struct {
+	Delete types.String `tfsdk:"delete"`
+}
+
+// deleteTimeoutBlock returns a timeouts block with a delete timeout,
+// described by deleteDescription.
+func deleteTimeoutBlock(deleteDescription string) schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: "How long operations may wait for the catalog.",
+		Attributes: map[string]schema.Attribute{
+			"delete": schema.StringAttribute{
+				Description: deleteDescription,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// validateTimeoutsConfig rejects timeouts that aren't positive durations.
+func validateTimeoutsConfig(ctx context.Context, timeouts types.Object, diags *diag.Diagnostics) {
+	var data timeoutsModel
+	if !timeoutsValue(ctx, timeouts, &data, diags) || data.Delete.IsNull() || data.Delete.IsUnknown() {
+		return
+	}
+
+	if d, err := time.ParseDuration(data.Delete.ValueString()); err != nil || d <= 0 {
+		diags.AddAttributeError(
+			path.Root("timeouts").AtName("delete"),
+			"Invalid timeouts",
+			fmt.Sprintf("timeouts.delete must be a positive duration such as 30s or 10m, got %q.", data.Delete.ValueString()),
+		)
+	}
+}
+
+// deleteTimeout returns the delete timeout of timeouts, or def if it isn't
+// set.
+func deleteTimeout(ctx context.Context, timeouts types.Object, def time.Duration, diags *diag.Diagnostics) time.Duration {
+	var data timeoutsModel
+	if !timeoutsValue(ctx, timeouts, &data, diags) || data.Delete.IsNull() || data.Delete.IsUnknown() {
+		return def
+	}
+
+	d, err := time.ParseDuration(data.Delete.ValueString())
+	if err != nil || d <= 0 {
+		return def
+	}
+
+	return d
+}
+
+// timeoutsValue reads timeouts into data, false if the block isn't set.
+func timeoutsValue(ctx context.Context, timeouts types.Object, data *timeoutsModel, diags *diag.Diagnostics) bool {
+	if timeouts.IsNull() || timeouts.IsUnknown() {
+		return false
+	}
+	diags.Append(timeouts.As(ctx, data, basetypes.ObjectAsOptions{})...)
+
+	return !diags.HasError()
+}
@@ -0,0 +1,127 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// newHTTPTransport returns the transport all requests of the provider start
+// from: http.DefaultTransport, or a copy of it that also trusts the
+// certificates of ca_cert_pem and ca_cert_file, or that doesn't verify
+// certificates at all with tls_insecure_skip_verify. The transport is created
+// once per provider so connections are reused.
+func newHTTPTransport(data icebergProviderModel, diags *diag.Diagnostics) http.RoundTripper {
+	insecure := data.InsecureSkipVerify.ValueBool()
+	pool := loadCACertPool(data, diags)
+	if pool == nil && !insecure {
+		return http.DefaultTransport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12, InsecureSkipVerify: insecure}
+	if insecure {
+		diags.AddAttributeWarning(
+			path.Root("tls_insecure_skip_verify"),
+			"TLS certificate verification disabled",
+			"tls_insecure_skip_verify is set, so the certificates of the catalog, the Polaris management API and the OAuth2 token endpoint aren't verified "+
+				"and anyone on the network path can intercept their requests, including credentials. Only use it with development catalogs; "+
+				"trust a private CA with ca_cert_pem or ca_cert_file instead.",
+		)
+	}
+
+	return transport
+}
+
+// loadCACertPool returns the system roots with the certificates of
+// ca_cert_pem and ca_cert_file added, or nil if neither is set.
+func loadCACertPool(data icebergProviderModel, diags *diag.Diagnostics) *x509.CertPool {
+	var sources []caCertSource
+	if value := data.CACertPEM; !value.IsNull() && !value.IsUnknown() {
+		sources = append(sources, caCertSource{attribute: "ca_cert_pem", data: []byte(value.ValueString())})
+	}
+	if file := data.CACertFile; !file.IsNull() && !file.IsUnknown() {
+		b, err := os.ReadFile(file.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("ca_cert_file"), "Invalid CA certificate", "Failed to read ca_cert_file: "+err.Error())
+
+			return nil
+		}
+		sources = append(sources, caCertSource{attribute: "ca_cert_file", data: b})
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	for _, source := range sources {
+		certs, err := parsePEMCertificates(source.data)
+		if err != nil {
+			diags.AddAttributeError(path.Root(source.attribute), "Invalid CA certificate", fmt.Sprintf("%s: %s.", source.attribute, err))
+
+			return nil
+		}
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+	}
+
+	return pool
+}
+
+type caCertSource struct {
+	attribute string
+	data      []byte
+}
+
+// parsePEMCertificates parses all CERTIFICATE blocks of data. Unlike
+// x509.CertPool.AppendCertsFromPEM it fails on a block it can't parse
+// instead of skipping it, so a broken bundle is reported right away.
+func parsePEMCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d can't be parsed: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM-encoded certificate found")
+	}
+
+	return certs, nil
+}
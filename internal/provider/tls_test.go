@@ -0,0 +1,211 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPrivateCATLSServer starts a catalog with a Polaris management API whose
+// certificate is issued by a freshly generated CA, and returns the server and
+// the PEM-encoded CA certificate.
+func newPrivateCATLSServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Private CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serverDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "catalog"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}, caCert, &serverKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/config":
+			writeJSON(w, http.StatusOK, map[string]any{"defaults": map[string]string{}, "overrides": map[string]string{}})
+		case "/api/management/v1/principals/etl":
+			writeJSON(w, http.StatusOK, polarisPrincipal{Name: "etl"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	// The untrusted client aborts handshakes, which isn't worth logging.
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{serverDER}, PrivateKey: serverKey}}}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+}
+
+func TestProviderCACertificates(t *testing.T) {
+	ctx := context.Background()
+	server, caPEM := newPrivateCATLSServer(t)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte(caPEM), 0o600))
+
+	for _, tc := range []struct {
+		name      string
+		attribute string
+		value     string
+	}{
+		{name: "pem", attribute: "ca_cert_pem", value: caPEM},
+		{name: "file", attribute: "ca_cert_file", value: caFile},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, diags := configureTestProvider(t, map[string]tftypes.Value{
+				"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+				"type":        tftypes.NewValue(tftypes.String, "polaris"),
+				tc.attribute:  tftypes.NewValue(tftypes.String, tc.value),
+			})
+			require.False(t, diags.HasError(), "%v", diags)
+
+			_, err := p.NewCatalog(ctx, "iceberg_namespace")
+			require.NoError(t, err, "the catalog client trusts the CA")
+
+			client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+			require.NoError(t, err)
+			_, err = client.GetPrincipal(ctx, "etl")
+			require.NoError(t, err, "the management client trusts the CA")
+		})
+	}
+
+	t.Run("untrusted", func(t *testing.T) {
+		p, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+
+		_, err := p.NewCatalog(ctx, "iceberg_namespace")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+	})
+}
+
+func TestProviderCACertificatesInvalid(t *testing.T) {
+	_, caPEM := newPrivateCATLSServer(t)
+
+	for _, tc := range []struct {
+		name       string
+		attributes map[string]tftypes.Value
+		wantError  string
+	}{
+		{
+			name:       "not pem",
+			attributes: map[string]tftypes.Value{"ca_cert_pem": tftypes.NewValue(tftypes.String, "not a certificate")},
+			wantError:  "ca_cert_pem: no PEM-encoded certificate found.",
+		},
+		{
+			name: "broken certificate",
+			attributes: map[string]tftypes.Value{"ca_cert_pem": tftypes.NewValue(tftypes.String, caPEM+string(pem.EncodeToMemory(&pem.Block{
+				Type: "CERTIFICATE", Bytes: []byte("garbage"),
+			})))},
+			wantError: "ca_cert_pem: certificate 2 can't be parsed",
+		},
+		{
+			name:       "missing file",
+			attributes: map[string]tftypes.Value{"ca_cert_file": tftypes.NewValue(tftypes.String, filepath.Join(t.TempDir(), "missing.pem"))},
+			wantError:  "Failed to read ca_cert_file",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.attributes["catalog_uri"] = tftypes.NewValue(tftypes.String, "https://catalog.example.com")
+			_, diags := configureTestProvider(t, tc.attributes)
+			require.Len(t, diags.Errors(), 1)
+			assert.Equal(t, "Invalid CA certificate", diags.Errors()[0].Summary())
+			assert.Contains(t, diags.Errors()[0].Detail(), tc.wantError)
+		})
+	}
+}
+
+func TestProviderInsecureSkipVerify(t *testing.T) {
+	ctx := context.Background()
+	server, _ := newPrivateCATLSServer(t)
+
+	for _, insecure := range []bool{true, false} {
+		t.Run(strconv.FormatBool(insecure), func(t *testing.T) {
+			p, diags := configureTestProvider(t, map[string]tftypes.Value{
+				"catalog_uri":              tftypes.NewValue(tftypes.String, server.URL),
+				"type":                     tftypes.NewValue(tftypes.String, "polaris"),
+				"tls_insecure_skip_verify": tftypes.NewValue(tftypes.Bool, insecure),
+			})
+			require.False(t, diags.HasError(), "%v", diags)
+
+			_, catalogErr := p.NewCatalog(ctx, "iceberg_namespace")
+			client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+			require.NoError(t, err)
+			_, managementErr := client.GetPrincipal(ctx, "etl")
+
+			if !insecure {
+				assert.Empty(t, diags.Warnings())
+				require.Error(t, catalogErr)
+				assert.Contains(t, catalogErr.Error(), "certificate signed by unknown authority")
+				require.Error(t, managementErr)
+				assert.Contains(t, managementErr.Error(), "certificate signed by unknown authority")
+
+				return
+			}
+			require.Len(t, diags.Warnings(), 1)
+			assert.Equal(t, "TLS certificate verification disabled", diags.Warnings()[0].Summary())
+			require.NoError(t, catalogErr, "the catalog client skips verification")
+			require.NoError(t, managementErr, "the management client skips verification")
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The track_* attributes of iceberg_table and iceberg_namespace turn off
+// computed attributes that change often without being of interest, so that
+// they neither show up in plans nor take space in state. The attributes are
+// null rather than stale while turned off, and are read again by the apply
+// that turns them back on.
+
+// tracked reports whether the attributes turned off by the track_* attribute
+// flag are stored. Unknown flags count as true until they are known.
+func tracked(flag types.Bool) bool {
+	return flag.IsNull() || flag.IsUnknown() || flag.ValueBool()
+}
+
+// planUntracked plans the attributes in untracked with their null values.
+// They aren't read while turned off, so unlike other computed attributes they
+// are known not to change.
+func planUntracked(ctx context.Context, untracked map[string]attr.Value, plan *tfsdk.Plan, diags *diag.Diagnostics) {
+	for name, value := range untracked {
+		diags.Append(plan.SetAttribute(ctx, path.Root(name), value)...)
+	}
+}
+
+// untrackedAttributes returns the attributes of m that its track_* flags turn
+// off, with null values.
+func (m *icebergTableResourceModel) untrackedAttributes() map[string]attr.Value {
+	untracked := make(map[string]attr.Value)
+	if !tracked(m.TrackServerProperties) {
+		untracked["server_properties"] = types.MapNull(types.StringType)
+		untracked["server_managed_properties"] = types.MapNull(types.StringType)
+		untracked["sensitive_server_properties"] = types.MapNull(types.StringType)
+	}
+	if !tracked(m.TrackStatistics) {
+		untracked["last_commit_timestamp_ms"] = types.Int64Null()
+	}
+
+	return untracked
+}
+
+// clearUntracked sets the attributes of m that its track_* flags turn off to
+// null.
+func (m *icebergTableResourceModel) clearUntracked() {
+	if !tracked(m.TrackServerProperties) {
+		m.ServerProperties = types.MapNull(types.StringType)
+		m.ServerManagedProperties = types.MapNull(types.StringType)
+		m.SensitiveServerProperties = types.MapNull(types.StringType)
+	}
+	if !tracked(m.TrackStatistics) {
+		m.LastCommitTimestampMs = types.Int64Null()
+	}
+}
+
+// validateTrackingConfig rejects max_staleness_check without the commit
+// timestamp it is checked against.
+func validateTrackingConfig(data *icebergTableResourceModel, diags *diag.Diagnostics) {
+	if !data.TrackStatistics.IsNull() && !data.TrackStatistics.IsUnknown() && !data.TrackStatistics.ValueBool() && !data.MaxStalenessCheck.IsNull() {
+		diags.AddAttributeError(
+			path.Root("max_staleness_check"),
+			"Invalid max_staleness_check",
+			"max_staleness_check is checked against last_commit_timestamp_ms, which track_statistics = false turns off. Remove one of them.",
+		)
+	}
+}
+
+// untrackedAttributes returns the attributes of m that its track_* flags turn
+// off, with null values.
+func (m *icebergNamespaceResourceModel) untrackedAttributes() map[string]attr.Value {
+	untracked := make(map[string]attr.Value)
+	if !tracked(m.TrackServerProperties) {
+		untracked["server_properties"] = types.MapNull(types.StringType)
+		untracked["server_managed_properties"] = types.MapNull(types.StringType)
+		untracked["properties"] = types.MapNull(types.StringType)
+	}
+
+	return untracked
+}
+
+// clearUntracked sets the attributes of m that its track_* flags turn off to
+// null.
+func (m *icebergNamespaceResourceModel) clearUntracked() {
+	if !tracked(m.TrackServerProperties) {
+		m.ServerProperties = types.MapNull(types.StringType)
+		m.ServerManagedProperties = types.MapNull(types.StringType)
+		m.Properties = types.MapNull(types.StringType)
+	}
+}
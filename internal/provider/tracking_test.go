@@ -0,0 +1,206 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClearUntracked(t *testing.T) {
+	serverProperties := types.MapValueMust(types.StringType, nil)
+	model := icebergTableResourceModel{
+		ServerProperties:          serverProperties,
+		ServerManagedProperties:   serverProperties,
+		SensitiveServerProperties: serverProperties,
+		LastCommitTimestampMs:     types.Int64Value(1700000000000),
+		TrackServerProperties:     types.BoolNull(),
+		TrackStatistics:           types.BoolUnknown(),
+	}
+	model.clearUntracked()
+	assert.Empty(t, model.untrackedAttributes())
+	assert.Equal(t, serverProperties, model.ServerProperties, "tracked by default")
+	assert.Equal(t, types.Int64Value(1700000000000), model.LastCommitTimestampMs)
+
+	model.TrackServerProperties = types.BoolValue(false)
+	model.clearUntracked()
+	assert.True(t, model.ServerProperties.IsNull())
+	assert.True(t, model.ServerManagedProperties.IsNull())
+	assert.True(t, model.SensitiveServerProperties.IsNull())
+	assert.False(t, model.LastCommitTimestampMs.IsNull())
+
+	model.TrackStatistics = types.BoolValue(false)
+	model.clearUntracked()
+	assert.True(t, model.LastCommitTimestampMs.IsNull())
+	assert.Len(t, model.untrackedAttributes(), 4)
+}
+
+func TestValidateTrackingConfig(t *testing.T) {
+	var diags diag.Diagnostics
+	validateTrackingConfig(&icebergTableResourceModel{TrackStatistics: types.BoolValue(false), MaxStalenessCheck: types.StringNull()}, &diags)
+	validateTrackingConfig(&icebergTableResourceModel{TrackStatistics: types.BoolNull(), MaxStalenessCheck: types.StringValue("36h")}, &diags)
+	require.Empty(t, diags)
+
+	validateTrackingConfig(&icebergTableResourceModel{TrackStatistics: types.BoolValue(false), MaxStalenessCheck: types.StringValue("36h")}, &diags)
+	require.Len(t, diags.Errors(), 1)
+	assert.Equal(t, "Invalid max_staleness_check", diags.Errors()[0].Summary())
+}
+
+func TestAccIcebergTable_Tracking(t *testing.T) {
+	m := newMockRESTCatalog()
+	m.addTable("db", "other", nil)
+	server := m.start(t)
+
+	config := func(tracking string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_table" "orders" {
+  namespace = ["db"]
+  name      = "orders"
+  %s
+  user_properties = {
+    owner = "etl"
+  }
+  schema = {
+    fields = [
+      { id = 1, name = "id", type = "long", required = true },
+    ]
+  }
+}
+`, server.URL, tracking)
+	}
+
+	tracked := resource.ComposeAggregateTestCheckFunc(
+		resource.TestCheckResourceAttr("iceberg_table.orders", "server_properties.owner", "etl"),
+		resource.TestCheckResourceAttrSet("iceberg_table.orders", "server_managed_properties.%"),
+		resource.TestCheckResourceAttrSet("iceberg_table.orders", "sensitive_server_properties.%"),
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check:  tracked,
+			},
+			{
+				Config: config(`track_server_properties = false
+  track_statistics        = false`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "server_properties.%"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "server_managed_properties.%"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "sensitive_server_properties.%"),
+					resource.TestCheckNoResourceAttr("iceberg_table.orders", "last_commit_timestamp_ms"),
+					resource.TestCheckResourceAttr("iceberg_table.orders", "user_properties.owner", "etl"),
+				),
+			},
+			{
+				// Nothing is planned while the properties change on the server.
+				PreConfig: func() {
+					m.mu.Lock()
+					defer m.mu.Unlock()
+					m.tables["db"]["orders"]["written-by"] = "spark"
+				},
+				Config: config(`track_server_properties = false
+  track_statistics        = false`),
+				PlanOnly: true,
+			},
+			{
+				Config: config(`track_server_properties = true`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					tracked,
+					resource.TestCheckResourceAttr("iceberg_table.orders", "server_managed_properties.written-by", "spark"),
+				),
+			},
+			{
+				Config: config(`track_statistics = false
+  max_staleness_check = "36h"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid max_staleness_check`),
+			},
+		},
+	})
+}
+
+func TestAccIcebergNamespace_Tracking(t *testing.T) {
+	m := newMockRESTCatalog()
+	server := m.start(t)
+
+	config := func(tracking string) string {
+		return fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+}
+
+resource "iceberg_namespace" "db" {
+  name = ["db"]
+  %s
+  user_properties = {
+    owner = "etl"
+  }
+}
+`, server.URL, tracking)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.db", "server_properties.owner", "etl"),
+					resource.TestCheckResourceAttr("iceberg_namespace.db", "server_managed_properties.%", "0"),
+				),
+			},
+			{
+				Config: config("track_server_properties = false"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("iceberg_namespace.db", "server_properties.%"),
+					resource.TestCheckNoResourceAttr("iceberg_namespace.db", "server_managed_properties.%"),
+					resource.TestCheckResourceAttr("iceberg_namespace.db", "user_properties.owner", "etl"),
+				),
+			},
+			{
+				PreConfig: func() {
+					m.mu.Lock()
+					defer m.mu.Unlock()
+					m.namespaces["db"]["location"] = "s3://warehouse/db"
+				},
+				Config:   config("track_server_properties = false"),
+				PlanOnly: true,
+			},
+			{
+				Config: config(""),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.db", "server_properties.owner", "etl"),
+					resource.TestCheckResourceAttr("iceberg_namespace.db", "server_managed_properties.location", "s3://warehouse/db"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,111 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// The REST catalog client builds the routes of namespaces and tables as
+// <catalog_uri>/v1/<prefix>/namespaces/..., with the prefix the catalog
+// returns in the overrides of GET /v1/config. Gateways that mount the catalog
+// under another path don't always return it, so uri_prefix replaces the
+// prefix in the config response before the client reads it.
+
+// uriPrefixConfig returns uri_prefix without leading and trailing slashes, or
+// "" if it isn't set. Prefixes that aren't a plain path are reported as
+// errors.
+func uriPrefixConfig(data icebergProviderModel, diags *diag.Diagnostics) string {
+	if data.URIPrefix.IsNull() || data.URIPrefix.IsUnknown() {
+		return ""
+	}
+
+	prefix, err := normalizeURIPrefix(data.URIPrefix.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("uri_prefix"), "Invalid uri_prefix", err.Error()+".")
+
+		return ""
+	}
+
+	return prefix
+}
+
+// normalizeURIPrefix trims the slashes around prefix and checks that what is
+// left is a path the catalog routes can be joined to.
+func normalizeURIPrefix(prefix string) (string, error) {
+	trimmed := strings.Trim(strings.TrimSpace(prefix), "/")
+	if trimmed == "" {
+		return "", fmt.Errorf("uri_prefix must be a path such as lakehouse/api/catalog, got %q", prefix)
+	}
+	if strings.ContainsAny(trimmed, "?#") {
+		return "", fmt.Errorf("uri_prefix is a path and can't contain a query or fragment, got %q", prefix)
+	}
+	for _, segment := range strings.Split(trimmed, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("uri_prefix can't contain empty, . or .. path segments, got %q", prefix)
+		}
+	}
+
+	return trimmed, nil
+}
+
+// overridePrefix sets the prefix in the overrides of a successful config
+// response to prefix. Other responses, and bodies that aren't a JSON object,
+// are left unchanged.
+func overridePrefix(req *http.Request, resp *http.Response, prefix string) error {
+	if prefix == "" || req.Method != http.MethodGet || !strings.HasSuffix(req.URL.Path, "/v1/config") || resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var cfg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &cfg); err != nil || cfg == nil {
+		return nil
+	}
+	overrides := make(map[string]string)
+	if raw, ok := cfg["overrides"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &overrides); err != nil {
+			return nil
+		}
+	}
+	overrides["prefix"] = prefix
+	if cfg["overrides"], err = json.Marshal(overrides); err != nil {
+		return err
+	}
+	if body, err = json.Marshal(cfg); err != nil {
+		return err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+
+	return nil
+}
@@ -0,0 +1,179 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/catalog"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeURIPrefix(t *testing.T) {
+	for _, tc := range []struct {
+		prefix    string
+		want      string
+		wantError string
+	}{
+		{prefix: "lakehouse/api/catalog", want: "lakehouse/api/catalog"},
+		{prefix: "/lakehouse/api/catalog/", want: "lakehouse/api/catalog"},
+		{prefix: " //ws-1// ", want: "ws-1"},
+		{prefix: "/", wantError: "must be a path"},
+		{prefix: "", wantError: "must be a path"},
+		{prefix: "api?warehouse=a", wantError: "can't contain a query or fragment"},
+		{prefix: "api//catalog", wantError: "can't contain empty, . or .. path segments"},
+		{prefix: "api/../admin", wantError: "can't contain empty, . or .. path segments"},
+	} {
+		got, err := normalizeURIPrefix(tc.prefix)
+		if tc.wantError != "" {
+			require.Error(t, err, tc.prefix)
+			assert.Contains(t, err.Error(), tc.wantError, tc.prefix)
+
+			continue
+		}
+		require.NoError(t, err, tc.prefix)
+		assert.Equal(t, tc.want, got, tc.prefix)
+	}
+}
+
+// newGatewayServer serves m under /v1/lakehouse/api/catalog the way a gateway
+// mounting the catalog does, while its config response returns another
+// prefix. It records the paths of the requests outside the config endpoint.
+func newGatewayServer(t *testing.T, m *mockRESTCatalog) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	catalogHandler := m.start(t).Config.Handler
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/config" {
+			writeJSON(w, http.StatusOK, map[string]any{
+				"defaults":  map[string]string{},
+				"overrides": map[string]string{"prefix": "internal"},
+			})
+
+			return
+		}
+
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if !strings.HasPrefix(r.URL.Path, "/v1/lakehouse/api/catalog/") {
+			http.NotFound(w, r)
+
+			return
+		}
+		r.URL.Path = strings.Replace(r.URL.Path, "/v1/lakehouse/api/catalog", "/v1", 1)
+		catalogHandler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]string(nil), paths...)
+	}
+}
+
+func TestProviderURIPrefix(t *testing.T) {
+	ctx := context.Background()
+	m := newMockRESTCatalog()
+	server, paths := newGatewayServer(t, m)
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"catalog_uri": tftypes.NewValue(tftypes.String, server.URL+"/"),
+		"uri_prefix":  tftypes.NewValue(tftypes.String, "/lakehouse/api/catalog/"),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+
+	cat, err := p.NewCatalog(ctx, "iceberg_namespace")
+	require.NoError(t, err)
+	assert.Equal(t, "lakehouse/api/catalog", p.capabilities.catalogPrefix())
+
+	ns := []string{"db"}
+	require.NoError(t, cat.CreateNamespace(ctx, ns, iceberg.Properties{"owner": "data_eng"}))
+	props, err := cat.LoadNamespaceProperties(ctx, ns)
+	require.NoError(t, err)
+	assert.Equal(t, "data_eng", props["owner"])
+	_, err = cat.UpdateNamespaceProperties(ctx, ns, []string{"owner"}, iceberg.Properties{"team": "platform"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "platform"}, m.namespaceProperties("db"))
+	require.NoError(t, cat.DropNamespace(ctx, ns))
+	_, err = cat.LoadNamespaceProperties(ctx, ns)
+	require.ErrorIs(t, err, catalog.ErrNoSuchNamespace)
+
+	require.NotEmpty(t, paths())
+	for _, request := range paths() {
+		_, path, _ := strings.Cut(request, " ")
+		assert.True(t, strings.HasPrefix(path, "/v1/lakehouse/api/catalog/namespaces"), request)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, diags := configureTestProvider(t, map[string]tftypes.Value{
+			"catalog_uri": tftypes.NewValue(tftypes.String, server.URL),
+			"uri_prefix":  tftypes.NewValue(tftypes.String, "api/../admin"),
+		})
+		require.True(t, diags.HasError())
+		assert.Equal(t, "Invalid uri_prefix", diags.Errors()[0].Summary())
+	})
+}
+
+func TestAccIcebergNamespace_URIPrefix(t *testing.T) {
+	m := newMockRESTCatalog()
+	server, _ := newGatewayServer(t, m)
+
+	providerCfg := fmt.Sprintf(`
+provider "iceberg" {
+  catalog_uri = "%s"
+  uri_prefix  = "/lakehouse/api/catalog/"
+}
+`, server.URL)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIcebergNamespaceResourceConfig(providerCfg, "db1", "test description"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("iceberg_namespace.test", "name.0", "db1"),
+					resource.TestCheckResourceAttr("iceberg_namespace.test", "server_properties.description", "test description"),
+				),
+			},
+			{
+				Config: testAccIcebergNamespaceResourceConfig(providerCfg, "db1", "updated description"),
+				Check:  resource.TestCheckResourceAttr("iceberg_namespace.test", "server_properties.description", "updated description"),
+			},
+			{
+				ResourceName:            "iceberg_namespace.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"server_properties"},
+			},
+		},
+	})
+}
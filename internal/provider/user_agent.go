@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"net/http"
+	"runtime/debug"
+	"strings"
+)
+
+// userAgentProduct is the product name of the provider in the User-Agent
+// header.
+const userAgentProduct = "apache-iceberg-terraform"
+
+// providerVersion returns the version of the provider: the one set at release
+// time, see main.go, or else the module version from the build info, which
+// go install sets for a tagged version. It is "dev" for local builds.
+func providerVersion(released string) string {
+	if released != "" {
+		return released
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return strings.TrimPrefix(info.Main.Version, "v")
+	}
+
+	return "dev"
+}
+
+// userAgent returns the User-Agent of the requests of the provider version.
+func userAgent(version string) string {
+	ua := userAgentProduct + "/" + version + " terraform-plugin-framework"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/hashicorp/terraform-plugin-framework" {
+				ua += "/" + strings.TrimPrefix(dep.Version, "v")
+			}
+		}
+	}
+
+	return ua
+}
+
+// userAgentTransport sets the User-Agent header of every request to
+// userAgent. A User-Agent the client library set, such as iceberg-go's
+// GoIceberg/<version>, follows it so the library stays identifiable, and
+// extra, from user_agent_extra, comes last.
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+	extra     string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ua := t.userAgent
+	if library := req.Header.Get("User-Agent"); library != "" {
+		ua += " " + library
+	}
+	if t.extra != "" {
+		ua += " " + t.extra
+	}
+	// Retries send the same request again, so it is left unchanged.
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", ua)
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,76 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAgent(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	userAgents := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgents[r.Method+" "+r.URL.Path] = r.Header.Get("User-Agent")
+		mu.Unlock()
+		if r.URL.Path == "/v1/config" {
+			writeJSON(w, http.StatusOK, map[string]any{"defaults": map[string]string{}, "overrides": map[string]string{}})
+
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	p, diags := configureTestProvider(t, map[string]tftypes.Value{
+		"catalog_uri":      tftypes.NewValue(tftypes.String, server.URL),
+		"user_agent_extra": tftypes.NewValue(tftypes.String, " pipeline/nightly "),
+	})
+	require.False(t, diags.HasError(), "%v", diags)
+	p.version = "1.2.3"
+
+	_, err := p.NewCatalog(ctx, "iceberg_table")
+	require.NoError(t, err)
+
+	p.polaris = &polarisConfig{managementURI: server.URL + "/api/management/v1"}
+	client, err := p.newPolarisManagementClient("iceberg_polaris_principal")
+	require.NoError(t, err)
+	require.NoError(t, client.DeletePrincipal(ctx, "etl"))
+
+	catalogUA := userAgents["GET /v1/config"]
+	assert.Regexp(t, `^apache-iceberg-terraform/1\.2\.3 terraform-plugin-framework(/\S+)? GoIceberg/.+ pipeline/nightly$`, catalogUA)
+	managementUA := userAgents["DELETE /api/management/v1/principals/etl"]
+	assert.Regexp(t, `^apache-iceberg-terraform/1\.2\.3 terraform-plugin-framework(/\S+)? pipeline/nightly$`, managementUA)
+}
+
+func TestProviderVersion(t *testing.T) {
+	assert.Equal(t, "1.2.3", providerVersion("1.2.3"))
+	assert.NotEmpty(t, providerVersion(""))
+
+	var resp provider.MetadataResponse
+	New("1.2.3")().Metadata(context.Background(), provider.MetadataRequest{}, &resp)
+	assert.Equal(t, "1.2.3", resp.Version)
+}
@@ -0,0 +1,166 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+)
+
+// writeOrderTermPattern matches a write_order term: a column name followed by
+// optional direction and null order keywords.
+var writeOrderTermPattern = regexp.MustCompile(`^\S+(\s+(?i:asc|desc|nulls_first|nulls_last))*$`)
+
+// parseWriteOrderTerm splits a write_order term such as "user_id desc" into
+// the column and its sort direction and null order. Nulls default to first
+// for ascending and last for descending columns.
+func parseWriteOrderTerm(term string) (string, table.SortDirection, table.NullOrder, error) {
+	words := strings.Fields(term)
+	if len(words) == 0 {
+		return "", "", "", fmt.Errorf("write_order term %q has no column", term)
+	}
+
+	var direction table.SortDirection
+	var nullOrder table.NullOrder
+	for _, word := range words[1:] {
+		switch strings.ToLower(word) {
+		case "asc", "desc":
+			if direction != "" {
+				return "", "", "", fmt.Errorf("write_order term %q sets the direction twice", term)
+			}
+			direction = table.SortDirection(strings.ToLower(word))
+		case "nulls_first", "nulls_last":
+			if nullOrder != "" {
+				return "", "", "", fmt.Errorf("write_order term %q sets the null order twice", term)
+			}
+			nullOrder = table.NullsFirst
+			if strings.EqualFold(word, "nulls_last") {
+				nullOrder = table.NullsLast
+			}
+		default:
+			return "", "", "", fmt.Errorf("write_order term %q: unknown keyword %q, expected asc, desc, nulls_first or nulls_last", term, word)
+		}
+	}
+
+	if direction == "" {
+		direction = table.SortASC
+	}
+	if nullOrder == "" {
+		nullOrder = table.NullsFirst
+		if direction == table.SortDESC {
+			nullOrder = table.NullsLast
+		}
+	}
+
+	return words[0], direction, nullOrder, nil
+}
+
+// compileWriteOrder turns write_order terms into identity sort fields. Each
+// column is looked up by name in the schemas in turn, using the first one in
+// which it has a field ID.
+func compileWriteOrder(terms []string, schemas ...*iceberg.Schema) ([]table.SortField, error) {
+	fields := make([]table.SortField, 0, len(terms))
+	for _, term := range terms {
+		column, direction, nullOrder, err := parseWriteOrderTerm(term)
+		if err != nil {
+			return nil, err
+		}
+
+		sourceID := 0
+		for _, schema := range schemas {
+			if schema == nil {
+				continue
+			}
+			if field, ok := schema.FindFieldByName(column); ok && field.ID != 0 {
+				sourceID = field.ID
+
+				break
+			}
+		}
+		if sourceID == 0 {
+			return nil, fmt.Errorf("write_order column %q is not in the table schema", column)
+		}
+
+		fields = append(fields, table.SortField{
+			SourceID:  sourceID,
+			Transform: iceberg.IdentityTransform{},
+			Direction: direction,
+			NullOrder: nullOrder,
+		})
+	}
+
+	return fields, nil
+}
+
+// newWriteOrder compiles terms into a sort order with the given ID.
+func newWriteOrder(orderID int, terms []string, schemas ...*iceberg.Schema) (table.SortOrder, error) {
+	fields, err := compileWriteOrder(terms, schemas...)
+	if err != nil {
+		return table.SortOrder{}, err
+	}
+	if len(fields) == 0 {
+		return table.UnsortedSortOrder, nil
+	}
+
+	return table.NewSortOrder(orderID, fields)
+}
+
+// writeOrderMatches reports whether terms compile to the fields of order.
+// Sort order IDs are assigned by the catalog and not compared.
+func writeOrderMatches(terms []string, order table.SortOrder, schema *iceberg.Schema) bool {
+	fields, err := compileWriteOrder(terms, schema)
+	if err != nil {
+		return false
+	}
+
+	return slices.Equal(fields, slices.Collect(order.Fields()))
+}
+
+// writeOrderTerms is the inverse of compileWriteOrder. It returns the
+// canonical terms for order, leaving out directions and null orders that are
+// the defaults, and false if order uses a transform other than identity.
+func writeOrderTerms(order table.SortOrder, schema *iceberg.Schema) ([]string, bool) {
+	terms := make([]string, 0, order.Len())
+	for field := range order.Fields() {
+		if _, ok := field.Transform.(iceberg.IdentityTransform); !ok {
+			return nil, false
+		}
+		column, ok := schema.FindColumnName(field.SourceID)
+		if !ok {
+			return nil, false
+		}
+
+		term := column
+		if field.Direction == table.SortDESC {
+			term += " desc"
+		}
+		defaultNulls := table.NullsFirst
+		if field.Direction == table.SortDESC {
+			defaultNulls = table.NullsLast
+		}
+		if field.NullOrder != defaultNulls {
+			term += " " + strings.ReplaceAll(string(field.NullOrder), "-", "_")
+		}
+		terms = append(terms, term)
+	}
+
+	return terms, true
+}
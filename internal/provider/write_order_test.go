@@ -0,0 +1,161 @@
+// Licensed to the Apache Software Foundation (ASF) under one or more
+// contributor license agreements.  See the NOTICE file distributed with
+// this work for additional information regarding copyright ownership.
+// The ASF licenses this file to You under the Apache License, Version 2.0
+// (the "License"); you may not use this file except in compliance with
+// the License.  You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/apache/iceberg-go"
+	"github.com/apache/iceberg-go/table"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeOrderTestSchema() *iceberg.Schema {
+	return iceberg.NewSchema(0,
+		iceberg.NestedField{ID: 10, Name: "event_date", Type: iceberg.PrimitiveTypes.Date, Required: true},
+		iceberg.NestedField{ID: 20, Name: "user_id", Type: iceberg.PrimitiveTypes.Int64},
+		iceberg.NestedField{ID: 30, Name: "payload", Type: &iceberg.StructType{FieldList: []iceberg.NestedField{
+			{ID: 31, Name: "kind", Type: iceberg.PrimitiveTypes.String},
+		}}},
+	)
+}
+
+func TestParseWriteOrderTerm(t *testing.T) {
+	tests := []struct {
+		term      string
+		column    string
+		direction table.SortDirection
+		nullOrder table.NullOrder
+	}{
+		{"event_date", "event_date", table.SortASC, table.NullsFirst},
+		{"user_id desc", "user_id", table.SortDESC, table.NullsLast},
+		{"user_id  ASC", "user_id", table.SortASC, table.NullsFirst},
+		{"user_id nulls_last", "user_id", table.SortASC, table.NullsLast},
+		{"user_id desc nulls_first", "user_id", table.SortDESC, table.NullsFirst},
+		{"payload.kind nulls_last desc", "payload.kind", table.SortDESC, table.NullsLast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			assert.True(t, writeOrderTermPattern.MatchString(tt.term))
+
+			column, direction, nullOrder, err := parseWriteOrderTerm(tt.term)
+			require.NoError(t, err)
+			assert.Equal(t, tt.column, column)
+			assert.Equal(t, tt.direction, direction)
+			assert.Equal(t, tt.nullOrder, nullOrder)
+		})
+	}
+
+	for _, term := range []string{"", "user_id desc asc", "user_id nulls_first nulls_last", "user_id descending"} {
+		_, _, _, err := parseWriteOrderTerm(term)
+		assert.Error(t, err, term)
+	}
+}
+
+func TestCompileWriteOrder(t *testing.T) {
+	fields, err := compileWriteOrder([]string{"event_date", "user_id desc", "payload.kind nulls_last"}, writeOrderTestSchema())
+	require.NoError(t, err)
+	assert.Equal(t, []table.SortField{
+		{SourceID: 10, Transform: iceberg.IdentityTransform{}, Direction: table.SortASC, NullOrder: table.NullsFirst},
+		{SourceID: 20, Transform: iceberg.IdentityTransform{}, Direction: table.SortDESC, NullOrder: table.NullsLast},
+		{SourceID: 31, Transform: iceberg.IdentityTransform{}, Direction: table.SortASC, NullOrder: table.NullsLast},
+	}, fields)
+
+	_, err = compileWriteOrder([]string{"missing"}, writeOrderTestSchema())
+	assert.ErrorContains(t, err, `write_order column "missing" is not in the table schema`)
+
+	// Columns without an ID in the first schema are looked up in the next.
+	unassigned := iceberg.NewSchema(0, iceberg.NestedField{Name: "user_id", Type: iceberg.PrimitiveTypes.Int64})
+	fields, err = compileWriteOrder([]string{"user_id"}, unassigned, writeOrderTestSchema())
+	require.NoError(t, err)
+	assert.Equal(t, 20, fields[0].SourceID)
+}
+
+// TestWriteOrderRoundTrip checks that the compiled order still matches the
+// configured terms after the catalog stored it, which reassigns field IDs.
+func TestWriteOrderRoundTrip(t *testing.T) {
+	terms := []string{"event_date", "user_id desc", "payload.kind desc nulls_first"}
+
+	order, err := newWriteOrder(1, terms, writeOrderTestSchema())
+	require.NoError(t, err)
+
+	metadata, err := table.NewMetadata(writeOrderTestSchema(), iceberg.UnpartitionedSpec, order, "file:///tmp/warehouse/db/events", nil)
+	require.NoError(t, err)
+	stored := metadata.SortOrder()
+	require.NotEqual(t, slices.Collect(order.Fields()), slices.Collect(stored.Fields()), "expected the catalog to reassign field IDs")
+
+	assert.True(t, writeOrderMatches(terms, stored, metadata.CurrentSchema()))
+
+	canonical, ok := writeOrderTerms(stored, metadata.CurrentSchema())
+	require.True(t, ok)
+	assert.Equal(t, terms, canonical)
+
+	// Non-canonical spellings still match the stored order.
+	assert.True(t, writeOrderMatches([]string{"event_date asc nulls_first", "user_id DESC", "payload.kind nulls_first desc"}, stored, metadata.CurrentSchema()))
+	assert.False(t, writeOrderMatches([]string{"event_date", "user_id"}, stored, metadata.CurrentSchema()))
+}
+
+func TestWriteOrderTermsUnsupportedTransform(t *testing.T) {
+	order, err := table.NewSortOrder(1, []table.SortField{
+		{SourceID: 20, Transform: iceberg.BucketTransform{NumBuckets: 16}, Direction: table.SortASC, NullOrder: table.NullsFirst},
+	})
+	require.NoError(t, err)
+
+	_, ok := writeOrderTerms(order, writeOrderTestSchema())
+	assert.False(t, ok)
+}
+
+func TestTableCreateArgsWriteOrder(t *testing.T) {
+	ctx := context.Background()
+
+	// Schema fields configured without IDs.
+	var s icebergTableSchema
+	require.NoError(t, s.FromIceberg(iceberg.NewSchema(0,
+		iceberg.NestedField{Name: "event_date", Type: iceberg.PrimitiveTypes.Date, Required: true},
+		iceberg.NestedField{Name: "user_id", Type: iceberg.PrimitiveTypes.Int64},
+	)))
+	for i := range s.Fields {
+		s.Fields[i].ID = types.Int64Null()
+	}
+	schema, diags := types.ObjectValueFrom(ctx, icebergTableSchema{}.AttrTypes(), s)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	data := &icebergTableResourceModel{
+		Namespace:      types.ListValueMust(types.StringType, []attr.Value{types.StringValue("db")}),
+		Name:           types.StringValue("events"),
+		Schema:         schema,
+		PartitionSpec:  types.ObjectNull(icebergTablePartitionSpec{}.AttrTypes()),
+		SortOrder:      types.ObjectUnknown(icebergTableSortOrder{}.AttrTypes()),
+		WriteOrder:     types.ListValueMust(types.StringType, []attr.Value{types.StringValue("user_id desc"), types.StringValue("event_date")}),
+		UserProperties: types.MapNull(types.StringType),
+	}
+
+	var d diag.Diagnostics
+	args := tableCreateArgsFromModel(ctx, data, &d)
+	require.False(t, d.HasError(), "%v", d)
+	require.NotNil(t, args.sortOrder)
+	assert.Equal(t, []table.SortField{
+		{SourceID: 2, Transform: iceberg.IdentityTransform{}, Direction: table.SortDESC, NullOrder: table.NullsLast},
+		{SourceID: 1, Transform: iceberg.IdentityTransform{}, Direction: table.SortASC, NullOrder: table.NullsFirst},
+	}, slices.Collect(args.sortOrder.Fields()))
+}
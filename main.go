@@ -17,19 +17,33 @@ package main
 
 import (
 	"context"
+	"io"
 	"log"
 
 	"github.com/apache/iceberg-terraform/internal/provider"
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
 
 const ADDRESS = "apache.org/iceberg/terraform-provider-iceberg"
 
+// version is set at release time with -ldflags "-X main.version=<version>".
+// Builds without it take the version from the build info.
+var version string
+
 func main() {
-	err := providerserver.Serve(context.Background(), provider.New(), providerserver.ServeOpts{
+	// The server configures this one provider instance for the whole
+	// process, which is closed once Terraform stopped the server.
+	p := provider.New(version)()
+	err := providerserver.Serve(context.Background(), func() fwprovider.Provider { return p }, providerserver.ServeOpts{
 		// TODO: This needs to change on release with the published name.
 		Address: ADDRESS,
 	})
+	if closer, ok := p.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("failed to close the provider: %s", err)
+		}
+	}
 	if err != nil {
 		log.Fatal(err)
 	}